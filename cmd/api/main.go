@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,12 +14,21 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/email"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	appmiddleware "github.com/rohit21755/groveserverv2/internal/middleware"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/push"
 	"github.com/rohit21755/groveserverv2/internal/router"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/scheduler"
+	"github.com/rohit21755/groveserverv2/internal/storage"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/telemetry"
+	"github.com/rohit21755/groveserverv2/internal/worker"
 )
 
 // @title           Gamified Campus Ambassador Platform API
@@ -38,6 +49,10 @@ import (
 // @securityDefinitions.basic  BasicAuth
 
 func main() {
+	migrateOnly := flag.Bool("migrate", false, "Run database migrations and exit")
+	migrateDown := flag.Int("migrate-down", 0, "Roll back N database migrations and exit")
+	flag.Parse()
+
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
@@ -46,13 +61,39 @@ func main() {
 	// Load configuration
 	cfg := env.Load()
 
+	// Initialize tracing
+	shutdownTracing, err := telemetry.InitTracing(context.Background(), cfg.OTELServiceName, cfg.OTELExporterEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	// Initialize database
-	database, err := db.NewPostgres(cfg.DatabaseURL)
+	database, err := db.NewPostgres(cfg.DatabaseURL, db.PoolConfig{
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: time.Duration(cfg.DBConnMaxLifetimeMins) * time.Minute,
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer database.Close()
 
+	// Apply database migrations before serving any request
+	if *migrateDown > 0 {
+		if err := db.RollbackMigrations(database.DB, cfg.MigrationsPath, *migrateDown); err != nil {
+			log.Fatalf("Failed to roll back migrations: %v", err)
+		}
+		log.Printf("Rolled back %d migration(s)", *migrateDown)
+		return
+	}
+	if err := db.RunMigrations(database.DB, cfg.MigrationsPath); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+	if *migrateOnly {
+		log.Println("Migrations complete")
+		return
+	}
+
 	// Initialize Redis
 	redisClient, err := db.NewRedis(cfg.RedisURL)
 	if err != nil {
@@ -60,37 +101,111 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// Start the WebSocket hubs before the schedulers and workers that send notifications through them
+	ws.InitHubs(redisClient, database, cfg)
+
+	// Initialize image moderation (uses the same AWS config as S3)
+	var moderator moderation.ImageModerator
+	awsCfg, err := storage.LoadAWSConfig(context.Background(), cfg.AWSRegion, cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey)
+	if err != nil {
+		log.Printf("Failed to load AWS config, image moderation disabled: %v", err)
+	} else {
+		moderator = moderation.NewModerationService(awsCfg)
+	}
+
+	// Load the content word filter and keep it refreshed from the database
+	wordFilter := moderation.NewWordFilter(context.Background(), store.NewBlockedWordStore(database).GetBlockedWordList,
+		cfg.BlockedWordsFile, moderation.ParseFilterMode(cfg.CommentFilterMode))
+	moderation.SetWordFilter(wordFilter)
+	go wordFilter.Run(context.Background())
+
+	// Daily XP caps per source
+	xpCaps := make(map[store.XPSource]int, len(cfg.DailyXPCaps))
+	for source, xpCap := range cfg.DailyXPCaps {
+		xpCaps[store.XPSource(source)] = xpCap
+	}
+	store.SetDailyXPCaps(xpCaps)
+
+	// Auto-create weekly task instances every Monday
+	go scheduler.NewWeeklyTaskScheduler(database).Run(context.Background())
+
+	// Outgoing email (verification links and notification digests)
+	emailer := email.NewEmailer(email.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.EmailFrom,
+	})
+	email.SetEmailer(emailer)
+
+	// Email nightly notification digests to opted-in users
+	go scheduler.NewDigestScheduler(database, emailer).Run(context.Background())
+
+	// Delete orphaned task proof files and old export archives every night
+	cleanupStorage, err := storage.NewS3Storage(storage.S3Config{
+		Region:                cfg.AWSRegion,
+		ProfileBucket:         cfg.AWSProfileBucket,
+		ResumeBucket:          cfg.AWSResumeBucket,
+		TaskProofBucket:       cfg.AWSTaskProofBucket,
+		AccessKeyID:           cfg.AWSAccessKeyID,
+		SecretAccessKey:       cfg.AWSSecretAccessKey,
+		TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+		TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
+	})
+	if err != nil {
+		log.Printf("Failed to initialize S3 storage, orphaned file cleanup disabled: %v", err)
+	} else {
+		go scheduler.NewOrphanedFileCleanupJob(database, cleanupStorage, cfg.CleanupDryRun).Run(context.Background())
+	}
+
+	// Push notifications to offline users' devices
+	if cfg.PushNotificationsEnabled {
+		pushService, err := push.NewPushNotificationService(context.Background(), database, cfg.FCMCredentialsFile)
+		if err != nil {
+			log.Printf("Failed to initialize push notifications, push disabled: %v", err)
+		} else {
+			push.SetService(pushService)
+			log.Println("Push notifications enabled (FCM)")
+		}
+	}
+
+	// Start background XP worker
+	xpWorker := worker.NewXPWorker(database, redisClient, cfg.PendingXPAwardsChannelSize)
+	go xpWorker.Run()
+
 	// Initialize router
 	r := chi.NewRouter()
 
 	// Middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	// Structured access log; wraps everything that can write a response
+	r.Use(appmiddleware.AccessLog(slog.Default()))
+	r.Use(telemetry.Middleware)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-
 	// CORS
-	r.Use(cors.Handler(cors.Options{
+	r.Use(appmiddleware.CORS(appmiddleware.CORSOptions{
 		AllowedOrigins:   cfg.CORSAllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-None-Match", "traceparent"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
-		MaxAge:           300,
+		MaxAgeSeconds:    cfg.CORSMaxAgeSeconds,
 	}))
 
 	// Setup routes
-	router.SetupRoutes(r, database, redisClient, cfg)
+	router.SetupRoutes(r, database, redisClient, cfg, moderator, xpWorker)
 
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.APIHost, cfg.APIPort)
 	srv := &http.Server{
-		Addr:         addr,
-		Handler:      r,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:    addr,
+		Handler: r,
+		// Body reads and response writes get per-route deadlines (see router.SetupRoutes):
+		// uploads need longer and WebSockets must not have one
+		ReadHeaderTimeout: 15 * time.Second,
+		IdleTimeout:       60 * time.Second,
 	}
 
 	// Graceful shutdown
@@ -115,5 +230,17 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Drain queued XP awards
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer drainCancel()
+
+	if err := xpWorker.Shutdown(drainCtx); err != nil {
+		log.Printf("XP worker did not drain: %v", err)
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Error flushing traces: %v", err)
+	}
+
 	log.Println("Server exited")
 }
@@ -7,22 +7,43 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// RoleAdmin is the role of tokens issued by admin login; only these are accepted on /admin routes
+const RoleAdmin = "admin"
+
+// RolePending2FA is the role of the short-lived token issued by admin login when the
+// admin has 2FA enabled. It is only accepted by the 2FA verify endpoint.
+const RolePending2FA = "admin_2fa_pending"
+
+// Pending2FATokenExpiry is how long an admin has to submit a TOTP code after logging in
+const Pending2FATokenExpiry = 5 * time.Minute
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID        string `json:"user_id"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	TwoFAVerified bool   `json:"two_fa_verified,omitempty"` // Set on admin tokens issued after a TOTP code was verified
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates a JWT token for a user
 func GenerateToken(userID, email, role, secret string, expiryDuration time.Duration) (string, error) {
+	return generateToken(userID, email, role, false, secret, expiryDuration)
+}
+
+// GenerateTwoFAVerifiedToken generates an admin JWT token carrying two_fa_verified: true
+func GenerateTwoFAVerifiedToken(userID, email, role, secret string, expiryDuration time.Duration) (string, error) {
+	return generateToken(userID, email, role, true, secret, expiryDuration)
+}
+
+func generateToken(userID, email, role string, twoFAVerified bool, secret string, expiryDuration time.Duration) (string, error) {
 	expirationTime := time.Now().Add(expiryDuration)
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:        userID,
+		Email:         email,
+		Role:          role,
+		TwoFAVerified: twoFAVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
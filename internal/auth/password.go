@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// MinPasswordLength is the minimum number of characters allowed in a password
+const MinPasswordLength = 10
+
+// ValidatePasswordStrength checks that a password meets the minimum complexity rules:
+// at least MinPasswordLength characters, one uppercase letter, one digit and one special character
+func ValidatePasswordStrength(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+
+	var hasUpper, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	if !hasUpper {
+		return fmt.Errorf("password must contain at least one uppercase letter")
+	}
+	if !hasDigit {
+		return fmt.Errorf("password must contain at least one digit")
+	}
+	if !hasSpecial {
+		return fmt.Errorf("password must contain at least one special character")
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Certificate is the content printed on a task completion certificate
+type Certificate struct {
+	UserName    string
+	TaskTitle   string
+	CompletedAt time.Time
+	XP          int
+}
+
+// logoImageTypes maps sniffed content types to the image types gofpdf can embed
+var logoImageTypes = map[string]string{
+	"image/png":  "PNG",
+	"image/jpeg": "JPG",
+	"image/gif":  "GIF",
+}
+
+// Render draws the certificate as a landscape A4 PDF. logo is a PNG, JPEG or GIF
+// placed at the top; the certificate is rendered without it when logo is empty.
+func Render(cert Certificate, logo []byte) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Certificate of Completion", true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+	// cp1252 so names with accents render with the core fonts
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageW, pageH := pdf.GetPageSize()
+
+	// Double border
+	pdf.SetDrawColor(46, 125, 50)
+	pdf.SetLineWidth(2)
+	pdf.Rect(10, 10, pageW-20, pageH-20, "D")
+	pdf.SetLineWidth(0.5)
+	pdf.Rect(15, 15, pageW-30, pageH-30, "D")
+
+	y := 28.0
+	if len(logo) > 0 {
+		imageType, ok := logoImageTypes[http.DetectContentType(logo)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported logo image type %s", http.DetectContentType(logo))
+		}
+		const logoH = 25.0
+		options := gofpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+		info := pdf.RegisterImageOptionsReader("logo", options, bytes.NewReader(logo))
+		if info == nil || pdf.Err() {
+			return nil, fmt.Errorf("failed to load logo: %w", pdf.Error())
+		}
+		logoW := logoH * info.Width() / info.Height()
+		pdf.ImageOptions("logo", (pageW-logoW)/2, y, logoW, logoH, false, options, 0, "")
+		y += logoH + 8
+	} else {
+		y += 10
+	}
+
+	centered := func(text string, style string, size float64, lineH float64) {
+		pdf.SetFont("Helvetica", style, size)
+		pdf.SetXY(20, y)
+		// MultiCell wraps long names and titles onto further lines
+		pdf.MultiCell(pageW-40, lineH, tr(text), "", "C", false)
+		y = pdf.GetY()
+	}
+
+	pdf.SetTextColor(46, 125, 50)
+	centered("Certificate of Completion", "B", 32, 16)
+	y += 6
+	pdf.SetTextColor(80, 80, 80)
+	centered("This certifies that", "", 14, 10)
+	pdf.SetTextColor(34, 34, 34)
+	centered(cert.UserName, "B", 28, 16)
+	pdf.SetTextColor(80, 80, 80)
+	centered("has successfully completed the task", "", 14, 10)
+	pdf.SetTextColor(34, 34, 34)
+	centered(cert.TaskTitle, "B", 20, 12)
+	y += 6
+	pdf.SetTextColor(80, 80, 80)
+	centered(fmt.Sprintf("earning %d XP on %s", cert.XP, cert.CompletedAt.Format("January 2, 2006")), "", 14, 10)
+
+	pdf.SetTextColor(136, 136, 136)
+	pdf.SetFont("Helvetica", "I", 10)
+	pdf.SetXY(20, pageH-30)
+	pdf.CellFormat(pageW-40, 6, "Grove", "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return buf.Bytes(), nil
+}
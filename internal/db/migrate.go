@@ -0,0 +1,83 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// newMigrate creates a migrate instance for the numbered SQL files in migrationsPath
+func newMigrate(db *sql.DB, migrationsPath string) (*migrate.Migrate, error) {
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsPath, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations from %s: %w", migrationsPath, err)
+	}
+	return m, nil
+}
+
+// RunMigrations applies all pending up migrations in migrationsPath.
+// The database connection is left open; only the migration source is closed.
+func RunMigrations(db *sql.DB, migrationsPath string) error {
+	m, err := newMigrate(db, migrationsPath)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return migrationError(m, err)
+	}
+
+	logMigrationVersion(m)
+	return nil
+}
+
+// RollbackMigrations applies the given number of down migrations
+func RollbackMigrations(db *sql.DB, migrationsPath string, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("rollback steps must be greater than 0")
+	}
+
+	m, err := newMigrate(db, migrationsPath)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return migrationError(m, err)
+	}
+
+	logMigrationVersion(m)
+	return nil
+}
+
+// migrationError wraps err, pointing at the dirty version when a migration failed part-way
+func migrationError(m *migrate.Migrate, err error) error {
+	if version, dirty, verr := m.Version(); verr == nil && dirty {
+		return fmt.Errorf("migration %d failed and the database is dirty (fix the schema, then run `make migrate-force VERSION=%d`): %w", version, version-1, err)
+	}
+	return fmt.Errorf("failed to run migrations: %w", err)
+}
+
+func logMigrationVersion(m *migrate.Migrate) {
+	version, _, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		log.Printf("[Migrate] Database has no migrations applied")
+	case err != nil:
+		log.Printf("[Migrate] Failed to read migration version: %v", err)
+	default:
+		log.Printf("[Migrate] Database schema at version %d", version)
+	}
+}
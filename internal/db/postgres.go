@@ -10,19 +10,40 @@ import (
 )
 
 type Postgres struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Traced *TracedPostgres // Same pool, with a tracing span per query
 }
 
-func NewPostgres(databaseURL string) (*Postgres, error) {
+// PoolConfig sizes the connection pool. Zero values fall back to the defaults
+// (25 open, 5 idle, 30 minute lifetime).
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func NewPostgres(databaseURL string, pool PoolConfig) (*Postgres, error) {
 	db, err := sql.Open("pgx", databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	if pool.MaxOpenConns <= 0 {
+		pool.MaxOpenConns = 25
+	}
+	if pool.MaxIdleConns <= 0 {
+		pool.MaxIdleConns = 5
+	}
+	if pool.MaxIdleConns > pool.MaxOpenConns {
+		pool.MaxIdleConns = pool.MaxOpenConns
+	}
+	if pool.ConnMaxLifetime <= 0 {
+		pool.ConnMaxLifetime = 30 * time.Minute
+	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -32,7 +53,7 @@ func NewPostgres(databaseURL string) (*Postgres, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Postgres{DB: db}, nil
+	return &Postgres{DB: db, Traced: NewTracedPostgres(db)}, nil
 }
 
 func (p *Postgres) Close() error {
@@ -42,3 +63,8 @@ func (p *Postgres) Close() error {
 func (p *Postgres) Ping(ctx context.Context) error {
 	return p.DB.PingContext(ctx)
 }
+
+// Stats returns connection pool statistics
+func (p *Postgres) Stats() sql.DBStats {
+	return p.DB.Stats()
+}
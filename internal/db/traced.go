@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/rohit21755/groveserverv2/internal/telemetry"
+)
+
+// tableNamePattern finds the first table a statement reads from or writes to
+var tableNamePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+([a-z_][a-z0-9_]*)`)
+
+// TracedPostgres wraps the *sql.DB query methods and starts a child span
+// named db.query.{table} for every statement
+type TracedPostgres struct {
+	DB *sql.DB
+}
+
+// NewTracedPostgres wraps an existing connection pool
+func NewTracedPostgres(db *sql.DB) *TracedPostgres {
+	return &TracedPostgres{DB: db}
+}
+
+// QueryContext runs a query that returns rows inside a db.query span
+func (t *TracedPostgres) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	rows, err := t.DB.QueryContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return rows, err
+}
+
+// QueryRowContext runs a query that returns at most one row inside a db.query span.
+// The span ends before Scan, so it covers query execution but not row decoding.
+func (t *TracedPostgres) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	row := t.DB.QueryRowContext(ctx, query, args...)
+	endQuerySpan(span, row.Err())
+	return row
+}
+
+// ExecContext runs a statement without returning rows inside a db.query span
+func (t *TracedPostgres) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	result, err := t.DB.ExecContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return result, err
+}
+
+// BeginTx starts a transaction; statements run on the returned *sql.Tx are not traced individually
+func (t *TracedPostgres) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return t.DB.BeginTx(ctx, opts)
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	table := "unknown"
+	if m := tableNamePattern.FindStringSubmatch(query); m != nil {
+		table = strings.ToLower(m[1])
+	}
+	return telemetry.Tracer().Start(ctx, "db.query."+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement", strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,95 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+)
+
+// Emailer sends HTML emails
+type Emailer interface {
+	Send(ctx context.Context, to, subject, htmlBody string) error
+}
+
+// SMTPConfig holds SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewEmailer returns an SMTP emailer, or a LogEmailer when no SMTP host is configured
+func NewEmailer(cfg SMTPConfig) Emailer {
+	if cfg.Host == "" {
+		log.Printf("[Email] SMTP_HOST not set, emails will be logged instead of sent")
+		return LogEmailer{}
+	}
+	return &SMTPEmailer{cfg: cfg}
+}
+
+// SMTPEmailer sends email through an SMTP server (STARTTLS when the server offers it)
+type SMTPEmailer struct {
+	cfg SMTPConfig
+}
+
+// Send sends an HTML email. ctx is only checked before sending; net/smtp has no cancellation.
+func (e *SMTPEmailer) Send(ctx context.Context, to, subject, htmlBody string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + e.cfg.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		`Content-Type: text/html; charset="UTF-8"`,
+		"",
+		htmlBody,
+	}, "\r\n")
+
+	addr := net.JoinHostPort(e.cfg.Host, e.cfg.Port)
+	if err := smtp.SendMail(addr, auth, e.cfg.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to, err)
+	}
+	return nil
+}
+
+// LogEmailer logs emails instead of sending them (local development)
+type LogEmailer struct{}
+
+// Send logs the recipient and subject
+func (LogEmailer) Send(ctx context.Context, to, subject, htmlBody string) error {
+	log.Printf("[Email] (not sent) To: %s, Subject: %s, Body: %d bytes", to, subject, len(htmlBody))
+	return nil
+}
+
+var (
+	globalEmailer   Emailer
+	globalEmailerMu sync.RWMutex
+)
+
+// SetEmailer installs the process-wide emailer used by request handlers
+func SetEmailer(e Emailer) {
+	globalEmailerMu.Lock()
+	globalEmailer = e
+	globalEmailerMu.Unlock()
+}
+
+// GetEmailer returns the process-wide emailer, or nil when none has been installed
+func GetEmailer() Emailer {
+	globalEmailerMu.RLock()
+	defer globalEmailerMu.RUnlock()
+	return globalEmailer
+}
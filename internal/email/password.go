@@ -0,0 +1,39 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/rohit21755/groveserverv2/internal/templates"
+)
+
+// SetPasswordEmail is the data rendered into the set password email template
+type SetPasswordEmail struct {
+	Name           string
+	SetPasswordURL string
+}
+
+// SendSetPasswordEmail emails the user a link to choose the password of an account created for them
+func SendSetPasswordEmail(ctx context.Context, e Emailer, to, name, setPasswordURL string) error {
+	var body bytes.Buffer
+	if err := templates.SetPassword.Execute(&body, SetPasswordEmail{Name: name, SetPasswordURL: setPasswordURL}); err != nil {
+		return fmt.Errorf("failed to render set password email: %w", err)
+	}
+	return e.Send(ctx, to, "Set your password", body.String())
+}
+
+// PasswordResetEmail is the data rendered into the reset password email template
+type PasswordResetEmail struct {
+	Name     string
+	ResetURL string
+}
+
+// SendPasswordResetEmail emails the user a link to choose a new password after they forgot theirs
+func SendPasswordResetEmail(ctx context.Context, e Emailer, to, name, resetURL string) error {
+	var body bytes.Buffer
+	if err := templates.ResetPassword.Execute(&body, PasswordResetEmail{Name: name, ResetURL: resetURL}); err != nil {
+		return fmt.Errorf("failed to render reset password email: %w", err)
+	}
+	return e.Send(ctx, to, "Reset your password", body.String())
+}
@@ -0,0 +1,24 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/rohit21755/groveserverv2/internal/templates"
+)
+
+// VerificationEmail is the data rendered into the verification email template
+type VerificationEmail struct {
+	Name      string
+	VerifyURL string
+}
+
+// SendVerificationEmail emails the user a link to confirm their address
+func SendVerificationEmail(ctx context.Context, e Emailer, to, name, verifyURL string) error {
+	var body bytes.Buffer
+	if err := templates.VerifyEmail.Execute(&body, VerificationEmail{Name: name, VerifyURL: verifyURL}); err != nil {
+		return fmt.Errorf("failed to render verification email: %w", err)
+	}
+	return e.Send(ctx, to, "Verify your email address", body.String())
+}
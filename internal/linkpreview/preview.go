@@ -0,0 +1,169 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// fetchTimeout bounds a whole preview fetch, including redirects and reading the body
+const fetchTimeout = 5 * time.Second
+
+// maxBodyBytes caps how much of the page is read while looking for Open Graph tags
+const maxBodyBytes = 1 << 20
+
+// maxRedirects is how many redirects a preview fetch follows
+const maxRedirects = 5
+
+// LinkPreview is the Open Graph metadata of a page
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// ErrDomainNotAllowed is returned when the URL (or a redirect target) is not on an allowed domain
+var ErrDomainNotAllowed = errors.New("domain not allowed")
+
+// HostAllowed reports whether host is one of domains or a subdomain of one
+func HostAllowed(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkURL returns an error unless u is an http(s) URL on an allowed domain
+func checkURL(u *url.URL, allowedDomains []string) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	if !HostAllowed(u.Hostname(), allowedDomains) {
+		return fmt.Errorf("%w: %s", ErrDomainNotAllowed, u.Hostname())
+	}
+	return nil
+}
+
+// FetchPreview downloads the page at rawURL and extracts its og:title, og:description
+// and og:image tags. The URL and every redirect must be on one of allowedDomains.
+// The page <title> is used when there is no og:title.
+func FetchPreview(ctx context.Context, rawURL string, allowedDomains []string) (*LinkPreview, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := checkURL(parsed, allowedDomains); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return checkURL(req.URL, allowedDomains)
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", "GroveLinkPreview/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	preview := parseOpenGraph(io.LimitReader(resp.Body, maxBodyBytes))
+	preview.URL = rawURL
+	if preview.ImageURL != "" {
+		// og:image may be relative to the final page URL
+		if imageURL, err := resp.Request.URL.Parse(preview.ImageURL); err == nil {
+			preview.ImageURL = imageURL.String()
+		}
+	}
+	return preview, nil
+}
+
+// parseOpenGraph reads the document head for Open Graph meta tags and the <title>.
+// Parsing stops at </head> or the first <body> tag.
+func parseOpenGraph(r io.Reader) *LinkPreview {
+	preview := &LinkPreview{}
+	var title strings.Builder
+	inTitle := false
+
+	tokenizer := html.NewTokenizer(r)
+parse:
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			break parse // io.EOF or malformed input
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				break parse
+			case "title":
+				inTitle = tokenType == html.StartTagToken
+			case "meta":
+				var property, content string
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "property", "name":
+						property = strings.ToLower(attr.Val)
+					case "content":
+						content = strings.TrimSpace(attr.Val)
+					}
+				}
+				switch property {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image":
+					preview.ImageURL = content
+				}
+			}
+		case html.EndTagToken:
+			switch tokenizer.Token().Data {
+			case "head":
+				break parse
+			case "title":
+				inTitle = false
+			}
+		case html.TextToken:
+			if inTitle {
+				title.Write(tokenizer.Text())
+			}
+		}
+	}
+
+	if preview.Title == "" {
+		preview.Title = strings.TrimSpace(title.String())
+	}
+	return preview
+}
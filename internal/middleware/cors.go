@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OriginAllowlist matches request origins against configured patterns.
+// A pattern is an exact origin ("https://app.example.com"), a wildcard subdomain with or
+// without a scheme ("https://*.example.com", "*.example.com"), or "*" to allow any origin.
+type OriginAllowlist struct {
+	allowAll bool
+	patterns []string
+}
+
+// NewOriginAllowlist builds an allowlist from patterns; empty entries are ignored
+func NewOriginAllowlist(patterns []string) *OriginAllowlist {
+	list := &OriginAllowlist{}
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimRight(strings.TrimSpace(p), "/"))
+		if p == "" {
+			continue
+		}
+		if p == "*" {
+			list.allowAll = true
+			continue
+		}
+		list.patterns = append(list.patterns, p)
+	}
+	return list
+}
+
+// Allowed reports whether origin matches the allowlist
+func (l *OriginAllowlist) Allowed(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if l.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	for _, p := range l.patterns {
+		target := origin
+		if !strings.Contains(p, "://") {
+			// Scheme-less patterns match the host only
+			target = u.Host
+		}
+		if matchOrigin(p, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin compares target to pattern, where a "*." in pattern matches one or more subdomain labels
+func matchOrigin(pattern, target string) bool {
+	idx := strings.Index(pattern, "*.")
+	if idx < 0 {
+		return pattern == target
+	}
+
+	prefix, suffix := pattern[:idx], pattern[idx+1:] // suffix keeps the leading "."
+	if !strings.HasPrefix(target, prefix) || !strings.HasSuffix(target, suffix) {
+		return false
+	}
+	sub := target[len(prefix) : len(target)-len(suffix)]
+	return sub != "" && !strings.ContainsAny(sub, "/:")
+}
+
+// CORSOptions configures the CORS middleware
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAgeSeconds    int
+}
+
+// CORS answers preflight requests and sets CORS headers for allowed origins.
+// The matched origin is echoed back (never "*") and Vary: Origin is always set so caches
+// don't serve one origin's response to another.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	allowlist := NewOriginAllowlist(opts.AllowedOrigins)
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(opts.MaxAgeSeconds)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if !allowlist.Allowed(origin) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				if opts.MaxAgeSeconds > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposed != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposed)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// writeGrace is how long past the context deadline a response may still be written,
+// so a handler whose queries were cancelled can still send its error
+const writeGrace = 5 * time.Second
+
+// TimeoutOverride applies a different timeout to requests matched by Match.
+// A Timeout of 0 or less leaves the request without a deadline.
+type TimeoutOverride struct {
+	Match   func(r *http.Request) bool
+	Timeout time.Duration
+}
+
+// DBTimeout derives a request context with a deadline so store queries using r.Context()
+// are cancelled instead of holding a connection indefinitely. The first matching override wins.
+// Overrides must be applied here rather than with a nested middleware: a child context
+// cannot extend its parent's deadline.
+//
+// It also sets the connection's read and write deadlines to writeGrace past the context
+// deadline. http.Server's ReadTimeout and WriteTimeout apply to every route alike, so the
+// server leaves them unset and routes get theirs here; a slow upload can then read its body
+// and write its response for as long as its override allows.
+func DBTimeout(timeout time.Duration, overrides ...TimeoutOverride) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestTimeout := timeout
+			for _, override := range overrides {
+				if override.Match(r) {
+					requestTimeout = override.Timeout
+					break
+				}
+			}
+			if requestTimeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+			defer cancel()
+
+			connDeadline := time.Now().Add(requestTimeout + writeGrace)
+			rc := http.NewResponseController(w)
+			if err := rc.SetReadDeadline(connDeadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				log.Printf("Error setting read deadline: %v", err)
+			}
+			if err := rc.SetWriteDeadline(connDeadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				log.Printf("Error setting write deadline: %v", err)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
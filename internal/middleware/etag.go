@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// etagResponseWriter buffers the response body so an ETag can be computed before anything is sent
+type etagResponseWriter struct {
+	http.ResponseWriter
+	buf    bytes.Buffer
+	status int
+}
+
+func (w *etagResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *etagResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(b)
+}
+
+// ETag sets an ETag header (md5 of the response body) on successful GET responses
+// and returns 304 Not Modified when the request's If-None-Match matches.
+// Register it inside any compression middleware so the hash covers the uncompressed body.
+func ETag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ew := &etagResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(ew, r)
+
+		status := ew.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		// Only cache successful responses; pass everything else through unchanged
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			_, _ = w.Write(ew.buf.Bytes())
+			return
+		}
+
+		sum := md5.Sum(ew.buf.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:]) + `"`
+		w.Header().Set("ETag", etag)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Length")
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(ew.buf.Bytes())
+	})
+}
+
+// etagMatches reports whether an If-None-Match header value matches the given ETag.
+// Weak validators (W/"...") are compared by their opaque value.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// accessLogSkipPaths (and their subpaths) are not access logged: health checks and metrics scrapes are noise
+var accessLogSkipPaths = []string{"/health", "/metrics"}
+
+// statusRecorder captures the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush passes through to the underlying writer (streamed CSV exports)
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack passes through to the underlying writer so WebSocket upgrades work; the request is logged as 101
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// accessLogUserKey holds a *string that auth middleware fills in with the request's user ID.
+// Auth middleware runs inside AccessLog and passes a new context down, so AccessLog can't read the ID from the context itself.
+type accessLogUserKey struct{}
+
+// SetAccessLogUserID records the authenticated user of a request in its access log entry.
+// It does nothing for requests that AccessLog does not log.
+func SetAccessLogUserID(ctx context.Context, userID string) {
+	if holder, ok := ctx.Value(accessLogUserKey{}).(*string); ok {
+		*holder = userID
+	}
+}
+
+// AccessLog logs method, path, status, latency_ms, user_id, request_id and ip of every request to logger
+// once the handler returns: INFO for 2xx/3xx, WARN for 4xx and ERROR for 5xx.
+// Register it after RequestID and RealIP and before everything else, so it also covers responses
+// written by auth and recovery middleware.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skipAccessLog(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			var userID string
+			ctx := context.WithValue(r.Context(), accessLogUserKey{}, &userID)
+			rec := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+
+			logger.LogAttrs(r.Context(), level, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("user_id", userID),
+				slog.String("request_id", chimiddleware.GetReqID(r.Context())),
+				slog.String("ip", clientIP(r)),
+			)
+		})
+	}
+}
+
+// skipAccessLog reports whether path is one of accessLogSkipPaths or below one
+func skipAccessLog(path string) bool {
+	for _, skip := range accessLogSkipPaths {
+		if path == skip || strings.HasPrefix(path, skip+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the request's remote address without the port
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+func TestAccessLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		userID    string
+		status    int // 0 writes a body without calling WriteHeader
+		wantLevel string
+		wantLog   bool
+	}{
+		{name: "success", path: "/api/user/profile", userID: "user-1", status: http.StatusOK, wantLevel: "INFO", wantLog: true},
+		{name: "implicit 200", path: "/api/tasks", wantLevel: "INFO", wantLog: true},
+		{name: "redirect", path: "/api/old", status: http.StatusFound, wantLevel: "INFO", wantLog: true},
+		{name: "client error", path: "/api/tasks/x", userID: "user-1", status: http.StatusNotFound, wantLevel: "WARN", wantLog: true},
+		{name: "server error", path: "/api/tasks", status: http.StatusInternalServerError, wantLevel: "ERROR", wantLog: true},
+		{name: "health check", path: "/health", status: http.StatusOK},
+		{name: "health subpath", path: "/health/ready", status: http.StatusOK},
+		{name: "metrics", path: "/metrics", status: http.StatusOK},
+		{name: "health lookalike", path: "/healthz", status: http.StatusOK, wantLevel: "INFO", wantLog: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, nil))
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Auth middleware runs inside AccessLog and records the user like this
+				if tt.userID != "" {
+					SetAccessLogUserID(r.Context(), tt.userID)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte("ok"))
+			})
+			handler := chimiddleware.RequestID(AccessLog(logger)(next))
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.RemoteAddr = "203.0.113.7:52114"
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if !tt.wantLog {
+				if out.Len() != 0 {
+					t.Errorf("logged %s, want nothing", out.String())
+				}
+				return
+			}
+			var entry map[string]any
+			if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+				t.Fatalf("decoding log entry %q: %v", out.String(), err)
+			}
+			for _, field := range []string{"method", "path", "status", "latency_ms", "user_id", "request_id", "ip"} {
+				if _, ok := entry[field]; !ok {
+					t.Errorf("log entry has no %s: %v", field, entry)
+				}
+			}
+			wantStatus := tt.status
+			if wantStatus == 0 {
+				wantStatus = http.StatusOK
+			}
+			if entry["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", entry["level"], tt.wantLevel)
+			}
+			if entry["method"] != http.MethodGet || entry["path"] != tt.path {
+				t.Errorf("method, path = %v %v, want GET %s", entry["method"], entry["path"], tt.path)
+			}
+			if entry["status"] != float64(wantStatus) {
+				t.Errorf("status = %v, want %d", entry["status"], wantStatus)
+			}
+			if entry["user_id"] != tt.userID {
+				t.Errorf("user_id = %v, want %q", entry["user_id"], tt.userID)
+			}
+			if id, _ := entry["request_id"].(string); id == "" {
+				t.Error("request_id is empty")
+			}
+			if entry["ip"] != "203.0.113.7" {
+				t.Errorf("ip = %v, want 203.0.113.7", entry["ip"])
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+)
+
+// MinConfidence is the minimum confidence (0-100) for a moderation label to mark an image as unsafe
+const MinConfidence float32 = 75
+
+// ImageModerator checks images for inappropriate content.
+// Handlers depend on this interface so it can be replaced with a mock.
+type ImageModerator interface {
+	// IsImageSafe returns false and the detected label names if the image is unsafe
+	IsImageSafe(ctx context.Context, reader io.ReadSeeker) (bool, string, error)
+}
+
+// ModerationService moderates images using AWS Rekognition
+type ModerationService struct {
+	client *rekognition.Client
+}
+
+// NewModerationService creates a moderation service from an AWS config (the same one used for S3)
+func NewModerationService(awsCfg aws.Config) *ModerationService {
+	return &ModerationService{
+		client: rekognition.NewFromConfig(awsCfg),
+	}
+}
+
+// IsImageSafe sends the image to Rekognition DetectModerationLabels.
+// The reader is rewound to the start afterwards so it can still be uploaded.
+// Rekognition accepts JPEG and PNG images up to 5MB.
+func (m *ModerationService) IsImageSafe(ctx context.Context, reader io.ReadSeeker) (bool, string, error) {
+	imageBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return false, "", fmt.Errorf("failed to rewind image: %w", err)
+	}
+
+	output, err := m.client.DetectModerationLabels(ctx, &rekognition.DetectModerationLabelsInput{
+		Image:         &types.Image{Bytes: imageBytes},
+		MinConfidence: aws.Float32(MinConfidence),
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to detect moderation labels: %w", err)
+	}
+
+	var labels []string
+	for _, label := range output.ModerationLabels {
+		if label.Confidence != nil && *label.Confidence >= MinConfidence {
+			labels = append(labels, aws.ToString(label.Name))
+		}
+	}
+	if len(labels) > 0 {
+		return false, strings.Join(labels, ", "), nil
+	}
+
+	return true, "", nil
+}
@@ -0,0 +1,184 @@
+package moderation
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WordFilterRefreshInterval is how often the blocklist is reloaded from the database
+const WordFilterRefreshInterval = 5 * time.Minute
+
+// FilterMode controls what happens when a comment contains blocked words
+type FilterMode string
+
+const (
+	FilterModeReject  FilterMode = "reject"  // Refuse the text
+	FilterModeReplace FilterMode = "replace" // Mask blocked words with ***
+)
+
+// ParseFilterMode returns the mode for s, defaulting to reject for unknown values
+func ParseFilterMode(s string) FilterMode {
+	if FilterMode(strings.ToLower(strings.TrimSpace(s))) == FilterModeReplace {
+		return FilterModeReplace
+	}
+	return FilterModeReject
+}
+
+// WordLoader returns the admin-managed blocklist (e.g. BlockedWordStore.GetBlockedWordList)
+type WordLoader func(ctx context.Context) ([]string, error)
+
+// WordFilter masks or detects blocked words in free text.
+// Words come from the database (via loader) plus an optional file with one word per line,
+// and are matched case-insensitively on word boundaries.
+type WordFilter struct {
+	loader   WordLoader
+	filePath string
+
+	// CommentMode is applied to feed comments; task descriptions always use replace
+	CommentMode FilterMode
+
+	mu      sync.RWMutex
+	pattern *regexp.Regexp
+}
+
+// NewWordFilter creates a word filter and loads the initial blocklist
+func NewWordFilter(ctx context.Context, loader WordLoader, filePath string, commentMode FilterMode) *WordFilter {
+	f := &WordFilter{
+		loader:      loader,
+		filePath:    filePath,
+		CommentMode: commentMode,
+	}
+	if err := f.Refresh(ctx); err != nil {
+		log.Printf("[WordFilter] Failed to load blocked words: %v", err)
+	}
+	return f
+}
+
+// Run reloads the blocklist every WordFilterRefreshInterval until ctx is cancelled
+func (f *WordFilter) Run(ctx context.Context) {
+	ticker := time.NewTicker(WordFilterRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.Refresh(ctx); err != nil {
+				log.Printf("[WordFilter] Failed to refresh blocked words: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh reloads the blocklist now (e.g. after an admin edits it).
+// On a database error the previous list is kept.
+func (f *WordFilter) Refresh(ctx context.Context) error {
+	seen := make(map[string]bool)
+	if f.filePath != "" {
+		fileWords, err := readWordFile(f.filePath)
+		if err != nil {
+			log.Printf("[WordFilter] Failed to read %s: %v", f.filePath, err)
+		}
+		for _, w := range fileWords {
+			seen[w] = true
+		}
+	}
+	if f.loader != nil {
+		dbWords, err := f.loader(ctx)
+		if err != nil {
+			return err
+		}
+		for _, w := range dbWords {
+			if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+				seen[w] = true
+			}
+		}
+	}
+
+	words := make([]string, 0, len(seen))
+	for w := range seen {
+		words = append(words, regexp.QuoteMeta(w))
+	}
+	// Longest first so multi-word entries win over their prefixes
+	sort.Slice(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+
+	var pattern *regexp.Regexp
+	if len(words) > 0 {
+		pattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+	}
+
+	f.mu.Lock()
+	f.pattern = pattern
+	f.mu.Unlock()
+	return nil
+}
+
+// Filter replaces blocked words in text with *** and returns the distinct (lowercase) matches
+func (f *WordFilter) Filter(text string) (string, []string) {
+	if f == nil {
+		return text, nil
+	}
+	f.mu.RLock()
+	pattern := f.pattern
+	f.mu.RUnlock()
+	if pattern == nil {
+		return text, nil
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+	cleaned := pattern.ReplaceAllStringFunc(text, func(match string) string {
+		lower := strings.ToLower(match)
+		if !seen[lower] {
+			seen[lower] = true
+			found = append(found, lower)
+		}
+		return "***"
+	})
+	return cleaned, found
+}
+
+func readWordFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+var (
+	globalWordFilter   *WordFilter
+	globalWordFilterMu sync.RWMutex
+)
+
+// SetWordFilter installs the process-wide word filter used by stores and handlers
+func SetWordFilter(f *WordFilter) {
+	globalWordFilterMu.Lock()
+	globalWordFilter = f
+	globalWordFilterMu.Unlock()
+}
+
+// GetWordFilter returns the process-wide word filter, or nil if none is configured
+func GetWordFilter() *WordFilter {
+	globalWordFilterMu.RLock()
+	defer globalWordFilterMu.RUnlock()
+	return globalWordFilter
+}
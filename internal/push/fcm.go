@@ -0,0 +1,119 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+	fcm "github.com/appleboy/go-fcm"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// sendTimeout bounds a single push delivery, including the token lookup
+const sendTimeout = 10 * time.Second
+
+// PushNotificationService delivers push notifications to users' devices through
+// Firebase Cloud Messaging. FCM relays to APNs for iOS devices.
+type PushNotificationService struct {
+	client   *fcm.Client
+	postgres *db.Postgres
+}
+
+// NewPushNotificationService creates an FCM client from a service account credentials file
+func NewPushNotificationService(ctx context.Context, postgres *db.Postgres, credentialsFile string) (*PushNotificationService, error) {
+	if credentialsFile == "" {
+		return nil, fmt.Errorf("FCM credentials file is required")
+	}
+	client, err := fcm.NewClient(ctx, fcm.WithCredentialsFile(credentialsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FCM client: %w", err)
+	}
+	return &PushNotificationService{
+		client:   client,
+		postgres: postgres,
+	}, nil
+}
+
+// SendToUser pushes a notification to the user's registered device.
+// Users without an FCM token are skipped. Tokens FCM reports as unregistered are cleared.
+func (s *PushNotificationService) SendToUser(ctx context.Context, userID, title, message string) error {
+	userStore := store.NewUserStore(s.postgres)
+	token, err := userStore.GetFCMToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return nil
+	}
+
+	resp, err := s.client.Send(ctx, &messaging.Message{
+		Token: token,
+		Notification: &messaging.Notification{
+			Title: title,
+			Body:  message,
+		},
+		Android: &messaging.AndroidConfig{
+			Priority: "high",
+		},
+		APNS: &messaging.APNSConfig{
+			Payload: &messaging.APNSPayload{
+				Aps: &messaging.Aps{Sound: "default"},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send push notification: %w", err)
+	}
+
+	if resp.FailureCount > 0 && len(resp.Responses) > 0 {
+		sendErr := resp.Responses[0].Error
+		if messaging.IsUnregistered(sendErr) || messaging.IsInvalidArgument(sendErr) {
+			if err := userStore.UpdateFCMToken(ctx, userID, ""); err != nil {
+				log.Printf("[Push] Failed to clear stale FCM token for user %s: %v", userID, err)
+			}
+		}
+		return fmt.Errorf("failed to send push notification: %w", sendErr)
+	}
+
+	return nil
+}
+
+var (
+	globalService   *PushNotificationService
+	globalServiceMu sync.RWMutex
+)
+
+// SetService installs the process-wide push service used by SendToUser
+func SetService(s *PushNotificationService) {
+	globalServiceMu.Lock()
+	globalService = s
+	globalServiceMu.Unlock()
+}
+
+// GetService returns the process-wide push service, or nil when push notifications are disabled
+func GetService() *PushNotificationService {
+	globalServiceMu.RLock()
+	defer globalServiceMu.RUnlock()
+	return globalService
+}
+
+// SendToUser pushes a notification with the process-wide service in the background.
+// It does nothing when push notifications are disabled; failures are only logged.
+func SendToUser(userID, title, message string) {
+	service := GetService()
+	if service == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := service.SendToUser(ctx, userID, title, message); err != nil {
+			log.Printf("[Push] Failed to notify user %s: %v", userID, err)
+		}
+	}()
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ActivityLogResponse is one page of a user's activity timeline
+type ActivityLogResponse struct {
+	Activities []store.ActivityLog `json:"activities"`
+	Total      int                 `json:"total"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// recordActivity adds an entry to a user's activity timeline. Failures are only logged
+// so the action that triggered the entry still succeeds.
+func recordActivity(ctx context.Context, postgres *db.Postgres, req store.RecordActivityRequest) {
+	activityStore := store.NewActivityLogStore(postgres)
+	if err := activityStore.RecordActivity(ctx, req); err != nil {
+		log.Printf("Error recording %s activity for user %s: %v", req.EventType, req.UserID, err)
+	}
+}
+
+// handleGetMyActivityLog returns the authenticated user's full activity timeline
+// @Summary      Get my activity log
+// @Description  Chronological timeline (newest first) of the user's actions: submissions created, approved and rejected, badges earned, streak milestones, follows added and removed, and comments posted.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page       query     int  false  "Page number (default: 1)"
+// @Param        page_size  query     int  false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  ActivityLogResponse  "Activity log"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/me/activity-log [get]
+func handleGetMyActivityLog(activityStore store.ActivityLogStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetUserIDFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		serveActivityLog(w, r, activityStore, userID, false)
+	}
+}
+
+// handleGetUserActivityLog returns the public part of a user's activity timeline
+// @Summary      Get user activity log
+// @Description  Public activity timeline of a user (newest first). Only public events are included: submission_approved, badge_earned and streak_milestone.
+// @Tags         user
+// @Produce      json
+// @Param        id         path      string  true   "User ID"
+// @Param        page       query     int     false  "Page number (default: 1)"
+// @Param        page_size  query     int     false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  ActivityLogResponse  "Public activity log"
+// @Failure      400        {string}  string  "Bad request - user ID is required"
+// @Failure      404        {string}  string  "User not found"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/{id}/activity-log [get]
+func handleGetUserActivityLog(userStore store.UserStoreInterface, activityStore store.ActivityLogStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := userStore.GetUserByID(ctx, userID); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		serveActivityLog(w, r, activityStore, userID, true)
+	}
+}
+
+// serveActivityLog writes one page of a user's activity as an ActivityLogResponse.
+// With publicOnly only store.PublicActivityEvents are included.
+func serveActivityLog(w http.ResponseWriter, r *http.Request, activityStore store.ActivityLogStoreInterface, userID string, publicOnly bool) {
+	ctx := r.Context()
+
+	page := 1
+	pageSize := 20
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var activities []store.ActivityLog
+	var total int
+	var err error
+	if publicOnly {
+		activities, total, err = activityStore.GetPublicActivityLog(ctx, userID, page, pageSize)
+	} else {
+		activities, total, err = activityStore.GetUserActivityLog(ctx, userID, page, pageSize)
+	}
+	if err != nil {
+		log.Printf("Error getting activity log: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to get activity log: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	response := ActivityLogResponse{
+		Activities: activities,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding activity log response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetMyActivityLog(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		query          string
+		total          int
+		err            error
+		wantStatus     int
+		wantPage       int
+		wantPageSize   int
+		wantTotalPages int
+	}{
+		{name: "defaults", userID: testutil.TestUserID, total: 0, wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 20, wantTotalPages: 1},
+		{name: "second page", userID: testutil.TestUserID, query: "?page=2&page_size=10", total: 25, wantStatus: http.StatusOK, wantPage: 2, wantPageSize: 10, wantTotalPages: 3},
+		{name: "page size capped", userID: testutil.TestUserID, query: "?page_size=500", total: 250, wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 100, wantTotalPages: 3},
+		{name: "invalid values ignored", userID: testutil.TestUserID, query: "?page=-1&page_size=abc", total: 5, wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 20, wantTotalPages: 1},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantPage: 1, wantPageSize: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activityStore := &mock.ActivityLogStore{
+				GetUserActivityLogFunc: func(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error) {
+					if page != tt.wantPage || pageSize != tt.wantPageSize {
+						t.Errorf("page, pageSize = %d, %d; want %d, %d", page, pageSize, tt.wantPage, tt.wantPageSize)
+					}
+					return []store.ActivityLog{}, tt.total, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/activity-log"+tt.query, ""), tt.userID)
+			w := serve(t, handleGetMyActivityLog(activityStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got ActivityLogResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.TotalPages != tt.wantTotalPages {
+				t.Errorf("total_pages = %d, want %d", got.TotalPages, tt.wantTotalPages)
+			}
+		})
+	}
+}
+
+func TestHandleGetUserActivityLog(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		userErr    error
+		wantStatus int
+		wantPublic bool
+	}{
+		{name: "public events only", userID: testutil.TestUserID, wantStatus: http.StatusOK, wantPublic: true},
+		{name: "missing user ID", wantStatus: http.StatusBadRequest},
+		{name: "unknown user", userID: testutil.TestUserID, userErr: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "lookup fails", userID: testutil.TestUserID, userErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			public := false
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					return testutil.NewTestUser(), tt.userErr
+				},
+			}
+			activityStore := &mock.ActivityLogStore{
+				GetPublicActivityLogFunc: func(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error) {
+					public = true
+					return []store.ActivityLog{}, 0, nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/user/x/activity-log", ""), "id", tt.userID)
+			serve(t, handleGetUserActivityLog(userStore, activityStore), r, tt.wantStatus)
+			if public != tt.wantPublic {
+				t.Errorf("GetPublicActivityLog called = %v, want %v", public, tt.wantPublic)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -13,11 +14,15 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/auth"
 	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
 	"github.com/rohit21755/groveserverv2/internal/router/ws"
 	"github.com/rohit21755/groveserverv2/internal/storage"
 	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/validator"
+	"github.com/rohit21755/groveserverv2/internal/worker"
 )
 
 // CreateTaskRequest represents the request body for creating a task
@@ -43,20 +48,51 @@ type CreateTaskResponse struct {
 	AssignedTo int         `json:"assigned_to"` // Number of users the task was assigned to
 }
 
+// writeInvalidTaskType responds 400 with the accepted task types
+func writeInvalidTaskType(w http.ResponseWriter) {
+	validValues := make([]string, len(store.ValidTaskTypes))
+	for i, t := range store.ValidTaskTypes {
+		validValues[i] = string(t)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":         "INVALID_TASK_TYPE",
+		"field":        "type",
+		"valid_values": validValues,
+	})
+}
+
+// writeInvalidProofType responds 400 with the accepted proof types
+func writeInvalidProofType(w http.ResponseWriter) {
+	validValues := make([]string, len(store.ValidProofTypes))
+	for i, t := range store.ValidProofTypes {
+		validValues[i] = string(t)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":         "INVALID_PROOF_TYPE",
+		"field":        "proof_type",
+		"valid_values": validValues,
+	})
+}
+
 // handleCreateTask handles creating a new task (admin)
 // @Summary      Create task
-// @Description  Create a new task and assign it to users. Can be assigned to all users, users from a state, users from a college, or a single user.
+// @Description  Create a new task and assign it to users. Can be assigned to all users, users from a state, users from a college, or a single user. XP must be between 1 and MAX_TASK_XP (default 10000); end_at must be in the future and after start_at.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        task  body      CreateTaskRequest  true  "Task information and assignment details"
 // @Success      201   {object}  CreateTaskResponse  "Task created successfully"
-// @Failure      400   {string}  string  "Bad request - invalid input"
+// @Failure      400   {string}  string  "Bad request - invalid input, XP out of range, invalid schedule, or INVALID_TASK_TYPE / INVALID_PROOF_TYPE with valid_values"
 // @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Forbidden - manage_tasks permission required"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /admin/tasks [post]
-func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+func handleCreateTask(adminStore store.AdminStoreInterface, taskStore store.TaskStoreInterface, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -73,12 +109,35 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			http.Error(w, "Missing required fields: title, description, type, proof_type are required", http.StatusBadRequest)
 			return
 		}
+		if err := validator.ValidateTask(store.CreateTaskRequest{Title: req.Title, Description: req.Description}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validator.ValidateTaskXP(req.XP, cfg.MaxTaskXP); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validator.ValidateTaskEndAt(req.EndAt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validator.ValidateTaskSchedule(req.StartAt, req.EndAt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Validate task and proof type
+		if !store.IsValidTaskType(req.Type) {
+			writeInvalidTaskType(w)
+			return
+		}
+		if !store.IsValidProofType(req.ProofType) {
+			writeInvalidProofType(w)
+			return
+		}
 
 		// Validate assignment type
-		if req.AssignmentType != store.AssignmentAll &&
-			req.AssignmentType != store.AssignmentState &&
-			req.AssignmentType != store.AssignmentCollege &&
-			req.AssignmentType != store.AssignmentUser {
+		if !store.IsValidAssignmentType(req.AssignmentType) {
 			http.Error(w, "Invalid assignment_type. Must be one of: all, state, college, user", http.StatusBadRequest)
 			return
 		}
@@ -98,7 +157,6 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 		log.Printf("Admin user ID: %s", adminUserID)
 
 		// Verify admin exists in admins table
-		adminStore := store.NewAdminStore(postgres)
 		_, err := adminStore.GetAdminByID(ctx, adminUserID)
 		if err != nil {
 			log.Printf("Error verifying admin: %v", err)
@@ -106,9 +164,6 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			return
 		}
 
-		// Create task store
-		taskStore := store.NewTaskStore(postgres)
-
 		// Prepare task creation request
 		createReq := store.CreateTaskRequest{
 			Title:       req.Title,
@@ -124,6 +179,9 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			CreatedBy:   adminUserID,
 		}
 
+		// Mask blocked words in the description
+		createReq.Description, _ = moderation.GetWordFilter().Filter(createReq.Description)
+
 		// Set default priority if not provided
 		if createReq.Priority == "" {
 			createReq.Priority = "normal"
@@ -138,7 +196,7 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 		}
 
 		// Send WebSocket notifications to all assigned users
-		wsHub := ws.GetHub()
+		wsHub := ws.GetNotificationHub()
 		if wsHub != nil && len(assignedUserIDs) > 0 {
 			err = ws.SendTaskAssignmentNotification(wsHub, assignedUserIDs, task.ID, task.Title, task.Description)
 			if err != nil {
@@ -149,6 +207,13 @@ func handleCreateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			}
 		}
 
+		// Let every online user know a flash task has started
+		if wsHub != nil && task.IsFlash {
+			if err := ws.BroadcastFlashTaskStarted(wsHub, task.ID, task.Title, task.XP, task.EndAt); err != nil {
+				log.Printf("Error broadcasting flash task: %v", err)
+			}
+		}
+
 		// Return response
 		response := CreateTaskResponse{
 			Task:       task,
@@ -181,7 +246,7 @@ type UpdateTaskRequest struct {
 
 // handleUpdateTask handles updating a task (admin)
 // @Summary      Update task
-// @Description  Update an existing task. Admin only. Sends notifications to assigned users.
+// @Description  Update an existing task. Admin only. Sends notifications to assigned users. XP must be between 1 and MAX_TASK_XP (default 10000); a new end_at must be in the future, and start_at must stay before end_at.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -191,10 +256,11 @@ type UpdateTaskRequest struct {
 // @Success      200      {object}  store.Task  "Task updated successfully"
 // @Failure      400      {string}  string  "Bad request"
 // @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_tasks permission required"
 // @Failure      404      {string}  string  "Task not found"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /admin/tasks/{id} [put]
-func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -229,15 +295,51 @@ func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			return
 		}
 
+		// Validate text lengths of the fields being changed
+		var textFields store.CreateTaskRequest
+		if req.Title != nil {
+			textFields.Title = *req.Title
+		}
+		if req.Description != nil {
+			textFields.Description = *req.Description
+		}
+		if err := validator.ValidateTask(textFields); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.XP != nil {
+			if err := validator.ValidateTaskXP(*req.XP, cfg.MaxTaskXP); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if err := validator.ValidateTaskEndAt(req.EndAt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Verify task exists
 		taskStore := store.NewTaskStore(postgres)
-		_, err = taskStore.GetTaskByID(ctx, taskID)
+		existingTask, err := taskStore.GetTaskByID(ctx, taskID)
 		if err != nil {
 			log.Printf("Error getting task: %v", err)
 			http.Error(w, "Task not found", http.StatusNotFound)
 			return
 		}
 
+		// The schedule must stay valid when only one of start_at/end_at changes
+		newStartAt, newEndAt := existingTask.StartAt, existingTask.EndAt
+		if req.StartAt != nil {
+			newStartAt = req.StartAt
+		}
+		if req.EndAt != nil {
+			newEndAt = req.EndAt
+		}
+		if err := validator.ValidateTaskSchedule(newStartAt, newEndAt); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Update task (we'll need to add UpdateTask method to TaskStore)
 		// For now, we'll use a simple SQL update
 		updateFields := []string{}
@@ -260,11 +362,19 @@ func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			argIndex++
 		}
 		if req.Type != nil {
+			if !store.IsValidTaskType(*req.Type) {
+				writeInvalidTaskType(w)
+				return
+			}
 			updateFields = append(updateFields, fmt.Sprintf("type = $%d", argIndex))
 			args = append(args, *req.Type)
 			argIndex++
 		}
 		if req.ProofType != nil {
+			if !store.IsValidProofType(*req.ProofType) {
+				writeInvalidProofType(w)
+				return
+			}
 			updateFields = append(updateFields, fmt.Sprintf("proof_type = $%d", argIndex))
 			args = append(args, *req.ProofType)
 			argIndex++
@@ -357,7 +467,7 @@ func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 		// In a real system, you'd have a task_assignments table
 		// For now, we'll get users who have submissions or can access the task
 		submissionStore := store.NewSubmissionStore(postgres)
-		submissions, err := submissionStore.GetAllSubmissions(ctx, "")
+		submissions, err := submissionStore.GetAllSubmissions(ctx, store.SubmissionFilter{TaskID: taskID})
 		if err == nil {
 			userIDs := make(map[string]bool)
 			for _, sub := range submissions {
@@ -367,7 +477,7 @@ func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 			}
 
 			// Send notifications to all users assigned to this task
-			wsHub := ws.GetHub()
+			wsHub := ws.GetNotificationHub()
 			if wsHub != nil && len(userIDs) > 0 {
 				userIDList := make([]string, 0, len(userIDs))
 				for uid := range userIDs {
@@ -390,40 +500,117 @@ func handleUpdateTask(postgres *db.Postgres, redisClient *db.Redis) http.Handler
 	}
 }
 
+// SubmissionsResponse is a page of submissions for the admin list.
+// NextCursor is set in cursor mode; Page is set in page/offset mode (use_cursor=false).
+type SubmissionsResponse struct {
+	Submissions []store.Submission `json:"submissions"`
+	NextCursor  string             `json:"next_cursor,omitempty"`
+	Page        int                `json:"page,omitempty"`
+	PageSize    int                `json:"page_size"`
+}
+
 // handleGetSubmissions handles getting all submissions (admin)
 // @Summary      Get all submissions
-// @Description  Get all task submissions with optional status filter. Admin only.
+// @Description  Get task submissions, newest first, with optional filters. Admin only. Uses cursor pagination by default: pass next_cursor from the previous response as cursor. New submissions arriving between requests do not shift pages. Set use_cursor=false for page/offset pagination. sort=priority returns high-priority task submissions first (oldest first within a priority) and unreviewed_first=true puts submissions from users who have never had a submission reviewed at the top; both always use page/offset pagination.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        status  query     string  false  "Filter by status (pending, approved, rejected)"
-// @Success      200     {array}   store.Submission  "List of submissions"
-// @Failure      401     {string}  string  "Unauthorized"
-// @Failure      500     {string}  string  "Internal server error"
+// @Param        status      query     string  false  "Filter by status (pending, approved, rejected)"
+// @Param        task_id     query     string  false  "Filter by task ID"
+// @Param        user_id     query     string  false  "Filter by user ID"
+// @Param        cursor      query     string  false  "Cursor from the previous page's next_cursor"
+// @Param        page_size   query     int     false  "Page size (default 50, max 100)"
+// @Param        use_cursor  query     bool    false  "Set to false to use page/offset pagination (default true)"
+// @Param        page        query     int     false  "Page number when use_cursor=false (default 1)"
+// @Param        sort              query     string  false  "Order: empty for newest first, or priority"
+// @Param        unreviewed_first  query     bool    false  "Put submissions from never-reviewed users first"
+// @Success      200         {object}  SubmissionsResponse  "Page of submissions"
+// @Failure      400         {string}  string  "Invalid cursor or sort"
+// @Failure      401         {string}  string  "Unauthorized"
+// @Failure      500         {string}  string  "Internal server error"
 // @Router       /admin/submissions [get]
-func handleGetSubmissions(postgres *db.Postgres) http.HandlerFunc {
+func handleGetSubmissions(submissionStore store.SubmissionStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		query := r.URL.Query()
+
+		filter := store.SubmissionFilter{
+			Status: query.Get("status"),
+			TaskID: query.Get("task_id"),
+			UserID: query.Get("user_id"),
+			Sort:   query.Get("sort"),
+		}
+		if filter.Sort != "" && filter.Sort != store.SubmissionSortPriority {
+			http.Error(w, "sort must be priority or omitted", http.StatusBadRequest)
+			return
+		}
+		if unreviewedFirstStr := query.Get("unreviewed_first"); unreviewedFirstStr != "" {
+			if parsed, err := strconv.ParseBool(unreviewedFirstStr); err == nil {
+				filter.UnreviewedFirst = parsed
+			}
+		}
 
-		// Get status filter from query parameter
-		statusFilter := r.URL.Query().Get("status")
+		pageSize := 50
+		if pageSizeStr := query.Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
+			}
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
 
-		// Create submission store
-		submissionStore := store.NewSubmissionStore(postgres)
+		useCursor := true
+		if useCursorStr := query.Get("use_cursor"); useCursorStr != "" {
+			if parsed, err := strconv.ParseBool(useCursorStr); err == nil {
+				useCursor = parsed
+			}
+		}
+		// The cursor encodes a position in newest-first order only
+		if filter.CustomOrder() {
+			useCursor = false
+		}
 
-		// Get all submissions
-		submissions, err := submissionStore.GetAllSubmissions(ctx, statusFilter)
+		response := SubmissionsResponse{PageSize: pageSize}
+		var cursor *store.SubmissionCursor
+		offset := 0
+		if useCursor {
+			if cursorStr := query.Get("cursor"); cursorStr != "" {
+				decoded, err := store.DecodeSubmissionCursor(cursorStr)
+				if err != nil {
+					http.Error(w, "Invalid cursor", http.StatusBadRequest)
+					return
+				}
+				cursor = decoded
+			}
+		} else {
+			page := 1
+			if pageStr := query.Get("page"); pageStr != "" {
+				if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+					page = p
+				}
+			}
+			response.Page = page
+			offset = (page - 1) * pageSize
+		}
+
+		submissions, nextCursor, err := submissionStore.ListSubmissions(ctx, filter, cursor, pageSize, offset)
 		if err != nil {
 			log.Printf("Error getting submissions: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to get submissions: %v", err), http.StatusInternalServerError)
 			return
 		}
 
+		response.Submissions = submissions
+		if useCursor && nextCursor != nil {
+			response.NextCursor = nextCursor.Encode()
+		}
+
 		// Return submissions
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(submissions); err != nil {
+		if err := json.NewEncoder(w).Encode(response); err != nil {
 			log.Printf("Error encoding submissions response: %v", err)
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -431,6 +618,383 @@ func handleGetSubmissions(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// handleGetSubmission returns a single submission for review (admin)
+// @Summary      Get submission
+// @Description  Get one submission by ID. Link proofs include link_preview (Open Graph title, description and image) once it has been fetched. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Submission ID"
+// @Success      200  {object}  store.Submission  "Submission"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "Submission not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/submissions/{id} [get]
+func handleGetSubmission(submissionStore store.SubmissionStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		submissionID := chi.URLParam(r, "id")
+		if submissionID == "" {
+			http.Error(w, "Submission ID is required", http.StatusBadRequest)
+			return
+		}
+
+		submission, err := submissionStore.GetSubmissionByID(ctx, submissionID)
+		if err != nil {
+			if err.Error() == "submission not found" {
+				http.Error(w, "Submission not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting submission: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get submission: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(submission); err != nil {
+			log.Printf("Error encoding submission response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// UserSubmissionsResponse is a user's profile with every submission they have made
+type UserSubmissionsResponse struct {
+	User        *store.User        `json:"user"`
+	Submissions []store.Submission `json:"submissions"`
+	Total       int                `json:"total"`
+}
+
+// handleGetUserSubmissions handles getting all submissions for a specific user (admin)
+// @Summary      Get user submissions
+// @Description  Get a user's profile and all of their submissions, newest first, with task_title and task_xp on each. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path      string  true  "User ID"
+// @Success      200  {object}  UserSubmissionsResponse  "User and submissions"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/users/{id}/submissions [get]
+func handleGetUserSubmissions(userStore store.UserStoreInterface, submissionStore store.SubmissionStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID := chi.URLParam(r, "id")
+
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		user, err := userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to get user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		submissions, err := submissionStore.GetAllSubmissions(ctx, store.SubmissionFilter{UserID: userID})
+		if err != nil {
+			log.Printf("Error getting submissions for user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to get submissions: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := UserSubmissionsResponse{
+			User:        user,
+			Submissions: submissions,
+			Total:       len(submissions),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding user submissions response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// UpdateTaskAssignmentRequest represents the request body for re-assigning a task
+type UpdateTaskAssignmentRequest struct {
+	AssignmentType store.AssignmentType `json:"assignment_type"`           // "all", "state", "college", "user"
+	AssignmentID   string               `json:"assignment_id,omitempty"`   // State ID, College ID, or User ID (empty for "all")
+	RemoveExisting bool                 `json:"remove_existing,omitempty"` // Unassign users outside the new scope
+}
+
+// UpdateTaskAssignmentResponse represents the response after re-assigning a task
+type UpdateTaskAssignmentResponse struct {
+	TaskID         string               `json:"task_id"`
+	AssignmentType store.AssignmentType `json:"assignment_type"`
+	AssignmentID   string               `json:"assignment_id,omitempty"`
+	NewlyAssigned  int                  `json:"newly_assigned"` // Users added by the new scope (notified)
+}
+
+// handleUpdateTaskAssignment handles moving an existing task to a new assignment scope (admin)
+// @Summary      Update task assignment
+// @Description  Re-assign a task to all users, a state, a college or a single user. Users newly in scope are assigned and notified. Users outside the new scope stay assigned unless remove_existing is true. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                       true  "Task ID"
+// @Param        request  body      UpdateTaskAssignmentRequest  true  "New assignment scope"
+// @Success      200      {object}  UpdateTaskAssignmentResponse  "Task re-assigned"
+// @Failure      400      {string}  string  "Bad request - invalid assignment"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_tasks permission required"
+// @Failure      404      {string}  string  "Task not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/tasks/{id}/assignment [put]
+func handleUpdateTaskAssignment(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req UpdateTaskAssignmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Error decoding update task assignment request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if !store.IsValidAssignmentType(req.AssignmentType) {
+			http.Error(w, "Invalid assignment_type. Must be one of: all, state, college, user", http.StatusBadRequest)
+			return
+		}
+		if req.AssignmentType == store.AssignmentAll {
+			req.AssignmentID = ""
+		} else if req.AssignmentID == "" {
+			http.Error(w, "assignment_id is required when assignment_type is not 'all'", http.StatusBadRequest)
+			return
+		}
+
+		task, err := taskStore.GetTaskByID(ctx, taskID)
+		if err != nil {
+			log.Printf("Error getting task: %v", err)
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+
+		addedUserIDs, err := taskStore.UpdateTaskAssignment(ctx, taskID, req.AssignmentType, req.AssignmentID, req.RemoveExisting)
+		if err != nil {
+			log.Printf("Error updating task assignment: %v", err)
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to update task assignment: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Only users who weren't assigned before get a notification
+		wsHub := ws.GetNotificationHub()
+		if wsHub != nil && len(addedUserIDs) > 0 {
+			if err := ws.SendTaskAssignmentNotification(wsHub, addedUserIDs, task.ID, task.Title, task.Description); err != nil {
+				log.Printf("Error sending task assignment notifications: %v", err)
+			}
+		}
+
+		response := UpdateTaskAssignmentResponse{
+			TaskID:         taskID,
+			AssignmentType: req.AssignmentType,
+			AssignmentID:   req.AssignmentID,
+			NewlyAssigned:  len(addedUserIDs),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding update task assignment response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// maxTaskAssignmentUsers caps the user IDs accepted by one assign or unassign call
+const maxTaskAssignmentUsers = 1000
+
+// TaskAssignmentUsersRequest represents the request body for assigning or unassigning specific users
+type TaskAssignmentUsersRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// AssignTaskUsersResponse represents the response after assigning a task to extra users
+type AssignTaskUsersResponse struct {
+	TaskID        string `json:"task_id"`
+	NewlyAssigned int    `json:"newly_assigned"` // Users not assigned before (notified)
+}
+
+// UnassignTaskUsersResponse represents the response after unassigning users from a task
+type UnassignTaskUsersResponse struct {
+	TaskID         string   `json:"task_id"`
+	Unassigned     int      `json:"unassigned"`
+	WithSubmission []string `json:"with_submission,omitempty"` // Kept assigned because they already submitted
+}
+
+// decodeTaskAssignmentUsers reads and validates the user_ids body of an assign or unassign call
+func decodeTaskAssignmentUsers(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	var req TaskAssignmentUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Error decoding task assignment users request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(req.UserIDs) == 0 {
+		http.Error(w, "user_ids is required", http.StatusBadRequest)
+		return nil, false
+	}
+	if len(req.UserIDs) > maxTaskAssignmentUsers {
+		http.Error(w, fmt.Sprintf("At most %d user_ids per request", maxTaskAssignmentUsers), http.StatusBadRequest)
+		return nil, false
+	}
+	return req.UserIDs, true
+}
+
+// handleAssignTaskUsers handles assigning an existing task to specific extra users (admin)
+// @Summary      Assign task to users
+// @Description  Assign a task to specific users in addition to its assignment scope, e.g. users who joined after it was created. Unknown and already assigned users are skipped. Newly assigned users are notified. At most 1000 user IDs per call. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Task ID"
+// @Param        request  body      TaskAssignmentUsersRequest  true  "Users to assign"
+// @Success      200      {object}  AssignTaskUsersResponse  "Users assigned"
+// @Failure      400      {string}  string  "Bad request - missing or too many user_ids"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_tasks permission required"
+// @Failure      404      {string}  string  "Task not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/tasks/{id}/assign [post]
+func handleAssignTaskUsers(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		userIDs, ok := decodeTaskAssignmentUsers(w, r)
+		if !ok {
+			return
+		}
+
+		task, err := taskStore.GetTaskByID(ctx, taskID)
+		if err != nil {
+			log.Printf("Error getting task: %v", err)
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+
+		addedUserIDs, err := taskStore.AddTaskAssignment(ctx, taskID, userIDs)
+		if err != nil {
+			log.Printf("Error assigning task users: %v", err)
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to assign task: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		wsHub := ws.GetNotificationHub()
+		if wsHub != nil && len(addedUserIDs) > 0 {
+			if err := ws.SendTaskAssignmentNotification(wsHub, addedUserIDs, task.ID, task.Title, task.Description); err != nil {
+				log.Printf("Error sending task assignment notifications: %v", err)
+			}
+		}
+
+		response := AssignTaskUsersResponse{
+			TaskID:        taskID,
+			NewlyAssigned: len(addedUserIDs),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding assign task users response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleUnassignTaskUsers handles removing specific users from a task's assignment (admin)
+// @Summary      Unassign task from users
+// @Description  Remove specific users from a task's assignment. Users who already have a submission for the task stay assigned and are listed in with_submission. At most 1000 user IDs per call. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                      true  "Task ID"
+// @Param        request  body      TaskAssignmentUsersRequest  true  "Users to unassign"
+// @Success      200      {object}  UnassignTaskUsersResponse  "Users unassigned"
+// @Failure      400      {string}  string  "Bad request - missing or too many user_ids"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_tasks permission required"
+// @Failure      404      {string}  string  "Task not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/tasks/{id}/unassign [delete]
+func handleUnassignTaskUsers(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		userIDs, ok := decodeTaskAssignmentUsers(w, r)
+		if !ok {
+			return
+		}
+
+		removed, withSubmission, err := taskStore.RemoveTaskAssignment(ctx, taskID, userIDs)
+		if err != nil {
+			log.Printf("Error unassigning task users: %v", err)
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to unassign task: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := UnassignTaskUsersResponse{
+			TaskID:         taskID,
+			Unassigned:     removed,
+			WithSubmission: withSubmission,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding unassign task users response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 // ApproveSubmissionRequest represents the request body for approving a submission
 type ApproveSubmissionRequest struct {
 	Comment string `json:"comment,omitempty"` // Optional admin comment
@@ -438,7 +1002,7 @@ type ApproveSubmissionRequest struct {
 
 // handleApproveSubmission handles approving a submission (admin)
 // @Summary      Approve submission
-// @Description  Approve a task submission. Admin only.
+// @Description  Approve a task submission. Admin only. XP is awarded and the user notified asynchronously. If this is the user's first approved submission, whoever referred them earns the referral bonus.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -451,7 +1015,7 @@ type ApproveSubmissionRequest struct {
 // @Failure      404      {string}  string  "Submission not found"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /admin/submissions/{id}/approve [post]
-func handleApproveSubmission(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+func handleApproveSubmission(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, xpWorker *worker.XPWorker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -518,6 +1082,14 @@ func handleApproveSubmission(postgres *db.Postgres, redisClient *db.Redis) http.
 			return
 		}
 
+		// The referrer earns a bonus when the user's first submission is approved
+		priorApproved, err := submissionStore.CountApprovedSubmissions(ctx, existingSubmission.UserID)
+		if err != nil {
+			log.Printf("Error counting approved submissions: %v", err)
+			http.Error(w, "Failed to approve submission", http.StatusInternalServerError)
+			return
+		}
+
 		// Approve submission
 		submission, err := submissionStore.ApproveSubmission(ctx, submissionID, adminUserID, req.Comment)
 		if err != nil {
@@ -529,57 +1101,38 @@ func handleApproveSubmission(postgres *db.Postgres, redisClient *db.Redis) http.
 			http.Error(w, fmt.Sprintf("Failed to approve submission: %v", err), http.StatusInternalServerError)
 			return
 		}
+		ws.InvalidateUserStatsCache(ctx, redisClient, submission.UserID)
 
-		// Award XP to user for task approval
-		xpAwarded := 0
-		if task.XP > 0 {
-			xpStore := store.NewXPStore(postgres)
-			xpLog, err := xpStore.AwardXP(ctx, store.AwardXPRequest{
+		// Award XP and notify the user in the background (always notify, even if XP is 0)
+		xpWorker.Submit(worker.AwardXPRequest{
+			AwardXPRequest: store.AwardXPRequest{
 				UserID:   submission.UserID,
 				XP:       task.XP,
 				Source:   store.XPSourceTaskApproval,
 				SourceID: submission.TaskID, // Link XP to the task
-			})
-			if err != nil {
-				log.Printf("Error awarding XP: %v", err)
-				// Log error but don't fail the approval - XP can be awarded manually later if needed
-				// In production, you might want to use a queue/retry mechanism for XP awards
-			} else {
-				xpAwarded = task.XP
-				log.Printf("Awarded %d XP to user %s for task approval (task_id: %s, xp_log_id: %s)",
-					task.XP, submission.UserID, submission.TaskID, xpLog.ID)
-
-				// Broadcast leaderboard updates with user's new rank and XP
-				userStore := store.NewUserStore(postgres)
-				leaderboardStore := store.NewLeaderboardStore(postgres)
-				user, err := userStore.GetUserByID(ctx, submission.UserID)
-				if err == nil {
-					rank, _ := leaderboardStore.GetUserRank(ctx, submission.UserID)
-					newXP := user.XP
-
-					ws.BroadcastLeaderboardUpdate(redisClient, "pan-india", "", submission.UserID, rank, newXP)
-					if user.StateID != "" {
-						ws.BroadcastLeaderboardUpdate(redisClient, "state", user.StateID, submission.UserID, rank, newXP)
-					}
-					if user.CollegeID != "" {
-						ws.BroadcastLeaderboardUpdate(redisClient, "college", user.CollegeID, submission.UserID, rank, newXP)
-					}
-				}
-			}
+			},
+			TaskID:    task.ID,
+			TaskTitle: task.Title,
+		})
+
+		if priorApproved == 0 {
+			awardReferralBonus(ctx, postgres, redisClient, cfg, submission.UserID)
 		}
 
-		// Send WebSocket notification to user about task approval (always send, even if XP is 0)
-		wsHub := ws.GetHub()
-		if wsHub != nil {
-			err = ws.SendTaskApprovalNotification(wsHub, submission.UserID, task.ID, task.Title, xpAwarded)
-			if err != nil {
-				log.Printf("Error sending task approval notification: %v", err)
-				// Don't fail the request if notification fails
-			} else {
-				log.Printf("Sent task approval notification to user %s for task %s", submission.UserID, task.ID)
-			}
+		// Record onboarding progress for the task completer
+		onboardingStore := store.NewOnboardingStore(postgres)
+		if _, err := onboardingStore.MarkStep(ctx, submission.UserID, store.OnboardingStepCompleteFirstTask); err != nil {
+			log.Printf("Error marking onboarding step: %v", err)
 		}
 
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      submission.UserID,
+			EventType:   store.ActivityEventSubmissionApproved,
+			EntityID:    submission.TaskID,
+			EntityType:  "task",
+			Description: fmt.Sprintf("Completed %s", task.Title),
+		})
+
 		// Create feed entry for approved submission
 		feedStore := store.NewFeedStore(postgres)
 		err = feedStore.CreateFeedEntry(ctx, submission.ID, submission.UserID, submission.TaskID)
@@ -626,7 +1179,7 @@ type RejectSubmissionRequest struct {
 // @Failure      404      {string}  string  "Submission not found"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /admin/submissions/{id}/reject [post]
-func handleRejectSubmission(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleRejectSubmission(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -693,22 +1246,26 @@ func handleRejectSubmission(postgres *db.Postgres, cfg *env.Config) http.Handler
 			http.Error(w, fmt.Sprintf("Failed to reject submission: %v", err), http.StatusInternalServerError)
 			return
 		}
+		ws.InvalidateUserStatsCache(ctx, redisClient, rejectedSubmission.UserID)
 
 		// Delete proof file from S3 (submission record remains)
 		if existingSubmission.ProofURL != "" {
 			s3Storage, s3Err := storage.NewS3Storage(storage.S3Config{
-				Region:             cfg.AWSRegion,
-				ProfileBucket:      cfg.AWSProfileBucket,
-				ResumeBucket:       cfg.AWSResumeBucket,
-				TaskProofBucket:    cfg.AWSTaskProofBucket,
-				AccessKeyID:        cfg.AWSAccessKeyID,
-				SecretAccessKey:    cfg.AWSSecretAccessKey,
-				ProfilePublicURL:   cfg.AWSProfilePublicURL,
-				ResumePublicURL:    cfg.AWSResumePublicURL,
-				TaskProofPublicURL: cfg.AWSTaskProofPublicURL,
+				Region:                cfg.AWSRegion,
+				ProfileBucket:         cfg.AWSProfileBucket,
+				ResumeBucket:          cfg.AWSResumeBucket,
+				TaskProofBucket:       cfg.AWSTaskProofBucket,
+				AccessKeyID:           cfg.AWSAccessKeyID,
+				SecretAccessKey:       cfg.AWSSecretAccessKey,
+				ProfilePublicURL:      cfg.AWSProfilePublicURL,
+				ResumePublicURL:       cfg.AWSResumePublicURL,
+				TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+				ProfileBucketRegion:   cfg.AWSProfileBucketRegion,
+				ResumeBucketRegion:    cfg.AWSResumeBucketRegion,
+				TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
 			})
 			if s3Err == nil {
-				proofKey := extractTaskProofKeyFromURL(existingSubmission.ProofURL)
+				proofKey := extractS3KeyFromURL(existingSubmission.ProofURL, s3Storage.GetTaskProofPublicURL())
 				if proofKey != "" {
 					if delErr := s3Storage.DeleteTaskProof(ctx, proofKey); delErr != nil {
 						log.Printf("Error deleting rejected submission proof from S3 (submission %s): %v", submissionID, delErr)
@@ -731,8 +1288,16 @@ func handleRejectSubmission(postgres *db.Postgres, cfg *env.Config) http.Handler
 			taskTitle = task.Title
 		}
 
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      existingSubmission.UserID,
+			EventType:   store.ActivityEventSubmissionRejected,
+			EntityID:    existingSubmission.ID,
+			EntityType:  "submission",
+			Description: fmt.Sprintf("Submission for %s was rejected", taskTitle),
+		})
+
 		// Send WebSocket notification to user about task rejection (always send, even if task lookup failed)
-		wsHub := ws.GetHub()
+		wsHub := ws.GetNotificationHub()
 		if wsHub != nil {
 			err = ws.SendTaskRejectionNotification(wsHub, existingSubmission.UserID, existingSubmission.TaskID, taskTitle, req.Comment)
 			if err != nil {
@@ -785,7 +1350,7 @@ func handleRejectSubmission(postgres *db.Postgres, cfg *env.Config) http.Handler
 // @Failure      401       {string}  string  "Unauthorized"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /admin/users [get]
-func handleGetAllUsers(postgres *db.Postgres) http.HandlerFunc {
+func handleGetAllUsers(adminStore store.AdminStoreInterface, userStore store.UserStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -795,7 +1360,6 @@ func handleGetAllUsers(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		adminStore := store.NewAdminStore(postgres)
 		adminUserID, _ := GetUserIDFromContext(ctx)
 		_, err := adminStore.GetAdminByID(ctx, adminUserID)
 		if err != nil {
@@ -824,7 +1388,6 @@ func handleGetAllUsers(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		userStore := store.NewUserStore(postgres)
 		users, err := userStore.GetAllUsers(ctx, pageSize, offset)
 		if err != nil {
 			log.Printf("Error getting all users: %v", err)
@@ -885,10 +1448,11 @@ type AddXPRequest struct {
 // @Success      200   {object}  object  "xp_awarded, new_total_xp, xp_log_id"
 // @Failure      400   {string}  string  "Bad request"
 // @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Forbidden - manage_users permission required"
 // @Failure      404   {string}  string  "User not found"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /admin/users/xp [post]
-func handleAddXP(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+func handleAddXP(adminStore store.AdminStoreInterface, xpStore store.XPStoreInterface, userStore store.UserStoreInterface, redisClient *db.Redis) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -898,7 +1462,6 @@ func handleAddXP(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc
 			return
 		}
 
-		adminStore := store.NewAdminStore(postgres)
 		_, err := adminStore.GetAdminByID(ctx, adminUserID)
 		if err != nil {
 			log.Printf("Error verifying admin: %v", err)
@@ -921,12 +1484,11 @@ func handleAddXP(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc
 			return
 		}
 
-		xpStore := store.NewXPStore(postgres)
 		xpLog, err := xpStore.AwardXP(ctx, store.AwardXPRequest{
-			UserID:   req.UserID,
-			XP:       req.XP,
-			Source:   store.XPSourceAdminGrant,
-			SourceID: req.Reason,
+			UserID: req.UserID,
+			XP:     req.XP,
+			Source: store.XPSourceAdminGrant,
+			Reason: req.Reason,
 		})
 		if err != nil {
 			log.Printf("Error awarding XP: %v", err)
@@ -937,22 +1499,13 @@ func handleAddXP(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc
 			http.Error(w, fmt.Sprintf("Failed to add XP: %v", err), http.StatusInternalServerError)
 			return
 		}
+		notifyLevelUp(xpLog)
 
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, req.UserID)
 		if err != nil {
 			log.Printf("Error getting user after XP award: %v", err)
 		} else {
-			leaderboardStore := store.NewLeaderboardStore(postgres)
-			rank, _ := leaderboardStore.GetUserRank(ctx, req.UserID)
-			newXP := user.XP
-			ws.BroadcastLeaderboardUpdate(redisClient, "pan-india", "", req.UserID, rank, newXP)
-			if user.StateID != "" {
-				ws.BroadcastLeaderboardUpdate(redisClient, "state", user.StateID, req.UserID, rank, newXP)
-			}
-			if user.CollegeID != "" {
-				ws.BroadcastLeaderboardUpdate(redisClient, "college", user.CollegeID, req.UserID, rank, newXP)
-			}
+			ws.BroadcastUserXPChange(ctx, redisClient, user)
 		}
 
 		response := map[string]interface{}{
@@ -997,6 +1550,7 @@ type CreateBadgeRequest struct {
 // @Success      201   {object}  store.Badge  "Badge created successfully"
 // @Failure      400   {string}  string  "Bad request"
 // @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Forbidden - manage_badges permission required"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /admin/badges [post]
 func handleCreateBadge(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
@@ -1064,19 +1618,27 @@ func handleCreateBadge(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		if badgeBucket == "" {
 			badgeBucket = cfg.AWSProfileBucket // Fallback to profile bucket
 		}
+		badgeBucketRegion := cfg.AWSBadgeBucketRegion
+		if cfg.AWSBadgeBucket == "" {
+			badgeBucketRegion = cfg.AWSProfileBucketRegion
+		}
 
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:             cfg.AWSRegion,
-			ProfileBucket:      cfg.AWSProfileBucket,
-			ResumeBucket:       cfg.AWSResumeBucket,
-			TaskProofBucket:    cfg.AWSTaskProofBucket,
-			BadgeBucket:        badgeBucket,
-			AccessKeyID:        cfg.AWSAccessKeyID,
-			SecretAccessKey:    cfg.AWSSecretAccessKey,
-			ProfilePublicURL:   cfg.AWSProfilePublicURL,
-			ResumePublicURL:    cfg.AWSResumePublicURL,
-			TaskProofPublicURL: cfg.AWSTaskProofPublicURL,
-			BadgePublicURL:     cfg.AWSBadgePublicURL,
+			Region:                cfg.AWSRegion,
+			ProfileBucket:         cfg.AWSProfileBucket,
+			ResumeBucket:          cfg.AWSResumeBucket,
+			TaskProofBucket:       cfg.AWSTaskProofBucket,
+			BadgeBucket:           badgeBucket,
+			AccessKeyID:           cfg.AWSAccessKeyID,
+			SecretAccessKey:       cfg.AWSSecretAccessKey,
+			ProfilePublicURL:      cfg.AWSProfilePublicURL,
+			ResumePublicURL:       cfg.AWSResumePublicURL,
+			TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+			BadgePublicURL:        cfg.AWSBadgePublicURL,
+			ProfileBucketRegion:   cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:    cfg.AWSResumeBucketRegion,
+			TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
+			BadgeBucketRegion:     badgeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -1181,24 +1743,89 @@ func handleCreateBadge(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 	}
 }
 
-// extractTaskProofKeyFromURL extracts the S3 object key from a task proof URL.
-// URL format: https://bucket.s3.region.amazonaws.com/task-proofs/taskID/userID_filename.ext
-// Returns the path part (e.g. task-proofs/taskID/userID_filename.ext) or empty if parse fails.
-func extractTaskProofKeyFromURL(proofURL string) string {
-	u, err := url.Parse(proofURL)
-	if err != nil {
+// extractS3KeyFromURL extracts the S3 object key from the public URL of an uploaded file.
+// URL format: https://bucket.s3.region.amazonaws.com/task-proofs/taskID/userID_timestamp.ext
+// Returns everything after scheme and host (e.g. task-proofs/taskID/userID_timestamp.ext) without
+// query string or fragment. When the file is served from publicURL and publicURL has a path of its
+// own (a CDN origin folder), that path is not part of the key and is stripped too.
+// Returns empty if the URL does not parse or has no key.
+func extractS3KeyFromURL(fileURL, publicURL string) string {
+	u, err := url.Parse(fileURL)
+	if err != nil || u.Host == "" {
 		return ""
 	}
-	return strings.TrimPrefix(u.Path, "/")
+	key := u.Path
+	if base, err := url.Parse(publicURL); err == nil && publicURL != "" && strings.EqualFold(base.Host, u.Host) {
+		if prefix := strings.TrimRight(base.Path, "/"); prefix != "" && strings.HasPrefix(key, prefix+"/") {
+			key = strings.TrimPrefix(key, prefix)
+		}
+	}
+	return strings.TrimLeft(key, "/")
+}
+
+// adminAuthMiddleware authenticates admin requests. It runs after JWTAuthMiddleware, which has already
+// validated the Bearer token: it requires the admin role, checks the admin account still exists and
+// loads its ID and permissions into context.
+func adminAuthMiddleware(postgres *db.Postgres, cfg *env.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			adminID, ok := GetUserIDFromContext(ctx)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			// User tokens are signed with the same secret, so the role is what keeps them off admin routes
+			if role, _ := GetUserRoleFromContext(ctx); role != auth.RoleAdmin {
+				http.Error(w, "Forbidden: admin access required", http.StatusForbidden)
+				return
+			}
+
+			adminStore := store.NewAdminStore(postgres)
+			admin, err := adminStore.GetAdminByID(ctx, adminID)
+			if err != nil {
+				// Deleted admins keep valid tokens until they expire
+				if err.Error() == "admin not found" {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+				log.Printf("Error getting admin %s: %v", adminID, err)
+				http.Error(w, "Failed to authenticate admin", http.StatusInternalServerError)
+				return
+			}
+
+			// Tokens issued before 2FA was enabled must log in again
+			if verified, _ := ctx.Value(TwoFAVerifiedKey).(bool); admin.TOTPEnabled && !verified {
+				http.Error(w, "Two-factor authentication required. Please log in again.", http.StatusUnauthorized)
+				return
+			}
+
+			permissions := admin.Permissions
+			if permissions == nil {
+				permissions = []string{}
+			}
+			ctx = context.WithValue(ctx, AdminIDKey, admin.ID)
+			ctx = context.WithValue(ctx, AdminPermissionsKey, permissions)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
-// adminAuthMiddleware handles admin authentication
-func adminAuthMiddleware(cfg *env.Config) func(http.Handler) http.Handler {
+// RequirePermission rejects admin requests whose permissions (set by adminAuthMiddleware) do not include perm
+func RequirePermission(perm string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// TODO: Implement admin authentication
-			log.Printf("Admin middleware: %s %s", r.Method, r.URL.Path)
-			next.ServeHTTP(w, r)
+			permissions, _ := GetAdminPermissionsFromContext(r.Context())
+			for _, p := range permissions {
+				if p == perm {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, fmt.Sprintf("Forbidden: %s permission required", perm), http.StatusForbidden)
 		})
 	}
 }
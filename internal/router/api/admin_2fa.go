@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+const (
+	// totpIssuer is shown as the account issuer in authenticator apps
+	totpIssuer = "Grove Admin"
+	// totpCodeReuseWindow covers the current and adjacent 30s TOTP periods accepted by totp.Validate
+	totpCodeReuseWindow = 90 * time.Second
+	// totpMaxFailedAttempts is how many wrong codes an admin may submit per totpFailedAttemptsWindow
+	totpMaxFailedAttempts    = 5
+	totpFailedAttemptsWindow = auth.Pending2FATokenExpiry
+)
+
+// Admin2FASetupResponse is returned when 2FA setup starts
+type Admin2FASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// URI to render as a QR code
+}
+
+// Admin2FACodeRequest is the body for confirming 2FA setup
+type Admin2FACodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Admin2FAVerifyRequest is the body for completing a 2FA login
+type Admin2FAVerifyRequest struct {
+	Token string `json:"token"` // Pending token from POST /admin/login
+	Code  string `json:"code"`
+}
+
+// checkTOTPCode validates code against secret, rejecting a code that was already used and
+// limiting failed attempts per admin. Returns an error message suitable for the client when rejected.
+func checkTOTPCode(ctx context.Context, redisClient *db.Redis, adminID, secret, code string) (bool, string) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return false, "code is required"
+	}
+
+	attemptsKey := fmt.Sprintf("totp_attempts:%s", adminID)
+	if redisClient != nil {
+		if attempts, err := redisClient.Client.Get(ctx, attemptsKey).Int(); err == nil && attempts >= totpMaxFailedAttempts {
+			return false, "Too many invalid codes. Please try again later."
+		}
+	}
+
+	if !totp.Validate(code, secret) {
+		if redisClient != nil {
+			pipe := redisClient.Client.TxPipeline()
+			pipe.Incr(ctx, attemptsKey)
+			pipe.ExpireNX(ctx, attemptsKey, totpFailedAttemptsWindow)
+			if _, err := pipe.Exec(ctx); err != nil {
+				log.Printf("Error recording failed TOTP attempt for admin %s: %v", adminID, err)
+			}
+		}
+		return false, "Invalid code"
+	}
+
+	// A code stays valid for its whole period, so remember it to prevent replay
+	if redisClient != nil {
+		usedKey := fmt.Sprintf("totp_used:%s:%s", adminID, code)
+		fresh, err := redisClient.Client.SetNX(ctx, usedKey, 1, totpCodeReuseWindow).Result()
+		if err != nil {
+			log.Printf("Error recording used TOTP code for admin %s: %v", adminID, err)
+		} else if !fresh {
+			return false, "Code has already been used"
+		}
+		redisClient.Client.Del(ctx, attemptsKey)
+	}
+
+	return true, ""
+}
+
+// handleSetupAdmin2FA starts TOTP setup for the authenticated admin
+// @Summary      Start admin 2FA setup
+// @Description  Generate a new TOTP secret for the authenticated admin and return its provisioning URI for QR code generation. 2FA stays disabled until confirmed with POST /admin/me/2fa/confirm.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  Admin2FASetupResponse  "TOTP secret and provisioning URI"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      409  {string}  string  "2FA is already enabled"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/me/2fa/setup [post]
+func handleSetupAdmin2FA(adminStore store.AdminStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		admin, err := adminStore.GetAdminByID(ctx, adminID)
+		if err != nil {
+			log.Printf("Error verifying admin: %v", err)
+			http.Error(w, "Admin not found. Please use a valid admin account.", http.StatusUnauthorized)
+			return
+		}
+		if admin.TOTPEnabled {
+			http.Error(w, "2FA is already enabled", http.StatusConflict)
+			return
+		}
+
+		key, err := totp.Generate(totp.GenerateOpts{
+			Issuer:      totpIssuer,
+			AccountName: admin.Username,
+		})
+		if err != nil {
+			log.Printf("Error generating TOTP secret for admin %s: %v", adminID, err)
+			http.Error(w, "Failed to set up 2FA", http.StatusInternalServerError)
+			return
+		}
+
+		if err := adminStore.SetTOTPSecret(ctx, adminID, key.Secret()); err != nil {
+			log.Printf("Error storing TOTP secret for admin %s: %v", adminID, err)
+			http.Error(w, "Failed to set up 2FA", http.StatusInternalServerError)
+			return
+		}
+
+		response := Admin2FASetupResponse{
+			Secret:          key.Secret(),
+			ProvisioningURI: key.URL(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding 2FA setup response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleConfirmAdmin2FA enables 2FA once the admin proves their authenticator works
+// @Summary      Confirm admin 2FA setup
+// @Description  Verify a TOTP code for the secret from POST /admin/me/2fa/setup and enable 2FA. Returns a new token with two_fa_verified: true; tokens issued before this stop working.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      Admin2FACodeRequest  true  "TOTP code"
+// @Success      200      {object}  map[string]string  "2FA enabled, new token"
+// @Failure      400      {string}  string  "Bad request - setup not started or code missing"
+// @Failure      401      {string}  string  "Unauthorized - invalid code"
+// @Failure      409      {string}  string  "2FA is already enabled"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/me/2fa/confirm [post]
+func handleConfirmAdmin2FA(adminStore store.AdminStoreInterface, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req Admin2FACodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		admin, err := adminStore.GetAdminByID(ctx, adminID)
+		if err != nil {
+			log.Printf("Error verifying admin: %v", err)
+			http.Error(w, "Admin not found. Please use a valid admin account.", http.StatusUnauthorized)
+			return
+		}
+
+		secret, enabled, err := adminStore.GetTOTPSecret(ctx, adminID)
+		if err != nil {
+			log.Printf("Error getting TOTP secret for admin %s: %v", adminID, err)
+			http.Error(w, "Failed to confirm 2FA", http.StatusInternalServerError)
+			return
+		}
+		if enabled {
+			http.Error(w, "2FA is already enabled", http.StatusConflict)
+			return
+		}
+		if secret == "" {
+			http.Error(w, "2FA setup has not been started", http.StatusBadRequest)
+			return
+		}
+
+		if valid, message := checkTOTPCode(ctx, redisClient, adminID, secret, req.Code); !valid {
+			http.Error(w, message, http.StatusUnauthorized)
+			return
+		}
+
+		if err := adminStore.EnableTOTP(ctx, adminID); err != nil {
+			log.Printf("Error enabling TOTP for admin %s: %v", adminID, err)
+			http.Error(w, "Failed to confirm 2FA", http.StatusInternalServerError)
+			return
+		}
+
+		expiryDuration, err := auth.ParseExpiryDuration(cfg.JWTExpiry)
+		if err != nil {
+			log.Printf("Error parsing JWT expiry: %v", err)
+			expiryDuration = 24 * time.Hour
+		}
+
+		token, err := auth.GenerateTwoFAVerifiedToken(admin.ID, admin.Username, "admin", cfg.JWTSecret, expiryDuration)
+		if err != nil {
+			log.Printf("Error generating JWT token: %v", err)
+			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"message": "2FA enabled successfully",
+			"token":   token,
+		}); err != nil {
+			log.Printf("Error encoding 2FA confirm response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleVerifyAdmin2FA exchanges a pending login token and TOTP code for a full admin token
+// @Summary      Verify admin 2FA login
+// @Description  Complete login for an admin with 2FA enabled. Send the pending token from POST /admin/login and a current TOTP code. Each code can only be used once.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      Admin2FAVerifyRequest  true  "Pending token and TOTP code"
+// @Success      200      {object}  AdminLoginResponse  "Login successful"
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      401      {string}  string  "Unauthorized - invalid token or code"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/auth/2fa/verify [post]
+func handleVerifyAdmin2FA(adminStore store.AdminStoreInterface, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req Admin2FAVerifyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Token == "" {
+			http.Error(w, "token is required", http.StatusBadRequest)
+			return
+		}
+
+		claims, err := auth.ValidateToken(req.Token, cfg.JWTSecret)
+		if err != nil || claims.Role != auth.RolePending2FA {
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		admin, err := adminStore.GetAdminByID(ctx, claims.UserID)
+		if err != nil {
+			log.Printf("Error getting admin for 2FA verify: %v", err)
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		secret, enabled, err := adminStore.GetTOTPSecret(ctx, admin.ID)
+		if err != nil {
+			log.Printf("Error getting TOTP secret for admin %s: %v", admin.ID, err)
+			http.Error(w, "Failed to verify code", http.StatusInternalServerError)
+			return
+		}
+		if !enabled || secret == "" {
+			http.Error(w, "2FA is not enabled for this account", http.StatusBadRequest)
+			return
+		}
+
+		if valid, message := checkTOTPCode(ctx, redisClient, admin.ID, secret, req.Code); !valid {
+			http.Error(w, message, http.StatusUnauthorized)
+			return
+		}
+
+		expiryDuration, err := auth.ParseExpiryDuration(cfg.JWTExpiry)
+		if err != nil {
+			log.Printf("Error parsing JWT expiry: %v", err)
+			expiryDuration = 24 * time.Hour
+		}
+
+		token, err := auth.GenerateTwoFAVerifiedToken(admin.ID, admin.Username, "admin", cfg.JWTSecret, expiryDuration)
+		if err != nil {
+			log.Printf("Error generating JWT token: %v", err)
+			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+			return
+		}
+
+		response := AdminLoginResponse{
+			Token: token,
+			Admin: admin,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding 2FA verify response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleSetupAdmin2FA(t *testing.T) {
+	tests := []struct {
+		name        string
+		adminID     string
+		totpEnabled bool
+		getErr      error
+		setErr      error
+		wantStatus  int
+		wantStored  bool
+	}{
+		{name: "generates secret", adminID: testutil.TestAdminID, wantStatus: http.StatusOK, wantStored: true},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "unknown admin", adminID: testutil.TestAdminID, getErr: errors.New("admin not found"), wantStatus: http.StatusUnauthorized},
+		{name: "already enabled", adminID: testutil.TestAdminID, totpEnabled: true, wantStatus: http.StatusConflict},
+		{name: "store error", adminID: testutil.TestAdminID, setErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantStored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored string
+			adminStore := &mock.AdminStore{
+				GetAdminByIDFunc: func(ctx context.Context, adminID string) (*store.Admin, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return testutil.NewTestAdmin(func(a *store.Admin) { a.TOTPEnabled = tt.totpEnabled }), nil
+				},
+				SetTOTPSecretFunc: func(ctx context.Context, adminID, secret string) error {
+					stored = secret
+					return tt.setErr
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/admin/me/2fa/setup", ""), tt.adminID)
+			w := serve(t, handleSetupAdmin2FA(adminStore), r, tt.wantStatus)
+			if (stored != "") != tt.wantStored {
+				t.Errorf("SetTOTPSecret called = %v, want %v", stored != "", tt.wantStored)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got Admin2FASetupResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Secret != stored {
+				t.Errorf("secret = %q, stored %q", got.Secret, stored)
+			}
+			if !strings.HasPrefix(got.ProvisioningURI, "otpauth://totp/") || !strings.Contains(got.ProvisioningURI, "testadmin") {
+				t.Errorf("provisioning_uri = %q", got.ProvisioningURI)
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package api
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/rohit21755/groveserverv2/internal/auth"
-	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
@@ -22,13 +26,16 @@ type CreateAdminRequest struct {
 
 // CreateAdminResponse represents the response after creating an admin
 type CreateAdminResponse struct {
-	Admin *store.Admin `json:"admin"`
-	Message string    `json:"message"`
+	Admin   *store.Admin `json:"admin"`
+	Message string       `json:"message"`
 }
 
+// adminBootstrapSecretHeader carries the secret for POST /admin/bootstrap
+const adminBootstrapSecretHeader = "X-Admin-Bootstrap-Secret"
+
 // handleCreateAdmin handles creating a new admin user
 // @Summary      Create admin
-// @Description  Create a new admin user. This should be protected and only accessible by super admins.
+// @Description  Create a new admin user with the default permissions. Requires an admin JWT with the manage_admins (super-admin) permission. The first admin is created with POST /admin/bootstrap instead.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -37,64 +44,106 @@ type CreateAdminResponse struct {
 // @Success      201    {object}  CreateAdminResponse  "Admin created successfully"
 // @Failure      400    {string}  string  "Bad request - invalid input or username already exists"
 // @Failure      401    {string}  string  "Unauthorized"
+// @Failure      403    {string}  string  "Forbidden - manage_admins permission required"
 // @Failure      500    {string}  string  "Internal server error"
 // @Router       /admin/create [post]
-func handleCreateAdmin(postgres *db.Postgres) http.HandlerFunc {
+func handleCreateAdmin(adminStore store.AdminStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		createAdmin(w, r, adminStore, nil)
+	}
+}
 
-		// Parse request body
-		var req CreateAdminRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			log.Printf("Error decoding create admin request: %v", err)
-			http.Error(w, "Invalid request body", http.StatusBadRequest)
+// handleBootstrapAdmin creates a super-admin without a JWT, gated by ADMIN_BOOTSTRAP_SECRET
+// @Summary      Bootstrap super-admin
+// @Description  Create an admin with every permission, including manage_admins. No JWT is needed; the X-Admin-Bootstrap-Secret header must match ADMIN_BOOTSTRAP_SECRET. Disabled (403) when ADMIN_BOOTSTRAP_SECRET is unset, which is the expected state after initial setup.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        X-Admin-Bootstrap-Secret  header    string              true  "Bootstrap secret"
+// @Param        admin                     body      CreateAdminRequest  true  "Admin information"
+// @Success      201                       {object}  CreateAdminResponse  "Super-admin created successfully"
+// @Failure      400                       {string}  string  "Bad request - invalid input or username already exists"
+// @Failure      401                       {string}  string  "Unauthorized - missing or wrong bootstrap secret"
+// @Failure      403                       {string}  string  "Forbidden - bootstrap disabled"
+// @Failure      500                       {string}  string  "Internal server error"
+// @Router       /admin/bootstrap [post]
+func handleBootstrapAdmin(adminStore store.AdminStoreInterface, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cfg.AdminBootstrapSecret == "" {
+			log.Printf("[Admin] Bootstrap attempt from %s rejected: ADMIN_BOOTSTRAP_SECRET is not set, use POST /admin/create with a super-admin JWT", r.RemoteAddr)
+			http.Error(w, "Admin bootstrap is disabled", http.StatusForbidden)
 			return
 		}
 
-		// Validate required fields
-		if req.Name == "" || req.Username == "" || req.Password == "" {
-			http.Error(w, "name, username, and password are required", http.StatusBadRequest)
+		secret := r.Header.Get(adminBootstrapSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.AdminBootstrapSecret)) != 1 {
+			log.Printf("[Admin] Bootstrap attempt from %s rejected: invalid secret", r.RemoteAddr)
+			http.Error(w, "Invalid bootstrap secret", http.StatusUnauthorized)
 			return
 		}
 
-		// Validate password strength (minimum 8 characters)
-		if len(req.Password) < 8 {
-			http.Error(w, "password must be at least 8 characters long", http.StatusBadRequest)
-			return
-		}
+		createAdmin(w, r, adminStore, store.AllAdminPermissions)
+	}
+}
 
-		// Create admin store
-		adminStore := store.NewAdminStore(postgres)
+// createAdmin decodes a CreateAdminRequest and creates the admin. When permissions
+// is non-nil it replaces the default permission set.
+func createAdmin(w http.ResponseWriter, r *http.Request, adminStore store.AdminStoreInterface, permissions []string) {
+	ctx := r.Context()
 
-		// Create admin
-		admin, err := adminStore.CreateAdmin(ctx, store.CreateAdminRequest{
-			Name:     req.Name,
-			Username: req.Username,
-			Password: req.Password,
-		})
-		if err != nil {
-			log.Printf("Error creating admin: %v", err)
-			if err.Error() == "username already exists" {
-				http.Error(w, "Username already exists", http.StatusBadRequest)
-				return
-			}
-			http.Error(w, fmt.Sprintf("Failed to create admin: %v", err), http.StatusInternalServerError)
-			return
-		}
+	// Parse request body
+	var req CreateAdminRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Error decoding create admin request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-		// Return response
-		response := CreateAdminResponse{
-			Admin:   admin,
-			Message: "Admin created successfully",
-		}
+	// Validate required fields
+	if req.Name == "" || req.Username == "" || req.Password == "" {
+		http.Error(w, "name, username, and password are required", http.StatusBadRequest)
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			log.Printf("Error encoding create admin response: %v", err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	// Validate password strength
+	if err := auth.ValidatePasswordStrength(req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Create admin
+	admin, err := adminStore.CreateAdmin(ctx, store.CreateAdminRequest{
+		Name:        req.Name,
+		Username:    req.Username,
+		Password:    req.Password,
+		Permissions: permissions,
+	})
+	if err != nil {
+		log.Printf("Error creating admin: %v", err)
+		if err.Error() == "username already exists" {
+			http.Error(w, "Username already exists", http.StatusBadRequest)
 			return
 		}
+		http.Error(w, fmt.Sprintf("Failed to create admin: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if permissions != nil {
+		log.Printf("[Admin] Bootstrapped super-admin %s (%s)", admin.Username, admin.ID)
+	}
+
+	// Return response
+	response := CreateAdminResponse{
+		Admin:   admin,
+		Message: "Admin created successfully",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding create admin response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
 	}
 }
 
@@ -106,13 +155,14 @@ type AdminLoginRequest struct {
 
 // AdminLoginResponse represents the response after admin login
 type AdminLoginResponse struct {
-	Token string       `json:"token"`
-	Admin *store.Admin `json:"admin"`
+	Token       string       `json:"token"`
+	Admin       *store.Admin `json:"admin"`
+	Requires2FA bool         `json:"requires_2fa,omitempty"` // Token is a short-lived pending token; call POST /admin/auth/2fa/verify
 }
 
 // handleAdminLogin handles admin login
 // @Summary      Admin login
-// @Description  Login as admin and get JWT token
+// @Description  Login as admin and get JWT token. When the admin has 2FA enabled the response has requires_2fa: true and a 5-minute pending token that must be exchanged at POST /admin/auth/2fa/verify.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -122,7 +172,7 @@ type AdminLoginResponse struct {
 // @Failure      401          {string}  string  "Unauthorized - invalid username or password"
 // @Failure      500          {string}  string  "Internal server error"
 // @Router       /admin/login [post]
-func handleAdminLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleAdminLogin(adminStore store.AdminStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -140,9 +190,6 @@ func handleAdminLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
-		// Create admin store
-		adminStore := store.NewAdminStore(postgres)
-
 		// Get admin by username
 		admin, err := adminStore.GetAdminByUsername(ctx, req.Username)
 		if err != nil {
@@ -164,6 +211,28 @@ func handleAdminLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
+		// With 2FA enabled, hand out a pending token that only the verify endpoint accepts
+		if admin.TOTPEnabled {
+			pendingToken, err := auth.GenerateToken(admin.ID, admin.Username, auth.RolePending2FA, cfg.JWTSecret, auth.Pending2FATokenExpiry)
+			if err != nil {
+				log.Printf("Error generating pending 2FA token: %v", err)
+				http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if err := json.NewEncoder(w).Encode(AdminLoginResponse{
+				Token:       pendingToken,
+				Admin:       admin,
+				Requires2FA: true,
+			}); err != nil {
+				log.Printf("Error encoding admin login response: %v", err)
+				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			}
+			return
+		}
+
 		// Generate JWT token
 		expiryDuration, err := auth.ParseExpiryDuration(cfg.JWTExpiry)
 		if err != nil {
@@ -171,7 +240,7 @@ func handleAdminLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			expiryDuration = 24 * time.Hour // Default to 24 hours
 		}
 
-		token, err := auth.GenerateToken(admin.ID, admin.Username, "admin", cfg.JWTSecret, expiryDuration)
+		token, err := auth.GenerateToken(admin.ID, admin.Username, auth.RoleAdmin, cfg.JWTSecret, expiryDuration)
 		if err != nil {
 			log.Printf("Error generating JWT token: %v", err)
 			http.Error(w, "Failed to generate token", http.StatusInternalServerError)
@@ -193,3 +262,215 @@ func handleAdminLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 		}
 	}
 }
+
+// ChangeAdminPasswordRequest represents the request to change the current admin's password
+type ChangeAdminPasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// handleChangeAdminPassword handles changing the authenticated admin's password
+// @Summary      Change admin password
+// @Description  Change the password of the authenticated admin. The new password must be at least 10 characters and contain an uppercase letter, a digit and a special character.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        passwords  body      ChangeAdminPasswordRequest  true  "Current and new password"
+// @Success      200        {object}  map[string]string  "Password updated successfully"
+// @Failure      400        {string}  string  "Bad request - invalid input or weak password"
+// @Failure      401        {string}  string  "Unauthorized - current password is incorrect"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /admin/me/password [post]
+func handleChangeAdminPassword(adminStore store.AdminStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Get admin ID from context (set by JWT middleware)
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Parse request body
+		var req ChangeAdminPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Error decoding change admin password request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// Validate required fields
+		if req.CurrentPassword == "" || req.NewPassword == "" {
+			http.Error(w, "current_password and new_password are required", http.StatusBadRequest)
+			return
+		}
+
+		// Validate new password strength
+		if err := auth.ValidatePasswordStrength(req.NewPassword); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Get admin to resolve username for password verification
+		admin, err := adminStore.GetAdminByID(ctx, adminID)
+		if err != nil {
+			log.Printf("Error verifying admin: %v", err)
+			http.Error(w, "Admin not found. Please use a valid admin account.", http.StatusUnauthorized)
+			return
+		}
+
+		// Verify current password
+		valid, err := adminStore.VerifyAdminPassword(ctx, admin.Username, req.CurrentPassword)
+		if err != nil {
+			log.Printf("Error verifying admin password: %v", err)
+			http.Error(w, "Failed to verify credentials", http.StatusInternalServerError)
+			return
+		}
+
+		if !valid {
+			http.Error(w, "Current password is incorrect", http.StatusUnauthorized)
+			return
+		}
+
+		// Hash new password
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			log.Printf("Error hashing admin password: %v", err)
+			http.Error(w, "Failed to update password", http.StatusInternalServerError)
+			return
+		}
+
+		// Store new password hash
+		if err := adminStore.UpdateAdminPassword(ctx, admin.ID, string(hashedPassword)); err != nil {
+			log.Printf("Error updating admin password: %v", err)
+			http.Error(w, "Failed to update password", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"message": "Password updated successfully",
+		}); err != nil {
+			log.Printf("Error encoding change admin password response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// UpdateAdminPermissionsRequest represents the request body for replacing an admin's permissions
+type UpdateAdminPermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// handleGetMyAdminPermissions returns the authenticated admin's permissions
+// @Summary      Get my admin permissions
+// @Description  Get the permission scopes of the authenticated admin
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "permissions"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Router       /admin/me/permissions [get]
+func handleGetMyAdminPermissions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if _, ok := GetUserIDFromContext(ctx); !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		permissions, _ := GetAdminPermissionsFromContext(ctx)
+		if permissions == nil {
+			permissions = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"permissions": permissions,
+		})
+	}
+}
+
+// handleUpdateAdminPermissions replaces another admin's permissions (super-admin only)
+// @Summary      Update admin permissions
+// @Description  Replace the permission scopes of an admin. Requires manage_admins. You cannot remove manage_admins from yourself.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                         true  "Admin ID"
+// @Param        request  body      UpdateAdminPermissionsRequest  true  "New permissions"
+// @Success      200      {object}  store.Admin  "Updated admin"
+// @Failure      400      {string}  string  "Bad request - invalid permission"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden"
+// @Failure      404      {string}  string  "Admin not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/{id}/permissions [put]
+func handleUpdateAdminPermissions(adminStore store.AdminStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		currentAdminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		adminID := chi.URLParam(r, "id")
+		if adminID == "" {
+			http.Error(w, "Admin ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req UpdateAdminPermissionsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// Prevent super-admins from locking themselves out
+		if adminID == currentAdminID {
+			keepsManageAdmins := false
+			for _, p := range req.Permissions {
+				if p == store.PermissionManageAdmins {
+					keepsManageAdmins = true
+					break
+				}
+			}
+			if !keepsManageAdmins {
+				http.Error(w, "Cannot remove manage_admins from yourself", http.StatusBadRequest)
+				return
+			}
+		}
+
+		admin, err := adminStore.UpdateAdminPermissions(ctx, adminID, req.Permissions)
+		if err != nil {
+			if err.Error() == "admin not found" {
+				http.Error(w, "Admin not found", http.StatusNotFound)
+				return
+			}
+			if strings.HasPrefix(err.Error(), "invalid permission") {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error updating admin permissions: %v", err)
+			http.Error(w, "Failed to update permissions", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(admin); err != nil {
+			log.Printf("Error encoding admin response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
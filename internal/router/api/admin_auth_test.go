@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleBootstrapAdmin(t *testing.T) {
+	const validBody = `{"name":"Root","username":"root","password":"Str0ng!pass"}`
+	tests := []struct {
+		name            string
+		configSecret    string
+		headerSecret    string
+		body            string
+		createErr       error
+		wantStatus      int
+		wantPermissions bool
+	}{
+		{name: "creates super-admin", configSecret: "s3cret", headerSecret: "s3cret", body: validBody, wantStatus: http.StatusCreated, wantPermissions: true},
+		{name: "disabled without secret", headerSecret: "s3cret", body: validBody, wantStatus: http.StatusForbidden},
+		{name: "wrong secret", configSecret: "s3cret", headerSecret: "guess", body: validBody, wantStatus: http.StatusUnauthorized},
+		{name: "missing secret", configSecret: "s3cret", body: validBody, wantStatus: http.StatusUnauthorized},
+		{name: "missing fields", configSecret: "s3cret", headerSecret: "s3cret", body: `{"name":"Root"}`, wantStatus: http.StatusBadRequest},
+		{name: "weak password", configSecret: "s3cret", headerSecret: "s3cret", body: `{"name":"Root","username":"root","password":"password"}`, wantStatus: http.StatusBadRequest},
+		{name: "username taken", configSecret: "s3cret", headerSecret: "s3cret", body: validBody, createErr: errors.New("username already exists"), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPermissions := false
+			adminStore := &mock.AdminStore{
+				CreateAdminFunc: func(ctx context.Context, req store.CreateAdminRequest) (*store.Admin, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					// Permissions go in with the admin; UpdateAdminPermissions is not set, so a
+					// second call to grant them would panic
+					gotPermissions = len(req.Permissions) == len(store.AllAdminPermissions)
+					return testutil.NewTestAdmin(func(a *store.Admin) { a.Permissions = req.Permissions }), nil
+				},
+			}
+
+			r := newTestRequest(http.MethodPost, "/admin/bootstrap", tt.body)
+			if tt.headerSecret != "" {
+				r.Header.Set(adminBootstrapSecretHeader, tt.headerSecret)
+			}
+			serve(t, handleBootstrapAdmin(adminStore, &env.Config{AdminBootstrapSecret: tt.configSecret}), r, tt.wantStatus)
+			if gotPermissions != tt.wantPermissions {
+				t.Errorf("every permission granted = %v, want %v", gotPermissions, tt.wantPermissions)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		wantStatus  int
+	}{
+		{name: "has permission", permissions: []string{store.PermissionManageTasks, store.PermissionManageUsers}, wantStatus: http.StatusOK},
+		{name: "other permissions only", permissions: []string{store.PermissionManageTasks}, wantStatus: http.StatusForbidden},
+		{name: "no permissions", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			r := withAdmin(newTestRequest(http.MethodGet, "/admin/users", ""), testutil.TestAdminID, tt.permissions...)
+			serve(t, RequirePermission(store.PermissionManageUsers)(next), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleGetSubmission(t *testing.T) {
+	tests := []struct {
+		name         string
+		submissionID string
+		err          error
+		wantStatus   int
+	}{
+		{name: "found", submissionID: testutil.TestSubmissionID, wantStatus: http.StatusOK},
+		{name: "missing ID", wantStatus: http.StatusBadRequest},
+		{name: "not found", submissionID: testutil.TestSubmissionID, err: errors.New("submission not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", submissionID: testutil.TestSubmissionID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submissionStore := &mock.SubmissionStore{
+				GetSubmissionByIDFunc: func(ctx context.Context, submissionID string) (*store.Submission, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return testutil.NewTestSubmission(), nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/admin/submissions/x", ""), "id", tt.submissionID)
+			serve(t, handleGetSubmission(submissionStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleCreateTaskProofType(t *testing.T) {
+	tests := []struct {
+		name        string
+		proofType   string
+		wantStatus  int
+		wantCreated bool
+	}{
+		{name: "image", proofType: "image", wantStatus: http.StatusCreated, wantCreated: true},
+		{name: "video", proofType: "video", wantStatus: http.StatusCreated, wantCreated: true},
+		{name: "link", proofType: "link", wantStatus: http.StatusCreated, wantCreated: true},
+		{name: "unknown", proofType: "file", wantStatus: http.StatusBadRequest},
+		{name: "wrong case", proofType: "Link", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			adminStore := &mock.AdminStore{
+				GetAdminByIDFunc: func(ctx context.Context, adminID string) (*store.Admin, error) {
+					return testutil.NewTestAdmin(), nil
+				},
+			}
+			taskStore := &mock.TaskStore{
+				CreateTaskFunc: func(ctx context.Context, req store.CreateTaskRequest, assignmentType store.AssignmentType, assignmentID string) (*store.Task, []string, error) {
+					created = true
+					return testutil.NewTestTask(func(task *store.Task) { task.ProofType = req.ProofType }), nil, nil
+				},
+			}
+
+			body := `{"title":"Share your post","description":"Post about Grove on LinkedIn","xp":50,"type":"online","proof_type":"` + tt.proofType + `","assignment_type":"all"}`
+			r := withAdmin(newTestRequest(http.MethodPost, "/admin/tasks", body), testutil.TestAdminID, store.PermissionManageTasks)
+			w := serve(t, handleCreateTask(adminStore, taskStore, nil, &env.Config{MaxTaskXP: 10000}), r, tt.wantStatus)
+			if created != tt.wantCreated {
+				t.Errorf("CreateTask called = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantStatus != http.StatusBadRequest {
+				return
+			}
+			var got map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got["code"] != "INVALID_PROOF_TYPE" {
+				t.Errorf("code = %v, want INVALID_PROOF_TYPE", got["code"])
+			}
+		})
+	}
+}
+
+func TestHandleCreateTaskValidation(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	soon := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	later := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	tests := []struct {
+		name       string
+		fields     string
+		wantStatus int
+	}{
+		{name: "minimum XP", fields: `"xp":1`, wantStatus: http.StatusCreated},
+		{name: "maximum XP", fields: `"xp":10000`, wantStatus: http.StatusCreated},
+		{name: "zero XP", fields: `"xp":0`, wantStatus: http.StatusBadRequest},
+		{name: "negative XP", fields: `"xp":-1`, wantStatus: http.StatusBadRequest},
+		{name: "XP over maximum", fields: `"xp":99999`, wantStatus: http.StatusBadRequest},
+		{name: "end_at in the past", fields: `"xp":50,"end_at":"` + past + `"`, wantStatus: http.StatusBadRequest},
+		{name: "start_at after end_at", fields: `"xp":50,"start_at":"` + later + `","end_at":"` + soon + `"`, wantStatus: http.StatusBadRequest},
+		{name: "start_at before end_at", fields: `"xp":50,"start_at":"` + soon + `","end_at":"` + later + `"`, wantStatus: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			adminStore := &mock.AdminStore{
+				GetAdminByIDFunc: func(ctx context.Context, adminID string) (*store.Admin, error) {
+					return testutil.NewTestAdmin(), nil
+				},
+			}
+			taskStore := &mock.TaskStore{
+				CreateTaskFunc: func(ctx context.Context, req store.CreateTaskRequest, assignmentType store.AssignmentType, assignmentID string) (*store.Task, []string, error) {
+					created = true
+					return testutil.NewTestTask(), nil, nil
+				},
+			}
+
+			body := `{"title":"Share your post","description":"Post about Grove on LinkedIn","type":"online","proof_type":"link","assignment_type":"all",` + tt.fields + `}`
+			r := withAdmin(newTestRequest(http.MethodPost, "/admin/tasks", body), testutil.TestAdminID, store.PermissionManageTasks)
+			serve(t, handleCreateTask(adminStore, taskStore, nil, &env.Config{MaxTaskXP: 10000}), r, tt.wantStatus)
+			if wantCreated := tt.wantStatus == http.StatusCreated; created != wantCreated {
+				t.Errorf("CreateTask called = %v, want %v", created, wantCreated)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateTaskValidation(t *testing.T) {
+	taskEnd := time.Now().Add(time.Hour).UTC()
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	afterTaskEnd := taskEnd.Add(time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name      string
+		body      string
+		fetchTask bool // whether the handler gets as far as loading the task
+	}{
+		{name: "zero XP", body: `{"xp":0}`},
+		{name: "negative XP", body: `{"xp":-1}`},
+		{name: "XP over maximum", body: `{"xp":99999}`},
+		{name: "end_at in the past", body: `{"end_at":"` + past + `"}`},
+		// Only start_at changes, so it is checked against the end_at already stored
+		{name: "start_at after stored end_at", body: `{"start_at":"` + afterTaskEnd + `"}`, fetchTask: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`SELECT id, name, username, role, permissions, totp_enabled, created_at, updated_at\s+FROM admins WHERE id = \$1`).
+				WithArgs(testutil.TestAdminID).
+				WillReturnRows([]string{"id", "name", "username", "role", "permissions", "totp_enabled", "created_at", "updated_at"},
+					[]any{testutil.TestAdminID, "Test Admin", "testadmin", "admin", store.AllAdminPermissions, false, testutil.TestTime, testutil.TestTime})
+			if tt.fetchTask {
+				mockDB.ExpectQuery(`FROM tasks WHERE id = \$1`).
+					WithArgs(testutil.TestTaskID).
+					WillReturnRows([]string{"id", "title", "description", "xp", "type", "proof_type", "priority", "start_at", "end_at", "is_flash", "is_weekly", "created_by", "created_at", "status"},
+						[]any{testutil.TestTaskID, "Share your post", "Post about Grove on LinkedIn", 50, "online", "link", "medium", nil, taskEnd, false, false, testutil.TestAdminID, testutil.TestTime, "ongoing"})
+			}
+
+			r := withURLParams(newTestRequest(http.MethodPut, "/admin/tasks/x", tt.body), "id", testutil.TestTaskID)
+			r = withAdmin(r, testutil.TestAdminID, store.PermissionManageTasks)
+			serve(t, handleUpdateTask(postgres, nil, &env.Config{MaxTaskXP: 10000}), r, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestHandleGetSubmissionsOrder(t *testing.T) {
+	cursor := store.SubmissionCursor{CreatedAt: testutil.TestTime, ID: testutil.TestSubmissionID}.Encode()
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantFilter store.SubmissionFilter
+		wantCursor bool
+		wantOffset int
+	}{
+		{name: "default newest first", query: "cursor=" + cursor, wantStatus: http.StatusOK, wantCursor: true},
+		{name: "priority", query: "sort=priority", wantStatus: http.StatusOK, wantFilter: store.SubmissionFilter{Sort: store.SubmissionSortPriority}},
+		{name: "unreviewed first", query: "unreviewed_first=true", wantStatus: http.StatusOK, wantFilter: store.SubmissionFilter{UnreviewedFirst: true}},
+		{name: "unreviewed first off", query: "unreviewed_first=false", wantStatus: http.StatusOK},
+		// A cursor only means something in newest-first order, so custom orders page by offset
+		{name: "priority ignores cursor", query: "sort=priority&cursor=" + cursor + "&page=3&page_size=10", wantStatus: http.StatusOK, wantFilter: store.SubmissionFilter{Sort: store.SubmissionSortPriority}, wantOffset: 20},
+		{name: "unknown sort", query: "sort=xp", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			submissionStore := &mock.SubmissionStore{
+				ListSubmissionsFunc: func(ctx context.Context, filter store.SubmissionFilter, cursor *store.SubmissionCursor, limit, offset int) ([]store.Submission, *store.SubmissionCursor, error) {
+					called = true
+					if filter != tt.wantFilter {
+						t.Errorf("filter = %+v, want %+v", filter, tt.wantFilter)
+					}
+					if (cursor != nil) != tt.wantCursor {
+						t.Errorf("cursor = %v, want one: %v", cursor, tt.wantCursor)
+					}
+					if offset != tt.wantOffset {
+						t.Errorf("offset = %d, want %d", offset, tt.wantOffset)
+					}
+					return []store.Submission{*testutil.NewTestSubmission()}, nil, nil
+				},
+			}
+
+			r := newTestRequest(http.MethodGet, "/admin/submissions?"+tt.query, "")
+			serve(t, handleGetSubmissions(submissionStore), r, tt.wantStatus)
+			if wantCalled := tt.wantStatus == http.StatusOK; called != wantCalled {
+				t.Errorf("ListSubmissions called = %v, want %v", called, wantCalled)
+			}
+		})
+	}
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	const testSecret = "test-secret"
+	token := func(userID, role string, expiry time.Duration) string {
+		tok, err := auth.GenerateToken(userID, "", role, testSecret, expiry)
+		if err != nil {
+			t.Fatalf("generating token: %v", err)
+		}
+		return tok
+	}
+	adminColumns := []string{"id", "name", "username", "role", "permissions", "totp_enabled", "created_at", "updated_at"}
+	tests := []struct {
+		name       string
+		header     string
+		userBanned *bool // set when the ban check runs, i.e. for non-admin tokens
+		adminRow   []any // nil when the admin lookup does not run
+		adminFound bool
+		wantStatus int
+	}{
+		{
+			name:       "admin token",
+			header:     "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, time.Hour),
+			adminRow:   []any{testutil.TestAdminID, "Test Admin", "testadmin", "admin", []string{store.PermissionManageTasks}, false, testutil.TestTime, testutil.TestTime},
+			adminFound: true,
+			wantStatus: http.StatusOK,
+		},
+		{name: "user token", header: "Bearer " + token(testutil.TestUserID, "student", time.Hour), userBanned: new(bool), wantStatus: http.StatusForbidden},
+		{name: "deleted admin", header: "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, time.Hour), adminRow: []any{}, wantStatus: http.StatusUnauthorized},
+		{
+			name:       "2FA enabled since the token was issued",
+			header:     "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, time.Hour),
+			adminRow:   []any{testutil.TestAdminID, "Test Admin", "testadmin", "admin", []string{store.PermissionManageTasks}, true, testutil.TestTime, testutil.TestTime},
+			adminFound: true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{name: "expired admin token", header: "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, -time.Minute), wantStatus: http.StatusUnauthorized},
+		{name: "malformed token", header: "Bearer not.a.jwt", wantStatus: http.StatusUnauthorized},
+		{name: "missing token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.userBanned != nil {
+				mockDB.ExpectQuery(`SELECT is_banned FROM users`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"is_banned"}, []any{*tt.userBanned})
+			}
+			if tt.adminRow != nil {
+				var rows [][]any
+				if tt.adminFound {
+					rows = append(rows, tt.adminRow)
+				}
+				mockDB.ExpectQuery(`FROM admins WHERE id = \$1`).
+					WithArgs(testutil.TestAdminID).
+					WillReturnRows(adminColumns, rows...)
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if adminID, _ := GetAdminIDFromContext(r.Context()); adminID != testutil.TestAdminID {
+					t.Errorf("admin ID in context = %q, want %q", adminID, testutil.TestAdminID)
+				}
+				if permissions, _ := GetAdminPermissionsFromContext(r.Context()); len(permissions) != 1 || permissions[0] != store.PermissionManageTasks {
+					t.Errorf("permissions in context = %v, want [%s]", permissions, store.PermissionManageTasks)
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			cfg := &env.Config{JWTSecret: testSecret}
+			r := newTestRequest(http.MethodGet, "/admin/tasks", "")
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			serve(t, JWTAuthMiddleware(postgres, cfg)(adminAuthMiddleware(postgres, cfg)(next)), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestExtractS3KeyFromURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileURL   string
+		publicURL string
+		want      string
+	}{
+		{name: "single segment", fileURL: "https://bucket.s3.ap-south-1.amazonaws.com/abc123_resume.pdf", want: "abc123_resume.pdf"},
+		{name: "multi segment", fileURL: "https://bucket.s3.ap-south-1.amazonaws.com/resumes/abc123_resume.pdf", want: "resumes/abc123_resume.pdf"},
+		{name: "task proof", fileURL: "https://proofs.s3.ap-south-1.amazonaws.com/task-proofs/task/user_1.png", want: "task-proofs/task/user_1.png"},
+		{name: "custom CDN domain", fileURL: "https://cdn.example.com/task-proofs/task/user_1.png", publicURL: "https://cdn.example.com", want: "task-proofs/task/user_1.png"},
+		{name: "CDN with origin folder", fileURL: "https://cdn.example.com/grove/task-proofs/task/user_1.png", publicURL: "https://cdn.example.com/grove/", want: "task-proofs/task/user_1.png"},
+		{name: "folder of another host kept", fileURL: "https://other.example.com/grove/task-proofs/task/user_1.png", publicURL: "https://cdn.example.com/grove", want: "grove/task-proofs/task/user_1.png"},
+		{name: "query string", fileURL: "https://cdn.example.com/task-proofs/task/user_1.png?X-Amz-Expires=900&X-Amz-Signature=abc", want: "task-proofs/task/user_1.png"},
+		{name: "fragment", fileURL: "https://cdn.example.com/task-proofs/task/user_1.png#preview", want: "task-proofs/task/user_1.png"},
+		{name: "percent-encoded", fileURL: "https://cdn.example.com/task-proofs/task/my%20proof.png", want: "task-proofs/task/my proof.png"},
+		{name: "double slash after public URL", fileURL: "https://cdn.example.com//task-proofs/task/user_1.png", want: "task-proofs/task/user_1.png"},
+		{name: "no key", fileURL: "https://cdn.example.com/", want: ""},
+		{name: "relative", fileURL: "task-proofs/task/user_1.png", want: ""},
+		{name: "unparseable", fileURL: "https://cdn.example.com/%zz", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractS3KeyFromURL(tt.fileURL, tt.publicURL); got != tt.want {
+				t.Errorf("extractS3KeyFromURL(%q, %q) = %q, want %q", tt.fileURL, tt.publicURL, got, tt.want)
+			}
+		})
+	}
+}
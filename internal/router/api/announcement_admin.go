@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// announcementCooldown is the minimum time between two broadcasts by the same admin
+const announcementCooldown = 10 * time.Minute
+
+// BroadcastAnnouncementRequest represents the request to broadcast an announcement
+type BroadcastAnnouncementRequest struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// handleBroadcastAnnouncement handles broadcasting an announcement to all users (admin)
+// @Summary      Broadcast announcement
+// @Description  Send an announcement to all online users via WebSocket and store it so offline users can see it in /api/notifications. Limited to one broadcast per 10 minutes per admin.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        announcement  body      BroadcastAnnouncementRequest  true  "Announcement"
+// @Success      201           {object}  store.Announcement  "Announcement broadcast"
+// @Failure      400           {string}  string  "Bad request"
+// @Failure      401           {string}  string  "Unauthorized"
+// @Failure      403           {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      429           {string}  string  "Too many requests - one broadcast per 10 minutes"
+// @Failure      500           {string}  string  "Internal server error"
+// @Router       /admin/broadcast [post]
+func handleBroadcastAnnouncement(adminStore store.AdminStoreInterface, announcementStore store.AnnouncementStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Parse request body
+		var req BroadcastAnnouncementRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Error decoding broadcast request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// Validate required fields
+		if req.Title == "" || req.Message == "" {
+			http.Error(w, "title and message are required", http.StatusBadRequest)
+			return
+		}
+		if req.Type == "" {
+			req.Type = string(ws.NotificationTypeAnnouncement)
+		}
+
+		// Get admin user ID from context (set by JWT middleware)
+		adminUserID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Admin user ID not found in context. Please ensure you are authenticated.", http.StatusUnauthorized)
+			return
+		}
+
+		// Verify admin exists in admins table
+		_, err := adminStore.GetAdminByID(ctx, adminUserID)
+		if err != nil {
+			log.Printf("Error verifying admin: %v", err)
+			http.Error(w, "Admin not found. Please use a valid admin account.", http.StatusUnauthorized)
+			return
+		}
+
+		// Rate limit: one broadcast per 10 minutes per admin
+		rateLimitKey := fmt.Sprintf("admin_broadcast:%s", adminUserID)
+		if redisClient != nil {
+			acquired, err := redisClient.Client.SetNX(ctx, rateLimitKey, time.Now().Unix(), announcementCooldown).Result()
+			if err != nil {
+				log.Printf("Error checking broadcast rate limit: %v", err)
+				http.Error(w, "Failed to broadcast announcement", http.StatusInternalServerError)
+				return
+			}
+			if !acquired {
+				http.Error(w, "You can only broadcast once every 10 minutes", http.StatusTooManyRequests)
+				return
+			}
+		}
+		releaseRateLimit := func() {
+			if redisClient != nil {
+				redisClient.Client.Del(ctx, rateLimitKey)
+			}
+		}
+
+		// Store announcement so offline users can see it later
+		announcement, err := announcementStore.CreateAnnouncement(ctx, store.CreateAnnouncementRequest{
+			AdminID: adminUserID,
+			Title:   req.Title,
+			Message: req.Message,
+			Type:    req.Type,
+		})
+		if err != nil {
+			// Nothing was broadcast, so the admin can retry straight away
+			releaseRateLimit()
+			log.Printf("Error creating announcement: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to create announcement: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Broadcast to all online users
+		wsHub := ws.GetNotificationHub()
+		if wsHub != nil {
+			err = ws.SendAnnouncement(wsHub, announcement.ID, announcement.Title, announcement.Message, map[string]interface{}{
+				"announcement_type": announcement.Type,
+			})
+			if err != nil {
+				log.Printf("Error broadcasting announcement: %v", err)
+				// Don't fail the request if broadcast fails - announcement is stored
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(announcement); err != nil {
+			log.Printf("Error encoding announcement response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleBroadcastAnnouncement(t *testing.T) {
+	rateLimitKey := "admin_broadcast:" + testutil.TestAdminID
+	tests := []struct {
+		name        string
+		body        string
+		adminErr    error
+		cooldown    bool
+		createErr   error
+		wantStatus  int
+		wantType    string
+		wantCreated bool
+		wantLimited bool
+	}{
+		{name: "broadcasts", body: `{"title":"Hi","message":"Welcome"}`, wantStatus: http.StatusCreated, wantType: string(ws.NotificationTypeAnnouncement), wantCreated: true, wantLimited: true},
+		{name: "keeps type", body: `{"title":"Hi","message":"Welcome","type":"maintenance"}`, wantStatus: http.StatusCreated, wantType: "maintenance", wantCreated: true, wantLimited: true},
+		{name: "missing message", body: `{"title":"Hi"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unknown admin", body: `{"title":"Hi","message":"Welcome"}`, adminErr: errors.New("admin not found"), wantStatus: http.StatusUnauthorized},
+		{name: "within cooldown", body: `{"title":"Hi","message":"Welcome"}`, cooldown: true, wantStatus: http.StatusTooManyRequests, wantLimited: true},
+		{name: "store error releases cooldown", body: `{"title":"Hi","message":"Welcome"}`, createErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantType: string(ws.NotificationTypeAnnouncement), wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.cooldown {
+				server.Set(rateLimitKey, "1")
+			}
+			created := false
+			adminStore := &mock.AdminStore{
+				GetAdminByIDFunc: func(ctx context.Context, adminID string) (*store.Admin, error) {
+					return testutil.NewTestAdmin(), tt.adminErr
+				},
+			}
+			announcementStore := &mock.AnnouncementStore{
+				CreateAnnouncementFunc: func(ctx context.Context, req store.CreateAnnouncementRequest) (*store.Announcement, error) {
+					created = true
+					if req.Type != tt.wantType {
+						t.Errorf("type = %q, want %q", req.Type, tt.wantType)
+					}
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &store.Announcement{ID: "a1", AdminID: req.AdminID, Title: req.Title, Message: req.Message, Type: req.Type}, nil
+				},
+			}
+
+			r := withAdmin(newTestRequest(http.MethodPost, "/admin/broadcast", tt.body), testutil.TestAdminID)
+			serve(t, handleBroadcastAnnouncement(adminStore, announcementStore, redisClient), r, tt.wantStatus)
+			if created != tt.wantCreated {
+				t.Errorf("CreateAnnouncement called = %v, want %v", created, tt.wantCreated)
+			}
+			if server.Exists(rateLimitKey) != tt.wantLimited {
+				t.Errorf("cooldown set = %v, want %v", server.Exists(rateLimitKey), tt.wantLimited)
+			}
+			if tt.wantStatus == http.StatusCreated {
+				if ttl := server.TTL(rateLimitKey); ttl != announcementCooldown {
+					t.Errorf("cooldown TTL = %v, want %v", ttl, announcementCooldown)
+				}
+			}
+		})
+	}
+}
+
+// The cooldown lapses after announcementCooldown
+func TestHandleBroadcastAnnouncementCooldownExpires(t *testing.T) {
+	redisClient, server := testutil.NewMockRedis(t)
+	adminStore := &mock.AdminStore{
+		GetAdminByIDFunc: func(ctx context.Context, adminID string) (*store.Admin, error) {
+			return testutil.NewTestAdmin(), nil
+		},
+	}
+	announcementStore := &mock.AnnouncementStore{
+		CreateAnnouncementFunc: func(ctx context.Context, req store.CreateAnnouncementRequest) (*store.Announcement, error) {
+			return &store.Announcement{ID: "a1", Title: req.Title, Message: req.Message, Type: req.Type}, nil
+		},
+	}
+	handler := handleBroadcastAnnouncement(adminStore, announcementStore, redisClient)
+	body := `{"title":"Hi","message":"Welcome"}`
+
+	serve(t, handler, withAdmin(newTestRequest(http.MethodPost, "/admin/broadcast", body), testutil.TestAdminID), http.StatusCreated)
+	serve(t, handler, withAdmin(newTestRequest(http.MethodPost, "/admin/broadcast", body), testutil.TestAdminID), http.StatusTooManyRequests)
+	server.FastForward(announcementCooldown + time.Second)
+	serve(t, handler, withAdmin(newTestRequest(http.MethodPost, "/admin/broadcast", body), testutil.TestAdminID), http.StatusCreated)
+}
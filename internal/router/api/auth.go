@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +15,7 @@ import (
 	"github.com/rohit21755/groveserverv2/internal/env"
 	"github.com/rohit21755/groveserverv2/internal/storage"
 	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/validator"
 )
 
 // LoginRequest represents the login request body
@@ -40,7 +42,7 @@ type LoginResponse struct {
 // @Failure      401         {string}  string  "Invalid credentials"
 // @Failure      500         {string}  string  "Internal server error"
 // @Router       /api/auth/login [post]
-func handleLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleLogin(userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -58,9 +60,6 @@ func handleLogin(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
-		// Create user store
-		userStore := store.NewUserStore(postgres)
-
 		// Get password hash
 		passwordHash, err := userStore.GetUserPasswordHash(ctx, loginReq.Email)
 		if err != nil {
@@ -123,7 +122,7 @@ type RegisterResponse struct {
 
 // handleRegister handles user registration
 // @Summary      User registration
-// @Description  Register a new user account. Each user gets a unique referral code automatically. Referral code (input), resume, and profile picture are optional. Returns JWT token for automatic login.
+// @Description  Register a new user account. Each user gets a unique referral code automatically. Referral code (input), resume, and profile picture are optional. Returns JWT token for automatic login. A verification link is emailed; tasks cannot be submitted until the email is verified.
 // @Tags         auth
 // @Accept       multipart/form-data
 // @Produce      json
@@ -145,13 +144,15 @@ func handleRegister(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:           cfg.AWSRegion,
-			ProfileBucket:    cfg.AWSProfileBucket,
-			ResumeBucket:     cfg.AWSResumeBucket,
-			AccessKeyID:      cfg.AWSAccessKeyID,
-			SecretAccessKey:  cfg.AWSSecretAccessKey,
-			ProfilePublicURL: cfg.AWSProfilePublicURL,
-			ResumePublicURL:  cfg.AWSResumePublicURL,
+			Region:              cfg.AWSRegion,
+			ProfileBucket:       cfg.AWSProfileBucket,
+			ResumeBucket:        cfg.AWSResumeBucket,
+			AccessKeyID:         cfg.AWSAccessKeyID,
+			SecretAccessKey:     cfg.AWSSecretAccessKey,
+			ProfilePublicURL:    cfg.AWSProfilePublicURL,
+			ResumePublicURL:     cfg.AWSResumePublicURL,
+			ProfileBucketRegion: cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:  cfg.AWSResumeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -179,9 +180,13 @@ func handleRegister(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			http.Error(w, "Missing required fields: name, email, password, state_id, college_id are required", http.StatusBadRequest)
 			return
 		}
+		if err := validator.ValidateUser(store.RegisterRequest{Name: name, Email: email}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
 		// Handle resume upload (optional)
-		var resumeURL string
+		var resumeURL, resumeKey string
 		resumeFile, resumeHeader, err := r.FormFile("resume")
 		if err == nil && resumeFile != nil {
 			defer resumeFile.Close()
@@ -189,27 +194,36 @@ func handleRegister(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			// Use email as temporary identifier (will be updated after user creation if needed)
 			tempUserID := email
 
-			resumeURL, err = s3Storage.UploadResume(ctx, resumeFile, tempUserID, resumeHeader.Filename)
+			resumeURL, resumeKey, err = s3Storage.UploadResume(ctx, resumeFile, tempUserID, resumeHeader.Filename)
 			if err != nil {
 				log.Printf("Error uploading resume: %v", err)
 				// Continue without resume if upload fails
-				resumeURL = ""
+				resumeURL, resumeKey = "", ""
 			}
 		}
 
 		// Handle profile picture upload (optional)
-		var profilePicURL string
-		profilePicFile, profilePicHeader, err := r.FormFile("profile_pic")
+		var profilePicURL, profilePicKey string
+		profilePicFile, _, err := r.FormFile("profile_pic")
 		if err == nil && profilePicFile != nil {
 			defer profilePicFile.Close()
 
 			tempUserID := email
 
-			profilePicURL, err = s3Storage.UploadProfilePic(ctx, profilePicFile, tempUserID, profilePicHeader.Filename)
+			profilePic, contentType, err := storage.ValidateAndProcessImage(profilePicFile, storage.ProfilePicMaxWidth, storage.ProfilePicMaxHeight)
 			if err != nil {
-				log.Printf("Error uploading profile picture: %v", err)
-				// Continue without profile pic if upload fails
-				profilePicURL = ""
+				if errors.Is(err, storage.ErrUnsupportedImageType) || errors.Is(err, storage.ErrInvalidImage) {
+					http.Error(w, "Profile picture: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				log.Printf("Error processing profile picture: %v", err)
+			} else {
+				profilePicURL, profilePicKey, err = s3Storage.UploadProfilePic(ctx, profilePic, tempUserID, "profile"+storage.ImageExtension(contentType))
+				if err != nil {
+					log.Printf("Error uploading profile picture: %v", err)
+					// Continue without profile pic if upload fails
+					profilePicURL, profilePicKey = "", ""
+				}
 			}
 		}
 
@@ -226,19 +240,16 @@ func handleRegister(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			ReferralCode: referralCode,
 		}
 
-		user, err := userStore.Register(ctx, registerReq, resumeURL, profilePicURL)
+		user, err := userStore.Register(ctx, registerReq, resumeURL, resumeKey, profilePicURL, profilePicKey)
 		if err != nil {
 			log.Printf("Error registering user: %v", err)
 
 			// If user creation failed, try to clean up uploaded files
-			if resumeURL != "" {
-				// Extract key from URL and delete
-				key := extractS3KeyFromURL(resumeURL)
-				_ = s3Storage.DeleteResume(ctx, key)
+			if resumeKey != "" {
+				_ = s3Storage.DeleteResume(ctx, resumeKey)
 			}
-			if profilePicURL != "" {
-				key := extractS3KeyFromURL(profilePicURL)
-				_ = s3Storage.DeleteProfilePic(ctx, key)
+			if profilePicKey != "" {
+				_ = s3Storage.DeleteProfilePic(ctx, profilePicKey)
 			}
 
 			http.Error(w, fmt.Sprintf("Failed to register user: %v", err), http.StatusInternalServerError)
@@ -248,6 +259,15 @@ func handleRegister(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 		// If files were uploaded with temp IDs, we might want to rename them
 		// For now, we'll keep the temp IDs in the filename - this is acceptable
 
+		// Tasks only reach users through task_assignments, so pick up the ones already open to this user
+		taskStore := store.NewTaskStore(postgres)
+		if _, err := taskStore.AssignOpenTasksToNewUser(ctx, user.ID); err != nil {
+			log.Printf("Error assigning open tasks to user %s: %v", user.ID, err)
+		}
+
+		// New accounts must confirm their email before submitting tasks
+		queueVerificationEmail(postgres, cfg, user)
+
 		// Parse JWT expiry duration
 		expiryDuration, err := auth.ParseExpiryDuration(cfg.JWTExpiry)
 		if err != nil {
@@ -297,7 +317,7 @@ type RefreshTokenRequest struct {
 // @Failure      401   {string}  string  "Invalid or expired token"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /api/auth/refresh [post]
-func handleRefresh(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleRefresh(userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -334,7 +354,6 @@ func handleRefresh(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 		}
 
 		// Optionally ensure user still exists
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, claims.UserID)
 		if err != nil {
 			log.Printf("Refresh: user not found: %v", err)
@@ -370,24 +389,6 @@ func handleRefresh(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 	}
 }
 
-// extractS3KeyFromURL extracts the S3 key from a full URL
-func extractS3KeyFromURL(url string) string {
-	// URL format: https://bucket.s3.region.amazonaws.com/folder/filename
-	// We need to extract: folder/filename
-	// Simple approach: find the last part after the domain
-	parts := url
-	if len(url) > 0 {
-		// Find the last "/" and take everything after it
-		for i := len(url) - 1; i >= 0; i-- {
-			if url[i] == '/' {
-				parts = url[i+1:]
-				break
-			}
-		}
-	}
-	return parts
-}
-
 // Helper function to read file content
 func readFileContent(file io.Reader) ([]byte, error) {
 	return io.ReadAll(file)
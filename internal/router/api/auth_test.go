@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleLogin(t *testing.T) {
+	const testSecret = "test-secret"
+	tests := []struct {
+		name       string
+		body       string
+		hashErr    error
+		passwordOK bool
+		userErr    error
+		wantStatus int
+	}{
+		{name: "valid credentials", body: `{"email":"test.user@example.com","password":"Secret1!"}`, passwordOK: true, wantStatus: http.StatusOK},
+		{name: "missing password", body: `{"email":"test.user@example.com"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{"email":`, wantStatus: http.StatusBadRequest},
+		{name: "unknown email", body: `{"email":"nobody@example.com","password":"Secret1!"}`, hashErr: errors.New("user not found"), wantStatus: http.StatusUnauthorized},
+		{name: "wrong password", body: `{"email":"test.user@example.com","password":"nope"}`, wantStatus: http.StatusUnauthorized},
+		{name: "user lookup fails", body: `{"email":"test.user@example.com","password":"Secret1!"}`, passwordOK: true, userErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				GetUserPasswordHashFunc: func(ctx context.Context, email string) (string, error) {
+					return "hash", tt.hashErr
+				},
+				VerifyPasswordFunc: func(hashedPassword, password string) bool {
+					return tt.passwordOK
+				},
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*store.User, error) {
+					if tt.userErr != nil {
+						return nil, tt.userErr
+					}
+					return testutil.NewTestUser(), nil
+				},
+			}
+
+			cfg := &env.Config{JWTSecret: testSecret, JWTExpiry: "1h"}
+			w := serve(t, handleLogin(userStore, cfg), newTestRequest(http.MethodPost, "/api/auth/login", tt.body), tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got LoginResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			claims, err := auth.ValidateToken(got.Token, testSecret)
+			if err != nil {
+				t.Fatalf("token does not validate: %v", err)
+			}
+			if claims.UserID != testutil.TestUserID {
+				t.Errorf("token user = %q, want %q", claims.UserID, testutil.TestUserID)
+			}
+		})
+	}
+}
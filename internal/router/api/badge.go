@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// handleGetBadges handles listing the badge catalogue
+// @Summary      List badges
+// @Description  List all badges that can be earned. Public; when a Bearer token is sent each badge includes earned and earned_at for the caller.
+// @Tags         badges
+// @Produce      json
+// @Param        is_streak_badge  query     bool    false  "Only streak badges (true) or only non-streak badges (false)"
+// @Param        search           query     string  false  "Case-insensitive match on badge name or rule"
+// @Success      200              {array}   store.BadgeWithStatus
+// @Failure      400              {string}  string  "Bad request - invalid is_streak_badge"
+// @Failure      401              {string}  string  "Invalid or expired token"
+// @Failure      500              {string}  string  "Internal server error"
+// @Router       /api/badges [get]
+func handleGetBadges(badgeStore store.BadgeStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var filter store.BadgeFilter
+		if streakStr := r.URL.Query().Get("is_streak_badge"); streakStr != "" {
+			isStreak, err := strconv.ParseBool(streakStr)
+			if err != nil {
+				http.Error(w, "is_streak_badge must be true or false", http.StatusBadRequest)
+				return
+			}
+			filter.IsStreakBadge = &isStreak
+		}
+		filter.Search = strings.TrimSpace(r.URL.Query().Get("search"))
+
+		// Optional - only set when a valid JWT was sent
+		userID, _ := GetUserIDFromContext(ctx)
+
+		badges, err := badgeStore.ListBadges(ctx, filter, userID)
+		if err != nil {
+			log.Printf("Error listing badges: %v", err)
+			http.Error(w, "Failed to get badges", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(badges); err != nil {
+			log.Printf("Error encoding badges response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetBadge handles getting a single badge
+// @Summary      Get badge
+// @Description  Get a badge by ID. Public; when a Bearer token is sent the response includes earned and earned_at for the caller.
+// @Tags         badges
+// @Produce      json
+// @Param        id   path      string  true  "Badge ID"
+// @Success      200  {object}  store.BadgeWithStatus
+// @Failure      401  {string}  string  "Invalid or expired token"
+// @Failure      404  {string}  string  "Badge not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/badges/{id} [get]
+func handleGetBadge(badgeStore store.BadgeStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		badgeID := chi.URLParam(r, "id")
+
+		if badgeID == "" {
+			http.Error(w, "Badge ID is required", http.StatusBadRequest)
+			return
+		}
+
+		userID, _ := GetUserIDFromContext(ctx)
+
+		badge, err := badgeStore.GetBadgeWithStatus(ctx, badgeID, userID)
+		if err != nil {
+			if err.Error() == "badge not found" {
+				http.Error(w, "Badge not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting badge %s: %v", badgeID, err)
+			http.Error(w, "Failed to get badge", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(badge); err != nil {
+			log.Printf("Error encoding badge response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetBadges(t *testing.T) {
+	streak, notStreak := true, false
+	tests := []struct {
+		name       string
+		query      string
+		userID     string
+		err        error
+		wantStatus int
+		wantFilter store.BadgeFilter
+	}{
+		{name: "all badges anonymously", wantStatus: http.StatusOK},
+		{name: "earned status for caller", userID: testutil.TestUserID, wantStatus: http.StatusOK},
+		{name: "streak badges only", query: "?is_streak_badge=true", wantStatus: http.StatusOK, wantFilter: store.BadgeFilter{IsStreakBadge: &streak}},
+		{name: "non-streak badges only", query: "?is_streak_badge=false", wantStatus: http.StatusOK, wantFilter: store.BadgeFilter{IsStreakBadge: &notStreak}},
+		{name: "search is trimmed", query: "?search=%20first%20", wantStatus: http.StatusOK, wantFilter: store.BadgeFilter{Search: "first"}},
+		{name: "invalid is_streak_badge", query: "?is_streak_badge=maybe", wantStatus: http.StatusBadRequest},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			badgeStore := &mock.BadgeStore{
+				ListBadgesFunc: func(ctx context.Context, filter store.BadgeFilter, userID string) ([]store.BadgeWithStatus, error) {
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					if filter.Search != tt.wantFilter.Search {
+						t.Errorf("search = %q, want %q", filter.Search, tt.wantFilter.Search)
+					}
+					if (filter.IsStreakBadge == nil) != (tt.wantFilter.IsStreakBadge == nil) ||
+						(filter.IsStreakBadge != nil && *filter.IsStreakBadge != *tt.wantFilter.IsStreakBadge) {
+						t.Errorf("is_streak_badge = %v, want %v", filter.IsStreakBadge, tt.wantFilter.IsStreakBadge)
+					}
+					return []store.BadgeWithStatus{{Badge: *testutil.NewTestBadge()}}, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/badges"+tt.query, ""), tt.userID)
+			serve(t, handleGetBadges(badgeStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleGetBadge(t *testing.T) {
+	tests := []struct {
+		name       string
+		badgeID    string
+		err        error
+		wantStatus int
+	}{
+		{name: "returns badge", badgeID: testutil.TestBadgeID, wantStatus: http.StatusOK},
+		{name: "missing ID", badgeID: "", wantStatus: http.StatusBadRequest},
+		{name: "unknown badge", badgeID: testutil.TestBadgeID, err: errors.New("badge not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", badgeID: testutil.TestBadgeID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			badgeStore := &mock.BadgeStore{
+				GetBadgeWithStatusFunc: func(ctx context.Context, badgeID, userID string) (*store.BadgeWithStatus, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.BadgeWithStatus{Badge: *testutil.NewTestBadge()}, nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/badges/x", ""), "id", tt.badgeID)
+			serve(t, handleGetBadge(badgeStore), r, tt.wantStatus)
+		})
+	}
+}
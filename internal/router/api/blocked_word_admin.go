@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// AddBlockedWordRequest is the body for adding a word to the content filter
+type AddBlockedWordRequest struct {
+	Word string `json:"word"`
+}
+
+// refreshWordFilter reloads the in-memory blocklist so admin edits apply immediately
+func refreshWordFilter(r *http.Request) {
+	if filter := moderation.GetWordFilter(); filter != nil {
+		if err := filter.Refresh(r.Context()); err != nil {
+			log.Printf("Error refreshing word filter: %v", err)
+		}
+	}
+}
+
+// handleGetBlockedWords lists the words blocked by the content filter (admin)
+// @Summary      Get blocked words
+// @Description  List all words blocked in feed comments and masked in task descriptions. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   store.BlockedWord
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/blocked-words [get]
+func handleGetBlockedWords(blockedWordStore store.BlockedWordStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		words, err := blockedWordStore.GetBlockedWords(ctx)
+		if err != nil {
+			log.Printf("Error getting blocked words: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get blocked words: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(words); err != nil {
+			log.Printf("Error encoding blocked words response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleAddBlockedWord adds a word to the content filter (admin)
+// @Summary      Add blocked word
+// @Description  Add a word to the content filter. Takes effect immediately on this instance and within 5 minutes on others. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      AddBlockedWordRequest  true  "Word to block"
+// @Success      201   {object}  store.BlockedWord
+// @Failure      400   {string}  string  "Bad request - word required"
+// @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      409   {string}  string  "Word already blocked"
+// @Failure      500   {string}  string  "Internal server error"
+// @Router       /admin/blocked-words [post]
+func handleAddBlockedWord(blockedWordStore store.BlockedWordStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminUserID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req AddBlockedWordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		word, err := blockedWordStore.AddBlockedWord(ctx, req.Word, adminUserID)
+		if err != nil {
+			switch err.Error() {
+			case "word is required":
+				http.Error(w, "word is required", http.StatusBadRequest)
+			case "word already blocked":
+				http.Error(w, "Word already blocked", http.StatusConflict)
+			default:
+				log.Printf("Error adding blocked word: %v", err)
+				http.Error(w, fmt.Sprintf("Failed to add blocked word: %v", err), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		refreshWordFilter(r)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(word); err != nil {
+			log.Printf("Error encoding blocked word response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleDeleteBlockedWord removes a word from the content filter (admin)
+// @Summary      Delete blocked word
+// @Description  Remove a word from the content filter. Admin only.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Blocked word ID"
+// @Success      204  "Deleted"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      404  {string}  string  "Blocked word not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/blocked-words/{id} [delete]
+func handleDeleteBlockedWord(blockedWordStore store.BlockedWordStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			http.Error(w, "Blocked word ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := blockedWordStore.DeleteBlockedWord(ctx, id); err != nil {
+			if err.Error() == "blocked word not found" {
+				http.Error(w, "Blocked word not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error deleting blocked word: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to delete blocked word: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		refreshWordFilter(r)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleAddBlockedWord(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminID    string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "adds word", adminID: testutil.TestAdminID, body: `{"word":"spam"}`, wantStatus: http.StatusCreated},
+		{name: "anonymous", body: `{"word":"spam"}`, wantStatus: http.StatusUnauthorized},
+		{name: "invalid JSON", adminID: testutil.TestAdminID, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "empty word", adminID: testutil.TestAdminID, body: `{"word":""}`, err: errors.New("word is required"), wantStatus: http.StatusBadRequest},
+		{name: "already blocked", adminID: testutil.TestAdminID, body: `{"word":"spam"}`, err: errors.New("word already blocked"), wantStatus: http.StatusConflict},
+		{name: "store error", adminID: testutil.TestAdminID, body: `{"word":"spam"}`, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockedWordStore := &mock.BlockedWordStore{
+				AddBlockedWordFunc: func(ctx context.Context, word, createdBy string) (*store.BlockedWord, error) {
+					if createdBy != tt.adminID {
+						t.Errorf("createdBy = %q, want %q", createdBy, tt.adminID)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.BlockedWord{ID: "w1", Word: word, CreatedBy: createdBy}, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/admin/blocked-words", tt.body), tt.adminID)
+			serve(t, handleAddBlockedWord(blockedWordStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleDeleteBlockedWord(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		wantStatus int
+	}{
+		{name: "deletes word", id: "w1", wantStatus: http.StatusNoContent},
+		{name: "missing ID", id: "", wantStatus: http.StatusBadRequest},
+		{name: "unknown word", id: "w1", err: errors.New("blocked word not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", id: "w1", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockedWordStore := &mock.BlockedWordStore{
+				DeleteBlockedWordFunc: func(ctx context.Context, id string) error {
+					return tt.err
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodDelete, "/admin/blocked-words/x", ""), "id", tt.id)
+			serve(t, handleDeleteBlockedWord(blockedWordStore), r, tt.wantStatus)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/certificate"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/storage"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// certificateURLExpiry is how long a certificate download link stays valid
+const certificateURLExpiry = time.Hour
+
+// CertificateResponse is returned by the task certificate endpoint
+type CertificateResponse struct {
+	TaskID      string    `json:"task_id"`
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// The platform logo is downloaded from S3 the first time a certificate is rendered and kept in memory
+var (
+	certificateLogo   []byte
+	certificateLogoMu sync.Mutex
+)
+
+// loadCertificateLogo returns the cached logo, fetching it on first use. A failed fetch is retried on the next call.
+func loadCertificateLogo(ctx context.Context, s3Storage *storage.S3Storage, key string) ([]byte, error) {
+	certificateLogoMu.Lock()
+	defer certificateLogoMu.Unlock()
+	if certificateLogo != nil {
+		return certificateLogo, nil
+	}
+	logo, err := s3Storage.GetTaskProofObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	certificateLogo = logo
+	return logo, nil
+}
+
+// certificateKey is the S3 key of a user's certificate for a task
+func certificateKey(userID, taskID string) string {
+	return fmt.Sprintf("certificates/%s_%s.pdf", userID, taskID)
+}
+
+// handleGetTaskCertificate returns a download link for the user's completion certificate of a task
+// @Summary      Get task completion certificate
+// @Description  Returns a 1 hour download link for a PDF certificate with the user's name, task title, completion date and XP earned. Only available once one of the user's submissions is approved (for weekly tasks, in any week). The PDF is generated on first request and stored in S3 at certificates/{userID}_{taskID}.pdf.
+// @Tags         task
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {object}  CertificateResponse  "Certificate download link"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Task not completed - no approved submission"
+// @Failure      404  {string}  string  "Task not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/tasks/{id}/certificate [get]
+func handleGetTaskCertificate(taskStore store.TaskStoreInterface, submissionStore store.SubmissionStoreInterface, userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		task, err := taskStore.GetTaskByID(ctx, taskID)
+		if err != nil {
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting task: %v", err)
+			http.Error(w, "Failed to get certificate", http.StatusInternalServerError)
+			return
+		}
+
+		// Any approved submission counts, including an earlier week's of a weekly task
+		submission, err := submissionStore.GetLatestApprovedSubmission(ctx, taskID, userID)
+		if err != nil && err.Error() != "submission not found" {
+			log.Printf("Error getting submission: %v", err)
+			http.Error(w, "Failed to get certificate", http.StatusInternalServerError)
+			return
+		}
+		if submission == nil {
+			http.Error(w, "Certificates are only available for completed tasks", http.StatusForbidden)
+			return
+		}
+
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Region:                cfg.AWSRegion,
+			ProfileBucket:         cfg.AWSProfileBucket,
+			ResumeBucket:          cfg.AWSResumeBucket,
+			TaskProofBucket:       cfg.AWSTaskProofBucket,
+			AccessKeyID:           cfg.AWSAccessKeyID,
+			SecretAccessKey:       cfg.AWSSecretAccessKey,
+			TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+			TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
+		})
+		if err != nil {
+			log.Printf("Error initializing S3 storage: %v", err)
+			http.Error(w, "Failed to initialize file storage", http.StatusInternalServerError)
+			return
+		}
+
+		key := certificateKey(userID, taskID)
+		exists, err := s3Storage.TaskProofObjectExists(ctx, key)
+		if err != nil {
+			log.Printf("Error checking certificate %s: %v", key, err)
+			http.Error(w, "Failed to get certificate", http.StatusInternalServerError)
+			return
+		}
+
+		if !exists {
+			user, err := userStore.GetUserByID(ctx, userID)
+			if err != nil {
+				log.Printf("Error getting user: %v", err)
+				http.Error(w, "Failed to get certificate", http.StatusInternalServerError)
+				return
+			}
+
+			// A missing logo should not block certificates
+			logo, err := loadCertificateLogo(ctx, s3Storage, cfg.CertificateLogoKey)
+			if err != nil {
+				log.Printf("Error loading certificate logo, rendering without it: %v", err)
+			}
+
+			pdf, err := certificate.Render(certificate.Certificate{
+				UserName:    user.Name,
+				TaskTitle:   task.Title,
+				CompletedAt: submission.UpdatedAt,
+				XP:          task.XP,
+			}, logo)
+			if err != nil {
+				log.Printf("Error rendering certificate for user %s task %s: %v", userID, taskID, err)
+				http.Error(w, "Failed to generate certificate", http.StatusInternalServerError)
+				return
+			}
+
+			if _, err := s3Storage.UploadFile(ctx, bytes.NewReader(pdf), s3Storage.GetTaskProofBucket(), key,
+				"application/pdf", s3Storage.GetTaskProofPublicURL(), true); err != nil {
+				log.Printf("Error uploading certificate %s: %v", key, err)
+				http.Error(w, "Failed to upload certificate", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		downloadURL, err := s3Storage.GeneratePresignedTaskProofURL(ctx, key, certificateURLExpiry)
+		if err != nil {
+			log.Printf("Error generating certificate URL: %v", err)
+			http.Error(w, "Failed to generate download URL", http.StatusInternalServerError)
+			return
+		}
+
+		response := CertificateResponse{
+			TaskID:      taskID,
+			DownloadURL: downloadURL,
+			ExpiresAt:   time.Now().Add(certificateURLExpiry).UTC(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
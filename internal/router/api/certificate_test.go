@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+// Only the paths that end before S3 is reached are covered
+func TestHandleGetTaskCertificate(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		taskID        string
+		taskErr       error
+		submission    *store.Submission
+		submissionErr error
+		wantStatus    int
+	}{
+		{name: "anonymous", taskID: testutil.TestTaskID, wantStatus: http.StatusUnauthorized},
+		{name: "missing task ID", userID: testutil.TestUserID, wantStatus: http.StatusBadRequest},
+		{name: "unknown task", userID: testutil.TestUserID, taskID: testutil.TestTaskID, taskErr: errors.New("task not found"), wantStatus: http.StatusNotFound},
+		{name: "no approved submission", userID: testutil.TestUserID, taskID: testutil.TestTaskID, submissionErr: errors.New("submission not found"), wantStatus: http.StatusForbidden},
+		{name: "submission lookup fails", userID: testutil.TestUserID, taskID: testutil.TestTaskID, submissionErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskStore := &mock.TaskStore{
+				GetTaskByIDFunc: func(ctx context.Context, taskID string) (*store.Task, error) {
+					if tt.taskErr != nil {
+						return nil, tt.taskErr
+					}
+					return testutil.NewTestTask(), nil
+				},
+			}
+			submissionStore := &mock.SubmissionStore{
+				GetLatestApprovedSubmissionFunc: func(ctx context.Context, taskID, userID string) (*store.Submission, error) {
+					return tt.submission, tt.submissionErr
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/tasks/x/certificate", ""), tt.userID)
+			r = withURLParams(r, "id", tt.taskID)
+			serve(t, handleGetTaskCertificate(taskStore, submissionStore, &mock.UserStore{}, &env.Config{}), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestCertificateKey(t *testing.T) {
+	got := certificateKey(testutil.TestUserID, testutil.TestTaskID)
+	want := "certificates/" + testutil.TestUserID + "_" + testutil.TestTaskID + ".pdf"
+	if got != want {
+		t.Errorf("certificateKey() = %q, want %q", got, want)
+	}
+}
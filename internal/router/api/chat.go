@@ -1,23 +1,316 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// Each user can post at most chatMessageLimit messages per chatMessageWindow
+const (
+	chatMessageLimit     = 20
+	chatMessageWindow    = time.Minute
+	maxChatMessageLength = 2000
 )
 
-// handleGetChatRooms handles getting all chat rooms
-func handleGetChatRooms(postgres *db.Postgres) http.HandlerFunc {
+// PostChatMessageRequest is the body for posting a chat message
+type PostChatMessageRequest struct {
+	Content string `json:"content"`
+}
+
+// ChatMessagesResponse is one page of a room's messages, newest first
+type ChatMessagesResponse struct {
+	Messages   []store.ChatMessage `json:"messages"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// loadChatRoom returns the room with the given ID if userID may access it.
+// Otherwise it writes the error response and returns nil.
+func loadChatRoom(ctx context.Context, w http.ResponseWriter, chatStore store.ChatStoreInterface, userStore store.UserStoreInterface, roomID, userID string) *store.ChatRoom {
+	if _, err := uuid.Parse(roomID); err != nil {
+		http.Error(w, "Chat room not found", http.StatusNotFound)
+		return nil
+	}
+
+	room, err := chatStore.GetRoomByID(ctx, roomID)
+	if err != nil {
+		if err.Error() == "chat room not found" {
+			http.Error(w, "Chat room not found", http.StatusNotFound)
+			return nil
+		}
+		log.Printf("Error getting chat room: %v", err)
+		http.Error(w, "Failed to get chat room", http.StatusInternalServerError)
+		return nil
+	}
+
+	user, err := userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return nil
+		}
+		log.Printf("Error getting user: %v", err)
+		http.Error(w, "Failed to get chat room", http.StatusInternalServerError)
+		return nil
+	}
+
+	if !room.CanAccess(user) {
+		http.Error(w, "You are not a member of this chat room", http.StatusForbidden)
+		return nil
+	}
+	return room
+}
+
+// handleGetChatRooms lists the chat rooms the current user belongs to
+// @Summary      Get chat rooms
+// @Description  List the chat rooms the current user belongs to: the global rooms, then the room of the user's state and the room of the user's college.
+// @Tags         chat
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   store.ChatRoom  "Chat rooms"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/chat/rooms [get]
+func handleGetChatRooms(chatStore store.ChatStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		rooms, err := chatStore.GetRoomsForUser(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting chat rooms: %v", err)
+			http.Error(w, "Failed to get chat rooms", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(rooms); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetChatRoom returns a chat room the current user belongs to
+// @Summary      Get chat room
+// @Description  Get a chat room. Only members of the room (everyone for global rooms, the state's or college's users otherwise) can see it.
+// @Tags         chat
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Chat room ID"
+// @Success      200  {object}  store.ChatRoom  "Chat room"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Not a member of the room"
+// @Failure      404  {string}  string  "Chat room not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/chat/rooms/{id} [get]
+func handleGetChatRoom(chatStore store.ChatStoreInterface, userStore store.UserStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte("Not implemented"))
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		room := loadChatRoom(ctx, w, chatStore, userStore, chi.URLParam(r, "id"), userID)
+		if room == nil {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(room); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetChatMessages returns a page of a chat room's messages
+// @Summary      Get chat messages
+// @Description  Get a room's messages, newest first, with cursor pagination: pass next_cursor from the previous response as cursor to load older messages. Messages posted between requests do not shift pages.
+// @Tags         chat
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "Chat room ID"
+// @Param        limit   query     int     false  "Messages per page (default: 50, max: 100)"
+// @Param        cursor  query     string  false  "Cursor from the previous page's next_cursor"
+// @Success      200     {object}  ChatMessagesResponse  "Messages"
+// @Failure      400     {string}  string  "Invalid cursor"
+// @Failure      401     {string}  string  "Unauthorized"
+// @Failure      403     {string}  string  "Not a member of the room"
+// @Failure      404     {string}  string  "Chat room not found"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/chat/rooms/{id}/messages [get]
+func handleGetChatMessages(chatStore store.ChatStoreInterface, userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		limit := 50
+		if limitStr := query.Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+				limit = l
+			}
+		}
+		if limit > 100 {
+			limit = 100
+		}
+
+		var cursor *store.ChatMessageCursor
+		if cursorStr := query.Get("cursor"); cursorStr != "" {
+			decoded, err := store.DecodeChatMessageCursor(cursorStr)
+			if err != nil {
+				http.Error(w, "Invalid cursor", http.StatusBadRequest)
+				return
+			}
+			cursor = decoded
+		}
+
+		room := loadChatRoom(ctx, w, chatStore, userStore, chi.URLParam(r, "id"), userID)
+		if room == nil {
+			return
+		}
+
+		messages, nextCursor, err := chatStore.GetMessages(ctx, room.ID, limit, cursor)
+		if err != nil {
+			log.Printf("Error getting chat messages: %v", err)
+			http.Error(w, "Failed to get messages", http.StatusInternalServerError)
+			return
+		}
+
+		response := ChatMessagesResponse{
+			Messages: messages,
+		}
+		if nextCursor != nil {
+			response.NextCursor = nextCursor.Encode()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
 	}
 }
 
-// handleGetChatRoom handles getting a specific chat room
-func handleGetChatRoom(postgres *db.Postgres) http.HandlerFunc {
+// handlePostChatMessage posts a message in a chat room and broadcasts it to the room's WebSocket subscribers
+// @Summary      Post chat message
+// @Description  Post a message (max 2000 characters) in a room the current user belongs to. The message is sent to everyone subscribed to the room at /ws/chat/{roomId}. Limited to 20 messages per minute.
+// @Tags         chat
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string                  true  "Chat room ID"
+// @Param        body  body      PostChatMessageRequest  true  "Message"
+// @Success      201   {object}  store.ChatMessage  "Message posted"
+// @Failure      400   {string}  string  "Bad request - empty, too long or inappropriate message"
+// @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Not a member of the room"
+// @Failure      404   {string}  string  "Chat room not found"
+// @Failure      429   {string}  string  "Too many requests"
+// @Failure      500   {string}  string  "Internal server error"
+// @Router       /api/chat/rooms/{id}/messages [post]
+func handlePostChatMessage(chatStore store.ChatStoreInterface, userStore store.UserStoreInterface, redisClient *db.Redis) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte("Not implemented"))
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req PostChatMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		content := strings.TrimSpace(req.Content)
+		if content == "" {
+			http.Error(w, "Message content is required", http.StatusBadRequest)
+			return
+		}
+		if utf8.RuneCountInString(content) > maxChatMessageLength {
+			http.Error(w, fmt.Sprintf("Message must be at most %d characters", maxChatMessageLength), http.StatusBadRequest)
+			return
+		}
+
+		room := loadChatRoom(ctx, w, chatStore, userStore, chi.URLParam(r, "id"), userID)
+		if room == nil {
+			return
+		}
+
+		if redisClient != nil {
+			rateLimitKey := fmt.Sprintf("chat_message:%s", userID)
+			count, err := redisClient.Client.Incr(ctx, rateLimitKey).Result()
+			if err != nil {
+				log.Printf("Error checking chat message rate limit: %v", err)
+				http.Error(w, "Failed to post message", http.StatusInternalServerError)
+				return
+			}
+			if count == 1 {
+				redisClient.Client.Expire(ctx, rateLimitKey, chatMessageWindow)
+			}
+			if count > chatMessageLimit {
+				http.Error(w, "Too many messages. Try again later.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		message, err := chatStore.CreateMessage(ctx, room.ID, userID, content)
+		if err != nil {
+			if err.Error() == "message contains blocked words" {
+				http.Error(w, "Message contains inappropriate language", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error creating chat message: %v", err)
+			http.Error(w, "Failed to post message", http.StatusInternalServerError)
+			return
+		}
+
+		// The message is saved; subscribers that miss the broadcast see it when they reload the room
+		if err := ws.PublishChatMessage(ctx, redisClient, message); err != nil {
+			log.Printf("Error broadcasting chat message %s: %v", message.ID, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(message); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
 	}
 }
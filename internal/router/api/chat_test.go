@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+const testRoomID = "cccccccc-cccc-cccc-cccc-cccccccccccc"
+
+func TestHandleGetChatRoom(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		roomID     string
+		room       *store.ChatRoom
+		roomErr    error
+		userErr    error
+		wantStatus int
+	}{
+		{name: "global room", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeGlobal}, wantStatus: http.StatusOK},
+		{name: "own state", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeState, ScopeID: testutil.TestStateID}, wantStatus: http.StatusOK},
+		{name: "other state", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeState, ScopeID: "other"}, wantStatus: http.StatusForbidden},
+		{name: "own college", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeCollege, ScopeID: testutil.TestCollegeID}, wantStatus: http.StatusOK},
+		{name: "other college", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeCollege, ScopeID: "other"}, wantStatus: http.StatusForbidden},
+		{name: "anonymous", roomID: testRoomID, wantStatus: http.StatusUnauthorized},
+		{name: "malformed ID", userID: testutil.TestUserID, roomID: "general", wantStatus: http.StatusNotFound},
+		{name: "unknown room", userID: testutil.TestUserID, roomID: testRoomID, roomErr: errors.New("chat room not found"), wantStatus: http.StatusNotFound},
+		{name: "deleted user", userID: testutil.TestUserID, roomID: testRoomID, room: &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeGlobal}, userErr: errors.New("user not found"), wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatStore := &mock.ChatStore{
+				GetRoomByIDFunc: func(ctx context.Context, roomID string) (*store.ChatRoom, error) {
+					return tt.room, tt.roomErr
+				},
+			}
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					if tt.userErr != nil {
+						return nil, tt.userErr
+					}
+					return testutil.NewTestUser(), nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/chat/rooms/x", ""), tt.userID)
+			r = withURLParams(r, "id", tt.roomID)
+			serve(t, handleGetChatRoom(chatStore, userStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandlePostChatMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		sent        int
+		createErr   error
+		wantStatus  int
+		wantContent string
+	}{
+		{name: "posts trimmed message", body: `{"content":"  hello  "}`, wantStatus: http.StatusCreated, wantContent: "hello"},
+		{name: "empty message", body: `{"content":"   "}`, wantStatus: http.StatusBadRequest},
+		{name: "longest message", body: `{"content":"` + strings.Repeat("é", maxChatMessageLength) + `"}`, wantStatus: http.StatusCreated, wantContent: strings.Repeat("é", maxChatMessageLength)},
+		{name: "too long", body: `{"content":"` + strings.Repeat("é", maxChatMessageLength+1) + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "last message in window", body: `{"content":"hello"}`, sent: chatMessageLimit - 1, wantStatus: http.StatusCreated, wantContent: "hello"},
+		{name: "rate limited", body: `{"content":"hello"}`, sent: chatMessageLimit, wantStatus: http.StatusTooManyRequests},
+		{name: "blocked words", body: `{"content":"hello"}`, createErr: errors.New("message contains blocked words"), wantStatus: http.StatusBadRequest, wantContent: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.sent > 0 {
+				server.Set("chat_message:"+testutil.TestUserID, strconv.Itoa(tt.sent))
+			}
+			chatStore := &mock.ChatStore{
+				GetRoomByIDFunc: func(ctx context.Context, roomID string) (*store.ChatRoom, error) {
+					return &store.ChatRoom{ID: testRoomID, Type: store.ChatRoomTypeGlobal}, nil
+				},
+				CreateMessageFunc: func(ctx context.Context, roomID, userID, content string) (*store.ChatMessage, error) {
+					if content != tt.wantContent {
+						t.Errorf("content = %q, want %q", content, tt.wantContent)
+					}
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &store.ChatMessage{ID: "m1", RoomID: roomID, Content: content}, nil
+				},
+			}
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					return testutil.NewTestUser(), nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/chat/rooms/x/messages", tt.body), testutil.TestUserID)
+			r = withURLParams(r, "id", testRoomID)
+			serve(t, handlePostChatMessage(chatStore, userStore, redisClient), r, tt.wantStatus)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// coinExchangeCooldown is the minimum time between two coin exchanges by the same user
+const coinExchangeCooldown = time.Hour
+
+// CoinExchangeRequest is the body for exchanging coins for XP
+type CoinExchangeRequest struct {
+	Coins int `json:"coins"`
+}
+
+// CoinExchangeResponse is the result of a coins-to-XP exchange
+type CoinExchangeResponse struct {
+	CoinsSpent int `json:"coins_spent"`
+	XPAwarded  int `json:"xp_awarded"`
+	NewCoins   int `json:"new_coins"`
+	NewXP      int `json:"new_xp"`
+}
+
+// handleGetCoinExchangeRate returns the current coins-to-XP exchange rate
+// @Summary      Get coin exchange rate
+// @Description  Get how much XP is awarded per coin when exchanging coins
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "xp_per_coin"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Router       /api/user/me/coins/exchange-rate [get]
+func handleGetCoinExchangeRate(cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"xp_per_coin": cfg.CoinToXPRate,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding exchange rate response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleExchangeCoins converts the authenticated user's coins into XP
+// @Summary      Exchange coins for XP
+// @Description  Convert coins into XP at the configured rate (COIN_TO_XP_RATE). Limited to one exchange per hour. When the daily user_add XP cap (XP_CAP_USER_ADD) leaves room for fewer coins, only those coins are spent (see coins_spent). Logs in xp_logs (source user_add, reason coins_exchange) and broadcasts leaderboard update.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      CoinExchangeRequest  true  "Number of coins to exchange"
+// @Success      200   {object}  CoinExchangeResponse  "Coins exchanged"
+// @Failure      400   {string}  string  "Bad request - invalid amount or insufficient coins"
+// @Failure      401   {string}  string  "Unauthorized"
+// @Failure      429   {string}  string  "Too many requests - one exchange per hour or daily XP cap reached"
+// @Failure      500   {string}  string  "Internal server error"
+// @Router       /api/user/me/coins/exchange [post]
+func handleExchangeCoins(coinStore store.CoinStoreInterface, userStore store.UserStoreInterface, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req CoinExchangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Coins <= 0 {
+			http.Error(w, "coins must be greater than 0", http.StatusBadRequest)
+			return
+		}
+		if cfg.CoinToXPRate <= 0 {
+			http.Error(w, "Coin exchange is currently disabled", http.StatusBadRequest)
+			return
+		}
+
+		// Rate limit: one exchange per hour per user
+		rateLimitKey := fmt.Sprintf("coins_exchange:%s", userID)
+		if redisClient != nil {
+			acquired, err := redisClient.Client.SetNX(ctx, rateLimitKey, time.Now().Unix(), coinExchangeCooldown).Result()
+			if err != nil {
+				log.Printf("Error checking coin exchange rate limit: %v", err)
+				http.Error(w, "Failed to exchange coins", http.StatusInternalServerError)
+				return
+			}
+			if !acquired {
+				http.Error(w, "You can only exchange coins once per hour", http.StatusTooManyRequests)
+				return
+			}
+		}
+		releaseRateLimit := func() {
+			if redisClient != nil {
+				redisClient.Client.Del(ctx, rateLimitKey)
+			}
+		}
+
+		// The debit and the XP award commit together in the store
+		exchange, err := coinStore.ExchangeCoinsForXP(ctx, userID, req.Coins, cfg.CoinToXPRate)
+		if err != nil {
+			releaseRateLimit()
+			if errors.Is(err, store.ErrInsufficientCoins) {
+				http.Error(w, "Insufficient coins", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, store.ErrDailyCapExceeded) {
+				http.Error(w, "Daily XP limit reached, try again tomorrow", http.StatusTooManyRequests)
+				return
+			}
+			log.Printf("Error exchanging coins for user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to exchange coins: %v", err), http.StatusInternalServerError)
+			return
+		}
+		xpLog := exchange.XPLog
+
+		notifyLevelUp(xpLog)
+
+		response := CoinExchangeResponse{
+			CoinsSpent: exchange.CoinsSpent, // May be less than requested when the daily XP cap is reached
+			XPAwarded:  xpLog.XP,
+			NewCoins:   exchange.NewCoins,
+		}
+
+		user, err := userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting user after coin exchange: %v", err)
+		} else {
+			response.NewXP = user.XP
+			if redisClient != nil {
+				ws.BroadcastUserXPChange(ctx, redisClient, user)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding coin exchange response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// CoinHistoryResponse is one page of a user's coin transaction history
+type CoinHistoryResponse struct {
+	Transactions []store.CoinLog `json:"transactions"`
+	Total        int             `json:"total"`
+	Page         int             `json:"page"`
+	PageSize     int             `json:"page_size"`
+	TotalPages   int             `json:"total_pages"`
+}
+
+// handleGetMyCoinHistory returns the authenticated user's coin transactions
+// @Summary      Get my coin history
+// @Description  Paginated coin transaction history of the user, newest first. amount is positive for coins earned and negative for coins spent; balance_after is the balance once the transaction was applied.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page       query     int  false  "Page number (default: 1)"
+// @Param        page_size  query     int  false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  CoinHistoryResponse  "Coin history"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/me/coins/history [get]
+func handleGetMyCoinHistory(coinStore store.CoinStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := GetUserIDFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		serveCoinHistory(w, r, coinStore, userID)
+	}
+}
+
+// handleGetUserCoinHistory returns a user's coin transactions (admin)
+// @Summary      Get user coin history
+// @Description  Paginated coin transaction history of any user, newest first, for dispute resolution. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true   "User ID"
+// @Param        page       query     int     false  "Page number (default: 1)"
+// @Param        page_size  query     int     false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  CoinHistoryResponse  "Coin history"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      404        {string}  string  "User not found"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /admin/users/{id}/coins/history [get]
+func handleGetUserCoinHistory(userStore store.UserStoreInterface, coinStore store.CoinStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := userStore.GetUserByID(ctx, userID); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		serveCoinHistory(w, r, coinStore, userID)
+	}
+}
+
+// serveCoinHistory writes one page of a user's coin transactions as a CoinHistoryResponse
+func serveCoinHistory(w http.ResponseWriter, r *http.Request, coinStore store.CoinStoreInterface, userID string) {
+	ctx := r.Context()
+
+	page := 1
+	pageSize := 20
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
+		}
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	transactions, total, err := coinStore.GetCoinHistory(ctx, userID, page, pageSize)
+	if err != nil {
+		log.Printf("Error getting coin history for user %s: %v", userID, err)
+		http.Error(w, fmt.Sprintf("Failed to get coin history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (total + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	response := CoinHistoryResponse{
+		Transactions: transactions,
+		Total:        total,
+		Page:         page,
+		PageSize:     pageSize,
+		TotalPages:   totalPages,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding coin history response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
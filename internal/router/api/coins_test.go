@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleExchangeCoins(t *testing.T) {
+	rateLimitKey := "coins_exchange:" + testutil.TestUserID
+	tests := []struct {
+		name          string
+		body          string
+		rate          int
+		cooldown      bool
+		capCoins      int // Coins that fit under the daily XP cap; 0 means no cap
+		exchangeErr   error
+		wantStatus    int
+		wantSpent     int
+		wantXP        int
+		wantRateLimit bool
+	}{
+		{name: "exchanges at rate", body: `{"coins":5}`, rate: 10, wantStatus: http.StatusOK, wantSpent: 5, wantXP: 50, wantRateLimit: true},
+		{name: "shrunk by daily cap", body: `{"coins":5}`, rate: 10, capCoins: 2, wantStatus: http.StatusOK, wantSpent: 2, wantXP: 20, wantRateLimit: true},
+		{name: "zero coins", body: `{"coins":0}`, rate: 10, wantStatus: http.StatusBadRequest},
+		{name: "exchange disabled", body: `{"coins":5}`, wantStatus: http.StatusBadRequest},
+		{name: "insufficient coins", body: `{"coins":6}`, rate: 10, exchangeErr: store.ErrInsufficientCoins, wantStatus: http.StatusBadRequest},
+		{name: "within cooldown", body: `{"coins":5}`, rate: 10, cooldown: true, wantStatus: http.StatusTooManyRequests, wantRateLimit: true},
+		{name: "daily cap reached", body: `{"coins":5}`, rate: 10, exchangeErr: fmt.Errorf("award: %w", store.ErrDailyCapExceeded), wantStatus: http.StatusTooManyRequests},
+		{name: "store failure", body: `{"coins":5}`, rate: 10, exchangeErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.cooldown {
+				server.Set(rateLimitKey, "1")
+			}
+			coinStore := &mock.CoinStore{
+				ExchangeCoinsForXPFunc: func(ctx context.Context, userID string, coins, xpPerCoin int) (*store.CoinExchange, error) {
+					if xpPerCoin != tt.rate {
+						t.Errorf("XP per coin = %d, want %d", xpPerCoin, tt.rate)
+					}
+					if tt.exchangeErr != nil {
+						return nil, tt.exchangeErr
+					}
+					if tt.capCoins > 0 {
+						coins = tt.capCoins
+					}
+					xpLog := testutil.NewTestXPLog(func(l *store.XPLog) { l.XP = coins * xpPerCoin; l.NewXP = l.XP })
+					return &store.CoinExchange{CoinsSpent: coins, XPLog: xpLog}, nil
+				},
+			}
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					return testutil.NewTestUser(func(u *store.User) { u.XP = tt.wantXP }), nil
+				},
+			}
+
+			cfg := &env.Config{CoinToXPRate: tt.rate}
+			r := withUserID(newTestRequest(http.MethodPost, "/api/user/me/coins/exchange", tt.body), testutil.TestUserID)
+			w := serve(t, handleExchangeCoins(coinStore, userStore, redisClient, cfg), r, tt.wantStatus)
+			// A failed exchange releases the cooldown so the user can retry
+			if server.Exists(rateLimitKey) != tt.wantRateLimit {
+				t.Errorf("cooldown set = %v, want %v", server.Exists(rateLimitKey), tt.wantRateLimit)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got CoinExchangeResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.CoinsSpent != tt.wantSpent || got.XPAwarded != tt.wantXP || got.NewXP != tt.wantXP || got.NewCoins != 0 {
+				t.Errorf("response = %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandleGetUserCoinHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		userErr    error
+		historyErr error
+		wantStatus int
+	}{
+		{name: "history", userID: testutil.TestUserID, wantStatus: http.StatusOK},
+		{name: "missing user ID", wantStatus: http.StatusBadRequest},
+		{name: "unknown user", userID: testutil.TestUserID, userErr: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, historyErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					return testutil.NewTestUser(), tt.userErr
+				},
+			}
+			coinStore := &mock.CoinStore{
+				GetCoinHistoryFunc: func(ctx context.Context, userID string, page, pageSize int) ([]store.CoinLog, int, error) {
+					return []store.CoinLog{}, 0, tt.historyErr
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/admin/users/x/coins/history", ""), "id", tt.userID)
+			serve(t, handleGetUserCoinHistory(userStore, coinStore), r, tt.wantStatus)
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
@@ -21,7 +20,7 @@ import (
 // @Failure      400      {string}  string  "Bad request"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /api/states/{stateId}/colleges [get]
-func handleGetCollegesByState(postgres *db.Postgres) http.HandlerFunc {
+func handleGetCollegesByState(collegeStore store.CollegeStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		stateID := chi.URLParam(r, "stateId")
@@ -31,7 +30,6 @@ func handleGetCollegesByState(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		collegeStore := store.NewCollegeStore(postgres)
 		colleges, err := collegeStore.GetCollegesByStateID(ctx, stateID)
 		if err != nil {
 			log.Printf("Error fetching colleges: %v", err)
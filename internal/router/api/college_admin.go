@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
@@ -20,7 +19,7 @@ import (
 // @Failure      400      {string}  string  "Bad request"
 // @Failure      500      {string}  string  "Internal server error"
 // @Router       /admin/colleges [post]
-func handleCreateCollege(postgres *db.Postgres) http.HandlerFunc {
+func handleCreateCollege(collegeStore store.CollegeStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -36,7 +35,6 @@ func handleCreateCollege(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		collegeStore := store.NewCollegeStore(postgres)
 		college, err := collegeStore.CreateCollege(ctx, req)
 		if err != nil {
 			log.Printf("Error creating college: %v", err)
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleCreateCollege(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "creates college", body: `{"name":"NIT Calicut","state_id":"` + testutil.TestStateID + `"}`, wantStatus: http.StatusCreated, wantCalled: true},
+		{name: "invalid JSON", body: `not json`, wantStatus: http.StatusBadRequest},
+		{name: "missing state", body: `{"name":"NIT Calicut"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing name", body: `{"state_id":"` + testutil.TestStateID + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "store error", body: `{"name":"NIT Calicut","state_id":"` + testutil.TestStateID + `"}`, err: errors.New("foreign key violation"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			collegeStore := &mock.CollegeStore{
+				CreateCollegeFunc: func(ctx context.Context, req store.CreateCollegeRequest) (*store.College, error) {
+					called = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.College{ID: testutil.TestCollegeID, Name: req.Name, StateID: req.StateID}, nil
+				},
+			}
+
+			serve(t, handleCreateCollege(collegeStore), newTestRequest(http.MethodPost, "/admin/colleges", tt.body), tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("CreateCollege called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetCollegesByState(t *testing.T) {
+	tests := []struct {
+		name       string
+		stateID    string
+		err        error
+		wantStatus int
+	}{
+		{name: "lists colleges of the state", stateID: testutil.TestStateID, wantStatus: http.StatusOK},
+		{name: "missing state ID", stateID: "", wantStatus: http.StatusBadRequest},
+		{name: "store error", stateID: testutil.TestStateID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collegeStore := &mock.CollegeStore{
+				GetCollegesByStateIDFunc: func(ctx context.Context, stateID string) ([]store.College, error) {
+					if stateID != tt.stateID {
+						t.Errorf("stateID = %q, want %q", stateID, tt.stateID)
+					}
+					return []store.College{{ID: testutil.TestCollegeID, StateID: stateID}}, tt.err
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/states/x/colleges", ""), "stateId", tt.stateID)
+			serve(t, handleGetCollegesByState(collegeStore), r, tt.wantStatus)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// The admin dashboard figures are cached for dashboardCacheTTL under dashboardCacheKey
+const (
+	dashboardCacheKey = "admin:dashboard"
+	dashboardCacheTTL = 5 * time.Minute
+)
+
+// handleAdminDashboard returns platform-wide statistics for the admin dashboard
+// @Summary      Get admin dashboard
+// @Description  User, task, submission, XP and streak totals for the whole platform. Cached for 5 minutes.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.DashboardStats  "Platform statistics"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/dashboard [get]
+func handleAdminDashboard(statsStore store.StatsStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if redisClient != nil {
+			if cached, err := redisClient.Client.Get(ctx, dashboardCacheKey).Bytes(); err == nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(cached)
+				return
+			}
+		}
+
+		stats, err := statsStore.GetDashboardStats(ctx)
+		if err != nil {
+			log.Printf("Error getting dashboard stats: %v", err)
+			http.Error(w, "Failed to get dashboard stats", http.StatusInternalServerError)
+			return
+		}
+
+		responseJSON, err := json.Marshal(stats)
+		if err != nil {
+			log.Printf("Error encoding dashboard response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+		if redisClient != nil {
+			if err := redisClient.Client.Set(ctx, dashboardCacheKey, responseJSON, dashboardCacheTTL).Err(); err != nil {
+				log.Printf("Error caching dashboard stats: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(responseJSON)
+	}
+}
+
+// handleGetUserCountByState returns the number of students in every state
+// @Summary      Get user count by state
+// @Description  Student count of every state, including states with none, largest first. For the admin choropleth chart.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   store.StateUserCount  "Students per state"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/users/count-by-state [get]
+func handleGetUserCountByState(statsStore store.StatsStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		counts, err := statsStore.GetUserCountByState(ctx)
+		if err != nil {
+			log.Printf("Error counting users by state: %v", err)
+			http.Error(w, "Failed to count users by state", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(counts); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleAdminDashboard(t *testing.T) {
+	tests := []struct {
+		name          string
+		cached        string
+		err           error
+		wantStatus    int
+		wantStoreHit  bool
+		wantUsers     int
+		wantCacheSave bool
+	}{
+		{name: "computes and caches stats", wantStatus: http.StatusOK, wantStoreHit: true, wantUsers: 42, wantCacheSave: true},
+		{name: "serves cached stats", cached: `{"total_users":7}`, wantStatus: http.StatusOK, wantUsers: 7},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantStoreHit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, redisServer := testutil.NewMockRedis(t)
+			if tt.cached != "" {
+				if err := redisServer.Set(dashboardCacheKey, tt.cached); err != nil {
+					t.Fatal(err)
+				}
+			}
+			storeHit := false
+			statsStore := &mock.StatsStore{
+				GetDashboardStatsFunc: func(ctx context.Context) (*store.DashboardStats, error) {
+					storeHit = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.DashboardStats{TotalUsers: 42}, nil
+				},
+			}
+
+			w := serve(t, handleAdminDashboard(statsStore, redisClient), newTestRequest(http.MethodGet, "/admin/dashboard", ""), tt.wantStatus)
+			if storeHit != tt.wantStoreHit {
+				t.Errorf("GetDashboardStats called = %v, want %v", storeHit, tt.wantStoreHit)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got store.DashboardStats
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.TotalUsers != tt.wantUsers {
+				t.Errorf("total_users = %d, want %d", got.TotalUsers, tt.wantUsers)
+			}
+			if tt.wantCacheSave {
+				if !redisServer.Exists(dashboardCacheKey) {
+					t.Errorf("stats were not cached under %s", dashboardCacheKey)
+				} else if ttl := redisServer.TTL(dashboardCacheKey); ttl != 5*time.Minute {
+					t.Errorf("cache TTL = %v, want 5m", ttl)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleGetUserCountByState(t *testing.T) {
+	tests := []struct {
+		name       string
+		counts     []store.StateUserCount
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "every state",
+			counts:     []store.StateUserCount{{StateID: testutil.TestStateID, StateName: "Karnataka", UserCount: 12}, {StateID: "s2", StateName: "Goa", UserCount: 0}},
+			wantStatus: http.StatusOK,
+			wantBody:   `[{"state_id":"` + testutil.TestStateID + `","state_name":"Karnataka","user_count":12},{"state_id":"s2","state_name":"Goa","user_count":0}]`,
+		},
+		{name: "no states", counts: []store.StateUserCount{}, wantStatus: http.StatusOK, wantBody: `[]`},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statsStore := &mock.StatsStore{
+				GetUserCountByStateFunc: func(ctx context.Context) ([]store.StateUserCount, error) {
+					return tt.counts, tt.err
+				},
+			}
+
+			w := serve(t, handleGetUserCountByState(statsStore), newTestRequest(http.MethodGet, "/admin/users/count-by-state", ""), tt.wantStatus)
+			if tt.wantBody != "" {
+				if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+					t.Errorf("body = %s, want %s", got, tt.wantBody)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/email"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// Verification emails can be resent at most resendVerificationLimit times per resendVerificationWindow
+const (
+	resendVerificationLimit  = 3
+	resendVerificationWindow = time.Hour
+	verificationEmailTimeout = 30 * time.Second
+)
+
+// VerifyEmailResponse is returned once an email address has been confirmed
+type VerifyEmailResponse struct {
+	Message string `json:"message"`
+	UserID  string `json:"user_id"`
+}
+
+// sendVerificationEmail creates a fresh verification token for user and emails them the link
+func sendVerificationEmail(ctx context.Context, postgres *db.Postgres, cfg *env.Config, user *store.User) error {
+	emailer := email.GetEmailer()
+	if emailer == nil {
+		return fmt.Errorf("email is not configured")
+	}
+
+	token, err := store.NewEmailVerificationStore(postgres).CreateToken(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	verifyURL, err := url.Parse(cfg.EmailVerificationURL)
+	if err != nil {
+		return fmt.Errorf("invalid EMAIL_VERIFICATION_URL: %w", err)
+	}
+	query := verifyURL.Query()
+	query.Set("token", token)
+	verifyURL.RawQuery = query.Encode()
+
+	return email.SendVerificationEmail(ctx, emailer, user.Email, user.Name, verifyURL.String())
+}
+
+// queueVerificationEmail sends the verification email in the background so registration does not wait on SMTP.
+// Failures are only logged; the user can request a new link with resend-verification.
+func queueVerificationEmail(postgres *db.Postgres, cfg *env.Config, user *store.User) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), verificationEmailTimeout)
+		defer cancel()
+		if err := sendVerificationEmail(ctx, postgres, cfg, user); err != nil {
+			log.Printf("Error sending verification email to user %s: %v", user.ID, err)
+		}
+	}()
+}
+
+// handleVerifyEmail confirms a user's email address from the emailed link
+// @Summary      Verify email
+// @Description  Confirm the email address of the account the token was issued to. Tokens are single-use and expire after 24 hours. No authentication required.
+// @Tags         auth
+// @Produce      json
+// @Param        token  query     string  true  "Verification token from the email"
+// @Success      200    {object}  VerifyEmailResponse  "Email verified"
+// @Failure      400    {string}  string  "Bad request - missing, invalid or expired token"
+// @Failure      500    {string}  string  "Internal server error"
+// @Router       /api/auth/verify-email [get]
+func handleVerifyEmail(verificationStore store.EmailVerificationStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			http.Error(w, "token is required", http.StatusBadRequest)
+			return
+		}
+
+		userID, err := verificationStore.VerifyToken(ctx, token)
+		if err != nil {
+			if err.Error() == "invalid token" {
+				http.Error(w, "Invalid or expired verification token", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error verifying email: %v", err)
+			http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+			return
+		}
+
+		response := VerifyEmailResponse{
+			Message: "Email verified",
+			UserID:  userID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleResendVerification emails the current user a new verification link
+// @Summary      Resend verification email
+// @Description  Send a new email verification link to the current user, invalidating earlier links. Limited to 3 requests per hour.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string  "Verification email sent"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      409  {string}  string  "Email already verified"
+// @Failure      429  {string}  string  "Too many requests"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/auth/resend-verification [post]
+func handleResendVerification(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		userStore := store.NewUserStore(postgres)
+		user, err := userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, "Failed to resend verification email", http.StatusInternalServerError)
+			return
+		}
+		if user.EmailVerifiedAt != nil {
+			http.Error(w, "Email already verified", http.StatusConflict)
+			return
+		}
+
+		if redisClient != nil {
+			rateLimitKey := fmt.Sprintf("email_verification_resend:%s", userID)
+			sends, err := redisClient.Client.Incr(ctx, rateLimitKey).Result()
+			if err != nil {
+				log.Printf("Error checking verification resend rate limit: %v", err)
+				http.Error(w, "Failed to resend verification email", http.StatusInternalServerError)
+				return
+			}
+			if sends == 1 {
+				redisClient.Client.Expire(ctx, rateLimitKey, resendVerificationWindow)
+			}
+			if sends > resendVerificationLimit {
+				http.Error(w, "Too many verification emails requested. Try again later.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		if err := sendVerificationEmail(ctx, postgres, cfg, user); err != nil {
+			log.Printf("Error sending verification email to user %s: %v", userID, err)
+			http.Error(w, "Failed to send verification email", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message": "Verification email sent",
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "verifies", query: "?token=abc", wantStatus: http.StatusOK, wantCalled: true},
+		{name: "missing token", wantStatus: http.StatusBadRequest},
+		{name: "invalid or expired", query: "?token=abc", err: errors.New("invalid token"), wantStatus: http.StatusBadRequest, wantCalled: true},
+		{name: "store error", query: "?token=abc", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			verificationStore := &mock.EmailVerificationStore{
+				VerifyTokenFunc: func(ctx context.Context, token string) (string, error) {
+					called = true
+					if token != "abc" {
+						t.Errorf("token = %q, want %q", token, "abc")
+					}
+					if tt.err != nil {
+						return "", tt.err
+					}
+					return testutil.TestUserID, nil
+				},
+			}
+
+			w := serve(t, handleVerifyEmail(verificationStore), newTestRequest(http.MethodGet, "/api/auth/verify-email"+tt.query, ""), tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("VerifyToken called = %v, want %v", called, tt.wantCalled)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got VerifyEmailResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.UserID != testutil.TestUserID {
+				t.Errorf("user_id = %q, want %q", got.UserID, testutil.TestUserID)
+			}
+		})
+	}
+}
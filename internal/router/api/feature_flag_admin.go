@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// handleGetFeatureFlags lists all feature flags (admin)
+// @Summary      Get feature flags
+// @Description  List every feature flag with its rollout configuration. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   store.FeatureFlag
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/feature-flags [get]
+func handleGetFeatureFlags(featureFlagStore store.FeatureFlagStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		flags, err := featureFlagStore.GetFeatureFlags(ctx)
+		if err != nil {
+			log.Printf("Error getting feature flags: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get feature flags: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(flags); err != nil {
+			log.Printf("Error encoding feature flags response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleUpdateFeatureFlag creates or updates a feature flag (admin)
+// @Summary      Update feature flag
+// @Description  Create or replace a feature flag. A flag is on for a user when enabled and the user is in enabled_user_ids or falls inside rollout_percentage. Takes effect immediately on this instance and within 30 seconds on others. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name  path      string                          true  "Flag name"
+// @Param        body  body      store.UpdateFeatureFlagRequest  true  "Flag configuration"
+// @Success      200   {object}  store.FeatureFlag
+// @Failure      400   {string}  string  "Bad request - invalid rollout_percentage"
+// @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      500   {string}  string  "Internal server error"
+// @Router       /admin/feature-flags/{name} [put]
+func handleUpdateFeatureFlag(featureFlagStore store.FeatureFlagStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		name := strings.TrimSpace(chi.URLParam(r, "name"))
+		if name == "" {
+			http.Error(w, "Flag name is required", http.StatusBadRequest)
+			return
+		}
+
+		var req store.UpdateFeatureFlagRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.RolloutPercentage < 0 || req.RolloutPercentage > 100 {
+			http.Error(w, "rollout_percentage must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+
+		flag, err := featureFlagStore.UpsertFeatureFlag(ctx, name, req)
+		if err != nil {
+			log.Printf("Error updating feature flag %s: %v", name, err)
+			http.Error(w, fmt.Sprintf("Failed to update feature flag: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(flag); err != nil {
+			log.Printf("Error encoding feature flag response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
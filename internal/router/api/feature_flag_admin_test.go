@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+)
+
+func TestHandleUpdateFeatureFlag(t *testing.T) {
+	tests := []struct {
+		name       string
+		flagName   string
+		body       string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "enables flag", flagName: "streak_freeze", body: `{"enabled":true,"rollout_percentage":50}`, wantStatus: http.StatusOK, wantCalled: true},
+		{name: "full rollout", flagName: "streak_freeze", body: `{"enabled":true,"rollout_percentage":100}`, wantStatus: http.StatusOK, wantCalled: true},
+		{name: "blank name", flagName: "  ", body: `{"enabled":true}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", flagName: "streak_freeze", body: `{"enabled":`, wantStatus: http.StatusBadRequest},
+		{name: "rollout below 0", flagName: "streak_freeze", body: `{"rollout_percentage":-1}`, wantStatus: http.StatusBadRequest},
+		{name: "rollout above 100", flagName: "streak_freeze", body: `{"rollout_percentage":101}`, wantStatus: http.StatusBadRequest},
+		{name: "store error", flagName: "streak_freeze", body: `{"enabled":true}`, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			featureFlagStore := &mock.FeatureFlagStore{
+				UpsertFeatureFlagFunc: func(ctx context.Context, name string, req store.UpdateFeatureFlagRequest) (*store.FeatureFlag, error) {
+					called = true
+					if name != tt.flagName {
+						t.Errorf("name = %q, want %q", name, tt.flagName)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.FeatureFlag{FlagName: name, Enabled: req.Enabled, RolloutPercentage: req.RolloutPercentage}, nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodPut, "/admin/feature-flags/x", tt.body), "name", tt.flagName)
+			serve(t, handleUpdateFeatureFlag(featureFlagStore), r, tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("UpsertFeatureFlag called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
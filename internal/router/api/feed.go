@@ -20,23 +20,25 @@ type FeedResponse struct {
 	Page       int              `json:"page"`
 	PageSize   int              `json:"page_size"`
 	TotalPages int              `json:"total_pages"`
-	FeedType   string           `json:"feed_type"` // "pan-india", "state", "college"
+	FeedType   string           `json:"feed_type"`             // "pan-india", "state", "college"
+	NextCursor string           `json:"next_cursor,omitempty"` // Pass as cursor to get the next page; omitted when a page comes back short
 }
 
 // handleGetFeed handles getting the task feed with pagination
 // @Summary      Get feed
-// @Description  Get feed items (pan-india, state, or college) with pagination. Only shows approved task submissions.
+// @Description  Get feed items (pan-india, state, or college), newest first. Only shows approved task submissions. With a JWT, items from users the caller has blocked or been blocked by are hidden. For infinite scroll pass next_cursor from the previous response as cursor: items added between requests do not shift pages. page is ignored when cursor is set.
 // @Tags         feed
 // @Accept       json
 // @Produce      json
 // @Param        type      query     string  false  "Feed type: pan-india, state, college (default: pan-india)"
 // @Param        page      query     int     false  "Page number (default: 1)"
 // @Param        page_size query     int     false  "Items per page (default: 20, max: 100)"
+// @Param        cursor    query     string  false  "Cursor from the previous page's next_cursor"
 // @Success      200       {object}  FeedResponse  "Feed items"
-// @Failure      400       {string}  string  "Bad request"
+// @Failure      400       {string}  string  "Bad request - invalid feed type or cursor"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/feed [get]
-func handleGetFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleGetFeed(feedStore store.FeedStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -75,6 +77,19 @@ func handleGetFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 				pageSize = ps
 			}
 		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+
+		var cursor *store.FeedCursor
+		if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+			decoded, err := store.DecodeFeedCursor(cursorStr)
+			if err != nil {
+				http.Error(w, "Invalid cursor", http.StatusBadRequest)
+				return
+			}
+			cursor = decoded
+		}
 
 		// Get current user ID (optional - for state/college filtering and reaction checking)
 		userID := ""
@@ -88,15 +103,13 @@ func handleGetFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
-		// Create feed store
-		feedStore := store.NewFeedStore(postgres)
-
 		// Get feed items
 		items, total, err := feedStore.GetFeed(ctx, store.GetFeedOptions{
 			FeedType: feedType,
 			UserID:   userID,
 			Page:     page,
 			PageSize: pageSize,
+			Cursor:   cursor,
 		})
 		if err != nil {
 			log.Printf("Error getting feed: %v", err)
@@ -119,6 +132,11 @@ func handleGetFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			TotalPages: totalPages,
 			FeedType:   feedTypeStr,
 		}
+		// A full page may have more after it
+		if len(items) == pageSize {
+			last := items[len(items)-1]
+			response.NextCursor = store.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -143,10 +161,8 @@ func handleGetFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 // @Failure      400       {string}  string  "Bad request"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/feed/user/{userId} [get]
-func handleGetUserFeed(postgres *db.Postgres) http.HandlerFunc {
+func handleGetUserFeed(feedStore store.FeedStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-
 		// Get user ID from URL path
 		userID := chi.URLParam(r, "userId")
 		if userID == "" {
@@ -154,56 +170,64 @@ func handleGetUserFeed(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		// Get pagination parameters
-		page := 1
-		pageSize := 20
-
-		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
-			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-				page = p
-			}
-		}
+		serveUserFeed(w, r, feedStore, userID)
+	}
+}
 
-		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
-			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
-				pageSize = ps
-			}
-		}
+// serveUserFeed writes one page of a user's approved submissions as a FeedResponse.
+// page_size defaults to 20 and is capped at 100.
+func serveUserFeed(w http.ResponseWriter, r *http.Request, feedStore store.FeedStoreInterface, userID string) {
+	ctx := r.Context()
 
-		// Create feed store
-		feedStore := store.NewFeedStore(postgres)
+	// Get pagination parameters
+	page := 1
+	pageSize := 20
 
-		// Get user feed items
-		items, total, err := feedStore.GetUserFeed(ctx, userID, page, pageSize)
-		if err != nil {
-			log.Printf("Error getting user feed: %v", err)
-			http.Error(w, fmt.Sprintf("Failed to get user feed: %v", err), http.StatusInternalServerError)
-			return
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
 		}
+	}
 
-		// Calculate total pages
-		totalPages := (total + pageSize - 1) / pageSize
-		if totalPages == 0 {
-			totalPages = 1
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+			pageSize = ps
 		}
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
 
-		// Return response
-		response := FeedResponse{
-			Items:      items,
-			Total:      total,
-			Page:       page,
-			PageSize:   pageSize,
-			TotalPages: totalPages,
-			FeedType:   "user",
-		}
+	// Get user feed items
+	items, total, err := feedStore.GetUserFeed(ctx, userID, page, pageSize)
+	if err != nil {
+		log.Printf("Error getting user feed: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to get user feed: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			log.Printf("Error encoding user feed response: %v", err)
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-			return
-		}
+	// Calculate total pages
+	totalPages := (total + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	// Return response
+	response := FeedResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		FeedType:   "user",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding user feed response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
 	}
 }
 
@@ -226,7 +250,7 @@ type ReactToFeedRequest struct {
 // @Failure      401       {string}  string  "Unauthorized"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/feed/{feedId}/react [post]
-func handleReactToFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleReactToFeed(feedStore store.FeedStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -257,9 +281,6 @@ func handleReactToFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 			return
 		}
 
-		// Create feed store
-		feedStore := store.NewFeedStore(postgres)
-
 		// Add reaction
 		err := feedStore.AddReaction(ctx, feedID, userID, req.Reaction)
 		if err != nil {
@@ -272,8 +293,8 @@ func handleReactToFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]string{
-			"message": "Reaction added successfully",
-			"feed_id": feedID,
+			"message":  "Reaction added successfully",
+			"feed_id":  feedID,
 			"reaction": req.Reaction,
 		})
 	}
@@ -292,7 +313,7 @@ type CommentResponse struct {
 
 // handleCommentOnFeed handles commenting on a feed item
 // @Summary      Comment on feed
-// @Description  Add a comment to a feed item. Protected route.
+// @Description  Add a comment to a feed item. Protected route. Users who have blocked each other cannot comment on each other's items.
 // @Tags         feed
 // @Accept       json
 // @Produce      json
@@ -302,6 +323,7 @@ type CommentResponse struct {
 // @Success      201       {object}  CommentResponse      "Comment added successfully"
 // @Failure      400       {string}  string  "Bad request"
 // @Failure      401       {string}  string  "Unauthorized"
+// @Failure      403       {string}  string  "Blocked by or blocking the item's owner"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/feed/{feedId}/comment [post]
 func handleCommentOnFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
@@ -341,11 +363,27 @@ func handleCommentOnFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFun
 		// Add comment
 		comment, err := feedStore.AddComment(ctx, feedID, userID, req.Comment)
 		if err != nil {
+			if err.Error() == "comment contains blocked words" {
+				http.Error(w, "Comment contains inappropriate language", http.StatusBadRequest)
+				return
+			}
+			if err.Error() == "comment blocked" {
+				http.Error(w, "You cannot comment on this post", http.StatusForbidden)
+				return
+			}
 			log.Printf("Error adding comment: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to add comment: %v", err), http.StatusInternalServerError)
 			return
 		}
 
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      userID,
+			EventType:   store.ActivityEventCommentPosted,
+			EntityID:    feedID,
+			EntityType:  "feed",
+			Description: "Commented on a post",
+		})
+
 		// Return response
 		response := CommentResponse{
 			Comment: comment,
@@ -361,3 +399,148 @@ func handleCommentOnFeed(postgres *db.Postgres, cfg *env.Config) http.HandlerFun
 		}
 	}
 }
+
+// FeedCommentsResponse represents a page of comments on a feed item
+type FeedCommentsResponse struct {
+	Comments   []store.FeedComment `json:"comments"`
+	Total      int                 `json:"total"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// handleGetFeedComments handles getting comments on a feed item with pagination
+// @Summary      Get feed comments
+// @Description  Get comments on a feed item, oldest first, with pagination. Used for "Show all N comments".
+// @Tags         feed
+// @Produce      json
+// @Param        feedId    path      string  true   "Feed ID"
+// @Param        page      query     int     false  "Page number (default: 1)"
+// @Param        page_size query     int     false  "Comments per page (default: 20, max: 100)"
+// @Success      200       {object}  FeedCommentsResponse  "Comments"
+// @Failure      400       {string}  string  "Bad request"
+// @Failure      404       {string}  string  "Feed item not found"
+// @Failure      500       {string}  string  "Internal server error"
+// @Router       /api/feed/{feedId}/comments [get]
+func handleGetFeedComments(feedStore store.FeedStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		feedID := chi.URLParam(r, "feedId")
+		if feedID == "" {
+			http.Error(w, "Feed ID is required", http.StatusBadRequest)
+			return
+		}
+
+		page := 1
+		pageSize := 20
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
+			}
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+
+		exists, err := feedStore.FeedEntryExists(ctx, feedID)
+		if err != nil {
+			log.Printf("Error checking feed item %s: %v", feedID, err)
+			http.Error(w, fmt.Sprintf("Failed to get comments: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if !exists {
+			http.Error(w, "Feed item not found", http.StatusNotFound)
+			return
+		}
+
+		total, err := feedStore.CountComments(ctx, feedID)
+		if err != nil {
+			log.Printf("Error counting comments for feed item %s: %v", feedID, err)
+			http.Error(w, fmt.Sprintf("Failed to get comments: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		comments, err := feedStore.GetComments(ctx, feedID, pageSize, (page-1)*pageSize)
+		if err != nil {
+			log.Printf("Error getting comments for feed item %s: %v", feedID, err)
+			http.Error(w, fmt.Sprintf("Failed to get comments: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if comments == nil {
+			comments = []store.FeedComment{}
+		}
+
+		totalPages := (total + pageSize - 1) / pageSize
+		if totalPages == 0 {
+			totalPages = 1
+		}
+
+		response := FeedCommentsResponse{
+			Comments:   comments,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: totalPages,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding comments response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleDeleteFeedEntry handles hiding the authenticated user's own feed item
+// @Summary      Delete feed item
+// @Description  Remove the authenticated user's own feed item from all feeds. The submission itself is kept.
+// @Tags         feed
+// @Security     BearerAuth
+// @Param        feedId  path  string  true  "Feed ID"
+// @Success      204     "Feed item removed"
+// @Failure      400     {string}  string  "Bad request"
+// @Failure      401     {string}  string  "Unauthorized"
+// @Failure      403     {string}  string  "Not the owner of this feed item"
+// @Failure      404     {string}  string  "Feed item not found"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/feed/{feedId} [delete]
+func handleDeleteFeedEntry(feedStore store.FeedStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		feedID := chi.URLParam(r, "feedId")
+		if feedID == "" {
+			http.Error(w, "Feed ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := feedStore.HideFeedEntry(ctx, feedID, userID); err != nil {
+			switch err.Error() {
+			case "feed item not found":
+				http.Error(w, "Feed item not found", http.StatusNotFound)
+			case "not the owner of this feed item":
+				http.Error(w, "You can only delete your own feed items", http.StatusForbidden)
+			default:
+				log.Printf("Error hiding feed item %s: %v", feedID, err)
+				http.Error(w, fmt.Sprintf("Failed to delete feed item: %v", err), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetFeedComments(t *testing.T) {
+	tests := []struct {
+		name           string
+		feedID         string
+		query          string
+		exists         bool
+		total          int
+		wantStatus     int
+		wantLimit      int
+		wantOffset     int
+		wantTotalPages int
+	}{
+		{name: "first page", feedID: testutil.TestFeedID, exists: true, total: 3, wantStatus: http.StatusOK, wantLimit: 20, wantOffset: 0, wantTotalPages: 1},
+		{name: "third page", feedID: testutil.TestFeedID, query: "?page=3&page_size=10", exists: true, total: 45, wantStatus: http.StatusOK, wantLimit: 10, wantOffset: 20, wantTotalPages: 5},
+		{name: "page size capped", feedID: testutil.TestFeedID, query: "?page_size=1000", exists: true, total: 150, wantStatus: http.StatusOK, wantLimit: 100, wantOffset: 0, wantTotalPages: 2},
+		{name: "no comments", feedID: testutil.TestFeedID, exists: true, wantStatus: http.StatusOK, wantLimit: 20, wantTotalPages: 1},
+		{name: "missing feed ID", wantStatus: http.StatusBadRequest},
+		{name: "unknown or hidden item", feedID: testutil.TestFeedID, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedStore := &mock.FeedStore{
+				FeedEntryExistsFunc: func(ctx context.Context, feedID string) (bool, error) {
+					return tt.exists, nil
+				},
+				CountCommentsFunc: func(ctx context.Context, feedID string) (int, error) {
+					return tt.total, nil
+				},
+				GetCommentsFunc: func(ctx context.Context, feedID string, limit, offset int) ([]store.FeedComment, error) {
+					if limit != tt.wantLimit || offset != tt.wantOffset {
+						t.Errorf("limit, offset = %d, %d; want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+					}
+					return nil, nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/feed/x/comments"+tt.query, ""), "feedId", tt.feedID)
+			w := serve(t, handleGetFeedComments(feedStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got FeedCommentsResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Comments == nil {
+				t.Error("comments = null, want []")
+			}
+			if got.TotalPages != tt.wantTotalPages {
+				t.Errorf("total_pages = %d, want %d", got.TotalPages, tt.wantTotalPages)
+			}
+		})
+	}
+}
+
+func TestHandleDeleteFeedEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		feedID     string
+		err        error
+		wantStatus int
+	}{
+		{name: "hides own item", userID: testutil.TestUserID, feedID: testutil.TestFeedID, wantStatus: http.StatusNoContent},
+		{name: "anonymous", feedID: testutil.TestFeedID, wantStatus: http.StatusUnauthorized},
+		{name: "missing feed ID", userID: testutil.TestUserID, wantStatus: http.StatusBadRequest},
+		{name: "unknown item", userID: testutil.TestUserID, feedID: testutil.TestFeedID, err: errors.New("feed item not found"), wantStatus: http.StatusNotFound},
+		{name: "someone else's item", userID: testutil.TestUserID, feedID: testutil.TestFeedID, err: errors.New("not the owner of this feed item"), wantStatus: http.StatusForbidden},
+		{name: "store error", userID: testutil.TestUserID, feedID: testutil.TestFeedID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedStore := &mock.FeedStore{
+				HideFeedEntryFunc: func(ctx context.Context, feedID, userID string) error {
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodDelete, "/api/feed/x", ""), tt.userID)
+			r = withURLParams(r, "feedId", tt.feedID)
+			serve(t, handleDeleteFeedEntry(feedStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleCommentOnFeedBlocked(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectQuery(`FROM completed_task_feed ctf\s+JOIN user_blocks ub`).
+		WithArgs(testutil.TestFeedID, testutil.TestUserID).
+		WillReturnRows([]string{"exists"}, []any{true})
+
+	r := withUserID(newTestRequest(http.MethodPost, "/api/feed/x/comment", `{"comment":"Nice work"}`), testutil.TestUserID)
+	r = withURLParams(r, "feedId", testutil.TestFeedID)
+	serve(t, handleCommentOnFeed(postgres, &env.Config{}), r, http.StatusForbidden)
+}
+
+func TestHandleGetFeedCursorPagination(t *testing.T) {
+	// 30 items, two per timestamp so the id tie-break is exercised
+	var items []store.FeedItem
+	base := testutil.TestTime
+	for i := 0; i < 30; i++ {
+		items = append(items, store.FeedItem{
+			ID:        fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+			CreatedAt: base.Add(time.Duration(i/2) * time.Minute),
+		})
+	}
+	original := len(items)
+
+	// The fake pages the way GetFeed's SQL does: (created_at, id) descending, strictly after the cursor
+	less := func(a, b store.FeedItem) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+	feedStore := &mock.FeedStore{
+		GetFeedFunc: func(ctx context.Context, opts store.GetFeedOptions) ([]store.FeedItem, int, error) {
+			sorted := append([]store.FeedItem(nil), items...)
+			sort.Slice(sorted, func(i, j int) bool { return less(sorted[j], sorted[i]) })
+			if opts.Cursor != nil {
+				after := store.FeedItem{ID: opts.Cursor.ID, CreatedAt: opts.Cursor.CreatedAt}
+				var rest []store.FeedItem
+				for _, item := range sorted {
+					if less(item, after) {
+						rest = append(rest, item)
+					}
+				}
+				sorted = rest
+			} else {
+				sorted = sorted[min((opts.Page-1)*opts.PageSize, len(sorted)):]
+			}
+			return sorted[:min(opts.PageSize, len(sorted))], len(items), nil
+		},
+	}
+
+	seen := make(map[string]int)
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination did not stop")
+		}
+		target := "/api/feed?page_size=10"
+		if cursor != "" {
+			target += "&cursor=" + cursor
+		}
+		w := serve(t, handleGetFeed(feedStore, &env.Config{}), newTestRequest(http.MethodGet, target, ""), http.StatusOK)
+		var got FeedResponse
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		for _, item := range got.Items {
+			seen[item.ID]++
+		}
+		if pages == 0 {
+			// Newer items arrive after the first page; they must not push older ones onto the next page
+			for i := original; i < original+5; i++ {
+				items = append(items, store.FeedItem{
+					ID:        fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+					CreatedAt: base.Add(time.Hour + time.Duration(i)*time.Minute),
+				})
+			}
+		}
+		if (got.NextCursor != "") != (len(got.Items) == 10) {
+			t.Errorf("page %d: %d items with next_cursor %q", pages+1, len(got.Items), got.NextCursor)
+		}
+		if got.NextCursor == "" {
+			break
+		}
+		cursor = got.NextCursor
+	}
+
+	for _, item := range items[:original] {
+		if seen[item.ID] != 1 {
+			t.Errorf("item %s seen %d times, want 1", item.ID, seen[item.ID])
+		}
+	}
+	if len(seen) != original {
+		t.Errorf("saw %d distinct items, want %d", len(seen), original)
+	}
+}
+
+func TestHandleGetFeedCursor(t *testing.T) {
+	cursor := store.FeedCursor{CreatedAt: testutil.TestTime, ID: testutil.TestFeedID}
+	tests := []struct {
+		name       string
+		query      string
+		items      int
+		wantCursor *store.FeedCursor
+		wantStatus int
+		wantNext   bool
+	}{
+		{name: "first page", query: "?page_size=2", items: 2, wantStatus: http.StatusOK, wantNext: true},
+		{name: "cursor passed through", query: "?page_size=2&cursor=" + cursor.Encode(), items: 2, wantCursor: &cursor, wantStatus: http.StatusOK, wantNext: true},
+		{name: "short page has no next cursor", query: "?page_size=2", items: 1, wantStatus: http.StatusOK},
+		{name: "invalid cursor", query: "?cursor=not-a-cursor", wantStatus: http.StatusBadRequest},
+		{name: "cursor without id", query: "?cursor=" + base64.RawURLEncoding.EncodeToString([]byte("2024-01-01T00:00:00Z|")), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedStore := &mock.FeedStore{
+				GetFeedFunc: func(ctx context.Context, opts store.GetFeedOptions) ([]store.FeedItem, int, error) {
+					if !reflect.DeepEqual(opts.Cursor, tt.wantCursor) {
+						t.Errorf("cursor = %+v, want %+v", opts.Cursor, tt.wantCursor)
+					}
+					items := make([]store.FeedItem, tt.items)
+					for i := range items {
+						items[i] = store.FeedItem{ID: fmt.Sprintf("item-%d", i), CreatedAt: testutil.TestTime.Add(-time.Duration(i) * time.Minute)}
+					}
+					return items, 10, nil
+				},
+			}
+
+			w := serve(t, handleGetFeed(feedStore, &env.Config{}), newTestRequest(http.MethodGet, "/api/feed"+tt.query, ""), tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got FeedResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if !tt.wantNext {
+				if got.NextCursor != "" {
+					t.Errorf("next_cursor = %q, want none", got.NextCursor)
+				}
+				return
+			}
+			next, err := store.DecodeFeedCursor(got.NextCursor)
+			if err != nil {
+				t.Fatalf("next_cursor %q: %v", got.NextCursor, err)
+			}
+			last := got.Items[len(got.Items)-1]
+			if next.ID != last.ID || !next.CreatedAt.Equal(last.CreatedAt) {
+				t.Errorf("next_cursor = %+v, want the last item %s at %v", next, last.ID, last.CreatedAt)
+			}
+		})
+	}
+}
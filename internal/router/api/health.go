@@ -65,3 +65,46 @@ func HandleHealth(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc
 		}
 	}
 }
+
+// DBPoolResponse reports PostgreSQL connection pool statistics
+type DBPoolResponse struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// HandleDBPoolHealth handles the database pool stats endpoint
+// @Summary      Database pool stats
+// @Description  PostgreSQL connection pool statistics (open, in use, idle, waits) for ops visibility
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  DBPoolResponse
+// @Router       /health/db-pool [get]
+func HandleDBPoolHealth(postgres *db.Postgres) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := postgres.Stats()
+
+		response := DBPoolResponse{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration.String(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleHealth(t *testing.T) {
+	tests := []struct {
+		name         string
+		postgresDown bool
+		redisDown    bool
+		wantStatus   int
+		wantPostgres string
+		wantRedis    string
+	}{
+		{name: "healthy", wantStatus: http.StatusOK, wantPostgres: "up", wantRedis: "up"},
+		{name: "postgres down", postgresDown: true, wantStatus: http.StatusServiceUnavailable, wantPostgres: "down", wantRedis: "up"},
+		{name: "redis down", redisDown: true, wantStatus: http.StatusServiceUnavailable, wantPostgres: "up", wantRedis: "down"},
+		{name: "both down", postgresDown: true, redisDown: true, wantStatus: http.StatusServiceUnavailable, wantPostgres: "down", wantRedis: "down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			ping := mockDB.ExpectPing()
+			if tt.postgresDown {
+				ping.WillReturnError(errors.New("connection refused"))
+			}
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.redisDown {
+				server.Close()
+			}
+
+			w := serve(t, HandleHealth(postgres, redisClient), newTestRequest(http.MethodGet, "/health", ""), tt.wantStatus)
+			var got HealthResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Services["postgres"] != tt.wantPostgres || got.Services["redis"] != tt.wantRedis {
+				t.Errorf("services = %v, want postgres %s, redis %s", got.Services, tt.wantPostgres, tt.wantRedis)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newTestRequest builds a request for calling a handler directly. body may be empty.
+func newTestRequest(method, target, body string) *http.Request {
+	if body == "" {
+		return httptest.NewRequest(method, target, nil)
+	}
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+// withUserID adds the authenticated user JWTAuthMiddleware would set; an empty userID leaves r anonymous
+func withUserID(r *http.Request, userID string) *http.Request {
+	if userID == "" {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+}
+
+// withAdmin adds the authenticated admin and permissions adminAuthMiddleware would set
+func withAdmin(r *http.Request, adminID string, permissions ...string) *http.Request {
+	ctx := context.WithValue(r.Context(), UserIDKey, adminID)
+	ctx = context.WithValue(ctx, AdminIDKey, adminID)
+	ctx = context.WithValue(ctx, AdminPermissionsKey, permissions)
+	return r.WithContext(ctx)
+}
+
+// withURLParams sets chi URL parameters from key, value pairs
+func withURLParams(r *http.Request, keyValues ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		rctx.URLParams.Add(keyValues[i], keyValues[i+1])
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+// serve runs handler on r and fails the test if the status is not wantStatus
+func serve(t *testing.T, handler http.Handler, r *http.Request, wantStatus int) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != wantStatus {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, wantStatus, strings.TrimSpace(w.Body.String()))
+	}
+	return w
+}
@@ -1,13 +1,16 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
@@ -29,11 +32,11 @@ type LeaderboardResponse struct {
 // @Produce      json
 // @Param        page      query     int     false  "Page number (default: 1)"
 // @Param        page_size query     int     false  "Items per page (default: 100, max: 1000)"
-// @Param        period    query     string  false  "Time period: all, weekly, monthly (default: all)"
+// @Param        period    query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
 // @Success      200       {object}  LeaderboardResponse  "Leaderboard entries"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/leaderboard/pan-india [get]
-func handleGetPanIndiaLeaderboard(postgres *db.Postgres) http.HandlerFunc {
+func handleGetPanIndiaLeaderboard(leaderboardStore store.LeaderboardStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -44,7 +47,7 @@ func handleGetPanIndiaLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 		if period == "" {
 			period = "all"
 		}
-		if period != "all" && period != "weekly" && period != "monthly" {
+		if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
 			period = "all"
 		}
 
@@ -65,9 +68,6 @@ func handleGetPanIndiaLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		// Create leaderboard store
-		leaderboardStore := store.NewLeaderboardStore(postgres)
-
 		// Get leaderboard entries
 		entries, err := leaderboardStore.GetPanIndiaLeaderboard(ctx, pageSize, offset, period)
 		if err != nil {
@@ -99,8 +99,8 @@ func handleGetPanIndiaLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
-// handleGetPanIndiaLeaderboardWithPeriod handles pan-India leaderboard with a fixed period (weekly or monthly).
-func handleGetPanIndiaLeaderboardWithPeriod(postgres *db.Postgres, period string) http.HandlerFunc {
+// handleGetPanIndiaLeaderboardWithPeriod handles pan-India leaderboard with a fixed period (daily, weekly or monthly).
+func handleGetPanIndiaLeaderboardWithPeriod(leaderboardStore store.LeaderboardStoreInterface, period string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		page := 1
@@ -119,7 +119,6 @@ func handleGetPanIndiaLeaderboardWithPeriod(postgres *db.Postgres, period string
 		if offset < 0 {
 			offset = 0
 		}
-		leaderboardStore := store.NewLeaderboardStore(postgres)
 		entries, err := leaderboardStore.GetPanIndiaLeaderboard(ctx, pageSize, offset, period)
 		if err != nil {
 			log.Printf("Error getting pan-india %s leaderboard: %v", period, err)
@@ -150,12 +149,12 @@ func handleGetPanIndiaLeaderboardWithPeriod(postgres *db.Postgres, period string
 // @Param        state_id  query     string  true   "State ID"
 // @Param        page      query     int     false  "Page number (default: 1)"
 // @Param        page_size query     int     false  "Items per page (default: 100, max: 1000)"
-// @Param        period    query     string  false  "Time period: all, weekly, monthly (default: all)"
+// @Param        period    query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
 // @Success      200       {object}  LeaderboardResponse  "Leaderboard entries"
 // @Failure      400       {string}  string  "Bad request - state_id required"
 // @Failure      500       {string}  string  "Internal server error"
 // @Router       /api/leaderboard/state [get]
-func handleGetStateLeaderboard(postgres *db.Postgres) http.HandlerFunc {
+func handleGetStateLeaderboard(leaderboardStore store.LeaderboardStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -173,7 +172,7 @@ func handleGetStateLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 		if period == "" {
 			period = "all"
 		}
-		if period != "all" && period != "weekly" && period != "monthly" {
+		if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
 			period = "all"
 		}
 
@@ -194,9 +193,6 @@ func handleGetStateLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		// Create leaderboard store
-		leaderboardStore := store.NewLeaderboardStore(postgres)
-
 		// Get leaderboard entries
 		entries, err := leaderboardStore.GetStateLeaderboard(ctx, stateID, pageSize, offset, period)
 		if err != nil {
@@ -229,8 +225,8 @@ func handleGetStateLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
-// handleGetStateLeaderboardWithPeriod handles state leaderboard with a fixed period (weekly or monthly).
-func handleGetStateLeaderboardWithPeriod(postgres *db.Postgres, period string) http.HandlerFunc {
+// handleGetStateLeaderboardWithPeriod handles state leaderboard with a fixed period (daily, weekly or monthly).
+func handleGetStateLeaderboardWithPeriod(leaderboardStore store.LeaderboardStoreInterface, period string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		stateID := r.URL.Query().Get("state_id")
@@ -254,7 +250,6 @@ func handleGetStateLeaderboardWithPeriod(postgres *db.Postgres, period string) h
 		if offset < 0 {
 			offset = 0
 		}
-		leaderboardStore := store.NewLeaderboardStore(postgres)
 		entries, err := leaderboardStore.GetStateLeaderboard(ctx, stateID, pageSize, offset, period)
 		if err != nil {
 			log.Printf("Error getting state %s leaderboard: %v", period, err)
@@ -286,12 +281,12 @@ func handleGetStateLeaderboardWithPeriod(postgres *db.Postgres, period string) h
 // @Param        college_id query     string  true   "College ID"
 // @Param        page       query     int     false  "Page number (default: 1)"
 // @Param        page_size  query     int     false  "Items per page (default: 100, max: 1000)"
-// @Param        period     query     string  false  "Time period: all, weekly, monthly (default: all)"
+// @Param        period     query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
 // @Success      200        {object}  LeaderboardResponse  "Leaderboard entries"
 // @Failure      400        {string}  string  "Bad request - college_id required"
 // @Failure      500        {string}  string  "Internal server error"
 // @Router       /api/leaderboard/college [get]
-func handleGetCollegeLeaderboard(postgres *db.Postgres) http.HandlerFunc {
+func handleGetCollegeLeaderboard(leaderboardStore store.LeaderboardStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -309,7 +304,7 @@ func handleGetCollegeLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 		if period == "" {
 			period = "all"
 		}
-		if period != "all" && period != "weekly" && period != "monthly" {
+		if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
 			period = "all"
 		}
 
@@ -330,9 +325,6 @@ func handleGetCollegeLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		// Create leaderboard store
-		leaderboardStore := store.NewLeaderboardStore(postgres)
-
 		// Get leaderboard entries
 		entries, err := leaderboardStore.GetCollegeLeaderboard(ctx, collegeID, pageSize, offset, period)
 		if err != nil {
@@ -365,8 +357,8 @@ func handleGetCollegeLeaderboard(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
-// handleGetCollegeLeaderboardWithPeriod handles college leaderboard with a fixed period (weekly or monthly).
-func handleGetCollegeLeaderboardWithPeriod(postgres *db.Postgres, period string) http.HandlerFunc {
+// handleGetCollegeLeaderboardWithPeriod handles college leaderboard with a fixed period (daily, weekly or monthly).
+func handleGetCollegeLeaderboardWithPeriod(leaderboardStore store.LeaderboardStoreInterface, period string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		collegeID := r.URL.Query().Get("college_id")
@@ -390,7 +382,6 @@ func handleGetCollegeLeaderboardWithPeriod(postgres *db.Postgres, period string)
 		if offset < 0 {
 			offset = 0
 		}
-		leaderboardStore := store.NewLeaderboardStore(postgres)
 		entries, err := leaderboardStore.GetCollegeLeaderboard(ctx, collegeID, pageSize, offset, period)
 		if err != nil {
 			log.Printf("Error getting college %s leaderboard: %v", period, err)
@@ -412,3 +403,325 @@ func handleGetCollegeLeaderboardWithPeriod(postgres *db.Postgres, period string)
 		_ = json.NewEncoder(w).Encode(response)
 	}
 }
+
+// statesTopCacheTTL is how long the states-top leaderboard is cached in Redis
+const statesTopCacheTTL = 60 * time.Second
+
+// handleGetStatesTopLeaderboard handles getting the top student from each state
+// @Summary      Get top user per state
+// @Description  Get the best student from every state side-by-side. States with no students are included with a null user. Cached for 60 seconds.
+// @Tags         leaderboard
+// @Accept       json
+// @Produce      json
+// @Param        limit   query     int     false  "Maximum number of states (default: 100, max: 1000)"
+// @Param        period  query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
+// @Success      200     {array}   store.StateTopEntry  "Top user per state"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/leaderboard/states-top [get]
+func handleGetStatesTopLeaderboard(leaderboardStore store.LeaderboardStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		limit := 100
+		period := r.URL.Query().Get("period")
+		if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
+			period = "all"
+		}
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+				limit = l
+			}
+		}
+
+		// Serve from cache when available
+		cacheKey := fmt.Sprintf("leaderboard:states-top:%s:%d", period, limit)
+		if redisClient != nil {
+			if cached, err := redisClient.Client.Get(ctx, cacheKey).Bytes(); err == nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(cached)
+				return
+			}
+		}
+
+		entries, err := leaderboardStore.GetTopUserPerState(ctx, limit, period)
+		if err != nil {
+			log.Printf("Error getting top user per state: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get leaderboard: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		responseJSON, err := json.Marshal(entries)
+		if err != nil {
+			log.Printf("Error encoding states-top response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+		if redisClient != nil {
+			if err := redisClient.Client.Set(ctx, cacheKey, responseJSON, statesTopCacheTTL).Err(); err != nil {
+				log.Printf("Error caching states-top leaderboard: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(responseJSON)
+	}
+}
+
+// parseRankingsQuery reads period, limit and offset for the college and state rankings
+func parseRankingsQuery(r *http.Request) (period string, limit, offset int) {
+	period = r.URL.Query().Get("period")
+	if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
+		period = "all"
+	}
+	limit = 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o > 0 {
+			offset = o
+		}
+	}
+	return period, limit, offset
+}
+
+// serveCachedRankings writes a rankings page, serving it from the Redis cache when present.
+// fetch loads the page from the database on a cache miss.
+func serveCachedRankings(w http.ResponseWriter, r *http.Request, redisClient *db.Redis, scope string, fetch func(ctx context.Context, limit, offset int, period string) (interface{}, error)) {
+	ctx := r.Context()
+	period, limit, offset := parseRankingsQuery(r)
+
+	cacheKey := ws.RankingsCacheKey(scope, period)
+	cacheField := fmt.Sprintf("%d:%d", limit, offset)
+	if redisClient != nil {
+		if cached, err := redisClient.Client.HGet(ctx, cacheKey, cacheField).Bytes(); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(cached)
+			return
+		}
+	}
+
+	entries, err := fetch(ctx, limit, offset, period)
+	if err != nil {
+		log.Printf("Error getting %s rankings: %v", scope, err)
+		http.Error(w, fmt.Sprintf("Failed to get rankings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	responseJSON, err := json.Marshal(entries)
+	if err != nil {
+		log.Printf("Error encoding %s rankings response: %v", scope, err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if redisClient != nil {
+		// The TTL applies to the whole hash, so it is only set when the hash is first created
+		pipe := redisClient.Client.TxPipeline()
+		pipe.HSet(ctx, cacheKey, cacheField, responseJSON)
+		pipe.ExpireNX(ctx, cacheKey, ws.RankingsCacheTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Error caching %s rankings: %v", scope, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(responseJSON)
+}
+
+// handleGetCollegeRankings handles ranking colleges by the combined XP of their students
+// @Summary      Get college rankings
+// @Description  Rank colleges by total student XP (lifetime for period=all, otherwise XP earned in the period). Includes student count and average XP. Cached for 5 minutes and refreshed when XP changes.
+// @Tags         leaderboard
+// @Produce      json
+// @Param        period  query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
+// @Param        limit   query     int     false  "Maximum number of colleges (default: 50, max: 1000)"
+// @Param        offset  query     int     false  "Number of colleges to skip (default: 0)"
+// @Success      200     {array}   store.CollegeRankEntry  "College rankings"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/leaderboard/college-rankings [get]
+func handleGetCollegeRankings(leaderboardStore store.LeaderboardStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveCachedRankings(w, r, redisClient, "college", func(ctx context.Context, limit, offset int, period string) (interface{}, error) {
+			return leaderboardStore.GetCollegeRankings(ctx, limit, offset, period)
+		})
+	}
+}
+
+// handleGetStateRankings handles ranking states by the combined XP of their students
+// @Summary      Get state rankings
+// @Description  Rank states by total student XP (lifetime for period=all, otherwise XP earned in the period). Includes student count and average XP. Cached for 5 minutes and refreshed when XP changes.
+// @Tags         leaderboard
+// @Produce      json
+// @Param        period  query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
+// @Param        limit   query     int     false  "Maximum number of states (default: 50, max: 1000)"
+// @Param        offset  query     int     false  "Number of states to skip (default: 0)"
+// @Success      200     {array}   store.StateRankEntry  "State rankings"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/leaderboard/state-rankings [get]
+func handleGetStateRankings(leaderboardStore store.LeaderboardStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveCachedRankings(w, r, redisClient, "state", func(ctx context.Context, limit, offset int, period string) (interface{}, error) {
+			return leaderboardStore.GetStateRankings(ctx, limit, offset, period)
+		})
+	}
+}
+
+// handleGetMyRank returns the current user's rank on the pan-India, state and college leaderboards
+// @Summary      Get my leaderboard ranks
+// @Description  Get the authenticated user's all-time rank in all three leaderboard scopes in one call. state_rank and college_rank are null when the user has no state or college.
+// @Tags         leaderboard
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.UserRanks  "User's ranks"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/leaderboard/my-rank [get]
+func handleGetMyRank(leaderboardStore store.LeaderboardStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		ranks, err := leaderboardStore.GetUserRanks(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user ranks: %v", err)
+			http.Error(w, "Failed to get ranks", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(ranks); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Default and maximum number of ranks shown on each side of the user by the around-me leaderboard
+const (
+	defaultAroundMeRadius = 5
+	maxAroundMeRadius     = 20
+)
+
+// LeaderboardAroundMeResponse is the slice of a leaderboard around the current user
+type LeaderboardAroundMeResponse struct {
+	Entries      []store.LeaderboardEntry `json:"entries"`
+	Type         string                   `json:"type"`               // "pan-india", "state", "college"
+	ScopeID      string                   `json:"scope_id,omitempty"` // state_id or college_id
+	Period       string                   `json:"period"`
+	UserPosition int                      `json:"user_position"` // index of the current user in entries
+}
+
+// handleGetLeaderboardAroundMe returns the leaderboard entries ranked just above and below the current user
+// @Summary      Get leaderboard around me
+// @Description  Get the users ranked within radius positions above and below the authenticated user, in the user's own state or college for those types. Fewer entries are returned near the top or bottom of the leaderboard. user_position is the index of the user in entries.
+// @Tags         leaderboard
+// @Produce      json
+// @Security     BearerAuth
+// @Param        type    query     string  false  "Leaderboard: pan-india, state, college (default: pan-india)"
+// @Param        period  query     string  false  "Time period: all, daily, weekly, monthly (default: all)"
+// @Param        radius  query     int     false  "Positions on each side of the user (default: 5, max: 20)"
+// @Success      200     {object}  LeaderboardAroundMeResponse  "Leaderboard entries around the user"
+// @Failure      400     {string}  string  "Invalid leaderboard type"
+// @Failure      401     {string}  string  "Unauthorized"
+// @Failure      404     {string}  string  "User not on this leaderboard"
+// @Failure      500     {string}  string  "Internal server error"
+// @Router       /api/leaderboard/around-me [get]
+func handleGetLeaderboardAroundMe(leaderboardStore store.LeaderboardStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leaderboardType := r.URL.Query().Get("type")
+		if leaderboardType == "" {
+			leaderboardType = "pan-india"
+		}
+
+		period := r.URL.Query().Get("period")
+		if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
+			period = "all"
+		}
+
+		radius := defaultAroundMeRadius
+		if radiusStr := r.URL.Query().Get("radius"); radiusStr != "" {
+			if rd, err := strconv.Atoi(radiusStr); err == nil && rd > 0 {
+				radius = rd
+			}
+		}
+		if radius > maxAroundMeRadius {
+			radius = maxAroundMeRadius
+		}
+
+		var entries []store.LeaderboardEntry
+		var userPosition int
+		var err error
+		switch leaderboardType {
+		case "pan-india":
+			entries, userPosition, err = leaderboardStore.GetLeaderboardAroundUser(ctx, userID, radius, period)
+		case "state":
+			entries, userPosition, err = leaderboardStore.GetStateLeaderboardAroundUser(ctx, userID, radius, period)
+		case "college":
+			entries, userPosition, err = leaderboardStore.GetCollegeLeaderboardAroundUser(ctx, userID, radius, period)
+		default:
+			http.Error(w, "type must be pan-india, state or college", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			if err.Error() == "user not on leaderboard" {
+				http.Error(w, "You are not on this leaderboard", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting %s leaderboard around user %s: %v", leaderboardType, userID, err)
+			http.Error(w, "Failed to get leaderboard", http.StatusInternalServerError)
+			return
+		}
+
+		response := LeaderboardAroundMeResponse{
+			Entries:      entries,
+			Type:         leaderboardType,
+			Period:       period,
+			UserPosition: userPosition,
+		}
+		switch leaderboardType {
+		case "state":
+			response.ScopeID = entries[userPosition].StateID
+		case "college":
+			response.ScopeID = entries[userPosition].CollegeID
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+const (
+	defaultAnomalyThreshold = 10000 // XP earned in the window above which a user is flagged
+	defaultAnomalyHours     = 24
+	maxAnomalyHours         = 24 * 30
+)
+
+// XPAnomaliesResponse lists users flagged for suspicious XP spikes
+type XPAnomaliesResponse struct {
+	Threshold int               `json:"threshold"`
+	Hours     int               `json:"hours"`
+	Anomalies []store.XPAnomaly `json:"anomalies"`
+}
+
+// handleGetXPAnomalies handles listing users with suspicious XP spikes (admin)
+// @Summary      Get XP anomalies
+// @Description  List users who earned more than threshold XP in the last hours, with XP broken down by source (task_approval, user_add, ...) to help spot XP farming. Sorted by total_xp_in_window descending. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        threshold  query     int  false  "Minimum XP earned in the window (default 10000)"
+// @Param        hours      query     int  false  "Window size in hours (default 24, max 720)"
+// @Success      200        {object}  XPAnomaliesResponse  "Flagged users"
+// @Failure      400        {string}  string  "Bad request - invalid threshold or hours"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /admin/leaderboard/anomalies [get]
+func handleGetXPAnomalies(xpStore store.XPStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		threshold := defaultAnomalyThreshold
+		if thresholdStr := r.URL.Query().Get("threshold"); thresholdStr != "" {
+			t, err := strconv.Atoi(thresholdStr)
+			if err != nil || t < 0 {
+				http.Error(w, "threshold must be a non-negative integer", http.StatusBadRequest)
+				return
+			}
+			threshold = t
+		}
+
+		hours := defaultAnomalyHours
+		if hoursStr := r.URL.Query().Get("hours"); hoursStr != "" {
+			h, err := strconv.Atoi(hoursStr)
+			if err != nil || h <= 0 || h > maxAnomalyHours {
+				http.Error(w, fmt.Sprintf("hours must be between 1 and %d", maxAnomalyHours), http.StatusBadRequest)
+				return
+			}
+			hours = h
+		}
+
+		anomalies, err := xpStore.GetXPAnomalies(ctx, threshold, time.Duration(hours)*time.Hour)
+		if err != nil {
+			log.Printf("Error getting XP anomalies: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get XP anomalies: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := XPAnomaliesResponse{
+			Threshold: threshold,
+			Hours:     hours,
+			Anomalies: anomalies,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding XP anomalies response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
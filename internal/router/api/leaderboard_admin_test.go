@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+)
+
+func TestHandleGetXPAnomalies(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		err           error
+		wantStatus    int
+		wantThreshold int
+		wantWindow    time.Duration
+	}{
+		{name: "defaults", query: "", wantStatus: http.StatusOK, wantThreshold: defaultAnomalyThreshold, wantWindow: defaultAnomalyHours * time.Hour},
+		{name: "custom threshold and hours", query: "?threshold=500&hours=48", wantStatus: http.StatusOK, wantThreshold: 500, wantWindow: 48 * time.Hour},
+		{name: "zero threshold", query: "?threshold=0", wantStatus: http.StatusOK, wantThreshold: 0, wantWindow: defaultAnomalyHours * time.Hour},
+		{name: "longest window", query: "?hours=720", wantStatus: http.StatusOK, wantThreshold: defaultAnomalyThreshold, wantWindow: maxAnomalyHours * time.Hour},
+		{name: "negative threshold", query: "?threshold=-1", wantStatus: http.StatusBadRequest},
+		{name: "non-numeric threshold", query: "?threshold=lots", wantStatus: http.StatusBadRequest},
+		{name: "zero hours", query: "?hours=0", wantStatus: http.StatusBadRequest},
+		{name: "window too long", query: "?hours=721", wantStatus: http.StatusBadRequest},
+		{name: "store error", query: "", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantThreshold: defaultAnomalyThreshold, wantWindow: defaultAnomalyHours * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xpStore := &mock.XPStore{
+				GetXPAnomaliesFunc: func(ctx context.Context, threshold int, window time.Duration) ([]store.XPAnomaly, error) {
+					if threshold != tt.wantThreshold || window != tt.wantWindow {
+						t.Errorf("GetXPAnomalies(%d, %v), want (%d, %v)", threshold, window, tt.wantThreshold, tt.wantWindow)
+					}
+					return nil, tt.err
+				},
+			}
+
+			serve(t, handleGetXPAnomalies(xpStore), newTestRequest(http.MethodGet, "/admin/leaderboard/anomalies"+tt.query, ""), tt.wantStatus)
+		})
+	}
+}
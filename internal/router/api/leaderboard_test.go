@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetLeaderboardAroundMe(t *testing.T) {
+	entries := []store.LeaderboardEntry{
+		{Rank: 1, UserID: "above", StateID: testutil.TestStateID, CollegeID: testutil.TestCollegeID},
+		{Rank: 2, UserID: testutil.TestUserID, StateID: testutil.TestStateID, CollegeID: testutil.TestCollegeID},
+	}
+	tests := []struct {
+		name        string
+		userID      string
+		query       string
+		err         error
+		wantStatus  int
+		wantCall    string
+		wantRadius  int
+		wantPeriod  string
+		wantScopeID string
+	}{
+		{name: "defaults", userID: testutil.TestUserID, wantStatus: http.StatusOK, wantCall: "pan-india", wantRadius: defaultAroundMeRadius, wantPeriod: "all"},
+		{name: "state board", userID: testutil.TestUserID, query: "?type=state&period=weekly&radius=3", wantStatus: http.StatusOK, wantCall: "state", wantRadius: 3, wantPeriod: "weekly", wantScopeID: testutil.TestStateID},
+		{name: "college board", userID: testutil.TestUserID, query: "?type=college", wantStatus: http.StatusOK, wantCall: "college", wantRadius: defaultAroundMeRadius, wantPeriod: "all", wantScopeID: testutil.TestCollegeID},
+		{name: "radius capped", userID: testutil.TestUserID, query: "?radius=500", wantStatus: http.StatusOK, wantCall: "pan-india", wantRadius: maxAroundMeRadius, wantPeriod: "all"},
+		{name: "invalid radius and period ignored", userID: testutil.TestUserID, query: "?radius=0&period=yearly", wantStatus: http.StatusOK, wantCall: "pan-india", wantRadius: defaultAroundMeRadius, wantPeriod: "all"},
+		{name: "invalid type", userID: testutil.TestUserID, query: "?type=global", wantStatus: http.StatusBadRequest},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "not on board", userID: testutil.TestUserID, err: errors.New("user not on leaderboard"), wantStatus: http.StatusNotFound, wantCall: "pan-india", wantRadius: defaultAroundMeRadius, wantPeriod: "all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called string
+			around := func(board string) func(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error) {
+				return func(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error) {
+					called = board
+					if radius != tt.wantRadius || period != tt.wantPeriod {
+						t.Errorf("radius, period = %d, %q; want %d, %q", radius, period, tt.wantRadius, tt.wantPeriod)
+					}
+					if tt.err != nil {
+						return nil, 0, tt.err
+					}
+					return entries, 1, nil
+				}
+			}
+			leaderboardStore := &mock.LeaderboardStore{
+				GetLeaderboardAroundUserFunc:        around("pan-india"),
+				GetStateLeaderboardAroundUserFunc:   around("state"),
+				GetCollegeLeaderboardAroundUserFunc: around("college"),
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/leaderboard/around-me"+tt.query, ""), tt.userID)
+			w := serve(t, handleGetLeaderboardAroundMe(leaderboardStore), r, tt.wantStatus)
+			if called != tt.wantCall {
+				t.Errorf("board queried = %q, want %q", called, tt.wantCall)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got LeaderboardAroundMeResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.UserPosition != 1 || got.ScopeID != tt.wantScopeID || got.Type != tt.wantCall {
+				t.Errorf("response = %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandleLeaderboardPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantPeriod string
+	}{
+		{name: "default", wantPeriod: "all"},
+		{name: "all", query: "period=all", wantPeriod: "all"},
+		{name: "daily", query: "period=daily", wantPeriod: "daily"},
+		{name: "weekly", query: "period=weekly", wantPeriod: "weekly"},
+		{name: "monthly", query: "period=monthly", wantPeriod: "monthly"},
+		{name: "unknown falls back to all", query: "period=yearly", wantPeriod: "all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var periods []string
+			record := func(period string) ([]store.LeaderboardEntry, error) {
+				periods = append(periods, period)
+				return []store.LeaderboardEntry{}, nil
+			}
+			leaderboardStore := &mock.LeaderboardStore{
+				GetPanIndiaLeaderboardFunc: func(ctx context.Context, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+					return record(period)
+				},
+				GetStateLeaderboardFunc: func(ctx context.Context, stateID string, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+					return record(period)
+				},
+				GetCollegeLeaderboardFunc: func(ctx context.Context, collegeID string, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+					return record(period)
+				},
+			}
+
+			serve(t, handleGetPanIndiaLeaderboard(leaderboardStore), newTestRequest(http.MethodGet, "/api/leaderboard/pan-india?"+tt.query, ""), http.StatusOK)
+			serve(t, handleGetStateLeaderboard(leaderboardStore), newTestRequest(http.MethodGet, "/api/leaderboard/state?state_id="+testutil.TestStateID+"&"+tt.query, ""), http.StatusOK)
+			serve(t, handleGetCollegeLeaderboard(leaderboardStore), newTestRequest(http.MethodGet, "/api/leaderboard/college?college_id="+testutil.TestCollegeID+"&"+tt.query, ""), http.StatusOK)
+			for i, period := range periods {
+				if period != tt.wantPeriod {
+					t.Errorf("call %d period = %q, want %q", i+1, period, tt.wantPeriod)
+				}
+			}
+			if len(periods) != 3 {
+				t.Errorf("store called %d times, want 3", len(periods))
+			}
+		})
+	}
+}
+
+func TestHandleGetMyRank(t *testing.T) {
+	stateID, collegeID := testutil.TestStateID, testutil.TestCollegeID
+	stateRank, collegeRank := 4, 2
+	tests := []struct {
+		name       string
+		userID     string
+		ranks      *store.UserRanks
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "every scope",
+			userID:     testutil.TestUserID,
+			ranks:      &store.UserRanks{XP: 900, PanIndiaRank: 31, StateID: &stateID, StateRank: &stateRank, CollegeID: &collegeID, CollegeRank: &collegeRank},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"xp":900,"pan_india_rank":31,"state_id":"` + stateID + `","state_rank":4,"college_id":"` + collegeID + `","college_rank":2}`,
+		},
+		{
+			name:       "no state or college",
+			userID:     testutil.TestUserID,
+			ranks:      &store.UserRanks{XP: 0, PanIndiaRank: 120},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"xp":0,"pan_india_rank":120,"state_id":null,"state_rank":null,"college_id":null,"college_rank":null}`,
+		},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", userID: testutil.TestUserID, err: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaderboardStore := &mock.LeaderboardStore{
+				GetUserRanksFunc: func(ctx context.Context, userID string) (*store.UserRanks, error) {
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					return tt.ranks, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/leaderboard/my-rank", ""), tt.userID)
+			w := serve(t, handleGetMyRank(leaderboardStore), r, tt.wantStatus)
+			if tt.wantBody != "" {
+				if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+					t.Errorf("body = %s, want %s", got, tt.wantBody)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// UpdateLevelsRequest represents the request body for replacing level thresholds
+type UpdateLevelsRequest struct {
+	Levels []store.LevelThreshold `json:"levels"`
+}
+
+// notifyLevelUp sends a level_up WebSocket notification if an XP award raised the user's level
+func notifyLevelUp(xpLog *store.XPLog) {
+	if xpLog == nil || !xpLog.LeveledUp {
+		return
+	}
+	wsHub := ws.GetNotificationHub()
+	if wsHub == nil {
+		return
+	}
+	if err := ws.SendLevelUpNotification(wsHub, xpLog.UserID, xpLog.NewLevel); err != nil {
+		log.Printf("Error sending level up notification: %v", err)
+	}
+}
+
+// handleGetLevels returns all level thresholds (admin)
+// @Summary      Get level thresholds
+// @Description  Get the XP range for each level (admin only)
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   store.LevelThreshold
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/levels [get]
+func handleGetLevels(levelStore store.LevelStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		levels, err := levelStore.GetLevelThresholds(ctx)
+		if err != nil {
+			log.Printf("Error getting level thresholds: %v", err)
+			http.Error(w, "Failed to get levels", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(levels); err != nil {
+			log.Printf("Error encoding levels: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleUpdateLevels replaces all level thresholds (admin)
+// @Summary      Update level thresholds
+// @Description  Replace all level thresholds and recalculate every user's level (admin only). Levels must be named and consecutive from 1, level 1 must start at 0 XP and min_xp must increase; max_xp is derived.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      UpdateLevelsRequest  true  "New level thresholds"
+// @Success      200      {array}   store.LevelThreshold
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      403      {string}  string  "Forbidden - manage_admins permission required"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/levels [put]
+func handleUpdateLevels(levelStore store.LevelStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req UpdateLevelsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		err := levelStore.ReplaceLevelThresholds(ctx, req.Levels)
+		if err != nil {
+			switch err.Error() {
+			case "at least one level is required",
+				"levels must be consecutive starting at 1",
+				"level 1 must start at 0 XP",
+				"min_xp must increase with each level",
+				"every level needs a name":
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				log.Printf("Error updating level thresholds: %v", err)
+				http.Error(w, "Failed to update levels", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		levels, err := levelStore.GetLevelThresholds(ctx)
+		if err != nil {
+			log.Printf("Error getting level thresholds: %v", err)
+			http.Error(w, "Failed to get levels", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(levels); err != nil {
+			log.Printf("Error encoding levels: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
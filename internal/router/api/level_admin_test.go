@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+)
+
+func TestHandleUpdateLevels(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		replaceErr error
+		getErr     error
+		wantStatus int
+		wantLevels int
+	}{
+		{name: "replaces levels", body: `{"levels":[{"level":1,"name":"Rookie","min_xp":0},{"level":2,"name":"Performer","min_xp":100}]}`, wantStatus: http.StatusOK, wantLevels: 2},
+		{name: "invalid JSON", body: `{"levels":`, wantStatus: http.StatusBadRequest},
+		{name: "no levels", body: `{"levels":[]}`, replaceErr: errors.New("at least one level is required"), wantStatus: http.StatusBadRequest},
+		{name: "gap in levels", body: `{"levels":[{"level":2,"min_xp":0}]}`, replaceErr: errors.New("levels must be consecutive starting at 1"), wantStatus: http.StatusBadRequest},
+		{name: "level 1 above 0 XP", body: `{"levels":[{"level":1,"min_xp":5}]}`, replaceErr: errors.New("level 1 must start at 0 XP"), wantStatus: http.StatusBadRequest},
+		{name: "decreasing min_xp", body: `{"levels":[{"level":1,"min_xp":0},{"level":2,"min_xp":0}]}`, replaceErr: errors.New("min_xp must increase with each level"), wantStatus: http.StatusBadRequest},
+		{name: "unnamed level", body: `{"levels":[{"level":1,"min_xp":0}]}`, replaceErr: errors.New("every level needs a name"), wantStatus: http.StatusBadRequest},
+		{name: "replace fails", body: `{"levels":[{"level":1,"name":"Rookie","min_xp":0}]}`, replaceErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "reload fails", body: `{"levels":[{"level":1,"name":"Rookie","min_xp":0}]}`, getErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []store.LevelThreshold
+			levelStore := &mock.LevelStore{
+				ReplaceLevelThresholdsFunc: func(ctx context.Context, levels []store.LevelThreshold) error {
+					if tt.replaceErr != nil {
+						return tt.replaceErr
+					}
+					saved = levels
+					return nil
+				},
+				GetLevelThresholdsFunc: func(ctx context.Context) ([]store.LevelThreshold, error) {
+					return saved, tt.getErr
+				},
+			}
+
+			serve(t, handleUpdateLevels(levelStore), newTestRequest(http.MethodPut, "/admin/levels", tt.body), tt.wantStatus)
+			if tt.wantStatus == http.StatusOK && len(saved) != tt.wantLevels {
+				t.Errorf("saved %d levels, want %d", len(saved), tt.wantLevels)
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"net/http"
 	"strings"
 
 	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/middleware"
+	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
 // contextKey is a type for context keys
@@ -20,10 +24,17 @@ const (
 	UserEmailKey contextKey = "user_email"
 	// UserRoleKey is the context key for user role
 	UserRoleKey contextKey = "user_role"
+	// AdminIDKey is the context key for the authenticated admin's ID (set by adminAuthMiddleware)
+	AdminIDKey contextKey = "admin_id"
+	// AdminPermissionsKey is the context key for the admin's permission scopes
+	AdminPermissionsKey contextKey = "admin_permissions"
+	// TwoFAVerifiedKey is the context key for whether the token was issued after a TOTP check
+	TwoFAVerifiedKey contextKey = "two_fa_verified"
 )
 
-// JWTAuthMiddleware validates JWT tokens and adds user info to context
-func JWTAuthMiddleware(cfg *env.Config) func(http.Handler) http.Handler {
+// JWTAuthMiddleware validates JWT tokens and adds user info to context.
+// Requests from banned users are rejected with 403 ACCOUNT_BANNED.
+func JWTAuthMiddleware(postgres *db.Postgres, cfg *env.Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get token from Authorization header
@@ -50,10 +61,38 @@ func JWTAuthMiddleware(cfg *env.Config) func(http.Handler) http.Handler {
 				return
 			}
 
+			middleware.SetAccessLogUserID(r.Context(), claims.UserID)
+
+			// Pending 2FA tokens are only good for POST /admin/auth/2fa/verify
+			if claims.Role == auth.RolePending2FA {
+				http.Error(w, "Two-factor verification required", http.StatusUnauthorized)
+				return
+			}
+
+			// Reject soft-banned users (admin tokens are not user accounts)
+			if claims.Role != auth.RoleAdmin && postgres != nil {
+				banned, err := store.NewUserStore(postgres).IsUserBanned(r.Context(), claims.UserID)
+				if err != nil {
+					log.Printf("Error checking ban status for user %s: %v", claims.UserID, err)
+					http.Error(w, "Failed to verify account status", http.StatusInternalServerError)
+					return
+				}
+				if banned {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					_ = json.NewEncoder(w).Encode(map[string]string{
+						"code":    "ACCOUNT_BANNED",
+						"message": "Your account has been suspended",
+					})
+					return
+				}
+			}
+
 			// Add user info to context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, TwoFAVerifiedKey, claims.TwoFAVerified)
 
 			// Call next handler with updated context
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -61,6 +100,22 @@ func JWTAuthMiddleware(cfg *env.Config) func(http.Handler) http.Handler {
 	}
 }
 
+// OptionalJWTAuthMiddleware adds user info to context when a valid Bearer token is sent.
+// Requests without an Authorization header pass through anonymously; an invalid token is rejected.
+func OptionalJWTAuthMiddleware(postgres *db.Postgres, cfg *env.Config) func(http.Handler) http.Handler {
+	required := JWTAuthMiddleware(postgres, cfg)
+	return func(next http.Handler) http.Handler {
+		withAuth := required(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withAuth.ServeHTTP(w, r)
+		})
+	}
+}
+
 // GetUserIDFromContext extracts user ID from context
 func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserIDKey).(string)
@@ -78,3 +133,15 @@ func GetUserRoleFromContext(ctx context.Context) (string, bool) {
 	role, ok := ctx.Value(UserRoleKey).(string)
 	return role, ok
 }
+
+// GetAdminIDFromContext extracts the authenticated admin's ID from context
+func GetAdminIDFromContext(ctx context.Context) (string, bool) {
+	adminID, ok := ctx.Value(AdminIDKey).(string)
+	return adminID, ok
+}
+
+// GetAdminPermissionsFromContext extracts admin permissions from context
+func GetAdminPermissionsFromContext(ctx context.Context) ([]string, bool) {
+	permissions, ok := ctx.Value(AdminPermissionsKey).([]string)
+	return permissions, ok
+}
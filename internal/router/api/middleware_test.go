@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	const testSecret = "test-secret"
+	token := func(role, secret string, expiry time.Duration) string {
+		tok, err := auth.GenerateToken(testutil.TestUserID, "test.user@example.com", role, secret, expiry)
+		if err != nil {
+			t.Fatalf("generating token: %v", err)
+		}
+		return tok
+	}
+	tests := []struct {
+		name       string
+		header     string
+		banned     *bool
+		wantStatus int
+	}{
+		{name: "valid student token", header: "Bearer " + token("student", testSecret, time.Hour), banned: new(bool), wantStatus: http.StatusOK},
+		{name: "banned student", header: "Bearer " + token("student", testSecret, time.Hour), banned: func() *bool { b := true; return &b }(), wantStatus: http.StatusForbidden},
+		{name: "admin token skips ban check", header: "Bearer " + token(auth.RoleAdmin, testSecret, time.Hour), wantStatus: http.StatusOK},
+		{name: "pending 2FA token", header: "Bearer " + token(auth.RolePending2FA, testSecret, time.Hour), wantStatus: http.StatusUnauthorized},
+		{name: "missing header", wantStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", header: "Basic abc", wantStatus: http.StatusUnauthorized},
+		{name: "wrong secret", header: "Bearer " + token("student", "other-secret", time.Hour), wantStatus: http.StatusUnauthorized},
+		{name: "expired", header: "Bearer " + token("student", testSecret, -time.Minute), wantStatus: http.StatusUnauthorized},
+		{name: "malformed token", header: "Bearer not.a.jwt", wantStatus: http.StatusUnauthorized},
+		{name: "truncated token", header: "Bearer " + token("student", testSecret, time.Hour)[:40], wantStatus: http.StatusUnauthorized},
+		{name: "empty bearer token", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "extra header parts", header: "Bearer " + token("student", testSecret, time.Hour) + " extra", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.banned != nil {
+				mockDB.ExpectQuery(`SELECT is_banned FROM users`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"is_banned"}, []any{*tt.banned})
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if userID, _ := GetUserIDFromContext(r.Context()); userID != testutil.TestUserID {
+					t.Errorf("user ID in context = %q, want %q", userID, testutil.TestUserID)
+				}
+				if role, _ := GetUserRoleFromContext(r.Context()); role == "" {
+					t.Error("no role in context")
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := newTestRequest(http.MethodGet, "/api/user/me", "")
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			serve(t, JWTAuthMiddleware(postgres, &env.Config{JWTSecret: testSecret})(next), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestOptionalJWTAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{name: "anonymous passes through", wantStatus: http.StatusOK},
+		{name: "invalid token rejected", header: "Bearer nonsense", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			r := newTestRequest(http.MethodGet, "/api/feed", "")
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			serve(t, OptionalJWTAuthMiddleware(nil, &env.Config{JWTSecret: "test-secret"})(next), r, tt.wantStatus)
+		})
+	}
+}
@@ -1,15 +1,297 @@
 package api
 
 import (
+	"encoding/json"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
+// NotificationsResponse represents the notifications list response.
+// Total counts announcements; NotificationTotal and UnreadCount count the user's own notifications.
+type NotificationsResponse struct {
+	Notifications     []store.Notification `json:"notifications"`
+	NotificationTotal int                  `json:"notification_total"`
+	UnreadCount       int                  `json:"unread_count"`
+	Announcements     []store.Announcement `json:"announcements"`
+	Total             int                  `json:"total"`
+	Page              int                  `json:"page"`
+	PageSize          int                  `json:"page_size"`
+}
+
 // handleGetNotifications handles getting user notifications
-func handleGetNotifications(postgres *db.Postgres) http.HandlerFunc {
+// @Summary      Get notifications
+// @Description  Get notifications for the authenticated user, unread first then newest first, together with platform announcements broadcast by admins. Notifications sent while the user was offline are included. page and page_size apply to both lists.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page       query     int  false  "Page number (default: 1)"
+// @Param        page_size  query     int  false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  NotificationsResponse
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/notifications [get]
+func handleGetNotifications(notificationStore store.NotificationStoreInterface, announcementStore store.AnnouncementStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotImplemented)
-		w.Write([]byte("Not implemented"))
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Parse pagination parameters
+		page := 1
+		pageSize := 20
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 && ps <= 100 {
+				pageSize = ps
+			}
+		}
+		offset := (page - 1) * pageSize
+
+		notifications, notificationTotal, unread, err := notificationStore.GetNotifications(ctx, userID, pageSize, offset)
+		if err != nil {
+			log.Printf("Error getting notifications: %v", err)
+			http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+			return
+		}
+
+		announcements, total, err := announcementStore.GetAnnouncements(ctx, pageSize, offset)
+		if err != nil {
+			log.Printf("Error getting announcements: %v", err)
+			http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+			return
+		}
+
+		response := NotificationsResponse{
+			Notifications:     notifications,
+			NotificationTotal: notificationTotal,
+			UnreadCount:       unread,
+			Announcements:     announcements,
+			Total:             total,
+			Page:              page,
+			PageSize:          pageSize,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding notifications response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleMarkNotificationRead marks one of the user's notifications as read
+// @Summary      Mark notification read
+// @Description  Mark one of the authenticated user's notifications as read. Marking an already read notification succeeds.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  map[string]string  "Notification marked read"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "Notification not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/notifications/{id}/read [post]
+func handleMarkNotificationRead(notificationStore store.NotificationStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		notificationID := chi.URLParam(r, "id")
+		if _, err := uuid.Parse(notificationID); err != nil {
+			http.Error(w, "Notification not found", http.StatusNotFound)
+			return
+		}
+
+		if err := notificationStore.MarkRead(ctx, userID, notificationID); err != nil {
+			if err.Error() == "notification not found" {
+				http.Error(w, "Notification not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error marking notification %s read: %v", notificationID, err)
+			http.Error(w, "Failed to mark notification read", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message": "Notification marked read",
+		})
+	}
+}
+
+// handleMarkAllNotificationsRead marks all of the user's notifications as read
+// @Summary      Mark all notifications read
+// @Description  Mark every unread notification of the authenticated user as read. Returns how many were updated.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "Notifications marked read"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/notifications/read-all [post]
+func handleMarkAllNotificationsRead(notificationStore store.NotificationStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		updated, err := notificationStore.MarkAllRead(ctx, userID)
+		if err != nil {
+			log.Printf("Error marking notifications read for user %s: %v", userID, err)
+			http.Error(w, "Failed to mark notifications read", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "Notifications marked read",
+			"updated": updated,
+		})
+	}
+}
+
+// DigestPreferenceRequest toggles the daily notification digest
+type DigestPreferenceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// handleUpdateDigestPreference handles turning the daily email digest on or off
+// @Summary      Update digest preference
+// @Description  Turn the daily email digest on or off. When on, notifications received while offline are summarized in one email each night.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      DigestPreferenceRequest  true  "Digest preference"
+// @Success      200      {object}  map[string]interface{}  "digest_enabled"
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/user/me/notification-preferences/digest [put]
+func handleUpdateDigestPreference(digestStore store.DigestStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req DigestPreferenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Enabled == nil {
+			http.Error(w, "enabled is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := digestStore.SetDigestEnabled(ctx, userID, *req.Enabled); err != nil {
+			log.Printf("Error updating digest preference for user %s: %v", userID, err)
+			http.Error(w, "Failed to update notification preferences", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"digest_enabled": *req.Enabled,
+		}); err != nil {
+			log.Printf("Error encoding digest preference response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// FCMTokenRequest registers the device that receives push notifications
+type FCMTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// handleUpdateFCMToken handles registering the user's device for push notifications
+// @Summary      Register push notification token
+// @Description  Store the Firebase Cloud Messaging registration token of the user's device (Android or iOS). Notifications sent while the user is offline are pushed to this device. Send an empty token to stop push notifications.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      FCMTokenRequest  true  "FCM registration token"
+// @Success      200      {object}  map[string]string  "Token saved"
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/user/me/fcm-token [put]
+func handleUpdateFCMToken(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req FCMTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		req.Token = strings.TrimSpace(req.Token)
+		if len(req.Token) > 4096 {
+			http.Error(w, "token is too long", http.StatusBadRequest)
+			return
+		}
+
+		if err := userStore.UpdateFCMToken(ctx, userID, req.Token); err != nil {
+			log.Printf("Error updating FCM token for user %s: %v", userID, err)
+			http.Error(w, "Failed to save push notification token", http.StatusInternalServerError)
+			return
+		}
+
+		message := "Push notification token saved"
+		if req.Token == "" {
+			message = "Push notification token removed"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]string{
+			"message": message,
+		}); err != nil {
+			log.Printf("Error encoding FCM token response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
 	}
 }
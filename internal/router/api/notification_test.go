@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleMarkNotificationRead(t *testing.T) {
+	const notificationID = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	tests := []struct {
+		name           string
+		userID         string
+		notificationID string
+		err            error
+		wantStatus     int
+		wantCalled     bool
+	}{
+		{name: "marks read", userID: testutil.TestUserID, notificationID: notificationID, wantStatus: http.StatusOK, wantCalled: true},
+		{name: "anonymous", notificationID: notificationID, wantStatus: http.StatusUnauthorized},
+		{name: "malformed ID", userID: testutil.TestUserID, notificationID: "42", wantStatus: http.StatusNotFound},
+		{name: "someone else's notification", userID: testutil.TestUserID, notificationID: notificationID, err: errors.New("notification not found"), wantStatus: http.StatusNotFound, wantCalled: true},
+		{name: "store error", userID: testutil.TestUserID, notificationID: notificationID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			notificationStore := &mock.NotificationStore{
+				MarkReadFunc: func(ctx context.Context, userID, notificationID string) error {
+					called = true
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPut, "/api/notifications/x/read", ""), tt.userID)
+			r = withURLParams(r, "id", tt.notificationID)
+			serve(t, handleMarkNotificationRead(notificationStore), r, tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("MarkRead called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateFCMToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		body        string
+		err         error
+		wantStatus  int
+		wantToken   string
+		wantMessage string
+	}{
+		{name: "saves token", userID: testutil.TestUserID, body: `{"token":" abc123 "}`, wantStatus: http.StatusOK, wantToken: "abc123", wantMessage: "Push notification token saved"},
+		{name: "empty token removes it", userID: testutil.TestUserID, body: `{"token":""}`, wantStatus: http.StatusOK, wantMessage: "Push notification token removed"},
+		{name: "anonymous", body: `{"token":"abc123"}`, wantStatus: http.StatusUnauthorized},
+		{name: "invalid JSON", userID: testutil.TestUserID, body: `[`, wantStatus: http.StatusBadRequest},
+		{name: "token too long", userID: testutil.TestUserID, body: `{"token":"` + strings.Repeat("x", 4097) + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "store error", userID: testutil.TestUserID, body: `{"token":"abc123"}`, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantToken: "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				UpdateFCMTokenFunc: func(ctx context.Context, userID, token string) error {
+					if token != tt.wantToken {
+						t.Errorf("token = %q, want %q", token, tt.wantToken)
+					}
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPut, "/api/user/me/fcm-token", tt.body), tt.userID)
+			w := serve(t, handleUpdateFCMToken(userStore), r, tt.wantStatus)
+			if tt.wantMessage != "" && !strings.Contains(w.Body.String(), tt.wantMessage) {
+				t.Errorf("body = %s, want message %q", w.Body.String(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHandleMarkAllNotificationsRead(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		updated    int64
+		err        error
+		wantStatus int
+	}{
+		{name: "marks unread", userID: testutil.TestUserID, updated: 3, wantStatus: http.StatusOK},
+		{name: "nothing left unread", userID: testutil.TestUserID, wantStatus: http.StatusOK},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationStore := &mock.NotificationStore{
+				MarkAllReadFunc: func(ctx context.Context, userID string) (int64, error) {
+					return tt.updated, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/notifications/read-all", ""), tt.userID)
+			w := serve(t, handleMarkAllNotificationsRead(notificationStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got struct {
+				Updated int64 `json:"updated"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Updated != tt.updated {
+				t.Errorf("updated = %d, want %d", got.Updated, tt.updated)
+			}
+		})
+	}
+}
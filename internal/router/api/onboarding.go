@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// handleGetMyOnboarding returns the authenticated user's onboarding checklist
+// @Summary      Get my onboarding checklist
+// @Description  Completion status of each onboarding step (upload_profile_pic, upload_resume, follow_first_user, complete_first_task, earn_first_badge). Completing every step awards a one-time XP bonus.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.OnboardingProgress  "Onboarding checklist"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/me/onboarding [get]
+func handleGetMyOnboarding(onboardingStore store.OnboardingStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		progress, err := onboardingStore.GetProgress(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting onboarding progress: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get onboarding progress: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(progress); err != nil {
+			log.Printf("Error encoding onboarding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
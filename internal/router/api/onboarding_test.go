@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetMyOnboarding(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		err        error
+		wantStatus int
+	}{
+		{name: "returns progress", userID: testutil.TestUserID, wantStatus: http.StatusOK},
+		{name: "anonymous", userID: "", wantStatus: http.StatusUnauthorized},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onboardingStore := &mock.OnboardingStore{
+				GetProgressFunc: func(ctx context.Context, userID string) (*store.OnboardingProgress, error) {
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.OnboardingProgress{CompletedCount: 2, TotalSteps: 5}, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/onboarding", ""), tt.userID)
+			w := serve(t, handleGetMyOnboarding(onboardingStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got store.OnboardingProgress
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.CompletedCount != 2 || got.TotalSteps != 5 {
+				t.Errorf("progress = %+v, want 2 of 5 steps", got)
+			}
+		})
+	}
+}
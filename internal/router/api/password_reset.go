@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/email"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ResetPasswordRequest is the body of the reset password endpoint
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Forgot password emails can be requested at most forgotPasswordLimit times per forgotPasswordWindow for an address
+const (
+	forgotPasswordLimit  = 3
+	forgotPasswordWindow = time.Hour
+)
+
+// ForgotPasswordRequest is the body of the forgot password endpoint
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// passwordResetLink creates a password reset token for the user valid for ttl and returns the emailed link
+func passwordResetLink(ctx context.Context, postgres *db.Postgres, cfg *env.Config, userID string, ttl time.Duration) (string, error) {
+	token, err := store.NewPasswordResetStore(postgres).CreateToken(ctx, userID, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	resetURL, err := url.Parse(cfg.PasswordResetURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid PASSWORD_RESET_URL: %w", err)
+	}
+	query := resetURL.Query()
+	query.Set("token", token)
+	resetURL.RawQuery = query.Encode()
+	return resetURL.String(), nil
+}
+
+// sendSetPasswordEmail creates a password reset token for the user and emails them the link to choose a password
+func sendSetPasswordEmail(ctx context.Context, postgres *db.Postgres, cfg *env.Config, userID, to, name string) error {
+	emailer := email.GetEmailer()
+	if emailer == nil {
+		return fmt.Errorf("email is not configured")
+	}
+
+	link, err := passwordResetLink(ctx, postgres, cfg, userID, store.PasswordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return email.SendSetPasswordEmail(ctx, emailer, to, name, link)
+}
+
+// queuePasswordResetEmail emails user a 1 hour reset link in the background, so the forgot password
+// response takes the same time whether or not the address has an account. Failures are only logged.
+func queuePasswordResetEmail(postgres *db.Postgres, cfg *env.Config, user *store.User) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), verificationEmailTimeout)
+		defer cancel()
+
+		emailer := email.GetEmailer()
+		if emailer == nil {
+			log.Printf("Error sending password reset email to user %s: email is not configured", user.ID)
+			return
+		}
+		link, err := passwordResetLink(ctx, postgres, cfg, user.ID, store.ForgotPasswordTokenTTL)
+		if err == nil {
+			err = email.SendPasswordResetEmail(ctx, emailer, user.Email, user.Name, link)
+		}
+		if err != nil {
+			log.Printf("Error sending password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+}
+
+// handleForgotPassword emails a password reset link to the account with the given address
+// @Summary      Forgot password
+// @Description  Email a single-use password reset link, valid for 1 hour, to the account with this address. Requesting a new link invalidates earlier ones. The response is the same whether or not an account exists, so it cannot be used to discover registered addresses. Limited to 3 requests per hour per address. No authentication required.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ForgotPasswordRequest  true  "Account email address"
+// @Success      200      {object}  map[string]string  "Reset link sent if the account exists"
+// @Failure      400      {string}  string  "Bad request - missing email"
+// @Failure      429      {string}  string  "Too many requests"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/auth/forgot-password [post]
+func handleForgotPassword(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req ForgotPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		emailAddress := strings.TrimSpace(req.Email)
+		if emailAddress == "" {
+			http.Error(w, "email is required", http.StatusBadRequest)
+			return
+		}
+
+		if redisClient != nil {
+			rateLimitKey := fmt.Sprintf("forgot_password:%s", strings.ToLower(emailAddress))
+			requests, err := redisClient.Client.Incr(ctx, rateLimitKey).Result()
+			if err != nil {
+				log.Printf("Error checking forgot password rate limit: %v", err)
+				http.Error(w, "Failed to send password reset email", http.StatusInternalServerError)
+				return
+			}
+			if requests == 1 {
+				redisClient.Client.Expire(ctx, rateLimitKey, forgotPasswordWindow)
+			}
+			if requests > forgotPasswordLimit {
+				http.Error(w, "Too many password reset emails requested. Try again later.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		userStore := store.NewUserStore(postgres)
+		user, err := userStore.GetUserByEmail(ctx, emailAddress)
+		if err != nil && err.Error() != "user not found" {
+			log.Printf("Error getting user for password reset: %v", err)
+			http.Error(w, "Failed to send password reset email", http.StatusInternalServerError)
+			return
+		}
+		if user != nil {
+			queuePasswordResetEmail(postgres, cfg, user)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message": "If an account exists for this email, a password reset link has been sent",
+		})
+	}
+}
+
+// handleResetPassword sets a new password from an emailed reset link
+// @Summary      Reset password
+// @Description  Set the password of the account the token was issued to, from a forgot password email or an invite for an account created by a bulk import. Tokens are single-use and expire after 1 hour (forgot password) or 7 days (invites). Also confirms the email address. No authentication required.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ResetPasswordRequest  true  "Token from the email and the new password"
+// @Success      200      {object}  map[string]string  "Password updated"
+// @Failure      400      {string}  string  "Bad request - missing fields, weak password, or invalid or expired token"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/auth/reset-password [post]
+func handleResetPassword(resetStore store.PasswordResetStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req ResetPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Token == "" || req.Password == "" {
+			http.Error(w, "token and password are required", http.StatusBadRequest)
+			return
+		}
+		if err := auth.ValidatePasswordStrength(req.Password); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := resetStore.ResetPassword(ctx, req.Token, req.Password); err != nil {
+			if err.Error() == "invalid token" {
+				http.Error(w, "Invalid or expired password reset token", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error resetting password: %v", err)
+			http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"message": "Password updated",
+		})
+	}
+}
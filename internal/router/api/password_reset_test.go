@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/email"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleResetPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "resets", body: `{"token":"abc","password":"N3w!password"}`, wantStatus: http.StatusOK, wantCalled: true},
+		{name: "missing token", body: `{"password":"N3w!password"}`, wantStatus: http.StatusBadRequest},
+		{name: "weak password", body: `{"token":"abc","password":"short"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "invalid or expired token", body: `{"token":"abc","password":"N3w!password"}`, err: errors.New("invalid token"), wantStatus: http.StatusBadRequest, wantCalled: true},
+		{name: "store error", body: `{"token":"abc","password":"N3w!password"}`, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			resetStore := &mock.PasswordResetStore{
+				ResetPasswordFunc: func(ctx context.Context, token, newPassword string) (string, error) {
+					called = true
+					return testutil.TestUserID, tt.err
+				},
+			}
+
+			serve(t, handleResetPassword(resetStore), newTestRequest(http.MethodPost, "/api/auth/reset-password", tt.body), tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("ResetPassword called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+// capturingEmailer hands each email body it is asked to send to sent
+type capturingEmailer struct {
+	sent chan string
+}
+
+func (e *capturingEmailer) Send(ctx context.Context, to, subject, htmlBody string) error {
+	e.sent <- htmlBody
+	return nil
+}
+
+func TestPasswordResetRoundTrip(t *testing.T) {
+	emailer := &capturingEmailer{sent: make(chan string, 1)}
+	previous := email.GetEmailer()
+	email.SetEmailer(emailer)
+	t.Cleanup(func() { email.SetEmailer(previous) })
+
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	cfg := &env.Config{PasswordResetURL: "https://app.example.com/reset-password"}
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+
+	// Forgot password: look the user up, then replace their tokens in the background
+	mockDB.ExpectQuery(`WHERE u.email = \$1`).
+		WithArgs("test.user@example.com").
+		WillReturnRows(userColumns, []any{testutil.TestUserID, "Test User", "test.user@example.com", nil, testutil.TestStateID, testutil.TestCollegeID, "student", int64(0), int64(1), int64(0), nil, "", "", "public", "ABC123", nil, nil, testutil.TestTime, "", ""})
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`DELETE FROM password_reset_tokens WHERE user_id = \$1`).WithArgs(testutil.TestUserID).WillReturnResult(0)
+	mockDB.ExpectExec(`INSERT INTO password_reset_tokens`).WithArgs(testutil.TestUserID, testutil.AnyArg(), testutil.AnyArg()).WillReturnResult(1)
+	mockDB.ExpectCommit()
+
+	serve(t, handleForgotPassword(postgres, nil, cfg), newTestRequest(http.MethodPost, "/api/auth/forgot-password", `{"email":"test.user@example.com"}`), http.StatusOK)
+
+	var body string
+	select {
+	case body = <-emailer.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no password reset email was sent")
+	}
+	match := regexp.MustCompile(`https://app\.example\.com/reset-password\?token=([0-9a-f]{64})`).FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("email has no reset link: %s", body)
+	}
+	token := match[1]
+	sum := sha256.Sum256([]byte(token))
+	tokenHash := hex.EncodeToString(sum[:])
+
+	resetStore := store.NewPasswordResetStore(postgres)
+	resetBody := `{"token":"` + token + `","password":"N3w!password"}`
+
+	// The emailed token sets the password and is consumed
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`DELETE FROM password_reset_tokens\s+WHERE token_hash = \$1 AND expires_at > NOW\(\)\s+RETURNING user_id`).
+		WithArgs(tokenHash).
+		WillReturnRows([]string{"user_id"}, []any{testutil.TestUserID})
+	mockDB.ExpectExec(`UPDATE users\s+SET password_hash = \$1`).WithArgs(testutil.AnyArg(), testutil.TestUserID).WillReturnResult(1)
+	mockDB.ExpectCommit()
+	serve(t, handleResetPassword(resetStore), newTestRequest(http.MethodPost, "/api/auth/reset-password", resetBody), http.StatusOK)
+
+	// Reusing it finds nothing to consume, the same as an expired token
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`DELETE FROM password_reset_tokens\s+WHERE token_hash = \$1 AND expires_at > NOW\(\)`).
+		WithArgs(tokenHash).
+		WillReturnRows([]string{"user_id"})
+	mockDB.ExpectRollback()
+	serve(t, handleResetPassword(resetStore), newTestRequest(http.MethodPost, "/api/auth/reset-password", resetBody), http.StatusBadRequest)
+}
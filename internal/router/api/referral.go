@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ReferralStatsResponse is the authenticated user's referral stats with a page of referred users
+type ReferralStatsResponse struct {
+	*store.ReferralStats
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+// awardReferralBonus gives the referrer of referredUserID the configured referral bonus and notifies them.
+// Call it once the referred user's first submission is approved; the store awards the bonus at most
+// once per referral. Failures are only logged so they never fail the approval.
+func awardReferralBonus(ctx context.Context, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, referredUserID string) {
+	if cfg.ReferralBonusXP <= 0 {
+		return
+	}
+
+	referralStore := store.NewReferralStore(postgres)
+	xpLog, err := referralStore.AwardReferralBonus(ctx, referredUserID, cfg.ReferralBonusXP)
+	if err != nil {
+		log.Printf("Error awarding referral bonus for user %s: %v", referredUserID, err)
+		return
+	}
+	if xpLog == nil {
+		return
+	}
+	log.Printf("Awarded %d XP referral bonus to user %s for referring user %s", xpLog.XP, xpLog.UserID, referredUserID)
+
+	broadcastXPChange(ctx, postgres, redisClient, xpLog.UserID)
+	notifyLevelUp(xpLog)
+
+	// The daily referral cap may have trimmed the award to nothing
+	if xpLog.XP <= 0 {
+		return
+	}
+	wsHub := ws.GetNotificationHub()
+	if wsHub == nil {
+		return
+	}
+	referredUser, err := store.NewUserStore(postgres).GetUserByID(ctx, referredUserID)
+	if err != nil {
+		log.Printf("Error getting referred user %s: %v", referredUserID, err)
+		return
+	}
+	if err := ws.SendReferralBonusNotification(wsHub, xpLog.UserID, referredUserID, referredUser.Name, xpLog.XP); err != nil {
+		log.Printf("Error sending referral bonus notification: %v", err)
+	}
+}
+
+// handleGetMyReferrals returns the authenticated user's referral statistics and referred users
+// @Summary      Get my referrals
+// @Description  Get referral stats (total referrals, referrals who completed a task, XP earned from referrals) and a paginated list of referred users, newest first.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page       query     int  false  "Page number (default 1)"
+// @Param        page_size  query     int  false  "Page size (default 50, max 100)"
+// @Success      200        {object}  ReferralStatsResponse  "Referral stats"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/me/referrals [get]
+func handleGetMyReferrals(referralStore store.ReferralStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		page, pageSize := 1, 50
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
+			}
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+		offset := (page - 1) * pageSize
+
+		stats, err := referralStore.GetReferralStats(ctx, userID, pageSize, offset)
+		if err != nil {
+			log.Printf("Error getting referral stats: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get referrals: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := ReferralStatsResponse{
+			ReferralStats: stats,
+			Page:          page,
+			PageSize:      pageSize,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding referrals response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetUserReferrals returns only the referral count of another user
+// @Summary      Get user referral count
+// @Description  Get how many users signed up with a user's referral code. Referred users are not listed.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  map[string]interface{}  "user_id, total_referrals"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/{id}/referrals [get]
+func handleGetUserReferrals(userStore store.UserStoreInterface, referralStore store.ReferralStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := userStore.GetUserByID(ctx, userID); err != nil {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+
+		count, err := referralStore.GetReferralCount(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting referral count: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get referrals: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := map[string]interface{}{
+			"user_id":         userID,
+			"total_referrals": count,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+// handleGetMyReferralCode returns the authenticated user's referral code
+// @Summary      Get my referral code
+// @Description  Get the referral code to share with friends. New users can enter it at registration.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]interface{}  "referral_code, total_referrals"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Router       /api/user/me/referral-code [get]
+func handleGetMyReferralCode(userStore store.UserStoreInterface, referralStore store.ReferralStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+
+		count, err := referralStore.GetReferralCount(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting referral count: %v", err)
+		}
+
+		response := map[string]interface{}{
+			"referral_code":   user.ReferralCode,
+			"total_referrals": count,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetMyReferralCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		userErr    error
+		countErr   error
+		wantStatus int
+		wantCount  float64
+	}{
+		{name: "code and count", userID: testutil.TestUserID, wantStatus: http.StatusOK, wantCount: 3},
+		{name: "count failure still returns code", userID: testutil.TestUserID, countErr: errors.New("connection refused"), wantStatus: http.StatusOK},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", userID: testutil.TestUserID, userErr: errors.New("user not found"), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					if tt.userErr != nil {
+						return nil, tt.userErr
+					}
+					return testutil.NewTestUser(), nil
+				},
+			}
+			referralStore := &mock.ReferralStore{
+				GetReferralCountFunc: func(ctx context.Context, userID string) (int, error) {
+					if tt.countErr != nil {
+						return 0, tt.countErr
+					}
+					return 3, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/referral-code", ""), tt.userID)
+			w := serve(t, handleGetMyReferralCode(userStore, referralStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got["referral_code"] != "TESTCODE" || got["total_referrals"] != tt.wantCount {
+				t.Errorf("response = %v", got)
+			}
+		})
+	}
+}
+
+// Approving more of the referred user's submissions never pays the referrer again
+func TestAwardReferralBonusOnce(t *testing.T) {
+	const referrerID = "abcdabcd-abcd-abcd-abcd-abcdabcdabcd"
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	cfg := &env.Config{ReferralBonusXP: 75}
+	expectClaim := func(referrer string) {
+		var rows [][]any
+		if referrer != "" {
+			rows = append(rows, []any{referrer})
+		}
+		mockDB.ExpectBegin()
+		mockDB.ExpectQuery(`UPDATE user_referrals\s+SET bonus_awarded_at = CURRENT_TIMESTAMP\s+WHERE referred_id = \$1 AND bonus_awarded_at IS NULL`).
+			WithArgs(testutil.TestUserID).
+			WillReturnRows([]string{"referrer_id"}, rows...)
+	}
+
+	// First approval: the configured bonus goes to the referrer, whose leaderboards are refreshed
+	expectClaim(referrerID)
+	mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+		WithArgs(75, referrerID).
+		WillReturnRows([]string{"xp", "level"}, []any{int64(175), int64(1)})
+	mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+		WithArgs(testutil.AnyArg(), referrerID, "referral", testutil.TestUserID, nil, 75).
+		WillReturnRows([]string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"},
+			[]any{"log-1", referrerID, "referral", testutil.TestUserID, nil, int64(75), testutil.TestTime})
+	mockDB.ExpectQuery(`SELECT level FROM levels`).
+		WithArgs(175).
+		WillReturnRows([]string{"level"}, []any{int64(1)})
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(`FROM badges b`).
+		WithArgs(175, 1, referrerID).
+		WillReturnRows([]string{"id", "xp", "required_level"})
+	mockDB.ExpectQuery(`FROM users u\s+LEFT JOIN states s`).
+		WithArgs(referrerID).
+		WillReturnRows(userColumns, []any{referrerID, "Referrer", "referrer@example.com", nil, nil, nil, "student", int64(175), int64(1), int64(0), nil, "", "", "public", "REF123", nil, nil, testutil.TestTime, "", ""})
+	awardReferralBonus(context.Background(), postgres, nil, cfg, testutil.TestUserID)
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatalf("first approval: %v", err)
+	}
+
+	// Later approvals find the referral already claimed and award nothing
+	for i := 0; i < 2; i++ {
+		expectClaim("")
+		mockDB.ExpectRollback()
+		awardReferralBonus(context.Background(), postgres, nil, cfg, testutil.TestUserID)
+	}
+	if err := mockDB.ExpectationsWereMet(); err != nil {
+		t.Fatalf("later approvals: %v", err)
+	}
+
+	// A bonus of 0 turns it off without touching the database
+	awardReferralBonus(context.Background(), postgres, nil, &env.Config{ReferralBonusXP: 0}, testutil.TestUserID)
+}
@@ -5,142 +5,318 @@ import (
 
 	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/middleware"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/worker"
 )
 
 // SetupAPIRoutes sets up all API routes
-func SetupAPIRoutes(r chi.Router, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) {
+func SetupAPIRoutes(r chi.Router, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, moderator moderation.ImageModerator) {
+	// Stores only wrap the connection pool, so the handlers share one of each
+	activityLogStore := store.NewActivityLogStore(postgres)
+	announcementStore := store.NewAnnouncementStore(postgres)
+	badgeStore := store.NewBadgeStore(postgres)
+	chatStore := store.NewChatStore(postgres)
+	coinStore := store.NewCoinStore(postgres)
+	collegeStore := store.NewCollegeStore(postgres)
+	digestStore := store.NewDigestStore(postgres)
+	emailVerificationStore := store.NewEmailVerificationStore(postgres)
+	featureFlagStore := store.NewFeatureFlagStore(postgres)
+	feedStore := store.NewFeedStore(postgres)
+	leaderboardStore := store.NewLeaderboardStore(postgres)
+	notificationStore := store.NewNotificationStore(postgres)
+	onboardingStore := store.NewOnboardingStore(postgres)
+	passwordResetStore := store.NewPasswordResetStore(postgres)
+	referralStore := store.NewReferralStore(postgres)
+	stateStore := store.NewStateStore(postgres)
+	statsStore := store.NewStatsStore(postgres)
+	streakStore := store.NewStreakStore(postgres)
+	submissionStore := store.NewSubmissionStore(postgres)
+	taskStore := store.NewTaskStore(postgres)
+	userStore := store.NewUserStore(postgres)
+	xpCodeStore := store.NewXPCodeStore(postgres)
+	xpStore := store.NewXPStore(postgres)
+
 	// Auth routes
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/login", handleLogin(postgres, cfg))
+		r.Post("/login", handleLogin(userStore, cfg))
 		r.Post("/register", handleRegister(postgres, cfg))
-		r.Post("/refresh", handleRefresh(postgres, cfg))
+		r.Post("/refresh", handleRefresh(userStore, cfg))
+		r.Get("/verify-email", handleVerifyEmail(emailVerificationStore))
+		r.Post("/forgot-password", handleForgotPassword(postgres, redisClient, cfg))
+		r.Post("/reset-password", handleResetPassword(passwordResetStore))
+		r.With(JWTAuthMiddleware(postgres, cfg)).Post("/resend-verification", handleResendVerification(postgres, redisClient, cfg))
 	})
 
+	// Completed tasks on a user's profile (public)
+	r.Get("/user/{id}/tasks/completed", handleGetUserCompletedTasks(feedStore))
+
+	// Activity timeline. The /me route is registered here rather than inside the /user
+	// group so it takes precedence over the public /user/{id}/activity-log route.
+	r.With(JWTAuthMiddleware(postgres, cfg)).Get("/user/me/activity-log", handleGetMyActivityLog(activityLogStore))
+	r.Get("/user/{id}/activity-log", handleGetUserActivityLog(userStore, activityLogStore))
+	r.With(OptionalJWTAuthMiddleware(postgres, cfg)).Get("/user/{id}/resume/download", handleDownloadResume(userStore, cfg))
+
+	// User search (protected with JWT)
+	r.With(JWTAuthMiddleware(postgres, cfg)).Get("/users/search", handleSearchUsers(userStore, redisClient))
+
 	// User routes (protected with JWT)
 	r.Route("/user", func(r chi.Router) {
-		r.Use(JWTAuthMiddleware(cfg))
-		r.Get("/me", handleGetMe(postgres))
+		r.Use(JWTAuthMiddleware(postgres, cfg))
+		r.Get("/me", handleGetMe(userStore))
+		r.Patch("/me", handleUpdateMe(userStore))
+		// Coins exchange
+		r.Get("/me/coins/exchange-rate", handleGetCoinExchangeRate(cfg))
+		r.Post("/me/coins/exchange", handleExchangeCoins(coinStore, userStore, redisClient, cfg))
+		r.Get("/me/coins/history", handleGetMyCoinHistory(coinStore))
+		r.Get("/me/xp-history", handleGetXPHistory(xpStore))
+		r.Get("/me/xp-summary", handleGetXPSummary(xpStore))
+		r.Get("/me/stats", handleGetMyStats(statsStore, redisClient))
+		// Referrals
+		r.Get("/me/referrals", handleGetMyReferrals(referralStore))
+		r.Get("/me/referral-code", handleGetMyReferralCode(userStore, referralStore))
+		// Onboarding checklist
+		r.Get("/me/onboarding", handleGetMyOnboarding(onboardingStore))
+		// Notification preferences
+		r.Put("/me/notification-preferences/digest", handleUpdateDigestPreference(digestStore))
+		r.Put("/me/fcm-token", handleUpdateFCMToken(userStore))
+		// Users the current user has blocked
+		r.Get("/me/blocked", handleGetBlockedUsers(userStore))
 		r.Get("/{id}", handleGetUser(postgres))
-		r.Get("/{id}/followers", handleGetFollowers(postgres))
-		r.Get("/{id}/following", handleGetFollowing(postgres))
+		r.Get("/{id}/referrals", handleGetUserReferrals(userStore, referralStore))
+		r.Get("/{id}/followers", handleGetFollowers(userStore))
+		r.Get("/{id}/following", handleGetFollowing(userStore))
+		r.Get("/{id}/mutual-follows", handleGetMutualFollows(userStore))
+		r.Get("/{id}/following-check", handleGetFollowingCheck(userStore))
+		r.Get("/{id}/is-following", handleGetFollowingCheck(userStore))
 		r.Post("/{id}/follow", handleFollow(postgres))
 		r.Post("/{id}/unfollow", handleUnfollow(postgres))
+		r.Post("/{id}/block", handleBlockUser(userStore))
+		r.Post("/{id}/unblock", handleUnblockUser(userStore))
 		// Resume routes
-		r.Post("/resume", handleUploadResume(postgres, cfg))
-		r.Put("/resume", handleUpdateResume(postgres, cfg))
+		r.Post("/resume", handleUploadResume(userStore, onboardingStore, cfg))
+		r.Put("/resume", handleUpdateResume(userStore, cfg))
+		r.Patch("/resume/visibility", handleUpdateResumeVisibility(userStore))
 		// Profile picture routes
-		r.Post("/profile-pic", handleUploadProfilePic(postgres, cfg))
-		r.Put("/profile-pic", handleUpdateProfilePic(postgres, cfg))
+		r.Post("/profile-pic", handleUploadProfilePic(userStore, onboardingStore, cfg))
+		r.Put("/profile-pic", handleUpdateProfilePic(userStore, cfg))
 		// Badge routes
-		r.Get("/badges", handleGetMyBadges(postgres))
+		r.Get("/badges", handleGetMyBadges(badgeStore))
 		// Task history
 		r.Get("/tasks/history", handleGetMyTaskHistory(postgres))
 		// Streak routes (daily check-in counts toward streak)
-		r.Post("/streak/check-in", handleStreakCheckIn(postgres))
-		r.Post("/streak/redeem", handleRedeemStreak(postgres))
+		r.Get("/streak", handleGetStreak(streakStore))
+		r.Post("/streak/check-in", handleStreakCheckIn(featureFlagStore, streakStore, redisClient))
+		r.Post("/streak/redeem", handleRedeemStreak(streakStore, userStore, badgeStore, redisClient))
+		r.Post("/streak/buy-freeze", handleBuyStreakFreeze(streakStore, coinStore))
 		// Add XP to own account (user only, not admin)
-		r.Post("/xp", handleAddXPForUser(postgres, redisClient))
+		r.Post("/xp", handleAddXPForUser(xpCodeStore, xpStore, userStore, redisClient, cfg))
 	})
 
 	// Task routes (protected with JWT)
 	r.Route("/tasks", func(r chi.Router) {
-		r.Use(JWTAuthMiddleware(cfg))
-		r.Get("/", handleGetTasks(postgres))
-		r.Post("/{id}/submit", handleSubmitTask(postgres, cfg))
+		r.Use(JWTAuthMiddleware(postgres, cfg))
+		r.Get("/", handleGetTasks(taskStore))
+		r.Get("/flash", handleGetFlashTasks(taskStore))
+		r.Get("/{id}/history", handleGetTaskHistory(taskStore))
+		r.Post("/{id}/react", handleReactToTask(taskStore))
+		r.Get("/{id}/reactions", handleGetTaskReactions(taskStore))
+		r.Get("/{id}/certificate", handleGetTaskCertificate(taskStore, submissionStore, userStore, cfg))
+		r.Post("/{id}/submit", handleSubmitTask(postgres, redisClient, cfg, moderator))
 	})
 
 	// Feed routes
 	r.Route("/feed", func(r chi.Router) {
-		r.Get("/", handleGetFeed(postgres, cfg))             // Public, but can use JWT for state/college filtering
-		r.Get("/user/{userId}", handleGetUserFeed(postgres)) // Public
+		r.With(middleware.ETag).Get("/", handleGetFeed(feedStore, cfg)) // Public, but can use JWT for state/college filtering
+		r.Get("/user/{userId}", handleGetUserFeed(feedStore))           // Public
+		r.Get("/{feedId}/comments", handleGetFeedComments(feedStore))   // Public
 		// Protected routes for reactions and comments
 		r.Group(func(r chi.Router) {
-			r.Use(JWTAuthMiddleware(cfg))
-			r.Post("/{feedId}/react", handleReactToFeed(postgres, cfg))
+			r.Use(JWTAuthMiddleware(postgres, cfg))
+			r.Post("/{feedId}/react", handleReactToFeed(feedStore, cfg))
 			r.Post("/{feedId}/comment", handleCommentOnFeed(postgres, cfg))
+			r.Delete("/{feedId}", handleDeleteFeedEntry(feedStore))
 		})
 	})
 
 	// Leaderboard routes
 	r.Route("/leaderboard", func(r chi.Router) {
-		// Pan-India: weekly and monthly first (more specific)
-		r.Get("/pan-india/weekly", handleGetPanIndiaLeaderboardWithPeriod(postgres, "weekly"))
-		r.Get("/pan-india/monthly", handleGetPanIndiaLeaderboardWithPeriod(postgres, "monthly"))
-		r.Get("/pan-india", handleGetPanIndiaLeaderboard(postgres))
+		// ETag/304 support for clients polling leaderboards
+		r.Use(middleware.ETag)
+		// Pan-India: daily, weekly and monthly first (more specific)
+		r.Get("/pan-india/daily", handleGetPanIndiaLeaderboardWithPeriod(leaderboardStore, "daily"))
+		r.Get("/pan-india/weekly", handleGetPanIndiaLeaderboardWithPeriod(leaderboardStore, "weekly"))
+		r.Get("/pan-india/monthly", handleGetPanIndiaLeaderboardWithPeriod(leaderboardStore, "monthly"))
+		r.Get("/pan-india", handleGetPanIndiaLeaderboard(leaderboardStore))
 		// State
-		r.Get("/state/weekly", handleGetStateLeaderboardWithPeriod(postgres, "weekly"))
-		r.Get("/state/monthly", handleGetStateLeaderboardWithPeriod(postgres, "monthly"))
-		r.Get("/state", handleGetStateLeaderboard(postgres))
+		r.Get("/state/daily", handleGetStateLeaderboardWithPeriod(leaderboardStore, "daily"))
+		r.Get("/state/weekly", handleGetStateLeaderboardWithPeriod(leaderboardStore, "weekly"))
+		r.Get("/state/monthly", handleGetStateLeaderboardWithPeriod(leaderboardStore, "monthly"))
+		r.Get("/state", handleGetStateLeaderboard(leaderboardStore))
 		// College
-		r.Get("/college/weekly", handleGetCollegeLeaderboardWithPeriod(postgres, "weekly"))
-		r.Get("/college/monthly", handleGetCollegeLeaderboardWithPeriod(postgres, "monthly"))
-		r.Get("/college", handleGetCollegeLeaderboard(postgres))
+		r.Get("/college/daily", handleGetCollegeLeaderboardWithPeriod(leaderboardStore, "daily"))
+		r.Get("/college/weekly", handleGetCollegeLeaderboardWithPeriod(leaderboardStore, "weekly"))
+		r.Get("/college/monthly", handleGetCollegeLeaderboardWithPeriod(leaderboardStore, "monthly"))
+		r.Get("/college", handleGetCollegeLeaderboard(leaderboardStore))
+		// Top user from each state
+		r.Get("/states-top", handleGetStatesTopLeaderboard(leaderboardStore, redisClient))
+		// Colleges and states ranked by aggregate student XP
+		r.Get("/college-rankings", handleGetCollegeRankings(leaderboardStore, redisClient))
+		r.Get("/state-rankings", handleGetStateRankings(leaderboardStore, redisClient))
+		// Current user's rank in every scope
+		r.With(JWTAuthMiddleware(postgres, cfg)).Get("/my-rank", handleGetMyRank(leaderboardStore))
+		// Users ranked just above and below the current user
+		r.With(JWTAuthMiddleware(postgres, cfg)).Get("/around-me", handleGetLeaderboardAroundMe(leaderboardStore))
 	})
 
 	// Chat routes
 	r.Route("/chat", func(r chi.Router) {
-		r.Get("/rooms", handleGetChatRooms(postgres))
-		r.Get("/rooms/{id}", handleGetChatRoom(postgres))
+		r.Use(JWTAuthMiddleware(postgres, cfg))
+		r.Get("/rooms", handleGetChatRooms(chatStore))
+		r.Get("/rooms/{id}", handleGetChatRoom(chatStore, userStore))
+		r.Get("/rooms/{id}/messages", handleGetChatMessages(chatStore, userStore))
+		r.Post("/rooms/{id}/messages", handlePostChatMessage(chatStore, userStore, redisClient))
 	})
 
 	// Notification routes
 	r.Route("/notifications", func(r chi.Router) {
-		r.Get("/", handleGetNotifications(postgres))
+		r.Use(JWTAuthMiddleware(postgres, cfg))
+		r.Get("/", handleGetNotifications(notificationStore, announcementStore))
+		r.Post("/read-all", handleMarkAllNotificationsRead(notificationStore))
+		r.Post("/{id}/read", handleMarkNotificationRead(notificationStore))
 	})
 
+	// Badge catalogue (public; earned status is included when a JWT is sent)
+	r.Route("/badges", func(r chi.Router) {
+		r.Use(OptionalJWTAuthMiddleware(postgres, cfg))
+		r.Get("/", handleGetBadges(badgeStore))
+		r.Get("/{id}", handleGetBadge(badgeStore))
+	})
+
+	// College and state statistics (public)
+	r.Get("/college/{id}/stats", handleGetCollegeStats(statsStore, redisClient))
+	r.Get("/state/{id}/stats", handleGetStateStats(statsStore, redisClient))
+
 	// State routes
 	r.Route("/states", func(r chi.Router) {
-		r.Get("/", handleGetStates(postgres))
-		r.Get("/{stateId}/colleges", handleGetCollegesByState(postgres))
+		r.Get("/", handleGetStates(stateStore))
+		r.Get("/{stateId}/colleges", handleGetCollegesByState(collegeStore))
 	})
 }
 
 // SetupAdminRoutes sets up all admin routes
-func SetupAdminRoutes(r chi.Router, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) {
+func SetupAdminRoutes(r chi.Router, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, xpWorker *worker.XPWorker) {
+	// Stores only wrap the connection pool, so the handlers share one of each
+	adminStore := store.NewAdminStore(postgres)
+	announcementStore := store.NewAnnouncementStore(postgres)
+	blockedWordStore := store.NewBlockedWordStore(postgres)
+	coinStore := store.NewCoinStore(postgres)
+	collegeStore := store.NewCollegeStore(postgres)
+	featureFlagStore := store.NewFeatureFlagStore(postgres)
+	levelStore := store.NewLevelStore(postgres)
+	stateStore := store.NewStateStore(postgres)
+	statsStore := store.NewStatsStore(postgres)
+	submissionStore := store.NewSubmissionStore(postgres)
+	taskStore := store.NewTaskStore(postgres)
+	userStore := store.NewUserStore(postgres)
+	xpStore := store.NewXPStore(postgres)
+
 	// Admin authentication routes (public - no auth required)
-	r.Post("/login", handleAdminLogin(postgres, cfg))
+	r.Post("/login", handleAdminLogin(adminStore, cfg))
+	r.Post("/auth/2fa/verify", handleVerifyAdmin2FA(adminStore, redisClient, cfg))
+	r.Post("/bootstrap", handleBootstrapAdmin(adminStore, cfg)) // Gated by ADMIN_BOOTSTRAP_SECRET instead of a JWT
 
 	// Protected admin routes (require JWT authentication)
 	r.Group(func(r chi.Router) {
 		// Use JWT middleware for admin routes
-		r.Use(JWTAuthMiddleware(cfg))
-		// Admin middleware (authorization/role checking will be added)
-		r.Use(adminAuthMiddleware(cfg))
+		r.Use(JWTAuthMiddleware(postgres, cfg))
+		// Admin middleware: requires the admin role and an existing admin account
+		r.Use(adminAuthMiddleware(postgres, cfg))
 
 		// Admin management
-		r.Post("/create", handleCreateAdmin(postgres))
+		r.With(RequirePermission(store.PermissionManageAdmins)).Post("/create", handleCreateAdmin(adminStore))
+		r.Post("/me/password", handleChangeAdminPassword(adminStore))
+		r.Get("/me/permissions", handleGetMyAdminPermissions())
+		r.Post("/me/2fa/setup", handleSetupAdmin2FA(adminStore))
+		r.Post("/me/2fa/confirm", handleConfirmAdmin2FA(adminStore, redisClient, cfg))
+		r.With(RequirePermission(store.PermissionManageAdmins)).Put("/{id}/permissions", handleUpdateAdminPermissions(adminStore))
 
 		// State management - must be before other routes to avoid conflicts
 		r.Route("/states", func(r chi.Router) {
-			r.Get("/", handleGetStates(postgres))
-			r.Post("/", handleCreateState(postgres))
+			r.Get("/", handleGetStates(stateStore))
+			r.Post("/", handleCreateState(stateStore))
 		})
 
 		// College management
 		r.Route("/colleges", func(r chi.Router) {
-			r.Post("/", handleCreateCollege(postgres))
+			r.Post("/", handleCreateCollege(collegeStore))
 		})
 
 		// Task management
 		r.Route("/tasks", func(r chi.Router) {
-			r.Post("/", handleCreateTask(postgres, redisClient))
-			r.Put("/{id}", handleUpdateTask(postgres, redisClient))
+			r.Use(RequirePermission(store.PermissionManageTasks))
+			r.Post("/", handleCreateTask(adminStore, taskStore, redisClient, cfg))
+			r.Put("/{id}", handleUpdateTask(postgres, redisClient, cfg))
+			r.Put("/{id}/assignment", handleUpdateTaskAssignment(taskStore))
+			r.Post("/{id}/assign", handleAssignTaskUsers(taskStore))
+			r.Delete("/{id}/unassign", handleUnassignTaskUsers(taskStore))
 		})
 
 		// Badge management
 		r.Route("/badges", func(r chi.Router) {
+			r.Use(RequirePermission(store.PermissionManageBadges))
 			r.Post("/", handleCreateBadge(postgres, cfg))
 		})
 
+		// XP audit trail (JSON or CSV export)
+		r.Get("/xp-logs", handleGetXPLogs(xpStore))
+
+		// XP farming detection
+		r.Get("/leaderboard/anomalies", handleGetXPAnomalies(xpStore))
+
+		// Level thresholds
+		r.Get("/levels", handleGetLevels(levelStore))
+		r.With(RequirePermission(store.PermissionManageAdmins)).Put("/levels", handleUpdateLevels(levelStore))
+
+		// Content filter blocklist
+		r.Route("/blocked-words", func(r chi.Router) {
+			r.Use(RequirePermission(store.PermissionManageAdmins))
+			r.Get("/", handleGetBlockedWords(blockedWordStore))
+			r.Post("/", handleAddBlockedWord(blockedWordStore))
+			r.Delete("/{id}", handleDeleteBlockedWord(blockedWordStore))
+		})
+
+		// Feature flags
+		r.Get("/feature-flags", handleGetFeatureFlags(featureFlagStore))
+		r.With(RequirePermission(store.PermissionManageAdmins)).Put("/feature-flags/{name}", handleUpdateFeatureFlag(featureFlagStore))
+
+		// Announcements (broadcast to all users)
+		r.With(RequirePermission(store.PermissionManageAdmins)).Post("/broadcast", handleBroadcastAnnouncement(adminStore, announcementStore, redisClient))
+
+		// Platform statistics
+		r.Get("/dashboard", handleAdminDashboard(statsStore, redisClient))
+
 		// User management
-		r.Get("/users", handleGetAllUsers(postgres))
-		r.Post("/users/xp", handleAddXP(postgres, redisClient))
+		r.Get("/users", handleGetAllUsers(adminStore, userStore))
+		r.Get("/users/count-by-state", handleGetUserCountByState(statsStore))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/bulk-import", handleBulkImportUsers(postgres, redisClient, cfg))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/xp", handleAddXP(adminStore, xpStore, userStore, redisClient))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/{id}/xp/adjust", handleAdjustUserXP(postgres, redisClient))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/{id}/xp", handleAdjustUserXP(postgres, redisClient))
+		r.Get("/users/{id}/submissions", handleGetUserSubmissions(userStore, submissionStore))
+		r.Get("/users/{id}/coins/history", handleGetUserCoinHistory(userStore, coinStore))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/{id}/ban", handleBanUser(userStore))
+		r.With(RequirePermission(store.PermissionManageUsers)).Post("/users/{id}/unban", handleUnbanUser(userStore))
 
 		// Submission management
 		r.Route("/submissions", func(r chi.Router) {
-			r.Get("/", handleGetSubmissions(postgres))
-			r.Post("/{id}/approve", handleApproveSubmission(postgres, redisClient))
-			r.Post("/{id}/reject", handleRejectSubmission(postgres, cfg))
+			r.Get("/", handleGetSubmissions(submissionStore))
+			r.With(RequirePermission(store.PermissionReviewSubmissions)).Post("/bulk-approve", handleBulkApproveSubmissions(postgres, redisClient, cfg))
+			r.With(RequirePermission(store.PermissionReviewSubmissions)).Post("/bulk-reject", handleBulkRejectSubmissions(postgres, redisClient, cfg))
+			r.Get("/{id}", handleGetSubmission(submissionStore))
+			r.With(RequirePermission(store.PermissionReviewSubmissions)).Post("/{id}/approve", handleApproveSubmission(postgres, redisClient, cfg, xpWorker))
+			r.With(RequirePermission(store.PermissionReviewSubmissions)).Post("/{id}/reject", handleRejectSubmission(postgres, redisClient, cfg))
 		})
 	})
 }
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestAdminRoutePermissions(t *testing.T) {
+	const testSecret = "test-secret"
+	token, err := auth.GenerateToken(testutil.TestAdminID, "", auth.RoleAdmin, testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("generating token: %v", err)
+	}
+	// Admins holding every scope except the one a route needs
+	allBut := func(perm string) []string {
+		var perms []string
+		for _, p := range store.AllAdminPermissions {
+			if p != perm {
+				perms = append(perms, p)
+			}
+		}
+		return perms
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		permissions []string
+		wantStatus  int
+	}{
+		{name: "ban without manage_users", method: http.MethodPost, target: "/users/" + testutil.TestUserID + "/ban", permissions: allBut(store.PermissionManageUsers), wantStatus: http.StatusForbidden},
+		{name: "unban without manage_users", method: http.MethodPost, target: "/users/" + testutil.TestUserID + "/unban", permissions: allBut(store.PermissionManageUsers), wantStatus: http.StatusForbidden},
+		{name: "add XP without manage_users", method: http.MethodPost, target: "/users/xp", permissions: allBut(store.PermissionManageUsers), wantStatus: http.StatusForbidden},
+		{name: "update levels without manage_admins", method: http.MethodPut, target: "/levels", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "update levels with manage_admins", method: http.MethodPut, target: "/levels", body: `{`, permissions: []string{store.PermissionManageAdmins}, wantStatus: http.StatusBadRequest},
+		{name: "update feature flag without manage_admins", method: http.MethodPut, target: "/feature-flags/chat", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "update feature flag with manage_admins", method: http.MethodPut, target: "/feature-flags/chat", body: `{`, permissions: []string{store.PermissionManageAdmins}, wantStatus: http.StatusBadRequest},
+		{name: "list blocked words without manage_admins", method: http.MethodGet, target: "/blocked-words", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "add blocked word without manage_admins", method: http.MethodPost, target: "/blocked-words", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "delete blocked word without manage_admins", method: http.MethodDelete, target: "/blocked-words/1", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "broadcast without manage_admins", method: http.MethodPost, target: "/broadcast", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+		{name: "create task without manage_tasks", method: http.MethodPost, target: "/tasks", permissions: allBut(store.PermissionManageTasks), wantStatus: http.StatusForbidden},
+		{name: "update task without manage_tasks", method: http.MethodPut, target: "/tasks/" + testutil.TestTaskID, permissions: allBut(store.PermissionManageTasks), wantStatus: http.StatusForbidden},
+		{name: "create badge without manage_badges", method: http.MethodPost, target: "/badges", permissions: allBut(store.PermissionManageBadges), wantStatus: http.StatusForbidden},
+		{name: "approve without review_submissions", method: http.MethodPost, target: "/submissions/" + testutil.TestSubmissionID + "/approve", permissions: allBut(store.PermissionReviewSubmissions), wantStatus: http.StatusForbidden},
+		{name: "create admin without manage_admins", method: http.MethodPost, target: "/create", permissions: allBut(store.PermissionManageAdmins), wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`SELECT id, name, username, role, permissions, totp_enabled, created_at, updated_at\s+FROM admins WHERE id = \$1`).
+				WithArgs(testutil.TestAdminID).
+				WillReturnRows([]string{"id", "name", "username", "role", "permissions", "totp_enabled", "created_at", "updated_at"},
+					[]any{testutil.TestAdminID, "Test Admin", "testadmin", "admin", tt.permissions, false, testutil.TestTime, testutil.TestTime})
+
+			router := chi.NewRouter()
+			SetupAdminRoutes(router, postgres, nil, &env.Config{JWTSecret: testSecret}, nil)
+
+			r := newTestRequest(tt.method, tt.target, tt.body)
+			r.Header.Set("Authorization", "Bearer "+token)
+			serve(t, router, r, tt.wantStatus)
+		})
+	}
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	const testSecret = "test-secret"
+	token := func(userID, role, secret string) string {
+		tok, err := auth.GenerateToken(userID, "", role, secret, time.Hour)
+		if err != nil {
+			t.Fatalf("generating token: %v", err)
+		}
+		return tok
+	}
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		header       string
+		banCheck     bool // non-admin tokens are checked for a ban first
+		adminMissing bool
+		adminErr     error
+		wantStatus   int
+	}{
+		{name: "no token", method: http.MethodGet, target: "/submissions", wantStatus: http.StatusUnauthorized},
+		{name: "signed with another secret", method: http.MethodGet, target: "/submissions", header: "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, "other-secret"), wantStatus: http.StatusUnauthorized},
+		{name: "student token", method: http.MethodGet, target: "/submissions", header: "Bearer " + token(testutil.TestUserID, "student", testSecret), banCheck: true, wantStatus: http.StatusForbidden},
+		{name: "deleted admin", method: http.MethodGet, target: "/submissions", header: "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, testSecret), adminMissing: true, wantStatus: http.StatusUnauthorized},
+		{name: "admin lookup fails", method: http.MethodGet, target: "/submissions", header: "Bearer " + token(testutil.TestAdminID, auth.RoleAdmin, testSecret), adminErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "dashboard without token", method: http.MethodGet, target: "/dashboard", wantStatus: http.StatusUnauthorized},
+		{name: "user count by state with student token", method: http.MethodGet, target: "/users/count-by-state", header: "Bearer " + token(testutil.TestUserID, "student", testSecret), banCheck: true, wantStatus: http.StatusForbidden},
+		{name: "login stays public", method: http.MethodPost, target: "/login", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.banCheck {
+				mockDB.ExpectQuery(`SELECT is_banned FROM users`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"is_banned"}, []any{false})
+			}
+			if tt.adminMissing || tt.adminErr != nil {
+				admin := mockDB.ExpectQuery(`FROM admins WHERE id = \$1`).WithArgs(testutil.TestAdminID)
+				if tt.adminErr != nil {
+					admin.WillReturnError(tt.adminErr)
+				} else {
+					admin.WillReturnRows([]string{"id", "name", "username", "role", "permissions", "totp_enabled", "created_at", "updated_at"})
+				}
+			}
+
+			router := chi.NewRouter()
+			SetupAdminRoutes(router, postgres, nil, &env.Config{JWTSecret: testSecret}, nil)
+
+			r := newTestRequest(tt.method, tt.target, "")
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			serve(t, router, r, tt.wantStatus)
+		})
+	}
+}
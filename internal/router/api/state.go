@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
@@ -18,11 +17,10 @@ import (
 // @Success      200  {array}   store.State
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/states [get]
-func handleGetStates(postgres *db.Postgres) http.HandlerFunc {
+func handleGetStates(stateStore store.StateStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		stateStore := store.NewStateStore(postgres)
 		states, err := stateStore.GetAllStates(ctx)
 		if err != nil {
 			log.Printf("Error fetching states: %v", err)
@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
@@ -20,7 +19,7 @@ import (
 // @Failure      400    {string}  string  "Bad request"
 // @Failure      500    {string}  string  "Internal server error"
 // @Router       /admin/states [post]
-func handleCreateState(postgres *db.Postgres) http.HandlerFunc {
+func handleCreateState(stateStore store.StateStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -36,7 +35,6 @@ func handleCreateState(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		stateStore := store.NewStateStore(postgres)
 		state, err := stateStore.CreateState(ctx, req)
 		if err != nil {
 			log.Printf("Error creating state: %v", err)
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+)
+
+func TestHandleCreateState(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "creates state", body: `{"name":"Kerala","code":"KL"}`, wantStatus: http.StatusCreated, wantCalled: true},
+		{name: "invalid JSON", body: `{"name":`, wantStatus: http.StatusBadRequest},
+		{name: "missing code", body: `{"name":"Kerala"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing name", body: `{"code":"KL"}`, wantStatus: http.StatusBadRequest},
+		{name: "store error", body: `{"name":"Kerala","code":"KL"}`, err: errors.New("duplicate key"), wantStatus: http.StatusInternalServerError, wantCalled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			stateStore := &mock.StateStore{
+				CreateStateFunc: func(ctx context.Context, req store.CreateStateRequest) (*store.State, error) {
+					called = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.State{ID: "s1", Name: req.Name, Code: req.Code}, nil
+				},
+			}
+
+			serve(t, handleCreateState(stateStore), newTestRequest(http.MethodPost, "/admin/states", tt.body), tt.wantStatus)
+			if called != tt.wantCalled {
+				t.Errorf("CreateState called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+)
+
+func TestHandleGetStates(t *testing.T) {
+	tests := []struct {
+		name       string
+		states     []store.State
+		err        error
+		wantStatus int
+		wantCount  int
+	}{
+		{name: "lists states", states: []store.State{{ID: "s1", Name: "Kerala", Code: "KL"}, {ID: "s2", Name: "Goa", Code: "GA"}}, wantStatus: http.StatusOK, wantCount: 2},
+		{name: "no states", states: []store.State{}, wantStatus: http.StatusOK, wantCount: 0},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateStore := &mock.StateStore{
+				GetAllStatesFunc: func(ctx context.Context) ([]store.State, error) {
+					return tt.states, tt.err
+				},
+			}
+
+			w := serve(t, handleGetStates(stateStore), newTestRequest(http.MethodGet, "/api/states", ""), tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []store.State
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("got %d states, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
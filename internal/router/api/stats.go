@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// handleGetCollegeStats returns public statistics for a college
+// @Summary      Get college stats
+// @Description  Student count, approved tasks, total and average XP, badges earned and the top student of a college. Cached for 10 minutes and refreshed when a student of the college earns XP.
+// @Tags         colleges
+// @Produce      json
+// @Param        id   path      string  true  "College ID"
+// @Success      200  {object}  store.CollegeStats  "College statistics"
+// @Failure      404  {string}  string  "College not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/college/{id}/stats [get]
+func handleGetCollegeStats(statsStore store.StatsStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collegeID := chi.URLParam(r, "id")
+		serveCachedStats(w, r, redisClient, "college", collegeID, "College not found", func(ctx context.Context) (interface{}, error) {
+			return statsStore.GetCollegeStats(ctx, collegeID)
+		})
+	}
+}
+
+// handleGetStateStats returns public statistics for a state
+// @Summary      Get state stats
+// @Description  Student and college counts, approved tasks, total and average XP, badges earned and the top student of a state. Cached for 10 minutes and refreshed when a student of the state earns XP.
+// @Tags         states
+// @Produce      json
+// @Param        id   path      string  true  "State ID"
+// @Success      200  {object}  store.StateStats  "State statistics"
+// @Failure      404  {string}  string  "State not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/state/{id}/stats [get]
+func handleGetStateStats(statsStore store.StatsStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stateID := chi.URLParam(r, "id")
+		serveCachedStats(w, r, redisClient, "state", stateID, "State not found", func(ctx context.Context) (interface{}, error) {
+			return statsStore.GetStateStats(ctx, stateID)
+		})
+	}
+}
+
+// serveCachedStats writes college or state statistics, serving them from the Redis cache when present.
+// fetch loads the statistics from the database on a cache miss; notFound is the 404 message
+// when fetch reports the college or state doesn't exist.
+func serveCachedStats(w http.ResponseWriter, r *http.Request, redisClient *db.Redis, scope, scopeID, notFound string, fetch func(ctx context.Context) (interface{}, error)) {
+	ctx := r.Context()
+
+	cacheKey := ws.StatsCacheKey(scope, scopeID)
+	if redisClient != nil {
+		if cached, err := redisClient.Client.Get(ctx, cacheKey).Bytes(); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(cached)
+			return
+		}
+	}
+
+	stats, err := fetch(ctx)
+	if err != nil {
+		if err.Error() == scope+" not found" {
+			http.Error(w, notFound, http.StatusNotFound)
+			return
+		}
+		log.Printf("Error getting %s stats: %v", scope, err)
+		http.Error(w, fmt.Sprintf("Failed to get %s stats: %v", scope, err), http.StatusInternalServerError)
+		return
+	}
+
+	responseJSON, err := json.Marshal(stats)
+	if err != nil {
+		log.Printf("Error encoding %s stats response: %v", scope, err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if redisClient != nil {
+		if err := redisClient.Client.Set(ctx, cacheKey, responseJSON, ws.StatsCacheTTL).Err(); err != nil {
+			log.Printf("Error caching %s stats: %v", scope, err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(responseJSON)
+}
+
+// handleGetMyStats returns the current user's home screen summary
+// @Summary      Get my stats
+// @Description  XP, level, pan-India rank, completed and pending (under review) tasks, XP earned this week, streak, badge count and follower counts in one call. Cached for 60 seconds and refreshed when the user earns XP, checks in or a submission changes.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.UserStats  "User statistics"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/me/stats [get]
+func handleGetMyStats(statsStore store.StatsStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		cacheKey := ws.UserStatsCacheKey(userID)
+		if redisClient != nil {
+			if cached, err := redisClient.Client.Get(ctx, cacheKey).Bytes(); err == nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(cached)
+				return
+			}
+		}
+
+		stats, err := statsStore.GetUserStats(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user stats: %v", err)
+			http.Error(w, "Failed to get stats", http.StatusInternalServerError)
+			return
+		}
+
+		responseJSON, err := json.Marshal(stats)
+		if err != nil {
+			log.Printf("Error encoding user stats response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+
+		if redisClient != nil {
+			if err := redisClient.Client.Set(ctx, cacheKey, responseJSON, ws.UserStatsCacheTTL).Err(); err != nil {
+				log.Printf("Error caching user stats: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(responseJSON)
+	}
+}
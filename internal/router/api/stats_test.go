@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetCollegeStats(t *testing.T) {
+	cacheKey := ws.StatsCacheKey("college", testutil.TestCollegeID)
+	tests := []struct {
+		name        string
+		cached      string
+		err         error
+		wantStatus  int
+		wantFetched bool
+		wantBody    string
+		wantCached  bool
+	}{
+		{name: "cache miss fetches and caches", wantStatus: http.StatusOK, wantFetched: true, wantBody: `"college_name":"Test College"`, wantCached: true},
+		{name: "cache hit", cached: `{"college_id":"cached"}`, wantStatus: http.StatusOK, wantBody: `"college_id":"cached"`, wantCached: true},
+		{name: "unknown college", err: errors.New("college not found"), wantStatus: http.StatusNotFound, wantFetched: true},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantFetched: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.cached != "" {
+				server.Set(cacheKey, tt.cached)
+			}
+			fetched := false
+			statsStore := &mock.StatsStore{
+				GetCollegeStatsFunc: func(ctx context.Context, collegeID string) (*store.CollegeStats, error) {
+					fetched = true
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &store.CollegeStats{CollegeID: collegeID, CollegeName: "Test College"}, nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/college/x/stats", ""), "id", testutil.TestCollegeID)
+			w := serve(t, handleGetCollegeStats(statsStore, redisClient), r, tt.wantStatus)
+			if fetched != tt.wantFetched {
+				t.Errorf("GetCollegeStats called = %v, want %v", fetched, tt.wantFetched)
+			}
+			if tt.wantBody != "" && !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantBody)
+			}
+			if server.Exists(cacheKey) != tt.wantCached {
+				t.Errorf("cached = %v, want %v", server.Exists(cacheKey), tt.wantCached)
+			}
+			if tt.wantFetched && tt.wantCached {
+				if ttl := server.TTL(cacheKey); ttl != ws.StatsCacheTTL {
+					t.Errorf("cache TTL = %v, want %v", ttl, ws.StatsCacheTTL)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleGetMyStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		err        error
+		wantStatus int
+	}{
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", userID: testutil.TestUserID, err: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			statsStore := &mock.StatsStore{
+				GetUserStatsFunc: func(ctx context.Context, userID string) (*store.UserStats, error) {
+					return nil, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/stats", ""), tt.userID)
+			serve(t, handleGetMyStats(statsStore, redisClient), r, tt.wantStatus)
+			if keys := server.Keys(); len(keys) != 0 {
+				t.Errorf("cached %v after a failed request", keys)
+			}
+		})
+	}
+}
+
+// Stats are served from the cache until an XP award, check-in or submission change drops it
+func TestHandleGetMyStatsCache(t *testing.T) {
+	redisClient, server := testutil.NewMockRedis(t)
+	cacheKey := ws.UserStatsCacheKey(testutil.TestUserID)
+	fetches := 0
+	statsStore := &mock.StatsStore{
+		GetUserStatsFunc: func(ctx context.Context, userID string) (*store.UserStats, error) {
+			fetches++
+			return &store.UserStats{TotalXP: 100 * fetches, TasksCompleted: fetches}, nil
+		},
+	}
+	handler := handleGetMyStats(statsStore, redisClient)
+	get := func() string {
+		t.Helper()
+		r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/stats", ""), testutil.TestUserID)
+		return serve(t, handler, r, http.StatusOK).Body.String()
+	}
+
+	if body := get(); !strings.Contains(body, `"total_xp":100,`) {
+		t.Fatalf("first body = %s", body)
+	}
+	if cacheKey != "stats:"+testutil.TestUserID {
+		t.Errorf("cache key = %q", cacheKey)
+	}
+	if ttl := server.TTL(cacheKey); ttl != 60*time.Second {
+		t.Errorf("cache TTL = %v, want 60s", ttl)
+	}
+	if body := get(); !strings.Contains(body, `"total_xp":100,`) || fetches != 1 {
+		t.Errorf("second body = %s after %d fetches, want the cached stats", body, fetches)
+	}
+
+	ws.InvalidateUserStatsCache(context.Background(), redisClient, testutil.TestUserID)
+	if body := get(); !strings.Contains(body, `"total_xp":200,`) || fetches != 2 {
+		t.Errorf("body after invalidation = %s after %d fetches, want fresh stats", body, fetches)
+	}
+}
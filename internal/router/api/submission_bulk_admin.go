@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/storage"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// BulkReviewRequest is the body of the bulk approve and bulk reject endpoints
+type BulkReviewRequest struct {
+	SubmissionIDs []string `json:"submission_ids"` // 1 to 100 pending submissions
+	Comment       string   `json:"comment"`        // Optional for approvals, required for rejections
+}
+
+// BulkReviewResponse reports the outcome of a bulk review. Failed is empty when every submission was processed;
+// otherwise nothing was processed and Failed lists why.
+type BulkReviewResponse struct {
+	Processed int                       `json:"processed"`
+	Failed    []store.BulkReviewFailure `json:"failed"`
+}
+
+// parseBulkReviewRequest decodes and validates a bulk review body, writing the 400 response itself when invalid.
+// Duplicate IDs are dropped. Malformed IDs are returned as failures so the caller can report them without a query.
+func parseBulkReviewRequest(w http.ResponseWriter, r *http.Request) (*BulkReviewRequest, []store.BulkReviewFailure, bool) {
+	var req BulkReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if len(req.SubmissionIDs) == 0 {
+		http.Error(w, "submission_ids is required", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	seen := make(map[string]bool, len(req.SubmissionIDs))
+	ids := make([]string, 0, len(req.SubmissionIDs))
+	var failures []store.BulkReviewFailure
+	for _, id := range req.SubmissionIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := uuid.Parse(id); err != nil {
+			failures = append(failures, store.BulkReviewFailure{ID: id, Error: "invalid submission id"})
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(seen) > store.MaxBulkReviewSize {
+		http.Error(w, fmt.Sprintf("At most %d submissions can be reviewed at once", store.MaxBulkReviewSize), http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	req.SubmissionIDs = ids
+	return &req, failures, true
+}
+
+// writeBulkReviewResponse writes the bulk review outcome: 200 when everything was processed, 422 with the failures otherwise
+func writeBulkReviewResponse(w http.ResponseWriter, processed int, failures []store.BulkReviewFailure) {
+	status := http.StatusOK
+	if len(failures) > 0 {
+		status = http.StatusUnprocessableEntity
+		processed = 0
+	} else {
+		failures = []store.BulkReviewFailure{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(BulkReviewResponse{Processed: processed, Failed: failures}); err != nil {
+		log.Printf("Error encoding bulk review response: %v", err)
+	}
+}
+
+// handleBulkApproveSubmissions approves many submissions at once (admin)
+// @Summary      Bulk approve submissions
+// @Description  Approve up to 100 pending submissions in one transaction, awarding each task's XP and creating the feed entries. If any submission is missing, not pending or fails to process, nothing is approved and the per-ID errors are returned with status 422. Each user is notified once the approvals are committed. Requires the review_submissions permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      BulkReviewRequest  true  "Submission IDs and optional comment"
+// @Success      200      {object}  BulkReviewResponse  "All submissions approved"
+// @Failure      400      {string}  string  "Bad request - empty or more than 100 submission_ids"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - review_submissions permission required"
+// @Failure      422      {object}  BulkReviewResponse  "Nothing approved; failed lists the submissions that could not be"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/submissions/bulk-approve [post]
+func handleBulkApproveSubmissions(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		req, failures, ok := parseBulkReviewRequest(w, r)
+		if !ok {
+			return
+		}
+		if len(failures) > 0 {
+			writeBulkReviewResponse(w, 0, failures)
+			return
+		}
+
+		submissionStore := store.NewSubmissionStore(postgres)
+		results, failures, err := submissionStore.BulkApprove(ctx, req.SubmissionIDs, adminID, req.Comment)
+		if err != nil {
+			log.Printf("Error bulk approving submissions: %v", err)
+			http.Error(w, "Failed to approve submissions", http.StatusInternalServerError)
+			return
+		}
+		if len(failures) > 0 {
+			writeBulkReviewResponse(w, 0, failures)
+			return
+		}
+
+		auditStore := store.NewAuditLogStore(postgres)
+		if err := auditStore.Record(ctx, adminID, store.AuditActionSubmissionsBulkApprove, map[string]interface{}{
+			"submission_ids": req.SubmissionIDs,
+			"comment":        req.Comment,
+		}); err != nil {
+			log.Printf("Error recording bulk approve audit log: %v", err)
+		}
+		log.Printf("Admin %s bulk approved %d submissions", adminID, len(results))
+
+		// Users whose only approved submissions are in this batch just completed their first task,
+		// which earns their referrer the referral bonus
+		approvedInBatch := make(map[string]int)
+		for _, result := range results {
+			approvedInBatch[result.Submission.UserID]++
+		}
+		for userID, count := range approvedInBatch {
+			approved, err := submissionStore.CountApprovedSubmissions(ctx, userID)
+			if err != nil {
+				log.Printf("Error counting approved submissions: %v", err)
+				continue
+			}
+			if approved == count {
+				awardReferralBonus(ctx, postgres, redisClient, cfg, userID)
+			}
+		}
+
+		onboardingStore := store.NewOnboardingStore(postgres)
+		wsHub := ws.GetNotificationHub()
+		for _, result := range results {
+			submission := result.Submission
+			ws.InvalidateUserStatsCache(ctx, redisClient, submission.UserID)
+
+			if _, err := onboardingStore.MarkStep(ctx, submission.UserID, store.OnboardingStepCompleteFirstTask); err != nil {
+				log.Printf("Error marking onboarding step: %v", err)
+			}
+
+			recordActivity(ctx, postgres, store.RecordActivityRequest{
+				UserID:      submission.UserID,
+				EventType:   store.ActivityEventSubmissionApproved,
+				EntityID:    submission.TaskID,
+				EntityType:  "task",
+				Description: fmt.Sprintf("Completed %s", result.TaskTitle),
+			})
+
+			xpAwarded := 0
+			if result.XPLog != nil {
+				xpAwarded = result.XPLog.XP
+				broadcastXPChange(ctx, postgres, redisClient, submission.UserID)
+				if result.XPLog.LeveledUp && wsHub != nil {
+					if err := ws.SendLevelUpNotification(wsHub, submission.UserID, result.XPLog.NewLevel); err != nil {
+						log.Printf("Error sending level up notification: %v", err)
+					}
+				}
+			}
+
+			if wsHub != nil {
+				if err := ws.SendTaskApprovalNotification(wsHub, submission.UserID, submission.TaskID, result.TaskTitle, xpAwarded); err != nil {
+					log.Printf("Error sending task approval notification: %v", err)
+				}
+			}
+		}
+
+		writeBulkReviewResponse(w, len(results), nil)
+	}
+}
+
+// handleBulkRejectSubmissions rejects many submissions at once (admin)
+// @Summary      Bulk reject submissions
+// @Description  Reject up to 100 pending submissions in one transaction with the same comment. If any submission is missing or not pending, nothing is rejected and the per-ID errors are returned with status 422. Proof files are deleted and each user is notified once the rejections are committed. Requires the review_submissions permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      BulkReviewRequest  true  "Submission IDs and rejection comment"
+// @Success      200      {object}  BulkReviewResponse  "All submissions rejected"
+// @Failure      400      {string}  string  "Bad request - missing comment, empty or more than 100 submission_ids"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - review_submissions permission required"
+// @Failure      422      {object}  BulkReviewResponse  "Nothing rejected; failed lists the submissions that could not be"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/submissions/bulk-reject [post]
+func handleBulkRejectSubmissions(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		req, failures, ok := parseBulkReviewRequest(w, r)
+		if !ok {
+			return
+		}
+		if req.Comment == "" {
+			http.Error(w, "Rejection comment is required", http.StatusBadRequest)
+			return
+		}
+		if len(failures) > 0 {
+			writeBulkReviewResponse(w, 0, failures)
+			return
+		}
+
+		submissionStore := store.NewSubmissionStore(postgres)
+		results, failures, err := submissionStore.BulkReject(ctx, req.SubmissionIDs, adminID, req.Comment)
+		if err != nil {
+			log.Printf("Error bulk rejecting submissions: %v", err)
+			http.Error(w, "Failed to reject submissions", http.StatusInternalServerError)
+			return
+		}
+		if len(failures) > 0 {
+			writeBulkReviewResponse(w, 0, failures)
+			return
+		}
+
+		auditStore := store.NewAuditLogStore(postgres)
+		if err := auditStore.Record(ctx, adminID, store.AuditActionSubmissionsBulkReject, map[string]interface{}{
+			"submission_ids": req.SubmissionIDs,
+			"comment":        req.Comment,
+		}); err != nil {
+			log.Printf("Error recording bulk reject audit log: %v", err)
+		}
+		log.Printf("Admin %s bulk rejected %d submissions", adminID, len(results))
+
+		// Proof files of rejected submissions are deleted; the submission rows remain
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Region:                cfg.AWSRegion,
+			ProfileBucket:         cfg.AWSProfileBucket,
+			ResumeBucket:          cfg.AWSResumeBucket,
+			TaskProofBucket:       cfg.AWSTaskProofBucket,
+			AccessKeyID:           cfg.AWSAccessKeyID,
+			SecretAccessKey:       cfg.AWSSecretAccessKey,
+			TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+			TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
+		})
+		if err != nil {
+			log.Printf("Error initializing S3 for proof deletion: %v", err)
+		}
+
+		wsHub := ws.GetNotificationHub()
+		for _, result := range results {
+			submission := result.Submission
+			ws.InvalidateUserStatsCache(ctx, redisClient, submission.UserID)
+
+			if s3Storage != nil && submission.ProofURL != "" {
+				if proofKey := extractS3KeyFromURL(submission.ProofURL, s3Storage.GetTaskProofPublicURL()); proofKey != "" {
+					if err := s3Storage.DeleteTaskProof(ctx, proofKey); err != nil {
+						log.Printf("Error deleting rejected submission proof from S3 (submission %s): %v", submission.ID, err)
+					}
+				}
+			}
+
+			recordActivity(ctx, postgres, store.RecordActivityRequest{
+				UserID:      submission.UserID,
+				EventType:   store.ActivityEventSubmissionRejected,
+				EntityID:    submission.ID,
+				EntityType:  "submission",
+				Description: fmt.Sprintf("Submission for %s was rejected", result.TaskTitle),
+			})
+
+			if wsHub != nil {
+				if err := ws.SendTaskRejectionNotification(wsHub, submission.UserID, submission.TaskID, result.TaskTitle, req.Comment); err != nil {
+					log.Printf("Error sending task rejection notification: %v", err)
+				}
+			}
+		}
+
+		writeBulkReviewResponse(w, len(results), nil)
+	}
+}
+
+// broadcastXPChange tells the pan-India, state and college leaderboards of the user that their XP changed
+func broadcastXPChange(ctx context.Context, postgres *db.Postgres, redisClient *db.Redis, userID string) {
+	user, err := store.NewUserStore(postgres).GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user %s for leaderboard update: %v", userID, err)
+		return
+	}
+	ws.BroadcastUserXPChange(ctx, redisClient, user)
+}
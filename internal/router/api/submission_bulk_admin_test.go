@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestParseBulkReviewRequest(t *testing.T) {
+	idsJSON := func(n int) string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf(`"00000000-0000-0000-0000-%012d"`, i)
+		}
+		return `{"submission_ids":[` + strings.Join(ids, ",") + `]}`
+	}
+	tests := []struct {
+		name         string
+		body         string
+		wantOK       bool
+		wantIDs      int
+		wantFailures int
+	}{
+		{name: "valid IDs", body: `{"submission_ids":["` + testutil.TestSubmissionID + `"],"comment":"ok"}`, wantOK: true, wantIDs: 1},
+		{name: "duplicates dropped", body: `{"submission_ids":["` + testutil.TestSubmissionID + `","` + testutil.TestSubmissionID + `"]}`, wantOK: true, wantIDs: 1},
+		{name: "malformed IDs reported", body: `{"submission_ids":["` + testutil.TestSubmissionID + `","42"]}`, wantOK: true, wantIDs: 1, wantFailures: 1},
+		{name: "largest batch", body: idsJSON(store.MaxBulkReviewSize), wantOK: true, wantIDs: store.MaxBulkReviewSize},
+		{name: "batch too large", body: idsJSON(store.MaxBulkReviewSize + 1)},
+		{name: "empty", body: `{"submission_ids":[]}`},
+		{name: "invalid JSON", body: `{"submission_ids":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, failures, ok := parseBulkReviewRequest(w, newTestRequest(http.MethodPost, "/admin/submissions/bulk-approve", tt.body))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if len(req.SubmissionIDs) != tt.wantIDs || len(failures) != tt.wantFailures {
+				t.Errorf("got %d IDs and %d failures, want %d and %d", len(req.SubmissionIDs), len(failures), tt.wantIDs, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestWriteBulkReviewResponse(t *testing.T) {
+	tests := []struct {
+		name          string
+		processed     int
+		failures      []store.BulkReviewFailure
+		wantStatus    int
+		wantProcessed int
+	}{
+		{name: "all processed", processed: 3, wantStatus: http.StatusOK, wantProcessed: 3},
+		{name: "any failure processes nothing", processed: 3, failures: []store.BulkReviewFailure{{ID: "x", Error: "submission not pending"}}, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeBulkReviewResponse(w, tt.processed, tt.failures)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var got BulkReviewResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Processed != tt.wantProcessed || got.Failed == nil || len(got.Failed) != len(tt.failures) {
+				t.Errorf("response = %+v", got)
+			}
+		})
+	}
+}
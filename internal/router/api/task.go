@@ -1,17 +1,25 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"path/filepath"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/linkpreview"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
 	"github.com/rohit21755/groveserverv2/internal/storage"
 	"github.com/rohit21755/groveserverv2/internal/store"
 )
@@ -27,7 +35,7 @@ import (
 // @Failure      401  {string}  string  "Unauthorized"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/tasks [get]
-func handleGetTasks(postgres *db.Postgres) http.HandlerFunc {
+func handleGetTasks(taskStore store.TaskStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -38,9 +46,6 @@ func handleGetTasks(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		// Create task store
-		taskStore := store.NewTaskStore(postgres)
-
 		// Get tasks for user with user_status (completed / ongoing)
 		tasks, err := taskStore.GetTasksForUserWithStatus(ctx, userID)
 		if err != nil {
@@ -60,22 +65,309 @@ func handleGetTasks(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
-// handleSubmitTask handles submitting a task with proof (image or video)
+// handleGetFlashTasks returns active flash tasks for the authenticated user
+// @Summary      Get flash tasks
+// @Description  Get flash tasks that have not ended yet, soonest-ending first. Each task includes user_status and seconds_remaining (when the task has an end time) for a countdown timer.
+// @Tags         task
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   store.TaskWithUserStatus  "Active flash tasks"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/tasks/flash [get]
+func handleGetFlashTasks(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		tasks, err := taskStore.GetFlashTasks(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting flash tasks: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get flash tasks: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(tasks); err != nil {
+			log.Printf("Error encoding flash tasks response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetTaskHistory returns every weekly instance in a task's series
+// @Summary      Get weekly task history
+// @Description  Get the original weekly task and all instances auto-created from it, oldest first. The ID may be the original or any instance.
+// @Tags         task
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {array}   store.Task  "Task series"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "Task not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/tasks/{id}/history [get]
+func handleGetTaskHistory(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		tasks, err := taskStore.GetTaskSeries(ctx, taskID)
+		if err != nil {
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting task history: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get task history: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(tasks); err != nil {
+			log.Printf("Error encoding task history response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// ReactToTaskRequest is the body for reacting to a task
+type ReactToTaskRequest struct {
+	Reaction string `json:"reaction"` // e.g., "like", "fire", "interested"
+}
+
+// ReactToTaskResponse is the user's reaction state after a toggle
+type ReactToTaskResponse struct {
+	TaskID   string `json:"task_id"`
+	Reaction string `json:"reaction,omitempty"` // Empty when the reaction was removed
+	Reacted  bool   `json:"reacted"`
+}
+
+// TaskReactionsResponse is the reaction breakdown of a task
+type TaskReactionsResponse struct {
+	TaskID    string         `json:"task_id"`
+	Total     int            `json:"total"`
+	Reactions map[string]int `json:"reactions"` // reaction -> count
+}
+
+// maxReactionLength matches the task_reactions.reaction column size
+const maxReactionLength = 50
+
+// handleReactToTask toggles the authenticated user's reaction on a task
+// @Summary      React to task
+// @Description  Add, change or remove a reaction on a task. Sending the reaction the user already has removes it; a different reaction replaces it.
+// @Tags         task
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string              true  "Task ID"
+// @Param        request  body      ReactToTaskRequest  true  "Reaction"
+// @Success      200      {object}  ReactToTaskResponse  "Reaction state after the toggle"
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      404      {string}  string  "Task not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/tasks/{id}/react [post]
+func handleReactToTask(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req ReactToTaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Error decoding task react request: %v", err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		req.Reaction = strings.TrimSpace(req.Reaction)
+		if req.Reaction == "" {
+			http.Error(w, "Reaction is required", http.StatusBadRequest)
+			return
+		}
+		if len(req.Reaction) > maxReactionLength {
+			http.Error(w, fmt.Sprintf("Reaction must be at most %d characters", maxReactionLength), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := taskStore.GetTaskByID(ctx, taskID); err != nil {
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting task: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get task: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		reacted, err := taskStore.ToggleReaction(ctx, taskID, userID, req.Reaction)
+		if err != nil {
+			log.Printf("Error toggling task reaction: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to react to task: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := ReactToTaskResponse{
+			TaskID:  taskID,
+			Reacted: reacted,
+		}
+		if reacted {
+			response.Reaction = req.Reaction
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding task react response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetTaskReactions returns how many of each reaction a task has
+// @Summary      Get task reactions
+// @Description  Breakdown of reactions on a task (reaction -> count) and the total.
+// @Tags         task
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {object}  TaskReactionsResponse  "Reaction breakdown"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "Task not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/tasks/{id}/reactions [get]
+func handleGetTaskReactions(taskStore store.TaskStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		taskID := chi.URLParam(r, "id")
+		if taskID == "" {
+			http.Error(w, "Task ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := taskStore.GetTaskByID(ctx, taskID); err != nil {
+			if err.Error() == "task not found" {
+				http.Error(w, "Task not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting task: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get task: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		breakdown, err := taskStore.GetReactionBreakdown(ctx, taskID)
+		if err != nil {
+			log.Printf("Error getting task reactions: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get task reactions: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := TaskReactionsResponse{
+			TaskID:    taskID,
+			Reactions: breakdown,
+		}
+		for _, count := range breakdown {
+			response.Total += count
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding task reactions response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// SubmitLinkProofRequest is the JSON body for submitting a link-type task proof
+type SubmitLinkProofRequest struct {
+	ProofURL string `json:"proof_url"`
+}
+
+// validateProofURL checks that a link proof is an absolute http(s) URL on an allowed domain
+func validateProofURL(rawURL string, allowedDomains []string) error {
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("Invalid proof URL")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("Invalid proof URL")
+	}
+
+	if !linkpreview.HostAllowed(parsed.Hostname(), allowedDomains) {
+		return fmt.Errorf("Proof URL domain not allowed.")
+	}
+	return nil
+}
+
+// fetchLinkPreview fetches the Open Graph preview of a link proof in the background and
+// stores it for the admin review panel. Failures are only logged; the submission stands.
+func fetchLinkPreview(postgres *db.Postgres, submissionID, proofURL string, allowedDomains []string) {
+	go func() {
+		ctx := context.Background()
+		preview, err := linkpreview.FetchPreview(ctx, proofURL, allowedDomains)
+		if err != nil {
+			log.Printf("Error fetching link preview for submission %s: %v", submissionID, err)
+			return
+		}
+
+		submissionStore := store.NewSubmissionStore(postgres)
+		if err := submissionStore.SaveLinkPreview(ctx, submissionID, preview.Title, preview.Description, preview.ImageURL); err != nil {
+			log.Printf("Error saving link preview for submission %s: %v", submissionID, err)
+		}
+	}()
+}
+
+// submissionLockTTL bounds how long a submission lock is held if the request never releases it
+const submissionLockTTL = 10 * time.Second
+
+// handleSubmitTask handles submitting a task with proof (image, video or link)
 // @Summary      Submit task
-// @Description  Submit a task with proof file (image or video). The proof file will be uploaded to S3.
+// @Description  Submit a task with proof. For image/video tasks send a multipart proof file (uploaded to S3): JPG, PNG, GIF or WEBP up to 10MB, or MP4, MOV or WEBM up to 100MB, detected from the file content rather than the extension. For link tasks send JSON {"proof_url":"..."}; the URL must be on an allowed domain (ALLOWED_PROOF_DOMAINS). Image proofs are moderated with AWS Rekognition before upload.
 // @Tags         task
 // @Accept       multipart/form-data
+// @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id    path      string  true   "Task ID"
-// @Param        proof formData  file    true   "Proof file (image or video)"
+// @Param        proof formData  file    false  "Proof file (image or video tasks)"
 // @Success      201   {object}  store.Submission  "Submission created successfully"
-// @Failure      400   {string}  string  "Bad request - invalid file or task already submitted"
+// @Failure      400   {string}  string  "Bad request - invalid or too large file, or task already submitted"
 // @Failure      401   {string}  string  "Unauthorized"
+// @Failure      403   {object}  map[string]string  "Email address not verified (code EMAIL_NOT_VERIFIED)"
 // @Failure      404   {string}  string  "Task not found"
+// @Failure      409   {string}  string  "Submission already in progress"
+// @Failure      422   {object}  map[string]string  "Inappropriate content (code INAPPROPRIATE_CONTENT)"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /api/tasks/{id}/submit [post]
-func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleSubmitTask(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, moderator moderation.ImageModerator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -93,6 +385,40 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
+		// Only users who have confirmed their email address can submit
+		verified, err := store.NewEmailVerificationStore(postgres).IsEmailVerified(ctx, userID)
+		if err != nil {
+			log.Printf("Error checking email verification for user %s: %v", userID, err)
+			http.Error(w, "Failed to submit task", http.StatusInternalServerError)
+			return
+		}
+		if !verified {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "EMAIL_NOT_VERIFIED",
+				"message": "Please verify your email address before submitting tasks",
+			})
+			return
+		}
+
+		// Lock out concurrent submissions of the same task by the same user
+		// (e.g. a double-tapped submit button) until this one is created
+		if redisClient != nil {
+			lockKey := fmt.Sprintf("submission_lock:%s:%s", userID, taskID)
+			acquired, err := redisClient.Client.SetNX(ctx, lockKey, 1, submissionLockTTL).Result()
+			if err != nil {
+				log.Printf("Error acquiring submission lock: %v", err)
+				http.Error(w, "Failed to submit task", http.StatusInternalServerError)
+				return
+			}
+			if !acquired {
+				http.Error(w, "Submission already in progress", http.StatusConflict)
+				return
+			}
+			defer redisClient.Client.Del(context.Background(), lockKey)
+		}
+
 		// Verify task exists and get task details
 		taskStore := store.NewTaskStore(postgres)
 		task, err := taskStore.GetTaskByID(ctx, taskID)
@@ -142,17 +468,72 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			}
 		}
 
+		// Link proofs are submitted as a URL in a JSON body; no file upload
+		if task.ProofType == string(store.ProofTypeLink) {
+			var req SubmitLinkProofRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			if req.ProofURL == "" {
+				http.Error(w, "proof_url is required", http.StatusBadRequest)
+				return
+			}
+			if err := validateProofURL(req.ProofURL, cfg.AllowedProofDomains); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			submission, err := submissionStore.CreateSubmission(ctx, store.CreateSubmissionRequest{
+				TaskID:   taskID,
+				UserID:   userID,
+				ProofURL: req.ProofURL,
+			})
+			if err != nil {
+				log.Printf("Error creating submission: %v", err)
+				if strings.Contains(err.Error(), "already exists") {
+					http.Error(w, "Task already submitted", http.StatusBadRequest)
+					return
+				}
+				http.Error(w, fmt.Sprintf("Failed to create submission: %v", err), http.StatusInternalServerError)
+				return
+			}
+
+			recordActivity(ctx, postgres, store.RecordActivityRequest{
+				UserID:      userID,
+				EventType:   store.ActivityEventSubmissionCreated,
+				EntityID:    submission.ID,
+				EntityType:  "submission",
+				Description: fmt.Sprintf("Submitted %s", task.Title),
+			})
+
+			ws.InvalidateUserStatsCache(ctx, redisClient, userID)
+			fetchLinkPreview(postgres, submission.ID, req.ProofURL, cfg.AllowedProofDomains)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			if err := json.NewEncoder(w).Encode(submission); err != nil {
+				log.Printf("Error encoding submission response: %v", err)
+				http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+				return
+			}
+			return
+		}
+
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:             cfg.AWSRegion,
-			ProfileBucket:      cfg.AWSProfileBucket,
-			ResumeBucket:       cfg.AWSResumeBucket,
-			TaskProofBucket:    cfg.AWSTaskProofBucket,
-			AccessKeyID:        cfg.AWSAccessKeyID,
-			SecretAccessKey:    cfg.AWSSecretAccessKey,
-			ProfilePublicURL:   cfg.AWSProfilePublicURL,
-			ResumePublicURL:    cfg.AWSResumePublicURL,
-			TaskProofPublicURL: cfg.AWSTaskProofPublicURL,
+			Region:                cfg.AWSRegion,
+			ProfileBucket:         cfg.AWSProfileBucket,
+			ResumeBucket:          cfg.AWSResumeBucket,
+			TaskProofBucket:       cfg.AWSTaskProofBucket,
+			AccessKeyID:           cfg.AWSAccessKeyID,
+			SecretAccessKey:       cfg.AWSSecretAccessKey,
+			ProfilePublicURL:      cfg.AWSProfilePublicURL,
+			ResumePublicURL:       cfg.AWSResumePublicURL,
+			TaskProofPublicURL:    cfg.AWSTaskProofPublicURL,
+			ProfileBucketRegion:   cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:    cfg.AWSResumeBucketRegion,
+			TaskProofBucketRegion: cfg.AWSTaskProofBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -160,7 +541,7 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			return
 		}
 
-		// Parse multipart form (max 50MB for videos)
+		// Parse multipart form (up to 50MB is kept in memory, larger videos go to temp files)
 		err = r.ParseMultipartForm(50 << 20) // 50MB
 		if err != nil {
 			http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
@@ -175,94 +556,102 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 		}
 		defer proofFile.Close()
 
-		// Validate file type (image or video)
+		// Validate the file type from its content (image or video); the extension can't be trusted
 		filename := proofHeader.Filename
-		ext := strings.ToLower(filepath.Ext(filename))
-		allowedImageExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
-		allowedVideoExts := []string{".mp4", ".mov", ".avi", ".mkv", ".webm"}
-
-		isImage := false
-		isVideo := false
-		for _, allowedExt := range allowedImageExts {
-			if ext == allowedExt {
-				isImage = true
-				break
+		contentType, proofReader, err := storage.ValidateProofFile(proofFile, filename, storage.MaxProofVideoSizeMB)
+		if err != nil {
+			if errors.Is(err, storage.ErrUnsupportedProofType) {
+				http.Error(w, fmt.Sprintf("Invalid file type %s. Only images (JPG, PNG, GIF, WEBP) and videos (MP4, MOV, WEBM) are allowed", contentType), http.StatusBadRequest)
+				return
 			}
-		}
-		if !isImage {
-			for _, allowedExt := range allowedVideoExts {
-				if ext == allowedExt {
-					isVideo = true
-					break
-				}
+			if errors.Is(err, storage.ErrProofFileTooLarge) {
+				http.Error(w, fmt.Sprintf("File too large. Images can be at most %d MB and videos %d MB", storage.MaxProofImageSizeMB, storage.MaxProofVideoSizeMB), http.StatusBadRequest)
+				return
 			}
-		}
-
-		if !isImage && !isVideo {
-			http.Error(w, "Invalid file type. Only images (JPG, PNG, GIF, WEBP) and videos (MP4, MOV, AVI, MKV, WEBM) are allowed", http.StatusBadRequest)
+			log.Printf("Error validating proof file: %v", err)
+			http.Error(w, "Failed to read proof file", http.StatusInternalServerError)
 			return
 		}
+		isImage := storage.IsProofImage(contentType)
+		isVideo := !isImage
 
-		// Upload proof file to S3 task proof bucket
-		// Use a unique key: task-proofs/{taskID}/{userID}_{filename}
-		proofKey := fmt.Sprintf("task-proofs/%s/%s_%s", taskID, userID, filename)
-
-		var proofURL string
-		var contentType string
+		// Moderate image proofs before uploading (videos are not moderated)
+		if isImage && moderator != nil {
+			// Images are at most 10MB and the moderator reads them whole anyway
+			imageBytes, err := io.ReadAll(proofReader)
+			if err != nil {
+				log.Printf("Error reading proof image: %v", err)
+				http.Error(w, "Failed to read proof file", http.StatusInternalServerError)
+				return
+			}
+			proofImage := bytes.NewReader(imageBytes)
+			proofReader = proofImage
 
-		if isImage {
-			// Determine content type for images
-			contentType = "image/jpeg"
-			switch ext {
-			case ".png":
-				contentType = "image/png"
-			case ".gif":
-				contentType = "image/gif"
-			case ".webp":
-				contentType = "image/webp"
+			safe, labels, err := moderator.IsImageSafe(ctx, proofImage)
+			if err != nil {
+				log.Printf("Error moderating proof image: %v", err)
+				http.Error(w, "Failed to check proof image", http.StatusInternalServerError)
+				return
 			}
-		} else {
-			// Determine content type for videos
-			contentType = "video/mp4"
-			switch ext {
-			case ".mov":
-				contentType = "video/quicktime"
-			case ".avi":
-				contentType = "video/x-msvideo"
-			case ".mkv":
-				contentType = "video/x-matroska"
-			case ".webm":
-				contentType = "video/webm"
+			if !safe {
+				log.Printf("Rejected proof image from user %s for task %s: %s", userID, taskID, labels)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"code":    "INAPPROPRIATE_CONTENT",
+					"message": "Proof image contains inappropriate content and cannot be submitted",
+				})
+				return
 			}
 		}
 
-		// Upload to task proof bucket (for both images and videos)
-		// Use the public URL from S3Storage (which has default if not set in config)
-		taskProofPublicURL := s3Storage.GetTaskProofPublicURL()
-		if taskProofPublicURL == "" {
-			// Fallback: construct default URL if somehow not set
-			taskProofPublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.AWSTaskProofBucket, cfg.AWSRegion)
-		}
-		proofURL, err = s3Storage.UploadFile(ctx, proofFile, s3Storage.GetTaskProofBucket(), proofKey, contentType, taskProofPublicURL, false)
+		// Compensate for the uploads if the handler returns before the submission row exists.
+		// A background context is used so a cancelled request still cleans up.
+		var uploadedKeys []string
+		submissionCreated := false
+		defer func() {
+			if submissionCreated {
+				return
+			}
+			for _, key := range uploadedKeys {
+				if err := s3Storage.DeleteTaskProof(context.Background(), key); err != nil {
+					log.Printf("Error deleting orphaned task proof %s: %v", key, err)
+				}
+			}
+		}()
 
+		// Upload proof file (image or video) to the task proof bucket
+		proofURL, proofKey, err := s3Storage.UploadTaskProof(ctx, proofReader, taskID, userID, contentType)
 		if err != nil {
 			log.Printf("Error uploading proof file: %v", err)
 			http.Error(w, "Failed to upload proof file", http.StatusInternalServerError)
 			return
 		}
+		uploadedKeys = append(uploadedKeys, proofKey)
+
+		// Generate a thumbnail for video proofs so admins can preview them; failure is not fatal
+		var thumbnailURL, thumbnailKey string
+		if isVideo {
+			thumbnailKey = proofKey + "_thumb.jpg"
+			thumbnailURL, err = uploadVideoThumbnail(ctx, s3Storage, proofFile, thumbnailKey, s3Storage.GetTaskProofPublicURL())
+			if err != nil {
+				log.Printf("Skipping video thumbnail for %s: %v", proofKey, err)
+				thumbnailKey = ""
+			} else {
+				uploadedKeys = append(uploadedKeys, thumbnailKey)
+			}
+		}
 
 		// Create or update submission (if resubmission)
 		submission, err := submissionStore.CreateSubmission(ctx, store.CreateSubmissionRequest{
-			TaskID:   taskID,
-			UserID:   userID,
-			ProofURL: proofURL,
+			TaskID:       taskID,
+			UserID:       userID,
+			ProofURL:     proofURL,
+			ThumbnailURL: thumbnailURL,
 		})
 		if err != nil {
 			log.Printf("Error creating submission: %v", err)
 
-			// Try to delete uploaded proof file from S3 if submission creation fails
-			_ = s3Storage.DeleteTaskProof(ctx, proofKey)
-
 			if strings.Contains(err.Error(), "already exists") {
 				http.Error(w, "Task already submitted", http.StatusBadRequest)
 				return
@@ -271,25 +660,16 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 			http.Error(w, fmt.Sprintf("Failed to create submission: %v", err), http.StatusInternalServerError)
 			return
 		}
+		submissionCreated = true
+		ws.InvalidateUserStatsCache(ctx, redisClient, userID)
 
-		// ============================================================================
-		// TODO: Send WebSocket notification to admin about new submission
-		// ============================================================================
-		// Call WebSocket notification function here to notify admins about the new submission.
-		// This should be implemented in internal/router/ws/notifications.go
-		//
-		// Example implementation:
-		//   notification := map[string]interface{}{
-		//       "type": "new_submission",
-		//       "submission_id": submission.ID,
-		//       "task_id": taskID,
-		//       "task_title": task.Title,
-		//       "user_id": userID,
-		//       "proof_url": proofURL,
-		//       "timestamp": time.Now(),
-		//   }
-		//   ws.SendNotificationToAdmins(redisClient, notification)
-		// ============================================================================
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      userID,
+			EventType:   store.ActivityEventSubmissionCreated,
+			EntityID:    submission.ID,
+			EntityType:  "submission",
+			Description: fmt.Sprintf("Submitted %s", task.Title),
+		})
 
 		// Return submission
 		w.Header().Set("Content-Type", "application/json")
@@ -301,3 +681,29 @@ func handleSubmitTask(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
 		}
 	}
 }
+
+// uploadVideoThumbnail copies an uploaded video to a temp file, extracts a JPEG thumbnail with ffmpeg
+// and uploads it to the task proof bucket. Returns storage.ErrFFmpegNotInstalled if ffmpeg is missing.
+func uploadVideoThumbnail(ctx context.Context, s3Storage *storage.S3Storage, video io.ReadSeeker, thumbnailKey, publicURL string) (string, error) {
+	if _, err := video.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind video: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp("", "proof-video-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, video); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	thumbnail, err := storage.GenerateVideoThumbnail(ctx, tmpFile.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return s3Storage.UploadFile(ctx, thumbnail, s3Storage.GetTaskProofBucket(), thumbnailKey, "image/jpeg", publicURL, false)
+}
@@ -0,0 +1,254 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleReactToTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		taskID       string
+		body         string
+		taskErr      error
+		reacted      bool
+		wantStatus   int
+		wantReaction string
+	}{
+		{name: "adds reaction", userID: testutil.TestUserID, taskID: testutil.TestTaskID, body: `{"reaction":" 🔥 "}`, reacted: true, wantStatus: http.StatusOK, wantReaction: "🔥"},
+		{name: "toggles reaction off", userID: testutil.TestUserID, taskID: testutil.TestTaskID, body: `{"reaction":"🔥"}`, wantStatus: http.StatusOK},
+		{name: "anonymous", taskID: testutil.TestTaskID, body: `{"reaction":"🔥"}`, wantStatus: http.StatusUnauthorized},
+		{name: "missing task ID", userID: testutil.TestUserID, body: `{"reaction":"🔥"}`, wantStatus: http.StatusBadRequest},
+		{name: "empty reaction", userID: testutil.TestUserID, taskID: testutil.TestTaskID, body: `{"reaction":"  "}`, wantStatus: http.StatusBadRequest},
+		{name: "reaction too long", userID: testutil.TestUserID, taskID: testutil.TestTaskID, body: `{"reaction":"` + strings.Repeat("a", maxReactionLength+1) + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown task", userID: testutil.TestUserID, taskID: testutil.TestTaskID, body: `{"reaction":"🔥"}`, taskErr: errors.New("task not found"), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskStore := &mock.TaskStore{
+				GetTaskByIDFunc: func(ctx context.Context, taskID string) (*store.Task, error) {
+					return testutil.NewTestTask(), tt.taskErr
+				},
+				ToggleReactionFunc: func(ctx context.Context, taskID, userID, reaction string) (bool, error) {
+					return tt.reacted, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/tasks/x/react", tt.body), tt.userID)
+			r = withURLParams(r, "id", tt.taskID)
+			w := serve(t, handleReactToTask(taskStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got ReactToTaskResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.Reacted != tt.reacted || got.Reaction != tt.wantReaction {
+				t.Errorf("response = %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandleGetTaskReactions(t *testing.T) {
+	taskStore := &mock.TaskStore{
+		GetTaskByIDFunc: func(ctx context.Context, taskID string) (*store.Task, error) {
+			return testutil.NewTestTask(), nil
+		},
+		GetReactionBreakdownFunc: func(ctx context.Context, taskID string) (map[string]int, error) {
+			return map[string]int{"🔥": 3, "👍": 2}, nil
+		},
+	}
+
+	r := withURLParams(newTestRequest(http.MethodGet, "/api/tasks/x/reactions", ""), "id", testutil.TestTaskID)
+	w := serve(t, handleGetTaskReactions(taskStore), r, http.StatusOK)
+	var got TaskReactionsResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if got.Total != 5 {
+		t.Errorf("total = %d, want 5", got.Total)
+	}
+}
+
+func TestValidateProofURL(t *testing.T) {
+	allowed := []string{"github.com", "YouTube.com"}
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "allowed domain", url: "https://github.com/user/repo"},
+		{name: "subdomain", url: "https://gist.github.com/abc"},
+		{name: "case insensitive", url: "http://www.YOUTUBE.com/watch?v=1"},
+		{name: "other domain", url: "https://example.com/page", wantErr: true},
+		{name: "lookalike domain", url: "https://evilgithub.com/page", wantErr: true},
+		{name: "non-http scheme", url: "ftp://github.com/file", wantErr: true},
+		{name: "relative", url: "/user/repo", wantErr: true},
+		{name: "not a URL", url: "github.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProofURL(tt.url, allowed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateProofURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Link proofs are stored as given, without an upload. The .invalid domain never resolves,
+// so the background preview fetch fails without touching the database.
+func TestHandleSubmitTaskLinkProof(t *testing.T) {
+	taskColumns := []string{"id", "title", "description", "xp", "type", "proof_type", "priority", "start_at", "end_at", "is_flash", "is_weekly", "created_by", "created_at", "status"}
+	submissionColumns := []string{"id", "task_id", "user_id", "proof_url", "thumbnail_url", "status", "admin_comment", "reviewed_by", "created_at", "updated_at"}
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantMessage string
+		wantCreated bool
+	}{
+		{name: "allowed domain", body: `{"proof_url":"https://www.example.invalid/posts/1"}`, wantStatus: http.StatusCreated, wantCreated: true},
+		{name: "disallowed domain", body: `{"proof_url":"https://evil.test/posts/1"}`, wantStatus: http.StatusBadRequest, wantMessage: "Proof URL domain not allowed."},
+		{name: "not a URL", body: `{"proof_url":"example.invalid/posts/1"}`, wantStatus: http.StatusBadRequest, wantMessage: "Invalid proof URL"},
+		{name: "non-http scheme", body: `{"proof_url":"javascript://example.invalid/%0aalert(1)"}`, wantStatus: http.StatusBadRequest, wantMessage: "Invalid proof URL"},
+		{name: "missing proof_url", body: `{}`, wantStatus: http.StatusBadRequest, wantMessage: "proof_url is required"},
+		{name: "not JSON", body: `proof_url=https://www.example.invalid`, wantStatus: http.StatusBadRequest, wantMessage: "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`SELECT email_verified_at IS NOT NULL FROM users`).
+				WithArgs(testutil.TestUserID).
+				WillReturnRows([]string{"verified"}, []any{true})
+			mockDB.ExpectQuery(`FROM tasks WHERE id = \$1`).
+				WithArgs(testutil.TestTaskID).
+				WillReturnRows(taskColumns, []any{testutil.TestTaskID, "Share your post", "", int64(50), "online", "link", "medium", nil, nil, false, false, testutil.TestAdminID, testutil.TestTime, "ongoing"})
+			mockDB.ExpectQuery(`FROM submissions s`).
+				WithArgs(testutil.TestTaskID, testutil.TestUserID).
+				WillReturnRows(submissionColumns)
+			if tt.wantCreated {
+				mockDB.ExpectQuery(`FROM submissions s`).
+					WithArgs(testutil.TestTaskID, testutil.TestUserID).
+					WillReturnRows(submissionColumns)
+				mockDB.ExpectQuery(`INSERT INTO submissions`).
+					WillReturnRows(submissionColumns, []any{testutil.TestSubmissionID, testutil.TestTaskID, testutil.TestUserID, "https://www.example.invalid/posts/1", nil, "pending", nil, nil, testutil.TestTime, testutil.TestTime})
+				mockDB.ExpectExec(`INSERT INTO user_activity_log`).WillReturnResult(1)
+			}
+			redisClient, server := testutil.NewMockRedis(t)
+
+			cfg := &env.Config{AllowedProofDomains: []string{"example.invalid"}}
+			r := withUserID(newTestRequest(http.MethodPost, "/api/tasks/x/submit", tt.body), testutil.TestUserID)
+			r = withURLParams(r, "id", testutil.TestTaskID)
+			w := serve(t, handleSubmitTask(postgres, redisClient, cfg, nil), r, tt.wantStatus)
+			if tt.wantMessage != "" && strings.TrimSpace(w.Body.String()) != tt.wantMessage {
+				t.Errorf("body = %q, want %q", strings.TrimSpace(w.Body.String()), tt.wantMessage)
+			}
+			if server.Exists("submission_lock:" + testutil.TestUserID + ":" + testutil.TestTaskID) {
+				t.Error("submission lock was not released")
+			}
+			if !tt.wantCreated {
+				return
+			}
+			var got store.Submission
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.ProofURL != "https://www.example.invalid/posts/1" || got.Status != "pending" {
+				t.Errorf("submission = %+v", got)
+			}
+		})
+	}
+}
+
+// A proof uploaded to S3 is deleted again when the submission row cannot be written.
+// The S3 storage the handler builds talks to a fake server through AWS_ENDPOINT_URL_S3.
+func TestHandleSubmitTaskDeletesProofWhenSubmissionFails(t *testing.T) {
+	var mu sync.Mutex
+	var s3Requests []string
+	fakeS3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		s3Requests = append(s3Requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer fakeS3.Close()
+	t.Setenv("AWS_ENDPOINT_URL_S3", fakeS3.URL)
+
+	taskColumns := []string{"id", "title", "description", "xp", "type", "proof_type", "priority", "start_at", "end_at", "is_flash", "is_weekly", "created_by", "created_at", "status"}
+	submissionColumns := []string{"id", "task_id", "user_id", "proof_url", "thumbnail_url", "status", "admin_comment", "reviewed_by", "created_at", "updated_at"}
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectQuery(`SELECT email_verified_at IS NOT NULL FROM users`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"verified"}, []any{true})
+	mockDB.ExpectQuery(`FROM tasks WHERE id = \$1`).
+		WithArgs(testutil.TestTaskID).
+		WillReturnRows(taskColumns, []any{testutil.TestTaskID, "Share your post", "", int64(50), "online", "image", "medium", nil, nil, false, false, testutil.TestAdminID, testutil.TestTime, "ongoing"})
+	mockDB.ExpectQuery(`FROM submissions s`).
+		WithArgs(testutil.TestTaskID, testutil.TestUserID).
+		WillReturnRows(submissionColumns)
+	mockDB.ExpectQuery(`FROM submissions s`).
+		WithArgs(testutil.TestTaskID, testutil.TestUserID).
+		WillReturnRows(submissionColumns)
+	mockDB.ExpectQuery(`INSERT INTO submissions`).
+		WillReturnError(errors.New("connection reset by peer"))
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("proof", "proof.png")
+	if err != nil {
+		t.Fatalf("creating form file: %v", err)
+	}
+	part.Write(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...))
+	form.Close()
+
+	cfg := &env.Config{
+		AWSRegion:          "us-east-1",
+		AWSTaskProofBucket: "proofs",
+		AWSAccessKeyID:     "test",
+		AWSSecretAccessKey: "test",
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/tasks/x/submit", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	r = withURLParams(withUserID(r, testutil.TestUserID), "id", testutil.TestTaskID)
+	serve(t, handleSubmitTask(postgres, nil, cfg, nil), r, http.StatusInternalServerError)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(s3Requests) != 2 {
+		t.Fatalf("S3 requests = %v, want an upload then a delete", s3Requests)
+	}
+	upload, cleanup := s3Requests[0], s3Requests[1]
+	wantPrefix := "/proofs/task-proofs/" + testutil.TestTaskID + "/" + testutil.TestUserID + "_"
+	if !strings.HasPrefix(upload, http.MethodPut+" "+wantPrefix) || !strings.HasSuffix(upload, ".png") {
+		t.Errorf("upload = %q, want a PUT under %s", upload, wantPrefix)
+	}
+	if cleanup != http.MethodDelete+" "+strings.TrimPrefix(upload, http.MethodPut+" ") {
+		t.Errorf("cleanup = %q, want DELETE of the uploaded key", cleanup)
+	}
+}
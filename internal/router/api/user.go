@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -15,6 +18,7 @@ import (
 	"github.com/rohit21755/groveserverv2/internal/router/ws"
 	"github.com/rohit21755/groveserverv2/internal/storage"
 	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/validator"
 )
 
 // handleGetMe handles getting the current user
@@ -28,7 +32,7 @@ import (
 // @Failure      401  {string}  string  "Unauthorized"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/user/me [get]
-func handleGetMe(postgres *db.Postgres) http.HandlerFunc {
+func handleGetMe(userStore store.UserStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -39,9 +43,6 @@ func handleGetMe(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		// Create user store
-		userStore := store.NewUserStore(postgres)
-
 		// Get user details with state and college names
 		user, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
@@ -61,19 +62,104 @@ func handleGetMe(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// handleUpdateMe handles updating the current user's profile text fields
+// @Summary      Update current user
+// @Description  Partially update the authenticated user's name, bio and phone. Omitted fields are left unchanged; an empty bio or phone clears it. name must be 2-100 characters, bio at most 500 characters and phone 10-15 digits.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      store.UpdateProfileRequest  true  "Fields to update"
+// @Success      200      {object}  store.User  "Updated user profile"
+// @Failure      400      {string}  string  "Bad request - invalid field or nothing to update"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      404      {string}  string  "User not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/user/me [patch]
+func handleUpdateMe(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req store.UpdateProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == nil && req.Bio == nil && req.Phone == nil {
+			http.Error(w, "No fields to update: provide name, bio or phone", http.StatusBadRequest)
+			return
+		}
+
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if err := validator.ValidateName(name); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Name = &name
+		}
+		var bio, phone string
+		if req.Bio != nil {
+			bio = strings.TrimSpace(*req.Bio)
+			req.Bio = &bio
+		}
+		if req.Phone != nil {
+			phone = strings.TrimSpace(*req.Phone)
+			req.Phone = &phone
+		}
+		if err := validator.ValidateProfile(bio, phone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		user, err := userStore.UpdateProfile(ctx, userID, req)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error updating profile for user %s: %v", userID, err)
+			http.Error(w, "Failed to update profile", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(user); err != nil {
+			log.Printf("Error encoding user response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// profileCompletedTasksPreview is how many completed tasks the profile includes;
+// the full list is paginated at /api/user/{id}/tasks/completed
+const profileCompletedTasksPreview = 6
+
 // UserProfile represents a complete user profile
 type UserProfile struct {
-	User           *store.User      `json:"user"`
-	CompletedTasks []store.FeedItem `json:"completed_tasks"`
-	FollowingCount int              `json:"following_count"`
-	FollowersCount int              `json:"followers_count"`
-	StateName      string           `json:"state_name,omitempty"`
-	CollegeName    string           `json:"college_name,omitempty"`
+	User                *store.User      `json:"user"`
+	CompletedTasks      []store.FeedItem `json:"completed_tasks"`       // Most recent completed tasks (preview)
+	CompletedTasksCount int              `json:"completed_tasks_count"` // Total completed tasks
+	FollowingCount      int              `json:"following_count"`
+	FollowersCount      int              `json:"followers_count"`
+	StateName           string           `json:"state_name,omitempty"`
+	CollegeName         string           `json:"college_name,omitempty"`
+	IsFollowingMe       bool             `json:"is_following_me"` // Profile owner follows the calling user
+	AmFollowing         bool             `json:"am_following"`    // Calling user follows the profile owner
+	IsFollowing         bool             `json:"is_following"`    // Same as AmFollowing; drives the Follow/Unfollow button
 }
 
 // handleGetUser handles getting a user profile by ID with completed tasks, following/followers
 // @Summary      Get user profile
-// @Description  Get a user's complete profile including completed tasks, resume, profile picture, following/followers count, college, and state.
+// @Description  Get a user's complete profile including a preview of the 6 most recent completed tasks with completed_tasks_count, resume, profile picture, following/followers count, college, and state. The full list is at /api/user/{id}/tasks/completed.
 // @Tags         user
 // @Accept       json
 // @Produce      json
@@ -105,6 +191,21 @@ func handleGetUser(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
+		// Hide the profile if either user has blocked the other
+		if viewerID, ok := GetUserIDFromContext(ctx); ok && viewerID != userID {
+			blocked, err := userStore.IsBlockedBetween(ctx, viewerID, userID)
+			if err != nil {
+				log.Printf("Error checking block relationship: %v", err)
+			} else if blocked {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"message": "Profile not available",
+				})
+				return
+			}
+		}
+
 		// Get following and followers count
 		followingCount, err := userStore.GetFollowingCount(ctx, userID)
 		if err != nil {
@@ -118,8 +219,8 @@ func handleGetUser(postgres *db.Postgres) http.HandlerFunc {
 			followersCount = 0
 		}
 
-		// Get completed tasks (feed items) for this user
-		completedTasks, _, err := feedStore.GetUserFeed(ctx, userID, 1, 50) // Get first 50 completed tasks
+		// Get a preview of completed tasks (feed items) for this user
+		completedTasks, completedTasksCount, err := feedStore.GetUserFeed(ctx, userID, 1, profileCompletedTasksPreview)
 		if err != nil {
 			log.Printf("Error getting user feed: %v", err)
 			completedTasks = []store.FeedItem{}
@@ -145,12 +246,32 @@ func handleGetUser(postgres *db.Postgres) http.HandlerFunc {
 
 		// Build profile response
 		profile := UserProfile{
-			User:           user,
-			CompletedTasks: completedTasks,
-			FollowingCount: followingCount,
-			FollowersCount: followersCount,
-			StateName:      stateName,
-			CollegeName:    collegeName,
+			User:                user,
+			CompletedTasks:      completedTasks,
+			CompletedTasksCount: completedTasksCount,
+			FollowingCount:      followingCount,
+			FollowersCount:      followersCount,
+			StateName:           stateName,
+			CollegeName:         collegeName,
+		}
+
+		// Follow relationship from the calling user's perspective
+		if viewerID, ok := GetUserIDFromContext(ctx); ok && viewerID != userID {
+			mutual, err := userStore.IsMutualFollow(ctx, viewerID, userID)
+			if err != nil {
+				log.Printf("Error checking mutual follow: %v", err)
+			} else if mutual {
+				profile.AmFollowing = true
+				profile.IsFollowingMe = true
+			} else {
+				if profile.AmFollowing, err = userStore.IsFollowing(ctx, viewerID, userID); err != nil {
+					log.Printf("Error checking following: %v", err)
+				}
+				if profile.IsFollowingMe, err = userStore.IsFollowing(ctx, userID, viewerID); err != nil {
+					log.Printf("Error checking follower: %v", err)
+				}
+			}
+			profile.IsFollowing = profile.AmFollowing
 		}
 
 		// Return response
@@ -164,9 +285,18 @@ func handleGetUser(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// FollowListResponse is one page of a user's followers or following
+type FollowListResponse struct {
+	Users      []store.FollowUserInfo `json:"users"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	TotalPages int                    `json:"total_pages"`
+}
+
 // handleGetFollowers returns the list of users who follow the given user. Works for any user ID.
 // @Summary      Get followers
-// @Description  Get list of users who follow the specified user. Generic – works for any user ID. Paginated.
+// @Description  Get list of users who follow the specified user. Generic – works for any user ID. Paginated. Users the caller has blocked or been blocked by are left out.
 // @Tags         user
 // @Accept       json
 // @Produce      json
@@ -174,17 +304,17 @@ func handleGetUser(postgres *db.Postgres) http.HandlerFunc {
 // @Param        id         path      string  true   "User ID whose followers to fetch"
 // @Param        page       query     int     false  "Page number (default 1)"
 // @Param        page_size  query     int     false  "Items per page (default 50, max 100)"
-// @Success      200        {array}   store.FollowUserInfo  "List of followers"
+// @Success      200        {object}  FollowListResponse  "Page of followers"
 // @Failure      400        {string}  string  "Bad request – user ID required"
 // @Failure      404        {string}  string  "User not found"
 // @Failure      401        {string}  string  "Unauthorized"
 // @Failure      500        {string}  string  "Internal server error"
 // @Router       /api/user/{id}/followers [get]
-func handleGetFollowers(postgres *db.Postgres) http.HandlerFunc {
+func handleGetFollowers(userStore store.UserStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		_, ok := GetUserIDFromContext(ctx)
+		viewerID, ok := GetUserIDFromContext(ctx)
 		if !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -215,14 +345,13 @@ func handleGetFollowers(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		userStore := store.NewUserStore(postgres)
 		_, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
 			http.Error(w, "User not found", http.StatusNotFound)
 			return
 		}
 
-		followers, err := userStore.GetFollowers(ctx, userID, pageSize, offset)
+		followers, total, err := userStore.GetFollowers(ctx, userID, viewerID, pageSize, offset)
 		if err != nil {
 			log.Printf("Error getting followers: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to get followers: %v", err), http.StatusInternalServerError)
@@ -235,13 +364,19 @@ func handleGetFollowers(postgres *db.Postgres) http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(followers)
+		_ = json.NewEncoder(w).Encode(FollowListResponse{
+			Users:      followers,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: (total + pageSize - 1) / pageSize,
+		})
 	}
 }
 
 // handleGetFollowing returns the list of users that the given user follows. Works for any user ID.
 // @Summary      Get following
-// @Description  Get list of users that the specified user follows. Generic – works for any user ID. Paginated.
+// @Description  Get list of users that the specified user follows. Generic – works for any user ID. Paginated. Users the caller has blocked or been blocked by are left out.
 // @Tags         user
 // @Accept       json
 // @Produce      json
@@ -249,17 +384,17 @@ func handleGetFollowers(postgres *db.Postgres) http.HandlerFunc {
 // @Param        id         path      string  true   "User ID whose following list to fetch"
 // @Param        page       query     int     false  "Page number (default 1)"
 // @Param        page_size  query     int     false  "Items per page (default 50, max 100)"
-// @Success      200        {array}   store.FollowUserInfo  "List of following"
+// @Success      200        {object}  FollowListResponse  "Page of followed users"
 // @Failure      400        {string}  string  "Bad request – user ID required"
 // @Failure      404        {string}  string  "User not found"
 // @Failure      401        {string}  string  "Unauthorized"
 // @Failure      500        {string}  string  "Internal server error"
 // @Router       /api/user/{id}/following [get]
-func handleGetFollowing(postgres *db.Postgres) http.HandlerFunc {
+func handleGetFollowing(userStore store.UserStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		_, ok := GetUserIDFromContext(ctx)
+		viewerID, ok := GetUserIDFromContext(ctx)
 		if !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -290,14 +425,13 @@ func handleGetFollowing(postgres *db.Postgres) http.HandlerFunc {
 			offset = 0
 		}
 
-		userStore := store.NewUserStore(postgres)
 		_, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
 			http.Error(w, "User not found", http.StatusNotFound)
 			return
 		}
 
-		following, err := userStore.GetFollowing(ctx, userID, pageSize, offset)
+		following, total, err := userStore.GetFollowing(ctx, userID, viewerID, pageSize, offset)
 		if err != nil {
 			log.Printf("Error getting following: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to get following: %v", err), http.StatusInternalServerError)
@@ -310,7 +444,182 @@ func handleGetFollowing(postgres *db.Postgres) http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(following)
+		_ = json.NewEncoder(w).Encode(FollowListResponse{
+			Users:      following,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: (total + pageSize - 1) / pageSize,
+		})
+	}
+}
+
+// MutualFollowsResponse represents the mutual follows between the calling user and another user
+type MutualFollowsResponse struct {
+	IsMutual bool                   `json:"is_mutual"` // Both users follow each other
+	Users    []store.FollowUserInfo `json:"users"`     // Users followed by both
+	Total    int                    `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+}
+
+// handleGetMutualFollows returns mutual follow status and the users followed by both the caller and the given user
+// @Summary      Get mutual follows
+// @Description  Check whether the authenticated user and the specified user follow each other, and list users that both of them follow. Paginated.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true   "Other user ID"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Items per page (default 50, max 100)"
+// @Success      200        {object}  MutualFollowsResponse  "Mutual follows"
+// @Failure      400        {string}  string  "Bad request – user ID required"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      404        {string}  string  "User not found"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/{id}/mutual-follows [get]
+func handleGetMutualFollows(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		viewerID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		page, pageSize := 1, 50
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
+			}
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+
+		_, err := userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+
+		isMutual, err := userStore.IsMutualFollow(ctx, viewerID, userID)
+		if err != nil {
+			log.Printf("Error checking mutual follow: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get mutual follows: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		users, total, err := userStore.GetMutualFollows(ctx, viewerID, userID, page, pageSize)
+		if err != nil {
+			log.Printf("Error getting mutual follows: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get mutual follows: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := MutualFollowsResponse{
+			IsMutual: isMutual,
+			Users:    users,
+			Total:    total,
+			Page:     page,
+			PageSize: pageSize,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+// FollowingCheckResponse reports whether the caller follows a user
+type FollowingCheckResponse struct {
+	IsFollowing bool `json:"is_following"`
+}
+
+// handleGetFollowingCheck reports whether the authenticated user follows the given user
+// @Summary      Check following
+// @Description  Check whether the authenticated user follows the specified user, without fetching the full followers list. Also served at /api/user/{id}/is-following. Checking yourself returns false.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  FollowingCheckResponse  "Following status"
+// @Failure      400  {string}  string  "Bad request – user ID required"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/{id}/following-check [get]
+// @Router       /api/user/{id}/is-following [get]
+func handleGetFollowingCheck(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		viewerID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		// Users can't follow themselves
+		if userID == viewerID {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(FollowingCheckResponse{IsFollowing: false})
+			return
+		}
+
+		isFollowing, err := userStore.IsFollowing(ctx, viewerID, userID)
+		if err != nil {
+			log.Printf("Error checking following: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to check following: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(FollowingCheckResponse{IsFollowing: isFollowing})
+	}
+}
+
+// handleGetUserCompletedTasks returns a user's completed tasks, newest first
+// @Summary      Get user's completed tasks
+// @Description  Paginated list of a user's approved task submissions (task_title, task_xp, proof_url, created_at) for their profile. Public.
+// @Tags         user
+// @Produce      json
+// @Param        id         path      string  true   "User ID"
+// @Param        page       query     int     false  "Page number (default: 1)"
+// @Param        page_size  query     int     false  "Items per page (default: 20, max: 100)"
+// @Success      200        {object}  FeedResponse  "Completed tasks"
+// @Failure      400        {string}  string  "Bad request"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/{id}/tasks/completed [get]
+func handleGetUserCompletedTasks(feedStore store.FeedStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		serveUserFeed(w, r, feedStore, userID)
 	}
 }
 
@@ -325,6 +634,7 @@ func handleGetFollowing(postgres *db.Postgres) http.HandlerFunc {
 // @Success      200  {object}  map[string]interface{}  "Successfully followed user"
 // @Failure      400  {string}  string  "Bad request - invalid user ID or already following"
 // @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Cannot follow this user (blocked)"
 // @Failure      404  {string}  string  "User not found"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/user/{id}/follow [post]
@@ -346,11 +656,28 @@ func handleFollow(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
+		if followerID == followingID {
+			http.Error(w, "Cannot follow yourself", http.StatusBadRequest)
+			return
+		}
+
 		// Create user store
 		userStore := store.NewUserStore(postgres)
 
+		// Check if either user has blocked the other
+		blocked, err := userStore.IsBlockedBetween(ctx, followerID, followingID)
+		if err != nil {
+			log.Printf("Error checking block relationship: %v", err)
+			http.Error(w, "Failed to follow user", http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			http.Error(w, "Cannot follow this user", http.StatusForbidden)
+			return
+		}
+
 		// Follow user
-		err := userStore.FollowUser(ctx, followerID, followingID)
+		err = userStore.FollowUser(ctx, followerID, followingID)
 		if err != nil {
 			log.Printf("Error following user: %v", err)
 
@@ -372,21 +699,28 @@ func handleFollow(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		// ============================================================================
-		// TODO: Send WebSocket notification to the user being followed
-		// ============================================================================
-		// Call WebSocket notification function here to notify the user that they have a new follower.
-		// This should be implemented in internal/router/ws/notifications.go
-		//
-		// Example implementation:
-		//   notification := map[string]interface{}{
-		//       "type": "new_follower",
-		//       "follower_id": followerID,
-		//       "follower_name": followerName, // Get from user store if needed
-		//       "timestamp": time.Now(),
-		//   }
-		//   ws.SendNotificationToUser(redisClient, followingID, notification)
-		// ============================================================================
+		// Record onboarding progress
+		onboardingStore := store.NewOnboardingStore(postgres)
+		if _, err := onboardingStore.MarkStep(ctx, followerID, store.OnboardingStepFollowFirstUser); err != nil {
+			log.Printf("Error marking onboarding step: %v", err)
+		}
+
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      followerID,
+			EventType:   store.ActivityEventFollowAdded,
+			EntityID:    followingID,
+			EntityType:  "user",
+			Description: "Followed " + followedUserName(ctx, userStore, followingID),
+		})
+
+		// Notify the user being followed
+		if wsHub := ws.GetNotificationHub(); wsHub != nil {
+			if follower, err := userStore.GetUserByID(ctx, followerID); err == nil {
+				if err := ws.SendNewFollowerNotification(wsHub, followingID, followerID, follower.Name); err != nil {
+					log.Printf("Error sending new follower notification: %v", err)
+				}
+			}
+		}
 
 		// Return success response
 		response := map[string]interface{}{
@@ -404,6 +738,15 @@ func handleFollow(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// followedUserName returns the user's name for the activity log, or "a user" if it can't be loaded
+func followedUserName(ctx context.Context, userStore *store.UserStore, userID string) string {
+	user, err := userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return "a user"
+	}
+	return user.Name
+}
+
 // handleUnfollow handles unfollowing a user
 // @Summary      Unfollow user
 // @Description  Unfollow a user. The authenticated user will unfollow the user specified in the URL path.
@@ -436,38 +779,238 @@ func handleUnfollow(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		// Create user store
-		userStore := store.NewUserStore(postgres)
-
-		// Unfollow user
-		err := userStore.UnfollowUser(ctx, followerID, followingID)
-		if err != nil {
-			log.Printf("Error unfollowing user: %v", err)
-
-			// Check for specific errors
-			if err.Error() == "cannot unfollow yourself" {
-				http.Error(w, "Cannot unfollow yourself", http.StatusBadRequest)
-				return
+		// Create user store
+		userStore := store.NewUserStore(postgres)
+
+		// Unfollow user
+		err := userStore.UnfollowUser(ctx, followerID, followingID)
+		if err != nil {
+			log.Printf("Error unfollowing user: %v", err)
+
+			// Check for specific errors
+			if err.Error() == "cannot unfollow yourself" {
+				http.Error(w, "Cannot unfollow yourself", http.StatusBadRequest)
+				return
+			}
+			if err.Error() == "not following this user" {
+				http.Error(w, "Not following this user", http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, fmt.Sprintf("Failed to unfollow user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		recordActivity(ctx, postgres, store.RecordActivityRequest{
+			UserID:      followerID,
+			EventType:   store.ActivityEventFollowRemoved,
+			EntityID:    followingID,
+			EntityType:  "user",
+			Description: "Unfollowed " + followedUserName(ctx, userStore, followingID),
+		})
+
+		// Return success response
+		response := map[string]interface{}{
+			"message":      "Successfully unfollowed user",
+			"following_id": followingID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding unfollow response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleBlockUser handles blocking a user
+// @Summary      Block user
+// @Description  Block another user. Removes any follow relationship in both directions; blocked users cannot follow or view each other's profile.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "User ID to block"
+// @Success      200  {object}  map[string]interface{}  "Successfully blocked user"
+// @Failure      400  {string}  string  "Bad request - invalid user ID or already blocked"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/{id}/block [post]
+func handleBlockUser(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Get blocker ID from context (set by JWT middleware)
+		blockerID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Get blocked ID from URL path
+		blockedID := chi.URLParam(r, "id")
+		if blockedID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		// Block user
+		err := userStore.BlockUser(ctx, blockerID, blockedID)
+		if err != nil {
+			log.Printf("Error blocking user: %v", err)
+
+			// Check for specific errors
+			if err.Error() == "cannot block yourself" {
+				http.Error(w, "Cannot block yourself", http.StatusBadRequest)
+				return
+			}
+			if err.Error() == "already blocked this user" {
+				http.Error(w, "Already blocked this user", http.StatusBadRequest)
+				return
+			}
+			if strings.HasPrefix(err.Error(), "user to block not found") {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+
+			http.Error(w, fmt.Sprintf("Failed to block user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Return success response
+		response := map[string]interface{}{
+			"message":    "Successfully blocked user",
+			"blocked_id": blockedID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding block response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleUnblockUser handles unblocking a user
+// @Summary      Unblock user
+// @Description  Unblock a previously blocked user.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "User ID to unblock"
+// @Success      200  {object}  map[string]interface{}  "Successfully unblocked user"
+// @Failure      400  {string}  string  "Bad request - invalid user ID or not blocked"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/{id}/unblock [post]
+func handleUnblockUser(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Get blocker ID from context (set by JWT middleware)
+		blockerID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Get blocked ID from URL path
+		blockedID := chi.URLParam(r, "id")
+		if blockedID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		// Unblock user
+		err := userStore.UnblockUser(ctx, blockerID, blockedID)
+		if err != nil {
+			log.Printf("Error unblocking user: %v", err)
+
+			// Check for specific errors
+			if err.Error() == "cannot unblock yourself" {
+				http.Error(w, "Cannot unblock yourself", http.StatusBadRequest)
+				return
+			}
+			if err.Error() == "not blocking this user" {
+				http.Error(w, "Not blocking this user", http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, fmt.Sprintf("Failed to unblock user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Return success response
+		response := map[string]interface{}{
+			"message":    "Successfully unblocked user",
+			"blocked_id": blockedID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding unblock response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetBlockedUsers returns the users the current user has blocked
+// @Summary      Get blocked users
+// @Description  Get the users the authenticated user has blocked, most recently blocked first. Paginated.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        page       query     int  false  "Page number (default 1)"
+// @Param        page_size  query     int  false  "Items per page (default 50, max 100)"
+// @Success      200        {array}   store.FollowUserInfo  "Blocked users"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /api/user/me/blocked [get]
+func handleGetBlockedUsers(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		page, pageSize := 1, 50
+		if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
 			}
-			if err.Error() == "not following this user" {
-				http.Error(w, "Not following this user", http.StatusBadRequest)
-				return
+		}
+		if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
 			}
-
-			http.Error(w, fmt.Sprintf("Failed to unfollow user: %v", err), http.StatusInternalServerError)
-			return
 		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+		offset := (page - 1) * pageSize
 
-		// Return success response
-		response := map[string]interface{}{
-			"message":      "Successfully unfollowed user",
-			"following_id": followingID,
+		blocked, err := userStore.GetBlockedUsers(ctx, userID, pageSize, offset)
+		if err != nil {
+			log.Printf("Error getting blocked users: %v", err)
+			http.Error(w, "Failed to get blocked users", http.StatusInternalServerError)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			log.Printf("Error encoding unfollow response: %v", err)
+		if err := json.NewEncoder(w).Encode(blocked); err != nil {
+			log.Printf("Error encoding blocked users response: %v", err)
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
 		}
@@ -487,7 +1030,7 @@ func handleUnfollow(postgres *db.Postgres) http.HandlerFunc {
 // @Failure      401     {string}  string  "Unauthorized"
 // @Failure      500     {string}  string  "Internal server error"
 // @Router       /api/user/resume [post]
-func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleUploadResume(userStore store.UserStoreInterface, onboardingStore store.OnboardingStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -499,7 +1042,6 @@ func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		}
 
 		// Get user to check if resume already exists
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user: %v", err)
@@ -515,13 +1057,15 @@ func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:           cfg.AWSRegion,
-			ProfileBucket:    cfg.AWSProfileBucket,
-			ResumeBucket:     cfg.AWSResumeBucket,
-			AccessKeyID:      cfg.AWSAccessKeyID,
-			SecretAccessKey:  cfg.AWSSecretAccessKey,
-			ProfilePublicURL: cfg.AWSProfilePublicURL,
-			ResumePublicURL:  cfg.AWSResumePublicURL,
+			Region:              cfg.AWSRegion,
+			ProfileBucket:       cfg.AWSProfileBucket,
+			ResumeBucket:        cfg.AWSResumeBucket,
+			AccessKeyID:         cfg.AWSAccessKeyID,
+			SecretAccessKey:     cfg.AWSSecretAccessKey,
+			ProfilePublicURL:    cfg.AWSProfilePublicURL,
+			ResumePublicURL:     cfg.AWSResumePublicURL,
+			ProfileBucketRegion: cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:  cfg.AWSResumeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -545,7 +1089,7 @@ func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		defer resumeFile.Close()
 
 		// Upload resume to S3
-		resumeURL, err := s3Storage.UploadResume(ctx, resumeFile, userID, resumeHeader.Filename)
+		resumeURL, resumeKey, err := s3Storage.UploadResume(ctx, resumeFile, userID, resumeHeader.Filename)
 		if err != nil {
 			log.Printf("Error uploading resume: %v", err)
 			http.Error(w, "Failed to upload resume", http.StatusInternalServerError)
@@ -553,16 +1097,20 @@ func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		}
 
 		// Update user's resume URL in database
-		err = userStore.UpdateResumeURL(ctx, userID, resumeURL)
+		err = userStore.UpdateResumeURL(ctx, userID, resumeURL, resumeKey)
 		if err != nil {
 			log.Printf("Error updating resume URL: %v", err)
 			// Try to delete uploaded file
-			key := extractS3KeyFromURL(resumeURL)
-			_ = s3Storage.DeleteResume(ctx, key)
+			_ = s3Storage.DeleteResume(ctx, resumeKey)
 			http.Error(w, "Failed to update resume URL", http.StatusInternalServerError)
 			return
 		}
 
+		// Record onboarding progress
+		if _, err := onboardingStore.MarkStep(ctx, userID, store.OnboardingStepUploadResume); err != nil {
+			log.Printf("Error marking onboarding step: %v", err)
+		}
+
 		// Get updated user
 		updatedUser, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
@@ -594,7 +1142,7 @@ func handleUploadResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 // @Failure      401     {string}  string  "Unauthorized"
 // @Failure      500     {string}  string  "Internal server error"
 // @Router       /api/user/resume [put]
-func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleUpdateResume(userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -605,9 +1153,8 @@ func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 			return
 		}
 
-		// Get user to get existing resume URL
-		userStore := store.NewUserStore(postgres)
-		user, err := userStore.GetUserByID(ctx, userID)
+		// Get the stored key of the existing resume
+		oldResumeKey, _, err := userStore.GetUserS3Keys(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user: %v", err)
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -616,13 +1163,15 @@ func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:           cfg.AWSRegion,
-			ProfileBucket:    cfg.AWSProfileBucket,
-			ResumeBucket:     cfg.AWSResumeBucket,
-			AccessKeyID:      cfg.AWSAccessKeyID,
-			SecretAccessKey:  cfg.AWSSecretAccessKey,
-			ProfilePublicURL: cfg.AWSProfilePublicURL,
-			ResumePublicURL:  cfg.AWSResumePublicURL,
+			Region:              cfg.AWSRegion,
+			ProfileBucket:       cfg.AWSProfileBucket,
+			ResumeBucket:        cfg.AWSResumeBucket,
+			AccessKeyID:         cfg.AWSAccessKeyID,
+			SecretAccessKey:     cfg.AWSSecretAccessKey,
+			ProfilePublicURL:    cfg.AWSProfilePublicURL,
+			ResumePublicURL:     cfg.AWSResumePublicURL,
+			ProfileBucketRegion: cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:  cfg.AWSResumeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -646,7 +1195,7 @@ func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		defer resumeFile.Close()
 
 		// Upload new resume to S3
-		newResumeURL, err := s3Storage.UploadResume(ctx, resumeFile, userID, resumeHeader.Filename)
+		newResumeURL, newResumeKey, err := s3Storage.UploadResume(ctx, resumeFile, userID, resumeHeader.Filename)
 		if err != nil {
 			log.Printf("Error uploading resume: %v", err)
 			http.Error(w, "Failed to upload resume", http.StatusInternalServerError)
@@ -654,20 +1203,18 @@ func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 		}
 
 		// Update user's resume URL in database
-		err = userStore.UpdateResumeURL(ctx, userID, newResumeURL)
+		err = userStore.UpdateResumeURL(ctx, userID, newResumeURL, newResumeKey)
 		if err != nil {
 			log.Printf("Error updating resume URL: %v", err)
 			// Try to delete uploaded file
-			key := extractS3KeyFromURL(newResumeURL)
-			_ = s3Storage.DeleteResume(ctx, key)
+			_ = s3Storage.DeleteResume(ctx, newResumeKey)
 			http.Error(w, "Failed to update resume URL", http.StatusInternalServerError)
 			return
 		}
 
-		// Delete old resume from S3 if it exists
-		if user.ResumeURL != "" {
-			oldKey := extractS3KeyFromURL(user.ResumeURL)
-			_ = s3Storage.DeleteResume(ctx, oldKey)
+		// Delete old resume from S3 if it exists (same key means the upload already overwrote it)
+		if oldResumeKey != "" && oldResumeKey != newResumeKey {
+			_ = s3Storage.DeleteResume(ctx, oldResumeKey)
 		}
 
 		// Get updated user
@@ -688,20 +1235,171 @@ func handleUpdateResume(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc
 	}
 }
 
+// resumeDownloadURLExpiry is how long a resume download link stays valid
+const resumeDownloadURLExpiry = 15 * time.Minute
+
+// UpdateResumeVisibilityRequest is the body of the resume visibility endpoint
+type UpdateResumeVisibilityRequest struct {
+	Visibility string `json:"visibility"` // public, private or college_only
+}
+
+// handleUpdateResumeVisibility changes who can download the current user's resume
+// @Summary      Update resume visibility
+// @Description  Set who can download the authenticated user's resume: public (anyone), private (only the user) or college_only (signed-in users of the same college).
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      UpdateResumeVisibilityRequest  true  "New visibility"
+// @Success      200      {object}  map[string]string  "Visibility updated"
+// @Failure      400      {string}  string  "Bad request - invalid visibility"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      404      {string}  string  "User not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /api/user/resume/visibility [patch]
+func handleUpdateResumeVisibility(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req UpdateResumeVisibilityRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if !store.IsValidResumeVisibility(req.Visibility) {
+			http.Error(w, "visibility must be public, private or college_only", http.StatusBadRequest)
+			return
+		}
+
+		if err := userStore.UpdateResumeVisibility(ctx, userID, req.Visibility); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error updating resume visibility: %v", err)
+			http.Error(w, "Failed to update resume visibility", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"resume_visibility": req.Visibility,
+		})
+	}
+}
+
+// handleDownloadResume redirects to a short-lived download link for a user's resume
+// @Summary      Download resume
+// @Description  Redirect to a 15 minute download link for the user's resume. The owner can always download it; others only when the resume is public, or college_only and the caller is signed in and in the same college. JWT optional.
+// @Tags         user
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      302  {string}  string  "Redirect to the resume download link"
+// @Failure      401  {string}  string  "Sign in required to download this resume"
+// @Failure      403  {string}  string  "Resume is not shared with the caller"
+// @Failure      404  {string}  string  "User or resume not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/{id}/resume/download [get]
+func handleDownloadResume(userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		targetUserID := chi.URLParam(r, "id")
+		if targetUserID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		owner, err := userStore.GetUserByID(ctx, targetUserID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, "Failed to get resume", http.StatusInternalServerError)
+			return
+		}
+
+		resumeKey, _, err := userStore.GetUserS3Keys(ctx, targetUserID)
+		if err != nil {
+			log.Printf("Error getting resume key: %v", err)
+			http.Error(w, "Failed to get resume", http.StatusInternalServerError)
+			return
+		}
+		if resumeKey == "" {
+			http.Error(w, "Resume not found", http.StatusNotFound)
+			return
+		}
+
+		callerID, signedIn := GetUserIDFromContext(ctx)
+		if callerID != owner.ID && owner.ResumeVisibility != store.ResumeVisibilityPublic {
+			if owner.ResumeVisibility != store.ResumeVisibilityCollegeOnly {
+				http.Error(w, "This resume is private", http.StatusForbidden)
+				return
+			}
+			if !signedIn {
+				http.Error(w, "Sign in to download this resume", http.StatusUnauthorized)
+				return
+			}
+			caller, err := userStore.GetUserByID(ctx, callerID)
+			if err != nil {
+				log.Printf("Error getting caller: %v", err)
+				http.Error(w, "Failed to get resume", http.StatusInternalServerError)
+				return
+			}
+			if caller.CollegeID == "" || caller.CollegeID != owner.CollegeID {
+				http.Error(w, "This resume is only shared within the user's college", http.StatusForbidden)
+				return
+			}
+		}
+
+		s3Storage, err := storage.NewS3Storage(storage.S3Config{
+			Region:             cfg.AWSRegion,
+			ResumeBucket:       cfg.AWSResumeBucket,
+			AccessKeyID:        cfg.AWSAccessKeyID,
+			SecretAccessKey:    cfg.AWSSecretAccessKey,
+			ResumePublicURL:    cfg.AWSResumePublicURL,
+			ResumeBucketRegion: cfg.AWSResumeBucketRegion,
+		})
+		if err != nil {
+			log.Printf("Error initializing S3 storage: %v", err)
+			http.Error(w, "Failed to initialize file storage", http.StatusInternalServerError)
+			return
+		}
+
+		downloadURL, err := s3Storage.GeneratePresignedResumeURL(ctx, resumeKey, resumeDownloadURLExpiry)
+		if err != nil {
+			log.Printf("Error generating resume download URL: %v", err)
+			http.Error(w, "Failed to generate download URL", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, downloadURL, http.StatusFound)
+	}
+}
+
 // handleUploadProfilePic handles uploading a user's profile picture (for users who didn't upload during registration)
 // @Summary      Upload profile picture
-// @Description  Upload a profile picture for the authenticated user. Only works if user hasn't uploaded a profile picture during registration.
+// @Description  Upload a profile picture for the authenticated user. Only works if user hasn't uploaded a profile picture during registration. The file content must be a JPEG, PNG, GIF or WebP image; it is scaled down to fit 800x800.
 // @Tags         user
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
-// @Param        profile_pic  formData  file  true  "Profile picture (JPG/PNG)"
+// @Param        profile_pic  formData  file  true  "Profile picture (JPG/PNG/GIF/WEBP)"
 // @Success      200          {object}  store.User  "Profile picture uploaded successfully"
 // @Failure      400          {string}  string  "Bad request - user already has a profile picture or invalid file"
 // @Failure      401          {string}  string  "Unauthorized"
 // @Failure      500          {string}  string  "Internal server error"
 // @Router       /api/user/profile-pic [post]
-func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleUploadProfilePic(userStore store.UserStoreInterface, onboardingStore store.OnboardingStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -713,7 +1411,6 @@ func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 		}
 
 		// Get user to check if profile pic already exists
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user: %v", err)
@@ -729,13 +1426,15 @@ func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:           cfg.AWSRegion,
-			ProfileBucket:    cfg.AWSProfileBucket,
-			ResumeBucket:     cfg.AWSResumeBucket,
-			AccessKeyID:      cfg.AWSAccessKeyID,
-			SecretAccessKey:  cfg.AWSSecretAccessKey,
-			ProfilePublicURL: cfg.AWSProfilePublicURL,
-			ResumePublicURL:  cfg.AWSResumePublicURL,
+			Region:              cfg.AWSRegion,
+			ProfileBucket:       cfg.AWSProfileBucket,
+			ResumeBucket:        cfg.AWSResumeBucket,
+			AccessKeyID:         cfg.AWSAccessKeyID,
+			SecretAccessKey:     cfg.AWSSecretAccessKey,
+			ProfilePublicURL:    cfg.AWSProfilePublicURL,
+			ResumePublicURL:     cfg.AWSResumePublicURL,
+			ProfileBucketRegion: cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:  cfg.AWSResumeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -751,15 +1450,31 @@ func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 		}
 
 		// Get profile picture file
-		profilePicFile, profilePicHeader, err := r.FormFile("profile_pic")
+		profilePicFile, _, err := r.FormFile("profile_pic")
 		if err != nil {
 			http.Error(w, "Profile picture file is required", http.StatusBadRequest)
 			return
 		}
 		defer profilePicFile.Close()
 
+		// Check the content really is an image and scale it down before uploading
+		profilePic, contentType, err := storage.ValidateAndProcessImage(profilePicFile, storage.ProfilePicMaxWidth, storage.ProfilePicMaxHeight)
+		if err != nil {
+			if errors.Is(err, storage.ErrUnsupportedImageType) {
+				http.Error(w, "unsupported image type", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, storage.ErrInvalidImage) {
+				http.Error(w, "Invalid image", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error processing profile picture: %v", err)
+			http.Error(w, "Failed to process profile picture", http.StatusInternalServerError)
+			return
+		}
+
 		// Upload profile picture to S3
-		profilePicURL, err := s3Storage.UploadProfilePic(ctx, profilePicFile, userID, profilePicHeader.Filename)
+		profilePicURL, profilePicKey, err := s3Storage.UploadProfilePic(ctx, profilePic, userID, "profile"+storage.ImageExtension(contentType))
 		if err != nil {
 			log.Printf("Error uploading profile picture: %v", err)
 			http.Error(w, "Failed to upload profile picture", http.StatusInternalServerError)
@@ -767,16 +1482,20 @@ func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 		}
 
 		// Update user's profile picture URL in database
-		err = userStore.UpdateProfilePicURL(ctx, userID, profilePicURL)
+		err = userStore.UpdateProfilePicURL(ctx, userID, profilePicURL, profilePicKey)
 		if err != nil {
 			log.Printf("Error updating profile picture URL: %v", err)
 			// Try to delete uploaded file
-			key := extractS3KeyFromURL(profilePicURL)
-			_ = s3Storage.DeleteProfilePic(ctx, key)
+			_ = s3Storage.DeleteProfilePic(ctx, profilePicKey)
 			http.Error(w, "Failed to update profile picture URL", http.StatusInternalServerError)
 			return
 		}
 
+		// Record onboarding progress
+		if _, err := onboardingStore.MarkStep(ctx, userID, store.OnboardingStepUploadProfilePic); err != nil {
+			log.Printf("Error marking onboarding step: %v", err)
+		}
+
 		// Get updated user
 		updatedUser, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
@@ -797,18 +1516,18 @@ func handleUploadProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 
 // handleUpdateProfilePic handles updating a user's existing profile picture
 // @Summary      Update profile picture
-// @Description  Update the profile picture for the authenticated user. Replaces existing profile picture.
+// @Description  Update the profile picture for the authenticated user. Replaces existing profile picture. The file content must be a JPEG, PNG, GIF or WebP image; it is scaled down to fit 800x800.
 // @Tags         user
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
-// @Param        profile_pic  formData  file  true  "Profile picture (JPG/PNG)"
+// @Param        profile_pic  formData  file  true  "Profile picture (JPG/PNG/GIF/WEBP)"
 // @Success      200          {object}  store.User  "Profile picture updated successfully"
 // @Failure      400          {string}  string  "Bad request - invalid file"
 // @Failure      401          {string}  string  "Unauthorized"
 // @Failure      500          {string}  string  "Internal server error"
 // @Router       /api/user/profile-pic [put]
-func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.HandlerFunc {
+func handleUpdateProfilePic(userStore store.UserStoreInterface, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -819,9 +1538,8 @@ func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 			return
 		}
 
-		// Get user to get existing profile pic URL
-		userStore := store.NewUserStore(postgres)
-		user, err := userStore.GetUserByID(ctx, userID)
+		// Get the stored key of the existing profile picture
+		_, oldProfilePicKey, err := userStore.GetUserS3Keys(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user: %v", err)
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -830,13 +1548,15 @@ func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 
 		// Initialize S3 storage
 		s3Storage, err := storage.NewS3Storage(storage.S3Config{
-			Region:           cfg.AWSRegion,
-			ProfileBucket:    cfg.AWSProfileBucket,
-			ResumeBucket:     cfg.AWSResumeBucket,
-			AccessKeyID:      cfg.AWSAccessKeyID,
-			SecretAccessKey:  cfg.AWSSecretAccessKey,
-			ProfilePublicURL: cfg.AWSProfilePublicURL,
-			ResumePublicURL:  cfg.AWSResumePublicURL,
+			Region:              cfg.AWSRegion,
+			ProfileBucket:       cfg.AWSProfileBucket,
+			ResumeBucket:        cfg.AWSResumeBucket,
+			AccessKeyID:         cfg.AWSAccessKeyID,
+			SecretAccessKey:     cfg.AWSSecretAccessKey,
+			ProfilePublicURL:    cfg.AWSProfilePublicURL,
+			ResumePublicURL:     cfg.AWSResumePublicURL,
+			ProfileBucketRegion: cfg.AWSProfileBucketRegion,
+			ResumeBucketRegion:  cfg.AWSResumeBucketRegion,
 		})
 		if err != nil {
 			log.Printf("Error initializing S3 storage: %v", err)
@@ -852,15 +1572,31 @@ func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 		}
 
 		// Get profile picture file
-		profilePicFile, profilePicHeader, err := r.FormFile("profile_pic")
+		profilePicFile, _, err := r.FormFile("profile_pic")
 		if err != nil {
 			http.Error(w, "Profile picture file is required", http.StatusBadRequest)
 			return
 		}
 		defer profilePicFile.Close()
 
+		// Check the content really is an image and scale it down before uploading
+		profilePic, contentType, err := storage.ValidateAndProcessImage(profilePicFile, storage.ProfilePicMaxWidth, storage.ProfilePicMaxHeight)
+		if err != nil {
+			if errors.Is(err, storage.ErrUnsupportedImageType) {
+				http.Error(w, "unsupported image type", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, storage.ErrInvalidImage) {
+				http.Error(w, "Invalid image", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error processing profile picture: %v", err)
+			http.Error(w, "Failed to process profile picture", http.StatusInternalServerError)
+			return
+		}
+
 		// Upload new profile picture to S3
-		newProfilePicURL, err := s3Storage.UploadProfilePic(ctx, profilePicFile, userID, profilePicHeader.Filename)
+		newProfilePicURL, newProfilePicKey, err := s3Storage.UploadProfilePic(ctx, profilePic, userID, "profile"+storage.ImageExtension(contentType))
 		if err != nil {
 			log.Printf("Error uploading profile picture: %v", err)
 			http.Error(w, "Failed to upload profile picture", http.StatusInternalServerError)
@@ -868,20 +1604,18 @@ func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 		}
 
 		// Update user's profile picture URL in database
-		err = userStore.UpdateProfilePicURL(ctx, userID, newProfilePicURL)
+		err = userStore.UpdateProfilePicURL(ctx, userID, newProfilePicURL, newProfilePicKey)
 		if err != nil {
 			log.Printf("Error updating profile picture URL: %v", err)
 			// Try to delete uploaded file
-			key := extractS3KeyFromURL(newProfilePicURL)
-			_ = s3Storage.DeleteProfilePic(ctx, key)
+			_ = s3Storage.DeleteProfilePic(ctx, newProfilePicKey)
 			http.Error(w, "Failed to update profile picture URL", http.StatusInternalServerError)
 			return
 		}
 
-		// Delete old profile picture from S3 if it exists
-		if user.AvatarURL != "" {
-			oldKey := extractS3KeyFromURL(user.AvatarURL)
-			_ = s3Storage.DeleteProfilePic(ctx, oldKey)
+		// Delete old profile picture from S3 if it exists (same key means the upload already overwrote it)
+		if oldProfilePicKey != "" && oldProfilePicKey != newProfilePicKey {
+			_ = s3Storage.DeleteProfilePic(ctx, oldProfilePicKey)
 		}
 
 		// Get updated user
@@ -913,7 +1647,7 @@ func handleUpdateProfilePic(postgres *db.Postgres, cfg *env.Config) http.Handler
 // @Failure      401  {string}  string  "Unauthorized"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/user/badges [get]
-func handleGetMyBadges(postgres *db.Postgres) http.HandlerFunc {
+func handleGetMyBadges(badgeStore store.BadgeStoreInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -925,7 +1659,6 @@ func handleGetMyBadges(postgres *db.Postgres) http.HandlerFunc {
 		}
 
 		// Get user badges
-		badgeStore := store.NewBadgeStore(postgres)
 		badges, err := badgeStore.GetUserBadges(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user badges: %v", err)
@@ -1015,6 +1748,9 @@ func handleGetMyTaskHistory(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// selfXPDailyCallLimit is the maximum number of POST /api/user/xp calls per user per day
+const selfXPDailyCallLimit = 3
+
 // UserAddXPRequest is the body for adding XP to own account (user only, not admin).
 type UserAddXPRequest struct {
 	XP     int    `json:"xp"`
@@ -1023,18 +1759,19 @@ type UserAddXPRequest struct {
 
 // handleAddXPForUser adds XP to the authenticated user's own account. User-only route (no admin).
 // @Summary      Add XP to my account
-// @Description  Add XP to your own account. JWT required. Use for redeeming codes, claiming rewards, etc. Logs in xp_logs (source user_add) and broadcasts leaderboard update.
+// @Description  Add XP to your own account. JWT required. Limited to 3 calls per day and MAX_SELF_XP_PER_CALL XP per call. When REQUIRE_XP_CODE=true, reason must be a valid promo code and the code's XP amount is awarded. Logs in xp_logs (source user_add) and broadcasts leaderboard update.
 // @Tags         user
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        body  body  UserAddXPRequest  true  "xp (required), optional reason"
+// @Param        body  body  UserAddXPRequest  true  "xp (required unless redeeming a code), optional reason or code"
 // @Success      200   {object}  map[string]interface{}  "xp_awarded, new_total_xp, xp_log_id"
 // @Failure      400   {string}  string  "Bad request"
 // @Failure      401   {string}  string  "Unauthorized"
+// @Failure      429   {string}  string  "Too many requests - daily limit reached"
 // @Failure      500   {string}  string  "Internal server error"
 // @Router       /api/user/xp [post]
-func handleAddXPForUser(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+func handleAddXPForUser(xpCodeStore store.XPCodeStoreInterface, xpStore store.XPStoreInterface, userStore store.UserStoreInterface, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -1049,43 +1786,91 @@ func handleAddXPForUser(postgres *db.Postgres, redisClient *db.Redis) http.Handl
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
 			return
 		}
-		if req.XP <= 0 {
-			http.Error(w, "xp must be greater than 0", http.StatusBadRequest)
-			return
+		if cfg.RequireXPCode {
+			if req.Reason == "" {
+				http.Error(w, "reason must be a valid XP code", http.StatusBadRequest)
+				return
+			}
+		} else {
+			if req.XP <= 0 {
+				http.Error(w, "xp must be greater than 0", http.StatusBadRequest)
+				return
+			}
+			if req.XP > cfg.MaxSelfXPPerCall {
+				http.Error(w, fmt.Sprintf("xp must not exceed %d per call", cfg.MaxSelfXPPerCall), http.StatusBadRequest)
+				return
+			}
 		}
 
-		xpStore := store.NewXPStore(postgres)
-		xpLog, err := xpStore.AwardXP(ctx, store.AwardXPRequest{
-			UserID:   userID,
-			XP:       req.XP,
-			Source:   store.XPSourceUserAdd,
-			SourceID: req.Reason,
-		})
+		// Rate limit: selfXPDailyCallLimit calls per 24 hours per user
+		if redisClient != nil {
+			rateLimitKey := fmt.Sprintf("user_xp_calls:%s", userID)
+			calls, err := redisClient.Client.Incr(ctx, rateLimitKey).Result()
+			if err != nil {
+				log.Printf("Error checking self XP rate limit: %v", err)
+				http.Error(w, "Failed to add XP", http.StatusInternalServerError)
+				return
+			}
+			if calls == 1 {
+				redisClient.Client.Expire(ctx, rateLimitKey, 24*time.Hour)
+			}
+			if calls > selfXPDailyCallLimit {
+				http.Error(w, "Daily XP limit reached. Try again tomorrow.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		awardReq := store.AwardXPRequest{
+			UserID: userID,
+			XP:     req.XP,
+			Source: store.XPSourceUserAdd,
+			Reason: req.Reason,
+		}
+
+		// Redeem promo code when required
+		if cfg.RequireXPCode {
+			xpCode, err := xpCodeStore.RedeemXPCode(ctx, req.Reason, userID)
+			if err != nil {
+				switch err.Error() {
+				case "invalid code":
+					http.Error(w, "Invalid XP code", http.StatusBadRequest)
+				case "code expired":
+					http.Error(w, "XP code has expired", http.StatusBadRequest)
+				case "code fully redeemed":
+					http.Error(w, "XP code has no uses left", http.StatusBadRequest)
+				case "code already redeemed":
+					http.Error(w, "You have already redeemed this XP code", http.StatusBadRequest)
+				default:
+					log.Printf("Error redeeming XP code for user %s: %v", userID, err)
+					http.Error(w, fmt.Sprintf("Failed to redeem XP code: %v", err), http.StatusInternalServerError)
+				}
+				return
+			}
+			awardReq.XP = xpCode.XPAmount
+			awardReq.SourceID = xpCode.ID
+		}
+
+		xpLog, err := xpStore.AwardXP(ctx, awardReq)
 		if err != nil {
+			if errors.Is(err, store.ErrDailyCapExceeded) {
+				http.Error(w, "Daily XP limit reached, try again tomorrow", http.StatusTooManyRequests)
+				return
+			}
 			log.Printf("Error adding XP for user %s: %v", userID, err)
 			http.Error(w, fmt.Sprintf("Failed to add XP: %v", err), http.StatusInternalServerError)
 			return
 		}
+		notifyLevelUp(xpLog)
 
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user after XP award: %v", err)
 		} else if redisClient != nil {
-			leaderboardStore := store.NewLeaderboardStore(postgres)
-			rank, _ := leaderboardStore.GetUserRank(ctx, userID)
-			newXP := user.XP
-			ws.BroadcastLeaderboardUpdate(redisClient, "pan-india", "", userID, rank, newXP)
-			if user.StateID != "" {
-				ws.BroadcastLeaderboardUpdate(redisClient, "state", user.StateID, userID, rank, newXP)
-			}
-			if user.CollegeID != "" {
-				ws.BroadcastLeaderboardUpdate(redisClient, "college", user.CollegeID, userID, rank, newXP)
-			}
+			ws.BroadcastUserXPChange(ctx, redisClient, user)
 		}
 
 		response := map[string]interface{}{
-			"xp_awarded": req.XP,
+			"xp_awarded": xpLog.XP,
 			"xp_log_id":  xpLog.ID,
 		}
 		if user != nil {
@@ -1101,16 +1886,17 @@ func handleAddXPForUser(postgres *db.Postgres, redisClient *db.Redis) http.Handl
 // handleStreakCheckIn records a daily check-in and updates the user's streak.
 // Call when the user opens the app / checks in for the day. Same day repeated calls are idempotent.
 // @Summary      Daily streak check-in
-// @Description  Record a daily check-in to the app. Counts toward streak (consecutive days). Same-day calls are idempotent. Returns current streak_days and streak_started_at.
+// @Description  Record a daily check-in to the app. Counts toward streak (consecutive days). Same-day calls are idempotent. Returns current streak_days, streak_started_at (first day of the current streak) and last_checkin_at. Gated by the "streak" feature flag.
 // @Tags         user
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200  {object}  map[string]interface{}  "Check-in recorded, current streak"
 // @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Streak feature flag is off for this user"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/user/streak/check-in [post]
-func handleStreakCheckIn(postgres *db.Postgres) http.HandlerFunc {
+func handleStreakCheckIn(featureFlags store.FeatureFlagStoreInterface, streakStore store.StreakStoreInterface, redisClient *db.Redis) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -1120,15 +1906,26 @@ func handleStreakCheckIn(postgres *db.Postgres) http.HandlerFunc {
 			return
 		}
 
-		streakStore := store.NewStreakStore(postgres)
-		err := streakStore.UpdateStreak(ctx, userID)
+		enabled, err := featureFlags.IsEnabled(ctx, "streak", userID)
+		if err != nil {
+			log.Printf("Error checking streak feature flag: %v", err)
+			http.Error(w, "Failed to record check-in", http.StatusInternalServerError)
+			return
+		}
+		if !enabled {
+			http.Error(w, "Streaks are not available for this account yet", http.StatusForbidden)
+			return
+		}
+
+		err = streakStore.UpdateStreak(ctx, userID)
 		if err != nil {
 			log.Printf("Error updating streak on check-in: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to record check-in: %v", err), http.StatusInternalServerError)
 			return
 		}
+		ws.InvalidateUserStatsCache(ctx, redisClient, userID)
 
-		streakDays, startedAt, err := streakStore.GetUserStreak(ctx, userID)
+		streakDays, startedAt, lastCheckinAt, err := streakStore.GetUserStreak(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user streak: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to get streak: %v", err), http.StatusInternalServerError)
@@ -1141,6 +1938,9 @@ func handleStreakCheckIn(postgres *db.Postgres) http.HandlerFunc {
 		if startedAt != nil {
 			response["streak_started_at"] = startedAt.Format(time.RFC3339)
 		}
+		if lastCheckinAt != nil {
+			response["last_checkin_at"] = lastCheckinAt.Format(time.RFC3339)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -1152,6 +1952,108 @@ func handleStreakCheckIn(postgres *db.Postgres) http.HandlerFunc {
 	}
 }
 
+// handleGetStreak returns the authenticated user's streak status without recording a check-in
+// @Summary      Get streak details
+// @Description  Get current streak, last check-in, next milestone (3, 7, 14, 30, 60, 100, 365 days), XP available to redeem today and whether a streak freeze is available.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.StreakDetails  "Streak details"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/streak [get]
+func handleGetStreak(streakStore store.StreakStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		details, err := streakStore.GetStreakDetails(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting streak details: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get streak: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(details); err != nil {
+			log.Printf("Error encoding streak response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// streakFreezeCost is the number of coins needed to buy a streak freeze
+const streakFreezeCost = 50
+
+// handleBuyStreakFreeze lets the authenticated user buy a streak freeze with coins
+// @Summary      Buy streak freeze
+// @Description  Spend 50 coins for a streak freeze. Only one unused freeze can be held at a time; it is used up at the next check-in after exactly one missed day. Returns 402 with code INSUFFICIENT_COINS when the balance is too low.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201  {object}  map[string]interface{}  "freeze_id, coins_spent, balance"
+// @Failure      400  {string}  string  "Already holding an unused streak freeze"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      402  {object}  map[string]interface{}  "code INSUFFICIENT_COINS, balance, required"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/streak/buy-freeze [post]
+func handleBuyStreakFreeze(streakStore store.StreakStoreInterface, coinStore store.CoinStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// The freeze and the debit commit together, so racing purchases cannot buy two
+		freezeID, balance, err := streakStore.BuyStreakFreeze(ctx, userID, streakFreezeCost)
+		if err != nil {
+			if errors.Is(err, store.ErrStreakFreezeHeld) {
+				http.Error(w, "You already have an unused streak freeze", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, store.ErrInsufficientCoins) {
+				balance, _ := coinStore.GetBalance(ctx, userID)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusPaymentRequired)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"code":     "INSUFFICIENT_COINS",
+					"balance":  balance,
+					"required": streakFreezeCost,
+				})
+				return
+			}
+			log.Printf("Error buying streak freeze for user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to buy streak freeze: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := map[string]interface{}{
+			"freeze_id":   freezeID,
+			"coins_spent": streakFreezeCost,
+			"balance":     balance,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
 // handleRedeemStreak handles redeeming streak rewards
 // @Summary      Redeem streak reward
 // @Description  Redeem XP and badges based on current streak. Updates streak if needed.
@@ -1163,7 +2065,7 @@ func handleStreakCheckIn(postgres *db.Postgres) http.HandlerFunc {
 // @Failure      401  {string}  string  "Unauthorized"
 // @Failure      500  {string}  string  "Internal server error"
 // @Router       /api/user/streak/redeem [post]
-func handleRedeemStreak(postgres *db.Postgres) http.HandlerFunc {
+func handleRedeemStreak(streakStore store.StreakStoreInterface, userStore store.UserStoreInterface, badgeStore store.BadgeStoreInterface, redisClient *db.Redis) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -1175,7 +2077,6 @@ func handleRedeemStreak(postgres *db.Postgres) http.HandlerFunc {
 		}
 
 		// Update streak first (in case user is active today)
-		streakStore := store.NewStreakStore(postgres)
 		err := streakStore.UpdateStreak(ctx, userID)
 		if err != nil {
 			log.Printf("Error updating streak: %v", err)
@@ -1183,7 +2084,7 @@ func handleRedeemStreak(postgres *db.Postgres) http.HandlerFunc {
 		}
 
 		// Get current streak
-		streakDays, _, err := streakStore.GetUserStreak(ctx, userID)
+		streakDays, _, _, err := streakStore.GetUserStreak(ctx, userID)
 		if err != nil {
 			log.Printf("Error getting user streak: %v", err)
 			http.Error(w, fmt.Sprintf("Failed to get streak: %v", err), http.StatusInternalServerError)
@@ -1197,13 +2098,12 @@ func handleRedeemStreak(postgres *db.Postgres) http.HandlerFunc {
 			http.Error(w, fmt.Sprintf("Failed to redeem streak reward: %v", err), http.StatusInternalServerError)
 			return
 		}
+		ws.InvalidateUserStatsCache(ctx, redisClient, userID)
 
 		// Get user to check for badge auto-awarding
-		userStore := store.NewUserStore(postgres)
 		user, err := userStore.GetUserByID(ctx, userID)
 		if err == nil {
 			// Check and award badges based on new XP/level
-			badgeStore := store.NewBadgeStore(postgres)
 			_ = badgeStore.CheckAndAwardBadges(ctx, userID, user.XP, user.Level)
 		}
 
@@ -1222,20 +2122,3 @@ func handleRedeemStreak(postgres *db.Postgres) http.HandlerFunc {
 		}
 	}
 }
-
-// extractS3KeyFromURL extracts the S3 key from a full URL
-// func extractS3KeyFromURL(url string) string {
-// 	// URL format: https://bucket.s3.region.amazonaws.com/folder/filename
-// 	// We need to extract: folder/filename
-// 	parts := url
-// 	if len(url) > 0 {
-// 		// Find the last "/" and take everything after it
-// 		for i := len(url) - 1; i >= 0; i-- {
-// 			if url[i] == '/' {
-// 				parts = url[i+1:]
-// 				break
-// 			}
-// 		}
-// 	}
-// 	return parts
-// }
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// BanUserRequest represents the request to ban a user
+type BanUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// handleBanUser handles soft-banning a user (admin)
+// @Summary      Ban user
+// @Description  Soft-ban a user. The user is notified over WebSocket, then every authenticated request from them returns 403 ACCOUNT_BANNED until unbanned.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string          true   "User ID"
+// @Param        request  body      BanUserRequest  false  "Ban reason"
+// @Success      200      {object}  map[string]interface{}  "User banned"
+// @Failure      400      {string}  string  "Bad request"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_users permission required"
+// @Failure      404      {string}  string  "User not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/users/{id}/ban [post]
+func handleBanUser(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID := chi.URLParam(r, "id")
+
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		// Reason is optional, so an empty body is allowed
+		var req BanUserRequest
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+
+		if _, err := userStore.GetUserByID(ctx, userID); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to get user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		// Notify the user's active connection before the ban takes effect
+		if wsHub := ws.GetNotificationHub(); wsHub != nil {
+			if err := ws.SendAccountBannedNotification(wsHub, userID, req.Reason); err != nil {
+				log.Printf("Error sending ban notification to user %s: %v", userID, err)
+			}
+		}
+
+		if err := userStore.BanUser(ctx, userID, req.Reason); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error banning user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to ban user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		adminID, _ := GetUserIDFromContext(ctx)
+		log.Printf("Admin %s banned user %s (reason: %q)", adminID, userID, req.Reason)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"user_id":    userID,
+			"is_banned":  true,
+			"ban_reason": req.Reason,
+		}); err != nil {
+			log.Printf("Error encoding ban response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleUnbanUser handles lifting a user's ban (admin)
+// @Summary      Unban user
+// @Description  Lift a user's soft-ban so they can use authenticated routes again
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  map[string]interface{}  "User unbanned"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      403  {string}  string  "Forbidden - manage_users permission required"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /admin/users/{id}/unban [post]
+func handleUnbanUser(userStore store.UserStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userID := chi.URLParam(r, "id")
+
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := userStore.UnbanUser(ctx, userID); err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error unbanning user %s: %v", userID, err)
+			http.Error(w, fmt.Sprintf("Failed to unban user: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		adminID, _ := GetUserIDFromContext(ctx)
+		log.Printf("Admin %s unbanned user %s", adminID, userID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"user_id":   userID,
+			"is_banned": false,
+		}); err != nil {
+			log.Printf("Error encoding unban response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
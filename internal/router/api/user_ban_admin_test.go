@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleBanUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		getErr     error
+		banErr     error
+		wantStatus int
+		wantReason string
+		wantBanned bool
+	}{
+		{name: "bans with reason", userID: testutil.TestUserID, body: `{"reason":"  spamming the feed "}`, wantStatus: http.StatusOK, wantReason: "spamming the feed", wantBanned: true},
+		{name: "bans without body", userID: testutil.TestUserID, wantStatus: http.StatusOK, wantBanned: true},
+		{name: "missing user ID", wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: testutil.TestUserID, body: `{"reason":`, wantStatus: http.StatusBadRequest},
+		{name: "unknown user", userID: testutil.TestUserID, getErr: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "lookup fails", userID: testutil.TestUserID, getErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "ban fails", userID: testutil.TestUserID, banErr: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantBanned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banned := false
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return testutil.NewTestUser(), nil
+				},
+				BanUserFunc: func(ctx context.Context, userID, reason string) error {
+					banned = true
+					if reason != tt.wantReason {
+						t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+					}
+					return tt.banErr
+				},
+			}
+
+			r := withAdmin(newTestRequest(http.MethodPost, "/admin/users/x/ban", tt.body), testutil.TestAdminID, store.PermissionManageUsers)
+			r = withURLParams(r, "id", tt.userID)
+			w := serve(t, handleBanUser(userStore), r, tt.wantStatus)
+			if banned != tt.wantBanned {
+				t.Errorf("BanUser called = %v, want %v", banned, tt.wantBanned)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got["is_banned"] != true || got["ban_reason"] != tt.wantReason {
+				t.Errorf("response = %v", got)
+			}
+		})
+	}
+}
+
+func TestHandleUnbanUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		err        error
+		wantStatus int
+	}{
+		{name: "lifts ban", userID: testutil.TestUserID, wantStatus: http.StatusOK},
+		{name: "missing user ID", wantStatus: http.StatusBadRequest},
+		{name: "unknown user", userID: testutil.TestUserID, err: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				UnbanUserFunc: func(ctx context.Context, userID string) error {
+					return tt.err
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodDelete, "/admin/users/x/ban", ""), "id", tt.userID)
+			serve(t, handleUnbanUser(userStore), r, tt.wantStatus)
+		})
+	}
+}
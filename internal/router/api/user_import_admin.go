@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/validator"
+)
+
+const (
+	// maxBulkImportUsers is the most rows accepted by one bulk import
+	maxBulkImportUsers = 1000
+	// maxBulkImportBodySize caps the JSON body or CSV upload of a bulk import
+	maxBulkImportBodySize = 10 << 20
+	// bulkImportWindow is how long an admin must wait between bulk imports
+	bulkImportWindow = time.Hour
+)
+
+// bulkImportColumns are the required CSV header columns
+var bulkImportColumns = []string{"name", "email", "state_id", "college_id"}
+
+// BulkImportResponse is the result of a bulk user import
+type BulkImportResponse struct {
+	Imported int                     `json:"imported"`
+	Skipped  int                     `json:"skipped"` // Emails that already have an account
+	Errors   []store.BulkImportError `json:"errors"`
+}
+
+// handleBulkImportUsers creates student accounts for a list of users (admin)
+// @Summary      Bulk import users
+// @Description  Create accounts for up to 1000 users at once, e.g. a college cohort. Send a JSON array of {name, email, state_id, college_id}, or a multipart upload with a CSV "file" whose header row has name, email, state_id and college_id. Emails that already have an account are skipped; invalid rows are reported in errors without affecting the rest. Each new user gets a random password and an email with a link to set their own (valid 7 days). One import per admin per hour. Requires the manage_users permission.
+// @Tags         admin
+// @Accept       json
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      []store.BulkImportUser  false  "Users to import (JSON)"
+// @Param        file     formData  file                    false  "CSV of users to import"
+// @Success      200      {object}  BulkImportResponse  "Import result"
+// @Failure      400      {string}  string  "Bad request - invalid body or CSV, no users, or more than 1000"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_users permission required"
+// @Failure      429      {string}  string  "Only one bulk import per hour is allowed"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/users/bulk-import [post]
+func handleBulkImportUsers(postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxBulkImportBodySize)
+
+		var users []store.BulkImportUser
+		source := "json"
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			source = "csv"
+			if err := r.ParseMultipartForm(maxBulkImportBodySize); err != nil {
+				http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, "CSV file is required", http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			users, err = parseBulkImportCSV(file)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&users); err != nil {
+			http.Error(w, "Invalid request body: expected a JSON array of users", http.StatusBadRequest)
+			return
+		}
+
+		if len(users) == 0 {
+			http.Error(w, "At least one user is required", http.StatusBadRequest)
+			return
+		}
+		if len(users) > maxBulkImportUsers {
+			http.Error(w, fmt.Sprintf("At most %d users can be imported at once", maxBulkImportUsers), http.StatusBadRequest)
+			return
+		}
+
+		valid, rowErrors := validateBulkImportUsers(users)
+
+		var rateLimitKey string
+		if redisClient != nil {
+			rateLimitKey = fmt.Sprintf("bulk_import:%s", adminID)
+			acquired, err := redisClient.Client.SetNX(ctx, rateLimitKey, time.Now().Unix(), bulkImportWindow).Result()
+			if err != nil {
+				log.Printf("Error checking bulk import rate limit: %v", err)
+				http.Error(w, "Failed to import users", http.StatusInternalServerError)
+				return
+			}
+			if !acquired {
+				http.Error(w, "Only one bulk import per hour is allowed", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		userStore := store.NewUserStore(postgres)
+		imported, skipped, importErrors, err := userStore.ImportUsers(ctx, valid)
+		if err != nil {
+			log.Printf("Error importing users: %v", err)
+			// Nothing was imported, so the admin may try again straight away
+			if rateLimitKey != "" {
+				redisClient.Client.Del(ctx, rateLimitKey)
+			}
+			http.Error(w, fmt.Sprintf("Failed to import users: %v", err), http.StatusInternalServerError)
+			return
+		}
+		rowErrors = append(rowErrors, importErrors...)
+
+		auditStore := store.NewAuditLogStore(postgres)
+		if err := auditStore.Record(ctx, adminID, store.AuditActionUsersBulkImport, map[string]interface{}{
+			"source":   source,
+			"rows":     len(users),
+			"imported": len(imported),
+			"skipped":  skipped,
+			"errors":   len(rowErrors),
+		}); err != nil {
+			log.Printf("Error recording bulk import audit log: %v", err)
+		}
+		log.Printf("Admin %s bulk imported %d users (%d skipped, %d errors)", adminID, len(imported), skipped, len(rowErrors))
+
+		taskStore := store.NewTaskStore(postgres)
+		for _, user := range imported {
+			if _, err := taskStore.AssignOpenTasksToNewUser(ctx, user.ID); err != nil {
+				log.Printf("Error assigning open tasks to user %s: %v", user.ID, err)
+			}
+		}
+
+		queueSetPasswordEmails(postgres, cfg, imported)
+
+		response := BulkImportResponse{
+			Imported: len(imported),
+			Skipped:  skipped,
+			Errors:   rowErrors,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// parseBulkImportCSV reads users from a CSV whose header row names the bulkImportColumns (in any order)
+func parseBulkImportCSV(file io.Reader) ([]store.BulkImportUser, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("CSV file is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range bulkImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header must include %s", strings.Join(bulkImportColumns, ", "))
+		}
+	}
+
+	var users []store.BulkImportUser
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(users) == maxBulkImportUsers {
+			return nil, fmt.Errorf("at most %d users can be imported at once", maxBulkImportUsers)
+		}
+		users = append(users, store.BulkImportUser{
+			Name:      record[columns["name"]],
+			Email:     record[columns["email"]],
+			StateID:   record[columns["state_id"]],
+			CollegeID: record[columns["college_id"]],
+		})
+	}
+	return users, nil
+}
+
+// validateBulkImportUsers trims every row and splits them into rows to import and rows with errors.
+// Repeats of an email already in the batch are reported as errors.
+func validateBulkImportUsers(users []store.BulkImportUser) ([]store.BulkImportUser, []store.BulkImportError) {
+	valid := make([]store.BulkImportUser, 0, len(users))
+	rowErrors := []store.BulkImportError{}
+	seen := make(map[string]bool)
+
+	for _, u := range users {
+		u.Name = strings.TrimSpace(u.Name)
+		u.Email = strings.TrimSpace(u.Email)
+		u.StateID = strings.TrimSpace(u.StateID)
+		u.CollegeID = strings.TrimSpace(u.CollegeID)
+
+		reject := func(reason string) {
+			rowErrors = append(rowErrors, store.BulkImportError{Email: u.Email, Reason: reason})
+		}
+
+		if u.Name == "" || u.Email == "" || u.StateID == "" || u.CollegeID == "" {
+			reject("name, email, state_id and college_id are required")
+			continue
+		}
+		if err := validator.ValidateUser(store.RegisterRequest{Name: u.Name, Email: u.Email}); err != nil {
+			reject(err.Error())
+			continue
+		}
+		if _, err := uuid.Parse(u.StateID); err != nil {
+			reject("invalid state_id")
+			continue
+		}
+		if _, err := uuid.Parse(u.CollegeID); err != nil {
+			reject("invalid college_id")
+			continue
+		}
+		key := strings.ToLower(u.Email)
+		if seen[key] {
+			reject("duplicate email in import")
+			continue
+		}
+		seen[key] = true
+
+		valid = append(valid, u)
+	}
+
+	return valid, rowErrors
+}
+
+// queueSetPasswordEmails emails each imported user a set password link in the background.
+// Failures are only logged.
+func queueSetPasswordEmails(postgres *db.Postgres, cfg *env.Config, users []store.ImportedUser) {
+	if len(users) == 0 {
+		return
+	}
+	go func() {
+		for _, u := range users {
+			ctx, cancel := context.WithTimeout(context.Background(), verificationEmailTimeout)
+			if err := sendSetPasswordEmail(ctx, postgres, cfg, u.ID, u.Email, u.Name); err != nil {
+				log.Printf("Error sending set password email to user %s: %v", u.ID, err)
+			}
+			cancel()
+		}
+	}()
+}
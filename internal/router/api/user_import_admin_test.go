@@ -0,0 +1,90 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestParseBulkImportCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		wantUsers int
+		wantErr   string
+	}{
+		{name: "columns in any order", csv: "email,name,college_id,state_id\na@example.com,Asha,c1,s1\nb@example.com,Bilal,c1,s1\n", wantUsers: 2},
+		{name: "header case and spaces", csv: " Name , EMAIL ,State_ID,college_id\nAsha,a@example.com,s1,c1\n", wantUsers: 1},
+		{name: "header only", csv: "name,email,state_id,college_id\n", wantUsers: 0},
+		{name: "empty file", csv: "", wantErr: "CSV file is empty"},
+		{name: "missing column", csv: "name,email,state_id\nAsha,a@example.com,s1\n", wantErr: "CSV header must include"},
+		{name: "ragged row", csv: "name,email,state_id,college_id\nAsha,a@example.com\n", wantErr: "invalid CSV"},
+		{name: "too many rows", csv: "name,email,state_id,college_id\n" + strings.Repeat("Asha,a@example.com,s1,c1\n", maxBulkImportUsers+1), wantErr: "at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := parseBulkImportCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(users) != tt.wantUsers {
+				t.Errorf("got %d users, want %d", len(users), tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestValidateBulkImportUsers(t *testing.T) {
+	valid := store.BulkImportUser{Name: "Asha Rao", Email: "asha@example.com", StateID: testutil.TestStateID, CollegeID: testutil.TestCollegeID}
+	with := func(change func(*store.BulkImportUser)) store.BulkImportUser {
+		u := valid
+		change(&u)
+		return u
+	}
+	tests := []struct {
+		name       string
+		users      []store.BulkImportUser
+		wantValid  int
+		wantReason string
+	}{
+		{name: "valid row", users: []store.BulkImportUser{valid}, wantValid: 1},
+		{name: "trimmed", users: []store.BulkImportUser{with(func(u *store.BulkImportUser) { u.Email = "  asha@example.com " })}, wantValid: 1},
+		{name: "missing field", users: []store.BulkImportUser{with(func(u *store.BulkImportUser) { u.CollegeID = " " })}, wantReason: "required"},
+		{name: "invalid email", users: []store.BulkImportUser{with(func(u *store.BulkImportUser) { u.Email = "asha" })}, wantReason: "email"},
+		{name: "invalid state", users: []store.BulkImportUser{with(func(u *store.BulkImportUser) { u.StateID = "kerala" })}, wantReason: "invalid state_id"},
+		{name: "invalid college", users: []store.BulkImportUser{with(func(u *store.BulkImportUser) { u.CollegeID = "iit" })}, wantReason: "invalid college_id"},
+		{name: "duplicate email ignores case", users: []store.BulkImportUser{valid, with(func(u *store.BulkImportUser) { u.Email = "ASHA@example.com" })}, wantValid: 1, wantReason: "duplicate email in import"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rowErrors := validateBulkImportUsers(tt.users)
+			if len(got) != tt.wantValid {
+				t.Errorf("got %d valid rows, want %d", len(got), tt.wantValid)
+			}
+			for _, u := range got {
+				if u.Email != strings.TrimSpace(u.Email) {
+					t.Errorf("email %q was not trimmed", u.Email)
+				}
+			}
+			if tt.wantReason == "" {
+				if len(rowErrors) != 0 {
+					t.Errorf("unexpected row errors: %+v", rowErrors)
+				}
+				return
+			}
+			if len(rowErrors) != 1 || !strings.Contains(rowErrors[0].Reason, tt.wantReason) {
+				t.Errorf("row errors = %+v, want one containing %q", rowErrors, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// Each user can search at most userSearchLimit times per userSearchWindow
+const (
+	userSearchLimit  = 30
+	userSearchWindow = time.Minute
+)
+
+// UserSearchResponse is one page of user search results
+type UserSearchResponse struct {
+	Users      []store.SearchUserResult `json:"users"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// handleSearchUsers finds users by name, optionally within a state or college
+// @Summary      Search users
+// @Description  Find students whose name contains q (case-insensitive), ordered by name. state_id and college_id narrow the results; without q every user matching the filters is returned. Results never include email or phone. Banned users and users who have blocked the caller are left out. Limited to 30 requests per minute.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q           query     string  false  "Part of the user's name"
+// @Param        state_id    query     string  false  "Only users from this state"
+// @Param        college_id  query     string  false  "Only users from this college"
+// @Param        page        query     int     false  "Page number (default: 1)"
+// @Param        page_size   query     int     false  "Items per page (default: 20, max: 100)"
+// @Success      200         {object}  UserSearchResponse  "Matching users"
+// @Failure      400         {string}  string  "Bad request - invalid state_id or college_id"
+// @Failure      401         {string}  string  "Unauthorized"
+// @Failure      429         {string}  string  "Too many requests"
+// @Failure      500         {string}  string  "Internal server error"
+// @Router       /api/users/search [get]
+func handleSearchUsers(userStore store.UserStoreInterface, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		q := strings.TrimSpace(query.Get("q"))
+		stateID := query.Get("state_id")
+		collegeID := query.Get("college_id")
+		if stateID != "" {
+			if _, err := uuid.Parse(stateID); err != nil {
+				http.Error(w, "Invalid state_id", http.StatusBadRequest)
+				return
+			}
+		}
+		if collegeID != "" {
+			if _, err := uuid.Parse(collegeID); err != nil {
+				http.Error(w, "Invalid college_id", http.StatusBadRequest)
+				return
+			}
+		}
+
+		page, pageSize := 1, 20
+		if pageStr := query.Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+		if pageSizeStr := query.Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				pageSize = ps
+			}
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+		offset := (page - 1) * pageSize
+
+		if redisClient != nil {
+			rateLimitKey := fmt.Sprintf("user_search:%s", userID)
+			searches, err := redisClient.Client.Incr(ctx, rateLimitKey).Result()
+			if err != nil {
+				log.Printf("Error checking user search rate limit: %v", err)
+				http.Error(w, "Failed to search users", http.StatusInternalServerError)
+				return
+			}
+			if searches == 1 {
+				redisClient.Client.Expire(ctx, rateLimitKey, userSearchWindow)
+			}
+			if searches > userSearchLimit {
+				http.Error(w, "Too many searches. Try again in a minute.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		users, total, err := userStore.SearchUsers(ctx, userID, q, stateID, collegeID, pageSize, offset)
+		if err != nil {
+			log.Printf("Error searching users: %v", err)
+			http.Error(w, "Failed to search users", http.StatusInternalServerError)
+			return
+		}
+
+		totalPages := (total + pageSize - 1) / pageSize
+		if totalPages == 0 {
+			totalPages = 1
+		}
+
+		response := UserSearchResponse{
+			Users:      users,
+			Total:      total,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: totalPages,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding user search response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
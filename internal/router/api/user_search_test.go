@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleSearchUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		query       string
+		searches    int
+		wantStatus  int
+		wantQuery   string
+		wantLimit   int
+		wantOffset  int
+		wantCollege string
+	}{
+		{name: "defaults", userID: testutil.TestUserID, query: "?q=%20asha%20", wantStatus: http.StatusOK, wantQuery: "asha", wantLimit: 20},
+		{name: "filters and paging", userID: testutil.TestUserID, query: "?college_id=" + testutil.TestCollegeID + "&page=3&page_size=10", wantStatus: http.StatusOK, wantLimit: 10, wantOffset: 20, wantCollege: testutil.TestCollegeID},
+		{name: "page size capped", userID: testutil.TestUserID, query: "?page_size=1000", wantStatus: http.StatusOK, wantLimit: 100},
+		{name: "invalid state", userID: testutil.TestUserID, query: "?state_id=kerala", wantStatus: http.StatusBadRequest},
+		{name: "invalid college", userID: testutil.TestUserID, query: "?college_id=iit", wantStatus: http.StatusBadRequest},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "last search in window", userID: testutil.TestUserID, searches: userSearchLimit - 1, wantStatus: http.StatusOK, wantLimit: 20},
+		{name: "rate limited", userID: testutil.TestUserID, searches: userSearchLimit, wantStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			if tt.searches > 0 {
+				server.Set("user_search:"+testutil.TestUserID, strconv.Itoa(tt.searches))
+			}
+			userStore := &mock.UserStore{
+				SearchUsersFunc: func(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]store.SearchUserResult, int, error) {
+					if viewerID != tt.userID || query != tt.wantQuery || collegeID != tt.wantCollege || limit != tt.wantLimit || offset != tt.wantOffset {
+						t.Errorf("SearchUsers(%q, %q, %q, %q, %d, %d)", viewerID, query, stateID, collegeID, limit, offset)
+					}
+					return []store.SearchUserResult{}, 0, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/users/search"+tt.query, ""), tt.userID)
+			serve(t, handleSearchUsers(userStore, redisClient), r, tt.wantStatus)
+		})
+	}
+}
+
+// The rate limit window starts with the first search
+func TestHandleSearchUsersRateLimitWindow(t *testing.T) {
+	redisClient, server := testutil.NewMockRedis(t)
+	userStore := &mock.UserStore{
+		SearchUsersFunc: func(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]store.SearchUserResult, int, error) {
+			return []store.SearchUserResult{}, 0, nil
+		},
+	}
+
+	r := withUserID(newTestRequest(http.MethodGet, "/api/users/search", ""), testutil.TestUserID)
+	serve(t, handleSearchUsers(userStore, redisClient), r, http.StatusOK)
+	if ttl := server.TTL("user_search:" + testutil.TestUserID); ttl != userSearchWindow {
+		t.Errorf("window TTL = %v, want %v", ttl, userSearchWindow)
+	}
+}
+
+// Results carry only the public profile fields and the page totals
+func TestHandleSearchUsersResponse(t *testing.T) {
+	userStore := &mock.UserStore{
+		SearchUsersFunc: func(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]store.SearchUserResult, int, error) {
+			return []store.SearchUserResult{{ID: testutil.TestFeedID, Name: "Asha", AvatarURL: "https://cdn.example.com/a.png", CollegeName: "IIT Bombay", StateName: "Maharashtra", XP: 340, Level: 3}}, 41, nil
+		},
+	}
+
+	r := withUserID(newTestRequest(http.MethodGet, "/api/users/search?q=ash", ""), testutil.TestUserID)
+	w := serve(t, handleSearchUsers(userStore, nil), r, http.StatusOK)
+
+	var response struct {
+		Users      []map[string]any `json:"users"`
+		Total      int              `json:"total"`
+		TotalPages int              `json:"total_pages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if response.Total != 41 || response.TotalPages != 3 || len(response.Users) != 1 {
+		t.Fatalf("response = %+v, want 1 of 41 users over 3 pages", response)
+	}
+	var keys []string
+	for key := range response.Users[0] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if want := []string{"avatar_url", "college_name", "id", "level", "name", "state_name", "xp"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("result keys = %v, want %v", keys, want)
+	}
+}
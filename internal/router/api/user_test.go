@@ -0,0 +1,614 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+const otherUserID = "dddddddd-dddd-dddd-dddd-dddddddddddd"
+
+func TestHandleBlockUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		blockedID  string
+		err        error
+		wantStatus int
+	}{
+		{name: "blocks", userID: testutil.TestUserID, blockedID: otherUserID, wantStatus: http.StatusOK},
+		{name: "anonymous", blockedID: otherUserID, wantStatus: http.StatusUnauthorized},
+		{name: "missing user ID", userID: testutil.TestUserID, wantStatus: http.StatusBadRequest},
+		{name: "self", userID: testutil.TestUserID, blockedID: testutil.TestUserID, err: errors.New("cannot block yourself"), wantStatus: http.StatusBadRequest},
+		{name: "already blocked", userID: testutil.TestUserID, blockedID: otherUserID, err: errors.New("already blocked this user"), wantStatus: http.StatusBadRequest},
+		{name: "unknown user", userID: testutil.TestUserID, blockedID: otherUserID, err: errors.New("user to block not found: " + otherUserID), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, blockedID: otherUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				BlockUserFunc: func(ctx context.Context, blockerID, blockedID string) error {
+					if blockerID != tt.userID || blockedID != tt.blockedID {
+						t.Errorf("BlockUser(%q, %q), want (%q, %q)", blockerID, blockedID, tt.userID, tt.blockedID)
+					}
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/user/x/block", ""), tt.userID)
+			r = withURLParams(r, "id", tt.blockedID)
+			serve(t, handleBlockUser(userStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleUnblockUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "unblocks", wantStatus: http.StatusOK},
+		{name: "not blocked", err: errors.New("not blocking this user"), wantStatus: http.StatusBadRequest},
+		{name: "self", err: errors.New("cannot unblock yourself"), wantStatus: http.StatusBadRequest},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				UnblockUserFunc: func(ctx context.Context, blockerID, blockedID string) error {
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/user/x/unblock", ""), testutil.TestUserID)
+			r = withURLParams(r, "id", otherUserID)
+			serve(t, handleUnblockUser(userStore), r, tt.wantStatus)
+		})
+	}
+}
+
+func TestHandleGetBlockedUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		query      string
+		err        error
+		wantLimit  int
+		wantOffset int
+		wantStatus int
+	}{
+		{name: "first page", userID: testutil.TestUserID, wantLimit: 50, wantStatus: http.StatusOK},
+		{name: "later page", userID: testutil.TestUserID, query: "?page=3&page_size=10", wantLimit: 10, wantOffset: 20, wantStatus: http.StatusOK},
+		{name: "page size capped", userID: testutil.TestUserID, query: "?page_size=1000", wantLimit: 100, wantStatus: http.StatusOK},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantLimit: 50, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				GetBlockedUsersFunc: func(ctx context.Context, blockerID string, limit, offset int) ([]store.FollowUserInfo, error) {
+					if blockerID != tt.userID || limit != tt.wantLimit || offset != tt.wantOffset {
+						t.Errorf("GetBlockedUsers(%q, %d, %d), want (%q, %d, %d)", blockerID, limit, offset, tt.userID, tt.wantLimit, tt.wantOffset)
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return []store.FollowUserInfo{{ID: otherUserID, Name: "Other User", XP: 40, Level: 1}}, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/blocked"+tt.query, ""), tt.userID)
+			w := serve(t, handleGetBlockedUsers(userStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got []store.FollowUserInfo
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if len(got) != 1 || got[0].ID != otherUserID {
+				t.Errorf("blocked = %+v, want the blocked user", got)
+			}
+		})
+	}
+}
+
+func TestHandleBuyStreakFreeze(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "buys", wantStatus: http.StatusCreated},
+		{name: "already holding one", err: store.ErrStreakFreezeHeld, wantStatus: http.StatusBadRequest},
+		{name: "insufficient coins", err: store.ErrInsufficientCoins, wantStatus: http.StatusPaymentRequired},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streakStore := &mock.StreakStore{
+				BuyStreakFreezeFunc: func(ctx context.Context, userID string, cost int) (string, int, error) {
+					if cost != streakFreezeCost {
+						t.Errorf("cost = %d, want %d", cost, streakFreezeCost)
+					}
+					if tt.err != nil {
+						return "", 0, tt.err
+					}
+					return "freeze-1", 10, nil
+				},
+			}
+			coinStore := &mock.CoinStore{
+				GetBalanceFunc: func(ctx context.Context, userID string) (int, error) {
+					return 10, nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/user/streak/buy-freeze", ""), testutil.TestUserID)
+			w := serve(t, handleBuyStreakFreeze(streakStore, coinStore), r, tt.wantStatus)
+			if tt.wantStatus == http.StatusBadRequest || tt.wantStatus == http.StatusInternalServerError {
+				return
+			}
+			var got map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got["balance"] != float64(10) {
+				t.Errorf("balance = %v, want 10", got["balance"])
+			}
+		})
+	}
+}
+
+func TestHandleGetFollowingCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		targetID      string
+		following     bool
+		err           error
+		wantStatus    int
+		wantFollowing bool
+	}{
+		{name: "following", userID: testutil.TestUserID, targetID: otherUserID, following: true, wantStatus: http.StatusOK, wantFollowing: true},
+		{name: "not following", userID: testutil.TestUserID, targetID: otherUserID, wantStatus: http.StatusOK},
+		// Answered without the store, which would say true here
+		{name: "yourself", userID: testutil.TestUserID, targetID: testutil.TestUserID, following: true, wantStatus: http.StatusOK},
+		{name: "anonymous", targetID: otherUserID, wantStatus: http.StatusUnauthorized},
+		{name: "missing user ID", userID: testutil.TestUserID, wantStatus: http.StatusBadRequest},
+		{name: "store error", userID: testutil.TestUserID, targetID: otherUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				IsFollowingFunc: func(ctx context.Context, followerID, followingID string) (bool, error) {
+					return tt.following, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/x/following-check", ""), tt.userID)
+			r = withURLParams(r, "id", tt.targetID)
+			w := serve(t, handleGetFollowingCheck(userStore), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got FollowingCheckResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.IsFollowing != tt.wantFollowing {
+				t.Errorf("is_following = %v, want %v", got.IsFollowing, tt.wantFollowing)
+			}
+		})
+	}
+}
+
+func TestHandleAddXPForUserCap(t *testing.T) {
+	tests := []struct {
+		name       string
+		xp         int
+		wantStatus int
+	}{
+		{name: "minimum", xp: 1, wantStatus: http.StatusOK},
+		{name: "at cap", xp: 500, wantStatus: http.StatusOK},
+		{name: "over cap", xp: 501, wantStatus: http.StatusBadRequest},
+		{name: "far over cap", xp: 99999, wantStatus: http.StatusBadRequest},
+		{name: "zero", xp: 0, wantStatus: http.StatusBadRequest},
+		{name: "negative", xp: -1, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awarded := false
+			xpStore := &mock.XPStore{
+				AwardXPFunc: func(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error) {
+					awarded = true
+					return testutil.NewTestXPLog(func(l *store.XPLog) { l.XP = req.XP }), nil
+				},
+			}
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					return testutil.NewTestUser(), nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPost, "/api/user/xp", fmt.Sprintf(`{"xp":%d}`, tt.xp)), testutil.TestUserID)
+			serve(t, handleAddXPForUser(nil, xpStore, userStore, nil, &env.Config{MaxSelfXPPerCall: 500}), r, tt.wantStatus)
+			if wantAwarded := tt.wantStatus == http.StatusOK; awarded != wantAwarded {
+				t.Errorf("AwardXP called = %v, want %v", awarded, wantAwarded)
+			}
+		})
+	}
+}
+
+func TestHandleAddXPForUserBroadcastsLeaderboards(t *testing.T) {
+	redisClient, _ := testutil.NewMockRedis(t)
+	xpStore := &mock.XPStore{
+		AwardXPFunc: func(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error) {
+			return testutil.NewTestXPLog(), nil
+		},
+	}
+	userStore := &mock.UserStore{
+		GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+			// The test user has a state and a college
+			return testutil.NewTestUser(), nil
+		},
+	}
+
+	r := withUserID(newTestRequest(http.MethodPost, "/api/user/xp", `{"xp":10}`), testutil.TestUserID)
+	serve(t, handleAddXPForUser(nil, xpStore, userStore, redisClient, &env.Config{MaxSelfXPPerCall: 500}), r, http.StatusOK)
+
+	entries, err := redisClient.Client.XRange(context.Background(), ws.LeaderboardStream, "-", "+").Result()
+	if err != nil {
+		t.Fatalf("reading leaderboard stream: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("got %d leaderboard updates, want pan-india, state and college", len(entries))
+	}
+}
+
+func TestHandleUpdateMe(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		wantFields *store.UpdateProfileRequest // nil when the store must not be called
+		err        error
+		wantStatus int
+	}{
+		{name: "name", userID: testutil.TestUserID, body: `{"name":"  New Name  "}`, wantFields: &store.UpdateProfileRequest{Name: str("New Name")}, wantStatus: http.StatusOK},
+		{name: "bio", userID: testutil.TestUserID, body: `{"bio":" Hello "}`, wantFields: &store.UpdateProfileRequest{Bio: str("Hello")}, wantStatus: http.StatusOK},
+		{name: "phone", userID: testutil.TestUserID, body: `{"phone":"9876543210"}`, wantFields: &store.UpdateProfileRequest{Phone: str("9876543210")}, wantStatus: http.StatusOK},
+		{name: "name and phone", userID: testutil.TestUserID, body: `{"name":"New Name","phone":"9876543210"}`, wantFields: &store.UpdateProfileRequest{Name: str("New Name"), Phone: str("9876543210")}, wantStatus: http.StatusOK},
+		{name: "all fields", userID: testutil.TestUserID, body: `{"name":"New Name","bio":"Hello","phone":"987654321012345"}`, wantFields: &store.UpdateProfileRequest{Name: str("New Name"), Bio: str("Hello"), Phone: str("987654321012345")}, wantStatus: http.StatusOK},
+		{name: "empty strings clear bio and phone", userID: testutil.TestUserID, body: `{"bio":"","phone":""}`, wantFields: &store.UpdateProfileRequest{Bio: str(""), Phone: str("")}, wantStatus: http.StatusOK},
+		{name: "unknown user", userID: testutil.TestUserID, body: `{"name":"New Name"}`, wantFields: &store.UpdateProfileRequest{Name: str("New Name")}, err: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, body: `{"name":"New Name"}`, wantFields: &store.UpdateProfileRequest{Name: str("New Name")}, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "anonymous", body: `{"name":"New Name"}`, wantStatus: http.StatusUnauthorized},
+		{name: "invalid JSON", userID: testutil.TestUserID, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "no fields", userID: testutil.TestUserID, body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "name too short", userID: testutil.TestUserID, body: `{"name":" A "}`, wantStatus: http.StatusBadRequest},
+		{name: "name too long", userID: testutil.TestUserID, body: `{"name":"` + strings.Repeat("a", 101) + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "bio too long", userID: testutil.TestUserID, body: `{"bio":"` + strings.Repeat("a", 501) + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "phone too short", userID: testutil.TestUserID, body: `{"phone":"987654321"}`, wantStatus: http.StatusBadRequest},
+		{name: "phone too long", userID: testutil.TestUserID, body: `{"phone":"9876543210123456"}`, wantStatus: http.StatusBadRequest},
+		{name: "phone with letters", userID: testutil.TestUserID, body: `{"phone":"98765abcde"}`, wantStatus: http.StatusBadRequest},
+		{name: "valid name with invalid phone", userID: testutil.TestUserID, body: `{"name":"New Name","phone":"12-34"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			userStore := &mock.UserStore{
+				UpdateProfileFunc: func(ctx context.Context, userID string, fields store.UpdateProfileRequest) (*store.User, error) {
+					called = true
+					if tt.wantFields != nil && !reflect.DeepEqual(fields, *tt.wantFields) {
+						t.Errorf("fields = %s, want %s", mustJSON(fields), mustJSON(*tt.wantFields))
+					}
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return testutil.NewTestUser(), nil
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPatch, "/api/user/me", tt.body), tt.userID)
+			w := serve(t, handleUpdateMe(userStore), r, tt.wantStatus)
+			if called != (tt.wantFields != nil) {
+				t.Errorf("UpdateProfile called = %v, want %v", called, tt.wantFields != nil)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got store.User
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if got.ID != testutil.TestUserID {
+				t.Errorf("user ID = %q, want the updated user", got.ID)
+			}
+		})
+	}
+}
+
+// mustJSON shows a struct of pointers by value in failure messages
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestHandleGetFollowList(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		total          int
+		wantLimit      int
+		wantOffset     int
+		wantPage       int
+		wantPageSize   int
+		wantTotalPages int
+	}{
+		{name: "defaults", total: 3, wantLimit: 50, wantOffset: 0, wantPage: 1, wantPageSize: 50, wantTotalPages: 1},
+		{name: "last full page", query: "?page=2&page_size=10", total: 20, wantLimit: 10, wantOffset: 10, wantPage: 2, wantPageSize: 10, wantTotalPages: 2},
+		{name: "one past a full page", query: "?page=3&page_size=10", total: 21, wantLimit: 10, wantOffset: 20, wantPage: 3, wantPageSize: 10, wantTotalPages: 3},
+		{name: "page past the end", query: "?page=5&page_size=10", total: 20, wantLimit: 10, wantOffset: 40, wantPage: 5, wantPageSize: 10, wantTotalPages: 2},
+		{name: "page size capped", query: "?page_size=1000", total: 150, wantLimit: 100, wantOffset: 0, wantPage: 1, wantPageSize: 100, wantTotalPages: 2},
+		{name: "invalid page and size ignored", query: "?page=0&page_size=-5", wantLimit: 50, wantOffset: 0, wantPage: 1, wantPageSize: 50, wantTotalPages: 0},
+	}
+
+	for _, list := range []string{"followers", "following"} {
+		for _, tt := range tests {
+			t.Run(list+"/"+tt.name, func(t *testing.T) {
+				page := func(ctx context.Context, userID, viewerID string, limit, offset int) ([]store.FollowUserInfo, int, error) {
+					if userID != otherUserID || viewerID != testutil.TestUserID {
+						t.Errorf("userID, viewerID = %q, %q", userID, viewerID)
+					}
+					if limit != tt.wantLimit || offset != tt.wantOffset {
+						t.Errorf("limit, offset = %d, %d; want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+					}
+					return nil, tt.total, nil
+				}
+				userStore := &mock.UserStore{
+					GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+						return testutil.NewTestUser(), nil
+					},
+					GetFollowersFunc: page,
+					GetFollowingFunc: page,
+				}
+				handler := handleGetFollowers(userStore)
+				if list == "following" {
+					handler = handleGetFollowing(userStore)
+				}
+
+				r := withUserID(newTestRequest(http.MethodGet, "/api/user/x/"+list+tt.query, ""), testutil.TestUserID)
+				r = withURLParams(r, "id", otherUserID)
+				w := serve(t, handler, r, http.StatusOK)
+				var got FollowListResponse
+				if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+					t.Fatalf("decoding response: %v", err)
+				}
+				if got.Users == nil {
+					t.Error("users = null, want []")
+				}
+				if got.Total != tt.total || got.Page != tt.wantPage || got.PageSize != tt.wantPageSize || got.TotalPages != tt.wantTotalPages {
+					t.Errorf("total, page, page_size, total_pages = %d, %d, %d, %d; want %d, %d, %d, %d",
+						got.Total, got.Page, got.PageSize, got.TotalPages, tt.total, tt.wantPage, tt.wantPageSize, tt.wantTotalPages)
+				}
+			})
+		}
+	}
+}
+
+// Profile pictures are checked by content before anything is uploaded
+func TestHandleProfilePicRejectsNonImages(t *testing.T) {
+	userStore := &mock.UserStore{
+		GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+			return &store.User{ID: userID}, nil
+		},
+		GetUserS3KeysFunc: func(ctx context.Context, userID string) (string, string, error) {
+			return "", "profile-pics/old.jpg", nil
+		},
+	}
+	cfg := &env.Config{AWSRegion: "us-east-1", AWSProfileBucket: "profiles", AWSAccessKeyID: "test", AWSSecretAccessKey: "test"}
+	handlers := []struct {
+		method  string
+		handler http.HandlerFunc
+	}{
+		{http.MethodPost, handleUploadProfilePic(userStore, nil, cfg)},
+		{http.MethodPut, handleUpdateProfilePic(userStore, cfg)},
+	}
+	tests := []struct {
+		name     string
+		content  []byte
+		wantBody string
+	}{
+		{name: "pdf named jpg", content: []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n"), wantBody: "unsupported image type"},
+		{name: "corrupt png", content: append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...), wantBody: "Invalid image"},
+	}
+
+	for _, tt := range tests {
+		for _, h := range handlers {
+			t.Run(h.method+" "+tt.name, func(t *testing.T) {
+				var body bytes.Buffer
+				form := multipart.NewWriter(&body)
+				part, err := form.CreateFormFile("profile_pic", "profile.jpg")
+				if err != nil {
+					t.Fatalf("creating form file: %v", err)
+				}
+				part.Write(tt.content)
+				form.Close()
+
+				r := httptest.NewRequest(h.method, "/api/user/profile-pic", &body)
+				r.Header.Set("Content-Type", form.FormDataContentType())
+				w := serve(t, h.handler, withUserID(r, testutil.TestUserID), http.StatusBadRequest)
+				if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+					t.Errorf("body = %q, want %q", got, tt.wantBody)
+				}
+			})
+		}
+	}
+}
+
+func TestHandleUpdateResumeVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		err        error
+		wantStatus int
+		wantStored string
+	}{
+		{name: "public", userID: testutil.TestUserID, body: `{"visibility":"public"}`, wantStatus: http.StatusOK, wantStored: "public"},
+		{name: "private", userID: testutil.TestUserID, body: `{"visibility":"private"}`, wantStatus: http.StatusOK, wantStored: "private"},
+		{name: "college only", userID: testutil.TestUserID, body: `{"visibility":"college_only"}`, wantStatus: http.StatusOK, wantStored: "college_only"},
+		{name: "unknown visibility", userID: testutil.TestUserID, body: `{"visibility":"friends"}`, wantStatus: http.StatusBadRequest},
+		{name: "case matters", userID: testutil.TestUserID, body: `{"visibility":"Public"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing visibility", userID: testutil.TestUserID, body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", userID: testutil.TestUserID, body: `visibility=public`, wantStatus: http.StatusBadRequest},
+		{name: "anonymous", body: `{"visibility":"public"}`, wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", userID: testutil.TestUserID, body: `{"visibility":"public"}`, err: errors.New("user not found"), wantStatus: http.StatusNotFound, wantStored: "public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := ""
+			userStore := &mock.UserStore{
+				UpdateResumeVisibilityFunc: func(ctx context.Context, userID, visibility string) error {
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					stored = visibility
+					return tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodPatch, "/api/user/resume/visibility", tt.body), tt.userID)
+			w := serve(t, handleUpdateResumeVisibility(userStore), r, tt.wantStatus)
+			if stored != tt.wantStored {
+				t.Errorf("stored visibility = %q, want %q", stored, tt.wantStored)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if got, want := strings.TrimSpace(w.Body.String()), `{"resume_visibility":"`+tt.wantStored+`"}`; got != want {
+					t.Errorf("body = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleDownloadResume(t *testing.T) {
+	const otherCollegeID = "cccccccc-0000-0000-0000-000000000000"
+	cfg := &env.Config{AWSRegion: "us-east-1", AWSResumeBucket: "resumes", AWSAccessKeyID: "test", AWSSecretAccessKey: "test"}
+	callers := map[string]*store.User{
+		"same college":      {ID: testutil.TestAdminID, CollegeID: testutil.TestCollegeID},
+		"different college": {ID: testutil.TestAdminID, CollegeID: otherCollegeID},
+		"no college":        {ID: testutil.TestAdminID},
+	}
+	tests := []struct {
+		visibility string
+		caller     string // anonymous, owner or a key of callers
+		wantStatus int
+	}{
+		{visibility: "public", caller: "anonymous", wantStatus: http.StatusFound},
+		{visibility: "public", caller: "same college", wantStatus: http.StatusFound},
+		{visibility: "public", caller: "different college", wantStatus: http.StatusFound},
+		{visibility: "public", caller: "owner", wantStatus: http.StatusFound},
+		{visibility: "college_only", caller: "anonymous", wantStatus: http.StatusUnauthorized},
+		{visibility: "college_only", caller: "same college", wantStatus: http.StatusFound},
+		{visibility: "college_only", caller: "different college", wantStatus: http.StatusForbidden},
+		{visibility: "college_only", caller: "no college", wantStatus: http.StatusForbidden},
+		{visibility: "college_only", caller: "owner", wantStatus: http.StatusFound},
+		{visibility: "private", caller: "anonymous", wantStatus: http.StatusForbidden},
+		{visibility: "private", caller: "same college", wantStatus: http.StatusForbidden},
+		{visibility: "private", caller: "different college", wantStatus: http.StatusForbidden},
+		{visibility: "private", caller: "owner", wantStatus: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.visibility+" "+tt.caller, func(t *testing.T) {
+			owner := &store.User{ID: testutil.TestUserID, CollegeID: testutil.TestCollegeID, ResumeVisibility: tt.visibility}
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					if userID == owner.ID {
+						return owner, nil
+					}
+					if caller, ok := callers[tt.caller]; ok && userID == caller.ID {
+						return caller, nil
+					}
+					t.Errorf("GetUserByID(%q)", userID)
+					return nil, errors.New("user not found")
+				},
+				GetUserS3KeysFunc: func(ctx context.Context, userID string) (string, string, error) {
+					return "resumes/" + userID + ".pdf", "", nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/user/x/resume/download", ""), "id", owner.ID)
+			switch tt.caller {
+			case "anonymous":
+			case "owner":
+				r = withUserID(r, owner.ID)
+			default:
+				r = withUserID(r, callers[tt.caller].ID)
+			}
+			w := serve(t, handleDownloadResume(userStore, cfg), r, tt.wantStatus)
+			if tt.wantStatus != http.StatusFound {
+				return
+			}
+			location := w.Header().Get("Location")
+			if !strings.Contains(location, "/resumes/"+owner.ID+".pdf") || !strings.Contains(location, "X-Amz-Expires=900") {
+				t.Errorf("Location = %q, want a 15 minute presigned URL for the owner's resume", location)
+			}
+		})
+	}
+}
+
+func TestHandleDownloadResumeNotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		userErr   error
+		resumeKey string
+	}{
+		{name: "unknown user", userErr: errors.New("user not found")},
+		{name: "no resume"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := &mock.UserStore{
+				GetUserByIDFunc: func(ctx context.Context, userID string) (*store.User, error) {
+					if tt.userErr != nil {
+						return nil, tt.userErr
+					}
+					return &store.User{ID: userID, ResumeVisibility: "public"}, nil
+				},
+				GetUserS3KeysFunc: func(ctx context.Context, userID string) (string, string, error) {
+					return tt.resumeKey, "", nil
+				},
+			}
+
+			r := withURLParams(newTestRequest(http.MethodGet, "/api/user/x/resume/download", ""), "id", testutil.TestUserID)
+			serve(t, handleDownloadResume(userStore, &env.Config{}), r, http.StatusNotFound)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+const (
+	// maxXPAdjustment is the largest amount of XP one adjustment may grant or deduct
+	maxXPAdjustment = 10000
+	// minXPAdjustReasonLength keeps admins from adjusting XP without a real justification
+	minXPAdjustReasonLength = 10
+)
+
+// AdjustXPRequest is the body of the admin XP award and deduction endpoints
+type AdjustXPRequest struct {
+	XP            int    `json:"xp"`                       // Positive awards XP, negative deducts it
+	Amount        int    `json:"amount,omitempty"`         // Deprecated: same as xp, used when xp is not set
+	Reason        string `json:"reason"`                   // Required, at least 10 characters
+	Source        string `json:"source,omitempty"`         // Deprecated: only admin_grant is accepted; the logged source follows the sign of xp
+	AllowNegative bool   `json:"allow_negative,omitempty"` // Deduct even if it is more than the user has; XP stops at 0
+}
+
+// AdjustXPResponse is returned after an admin XP adjustment
+type AdjustXPResponse struct {
+	UserID  string `json:"user_id"`
+	Amount  int    `json:"amount"` // XP actually changed; a deduction stops at 0 XP
+	OldXP   int    `json:"old_xp"`
+	NewXP   int    `json:"new_xp"`
+	XPLogID string `json:"xp_log_id"`
+}
+
+// handleAdjustUserXP manually awards or deducts a user's XP (admin)
+// @Summary      Award or deduct user XP
+// @Description  Award (positive xp) or deduct (negative xp) up to 10000 XP for manual events such as event prizes or dispute resolution, with a reason of at least 10 characters. A deduction larger than the user's XP is rejected unless allow_negative is true, in which case XP stops at 0. Logged in xp_logs with source admin_grant or admin_deduct and the admin's ID as source_id, and in the admin audit log; the user is notified over WebSocket and leaderboards are updated. Admin grants are not subject to daily XP caps. /xp/adjust is the older path and also accepts amount in place of xp. Requires the manage_users permission.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string           true  "User ID"
+// @Param        request  body      AdjustXPRequest  true  "xp, reason and allow_negative"
+// @Success      200      {object}  AdjustXPResponse  "XP adjusted"
+// @Failure      400      {string}  string  "Bad request - invalid xp, reason or source, or deduction would go below 0"
+// @Failure      401      {string}  string  "Unauthorized"
+// @Failure      403      {string}  string  "Forbidden - manage_users permission required"
+// @Failure      404      {string}  string  "User not found"
+// @Failure      500      {string}  string  "Internal server error"
+// @Router       /admin/users/{id}/xp [post]
+// @Router       /admin/users/{id}/xp/adjust [post]
+func handleAdjustUserXP(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		adminID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		userID := chi.URLParam(r, "id")
+		if userID == "" {
+			http.Error(w, "User ID is required", http.StatusBadRequest)
+			return
+		}
+
+		var req AdjustXPRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.XP == 0 {
+			req.XP = req.Amount
+		}
+
+		if req.XP == 0 {
+			http.Error(w, "xp must not be 0", http.StatusBadRequest)
+			return
+		}
+		if req.XP > maxXPAdjustment || req.XP < -maxXPAdjustment {
+			http.Error(w, fmt.Sprintf("xp must be between -%d and %d", maxXPAdjustment, maxXPAdjustment), http.StatusBadRequest)
+			return
+		}
+		if utf8.RuneCountInString(req.Reason) < minXPAdjustReasonLength {
+			http.Error(w, fmt.Sprintf("reason must be at least %d characters", minXPAdjustReasonLength), http.StatusBadRequest)
+			return
+		}
+		if req.Source != "" && req.Source != string(store.XPSourceAdminGrant) {
+			http.Error(w, "source must be admin_grant", http.StatusBadRequest)
+			return
+		}
+
+		xpStore := store.NewXPStore(postgres)
+		xpLog, err := xpStore.AdjustXP(ctx, adminID, userID, req.XP, req.Reason, req.AllowNegative)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, store.ErrInsufficientXP) {
+				http.Error(w, fmt.Sprintf("%v; pass allow_negative to deduct down to 0", err), http.StatusBadRequest)
+				return
+			}
+			log.Printf("Error adjusting XP of user %s: %v", userID, err)
+			http.Error(w, "Failed to adjust XP", http.StatusInternalServerError)
+			return
+		}
+
+		recordXPAdjustment(ctx, postgres, redisClient, adminID, req.Reason, xpLog)
+
+		response := AdjustXPResponse{
+			UserID:  userID,
+			Amount:  xpLog.XP,
+			OldXP:   xpLog.NewXP - xpLog.XP,
+			NewXP:   xpLog.NewXP,
+			XPLogID: xpLog.ID,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// recordXPAdjustment runs the side effects of an admin XP change: the admin audit log entry,
+// the user's WebSocket notifications and the leaderboard updates. Failures are only logged.
+func recordXPAdjustment(ctx context.Context, postgres *db.Postgres, redisClient *db.Redis, adminID, reason string, xpLog *store.XPLog) {
+	userID := xpLog.UserID
+	notifyLevelUp(xpLog)
+
+	oldXP := xpLog.NewXP - xpLog.XP
+
+	auditStore := store.NewAuditLogStore(postgres)
+	if err := auditStore.Record(ctx, adminID, store.AuditActionUserXPAdjust, map[string]interface{}{
+		"user_id":   userID,
+		"amount":    xpLog.XP,
+		"old_xp":    oldXP,
+		"new_xp":    xpLog.NewXP,
+		"reason":    reason,
+		"xp_log_id": xpLog.ID,
+	}); err != nil {
+		log.Printf("Error recording XP adjustment audit log: %v", err)
+	}
+	log.Printf("Admin %s adjusted XP of user %s by %+d (%q)", adminID, userID, xpLog.XP, reason)
+
+	if wsHub := ws.GetNotificationHub(); wsHub != nil {
+		if err := ws.SendXPAdjustedNotification(wsHub, userID, xpLog.XP, xpLog.NewXP, reason); err != nil {
+			log.Printf("Error sending XP adjustment notification to user %s: %v", userID, err)
+		}
+	}
+
+	user, err := store.NewUserStore(postgres).GetUserByID(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user after XP adjustment: %v", err)
+		return
+	}
+	ws.BroadcastUserXPChange(ctx, redisClient, user)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+// Requests rejected before the database is touched
+func TestHandleAdjustUserXPValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		wantStatus int
+	}{
+		{name: "missing user ID", body: `{"amount":10,"reason":"event prize winner"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: testutil.TestUserID, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "zero amount", userID: testutil.TestUserID, body: `{"amount":0,"reason":"event prize winner"}`, wantStatus: http.StatusBadRequest},
+		{name: "amount too large", userID: testutil.TestUserID, body: `{"amount":10001,"reason":"event prize winner"}`, wantStatus: http.StatusBadRequest},
+		{name: "deduction too large", userID: testutil.TestUserID, body: `{"amount":-10001,"reason":"event prize winner"}`, wantStatus: http.StatusBadRequest},
+		{name: "reason too short", userID: testutil.TestUserID, body: `{"amount":10,"reason":"  prize    "}`, wantStatus: http.StatusBadRequest},
+		{name: "other source", userID: testutil.TestUserID, body: `{"amount":10,"reason":"event prize winner","source":"task_approval"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := withAdmin(newTestRequest(http.MethodPost, "/admin/users/x/xp/adjust", tt.body), testutil.TestAdminID)
+			r = withURLParams(r, "id", tt.userID)
+			serve(t, handleAdjustUserXP(nil, nil), r, tt.wantStatus)
+		})
+	}
+}
+
+// Both paths share one handler, so /xp/adjust's amount and /xp's xp behave alike
+func TestHandleAdjustUserXP(t *testing.T) {
+	logColumns := []string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"}
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+	const reason = "abuse penalty applied"
+	// expectDeduction expects 100 XP deducted from a user who has 40; logged is false when it is rejected
+	expectDeduction := func(mockDB *testutil.MockPostgres, logged bool) {
+		mockDB.ExpectBegin()
+		mockDB.ExpectQuery(`SELECT xp, level FROM users WHERE id = \$1 FOR UPDATE`).
+			WithArgs(testutil.TestUserID).
+			WillReturnRows([]string{"xp", "level"}, []any{int64(40), int64(1)})
+		if !logged {
+			mockDB.ExpectRollback()
+			return
+		}
+		mockDB.ExpectExec(`UPDATE users SET xp = \$1 WHERE id = \$2`).
+			WithArgs(0, testutil.TestUserID).
+			WillReturnResult(1)
+		mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+			WithArgs(testutil.AnyArg(), testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, -40).
+			WillReturnRows(logColumns, []any{"log-1", testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, int64(-40), testutil.TestTime})
+		mockDB.ExpectQuery(`SELECT level FROM levels`).
+			WithArgs(0).
+			WillReturnRows([]string{"level"}, []any{int64(1)})
+		mockDB.ExpectCommit()
+	}
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		logged     bool
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "deduction below 0", target: "/admin/users/x/xp", body: `{"xp":-100,"reason":"` + reason + `"}`, wantStatus: http.StatusBadRequest,
+			wantBody: "deduction exceeds the user's XP: user has 40 XP; pass allow_negative to deduct down to 0\n"},
+		{name: "amount below 0 on the older path", target: "/admin/users/x/xp/adjust", body: `{"amount":-100,"reason":"` + reason + `"}`, wantStatus: http.StatusBadRequest,
+			wantBody: "deduction exceeds the user's XP: user has 40 XP; pass allow_negative to deduct down to 0\n"},
+		{name: "allow_negative stops at 0", target: "/admin/users/x/xp", body: `{"xp":-100,"reason":"` + reason + `","allow_negative":true}`, logged: true, wantStatus: http.StatusOK,
+			wantBody: `{"user_id":"` + testutil.TestUserID + `","amount":-40,"old_xp":40,"new_xp":0,"xp_log_id":"log-1"}` + "\n"},
+		{name: "allow_negative on the older path", target: "/admin/users/x/xp/adjust", body: `{"amount":-100,"reason":"` + reason + `","allow_negative":true}`, logged: true, wantStatus: http.StatusOK,
+			wantBody: `{"user_id":"` + testutil.TestUserID + `","amount":-40,"old_xp":40,"new_xp":0,"xp_log_id":"log-1"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			// An offline user's notification is stored through the hub's database
+			previous := ws.GetNotificationHub()
+			t.Cleanup(func() { ws.SetHub(previous) })
+			ws.SetHub(ws.NewHub(nil, postgres))
+
+			expectDeduction(mockDB, tt.logged)
+			if tt.logged {
+				mockDB.ExpectExec(`INSERT INTO admin_audit_log \(admin_id, action, details\)`).
+					WithArgs(testutil.TestAdminID, store.AuditActionUserXPAdjust,
+						[]byte(`{"amount":-40,"new_xp":0,"old_xp":40,"reason":"`+reason+`","user_id":"`+testutil.TestUserID+`","xp_log_id":"log-1"}`)).
+					WillReturnResult(1)
+				mockDB.ExpectExec(`INSERT INTO notifications \(user_id, title, body, type, data\)`).
+					WithArgs(testutil.TestUserID, "XP Adjusted", "An admin adjusted your XP by -40 ("+reason+").", "xp_adjusted",
+						[]byte(`{"amount":-40,"new_xp":0,"reason":"`+reason+`"}`)).
+					WillReturnResult(1)
+				mockDB.ExpectQuery(`FROM users u\s+LEFT JOIN states s`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows(userColumns, []any{testutil.TestUserID, "Test User", "test.user@example.com", "9876543210", testutil.TestStateID, testutil.TestCollegeID, "student", int64(0), int64(1), int64(0), "", "", "", "public", "ABC123", nil, nil, testutil.TestTime, "", ""})
+			}
+
+			r := withAdmin(newTestRequest(http.MethodPost, tt.target, tt.body), testutil.TestAdminID)
+			r = withURLParams(r, "id", testutil.TestUserID)
+			w := serve(t, handleAdjustUserXP(postgres, nil), r, tt.wantStatus)
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
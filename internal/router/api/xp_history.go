@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// handleGetXPHistory returns the authenticated user's most recent XP log entries
+// @Summary      Get my XP history
+// @Description  Get the authenticated user's XP log, newest first. Each entry includes source_label, a human-readable name of the source (e.g. "Task Approval", "Daily Login"). Deductions have negative xp.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query     int  false  "Number of entries (default 20, max 100)"
+// @Success      200    {array}   store.XPLog  "XP log entries"
+// @Failure      401    {string}  string  "Unauthorized"
+// @Failure      500    {string}  string  "Internal server error"
+// @Router       /api/user/me/xp-history [get]
+func handleGetXPHistory(xpStore store.XPStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		limit := 20
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+				limit = l
+			}
+		}
+		if limit > 100 {
+			limit = 100
+		}
+
+		logs, err := xpStore.GetXPLogs(ctx, userID, limit)
+		if err != nil {
+			log.Printf("Error getting XP history for user %s: %v", userID, err)
+			http.Error(w, "Failed to get XP history", http.StatusInternalServerError)
+			return
+		}
+		if logs == nil {
+			logs = []store.XPLog{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(logs); err != nil {
+			log.Printf("Error encoding XP history response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleGetXPSummary returns the authenticated user's total XP, level and XP earned this week and month
+// @Summary      Get my XP summary
+// @Description  Get the authenticated user's total XP and level with the net XP earned since the start of the current week (Monday) and month.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  store.XPSummary  "XP summary"
+// @Failure      401  {string}  string  "Unauthorized"
+// @Failure      404  {string}  string  "User not found"
+// @Failure      500  {string}  string  "Internal server error"
+// @Router       /api/user/me/xp-summary [get]
+func handleGetXPSummary(xpStore store.XPStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		userID, ok := GetUserIDFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		summary, err := xpStore.GetXPSummary(ctx, userID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting XP summary for user %s: %v", userID, err)
+			http.Error(w, "Failed to get XP summary", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			log.Printf("Error encoding XP summary response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHandleGetXPHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		query      string
+		logs       []store.XPLog
+		err        error
+		wantStatus int
+		wantLimit  int
+		wantBody   string
+	}{
+		{name: "default limit", userID: testutil.TestUserID, logs: []store.XPLog{*testutil.NewTestXPLog()}, wantStatus: http.StatusOK, wantLimit: 20},
+		{name: "custom limit", userID: testutil.TestUserID, query: "?limit=5", wantStatus: http.StatusOK, wantLimit: 5},
+		{name: "limit capped at 100", userID: testutil.TestUserID, query: "?limit=1000", wantStatus: http.StatusOK, wantLimit: 100},
+		{name: "invalid limit ignored", userID: testutil.TestUserID, query: "?limit=-3", wantStatus: http.StatusOK, wantLimit: 20},
+		{name: "no history is an empty array", userID: testutil.TestUserID, wantStatus: http.StatusOK, wantLimit: 20, wantBody: "[]"},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantLimit: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xpStore := &mock.XPStore{
+				GetXPLogsFunc: func(ctx context.Context, userID string, limit int) ([]store.XPLog, error) {
+					if limit != tt.wantLimit {
+						t.Errorf("limit = %d, want %d", limit, tt.wantLimit)
+					}
+					return tt.logs, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/xp-history"+tt.query, ""), tt.userID)
+			w := serve(t, handleGetXPHistory(xpStore), r, tt.wantStatus)
+			if tt.wantBody != "" && strings.TrimSpace(w.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandleGetXPSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		summary    *store.XPSummary
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "summary",
+			userID:     testutil.TestUserID,
+			summary:    &store.XPSummary{TotalXP: 1200, XPThisWeek: 150, XPThisMonth: 600, Level: 4},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"total_xp":1200,"xp_this_week":150,"xp_this_month":600,"level":4}`,
+		},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", userID: testutil.TestUserID, err: errors.New("user not found"), wantStatus: http.StatusNotFound},
+		{name: "store error", userID: testutil.TestUserID, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xpStore := &mock.XPStore{
+				GetXPSummaryFunc: func(ctx context.Context, userID string) (*store.XPSummary, error) {
+					if userID != tt.userID {
+						t.Errorf("userID = %q, want %q", userID, tt.userID)
+					}
+					return tt.summary, tt.err
+				},
+			}
+
+			r := withUserID(newTestRequest(http.MethodGet, "/api/user/me/xp-summary", ""), tt.userID)
+			w := serve(t, handleGetXPSummary(xpStore), r, tt.wantStatus)
+			if tt.wantBody != "" && strings.TrimSpace(w.Body.String()) != tt.wantBody {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
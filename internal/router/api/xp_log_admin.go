@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// xpLogCSVFlushEvery is how many CSV rows are buffered before flushing to the client
+const xpLogCSVFlushEvery = 500
+
+// XPLogsResponse is one page of the global XP audit trail
+type XPLogsResponse struct {
+	Logs     []store.XPLogWithUser `json:"logs"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+// parseXPLogTime parses an RFC 3339 timestamp or a YYYY-MM-DD date.
+// A date used as the upper bound covers the whole day.
+func parseXPLogTime(value string, endOfDay bool) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// handleGetXPLogs returns the XP audit trail across all users (admin)
+// @Summary      Get XP logs
+// @Description  List XP log entries for all users, newest first, with the user's name and email. Filter by user_id, source and a created_at range. With format=csv the full filtered result is streamed as a CSV attachment (pagination is ignored). Admin only.
+// @Tags         admin
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        user_id    query     string  false  "Only logs for this user"
+// @Param        source     query     string  false  "Only logs with this source (task_approval, user_add, admin_grant, ...)"
+// @Param        from       query     string  false  "Earliest created_at (RFC 3339 or YYYY-MM-DD)"
+// @Param        to         query     string  false  "Latest created_at (RFC 3339, exclusive, or YYYY-MM-DD, inclusive)"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Items per page (default 100, max 1000)"
+// @Param        format     query     string  false  "json (default) or csv"
+// @Success      200        {object}  XPLogsResponse  "XP logs"
+// @Failure      400        {string}  string  "Bad request - invalid date or format"
+// @Failure      401        {string}  string  "Unauthorized"
+// @Failure      500        {string}  string  "Internal server error"
+// @Router       /admin/xp-logs [get]
+func handleGetXPLogs(xpStore store.XPStoreInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+
+		filter := store.XPLogFilter{
+			UserID:   query.Get("user_id"),
+			Source:   query.Get("source"),
+			Page:     1,
+			PageSize: 100,
+		}
+		if fromStr := query.Get("from"); fromStr != "" {
+			from, err := parseXPLogTime(fromStr, false)
+			if err != nil {
+				http.Error(w, "from must be an RFC 3339 timestamp or YYYY-MM-DD date", http.StatusBadRequest)
+				return
+			}
+			filter.From = from
+		}
+		if toStr := query.Get("to"); toStr != "" {
+			to, err := parseXPLogTime(toStr, true)
+			if err != nil {
+				http.Error(w, "to must be an RFC 3339 timestamp or YYYY-MM-DD date", http.StatusBadRequest)
+				return
+			}
+			filter.To = to
+		}
+		if pageStr := query.Get("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				filter.Page = p
+			}
+		}
+		if pageSizeStr := query.Get("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 {
+				filter.PageSize = ps
+			}
+		}
+		if filter.PageSize > 1000 {
+			filter.PageSize = 1000
+		}
+
+		switch query.Get("format") {
+		case "", "json":
+		case "csv":
+			streamXPLogsCSV(w, r, xpStore, filter)
+			return
+		default:
+			http.Error(w, "format must be json or csv", http.StatusBadRequest)
+			return
+		}
+
+		logs, err := xpStore.GetAllXPLogs(ctx, filter)
+		if err != nil {
+			log.Printf("Error getting XP logs: %v", err)
+			http.Error(w, fmt.Sprintf("Failed to get XP logs: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := XPLogsResponse{
+			Logs:     logs,
+			Page:     filter.Page,
+			PageSize: filter.PageSize,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding XP logs response: %v", err)
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// streamXPLogsCSV writes every XP log matching filter (ignoring pagination) as a CSV attachment
+func streamXPLogsCSV(w http.ResponseWriter, r *http.Request, xpStore store.XPStoreInterface, filter store.XPLogFilter) {
+	filter.Page = 0
+	filter.PageSize = 0
+
+	filename := fmt.Sprintf("xp_logs_%s.csv", time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"id", "created_at", "user_id", "user_name", "user_email", "source", "source_id", "reason", "xp"})
+
+	rowCount := 0
+	err := xpStore.StreamXPLogs(r.Context(), filter, func(entry store.XPLogWithUser) error {
+		if err := writer.Write([]string{
+			entry.ID,
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.UserID,
+			entry.UserName,
+			entry.UserEmail,
+			entry.Source,
+			entry.SourceID,
+			entry.Reason,
+			strconv.Itoa(entry.XP),
+		}); err != nil {
+			return err
+		}
+		rowCount++
+		if rowCount%xpLogCSVFlushEvery == 0 {
+			writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+	writer.Flush()
+
+	// Headers are already sent, so a failure part-way can only be logged
+	if err != nil {
+		log.Printf("Error streaming XP logs CSV after %d rows: %v", rowCount, err)
+	} else if err := writer.Error(); err != nil {
+		log.Printf("Error writing XP logs CSV: %v", err)
+	}
+}
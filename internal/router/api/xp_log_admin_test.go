@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/store/mock"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestParseXPLogTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "RFC 3339", value: "2025-03-01T10:30:00Z", want: time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "RFC 3339 upper bound is exact", value: "2025-03-01T10:30:00Z", endOfDay: true, want: time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "date as lower bound", value: "2025-03-01", want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "date as upper bound covers the day", value: "2025-03-01", endOfDay: true, want: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "upper bound at month end", value: "2025-02-28", endOfDay: true, want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "not a time", value: "yesterday", wantErr: true},
+		{name: "wrong date order", value: "01-03-2025", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseXPLogTime(tt.value, tt.endOfDay)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseXPLogTime(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseXPLogTime(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseXPLogTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGetXPLogs(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		err          error
+		wantStatus   int
+		wantPage     int
+		wantPageSize int
+		wantCSV      bool
+	}{
+		{name: "first page by default", wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 100},
+		{name: "page and size", query: "?page=3&page_size=50", wantStatus: http.StatusOK, wantPage: 3, wantPageSize: 50},
+		{name: "page size capped", query: "?page_size=5000", wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 1000},
+		{name: "date range", query: "?from=2025-01-01&to=2025-01-31", wantStatus: http.StatusOK, wantPage: 1, wantPageSize: 100},
+		{name: "invalid from", query: "?from=soon", wantStatus: http.StatusBadRequest},
+		{name: "invalid to", query: "?to=later", wantStatus: http.StatusBadRequest},
+		{name: "unknown format", query: "?format=xml", wantStatus: http.StatusBadRequest},
+		{name: "CSV export ignores pagination", query: "?format=csv&page=2", wantStatus: http.StatusOK, wantCSV: true},
+		{name: "store error", err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError, wantPage: 1, wantPageSize: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := store.XPLogWithUser{XPLog: *testutil.NewTestXPLog(), UserName: "Test User", UserEmail: "test.user@example.com"}
+			xpStore := &mock.XPStore{
+				GetAllXPLogsFunc: func(ctx context.Context, filter store.XPLogFilter) ([]store.XPLogWithUser, error) {
+					if filter.Page != tt.wantPage || filter.PageSize != tt.wantPageSize {
+						t.Errorf("page %d size %d, want page %d size %d", filter.Page, filter.PageSize, tt.wantPage, tt.wantPageSize)
+					}
+					return []store.XPLogWithUser{entry}, tt.err
+				},
+				StreamXPLogsFunc: func(ctx context.Context, filter store.XPLogFilter, fn func(store.XPLogWithUser) error) error {
+					if filter.Page != 0 || filter.PageSize != 0 {
+						t.Errorf("CSV export paginated: page %d size %d", filter.Page, filter.PageSize)
+					}
+					return fn(entry)
+				},
+			}
+
+			w := serve(t, handleGetXPLogs(xpStore), newTestRequest(http.MethodGet, "/admin/xp-logs"+tt.query, ""), tt.wantStatus)
+			if !tt.wantCSV {
+				return
+			}
+			lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+			if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,created_at,user_id") || !strings.Contains(lines[1], "test.user@example.com") {
+				t.Errorf("CSV = %q, want a header and one row", w.Body.String())
+			}
+		})
+	}
+}
@@ -1,43 +1,88 @@
 package router
 
 import (
-	// "net/http"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	appmiddleware "github.com/rohit21755/groveserverv2/internal/middleware"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
 	"github.com/rohit21755/groveserverv2/internal/router/api"
 	"github.com/rohit21755/groveserverv2/internal/router/graphql"
 	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/worker"
 	_ "github.com/rohit21755/groveserverv2/docs" // swagger docs
 )
 
-func SetupRoutes(r *chi.Mux, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) {
-	// Swagger documentation
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"), // The url pointing to API definition
-	))
+// handlerTimeout is the longest any HTTP request may run before chi replies 504
+const handlerTimeout = 60 * time.Second
 
-	// Health check
-	r.Get("/health", api.HandleHealth(postgres, redisClient))
+// isTaskProofUpload matches task submissions, which upload proof files to S3 and can be slow for large videos
+func isTaskProofUpload(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/tasks/") && strings.HasSuffix(r.URL.Path, "/submit")
+}
 
-	// API routes
-	r.Route("/api", func(r chi.Router) {
-		api.SetupAPIRoutes(r, postgres, redisClient, cfg)
-	})
+// unlessWebSocket applies mw to every request except WebSocket upgrades, whose connections are long-lived.
+// GraphQL subscriptions are upgraded on /graphql, the same path as queries.
+func unlessWebSocket(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
 
+// timeouts returns the deadline middleware of every HTTP route. The /ws routes are mounted without it.
+func timeouts(cfg *env.Config) []func(http.Handler) http.Handler {
+	return []func(http.Handler) http.Handler{
+		// Never cut a proof upload short of its own timeout
+		unlessWebSocket(middleware.Timeout(max(handlerTimeout, cfg.S3UploadTimeout))),
+		unlessWebSocket(appmiddleware.DBTimeout(cfg.DBQueryTimeout,
+			appmiddleware.TimeoutOverride{Match: isTaskProofUpload, Timeout: cfg.S3UploadTimeout},
+		)),
+	}
+}
+
+func SetupRoutes(r *chi.Mux, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config, moderator moderation.ImageModerator, xpWorker *worker.XPWorker) {
 	// WebSocket routes
 	r.Route("/ws", func(r chi.Router) {
 		ws.SetupWSRoutes(r, postgres, redisClient, cfg)
 	})
 
-	// Admin routes
-	r.Route("/admin", func(r chi.Router) {
-		api.SetupAdminRoutes(r, postgres, redisClient, cfg)
-	})
+	r.Group(func(r chi.Router) {
+		r.Use(timeouts(cfg)...)
 
-	// GraphQL routes
-	graphql.SetupGraphQLRoutes(r, postgres, redisClient, cfg)
+		// Swagger documentation
+		r.Get("/swagger/*", httpSwagger.Handler(
+			httpSwagger.URL("/swagger/doc.json"), // The url pointing to API definition
+		))
+
+		// Health check
+		r.Get("/health", api.HandleHealth(postgres, redisClient))
+		r.Get("/health/db-pool", api.HandleDBPoolHealth(postgres))
+
+		// API routes
+		r.Route("/api", func(r chi.Router) {
+			api.SetupAPIRoutes(r, postgres, redisClient, cfg, moderator)
+		})
+
+		// Admin routes
+		r.Route("/admin", func(r chi.Router) {
+			api.SetupAdminRoutes(r, postgres, redisClient, cfg, xpWorker)
+		})
+
+		// GraphQL routes
+		graphql.SetupGraphQLRoutes(r, postgres, redisClient, cfg)
+	})
 }
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/rohit21755/groveserverv2/internal/env"
+)
+
+func TestTimeouts(t *testing.T) {
+	cfg := &env.Config{DBQueryTimeout: 10 * time.Second, S3UploadTimeout: 90 * time.Second}
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		upgrade      bool
+		wantDeadline time.Duration // 0 for none
+	}{
+		{name: "API request", method: http.MethodGet, target: "/api/leaderboard/pan-india", wantDeadline: cfg.DBQueryTimeout},
+		{name: "proof upload", method: http.MethodPost, target: "/api/tasks/abc/submit", wantDeadline: cfg.S3UploadTimeout},
+		{name: "task read is not an upload", method: http.MethodGet, target: "/api/tasks/abc/submit", wantDeadline: cfg.DBQueryTimeout},
+		{name: "GraphQL subscription", method: http.MethodGet, target: "/graphql", upgrade: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			r := chi.NewRouter()
+			r.Group(func(r chi.Router) {
+				r.Use(timeouts(cfg)...)
+				r.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
+					deadline, hasDeadline = r.Context().Deadline()
+				})
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+			}
+			start := time.Now()
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantDeadline == 0 {
+				if hasDeadline {
+					t.Errorf("deadline in %v, want none", deadline.Sub(start))
+				}
+				return
+			}
+			if !hasDeadline {
+				t.Fatalf("no deadline, want %v", tt.wantDeadline)
+			}
+			// The 60s handler timeout must not cut the upload short of S3_UPLOAD_TIMEOUT
+			if got := deadline.Sub(start); got < tt.wantDeadline-time.Second || got > tt.wantDeadline+time.Second {
+				t.Errorf("deadline in %v, want %v", got, tt.wantDeadline)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/middleware"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// chatRoomChannelPrefix prefixes the Redis pub/sub channel of each chat room
+const chatRoomChannelPrefix = "chat:room:"
+
+// ChatRoomChannel returns the Redis pub/sub channel new messages of a room are published on.
+// Each instance subscribes to the channels of the rooms its clients have open.
+func ChatRoomChannel(roomID string) string {
+	return chatRoomChannelPrefix + roomID
+}
+
+// ChatClient is a WebSocket connection subscribed to one chat room
+type ChatClient struct {
+	conn   *websocket.Conn
+	send   chan []byte
+	roomID string
+	userID string
+	hub    *ChatHub
+}
+
+// ChatHub keeps the clients of each chat room and forwards the room's messages to them
+type ChatHub struct {
+	// Connected clients by room ID
+	rooms map[string]map[*ChatClient]bool
+
+	// Register requests from clients
+	register chan *ChatClient
+
+	// Unregister requests from clients
+	unregister chan *ChatClient
+
+	// Mutex for thread safety
+	mu sync.RWMutex
+
+	// Redis client for the room channels
+	redisClient *db.Redis
+
+	// Subscription to the channels of the rooms with clients on this instance (nil without Redis)
+	pubsub *redis.PubSub
+}
+
+// NewChatHub creates a new chat hub
+func NewChatHub(redisClient *db.Redis) *ChatHub {
+	return &ChatHub{
+		rooms:       make(map[string]map[*ChatClient]bool),
+		register:    make(chan *ChatClient),
+		unregister:  make(chan *ChatClient),
+		redisClient: redisClient,
+	}
+}
+
+// Run starts the hub
+func (h *ChatHub) Run() {
+	if h.redisClient == nil || h.redisClient.Client == nil {
+		log.Printf("[WS] Redis not configured, chat messages are only delivered on this instance")
+	} else {
+		// Rooms are added to and removed from this subscription as clients come and go
+		h.pubsub = h.redisClient.Client.Subscribe(context.Background())
+		go h.subscribeToRooms()
+	}
+
+	for {
+		select {
+		case client := <-h.register:
+			h.mu.Lock()
+			clients, ok := h.rooms[client.roomID]
+			if !ok {
+				clients = make(map[*ChatClient]bool)
+				h.rooms[client.roomID] = clients
+				h.subscribeRoom(client.roomID)
+			}
+			clients[client] = true
+			h.mu.Unlock()
+			log.Printf("Chat client connected: user_id=%s, room=%s", client.userID, client.roomID)
+
+		case client := <-h.unregister:
+			h.mu.Lock()
+			h.removeClient(client)
+			h.mu.Unlock()
+			log.Printf("Chat client disconnected: user_id=%s, room=%s", client.userID, client.roomID)
+		}
+	}
+}
+
+// removeClient closes a client's send channel and drops the room once it has no clients.
+// The caller must hold the write lock.
+func (h *ChatHub) removeClient(client *ChatClient) {
+	clients, ok := h.rooms[client.roomID]
+	if !ok || !clients[client] {
+		return
+	}
+	delete(clients, client)
+	close(client.send)
+	if len(clients) == 0 {
+		delete(h.rooms, client.roomID)
+		h.unsubscribeRoom(client.roomID)
+	}
+}
+
+// subscribeRoom starts receiving a room's messages from Redis
+func (h *ChatHub) subscribeRoom(roomID string) {
+	if h.pubsub == nil {
+		return
+	}
+	if err := h.pubsub.Subscribe(context.Background(), ChatRoomChannel(roomID)); err != nil {
+		log.Printf("Error subscribing to chat room %s: %v", roomID, err)
+	}
+}
+
+// unsubscribeRoom stops receiving a room's messages from Redis
+func (h *ChatHub) unsubscribeRoom(roomID string) {
+	if h.pubsub == nil {
+		return
+	}
+	if err := h.pubsub.Unsubscribe(context.Background(), ChatRoomChannel(roomID)); err != nil {
+		log.Printf("Error unsubscribing from chat room %s: %v", roomID, err)
+	}
+}
+
+// subscribeToRooms forwards messages published on the room channels to this instance's clients
+func (h *ChatHub) subscribeToRooms() {
+	for msg := range h.pubsub.Channel() {
+		roomID := strings.TrimPrefix(msg.Channel, chatRoomChannelPrefix)
+		h.deliver(roomID, []byte(msg.Payload))
+	}
+}
+
+// deliver sends a message to every client in the room connected to this instance.
+// Clients that can't keep up are disconnected.
+func (h *ChatHub) deliver(roomID string, message []byte) {
+	h.mu.RLock()
+	var toRemove []*ChatClient
+	for client := range h.rooms[roomID] {
+		select {
+		case client.send <- message:
+		default:
+			toRemove = append(toRemove, client)
+		}
+	}
+	h.mu.RUnlock()
+	// Remove and close outside RLock (map write + close must not happen under RLock)
+	if len(toRemove) > 0 {
+		h.mu.Lock()
+		for _, client := range toRemove {
+			h.removeClient(client)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// PublishChatMessage sends a newly posted message to every client subscribed to its room, on all instances.
+// Without Redis the message only reaches clients connected to this instance.
+func PublishChatMessage(ctx context.Context, redisClient *db.Redis, message *store.ChatMessage) error {
+	messageBytes, err := json.Marshal(WSMessage{
+		Type: MessageTypeChat,
+		Data: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	if redisClient == nil || redisClient.Client == nil {
+		if hub := GetChatHub(); hub != nil {
+			hub.deliver(message.RoomID, messageBytes)
+		}
+		return nil
+	}
+
+	if err := redisClient.Client.Publish(ctx, ChatRoomChannel(message.RoomID), messageBytes).Err(); err != nil {
+		return fmt.Errorf("failed to publish chat message: %w", err)
+	}
+	return nil
+}
+
+// readPump keeps the connection alive; messages are posted through the REST API, so anything the client sends is ignored
+func (c *ChatClient) readPump() {
+	defer func() {
+		c.hub.unregister <- c
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		_, _, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("WebSocket error: %v", err)
+			}
+			break
+		}
+	}
+}
+
+// writePump pumps messages from the hub to the WebSocket connection
+func (c *ChatClient) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+
+			w, err := c.conn.NextWriter(websocket.TextMessage)
+			if err != nil {
+				return
+			}
+			w.Write(message)
+
+			// Add queued messages to the current message
+			n := len(c.send)
+			for i := 0; i < n; i++ {
+				w.Write([]byte{'\n'})
+				w.Write(<-c.send)
+			}
+
+			if err := w.Close(); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleChatWS subscribes an authenticated user to the new messages of a chat room they belong to
+func handleChatWS(postgres *db.Postgres, hub *ChatHub, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		roomID := chi.URLParam(r, "roomId")
+		if _, err := uuid.Parse(roomID); err != nil {
+			http.Error(w, "Chat room not found", http.StatusNotFound)
+			return
+		}
+
+		tokenString := requestToken(r)
+		if tokenString == "" {
+			http.Error(w, "Token required", http.StatusUnauthorized)
+			return
+		}
+		claims, err := auth.ValidateToken(tokenString, cfg.JWTSecret)
+		if err != nil || claims.Role == auth.RolePending2FA {
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+		middleware.SetAccessLogUserID(ctx, claims.UserID)
+
+		chatStore := store.NewChatStore(postgres)
+		room, err := chatStore.GetRoomByID(ctx, roomID)
+		if err != nil {
+			if err.Error() == "chat room not found" {
+				http.Error(w, "Chat room not found", http.StatusNotFound)
+				return
+			}
+			log.Printf("Error getting chat room: %v", err)
+			http.Error(w, "Failed to join chat room", http.StatusInternalServerError)
+			return
+		}
+
+		userStore := store.NewUserStore(postgres)
+		user, err := userStore.GetUserByID(ctx, claims.UserID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			log.Printf("Error getting user: %v", err)
+			http.Error(w, "Failed to join chat room", http.StatusInternalServerError)
+			return
+		}
+		banned, err := userStore.IsUserBanned(ctx, user.ID)
+		if err != nil {
+			log.Printf("Error checking ban status for user %s: %v", user.ID, err)
+			http.Error(w, "Failed to verify account status", http.StatusInternalServerError)
+			return
+		}
+		if banned || !room.CanAccess(user) {
+			http.Error(w, "You are not a member of this chat room", http.StatusForbidden)
+			return
+		}
+
+		// Upgrade connection to WebSocket
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("Error upgrading to WebSocket: %v", err)
+			return
+		}
+
+		client := &ChatClient{
+			conn:   conn,
+			send:   make(chan []byte, 256),
+			roomID: room.ID,
+			userID: user.ID,
+			hub:    hub,
+		}
+
+		// Register client
+		hub.register <- client
+
+		// Start pumps
+		go client.writePump()
+		go client.readPump()
+	}
+}
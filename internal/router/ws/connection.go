@@ -1,15 +1,18 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/rohit21755/groveserverv2/internal/auth"
 	"github.com/rohit21755/groveserverv2/internal/env"
+	"github.com/rohit21755/groveserverv2/internal/middleware"
 )
 
 const (
@@ -29,38 +32,60 @@ const (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow all origins for now - customize in production
-		return true
-	},
+	// CheckOrigin is replaced with the ALLOWED_WS_ORIGINS allowlist in SetupWSRoutes
 }
 
-// handleWSConnection handles WebSocket connections with JWT authentication
-func handleWSConnection(hub *Hub, cfg *env.Config) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Get token from query parameter or Authorization header
-		tokenString := r.URL.Query().Get("token")
-		
-		// If token from query param has "Bearer " prefix, remove it
-		if tokenString != "" {
-			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-			tokenString = strings.TrimSpace(tokenString)
+// originChecker allows requests without an Origin header (non-browser clients), same-host
+// origins, and origins on the allowlist
+func originChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowlist := middleware.NewOriginAllowlist(allowedOrigins)
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
 		}
-		
-		if tokenString == "" {
-			// Try Authorization header
-			authHeader := r.Header.Get("Authorization")
-			if authHeader != "" {
-				parts := strings.Split(authHeader, " ")
-				if len(parts) == 2 && parts[0] == "Bearer" {
-					tokenString = parts[1]
-				} else if len(parts) == 1 {
-					// Sometimes the header might not have "Bearer " prefix
-					tokenString = parts[0]
-				}
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		if allowlist.Allowed(origin) {
+			return true
+		}
+		log.Printf("WebSocket connection rejected: origin %s not allowed", origin)
+		return false
+	}
+}
+
+// requestToken returns the JWT sent in the token query parameter or the Authorization header
+func requestToken(r *http.Request) string {
+	// Get token from query parameter or Authorization header
+	tokenString := r.URL.Query().Get("token")
+
+	// If token from query param has "Bearer " prefix, remove it
+	if tokenString != "" {
+		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+		tokenString = strings.TrimSpace(tokenString)
+	}
+
+	if tokenString == "" {
+		// Try Authorization header
+		authHeader := r.Header.Get("Authorization")
+		if authHeader != "" {
+			parts := strings.Split(authHeader, " ")
+			if len(parts) == 2 && parts[0] == "Bearer" {
+				tokenString = parts[1]
+			} else if len(parts) == 1 {
+				// Sometimes the header might not have "Bearer " prefix
+				tokenString = parts[0]
 			}
 		}
+	}
+	return tokenString
+}
 
+// handleWSConnection handles WebSocket connections with JWT authentication
+func handleWSConnection(hub *Hub, cfg *env.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString := requestToken(r)
 		if tokenString == "" {
 			log.Printf("WebSocket connection rejected: No token provided")
 			http.Error(w, "Token required", http.StatusUnauthorized)
@@ -76,6 +101,7 @@ func handleWSConnection(hub *Hub, cfg *env.Config) http.HandlerFunc {
 		}
 		
 		log.Printf("WebSocket connection authenticated: user_id=%s, role=%s", claims.UserID, claims.Role)
+		middleware.SetAccessLogUserID(r.Context(), claims.UserID)
 
 		// Upgrade connection to WebSocket
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -117,6 +143,7 @@ func (c *Client) readPump() {
 		return nil
 	})
 
+	violations := 0
 	for {
 		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
@@ -126,6 +153,22 @@ func (c *Client) readPump() {
 			break
 		}
 
+		// Per-user rate limit; repeated violations close the connection
+		allowed, err := c.allowMessage(context.Background())
+		if err != nil {
+			log.Printf("Error checking WebSocket rate limit for user %s: %v", c.UserID, err)
+		}
+		if !allowed {
+			violations++
+			c.sendToClient(rateLimitExceededMessage)
+			if violations >= maxRateLimitViolations {
+				log.Printf("Closing WebSocket for user %s: rate limit exceeded %d times in a row", c.UserID, violations)
+				break
+			}
+			continue
+		}
+		violations = 0
+
 		// Handle incoming messages (e.g., chat messages, ping/pong)
 		// For now, we just log them
 		var wsMessage WSMessage
@@ -3,11 +3,15 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gorilla/websocket"
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/push"
+	"github.com/rohit21755/groveserverv2/internal/store"
 )
 
 // MessageType represents the type of WebSocket message
@@ -25,12 +29,18 @@ const (
 type NotificationType string
 
 const (
-	NotificationTypeTaskAssigned NotificationType = "task_assigned"
-	NotificationTypeTaskApproved NotificationType = "task_approved"
-	NotificationTypeTaskRejected NotificationType = "task_rejected"
-	NotificationTypeNewFollower  NotificationType = "new_follower"
-	NotificationTypeNewComment   NotificationType = "new_comment"
-	NotificationTypeNewReaction  NotificationType = "new_reaction"
+	NotificationTypeTaskAssigned  NotificationType = "task_assigned"
+	NotificationTypeTaskApproved  NotificationType = "task_approved"
+	NotificationTypeTaskRejected  NotificationType = "task_rejected"
+	NotificationTypeNewFollower   NotificationType = "new_follower"
+	NotificationTypeNewComment    NotificationType = "new_comment"
+	NotificationTypeNewReaction   NotificationType = "new_reaction"
+	NotificationTypeAnnouncement  NotificationType = "announcement"
+	NotificationTypeLevelUp       NotificationType = "level_up"
+	NotificationTypeFlashTask     NotificationType = "flash_task_started"
+	NotificationTypeAccountBanned NotificationType = "account_banned"
+	NotificationTypeXPAdjusted    NotificationType = "xp_adjusted"
+	NotificationTypeReferralBonus NotificationType = "referral_bonus"
 )
 
 // WSMessage represents a WebSocket message
@@ -77,11 +87,17 @@ type Hub struct {
 	// Mutex for thread safety
 	mu sync.RWMutex
 
-	// Redis client for pub/sub
+	// Redis client for pub/sub and the notifications stream
 	redisClient *db.Redis
 
 	// Postgres for database operations
 	postgres *db.Postgres
+
+	// Messages a client may send per minute (DefaultMessageRateLimit when zero)
+	messageRateLimit int
+
+	// Broadcasts dropped because the broadcast channel was full
+	droppedBroadcasts atomic.Int64
 }
 
 // NewHub creates a new WebSocket hub
@@ -98,8 +114,12 @@ func NewHub(redisClient *db.Redis, postgres *db.Postgres) *Hub {
 
 // Run starts the hub
 func (h *Hub) Run() {
-	// Subscribe to Redis pub/sub for notifications
+	// Consume the Redis notifications stream
 	go h.subscribeToNotifications()
+	// Subscribe to Redis pub/sub for announcements broadcast by any instance
+	go h.subscribeToAnnouncements()
+	// Subscribe to Redis pub/sub for newly started flash tasks
+	go h.subscribeToFlashTasks()
 
 	for {
 		select {
@@ -166,9 +186,17 @@ func (h *Hub) SendNotification(userID string, notification NotificationPayload)
 	client, exists := h.clients[userID]
 	if !exists {
 		h.mu.Unlock()
-		// User not connected, store notification in database for later retrieval
-		// TODO: Store notification in database
-		log.Printf("User %s not connected, notification will be stored in database", userID)
+		// User not connected, store notification in database for later retrieval (and the daily digest)
+		if h.postgres != nil {
+			notificationStore := store.NewNotificationStore(h.postgres)
+			if err := notificationStore.CreateNotification(context.Background(), userID, notification.Title, notification.Message, string(notification.Type), notification.Data); err != nil {
+				log.Printf("Error storing notification for offline user %s: %v", userID, err)
+				return err
+			}
+		}
+		log.Printf("User %s not connected, notification stored in database", userID)
+		// Also push to the user's device so they see it without opening the app
+		push.SendToUser(userID, notification.Title, notification.Message)
 		return nil
 	}
 	select {
@@ -200,33 +228,137 @@ func (h *Hub) BroadcastMessage(messageType MessageType, data interface{}) error
 		return err
 	}
 
-	h.broadcast <- messageBytes
+	// Drop rather than block the caller when clients can't keep up
+	select {
+	case h.broadcast <- messageBytes:
+	default:
+		dropped := h.droppedBroadcasts.Add(1)
+		return fmt.Errorf("broadcast channel full, message dropped (%d dropped so far)", dropped)
+	}
 	return nil
 }
 
-// subscribeToNotifications subscribes to Redis pub/sub for notifications
-func (h *Hub) subscribeToNotifications() {
+// BroadcastToAll sends a message directly to every client connected to this hub.
+// Clients whose send channel is full are skipped rather than blocking the caller.
+func BroadcastToAll(hub *Hub, messageType MessageType, data interface{}) error {
+	if hub == nil {
+		return fmt.Errorf("hub is nil")
+	}
+
+	message := WSMessage{
+		Type: messageType,
+		Data: data,
+	}
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	sent := 0
+	for userID, client := range hub.clients {
+		select {
+		case client.Send <- messageBytes:
+			sent++
+		default:
+			log.Printf("Failed to send broadcast to user %s: channel full", userID)
+		}
+	}
+	log.Printf("Broadcast %s message to %d connected clients", messageType, sent)
+	return nil
+}
+
+// subscribeToAnnouncements subscribes to Redis pub/sub for announcements and
+// forwards them to all clients connected to this instance
+func (h *Hub) subscribeToAnnouncements() {
 	if h.redisClient == nil || h.redisClient.Client == nil {
-		log.Printf("[WS] Redis not configured, skipping notification subscription")
+		log.Printf("[WS] Redis not configured, skipping announcement subscription")
+		return
+	}
+	ctx := context.Background()
+	pubsub := h.redisClient.Client.Subscribe(ctx, "announcements")
+
+	ch := pubsub.Channel()
+	for msg := range ch {
+		var announcement NotificationPayload
+		if err := json.Unmarshal([]byte(msg.Payload), &announcement); err != nil {
+			log.Printf("Error unmarshaling announcement: %v", err)
+			continue
+		}
+
+		if err := BroadcastToAll(h, MessageTypeSystem, announcement); err != nil {
+			log.Printf("Error broadcasting announcement: %v", err)
+		}
+	}
+}
+
+// subscribeToFlashTasks subscribes to Redis pub/sub for flash task announcements and
+// forwards them to all clients connected to this instance
+func (h *Hub) subscribeToFlashTasks() {
+	if h.redisClient == nil || h.redisClient.Client == nil {
+		log.Printf("[WS] Redis not configured, skipping flash task subscription")
 		return
 	}
 	ctx := context.Background()
-	pubsub := h.redisClient.Client.Subscribe(ctx, "notifications")
+	pubsub := h.redisClient.Client.Subscribe(ctx, "flash_tasks")
 
 	ch := pubsub.Channel()
 	for msg := range ch {
 		var notification NotificationPayload
 		if err := json.Unmarshal([]byte(msg.Payload), &notification); err != nil {
-			log.Printf("Error unmarshaling notification: %v", err)
+			log.Printf("Error unmarshaling flash task notification: %v", err)
 			continue
 		}
 
-		// Extract user ID from notification data
-		// The notification payload should contain target user ID
+		if err := BroadcastToAll(h, MessageTypeTask, notification); err != nil {
+			log.Printf("Error broadcasting flash task: %v", err)
+		}
+	}
+}
+
+// subscribeToNotifications consumes the notifications stream. The instances share one consumer
+// group, so each notification is handled once: delivered if the user is connected here,
+// otherwise stored for later like any notification to an offline user.
+func (h *Hub) subscribeToNotifications() {
+	if h.redisClient == nil || h.redisClient.Client == nil {
+		log.Printf("[WS] Redis not configured, skipping notification subscription")
+		return
+	}
+
+	consumer := &streamConsumer{
+		redisClient: h.redisClient,
+		stream:      NotificationsStream,
+		group:       NotificationsGroup,
+		consumer:    streamConsumerName(),
+		handle:      h.handleStreamNotification,
+	}
+	consumer.run(context.Background())
+}
+
+// handleStreamNotification delivers one notification read from the notifications stream
+func (h *Hub) handleStreamNotification(values map[string]interface{}) {
+	payload, err := streamPayload(values)
+	if err != nil {
+		log.Printf("Error reading notification: %v", err)
+		return
+	}
+	var notification NotificationPayload
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		log.Printf("Error unmarshaling notification: %v", err)
+		return
+	}
+
+	// The target user is stored alongside the payload; older entries only had it in the notification data
+	userID, _ := values["user_id"].(string)
+	if userID == "" {
 		if notificationData, ok := notification.Data.(map[string]interface{}); ok {
-			if userID, ok := notificationData["user_id"].(string); ok {
-				h.SendNotification(userID, notification)
-			}
+			userID, _ = notificationData["user_id"].(string)
 		}
 	}
+	if userID == "" {
+		log.Printf("Notification %s has no target user, skipping", notification.ID)
+		return
+	}
+	h.SendNotification(userID, notification)
 }
@@ -0,0 +1,75 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestHubSendNotification(t *testing.T) {
+	notification := NotificationPayload{
+		Type:    NotificationTypeTaskApproved,
+		Title:   "Task approved",
+		Message: "You earned 50 XP",
+		Data:    map[string]string{"task_id": testutil.TestTaskID},
+	}
+	wantData := []byte(`{"task_id":"` + testutil.TestTaskID + `"}`)
+	tests := []struct {
+		name      string
+		online    bool
+		insertErr error
+		wantErr   bool
+	}{
+		{name: "online user gets it over the socket", online: true},
+		{name: "offline user has it stored"},
+		{name: "storing fails", insertErr: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			hub := NewHub(nil, postgres)
+			client := &Client{ID: testutil.TestUserID, UserID: testutil.TestUserID, Send: make(chan []byte, 1), Hub: hub}
+			if tt.online {
+				hub.clients[testutil.TestUserID] = client
+			} else {
+				// No expectation for the online case: the mock fails the test on any statement
+				insert := mockDB.ExpectExec(`INSERT INTO notifications \(user_id, title, body, type, data\)`).
+					WithArgs(testutil.TestUserID, notification.Title, notification.Message, string(notification.Type), wantData)
+				if tt.insertErr != nil {
+					insert.WillReturnError(tt.insertErr)
+				} else {
+					insert.WillReturnResult(1)
+				}
+			}
+
+			err := hub.SendNotification(testutil.TestUserID, notification)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.online {
+				if len(client.Send) != 0 {
+					t.Error("offline user's client got a message")
+				}
+				return
+			}
+			select {
+			case raw := <-client.Send:
+				var got struct {
+					Type MessageType         `json:"type"`
+					Data NotificationPayload `json:"data"`
+				}
+				if err := json.Unmarshal(raw, &got); err != nil {
+					t.Fatalf("decoding message: %v", err)
+				}
+				if got.Type != MessageTypeNotification || got.Data.Title != notification.Title {
+					t.Errorf("message = %s", raw)
+				}
+			default:
+				t.Error("online user got no message")
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -41,11 +43,14 @@ type LeaderboardHub struct {
 	// Mutex for thread safety
 	mu sync.RWMutex
 
-	// Redis client for pub/sub
+	// Redis client for the leaderboard stream
 	redisClient *db.Redis
 
 	// Postgres for fetching leaderboard data
 	postgres *db.Postgres
+
+	// Updates dropped because the broadcast channel was past the high-water mark
+	droppedBroadcasts atomic.Int64
 }
 
 // NewLeaderboardHub creates a new leaderboard hub
@@ -62,7 +67,7 @@ func NewLeaderboardHub(redisClient *db.Redis, postgres *db.Postgres) *Leaderboar
 
 // Run starts the hub
 func (h *LeaderboardHub) Run() {
-	// Subscribe to Redis pub/sub for leaderboard updates
+	// Consume the Redis leaderboard stream
 	go h.subscribeToUpdates()
 
 	for {
@@ -97,30 +102,118 @@ func (h *LeaderboardHub) Run() {
 	}
 }
 
-// subscribeToUpdates subscribes to Redis pub/sub for leaderboard updates
+// subscribeToUpdates consumes the leaderboard stream. Each instance reads through its own
+// consumer group so every instance forwards every update to its clients.
 func (h *LeaderboardHub) subscribeToUpdates() {
-	ctx := context.Background()
-	pubsub := h.redisClient.Client.Subscribe(ctx, "leaderboard:updates")
-	defer pubsub.Close()
+	if h.redisClient == nil || h.redisClient.Client == nil {
+		log.Printf("[WS] Redis not configured, skipping leaderboard subscription")
+		return
+	}
+
+	name := streamConsumerName()
+	consumer := &streamConsumer{
+		redisClient: h.redisClient,
+		stream:      LeaderboardStream,
+		group:       leaderboardGroup(name),
+		consumer:    name,
+		handle:      h.handleStreamUpdate,
+	}
+	consumer.run(context.Background())
+}
 
-	ch := pubsub.Channel()
-	for msg := range ch {
-		// Broadcast the update to all connected clients
-		h.broadcast <- []byte(msg.Payload)
+// handleStreamUpdate forwards one update read from the leaderboard stream to connected clients
+func (h *LeaderboardHub) handleStreamUpdate(values map[string]interface{}) {
+	payload, err := streamPayload(values)
+	if err != nil {
+		log.Printf("Error reading leaderboard update: %v", err)
+		return
+	}
+	// Leaderboard sockets are anonymous, so they shed load first under pressure
+	if broadcastNearlyFull(h.broadcast) {
+		if dropped := h.droppedBroadcasts.Add(1); dropped%100 == 1 {
+			log.Printf("Leaderboard broadcast channel over %d%% full, dropping updates (%d dropped so far)", broadcastHighWaterPercent, dropped)
+		}
+		return
 	}
+	// Broadcast the update to all connected clients
+	h.broadcast <- payload
 }
 
-// BroadcastLeaderboardUpdate publishes a leaderboard update to Redis.
-// userID, rank, and xp are the updated user's id, new rank (pan-india), and new XP so clients can update that row.
-func BroadcastLeaderboardUpdate(redisClient *db.Redis, leaderboardType string, scopeID string, userID string, rank int, xp int) {
+// RankingsCacheTTL is how long college and state rankings are cached in Redis
+const RankingsCacheTTL = 5 * time.Minute
+
+// rankingPeriods are the periods rankings can be requested (and cached) for
+var rankingPeriods = []string{"all", "daily", "weekly", "monthly"}
+
+// RankingsCacheKey returns the Redis hash caching pages of the college or state rankings for a period.
+// scope is "college" or "state"; each hash field is one "limit:offset" page.
+func RankingsCacheKey(scope, period string) string {
+	return fmt.Sprintf("leaderboard:%s-rankings:%s", scope, period)
+}
+
+// StatsCacheTTL is how long college and state statistics are cached in Redis
+const StatsCacheTTL = 10 * time.Minute
+
+// StatsCacheKey returns the Redis key caching the statistics of a college or state.
+// scope is "college" or "state".
+func StatsCacheKey(scope, scopeID string) string {
+	return fmt.Sprintf("stats:%s:%s", scope, scopeID)
+}
+
+// UserStatsCacheTTL is how long a user's home screen statistics are cached in Redis
+const UserStatsCacheTTL = 60 * time.Second
+
+// UserStatsCacheKey returns the Redis key caching a user's home screen statistics
+func UserStatsCacheKey(userID string) string {
+	return fmt.Sprintf("stats:%s", userID)
+}
+
+// InvalidateUserStatsCache drops a user's cached home screen statistics.
+// Call it whenever the user's XP, streak or submissions change.
+func InvalidateUserStatsCache(ctx context.Context, redisClient *db.Redis, userID string) {
+	if redisClient == nil || redisClient.Client == nil {
+		return
+	}
+	if err := redisClient.Client.Del(ctx, UserStatsCacheKey(userID)).Err(); err != nil {
+		log.Printf("Error invalidating stats cache for user %s: %v", userID, err)
+	}
+}
+
+// invalidateRankingsCache drops every cached college and state ranking page
+func invalidateRankingsCache(ctx context.Context, redisClient *db.Redis) {
+	keys := make([]string, 0, 2*len(rankingPeriods))
+	for _, period := range rankingPeriods {
+		keys = append(keys, RankingsCacheKey("college", period), RankingsCacheKey("state", period))
+	}
+	if err := redisClient.Client.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Error invalidating rankings cache: %v", err)
+	}
+}
+
+// BroadcastLeaderboardUpdate publishes a leaderboard update to the Redis leaderboard stream so clients refetch that board.
+// The payload carries "scope" (leaderboard type) and "scope_id" (state_id/college_id, empty for pan-india).
+func BroadcastLeaderboardUpdate(redisClient *db.Redis, leaderboardType string, scopeID string) {
+	if redisClient == nil || redisClient.Client == nil {
+		log.Printf("[WS] Redis not configured, skipping leaderboard update")
+		return
+	}
+
 	ctx := context.Background()
+
+	// Aggregate rankings change with every XP award
+	invalidateRankingsCache(ctx, redisClient)
+
+	// So do the statistics of the user's college and state
+	if (leaderboardType == "college" || leaderboardType == "state") && scopeID != "" {
+		if err := redisClient.Client.Del(ctx, StatsCacheKey(leaderboardType, scopeID)).Err(); err != nil {
+			log.Printf("Error invalidating %s stats cache: %v", leaderboardType, err)
+		}
+	}
+
 	update := map[string]interface{}{
 		"type":      "leaderboard_update",
 		"scope":     leaderboardType,
 		"scope_id":  scopeID,
-		"user_id":   userID,
-		"rank":      rank,
-		"xp":        xp,
 		"timestamp": time.Now().Unix(),
 	}
 
@@ -130,12 +223,26 @@ func BroadcastLeaderboardUpdate(redisClient *db.Redis, leaderboardType string, s
 		return
 	}
 
-	err = redisClient.Client.Publish(ctx, "leaderboard:updates", updateJSON).Err()
+	err = publishToStream(ctx, redisClient, LeaderboardStream, updateJSON, nil)
 	if err != nil {
 		log.Printf("Error publishing leaderboard update: %v", err)
 	}
 }
 
+// BroadcastUserXPChange tells every leaderboard a user ranks on that their XP changed: pan-India,
+// and their state and college when set. It drops the user's cached statistics first, so the
+// refetch that the update triggers sees the new XP.
+func BroadcastUserXPChange(ctx context.Context, redisClient *db.Redis, user *store.User) {
+	InvalidateUserStatsCache(ctx, redisClient, user.ID)
+	BroadcastLeaderboardUpdate(redisClient, "pan-india", "")
+	if user.StateID != "" {
+		BroadcastLeaderboardUpdate(redisClient, "state", user.StateID)
+	}
+	if user.CollegeID != "" {
+		BroadcastLeaderboardUpdate(redisClient, "college", user.CollegeID)
+	}
+}
+
 // readPump pumps messages from the WebSocket connection to the hub
 func (c *LeaderboardClient) readPump() {
 	defer func() {
@@ -205,14 +312,7 @@ func (c *LeaderboardClient) writePump() {
 }
 
 // handleLeaderboardWS handles WebSocket connections for leaderboard updates
-func handleLeaderboardWS(postgres *db.Postgres, redisClient *db.Redis) http.HandlerFunc {
-	// Create hub if it doesn't exist (singleton pattern)
-	var hub *LeaderboardHub
-	hubOnce.Do(func() {
-		hub = NewLeaderboardHub(redisClient, postgres)
-		go hub.Run()
-	})
-
+func handleLeaderboardWS(postgres *db.Postgres, leaderboardHub *LeaderboardHub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Upgrade connection to WebSocket
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -235,11 +335,11 @@ func handleLeaderboardWS(postgres *db.Postgres, redisClient *db.Redis) http.Hand
 			send:            make(chan []byte, 256),
 			leaderboardType: leaderboardType,
 			scopeID:         scopeID,
-			hub:             hub,
+			hub:             leaderboardHub,
 		}
 
 		// Register client
-		hub.register <- client
+		leaderboardHub.register <- client
 
 		// Send initial leaderboard data
 		go func() {
@@ -252,7 +352,7 @@ func handleLeaderboardWS(postgres *db.Postgres, redisClient *db.Redis) http.Hand
 			if period == "" {
 				period = "all"
 			}
-			if period != "all" && period != "weekly" && period != "monthly" {
+			if period != "all" && period != "daily" && period != "weekly" && period != "monthly" {
 				period = "all"
 			}
 
@@ -305,8 +405,3 @@ func handleLeaderboardWS(postgres *db.Postgres, redisClient *db.Redis) http.Hand
 		go client.readPump()
 	}
 }
-
-var (
-	hub     *LeaderboardHub
-	hubOnce sync.Once
-)
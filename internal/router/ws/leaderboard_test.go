@@ -0,0 +1,128 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+// Every call site must use the three-argument form; this fails to compile if the signature drifts
+var _ func(*db.Redis, string, string) = BroadcastLeaderboardUpdate
+
+func TestBroadcastLeaderboardUpdate(t *testing.T) {
+	tests := []struct {
+		name            string
+		leaderboardType string
+		scopeID         string
+		wantStatsDrop   bool
+	}{
+		{name: "pan-india", leaderboardType: "pan-india", scopeID: ""},
+		{name: "state", leaderboardType: "state", scopeID: testutil.TestStateID, wantStatsDrop: true},
+		{name: "college", leaderboardType: "college", scopeID: testutil.TestCollegeID, wantStatsDrop: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			statsKey := StatsCacheKey(tt.leaderboardType, tt.scopeID)
+			server.Set(statsKey, "{}")
+			rankingsKey := RankingsCacheKey("college", "all")
+			server.HSet(rankingsKey, "10:0", "[]")
+
+			BroadcastLeaderboardUpdate(redisClient, tt.leaderboardType, tt.scopeID)
+
+			entries, err := redisClient.Client.XRange(context.Background(), LeaderboardStream, "-", "+").Result()
+			if err != nil {
+				t.Fatalf("reading stream: %v", err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("got %d stream entries, want 1", len(entries))
+			}
+			payload, err := streamPayload(entries[0].Values)
+			if err != nil {
+				t.Fatalf("reading payload: %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("decoding payload: %v", err)
+			}
+			if got["type"] != "leaderboard_update" || got["scope"] != tt.leaderboardType || got["scope_id"] != tt.scopeID {
+				t.Errorf("payload = %v, want scope %q and scope_id %q", got, tt.leaderboardType, tt.scopeID)
+			}
+			if _, ok := got["user_id"]; ok {
+				t.Errorf("payload carries user_id: %v", got)
+			}
+
+			if server.Exists(rankingsKey) {
+				t.Error("rankings cache was not invalidated")
+			}
+			if server.Exists(statsKey) == tt.wantStatsDrop {
+				t.Errorf("stats cache kept = %v, want %v", server.Exists(statsKey), !tt.wantStatsDrop)
+			}
+		})
+	}
+}
+
+func TestBroadcastLeaderboardUpdateWithoutRedis(t *testing.T) {
+	// Must not panic when Redis is not configured
+	BroadcastLeaderboardUpdate(nil, "pan-india", "")
+	BroadcastLeaderboardUpdate(&db.Redis{}, "state", testutil.TestStateID)
+}
+
+func TestBroadcastUserXPChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		user       store.User
+		wantScopes []string
+	}{
+		{name: "pan-india only", user: store.User{ID: testutil.TestUserID}, wantScopes: []string{"pan-india:"}},
+		{name: "state", user: store.User{ID: testutil.TestUserID, StateID: testutil.TestStateID}, wantScopes: []string{"pan-india:", "state:" + testutil.TestStateID}},
+		{
+			name:       "state and college",
+			user:       store.User{ID: testutil.TestUserID, StateID: testutil.TestStateID, CollegeID: testutil.TestCollegeID},
+			wantScopes: []string{"pan-india:", "state:" + testutil.TestStateID, "college:" + testutil.TestCollegeID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisClient, server := testutil.NewMockRedis(t)
+			server.Set(UserStatsCacheKey(tt.user.ID), "{}")
+
+			BroadcastUserXPChange(context.Background(), redisClient, &tt.user)
+
+			if server.Exists(UserStatsCacheKey(tt.user.ID)) {
+				t.Error("user stats cache was not invalidated")
+			}
+			entries, err := redisClient.Client.XRange(context.Background(), LeaderboardStream, "-", "+").Result()
+			if err != nil {
+				t.Fatalf("reading stream: %v", err)
+			}
+			var scopes []string
+			for _, entry := range entries {
+				payload, err := streamPayload(entry.Values)
+				if err != nil {
+					t.Fatalf("reading payload: %v", err)
+				}
+				var got map[string]interface{}
+				if err := json.Unmarshal(payload, &got); err != nil {
+					t.Fatalf("decoding payload: %v", err)
+				}
+				// Clients refetch the board; the update carries no per-user rank data
+				if len(got) != 4 || got["type"] != "leaderboard_update" || got["timestamp"] == nil {
+					t.Errorf("payload = %v, want type, scope, scope_id and timestamp only", got)
+				}
+				scopes = append(scopes, fmt.Sprintf("%v:%v", got["scope"], got["scope_id"]))
+			}
+			if strings.Join(scopes, ",") != strings.Join(tt.wantScopes, ",") {
+				t.Errorf("broadcast %v, want %v", scopes, tt.wantScopes)
+			}
+		})
+	}
+}
@@ -75,6 +75,18 @@ func SendTaskApprovalNotification(hub *Hub, userID, taskID, taskTitle string, xp
 	return SendNotification(hub, userID, NotificationTypeTaskApproved, title, message, data)
 }
 
+// SendLevelUpNotification sends a notification when a user reaches a new level
+func SendLevelUpNotification(hub *Hub, userID string, newLevel int) error {
+	data := map[string]interface{}{
+		"level": newLevel,
+	}
+
+	title := "Level Up!"
+	message := fmt.Sprintf("Congratulations! You reached level %d.", newLevel)
+
+	return SendNotification(hub, userID, NotificationTypeLevelUp, title, message, data)
+}
+
 // SendTaskRejectionNotification sends a notification when a task is rejected
 func SendTaskRejectionNotification(hub *Hub, userID, taskID, taskTitle, rejectionComment string) error {
 	data := map[string]interface{}{
@@ -102,7 +114,64 @@ func SendTaskUpdateNotification(hub *Hub, userIDs []string, taskID, taskTitle st
 	return SendNotificationToMultiple(hub, userIDs, NotificationTypeTaskAssigned, title, message, data)
 }
 
-// PublishNotificationToRedis publishes a notification to Redis for distribution
+// SendNewFollowerNotification sends a notification when someone follows a user
+func SendNewFollowerNotification(hub *Hub, userID, followerID, followerName string) error {
+	data := map[string]interface{}{
+		"follower_id":   followerID,
+		"follower_name": followerName,
+	}
+
+	title := "New Follower"
+	message := fmt.Sprintf("%s started following you", followerName)
+
+	return SendNotification(hub, userID, NotificationTypeNewFollower, title, message, data)
+}
+
+// SendAccountBannedNotification tells a user their account has been suspended
+func SendAccountBannedNotification(hub *Hub, userID, reason string) error {
+	data := map[string]interface{}{
+		"reason": reason,
+	}
+
+	title := "Account Suspended"
+	message := "Your account has been suspended"
+	if reason != "" {
+		message = fmt.Sprintf("Your account has been suspended. Reason: %s", reason)
+	}
+
+	return SendNotification(hub, userID, NotificationTypeAccountBanned, title, message, data)
+}
+
+// SendXPAdjustedNotification tells a user an admin changed their XP by amount (negative for a deduction)
+func SendXPAdjustedNotification(hub *Hub, userID string, amount, newXP int, reason string) error {
+	data := map[string]interface{}{
+		"amount": amount,
+		"new_xp": newXP,
+		"reason": reason,
+	}
+
+	title := "XP Adjusted"
+	message := fmt.Sprintf("An admin adjusted your XP by %+d (%s).", amount, reason)
+
+	return SendNotification(hub, userID, NotificationTypeXPAdjusted, title, message, data)
+}
+
+// SendReferralBonusNotification tells a referrer they earned XP because a user they referred completed their first task
+func SendReferralBonusNotification(hub *Hub, userID, referredUserID, referredUserName string, xpAwarded int) error {
+	data := map[string]interface{}{
+		"referred_user_id":   referredUserID,
+		"referred_user_name": referredUserName,
+		"xp_awarded":         xpAwarded,
+	}
+
+	title := "Referral Bonus"
+	message := fmt.Sprintf("%s, who joined with your referral code, completed their first task! You earned %d XP.", referredUserName, xpAwarded)
+
+	return SendNotification(hub, userID, NotificationTypeReferralBonus, title, message, data)
+}
+
+// PublishNotificationToRedis appends a notification for userID to the Redis notifications stream.
+// One instance consumes it and delivers it to the user, or stores it if they are offline.
 func PublishNotificationToRedis(hub *Hub, userID string, notification NotificationPayload) error {
 	if hub == nil || hub.redisClient == nil {
 		return fmt.Errorf("hub or redis client is nil")
@@ -114,7 +183,7 @@ func PublishNotificationToRedis(hub *Hub, userID string, notification Notificati
 	}
 
 	ctx := context.Background()
-	err = hub.redisClient.Client.Publish(ctx, "notifications", notificationBytes).Err()
+	err = publishToStream(ctx, hub.redisClient, NotificationsStream, notificationBytes, map[string]interface{}{"user_id": userID})
 	if err != nil {
 		return fmt.Errorf("failed to publish notification to Redis: %w", err)
 	}
@@ -122,3 +191,83 @@ func PublishNotificationToRedis(hub *Hub, userID string, notification Notificati
 	log.Printf("Published notification to Redis for user %s: %s", userID, notification.Type)
 	return nil
 }
+
+// SendAnnouncement broadcasts an announcement to all online users.
+// When Redis is configured the announcement is published to the "announcements" channel so every
+// server instance (including this one) delivers it to its local clients; otherwise it is sent directly.
+func SendAnnouncement(hub *Hub, id, title, message string, data map[string]interface{}) error {
+	if hub == nil {
+		return fmt.Errorf("hub is nil")
+	}
+
+	announcement := NotificationPayload{
+		ID:        id,
+		Type:      NotificationTypeAnnouncement,
+		Title:     title,
+		Message:   message,
+		Data:      data,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if hub.redisClient == nil || hub.redisClient.Client == nil {
+		return BroadcastToAll(hub, MessageTypeSystem, announcement)
+	}
+
+	announcementBytes, err := json.Marshal(announcement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal announcement: %w", err)
+	}
+
+	ctx := context.Background()
+	err = hub.redisClient.Client.Publish(ctx, "announcements", announcementBytes).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish announcement to Redis: %w", err)
+	}
+
+	log.Printf("Published announcement to Redis: %s", title)
+	return nil
+}
+
+// BroadcastFlashTaskStarted tells every online user that a new flash task has started.
+// Like SendAnnouncement, it goes through Redis ("flash_tasks") so all instances deliver it.
+func BroadcastFlashTaskStarted(hub *Hub, taskID, taskTitle string, xp int, endAt *time.Time) error {
+	if hub == nil {
+		return fmt.Errorf("hub is nil")
+	}
+
+	data := map[string]interface{}{
+		"task_id":    taskID,
+		"task_title": taskTitle,
+		"xp":         xp,
+	}
+	if endAt != nil {
+		data["end_at"] = endAt.UTC().Format(time.RFC3339)
+	}
+
+	notification := NotificationPayload{
+		ID:        uuid.New().String(),
+		Type:      NotificationTypeFlashTask,
+		Title:     "Flash Task Started",
+		Message:   fmt.Sprintf("A new flash task is live: %s", taskTitle),
+		Data:      data,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if hub.redisClient == nil || hub.redisClient.Client == nil {
+		return BroadcastToAll(hub, MessageTypeTask, notification)
+	}
+
+	notificationBytes, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flash task notification: %w", err)
+	}
+
+	ctx := context.Background()
+	err = hub.redisClient.Client.Publish(ctx, "flash_tasks", notificationBytes).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish flash task to Redis: %w", err)
+	}
+
+	log.Printf("Published flash task to Redis: %s", taskID)
+	return nil
+}
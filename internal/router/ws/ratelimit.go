@@ -0,0 +1,73 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultMessageRateLimit is how many messages a client may send per minute when not configured
+	DefaultMessageRateLimit = 30
+
+	// maxRateLimitViolations is how many rate-limited messages in a row close the connection
+	maxRateLimitViolations = 3
+
+	// broadcastHighWaterPercent is how full a broadcast channel may get before messages
+	// for anonymous (leaderboard) connections are dropped
+	broadcastHighWaterPercent = 80
+)
+
+// rateLimitExceededMessage is sent to a client whose message was rejected by the rate limit
+var rateLimitExceededMessage, _ = json.Marshal(map[string]string{
+	"type":    "error",
+	"message": "rate_limit_exceeded",
+})
+
+// messageRateKey is the Redis counter for a user's messages in the current minute
+func messageRateKey(userID string, now time.Time) string {
+	return fmt.Sprintf("ws_rate:%s:%d", userID, now.Unix()/60)
+}
+
+// allowMessage counts a message from the client against its per-minute limit.
+// Messages are allowed when Redis is unavailable.
+func (c *Client) allowMessage(ctx context.Context) (bool, error) {
+	if c.Hub.redisClient == nil || c.Hub.redisClient.Client == nil {
+		return true, nil
+	}
+
+	limit := c.Hub.messageRateLimit
+	if limit <= 0 {
+		limit = DefaultMessageRateLimit
+	}
+
+	key := messageRateKey(c.UserID, time.Now())
+	pipe := c.Hub.redisClient.Client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return true, err
+	}
+
+	return incr.Val() <= int64(limit), nil
+}
+
+// sendToClient queues a message for this client unless it has been replaced or disconnected.
+// The hub lock guards against sending on a channel the hub has closed.
+func (c *Client) sendToClient(message []byte) {
+	c.Hub.mu.RLock()
+	defer c.Hub.mu.RUnlock()
+	if c.Hub.clients[c.UserID] != c {
+		return
+	}
+	select {
+	case c.Send <- message:
+	default:
+	}
+}
+
+// broadcastNearlyFull reports whether a broadcast channel is past the high-water mark
+func broadcastNearlyFull(ch chan []byte) bool {
+	return len(ch)*100 >= cap(ch)*broadcastHighWaterPercent
+}
@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/env"
+)
+
+// HubRegistry holds the process-wide WebSocket hubs.
+// The hubs are read from many goroutines, so they are stored atomically.
+type HubRegistry struct {
+	notifications atomic.Pointer[Hub]
+	leaderboard   atomic.Pointer[LeaderboardHub]
+	chat          atomic.Pointer[ChatHub]
+}
+
+var (
+	hubs     HubRegistry
+	hubsOnce sync.Once
+)
+
+// InitHubs creates and starts the notification hub, the legacy leaderboard hub and the chat hub.
+// Only the first call has an effect; main calls it before starting anything that sends notifications.
+// A notification hub injected with SetHub beforehand is kept.
+func InitHubs(redisClient *db.Redis, postgres *db.Postgres, cfg *env.Config) {
+	hubsOnce.Do(func() {
+		if hubs.notifications.Load() == nil {
+			notifications := NewHub(redisClient, postgres)
+			notifications.messageRateLimit = cfg.WSMessageRateLimit
+			go notifications.Run()
+			hubs.notifications.Store(notifications)
+		}
+
+		leaderboard := NewLeaderboardHub(redisClient, postgres)
+		go leaderboard.Run()
+		hubs.leaderboard.Store(leaderboard)
+
+		chat := NewChatHub(redisClient)
+		go chat.Run()
+		hubs.chat.Store(chat)
+	})
+}
+
+// SetHub replaces the notification hub returned by GetNotificationHub, e.g. to inject a hub in tests.
+// The caller is responsible for running it.
+func SetHub(h *Hub) {
+	hubs.notifications.Store(h)
+}
+
+// GetNotificationHub returns the notification WebSocket hub, or nil before InitHubs has run
+func GetNotificationHub() *Hub {
+	return hubs.notifications.Load()
+}
+
+// GetLeaderboardHub returns the legacy leaderboard WebSocket hub, or nil before InitHubs has run
+func GetLeaderboardHub() *LeaderboardHub {
+	return hubs.leaderboard.Load()
+}
+
+// GetChatHub returns the chat room WebSocket hub, or nil before InitHubs has run
+func GetChatHub() *ChatHub {
+	return hubs.chat.Load()
+}
@@ -0,0 +1,135 @@
+package ws
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/rohit21755/groveserverv2/internal/auth"
+	"github.com/rohit21755/groveserverv2/internal/env"
+)
+
+// The leaderboard singleton is reached through GetLeaderboardHub, like the other hubs
+var (
+	_ func() *LeaderboardHub = GetLeaderboardHub
+	_ func() *Hub            = GetNotificationHub
+	_ func() *ChatHub        = GetChatHub
+)
+
+func TestInitHubsStartsLeaderboardHubOnce(t *testing.T) {
+	InitHubs(nil, nil, &env.Config{})
+	first := GetLeaderboardHub()
+	if first == nil {
+		t.Fatal("GetLeaderboardHub returned nil after InitHubs")
+	}
+	InitHubs(nil, nil, &env.Config{})
+	if GetLeaderboardHub() != first {
+		t.Error("second InitHubs replaced the leaderboard hub")
+	}
+
+	// register is unbuffered, so a send only completes once Run is consuming it
+	client := &LeaderboardClient{send: make(chan []byte, 1), leaderboardType: "pan-india", hub: first}
+	select {
+	case first.register <- client:
+	case <-time.After(time.Second):
+		t.Fatal("leaderboard hub is not running")
+	}
+	first.unregister <- client
+	select {
+	case _, open := <-client.send:
+		if open {
+			t.Error("unregistered client's send channel is still open")
+		}
+	case <-time.After(time.Second):
+		t.Error("leaderboard hub did not unregister the client")
+	}
+}
+
+func TestSetHub(t *testing.T) {
+	previous := GetNotificationHub()
+	t.Cleanup(func() { SetHub(previous) })
+
+	injected := NewHub(nil, nil)
+	SetHub(injected)
+	if GetNotificationHub() != injected {
+		t.Fatal("GetNotificationHub did not return the injected hub")
+	}
+	// InitHubs never replaces an injected hub, whether or not it has already run
+	InitHubs(nil, nil, &env.Config{})
+	if GetNotificationHub() != injected {
+		t.Error("InitHubs replaced the injected hub")
+	}
+}
+
+// Run with -race: upgrades register clients while the hub is read and swapped from other goroutines
+func TestConcurrentUpgrades(t *testing.T) {
+	previous := GetNotificationHub()
+	t.Cleanup(func() { SetHub(previous) })
+
+	hub := NewHub(nil, nil)
+	go hub.Run()
+	SetHub(hub)
+
+	cfg := &env.Config{JWTSecret: "test-secret"}
+	server := httptest.NewServer(handleWSConnection(GetNotificationHub(), cfg))
+	t.Cleanup(server.Close)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	const clients = 20
+	var wg sync.WaitGroup
+	conns := make(chan *websocket.Conn, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(2)
+		userID := fmt.Sprintf("user-%d", i)
+		go func() {
+			defer wg.Done()
+			token, err := auth.GenerateToken(userID, userID+"@example.com", "student", cfg.JWTSecret, time.Minute)
+			if err != nil {
+				t.Errorf("GenerateToken: %v", err)
+				return
+			}
+			conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?token="+token, nil)
+			if err != nil {
+				t.Errorf("Dial: %v", err)
+				return
+			}
+			conns <- conn
+		}()
+		go func() {
+			defer wg.Done()
+			h := GetNotificationHub()
+			SetHub(h)
+			BroadcastToAll(h, MessageTypeNotification, map[string]string{"user_id": userID})
+			h.SendNotification(userID, NotificationPayload{Title: "Hello"})
+		}()
+	}
+	wg.Wait()
+	close(conns)
+	t.Cleanup(func() {
+		for conn := range conns {
+			conn.Close()
+		}
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		hub.mu.RLock()
+		connected := len(hub.clients)
+		hub.mu.RUnlock()
+		if connected == clients {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients registered, want %d", connected, clients)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if GetNotificationHub() != hub {
+		t.Error("concurrent SetHub calls lost the hub")
+	}
+}
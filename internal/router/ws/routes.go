@@ -7,26 +7,22 @@ import (
 	"github.com/rohit21755/groveserverv2/internal/env"
 )
 
-var globalHub *Hub
-
 // SetupWSRoutes sets up WebSocket routes
 func SetupWSRoutes(r chi.Router, postgres *db.Postgres, redisClient *db.Redis, cfg *env.Config) {
-	// Create global hub if not exists
-	if globalHub == nil {
-		globalHub = NewHub(redisClient, postgres)
-		go globalHub.Run()
-	}
+	upgrader.CheckOrigin = originChecker(cfg.AllowedWSOrigins)
+
+	// No-op when main has already started the hubs
+	InitHubs(redisClient, postgres, cfg)
 
 	// Unified WebSocket connection endpoint (requires JWT token)
 	// Connect via: ws://localhost:8080/ws/connect?token=JWT_TOKEN
 	// Or: ws://localhost:8080/ws/connect with Authorization: Bearer JWT_TOKEN header
-	r.Get("/connect", handleWSConnection(globalHub, cfg))
+	r.Get("/connect", handleWSConnection(GetNotificationHub(), cfg))
 
-	// Legacy endpoints (kept for backward compatibility)
-	r.Get("/leaderboard", handleLeaderboardWS(postgres, redisClient))
-}
+	// Chat room subscription (requires JWT token, same as /connect)
+	// Connect via: ws://localhost:8080/ws/chat/{roomId}?token=JWT_TOKEN
+	r.Get("/chat/{roomId}", handleChatWS(postgres, GetChatHub(), cfg))
 
-// GetHub returns the global WebSocket hub
-func GetHub() *Hub {
-	return globalHub
+	// Legacy endpoints (kept for backward compatibility)
+	r.Get("/leaderboard", handleLeaderboardWS(postgres, GetLeaderboardHub()))
 }
@@ -0,0 +1,186 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// Redis Streams carrying user notifications and leaderboard updates between server instances.
+// Unlike pub/sub, entries published while no instance is reading stay in the stream and
+// entries read but not acknowledged (e.g. the instance crashed) are delivered again.
+const (
+	NotificationsStream = "notifications-stream"
+	NotificationsGroup  = "notifications-workers"
+	LeaderboardStream   = "leaderboard-stream"
+
+	// streamMaxLen approximately caps each stream so it cannot grow without bound
+	streamMaxLen = 10000
+	// streamBlockTimeout is how long each XREADGROUP waits for new entries
+	streamBlockTimeout = time.Second
+	// streamReadCount is the most entries read per XREADGROUP call
+	streamReadCount = 100
+	// streamClaimMinIdle is how long an entry must sit unacknowledged before another consumer takes it over
+	streamClaimMinIdle = time.Minute
+	// streamPayloadField holds the JSON message in each stream entry
+	streamPayloadField = "payload"
+)
+
+// leaderboardGroup returns this instance's consumer group on the leaderboard stream.
+// Every instance must see every leaderboard update, so each gets its own group.
+func leaderboardGroup(consumer string) string {
+	return "leaderboard-workers:" + consumer
+}
+
+// streamConsumerName identifies this instance within a consumer group. The hostname is stable
+// across restarts, so entries left pending by the previous run are picked up again.
+func streamConsumerName() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "ws-" + uuid.New().String()
+}
+
+// publishToStream appends a JSON message (and optional extra fields) to a stream
+func publishToStream(ctx context.Context, redisClient *db.Redis, stream string, payload []byte, fields map[string]interface{}) error {
+	values := map[string]interface{}{streamPayloadField: payload}
+	for key, value := range fields {
+		values[key] = value
+	}
+	return redisClient.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+// streamConsumer reads a stream through a consumer group and acknowledges each entry once handled
+type streamConsumer struct {
+	redisClient *db.Redis
+	stream      string
+	group       string
+	consumer    string
+	handle      func(values map[string]interface{})
+}
+
+// run creates the consumer group if needed, re-delivers entries left pending, then consumes new entries until ctx is cancelled
+func (c *streamConsumer) run(ctx context.Context) {
+	// "$" starts a new group at the end of the stream; an existing group keeps its position
+	err := c.redisClient.Client.XGroupCreateMkStream(ctx, c.stream, c.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		log.Printf("[WS] Error creating consumer group %s on %s: %v", c.group, c.stream, err)
+		return
+	}
+
+	c.recoverPending(ctx)
+
+	for ctx.Err() == nil {
+		streams, err := c.redisClient.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.consumer,
+			Streams:  []string{c.stream, ">"},
+			Count:    streamReadCount,
+			Block:    streamBlockTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[WS] Error reading %s: %v", c.stream, err)
+			time.Sleep(streamBlockTimeout)
+			continue
+		}
+		for _, s := range streams {
+			c.process(ctx, s.Messages)
+		}
+	}
+}
+
+// recoverPending re-delivers entries read but never acknowledged: first this consumer's own
+// (from before a restart), then entries of other consumers that have been idle too long.
+func (c *streamConsumer) recoverPending(ctx context.Context) {
+	pending, err := c.redisClient.Client.XPending(ctx, c.stream, c.group).Result()
+	if err != nil {
+		log.Printf("[WS] Error checking pending entries on %s: %v", c.stream, err)
+		return
+	}
+	if pending.Count == 0 {
+		return
+	}
+	log.Printf("[WS] %d pending entries on %s, re-delivering", pending.Count, c.stream)
+
+	// Reading from ID "0" returns this consumer's pending entries instead of new ones
+	for ctx.Err() == nil {
+		streams, err := c.redisClient.Client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.consumer,
+			Streams:  []string{c.stream, "0"},
+			Count:    streamReadCount,
+			Block:    -1,
+		}).Result()
+		if err != nil {
+			log.Printf("[WS] Error reading pending entries on %s: %v", c.stream, err)
+			return
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			break
+		}
+		c.process(ctx, streams[0].Messages)
+	}
+
+	start := "0-0"
+	for ctx.Err() == nil {
+		messages, next, err := c.redisClient.Client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.stream,
+			Group:    c.group,
+			Consumer: c.consumer,
+			MinIdle:  streamClaimMinIdle,
+			Start:    start,
+			Count:    streamReadCount,
+		}).Result()
+		if err != nil {
+			log.Printf("[WS] Error claiming idle entries on %s: %v", c.stream, err)
+			return
+		}
+		c.process(ctx, messages)
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// process handles and acknowledges entries. Malformed entries are acknowledged too so they are not retried forever.
+func (c *streamConsumer) process(ctx context.Context, messages []redis.XMessage) {
+	for _, msg := range messages {
+		// Entries trimmed by MAXLEN while pending come back without values
+		if msg.Values != nil {
+			c.handle(msg.Values)
+		}
+		if err := c.redisClient.Client.XAck(ctx, c.stream, c.group, msg.ID).Err(); err != nil {
+			log.Printf("[WS] Error acknowledging %s entry %s: %v", c.stream, msg.ID, err)
+		}
+	}
+}
+
+// streamPayload returns the JSON message of a stream entry
+func streamPayload(values map[string]interface{}) ([]byte, error) {
+	payload, ok := values[streamPayloadField].(string)
+	if !ok {
+		return nil, fmt.Errorf("stream entry has no %s field", streamPayloadField)
+	}
+	return []byte(payload), nil
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/storage"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+const (
+	// orphanedFileMinAge keeps files from uploads that may still be waiting on their DB write
+	orphanedFileMinAge = 48 * time.Hour
+	// maxCleanupDeletionsPerRun caps deletions so a bad query can't wipe the bucket in one night
+	maxCleanupDeletionsPerRun = 10000
+
+	taskProofsPrefix = "task-proofs/"
+	exportsPrefix    = "exports/"
+)
+
+// errCleanupLimitReached stops listing once the per-run deletion cap is hit
+var errCleanupLimitReached = errors.New("cleanup deletion limit reached")
+
+// OrphanedFileCleanupJob deletes task proof files that no submission references
+// (e.g. left behind when a submission failed after the upload) and old export archives
+type OrphanedFileCleanupJob struct {
+	postgres *db.Postgres
+	s3       *storage.S3Storage
+	dryRun   bool
+}
+
+// NewOrphanedFileCleanupJob creates a cleanup job. With dryRun set it only logs what it would delete.
+func NewOrphanedFileCleanupJob(postgres *db.Postgres, s3Storage *storage.S3Storage, dryRun bool) *OrphanedFileCleanupJob {
+	return &OrphanedFileCleanupJob{
+		postgres: postgres,
+		s3:       s3Storage,
+		dryRun:   dryRun,
+	}
+}
+
+// Run cleans up every night at midnight until ctx is cancelled
+func (j *OrphanedFileCleanupJob) Run(ctx context.Context) {
+	for {
+		wait := time.Until(nextMidnight(time.Now()))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce deletes orphaned task proofs and expired exports older than orphanedFileMinAge,
+// up to maxCleanupDeletionsPerRun files
+func (j *OrphanedFileCleanupJob) RunOnce(ctx context.Context) {
+	submissionStore := store.NewSubmissionStore(j.postgres)
+	cutoff := time.Now().Add(-orphanedFileMinAge)
+	deleted := 0
+
+	// Returns errCleanupLimitReached once the cap is hit so listing stops
+	deleteFile := func(key string) error {
+		if deleted >= maxCleanupDeletionsPerRun {
+			return errCleanupLimitReached
+		}
+		if j.dryRun {
+			log.Printf("[Scheduler] Cleanup dry run: would delete %s", key)
+		} else if err := j.s3.DeleteTaskProof(ctx, key); err != nil {
+			log.Printf("[Scheduler] Error deleting %s: %v", key, err)
+			return nil
+		}
+		deleted++
+		return nil
+	}
+
+	err := j.s3.ListTaskProofObjects(ctx, taskProofsPrefix, func(key string, lastModified time.Time) error {
+		if lastModified.After(cutoff) {
+			return nil
+		}
+		referenced, err := submissionStore.IsProofKeyReferenced(ctx, key)
+		if err != nil {
+			log.Printf("[Scheduler] Error checking proof %s: %v", key, err)
+			return nil
+		}
+		if referenced {
+			return nil
+		}
+		return deleteFile(key)
+	})
+	if err == nil {
+		// Export archives are temporary downloads, nothing references them
+		err = j.s3.ListTaskProofObjects(ctx, exportsPrefix, func(key string, lastModified time.Time) error {
+			if lastModified.After(cutoff) {
+				return nil
+			}
+			return deleteFile(key)
+		})
+	}
+
+	switch {
+	case errors.Is(err, errCleanupLimitReached):
+		log.Printf("[Scheduler] Cleanup stopped at the limit of %d files; the rest will be picked up next run", maxCleanupDeletionsPerRun)
+	case err != nil:
+		log.Printf("[Scheduler] Error listing files for cleanup: %v", err)
+	}
+
+	if j.dryRun {
+		log.Printf("[Scheduler] Cleanup dry run: %d files would be deleted", deleted)
+	} else {
+		log.Printf("[Scheduler] Cleanup deleted %d orphaned files", deleted)
+	}
+}
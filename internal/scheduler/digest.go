@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/email"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/templates"
+)
+
+// digestFirstWindow is how far back the first digest for a user looks
+const digestFirstWindow = 24 * time.Hour
+
+// DigestScheduler emails each opted-in user a nightly summary of their notifications
+type DigestScheduler struct {
+	postgres *db.Postgres
+	emailer  email.Emailer
+}
+
+// NewDigestScheduler creates a digest scheduler
+func NewDigestScheduler(postgres *db.Postgres, emailer email.Emailer) *DigestScheduler {
+	return &DigestScheduler{
+		postgres: postgres,
+		emailer:  emailer,
+	}
+}
+
+// Run sends digests every night at midnight until ctx is cancelled
+func (s *DigestScheduler) Run(ctx context.Context) {
+	for {
+		wait := time.Until(nextMidnight(time.Now()))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce sends a digest to every opted-in user who has notifications since their last digest
+func (s *DigestScheduler) RunOnce(ctx context.Context) {
+	digestStore := store.NewDigestStore(s.postgres)
+	recipients, err := digestStore.GetDigestRecipients(ctx)
+	if err != nil {
+		log.Printf("[Scheduler] Error getting digest recipients: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, recipient := range recipients {
+		since := time.Now().Add(-digestFirstWindow)
+		if recipient.DigestSentAt != nil {
+			since = *recipient.DigestSentAt
+		}
+
+		notifications, err := digestStore.GetUnsentSince(ctx, recipient.UserID, since)
+		if err != nil {
+			log.Printf("[Scheduler] Error getting notifications for digest to user %s: %v", recipient.UserID, err)
+			continue
+		}
+		if len(notifications) == 0 {
+			continue
+		}
+
+		var body bytes.Buffer
+		digest := store.NewNotificationDigest(recipient.Name, since, notifications)
+		if err := templates.Digest.Execute(&body, digest); err != nil {
+			log.Printf("[Scheduler] Error rendering digest for user %s: %v", recipient.UserID, err)
+			continue
+		}
+
+		if err := s.emailer.Send(ctx, recipient.Email, "Your daily summary", body.String()); err != nil {
+			log.Printf("[Scheduler] Error sending digest to user %s: %v", recipient.UserID, err)
+			continue
+		}
+
+		// Advance to the newest notification included so nothing is skipped or sent twice
+		if err := digestStore.MarkDigestSent(ctx, recipient.UserID, notifications[len(notifications)-1].CreatedAt); err != nil {
+			log.Printf("[Scheduler] Error marking digest sent for user %s: %v", recipient.UserID, err)
+		}
+		sent++
+	}
+
+	log.Printf("[Scheduler] Digests: %d recipients, %d sent", len(recipients), sent)
+}
+
+// nextMidnight returns the first midnight strictly after t
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// WeeklyTaskScheduler creates the next week's instance of every weekly task each Monday at midnight
+// and clears last week's weekly submissions that were never reviewed
+type WeeklyTaskScheduler struct {
+	postgres *db.Postgres
+}
+
+// NewWeeklyTaskScheduler creates a weekly task scheduler
+func NewWeeklyTaskScheduler(postgres *db.Postgres) *WeeklyTaskScheduler {
+	return &WeeklyTaskScheduler{
+		postgres: postgres,
+	}
+}
+
+// Run creates any missing instances on startup, then again every Monday at midnight until ctx is cancelled
+func (s *WeeklyTaskScheduler) Run(ctx context.Context) {
+	s.RunOnce(ctx)
+
+	for {
+		wait := time.Until(nextMonday(time.Now()))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce clears unreviewed weekly submissions from earlier weeks and creates this week's instance
+// for every ended weekly task. Safe to call repeatedly: a series gets at most one instance per week.
+func (s *WeeklyTaskScheduler) RunOnce(ctx context.Context) {
+	cleared, err := store.NewWeeklyResetStore(s.postgres).ClearUnreviewedSubmissions(ctx)
+	if err != nil {
+		log.Printf("[Scheduler] Error clearing unreviewed weekly submissions: %v", err)
+	} else if cleared > 0 {
+		log.Printf("[Scheduler] Cleared %d unreviewed weekly submissions from earlier weeks", cleared)
+	}
+
+	taskStore := store.NewTaskStore(s.postgres)
+	templates, err := taskStore.GetWeeklyTasksDueForRepeat(ctx)
+	if err != nil {
+		log.Printf("[Scheduler] Error getting weekly tasks: %v", err)
+		return
+	}
+
+	startAt := weekStart(time.Now())
+	endAt := startAt.AddDate(0, 0, 7)
+	created := 0
+	for _, template := range templates {
+		task, userIDs, err := taskStore.CreateWeeklyInstance(ctx, template, startAt, endAt)
+		if err != nil {
+			log.Printf("[Scheduler] Error creating weekly instance of task %s: %v", template.ID, err)
+			continue
+		}
+		if task == nil {
+			continue // This week's instance already exists
+		}
+		created++
+
+		if wsHub := ws.GetNotificationHub(); wsHub != nil && len(userIDs) > 0 {
+			if err := ws.SendTaskAssignmentNotification(wsHub, userIDs, task.ID, task.Title, task.Description); err != nil {
+				log.Printf("[Scheduler] Error sending task assignment notifications: %v", err)
+			}
+		}
+	}
+
+	log.Printf("[Scheduler] Weekly tasks: %d due, %d instances created for week of %s", len(templates), created, startAt.Format("2006-01-02"))
+}
+
+// weekStart returns midnight of the Monday that starts the week containing t
+func weekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -daysSinceMonday).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// nextMonday returns midnight of the first Monday strictly after t
+func nextMonday(t time.Time) time.Time {
+	return weekStart(t).AddDate(0, 0, 7)
+}
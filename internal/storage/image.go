@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Profile pictures are scaled down to fit within ProfilePicMaxWidth x ProfilePicMaxHeight
+const (
+	ProfilePicMaxWidth  = 800
+	ProfilePicMaxHeight = 800
+
+	// maxImagePixels rejects images whose decoded size would be unreasonably large
+	maxImagePixels = 50_000_000
+	// processedJPEGQuality is the quality JPEGs are re-encoded with
+	processedJPEGQuality = 90
+)
+
+var (
+	// ErrUnsupportedImageType is returned when the file content is not a JPEG, PNG, GIF or WebP image
+	ErrUnsupportedImageType = errors.New("unsupported image type")
+	// ErrInvalidImage is returned when an image of a supported type cannot be decoded
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+// imageDecoders are the decoders of the image types accepted, keyed by sniffed content type
+var imageDecoders = map[string]func(io.Reader) (image.Image, error){
+	"image/jpeg": jpeg.Decode,
+	"image/png":  png.Decode,
+	"image/gif":  gif.Decode,
+	"image/webp": webp.Decode,
+}
+
+// imageConfigDecoders read only the dimensions of the accepted image types
+var imageConfigDecoders = map[string]func(io.Reader) (image.Config, error){
+	"image/jpeg": jpeg.DecodeConfig,
+	"image/png":  png.DecodeConfig,
+	"image/gif":  gif.DecodeConfig,
+	"image/webp": webp.DecodeConfig,
+}
+
+// imageExtensions are the file extensions of the content types ValidateAndProcessImage returns
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// ImageExtension returns the file extension for a content type returned by ValidateAndProcessImage
+func ImageExtension(contentType string) string {
+	return imageExtensions[contentType]
+}
+
+// ValidateAndProcessImage checks that file really is a JPEG, PNG, GIF or WebP image by sniffing its content
+// (the extension is not trusted), then re-encodes it scaled down to fit within maxWidth x maxHeight with its
+// aspect ratio preserved. Images that already fit are re-encoded at their size, which also drops metadata.
+// JPEGs stay JPEG; PNG, GIF (first frame) and WebP become PNG so transparency is kept.
+// It returns the processed image and its content type.
+func ValidateAndProcessImage(file io.Reader, maxWidth, maxHeight int) (io.Reader, string, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	sniffLen := min(len(data), 512)
+	contentType := http.DetectContentType(data[:sniffLen])
+	decode, ok := imageDecoders[contentType]
+	if !ok {
+		return nil, "", ErrUnsupportedImageType
+	}
+
+	cfg, err := imageConfigDecoders[contentType](bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("%w: %dx%d is too large", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	img = fitImage(img, maxWidth, maxHeight)
+
+	var buf bytes.Buffer
+	out := bufio.NewWriter(&buf)
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: processedJPEGQuality})
+	} else {
+		contentType = "image/png"
+		err = png.Encode(out, img)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return &buf, contentType, nil
+}
+
+// fitImage scales img down to fit within maxWidth x maxHeight preserving its aspect ratio.
+// Images that already fit are returned unchanged.
+func fitImage(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxWidth && height <= maxHeight {
+		return img
+	}
+
+	scale := min(float64(maxWidth)/float64(width), float64(maxHeight)/float64(height))
+	newWidth := max(1, int(float64(width)*scale))
+	newHeight := max(1, int(float64(height)*scale))
+
+	resized := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	draw.ApproxBiLinear.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
+	return resized
+}
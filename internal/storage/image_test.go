@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// encodeImage draws a width x height image and encodes it with encode
+func encodeImage(t *testing.T, width, height int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+	img := image.NewPaletted(image.Rect(0, 0, width, height), color.Palette{color.White, color.Black})
+	for x := 0; x < width; x += 2 {
+		img.SetColorIndex(x, x%height, 1)
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("encoding test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestValidateAndProcessImage(t *testing.T) {
+	encodeJPEG := func(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) }
+	encodePNG := func(buf *bytes.Buffer, img image.Image) error { return png.Encode(buf, img) }
+	encodeGIF := func(buf *bytes.Buffer, img image.Image) error { return gif.Encode(buf, img, nil) }
+	tests := []struct {
+		name            string
+		file            []byte
+		wantErr         error
+		wantContentType string
+		wantWidth       int
+		wantHeight      int
+	}{
+		{name: "jpeg", file: encodeImage(t, 640, 480, encodeJPEG), wantContentType: "image/jpeg", wantWidth: 640, wantHeight: 480},
+		{name: "png", file: encodeImage(t, 300, 300, encodePNG), wantContentType: "image/png", wantWidth: 300, wantHeight: 300},
+		// GIFs are re-encoded as PNG
+		{name: "gif", file: encodeImage(t, 120, 90, encodeGIF), wantContentType: "image/png", wantWidth: 120, wantHeight: 90},
+		{name: "at the limit", file: encodeImage(t, ProfilePicMaxWidth, ProfilePicMaxHeight, encodePNG), wantContentType: "image/png", wantWidth: 800, wantHeight: 800},
+		{name: "oversized landscape", file: encodeImage(t, 1600, 1200, encodeJPEG), wantContentType: "image/jpeg", wantWidth: 800, wantHeight: 600},
+		{name: "oversized portrait", file: encodeImage(t, 400, 2000, encodePNG), wantContentType: "image/png", wantWidth: 160, wantHeight: 800},
+		// The handlers receive it as profile.jpg; only the content counts
+		{name: "pdf named jpg", file: []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n"), wantErr: ErrUnsupportedImageType},
+		{name: "empty", file: nil, wantErr: ErrUnsupportedImageType},
+		{name: "truncated png", file: encodeImage(t, 300, 300, encodePNG)[:100], wantErr: ErrInvalidImage},
+		// A GIF header claiming 60000x60000 pixels is refused before it is decoded
+		{name: "decompression bomb", file: []byte("GIF89a\x60\xea\x60\xea\x00\x00\x00"), wantErr: ErrInvalidImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed, contentType, err := ValidateAndProcessImage(bytes.NewReader(tt.file), ProfilePicMaxWidth, ProfilePicMaxHeight)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAndProcessImage: %v", err)
+			}
+			if contentType != tt.wantContentType {
+				t.Errorf("content type = %q, want %q", contentType, tt.wantContentType)
+			}
+
+			img, format, err := image.Decode(processed)
+			if err != nil {
+				t.Fatalf("decoding processed image: %v", err)
+			}
+			if "image/"+format != contentType {
+				t.Errorf("encoded as %s, reported as %s", format, contentType)
+			}
+			if got := img.Bounds(); got.Dx() != tt.wantWidth || got.Dy() != tt.wantHeight {
+				t.Errorf("size = %dx%d, want %dx%d", got.Dx(), got.Dy(), tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
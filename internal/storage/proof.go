@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Largest task proof files accepted, by kind
+const (
+	MaxProofImageSizeMB = 10
+	MaxProofVideoSizeMB = 100
+
+	// proofSniffLen is how much of a proof file is read to detect its type
+	proofSniffLen = 512
+)
+
+var (
+	// ErrUnsupportedProofType is returned when a proof file's content is not an allowed image or video type
+	ErrUnsupportedProofType = errors.New("unsupported proof file type")
+	// ErrProofFileTooLarge is returned when a proof file exceeds the size limit of its type
+	ErrProofFileTooLarge = errors.New("proof file too large")
+)
+
+// proofFileExtensions are the allowed proof content types and the extension proofs of that type are stored with
+var proofFileExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+}
+
+// IsProofImage reports whether a content type returned by ValidateProofFile is an image
+func IsProofImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// ValidateProofFile checks that a task proof really is an allowed image (JPEG, PNG, GIF, WebP) or
+// video (MP4, QuickTime, WebM) by sniffing its first 512 bytes; the filename's extension is not trusted
+// and is only used in error messages. Images may be at most 10 MB and videos 100 MB, or maxSizeMB if
+// it is lower. It returns the detected content type, also on error, and a reader that yields the whole
+// file, which must be used in place of file.
+//
+// A seekable file (such as a multipart.File) is sniffed in place, measured up front and returned
+// still seekable, positioned where it was, so an upload can rewind it to retry. Other readers are
+// returned with the sniffed bytes put back in front, and fail once they have read past the limit.
+func ValidateProofFile(file io.Reader, filename string, maxSizeMB int) (string, io.Reader, error) {
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		return validateSeekableProofFile(seeker, filename, maxSizeMB)
+	}
+
+	head := make([]byte, proofSniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, fmt.Errorf("failed to read proof file: %w", err)
+	}
+	head = head[:n]
+
+	contentType, limitMB, err := checkProofType(head, filename, maxSizeMB)
+	if err != nil {
+		return contentType, nil, err
+	}
+
+	body := &sizeLimitedReader{r: io.MultiReader(bytes.NewReader(head), file), remaining: int64(limitMB) << 20, limitMB: limitMB}
+	return contentType, body, nil
+}
+
+// validateSeekableProofFile is ValidateProofFile for a file that can be read again from where it is
+func validateSeekableProofFile(file io.ReadSeeker, filename string, maxSizeMB int) (string, io.Reader, error) {
+	start, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read proof file: %w", err)
+	}
+
+	// ReadAt leaves the offset alone; other seekers are read and rewound
+	head := make([]byte, proofSniffLen)
+	var n int
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		n, err = readerAt.ReadAt(head, start)
+	} else {
+		n, err = io.ReadFull(file, head)
+		if _, seekErr := file.Seek(start, io.SeekStart); seekErr != nil {
+			return "", nil, fmt.Errorf("failed to rewind proof file: %w", seekErr)
+		}
+	}
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, fmt.Errorf("failed to read proof file: %w", err)
+	}
+	head = head[:n]
+
+	contentType, limitMB, err := checkProofType(head, filename, maxSizeMB)
+	if err != nil {
+		return contentType, nil, err
+	}
+
+	end, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return contentType, nil, fmt.Errorf("failed to read proof file: %w", err)
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return contentType, nil, fmt.Errorf("failed to rewind proof file: %w", err)
+	}
+	limit := int64(limitMB) << 20
+	if end-start > limit {
+		return contentType, nil, fmt.Errorf("%w: %s is larger than %d MB", ErrProofFileTooLarge, filename, limitMB)
+	}
+
+	return contentType, &sizeLimitedReadSeeker{rs: file, start: start, limit: limit, limitMB: limitMB}, nil
+}
+
+// checkProofType detects the content type of a proof from its first bytes and returns it with
+// the size limit in MB for that type. The content type is returned even when it is not allowed.
+func checkProofType(head []byte, filename string, maxSizeMB int) (string, int, error) {
+	contentType := sniffProofContentType(head)
+	if _, ok := proofFileExtensions[contentType]; !ok {
+		return contentType, 0, fmt.Errorf("%w: %s is %s", ErrUnsupportedProofType, filename, contentType)
+	}
+
+	limitMB := MaxProofVideoSizeMB
+	if IsProofImage(contentType) {
+		limitMB = MaxProofImageSizeMB
+	}
+	if maxSizeMB > 0 && maxSizeMB < limitMB {
+		limitMB = maxSizeMB
+	}
+	return contentType, limitMB, nil
+}
+
+// sniffProofContentType detects the content type of a proof from its first bytes.
+// http.DetectContentType does not know QuickTime, which is what iPhones record.
+func sniffProofContentType(head []byte) string {
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" && isQuickTime(head) {
+		return "video/quicktime"
+	}
+	return contentType
+}
+
+// isQuickTime reports whether head starts with a QuickTime ftyp box or one of the atoms older .mov files start with
+func isQuickTime(head []byte) bool {
+	if len(head) < 12 {
+		return false
+	}
+	switch string(head[4:8]) {
+	case "ftyp":
+		return string(head[8:12]) == "qt  "
+	case "moov", "mdat", "wide", "free", "skip":
+		return true
+	}
+	return false
+}
+
+// sizeLimitedReader returns ErrProofFileTooLarge once more than remaining bytes have been read
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	limitMB   int
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: larger than %d MB", ErrProofFileTooLarge, l.limitMB)
+	}
+	// Read one byte past the limit to tell a file of exactly the limit from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("%w: larger than %d MB", ErrProofFileTooLarge, l.limitMB)
+	}
+	return n, err
+}
+
+// sizeLimitedReadSeeker is a sizeLimitedReader for a seekable file. Offsets are relative to start,
+// where the file was when it was validated, and seeking back moves the limit back with it.
+type sizeLimitedReadSeeker struct {
+	rs      io.ReadSeeker
+	start   int64
+	offset  int64 // position relative to start
+	limit   int64
+	limitMB int
+}
+
+func (l *sizeLimitedReadSeeker) Read(p []byte) (int, error) {
+	if l.offset > l.limit {
+		return 0, fmt.Errorf("%w: larger than %d MB", ErrProofFileTooLarge, l.limitMB)
+	}
+	// Read one byte past the limit to tell a file of exactly the limit from a larger one
+	if int64(len(p)) > l.limit-l.offset+1 {
+		p = p[:l.limit-l.offset+1]
+	}
+	n, err := l.rs.Read(p)
+	l.offset += int64(n)
+	if l.offset > l.limit {
+		return n, fmt.Errorf("%w: larger than %d MB", ErrProofFileTooLarge, l.limitMB)
+	}
+	return n, err
+}
+
+func (l *sizeLimitedReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekStart {
+		offset += l.start
+	}
+	pos, err := l.rs.Seek(offset, whence)
+	if err != nil {
+		return 0, err
+	}
+	if pos < l.start {
+		// Put the file back where it was so the reader stays usable
+		if _, err := l.rs.Seek(l.start+l.offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+		return 0, fmt.Errorf("seek to before the start of the proof file")
+	}
+	l.offset = pos - l.start
+	return l.offset, nil
+}
+
+// TaskProofExtension returns the file extension proofs of a content type returned by ValidateProofFile are stored with
+func TaskProofExtension(contentType string) string {
+	return proofFileExtensions[contentType]
+}
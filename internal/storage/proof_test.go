@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Leading bytes of each allowed proof type, padded so the sniffer sees a full header
+var proofHeads = map[string][]byte{
+	"image/jpeg":      []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"),
+	"image/png":       []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"),
+	"image/gif":       []byte("GIF89a\x01\x00\x01\x00"),
+	"image/webp":      []byte("RIFF\x24\x00\x00\x00WEBPVP8 "),
+	"video/mp4":       []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"),
+	"video/quicktime": []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00qt  "),
+	"video/webm":      []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81\x01\x42\xf2\x81\x04\x42\xf3\x81\x08\x42\x82\x84webm"),
+}
+
+// proofOfSize returns a file of size bytes starting with head
+func proofOfSize(head []byte, size int) []byte {
+	file := make([]byte, size)
+	copy(file, head)
+	return file
+}
+
+// unseekable hides a reader's Seek so ValidateProofFile takes the streaming path
+type unseekable struct {
+	io.Reader
+}
+
+func TestValidateProofFileTypes(t *testing.T) {
+	tests := []struct {
+		name            string
+		filename        string
+		file            []byte
+		wantContentType string
+		wantErr         error
+	}{
+		{name: "jpeg", filename: "proof.jpg", file: proofHeads["image/jpeg"], wantContentType: "image/jpeg"},
+		{name: "png", filename: "proof.png", file: proofHeads["image/png"], wantContentType: "image/png"},
+		{name: "gif", filename: "proof.gif", file: proofHeads["image/gif"], wantContentType: "image/gif"},
+		{name: "webp", filename: "proof.webp", file: proofHeads["image/webp"], wantContentType: "image/webp"},
+		{name: "mp4", filename: "proof.mp4", file: proofHeads["video/mp4"], wantContentType: "video/mp4"},
+		{name: "quicktime", filename: "IMG_0001.MOV", file: proofHeads["video/quicktime"], wantContentType: "video/quicktime"},
+		{name: "old quicktime", filename: "IMG_0001.MOV", file: []byte("\x00\x00\x00\x08wide\x00\x00\x00\x00mdat"), wantContentType: "video/quicktime"},
+		{name: "webm", filename: "proof.webm", file: proofHeads["video/webm"], wantContentType: "video/webm"},
+		// The extension is never trusted
+		{name: "png named jpg", filename: "proof.jpg", file: proofHeads["image/png"], wantContentType: "image/png"},
+		{name: "executable named jpg", filename: "malware.jpg", file: []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"), wantContentType: "application/octet-stream", wantErr: ErrUnsupportedProofType},
+		{name: "pdf named png", filename: "proof.png", file: []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3"), wantContentType: "application/pdf", wantErr: ErrUnsupportedProofType},
+		{name: "html named mp4", filename: "proof.mp4", file: []byte("<html><script>alert(1)</script></html>"), wantContentType: "text/html; charset=utf-8", wantErr: ErrUnsupportedProofType},
+		{name: "empty", filename: "proof.jpg", file: nil, wantContentType: "text/plain; charset=utf-8", wantErr: ErrUnsupportedProofType},
+	}
+
+	for _, tt := range tests {
+		for _, seekable := range []bool{true, false} {
+			name := tt.name
+			if !seekable {
+				name += " unseekable"
+			}
+			t.Run(name, func(t *testing.T) {
+				var file io.Reader = bytes.NewReader(tt.file)
+				if !seekable {
+					file = unseekable{file}
+				}
+				contentType, body, err := ValidateProofFile(file, tt.filename, MaxProofVideoSizeMB)
+				// The detected type is returned for the error message too
+				if contentType != tt.wantContentType {
+					t.Errorf("content type = %q, want %q", contentType, tt.wantContentType)
+				}
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("err = %v, want %v", err, tt.wantErr)
+					}
+					if !strings.Contains(err.Error(), tt.filename) {
+						t.Errorf("err = %q, want it to name %s", err, tt.filename)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("ValidateProofFile: %v", err)
+				}
+				got, err := io.ReadAll(body)
+				if err != nil {
+					t.Fatalf("reading validated file: %v", err)
+				}
+				if !bytes.Equal(got, tt.file) {
+					t.Errorf("validated file = %q, want the whole file %q", got, tt.file)
+				}
+				if _, ok := body.(io.ReadSeeker); ok != seekable {
+					t.Errorf("validated file seekable = %v, want %v", ok, seekable)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateProofFileSize(t *testing.T) {
+	const mb = 1 << 20
+	tests := []struct {
+		name      string
+		head      []byte
+		size      int
+		maxSizeMB int
+		wantErr   bool
+	}{
+		{name: "image at the image limit", head: proofHeads["image/png"], size: MaxProofImageSizeMB * mb, maxSizeMB: MaxProofVideoSizeMB},
+		{name: "image over the image limit", head: proofHeads["image/png"], size: MaxProofImageSizeMB*mb + 1, maxSizeMB: MaxProofVideoSizeMB, wantErr: true},
+		// Videos only have the larger limit
+		{name: "video over the image limit", head: proofHeads["video/mp4"], size: MaxProofImageSizeMB*mb + 1, maxSizeMB: MaxProofVideoSizeMB},
+		{name: "lower caller limit", head: proofHeads["video/mp4"], size: 2*mb + 1, maxSizeMB: 2, wantErr: true},
+		{name: "at the lower caller limit", head: proofHeads["image/jpeg"], size: 2 * mb, maxSizeMB: 2},
+		{name: "higher caller limit is ignored", head: proofHeads["image/jpeg"], size: MaxProofImageSizeMB*mb + 1, maxSizeMB: 500, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		file := proofOfSize(tt.head, tt.size)
+		t.Run(tt.name, func(t *testing.T) {
+			// Seekable files are measured before anything is uploaded
+			_, body, err := ValidateProofFile(bytes.NewReader(file), "proof", tt.maxSizeMB)
+			if tt.wantErr {
+				if !errors.Is(err, ErrProofFileTooLarge) {
+					t.Fatalf("err = %v, want ErrProofFileTooLarge", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("ValidateProofFile: %v", err)
+				}
+				if n, err := io.Copy(io.Discard, body); err != nil || n != int64(tt.size) {
+					t.Errorf("read %d bytes, err %v; want %d", n, err, tt.size)
+				}
+			}
+		})
+		t.Run(tt.name+" unseekable", func(t *testing.T) {
+			// Other readers fail part way through being read
+			_, body, err := ValidateProofFile(unseekable{bytes.NewReader(file)}, "proof", tt.maxSizeMB)
+			if err != nil {
+				t.Fatalf("ValidateProofFile: %v", err)
+			}
+			_, err = io.Copy(io.Discard, body)
+			if tt.wantErr != errors.Is(err, ErrProofFileTooLarge) {
+				t.Errorf("reading err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProofFileRewinds(t *testing.T) {
+	proof := append(append([]byte{}, proofHeads["image/png"]...), "rest of the image"...)
+	tests := []struct {
+		name string
+		file func() io.ReadSeeker
+	}{
+		{name: "reader at", file: func() io.ReadSeeker { return bytes.NewReader(proof) }},
+		{name: "seeker only", file: func() io.ReadSeeker { return seekOnly{bytes.NewReader(proof)} }},
+		{
+			name: "already part read",
+			file: func() io.ReadSeeker {
+				r := bytes.NewReader(append([]byte("junk"), proof...))
+				r.Seek(4, io.SeekStart)
+				return r
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, err := ValidateProofFile(tt.file(), "proof.png", MaxProofVideoSizeMB)
+			if err != nil {
+				t.Fatalf("ValidateProofFile: %v", err)
+			}
+			seeker, ok := body.(io.ReadSeeker)
+			if !ok {
+				t.Fatalf("validated file is %T, want an io.ReadSeeker", body)
+			}
+			for i := 0; i < 2; i++ {
+				got, err := io.ReadAll(seeker)
+				if err != nil || !bytes.Equal(got, proof) {
+					t.Fatalf("read %d: %q, %v; want the whole proof", i+1, got, err)
+				}
+				// Offsets start where the file was validated
+				if pos, err := seeker.Seek(0, io.SeekStart); err != nil || pos != 0 {
+					t.Fatalf("rewind = %d, %v", pos, err)
+				}
+			}
+			if size, err := seeker.Seek(0, io.SeekEnd); err != nil || size != int64(len(proof)) {
+				t.Errorf("seek to end = %d, %v; want %d", size, err, len(proof))
+			}
+		})
+	}
+}
+
+// A proof straight from a multipart form is sniffed in place and can be retried
+func TestValidateProofFileMultipartUpload(t *testing.T) {
+	proof := proofOfSize(proofHeads["video/mp4"], 4096)
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile("proof", "proof.mp4")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	part.Write(proof)
+	writer.Close()
+
+	// No memory allowance, so the part is kept in a temp file like large uploads are
+	parsed, err := multipart.NewReader(&form, writer.Boundary()).ReadForm(0)
+	if err != nil {
+		t.Fatalf("ReadForm: %v", err)
+	}
+	t.Cleanup(func() { parsed.RemoveAll() })
+	file, err := parsed.File["proof"][0].Open()
+	if err != nil {
+		t.Fatalf("opening proof: %v", err)
+	}
+	defer file.Close()
+
+	contentType, body, err := ValidateProofFile(file, "proof.mp4", MaxProofVideoSizeMB)
+	if err != nil || contentType != "video/mp4" {
+		t.Fatalf("ValidateProofFile = %q, %v; want video/mp4", contentType, err)
+	}
+
+	s, fake := newFakeS3Storage(t, http.StatusServiceUnavailable)
+	if _, _, err := s.UploadTaskProof(context.Background(), body, "task", "user", contentType); err != nil {
+		t.Fatalf("UploadTaskProof: %v", err)
+	}
+	if len(fake.bodies) != 2 {
+		t.Fatalf("S3 got %d requests, want 2", len(fake.bodies))
+	}
+	for i, got := range fake.bodies {
+		if got != string(proof) {
+			t.Errorf("request %d sent %d bytes, want the whole %d byte proof", i+1, len(got), len(proof))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	// s3MaxAttempts is the number of times an S3 call is tried before giving up
+	s3MaxAttempts = 4
+	// retryBaseDelay is the delay before the first retry; it doubles on each attempt
+	retryBaseDelay = 100 * time.Millisecond
+	// retryMaxDelay caps the delay between attempts
+	retryMaxDelay = 2 * time.Second
+)
+
+// withoutSDKRetries turns off the SDK's own retries for a call wrapped in RetryWithBackoff;
+// otherwise each of our attempts would itself be retried by the SDK's default retryer
+func withoutSDKRetries(o *s3.Options) {
+	o.Retryer = aws.NopRetryer{}
+}
+
+// RetryWithBackoff calls fn until it succeeds, returns a non-retryable error, maxAttempts is
+// reached or ctx is done. Delays grow exponentially from 100ms up to 2s, with full jitter.
+func RetryWithBackoff(ctx context.Context, maxAttempts int, fn func() error) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isRetryableS3Error(err) || attempt == maxAttempts {
+			return err
+		}
+
+		delay := backoffDelay(attempt)
+		log.Printf("[S3] INFO: Retrying after transient error - Attempt: %d/%d, Delay: %v, Error: %v", attempt, maxAttempts, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// backoffDelay returns a random delay in [0, min(base*2^(attempt-1), max)]
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// isRetryableS3Error reports whether err is a throttling/unavailable response (429/503)
+// or a transient network error
+func isRetryableS3Error(err error) bool {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		status := respErr.HTTPStatusCode()
+		return status == http.StatusServiceUnavailable || status == http.StatusTooManyRequests
+	}
+
+	var apiErr *smithy.GenericAPIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "SlowDown", "ServiceUnavailable", "Throttling", "ThrottlingException", "TooManyRequests", "RequestLimitExceeded":
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
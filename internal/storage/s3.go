@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,17 +15,26 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	// "github.com/google/uuid"
 )
 
 type S3Storage struct {
-	client             *s3.Client
+	client             *s3.Client // Client for Region, used for buckets without their own entry
+	profileClient      *s3.Client
+	resumeClient       *s3.Client
+	taskProofClient    *s3.Client
+	badgeClient        *s3.Client
 	uploader           *manager.Uploader
 	profileBucket      string
 	resumeBucket       string
 	taskProofBucket    string
 	badgeBucket        string
 	region             string
+	profileRegion      string
+	resumeRegion       string
+	taskProofRegion    string
+	badgeRegion        string
 	profilePublicURL   string
 	resumePublicURL    string
 	taskProofPublicURL string
@@ -43,31 +53,86 @@ type S3Config struct {
 	ResumePublicURL    string // Optional: CDN URL or S3 public URL for resume bucket
 	TaskProofPublicURL string // Optional: CDN URL or S3 public URL for task proof bucket
 	BadgePublicURL     string // Optional: CDN URL or S3 public URL for badge bucket
-}
 
-func NewS3Storage(cfg S3Config) (*S3Storage, error) {
-	log.Printf("[S3] Initializing S3 storage - Region: %s, Profile Bucket: %s, Resume Bucket: %s, Task Proof Bucket: %s, Badge Bucket: %s", cfg.Region, cfg.ProfileBucket, cfg.ResumeBucket, cfg.TaskProofBucket, cfg.BadgeBucket)
+	// Optional per-bucket regions; each falls back to Region when empty
+	ProfileBucketRegion   string
+	ResumeBucketRegion    string
+	TaskProofBucketRegion string
+	BadgeBucketRegion     string // Falls back to the profile bucket region when BadgeBucket is empty
+}
 
-	awsCfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(cfg.Region),
+// LoadAWSConfig loads an AWS config with static credentials.
+// Shared by S3Storage and other AWS services (e.g. Rekognition moderation).
+func LoadAWSConfig(ctx context.Context, region, accessKeyID, secretAccessKey string) (aws.Config, error) {
+	return config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			cfg.AccessKeyID,
-			cfg.SecretAccessKey,
+			accessKeyID,
+			secretAccessKey,
 			"",
 		)),
 	)
-	if err != nil {
-		log.Printf("[S3] ERROR: Failed to load AWS config: %v", err)
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+}
+
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	log.Printf("[S3] Initializing S3 storage - Region: %s, Profile Bucket: %s, Resume Bucket: %s, Task Proof Bucket: %s, Badge Bucket: %s", cfg.Region, cfg.ProfileBucket, cfg.ResumeBucket, cfg.TaskProofBucket, cfg.BadgeBucket)
+
+	regionOrDefault := func(region string) string {
+		if region == "" {
+			return cfg.Region
+		}
+		return region
+	}
+	profileRegion := regionOrDefault(cfg.ProfileBucketRegion)
+	resumeRegion := regionOrDefault(cfg.ResumeBucketRegion)
+	taskProofRegion := regionOrDefault(cfg.TaskProofBucketRegion)
+	badgeRegion := regionOrDefault(cfg.BadgeBucketRegion)
+	if cfg.BadgeBucket == "" && cfg.BadgeBucketRegion == "" {
+		badgeRegion = profileRegion
+	}
+
+	// One client per distinct region
+	clients := make(map[string]*s3.Client)
+	clientFor := func(region string) (*s3.Client, error) {
+		if client, ok := clients[region]; ok {
+			return client, nil
+		}
+		awsCfg, err := LoadAWSConfig(context.TODO(), region, cfg.AccessKeyID, cfg.SecretAccessKey)
+		if err != nil {
+			log.Printf("[S3] ERROR: Failed to load AWS config for region %s: %v", region, err)
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		client := s3.NewFromConfig(awsCfg)
+		clients[region] = client
+		return client, nil
 	}
 
-	client := s3.NewFromConfig(awsCfg)
-	uploader := manager.NewUploader(client)
+	defaultClient, err := clientFor(cfg.Region)
+	if err != nil {
+		return nil, err
+	}
+	profileClient, err := clientFor(profileRegion)
+	if err != nil {
+		return nil, err
+	}
+	resumeClient, err := clientFor(resumeRegion)
+	if err != nil {
+		return nil, err
+	}
+	taskProofClient, err := clientFor(taskProofRegion)
+	if err != nil {
+		return nil, err
+	}
+	badgeClient, err := clientFor(badgeRegion)
+	if err != nil {
+		return nil, err
+	}
+	uploader := manager.NewUploader(defaultClient)
 
 	// Set default public URLs if not provided
 	profilePublicURL := cfg.ProfilePublicURL
 	if profilePublicURL == "" {
-		profilePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.ProfileBucket, cfg.Region)
+		profilePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.ProfileBucket, profileRegion)
 		log.Printf("[S3] Using default profile public URL: %s", profilePublicURL)
 	} else {
 		log.Printf("[S3] Using custom profile public URL: %s", profilePublicURL)
@@ -75,7 +140,7 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 
 	resumePublicURL := cfg.ResumePublicURL
 	if resumePublicURL == "" {
-		resumePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.ResumeBucket, cfg.Region)
+		resumePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.ResumeBucket, resumeRegion)
 		log.Printf("[S3] Using default resume public URL: %s", resumePublicURL)
 	} else {
 		log.Printf("[S3] Using custom resume public URL: %s", resumePublicURL)
@@ -83,7 +148,7 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 
 	taskProofPublicURL := cfg.TaskProofPublicURL
 	if taskProofPublicURL == "" {
-		taskProofPublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.TaskProofBucket, cfg.Region)
+		taskProofPublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.TaskProofBucket, taskProofRegion)
 		log.Printf("[S3] Using default task proof public URL: %s", taskProofPublicURL)
 	} else {
 		log.Printf("[S3] Using custom task proof public URL: %s", taskProofPublicURL)
@@ -98,7 +163,7 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 
 	badgePublicURL := cfg.BadgePublicURL
 	if badgePublicURL == "" {
-		badgePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", badgeBucket, cfg.Region)
+		badgePublicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", badgeBucket, badgeRegion)
 		log.Printf("[S3] Using default badge public URL: %s", badgePublicURL)
 	} else {
 		log.Printf("[S3] Using custom badge public URL: %s", badgePublicURL)
@@ -106,13 +171,21 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 
 	log.Printf("[S3] S3 storage initialized successfully")
 	return &S3Storage{
-		client:             client,
+		client:             defaultClient,
+		profileClient:      profileClient,
+		resumeClient:       resumeClient,
+		taskProofClient:    taskProofClient,
+		badgeClient:        badgeClient,
 		uploader:           uploader,
 		profileBucket:      cfg.ProfileBucket,
 		resumeBucket:       cfg.ResumeBucket,
 		taskProofBucket:    cfg.TaskProofBucket,
 		badgeBucket:        badgeBucket,
 		region:             cfg.Region,
+		profileRegion:      profileRegion,
+		resumeRegion:       resumeRegion,
+		taskProofRegion:    taskProofRegion,
+		badgeRegion:        badgeRegion,
 		profilePublicURL:   profilePublicURL,
 		resumePublicURL:    resumePublicURL,
 		taskProofPublicURL: taskProofPublicURL,
@@ -176,7 +249,7 @@ func (s *S3Storage) UploadBadgeImage(ctx context.Context, file io.Reader, badgeI
 	log.Printf("[S3] Badge image upload - Key: %s, ContentType: %s", key, contentType)
 
 	// Don't force download for badge images (display in browser)
-	url, err := s.UploadFile(ctx, file, s.badgeBucket, key, contentType, s.badgePublicURL, false)
+	url, err := s.uploadFile(ctx, s.badgeClient, file, s.badgeBucket, s.badgeRegion, key, contentType, s.badgePublicURL, false)
 	if err != nil {
 		log.Printf("[S3] ERROR: Badge image upload failed - BadgeID: %s, Key: %s, Error: %v", badgeID, key, err)
 		return "", err
@@ -186,7 +259,23 @@ func (s *S3Storage) UploadBadgeImage(ctx context.Context, file io.Reader, badgeI
 	return url, nil
 }
 
-// UploadFile uploads a file to S3 and returns the public URL
+// clientForBucket returns the client and region configured for a bucket
+func (s *S3Storage) clientForBucket(bucket string) (*s3.Client, string) {
+	switch bucket {
+	case s.taskProofBucket:
+		return s.taskProofClient, s.taskProofRegion
+	case s.profileBucket:
+		return s.profileClient, s.profileRegion
+	case s.resumeBucket:
+		return s.resumeClient, s.resumeRegion
+	case s.badgeBucket:
+		return s.badgeClient, s.badgeRegion
+	}
+	return s.client, s.region
+}
+
+// UploadFile uploads a file to S3 and returns the public URL.
+// The client is chosen from the bucket's configured region.
 func (s *S3Storage) UploadFile(
 	ctx context.Context,
 	file io.Reader,
@@ -196,11 +285,26 @@ func (s *S3Storage) UploadFile(
 	publicURL string,
 	forceDownload bool,
 ) (string, error) {
+	client, region := s.clientForBucket(bucket)
+	return s.uploadFile(ctx, client, file, bucket, region, key, contentType, publicURL, forceDownload)
+}
+
+// uploadFile uploads a file to S3 using the given client and returns the public URL
+func (s *S3Storage) uploadFile(
+	ctx context.Context,
+	client *s3.Client,
+	file io.Reader,
+	bucket string,
+	region string,
+	key string,
+	contentType string,
+	publicURL string,
+	forceDownload bool,
+) (string, error) {
 
 	input := &s3.PutObjectInput{
 		Bucket:      aws.String(bucket),
 		Key:         aws.String(key),
-		Body:        file,
 		ContentType: aws.String(contentType),
 	}
 
@@ -212,15 +316,46 @@ func (s *S3Storage) UploadFile(
 	}
 
 	start := time.Now()
-	result, err := s.client.PutObject(ctx, input)
-	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %w", err)
+	var etag *string
+	if body, ok := file.(io.ReadSeeker); ok {
+		// Seekable files may already be partly read (e.g. sniffed), so retries rewind to here rather than to 0
+		bodyStart, err := body.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+
+		var result *s3.PutObjectOutput
+		err = RetryWithBackoff(ctx, s3MaxAttempts, func() error {
+			if _, err := body.Seek(bodyStart, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to rewind file: %w", err)
+			}
+			input.Body = body
+			var err error
+			result, err = client.PutObject(ctx, input, withoutSDKRetries)
+			return err
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		}
+		etag = result.ETag
+	} else {
+		// A reader that can't seek can't be rewound for a retry. Instead of buffering it whole,
+		// the upload manager sends it one part at a time and the SDK retries each part.
+		input.Body = file
+		uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+			u.Concurrency = 1
+		})
+		result, err := uploader.Upload(ctx, input)
+		if err != nil {
+			return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		}
+		etag = result.ETag
 	}
 
 	// Ensure publicURL is not empty - construct default if needed
 	if publicURL == "" {
 		// Construct default S3 public URL
-		publicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, s.region)
+		publicURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
 		log.Printf("[S3] Warning: publicURL was empty, using default: %s", publicURL)
 	}
 
@@ -236,15 +371,15 @@ func (s *S3Storage) UploadFile(
 		"[S3] Upload successful - Bucket=%s Key=%s ETag=%s Duration=%v",
 		bucket,
 		key,
-		aws.ToString(result.ETag),
+		aws.ToString(etag),
 		time.Since(start),
 	)
 
 	return url, nil
 }
 
-// UploadResume uploads a resume file to S3 resume bucket
-func (s *S3Storage) UploadResume(ctx context.Context, file io.Reader, userID string, filename string) (string, error) {
+// UploadResume uploads a resume file to S3 resume bucket and returns its public URL and S3 key
+func (s *S3Storage) UploadResume(ctx context.Context, file io.Reader, userID string, filename string) (string, string, error) {
 	log.Printf("[S3] UploadResume - UserID: %s, OriginalFilename: %s", userID, filename)
 
 	ext := filepath.Ext(filename)
@@ -266,18 +401,18 @@ func (s *S3Storage) UploadResume(ctx context.Context, file io.Reader, userID str
 	log.Printf("[S3] Resume upload - Key: %s, ContentType: %s", key, contentType)
 
 	// Force download for resumes
-	url, err := s.UploadFile(ctx, file, s.resumeBucket, key, contentType, s.resumePublicURL, true)
+	url, err := s.uploadFile(ctx, s.resumeClient, file, s.resumeBucket, s.resumeRegion, key, contentType, s.resumePublicURL, true)
 	if err != nil {
 		log.Printf("[S3] ERROR: Resume upload failed - UserID: %s, Key: %s, Error: %v", userID, key, err)
-		return "", err
+		return "", "", err
 	}
 
 	log.Printf("[S3] Resume upload completed - UserID: %s, URL: %s", userID, url)
-	return url, nil
+	return url, key, nil
 }
 
-// UploadProfilePic uploads a profile picture to S3 profile bucket
-func (s *S3Storage) UploadProfilePic(ctx context.Context, file io.Reader, userID string, filename string) (string, error) {
+// UploadProfilePic uploads a profile picture to S3 profile bucket and returns its public URL and S3 key
+func (s *S3Storage) UploadProfilePic(ctx context.Context, file io.Reader, userID string, filename string) (string, string, error) {
 	log.Printf("[S3] UploadProfilePic - UserID: %s, OriginalFilename: %s", userID, filename)
 
 	ext := filepath.Ext(filename)
@@ -302,22 +437,25 @@ func (s *S3Storage) UploadProfilePic(ctx context.Context, file io.Reader, userID
 	log.Printf("[S3] Profile pic upload - Key: %s, ContentType: %s", key, contentType)
 
 	// Don't force download for profile pictures (display in browser)
-	url, err := s.UploadFile(ctx, file, s.profileBucket, key, contentType, s.profilePublicURL, false)
+	url, err := s.uploadFile(ctx, s.profileClient, file, s.profileBucket, s.profileRegion, key, contentType, s.profilePublicURL, false)
 	if err != nil {
 		log.Printf("[S3] ERROR: Profile pic upload failed - UserID: %s, Key: %s, Error: %v", userID, key, err)
-		return "", err
+		return "", "", err
 	}
 
 	log.Printf("[S3] Profile pic upload completed - UserID: %s, URL: %s", userID, url)
-	return url, nil
+	return url, key, nil
 }
 
 // DeleteResume deletes a resume file from S3
 func (s *S3Storage) DeleteResume(ctx context.Context, key string) error {
 	log.Printf("[S3] Deleting resume - Bucket: %s, Key: %s", s.resumeBucket, key)
-	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(s.resumeBucket),
-		Key:    aws.String(key),
+	err := RetryWithBackoff(ctx, s3MaxAttempts, func() error {
+		_, err := s.resumeClient.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.resumeBucket),
+			Key:    aws.String(key),
+		}, withoutSDKRetries)
+		return err
 	})
 	if err != nil {
 		log.Printf("[S3] ERROR: Failed to delete resume - Bucket: %s, Key: %s, Error: %v", s.resumeBucket, key, err)
@@ -330,9 +468,12 @@ func (s *S3Storage) DeleteResume(ctx context.Context, key string) error {
 // DeleteProfilePic deletes a profile picture from S3
 func (s *S3Storage) DeleteProfilePic(ctx context.Context, key string) error {
 	log.Printf("[S3] Deleting profile pic - Bucket: %s, Key: %s", s.profileBucket, key)
-	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(s.profileBucket),
-		Key:    aws.String(key),
+	err := RetryWithBackoff(ctx, s3MaxAttempts, func() error {
+		_, err := s.profileClient.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.profileBucket),
+			Key:    aws.String(key),
+		}, withoutSDKRetries)
+		return err
 	})
 	if err != nil {
 		log.Printf("[S3] ERROR: Failed to delete profile pic - Bucket: %s, Key: %s, Error: %v", s.profileBucket, key, err)
@@ -342,12 +483,37 @@ func (s *S3Storage) DeleteProfilePic(ctx context.Context, key string) error {
 	return nil
 }
 
+// UploadTaskProof uploads a task proof image or video to the task proof bucket and returns its public URL and S3 key.
+// contentType is the type detected by ValidateProofFile, which also picks the extension.
+// The key is task-proofs/{taskID}/{userID}_{timestamp}{ext}, so a resubmission never overwrites the earlier proof.
+func (s *S3Storage) UploadTaskProof(ctx context.Context, file io.Reader, taskID, userID, contentType string) (string, string, error) {
+	ext := TaskProofExtension(contentType)
+	if ext == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedProofType, contentType)
+	}
+	key := fmt.Sprintf("task-proofs/%s/%s_%d%s", taskID, userID, time.Now().UnixNano(), ext)
+
+	log.Printf("[S3] Task proof upload - Key: %s, ContentType: %s", key, contentType)
+
+	url, err := s.uploadFile(ctx, s.taskProofClient, file, s.taskProofBucket, s.taskProofRegion, key, contentType, s.taskProofPublicURL, false)
+	if err != nil {
+		log.Printf("[S3] ERROR: Task proof upload failed - UserID: %s, Key: %s, Error: %v", userID, key, err)
+		return "", "", err
+	}
+
+	log.Printf("[S3] Task proof upload completed - UserID: %s, URL: %s", userID, url)
+	return url, key, nil
+}
+
 // DeleteTaskProof deletes a task proof file from S3 (image or video)
 func (s *S3Storage) DeleteTaskProof(ctx context.Context, key string) error {
 	log.Printf("[S3] Deleting task proof - Bucket: %s, Key: %s", s.taskProofBucket, key)
-	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(s.taskProofBucket),
-		Key:    aws.String(key),
+	err := RetryWithBackoff(ctx, s3MaxAttempts, func() error {
+		_, err := s.taskProofClient.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.taskProofBucket),
+			Key:    aws.String(key),
+		}, withoutSDKRetries)
+		return err
 	})
 	if err != nil {
 		log.Printf("[S3] ERROR: Failed to delete task proof - Bucket: %s, Key: %s, Error: %v", s.taskProofBucket, key, err)
@@ -357,10 +523,86 @@ func (s *S3Storage) DeleteTaskProof(ctx context.Context, key string) error {
 	return nil
 }
 
+// ListTaskProofObjects calls fn for every object in the task proof bucket under prefix.
+// Listing stops at the first error returned by fn.
+func (s *S3Storage) ListTaskProofObjects(ctx context.Context, prefix string, fn func(key string, lastModified time.Time) error) error {
+	paginator := s3.NewListObjectsV2Paginator(s.taskProofClient, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.taskProofBucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			if err := fn(aws.ToString(obj.Key), aws.ToTime(obj.LastModified)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TaskProofObjectExists reports whether key exists in the task proof bucket
+func (s *S3Storage) TaskProofObjectExists(ctx context.Context, key string) (bool, error) {
+	_, err := s.taskProofClient.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.taskProofBucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check object %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// GetTaskProofObject downloads an object from the task proof bucket
+func (s *S3Storage) GetTaskProofObject(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.taskProofClient.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.taskProofBucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// GeneratePresignedTaskProofURL generates a presigned download URL for an object in the task proof bucket
+func (s *S3Storage) GeneratePresignedTaskProofURL(ctx context.Context, key string, duration time.Duration) (string, error) {
+	log.Printf("[S3] Generating presigned task proof URL - Bucket: %s, Key: %s, Duration: %v", s.taskProofBucket, key, duration)
+	presignClient := s3.NewPresignClient(s.taskProofClient)
+
+	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.taskProofBucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String("attachment"), // Force download
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = duration
+	})
+	if err != nil {
+		log.Printf("[S3] ERROR: Failed to generate presigned task proof URL - Key: %s, Error: %v", key, err)
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	log.Printf("[S3] Presigned task proof URL generated - Key: %s, Expires: %v", key, duration)
+	return request.URL, nil
+}
+
 // GeneratePresignedResumeURL generates a presigned URL for resume download
 func (s *S3Storage) GeneratePresignedResumeURL(ctx context.Context, key string, duration time.Duration) (string, error) {
 	log.Printf("[S3] Generating presigned resume URL - Bucket: %s, Key: %s, Duration: %v", s.resumeBucket, key, duration)
-	presignClient := s3.NewPresignClient(s.client)
+	presignClient := s3.NewPresignClient(s.resumeClient)
 
 	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket:                     aws.String(s.resumeBucket),
@@ -381,7 +623,7 @@ func (s *S3Storage) GeneratePresignedResumeURL(ctx context.Context, key string,
 // GeneratePresignedProfileURL generates a presigned URL for profile picture
 func (s *S3Storage) GeneratePresignedProfileURL(ctx context.Context, key string, duration time.Duration) (string, error) {
 	log.Printf("[S3] Generating presigned profile URL - Bucket: %s, Key: %s, Duration: %v", s.profileBucket, key, duration)
-	presignClient := s3.NewPresignClient(s.client)
+	presignClient := s3.NewPresignClient(s.profileClient)
 
 	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.profileBucket),
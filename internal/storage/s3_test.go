@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeS3 answers each request with the next status in statuses (200 once they run out)
+// and records the requests and bodies it received
+type fakeS3 struct {
+	mu           sync.Mutex
+	statuses     []int
+	requests     []string // method and path
+	contentTypes []string
+	bodies       []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.contentTypes = append(f.contentTypes, r.Header.Get("Content-Type"))
+	f.bodies = append(f.bodies, string(body))
+	status := http.StatusOK
+	if len(f.statuses) > 0 {
+		status, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+	f.mu.Unlock()
+
+	if status == http.StatusServiceUnavailable {
+		w.WriteHeader(status)
+		io.WriteString(w, `<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`)
+		return
+	}
+	if status == http.StatusForbidden {
+		w.WriteHeader(status)
+		io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("ETag", `"etag"`)
+	w.WriteHeader(status)
+}
+
+func newFakeS3Storage(t *testing.T, statuses ...int) (*S3Storage, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{statuses: statuses}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	return &S3Storage{
+		taskProofClient:    client,
+		taskProofBucket:    "proofs",
+		taskProofRegion:    "us-east-1",
+		taskProofPublicURL: "https://cdn.example.com",
+	}, fake
+}
+
+// seekOnly hides any other interface of a reader so uploadFile sees a plain io.ReadSeeker
+type seekOnly struct {
+	io.ReadSeeker
+}
+
+func TestUploadFileRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		file         func() io.Reader
+		wantErr      bool
+		wantRequests int
+		wantBody     string
+	}{
+		{name: "succeeds first time", file: func() io.Reader { return strings.NewReader("proof") }, wantRequests: 1, wantBody: "proof"},
+		{name: "retries slow down once per attempt", statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}, file: func() io.Reader { return strings.NewReader("proof") }, wantRequests: 3, wantBody: "proof"},
+		{name: "gives up after max attempts", statuses: []int{503, 503, 503, 503, 503, 503, 503, 503}, file: func() io.Reader { return strings.NewReader("proof") }, wantErr: true, wantRequests: s3MaxAttempts},
+		{name: "does not retry access denied", statuses: []int{http.StatusForbidden}, file: func() io.Reader { return strings.NewReader("proof") }, wantErr: true, wantRequests: 1},
+		{
+			name:     "rewinds to where the file was",
+			statuses: []int{http.StatusServiceUnavailable},
+			file: func() io.Reader {
+				r := strings.NewReader("headproof")
+				r.Seek(4, io.SeekStart)
+				return seekOnly{r}
+			},
+			wantRequests: 2,
+			wantBody:     "proof",
+		},
+		{name: "streams unseekable readers", statuses: []int{http.StatusServiceUnavailable}, file: func() io.Reader { return io.MultiReader(strings.NewReader("pro"), strings.NewReader("of")) }, wantRequests: 2, wantBody: "proof"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, fake := newFakeS3Storage(t, tt.statuses...)
+			url, _, err := s.UploadTaskProof(context.Background(), tt.file(), "task", "user", "image/png")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(fake.bodies) != tt.wantRequests {
+				t.Errorf("S3 got %d requests, want %d", len(fake.bodies), tt.wantRequests)
+			}
+			if tt.wantErr {
+				return
+			}
+			for i, body := range fake.bodies {
+				if body != tt.wantBody {
+					t.Errorf("request %d body = %q, want %q", i+1, body, tt.wantBody)
+				}
+			}
+			if !strings.HasPrefix(url, "https://cdn.example.com/task-proofs/task/user_") {
+				t.Errorf("url = %q", url)
+			}
+		})
+	}
+}
+
+func TestUploadTaskProof(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantExt     string // empty when the upload should be refused
+	}{
+		{name: "jpeg", contentType: "image/jpeg", wantExt: ".jpg"},
+		{name: "png", contentType: "image/png", wantExt: ".png"},
+		{name: "mp4", contentType: "video/mp4", wantExt: ".mp4"},
+		{name: "quicktime", contentType: "video/quicktime", wantExt: ".mov"},
+		{name: "unsupported", contentType: "application/pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, fake := newFakeS3Storage(t)
+			url, key, err := s.UploadTaskProof(context.Background(), strings.NewReader("proof"), "task-1", "user-1", tt.contentType)
+			if tt.wantExt == "" {
+				if !errors.Is(err, ErrUnsupportedProofType) {
+					t.Errorf("err = %v, want ErrUnsupportedProofType", err)
+				}
+				if len(fake.requests) != 0 {
+					t.Errorf("S3 got %v, want no requests", fake.requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UploadTaskProof: %v", err)
+			}
+
+			if !regexp.MustCompile(`^task-proofs/task-1/user-1_[0-9]+` + regexp.QuoteMeta(tt.wantExt) + `$`).MatchString(key) {
+				t.Errorf("key = %q, want task-proofs/task-1/user-1_{timestamp}%s", key, tt.wantExt)
+			}
+			if url != "https://cdn.example.com/"+key {
+				t.Errorf("url = %q, want the proof CDN URL of %q", url, key)
+			}
+			// Proofs go to the task proof bucket, never the profile bucket
+			if len(fake.requests) != 1 || fake.requests[0] != "PUT /proofs/"+key {
+				t.Errorf("S3 got %v, want PUT /proofs/%s", fake.requests, key)
+			}
+			if len(fake.contentTypes) == 1 && fake.contentTypes[0] != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", fake.contentTypes[0], tt.contentType)
+			}
+		})
+	}
+}
+
+func TestDeleteTaskProof(t *testing.T) {
+	s, fake := newFakeS3Storage(t)
+	if err := s.DeleteTaskProof(context.Background(), "task-proofs/task-1/user-1_1.png"); err != nil {
+		t.Fatalf("DeleteTaskProof: %v", err)
+	}
+	if len(fake.requests) != 1 || fake.requests[0] != "DELETE /proofs/task-proofs/task-1/user-1_1.png" {
+		t.Errorf("S3 got %v, want the key deleted from the proof bucket", fake.requests)
+	}
+}
+
+func TestDeleteTaskProofRetries(t *testing.T) {
+	s, fake := newFakeS3Storage(t, http.StatusServiceUnavailable)
+	if err := s.DeleteTaskProof(context.Background(), "task-proofs/task/user_1.png"); err != nil {
+		t.Fatalf("DeleteTaskProof: %v", err)
+	}
+	if len(fake.bodies) != 2 {
+		t.Errorf("S3 got %d requests, want 2", len(fake.bodies))
+	}
+}
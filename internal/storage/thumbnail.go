@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// ErrFFmpegNotInstalled is returned when the ffmpeg binary is not on PATH
+var ErrFFmpegNotInstalled = errors.New("ffmpeg is not installed")
+
+// GenerateVideoThumbnail extracts the frame at 1 second of a local video file as a JPEG using ffmpeg
+func GenerateVideoThumbnail(ctx context.Context, videoPath string) (io.Reader, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, ErrFFmpegNotInstalled
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-i", videoPath,
+		"-ss", "00:00:01",
+		"-vframes", "1",
+		"-f", "image2",
+		"-",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, stderr.String())
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("ffmpeg produced no thumbnail")
+	}
+
+	return &stdout, nil
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// Activity log event types
+const (
+	ActivityEventSubmissionCreated  = "submission_created"
+	ActivityEventSubmissionApproved = "submission_approved"
+	ActivityEventSubmissionRejected = "submission_rejected"
+	ActivityEventBadgeEarned        = "badge_earned"
+	ActivityEventStreakMilestone    = "streak_milestone"
+	ActivityEventFollowAdded        = "follow_added"
+	ActivityEventFollowRemoved      = "follow_removed"
+	ActivityEventCommentPosted      = "comment_posted"
+)
+
+// PublicActivityEvents are the event types shown on other users' profiles
+var PublicActivityEvents = []string{
+	ActivityEventSubmissionApproved,
+	ActivityEventBadgeEarned,
+	ActivityEventStreakMilestone,
+}
+
+// ActivityLog is one entry in a user's activity timeline
+type ActivityLog struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	EventType   string    `json:"event_type"`
+	EntityID    string    `json:"entity_id,omitempty"`
+	EntityType  string    `json:"entity_type,omitempty"` // "task", "submission", "badge", "user", "feed"
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RecordActivityRequest is the input for RecordActivity
+type RecordActivityRequest struct {
+	UserID      string
+	EventType   string
+	EntityID    string
+	EntityType  string
+	Description string
+}
+
+type ActivityLogStore struct {
+	postgres *db.Postgres
+}
+
+func NewActivityLogStore(postgres *db.Postgres) *ActivityLogStore {
+	return &ActivityLogStore{
+		postgres: postgres,
+	}
+}
+
+// RecordActivity appends an entry to the user's activity timeline
+func (s *ActivityLogStore) RecordActivity(ctx context.Context, req RecordActivityRequest) error {
+	query := `
+		INSERT INTO user_activity_log (user_id, event_type, entity_id, entity_type, description)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+	`
+	_, err := s.postgres.DB.ExecContext(ctx, query, req.UserID, req.EventType, req.EntityID, req.EntityType, req.Description)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// GetUserActivityLog returns one page of a user's activity, newest first, and the total count
+func (s *ActivityLogStore) GetUserActivityLog(ctx context.Context, userID string, page, pageSize int) ([]ActivityLog, int, error) {
+	return s.getActivityLog(ctx, userID, nil, page, pageSize)
+}
+
+// GetPublicActivityLog is GetUserActivityLog restricted to PublicActivityEvents
+func (s *ActivityLogStore) GetPublicActivityLog(ctx context.Context, userID string, page, pageSize int) ([]ActivityLog, int, error) {
+	return s.getActivityLog(ctx, userID, PublicActivityEvents, page, pageSize)
+}
+
+// getActivityLog pages through a user's activity. A nil eventTypes matches every event.
+func (s *ActivityLogStore) getActivityLog(ctx context.Context, userID string, eventTypes []string, page, pageSize int) ([]ActivityLog, int, error) {
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	var total int
+	countQuery := `
+		SELECT COUNT(*) FROM user_activity_log
+		WHERE user_id = $1 AND ($2::text[] IS NULL OR event_type = ANY($2))
+	`
+	err := s.postgres.DB.QueryRowContext(ctx, countQuery, userID, eventTypes).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count activity log: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, event_type, entity_id, entity_type, description, created_at
+		FROM user_activity_log
+		WHERE user_id = $1 AND ($2::text[] IS NULL OR event_type = ANY($2))
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, eventTypes, pageSize, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get activity log: %w", err)
+	}
+	defer rows.Close()
+
+	activities := []ActivityLog{}
+	for rows.Next() {
+		var activity ActivityLog
+		var entityID, entityType sql.NullString
+		if err := rows.Scan(
+			&activity.ID,
+			&activity.UserID,
+			&activity.EventType,
+			&entityID,
+			&entityType,
+			&activity.Description,
+			&activity.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan activity log: %w", err)
+		}
+		activity.EntityID = entityID.String
+		activity.EntityType = entityType.String
+		activities = append(activities, activity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating activity log: %w", err)
+	}
+
+	return activities, total, nil
+}
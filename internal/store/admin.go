@@ -7,18 +7,49 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
 )
 
+// Admin permission scopes
+const (
+	PermissionManageTasks       = "manage_tasks"
+	PermissionReviewSubmissions = "review_submissions"
+	PermissionManageUsers       = "manage_users"
+	PermissionManageBadges      = "manage_badges"
+	PermissionManageAdmins      = "manage_admins" // Super-admin only
+)
+
+// AllAdminPermissions lists every valid admin permission
+var AllAdminPermissions = []string{
+	PermissionManageTasks,
+	PermissionReviewSubmissions,
+	PermissionManageUsers,
+	PermissionManageBadges,
+	PermissionManageAdmins,
+}
+
+// IsValidAdminPermission returns true if p is a known admin permission
+func IsValidAdminPermission(p string) bool {
+	for _, perm := range AllAdminPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
 type Admin struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Username  string    `json:"username"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	Permissions []string  `json:"permissions"`
+	TOTPEnabled bool      `json:"totp_enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type AdminStore struct {
@@ -36,6 +67,8 @@ type CreateAdminRequest struct {
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+	// Permissions replaces the column's default permission set when non-nil
+	Permissions []string `json:"-"`
 }
 
 // CreateAdmin creates a new admin user
@@ -44,6 +77,11 @@ func (s *AdminStore) CreateAdmin(ctx context.Context, req CreateAdminRequest) (*
 	if req.Name == "" || req.Username == "" || req.Password == "" {
 		return nil, fmt.Errorf("name, username, and password are required")
 	}
+	for _, p := range req.Permissions {
+		if !IsValidAdminPermission(p) {
+			return nil, fmt.Errorf("invalid permission: %s", p)
+		}
+	}
 
 	// Check if username already exists
 	var exists bool
@@ -63,19 +101,26 @@ func (s *AdminStore) CreateAdmin(ctx context.Context, req CreateAdminRequest) (*
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Create admin
+	// Create admin, with its permissions in the same INSERT so it never exists without them
 	adminID := uuid.New().String()
 	query := `
 		INSERT INTO admins (id, name, username, password_hash, role)
 		VALUES ($1, $2, $3, $4, 'admin')
-		RETURNING id, name, username, role, created_at, updated_at
+		RETURNING id, name, username, role, permissions, created_at, updated_at
 	`
+	args := []any{adminID, req.Name, req.Username, string(hashedPassword)}
+	if req.Permissions != nil {
+		query = `
+			INSERT INTO admins (id, name, username, password_hash, role, permissions)
+			VALUES ($1, $2, $3, $4, 'admin', $5)
+			RETURNING id, name, username, role, permissions, created_at, updated_at
+		`
+		args = append(args, req.Permissions)
+	}
 
 	var admin Admin
-	err = s.postgres.DB.QueryRowContext(ctx, query,
-		adminID, req.Name, req.Username, string(hashedPassword),
-	).Scan(
-		&admin.ID, &admin.Name, &admin.Username, &admin.Role, &admin.CreatedAt, &admin.UpdatedAt,
+	err = s.postgres.DB.QueryRowContext(ctx, query, args...).Scan(
+		&admin.ID, &admin.Name, &admin.Username, &admin.Role, pgtype.NewMap().SQLScanner(&admin.Permissions), &admin.CreatedAt, &admin.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create admin: %w", err)
@@ -87,13 +132,13 @@ func (s *AdminStore) CreateAdmin(ctx context.Context, req CreateAdminRequest) (*
 // GetAdminByID retrieves an admin by ID
 func (s *AdminStore) GetAdminByID(ctx context.Context, adminID string) (*Admin, error) {
 	query := `
-		SELECT id, name, username, role, created_at, updated_at
+		SELECT id, name, username, role, permissions, totp_enabled, created_at, updated_at
 		FROM admins WHERE id = $1
 	`
 
 	var admin Admin
 	err := s.postgres.DB.QueryRowContext(ctx, query, adminID).Scan(
-		&admin.ID, &admin.Name, &admin.Username, &admin.Role, &admin.CreatedAt, &admin.UpdatedAt,
+		&admin.ID, &admin.Name, &admin.Username, &admin.Role, pgtype.NewMap().SQLScanner(&admin.Permissions), &admin.TOTPEnabled, &admin.CreatedAt, &admin.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -108,13 +153,13 @@ func (s *AdminStore) GetAdminByID(ctx context.Context, adminID string) (*Admin,
 // GetAdminByUsername retrieves an admin by username
 func (s *AdminStore) GetAdminByUsername(ctx context.Context, username string) (*Admin, error) {
 	query := `
-		SELECT id, name, username, role, created_at, updated_at
+		SELECT id, name, username, role, permissions, totp_enabled, created_at, updated_at
 		FROM admins WHERE username = $1
 	`
 
 	var admin Admin
 	err := s.postgres.DB.QueryRowContext(ctx, query, username).Scan(
-		&admin.ID, &admin.Name, &admin.Username, &admin.Role, &admin.CreatedAt, &admin.UpdatedAt,
+		&admin.ID, &admin.Name, &admin.Username, &admin.Role, pgtype.NewMap().SQLScanner(&admin.Permissions), &admin.TOTPEnabled, &admin.CreatedAt, &admin.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -141,3 +186,108 @@ func (s *AdminStore) VerifyAdminPassword(ctx context.Context, username, password
 	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password))
 	return err == nil, nil
 }
+
+// UpdateAdminPassword replaces an admin's password hash
+func (s *AdminStore) UpdateAdminPassword(ctx context.Context, adminID, newHash string) error {
+	query := `UPDATE admins SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, newHash, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to update admin password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("admin not found")
+	}
+
+	return nil
+}
+
+// UpdateAdminPermissions replaces an admin's permissions
+func (s *AdminStore) UpdateAdminPermissions(ctx context.Context, adminID string, permissions []string) (*Admin, error) {
+	for _, p := range permissions {
+		if !IsValidAdminPermission(p) {
+			return nil, fmt.Errorf("invalid permission: %s", p)
+		}
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	query := `
+		UPDATE admins SET permissions = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, name, username, role, permissions, totp_enabled, created_at, updated_at
+	`
+
+	var admin Admin
+	err := s.postgres.DB.QueryRowContext(ctx, query, permissions, adminID).Scan(
+		&admin.ID, &admin.Name, &admin.Username, &admin.Role, pgtype.NewMap().SQLScanner(&admin.Permissions), &admin.TOTPEnabled, &admin.CreatedAt, &admin.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("admin not found")
+		}
+		return nil, fmt.Errorf("failed to update admin permissions: %w", err)
+	}
+
+	return &admin, nil
+}
+
+// SetTOTPSecret stores a new, unconfirmed TOTP secret for an admin and leaves 2FA disabled
+// until EnableTOTP is called
+func (s *AdminStore) SetTOTPSecret(ctx context.Context, adminID, secret string) error {
+	query := `UPDATE admins SET totp_secret = $1, totp_enabled = FALSE, updated_at = NOW() WHERE id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, secret, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to set TOTP secret: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("admin not found")
+	}
+
+	return nil
+}
+
+// GetTOTPSecret returns an admin's TOTP secret (empty when setup was never started) and whether 2FA is enabled
+func (s *AdminStore) GetTOTPSecret(ctx context.Context, adminID string) (string, bool, error) {
+	query := `SELECT totp_secret, totp_enabled FROM admins WHERE id = $1`
+	var secret sql.NullString
+	var enabled bool
+	err := s.postgres.DB.QueryRowContext(ctx, query, adminID).Scan(&secret, &enabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, fmt.Errorf("admin not found")
+		}
+		return "", false, fmt.Errorf("failed to get TOTP secret: %w", err)
+	}
+
+	return secret.String, enabled, nil
+}
+
+// EnableTOTP turns on 2FA for an admin whose TOTP secret has been confirmed
+func (s *AdminStore) EnableTOTP(ctx context.Context, adminID string) error {
+	query := `UPDATE admins SET totp_enabled = TRUE, updated_at = NOW() WHERE id = $1 AND totp_secret IS NOT NULL`
+	result, err := s.postgres.DB.ExecContext(ctx, query, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("admin not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestAdminStoreCreateAdmin(t *testing.T) {
+	columns := []string{"id", "name", "username", "role", "permissions", "created_at", "updated_at"}
+	tests := []struct {
+		name        string
+		permissions []string
+		wantQuery   string
+		wantArgs    []any
+		wantErr     string
+	}{
+		{
+			name:      "default permissions",
+			wantQuery: `INSERT INTO admins \(id, name, username, password_hash, role\)\s+VALUES \(\$1, \$2, \$3, \$4, 'admin'\)`,
+			wantArgs:  []any{testutil.AnyArg(), "Root", "root", testutil.AnyArg()},
+		},
+		{
+			name:        "permissions in the same insert",
+			permissions: store.AllAdminPermissions,
+			wantQuery:   `INSERT INTO admins \(id, name, username, password_hash, role, permissions\)\s+VALUES \(\$1, \$2, \$3, \$4, 'admin', \$5\)`,
+			wantArgs:    []any{testutil.AnyArg(), "Root", "root", testutil.AnyArg(), store.AllAdminPermissions},
+		},
+		{name: "invalid permission", permissions: []string{"delete_everything"}, wantErr: "invalid permission: delete_everything"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			if tt.wantErr == "" {
+				mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM admins WHERE username = \$1\)`).
+					WithArgs("root").
+					WillReturnRows([]string{"exists"}, []any{false})
+				mock.ExpectQuery(tt.wantQuery).
+					WithArgs(tt.wantArgs...).
+					WillReturnRows(columns, []any{testutil.TestAdminID, "Root", "root", "admin", tt.permissions, testutil.TestTime, testutil.TestTime})
+			}
+
+			admin, err := store.NewAdminStore(postgres).CreateAdmin(context.Background(), store.CreateAdminRequest{
+				Name:        "Root",
+				Username:    "root",
+				Password:    "Str0ng!pass",
+				Permissions: tt.permissions,
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateAdmin: %v", err)
+			}
+			if len(admin.Permissions) != len(tt.permissions) {
+				t.Errorf("permissions = %v, want %v", admin.Permissions, tt.permissions)
+			}
+		})
+	}
+}
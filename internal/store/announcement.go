@@ -0,0 +1,123 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+type Announcement struct {
+	ID        string    `json:"id"`
+	AdminID   string    `json:"admin_id,omitempty"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AnnouncementStore struct {
+	postgres *db.Postgres
+}
+
+func NewAnnouncementStore(postgres *db.Postgres) *AnnouncementStore {
+	return &AnnouncementStore{
+		postgres: postgres,
+	}
+}
+
+// CreateAnnouncementRequest represents the request to create an announcement
+type CreateAnnouncementRequest struct {
+	AdminID string `json:"admin_id"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// CreateAnnouncement stores a new announcement
+func (s *AnnouncementStore) CreateAnnouncement(ctx context.Context, req CreateAnnouncementRequest) (*Announcement, error) {
+	if req.Type == "" {
+		req.Type = "announcement"
+	}
+
+	announcementID := uuid.New().String()
+	query := `
+		INSERT INTO announcements (id, admin_id, title, message, type)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, admin_id, title, message, type, created_at
+	`
+
+	var announcement Announcement
+	var adminID sql.NullString
+	err := s.postgres.DB.QueryRowContext(ctx, query,
+		announcementID, req.AdminID, req.Title, req.Message, req.Type,
+	).Scan(
+		&announcement.ID, &adminID, &announcement.Title, &announcement.Message, &announcement.Type, &announcement.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create announcement: %w", err)
+	}
+
+	if adminID.Valid {
+		announcement.AdminID = adminID.String
+	}
+
+	return &announcement, nil
+}
+
+// GetAnnouncements retrieves announcements, newest first
+func (s *AnnouncementStore) GetAnnouncements(ctx context.Context, limit, offset int) ([]Announcement, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM announcements`
+	if err := s.postgres.DB.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count announcements: %w", err)
+	}
+
+	query := `
+		SELECT id, admin_id, title, message, type, created_at
+		FROM announcements
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []Announcement{}
+	for rows.Next() {
+		var announcement Announcement
+		var adminID sql.NullString
+
+		err := rows.Scan(
+			&announcement.ID, &adminID, &announcement.Title, &announcement.Message, &announcement.Type, &announcement.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan announcement: %w", err)
+		}
+
+		if adminID.Valid {
+			announcement.AdminID = adminID.String
+		}
+
+		announcements = append(announcements, announcement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating announcement rows: %w", err)
+	}
+
+	return announcements, total, nil
+}
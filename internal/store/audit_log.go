@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// Admin audit log actions
+const (
+	AuditActionUsersBulkImport        = "users_bulk_import"
+	AuditActionUserXPAdjust           = "user_xp_adjust"
+	AuditActionSubmissionsBulkApprove = "submissions_bulk_approve"
+	AuditActionSubmissionsBulkReject  = "submissions_bulk_reject"
+)
+
+type AuditLogStore struct {
+	postgres *db.Postgres
+}
+
+func NewAuditLogStore(postgres *db.Postgres) *AuditLogStore {
+	return &AuditLogStore{
+		postgres: postgres,
+	}
+}
+
+// Record adds an entry to the admin audit log. details is stored as JSON.
+func (s *AuditLogStore) Record(ctx context.Context, adminID, action string, details interface{}) error {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log details: %w", err)
+	}
+
+	query := `
+		INSERT INTO admin_audit_log (admin_id, action, details)
+		VALUES (NULLIF($1, '')::uuid, $2, $3)
+	`
+	if _, err := s.postgres.DB.ExecContext(ctx, query, adminID, action, detailsJSON); err != nil {
+		return fmt.Errorf("failed to record audit log entry: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -191,6 +192,27 @@ func (s *BadgeStore) AwardBadge(ctx context.Context, userID, badgeID string) err
 		return fmt.Errorf("failed to award badge: %w", err)
 	}
 
+	// The badge is awarded either way; an onboarding failure is only logged
+	onboardingStore := NewOnboardingStore(s.postgres)
+	if _, err := onboardingStore.MarkStep(ctx, userID, OnboardingStepEarnFirstBadge); err != nil {
+		log.Printf("[Onboarding] Failed to mark %s for user %s: %v", OnboardingStepEarnFirstBadge, userID, err)
+	}
+
+	description := "Earned a badge"
+	if badge, err := s.GetBadgeByID(ctx, badgeID); err == nil {
+		description = fmt.Sprintf("Earned the %s badge", badge.Name)
+	}
+	activityStore := NewActivityLogStore(s.postgres)
+	if err := activityStore.RecordActivity(ctx, RecordActivityRequest{
+		UserID:      userID,
+		EventType:   ActivityEventBadgeEarned,
+		EntityID:    badgeID,
+		EntityType:  "badge",
+		Description: description,
+	}); err != nil {
+		log.Printf("[Activity] Failed to record badge for user %s: %v", userID, err)
+	}
+
 	return nil
 }
 
@@ -281,3 +303,116 @@ func (s *BadgeStore) GetAllBadges(ctx context.Context) ([]Badge, error) {
 
 	return badges, nil
 }
+
+// BadgeWithStatus is a badge along with whether the requesting user has earned it
+type BadgeWithStatus struct {
+	Badge
+	Earned   bool       `json:"earned"`
+	EarnedAt *time.Time `json:"earned_at,omitempty"`
+}
+
+// BadgeFilter narrows the public badge catalogue
+type BadgeFilter struct {
+	IsStreakBadge *bool
+	Search        string
+}
+
+// ListBadges retrieves the badge catalogue matching filter. When userID is set,
+// each badge is marked with whether (and when) that user earned it.
+func (s *BadgeStore) ListBadges(ctx context.Context, filter BadgeFilter, userID string) ([]BadgeWithStatus, error) {
+	query := `
+		SELECT b.id, b.name, b.icon, b.rule, b.xp, b.required_level, b.image_url, b.is_streak_badge, b.created_at, ub.earned_at
+		FROM badges b
+		LEFT JOIN user_badges ub ON ub.badge_id = b.id AND ub.user_id = NULLIF($1, '')::uuid
+		WHERE 1=1
+	`
+	args := []interface{}{userID}
+	argPos := 2
+
+	if filter.IsStreakBadge != nil {
+		query += fmt.Sprintf(" AND b.is_streak_badge = $%d", argPos)
+		args = append(args, *filter.IsStreakBadge)
+		argPos++
+	}
+	if filter.Search != "" {
+		query += fmt.Sprintf(" AND (b.name ILIKE $%d OR b.rule ILIKE $%d)", argPos, argPos)
+		args = append(args, "%"+filter.Search+"%")
+		argPos++
+	}
+	query += " ORDER BY b.required_level ASC, b.xp ASC, b.name ASC"
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query badges: %w", err)
+	}
+	defer rows.Close()
+
+	badges := []BadgeWithStatus{}
+	for rows.Next() {
+		badge, err := scanBadgeWithStatus(rows)
+		if err != nil {
+			return nil, err
+		}
+		badges = append(badges, *badge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating badge rows: %w", err)
+	}
+
+	return badges, nil
+}
+
+// GetBadgeWithStatus retrieves a single badge. When userID is set, the badge is
+// marked with whether (and when) that user earned it.
+func (s *BadgeStore) GetBadgeWithStatus(ctx context.Context, badgeID, userID string) (*BadgeWithStatus, error) {
+	query := `
+		SELECT b.id, b.name, b.icon, b.rule, b.xp, b.required_level, b.image_url, b.is_streak_badge, b.created_at, ub.earned_at
+		FROM badges b
+		LEFT JOIN user_badges ub ON ub.badge_id = b.id AND ub.user_id = NULLIF($2, '')::uuid
+		WHERE b.id = $1
+	`
+
+	badge, err := scanBadgeWithStatus(s.postgres.DB.QueryRowContext(ctx, query, badgeID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("badge not found")
+		}
+		return nil, err
+	}
+
+	return badge, nil
+}
+
+// scanBadgeWithStatus scans a badge row followed by the user's earned_at (nullable)
+func scanBadgeWithStatus(row interface{ Scan(dest ...any) error }) (*BadgeWithStatus, error) {
+	var badge BadgeWithStatus
+	var icon, rule, imageURL sql.NullString
+	var earnedAt sql.NullTime
+
+	err := row.Scan(
+		&badge.ID, &badge.Name, &icon, &rule, &badge.XP, &badge.RequiredLevel, &imageURL, &badge.IsStreakBadge, &badge.CreatedAt, &earnedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan badge: %w", err)
+	}
+
+	if icon.Valid {
+		badge.Icon = icon.String
+	}
+	if rule.Valid {
+		badge.Rule = rule.String
+	}
+	if imageURL.Valid {
+		badge.ImageURL = imageURL.String
+	}
+	if earnedAt.Valid {
+		badge.Earned = true
+		badge.EarnedAt = &earnedAt.Time
+	}
+
+	return &badge, nil
+}
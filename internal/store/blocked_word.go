@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// BlockedWord is a word rejected or masked by the content filter
+type BlockedWord struct {
+	ID        string    `json:"id"`
+	Word      string    `json:"word"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BlockedWordStore struct {
+	postgres *db.Postgres
+}
+
+func NewBlockedWordStore(postgres *db.Postgres) *BlockedWordStore {
+	return &BlockedWordStore{
+		postgres: postgres,
+	}
+}
+
+// GetBlockedWords returns all blocked words in alphabetical order
+func (s *BlockedWordStore) GetBlockedWords(ctx context.Context) ([]BlockedWord, error) {
+	query := `
+		SELECT id, word, created_by, created_at
+		FROM blocked_words
+		ORDER BY word ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked words: %w", err)
+	}
+	defer rows.Close()
+
+	words := []BlockedWord{}
+	for rows.Next() {
+		var word BlockedWord
+		var createdBy sql.NullString
+		if err := rows.Scan(&word.ID, &word.Word, &createdBy, &word.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked word: %w", err)
+		}
+		if createdBy.Valid {
+			word.CreatedBy = createdBy.String
+		}
+		words = append(words, word)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating blocked words: %w", err)
+	}
+
+	return words, nil
+}
+
+// GetBlockedWordList returns just the words, for loading into the word filter
+func (s *BlockedWordStore) GetBlockedWordList(ctx context.Context) ([]string, error) {
+	words, err := s.GetBlockedWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, len(words))
+	for _, w := range words {
+		list = append(list, w.Word)
+	}
+	return list, nil
+}
+
+// AddBlockedWord adds a word (stored lowercase) to the blocklist
+func (s *BlockedWordStore) AddBlockedWord(ctx context.Context, word, adminID string) (*BlockedWord, error) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return nil, fmt.Errorf("word is required")
+	}
+
+	query := `
+		INSERT INTO blocked_words (word, created_by)
+		VALUES ($1, NULLIF($2, '')::uuid)
+		ON CONFLICT (word) DO NOTHING
+		RETURNING id, word, created_by, created_at
+	`
+
+	var blocked BlockedWord
+	var createdBy sql.NullString
+	err := s.postgres.DB.QueryRowContext(ctx, query, word, adminID).Scan(
+		&blocked.ID, &blocked.Word, &createdBy, &blocked.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("word already blocked")
+		}
+		return nil, fmt.Errorf("failed to add blocked word: %w", err)
+	}
+	if createdBy.Valid {
+		blocked.CreatedBy = createdBy.String
+	}
+
+	return &blocked, nil
+}
+
+// DeleteBlockedWord removes a word from the blocklist
+func (s *BlockedWordStore) DeleteBlockedWord(ctx context.Context, id string) error {
+	result, err := s.postgres.DB.ExecContext(ctx, `DELETE FROM blocked_words WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blocked word: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("blocked word not found")
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+)
+
+// Chat room types. Global rooms are open to everyone; state and college rooms to the
+// users of the state or college identified by the room's scope_id.
+const (
+	ChatRoomTypeGlobal  = "global"
+	ChatRoomTypeState   = "state"
+	ChatRoomTypeCollege = "college"
+)
+
+// ChatRoom is a chat room
+type ChatRoom struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	ScopeID   string    `json:"scope_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CanAccess reports whether user may read and post in the room
+func (r *ChatRoom) CanAccess(user *User) bool {
+	switch r.Type {
+	case ChatRoomTypeGlobal:
+		return true
+	case ChatRoomTypeState:
+		return user.StateID != "" && user.StateID == r.ScopeID
+	case ChatRoomTypeCollege:
+		return user.CollegeID != "" && user.CollegeID == r.ScopeID
+	}
+	return false
+}
+
+// ChatMessage is a message posted in a chat room
+type ChatMessage struct {
+	ID         string    `json:"id"`
+	RoomID     string    `json:"room_id"`
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	UserAvatar string    `json:"user_avatar,omitempty"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ChatMessageCursor identifies the last message seen when paging by (created_at, id) descending
+type ChatMessageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns an opaque cursor string for clients
+func (c ChatMessageCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeChatMessageCursor parses a cursor string produced by ChatMessageCursor.Encode
+func DecodeChatMessageCursor(cursor string) (*ChatMessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &ChatMessageCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+type ChatStore struct {
+	postgres *db.Postgres
+}
+
+func NewChatStore(postgres *db.Postgres) *ChatStore {
+	return &ChatStore{
+		postgres: postgres,
+	}
+}
+
+// GetRoomsForUser returns the global rooms plus the rooms of the user's state and college.
+// State and college rooms are created the first time one of their users asks for them.
+func (s *ChatStore) GetRoomsForUser(ctx context.Context, userID string) ([]ChatRoom, error) {
+	ensureQuery := `
+		INSERT INTO chat_rooms (name, type, scope_id)
+		SELECT st.name, 'state', st.id FROM users u JOIN states st ON st.id = u.state_id WHERE u.id = $1
+		UNION ALL
+		SELECT c.name, 'college', c.id FROM users u JOIN colleges c ON c.id = u.college_id WHERE u.id = $1
+		ON CONFLICT (type, scope_id) WHERE scope_id IS NOT NULL DO NOTHING
+	`
+	if _, err := s.postgres.DB.ExecContext(ctx, ensureQuery, userID); err != nil {
+		return nil, fmt.Errorf("failed to create chat rooms: %w", err)
+	}
+
+	query := `
+		SELECT r.id, r.name, r.type, r.scope_id, r.created_at
+		FROM chat_rooms r
+		LEFT JOIN users u ON u.id = $1
+		WHERE r.type = 'global'
+			OR (r.type = 'state' AND r.scope_id = u.state_id)
+			OR (r.type = 'college' AND r.scope_id = u.college_id)
+		ORDER BY CASE r.type WHEN 'global' THEN 0 WHEN 'state' THEN 1 ELSE 2 END, r.name ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat rooms: %w", err)
+	}
+	defer rows.Close()
+
+	rooms := []ChatRoom{}
+	for rows.Next() {
+		room, err := scanChatRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, *room)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chat rooms: %w", err)
+	}
+
+	return rooms, nil
+}
+
+// GetRoomByID returns a chat room. Returns "chat room not found" if it does not exist.
+func (s *ChatStore) GetRoomByID(ctx context.Context, roomID string) (*ChatRoom, error) {
+	query := `SELECT id, name, type, scope_id, created_at FROM chat_rooms WHERE id = $1`
+
+	room, err := scanChatRoom(s.postgres.DB.QueryRowContext(ctx, query, roomID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("chat room not found")
+		}
+		return nil, err
+	}
+	return room, nil
+}
+
+// scanChatRoom scans id, name, type, scope_id and created_at
+func scanChatRoom(row interface{ Scan(...any) error }) (*ChatRoom, error) {
+	var room ChatRoom
+	var scopeID sql.NullString
+	if err := row.Scan(&room.ID, &room.Name, &room.Type, &scopeID, &room.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan chat room: %w", err)
+	}
+	if scopeID.Valid {
+		room.ScopeID = scopeID.String
+	}
+	return &room, nil
+}
+
+// GetMessages returns up to limit messages of a room, newest first (created_at DESC, id DESC).
+// With a cursor, only messages older than the cursor are returned, so messages posted between
+// requests don't shift pages. nextCursor is nil when there are no older messages.
+func (s *ChatStore) GetMessages(ctx context.Context, roomID string, limit int, cursor *ChatMessageCursor) ([]ChatMessage, *ChatMessageCursor, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	args := []interface{}{roomID}
+	condition := ""
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		condition = "AND (m.created_at, m.id) < ($2::timestamp, $3::uuid)"
+	}
+	args = append(args, limit+1)
+
+	query := fmt.Sprintf(`
+		SELECT m.id, m.room_id, m.user_id, u.name, COALESCE(u.avatar_url, ''), m.content, m.created_at
+		FROM chat_messages m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.room_id = $1 %s
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $%d
+	`, condition, len(args))
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []ChatMessage{}
+	for rows.Next() {
+		var message ChatMessage
+		if err := rows.Scan(&message.ID, &message.RoomID, &message.UserID, &message.UserName,
+			&message.UserAvatar, &message.Content, &message.CreatedAt); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating chat messages: %w", err)
+	}
+
+	// One extra row was fetched to tell whether another page exists
+	var nextCursor *ChatMessageCursor
+	if len(messages) > limit {
+		messages = messages[:limit]
+		last := messages[len(messages)-1]
+		nextCursor = &ChatMessageCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return messages, nextCursor, nil
+}
+
+// CreateMessage posts a message in a room.
+// Blocked words are rejected or masked depending on the word filter's comment mode.
+func (s *ChatStore) CreateMessage(ctx context.Context, roomID, userID, content string) (*ChatMessage, error) {
+	if filter := moderation.GetWordFilter(); filter != nil {
+		cleaned, found := filter.Filter(content)
+		if len(found) > 0 {
+			if filter.CommentMode == moderation.FilterModeReject {
+				return nil, fmt.Errorf("message contains blocked words")
+			}
+			content = cleaned
+		}
+	}
+
+	query := `
+		WITH m AS (
+			INSERT INTO chat_messages (room_id, user_id, content)
+			VALUES ($1, $2, $3)
+			RETURNING id, room_id, user_id, content, created_at
+		)
+		SELECT m.id, m.room_id, m.user_id, u.name, COALESCE(u.avatar_url, ''), m.content, m.created_at
+		FROM m
+		JOIN users u ON u.id = m.user_id
+	`
+
+	var message ChatMessage
+	err := s.postgres.DB.QueryRowContext(ctx, query, roomID, userID, content).Scan(
+		&message.ID, &message.RoomID, &message.UserID, &message.UserName,
+		&message.UserAvatar, &message.Content, &message.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat message: %w", err)
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,223 @@
+package store_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+const testRoomID = "abababab-abab-abab-abab-abababababab"
+
+var (
+	chatRoomColumns    = []string{"id", "name", "type", "scope_id", "created_at"}
+	chatMessageColumns = []string{"id", "room_id", "user_id", "name", "avatar_url", "content", "created_at"}
+)
+
+func TestChatStoreGetRoomsForUser(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	// The user's state and college rooms are created on first use
+	mockDB.ExpectExec(`INSERT INTO chat_rooms \(name, type, scope_id\)[\s\S]+'state', st.id FROM users u JOIN states st ON st.id = u.state_id WHERE u.id = \$1[\s\S]+'college', c.id FROM users u JOIN colleges c ON c.id = u.college_id WHERE u.id = \$1\s+ON CONFLICT`).
+		WithArgs(testutil.TestUserID).
+		WillReturnResult(0)
+	mockDB.ExpectQuery(`WHERE r.type = 'global'\s+OR \(r.type = 'state' AND r.scope_id = u.state_id\)\s+OR \(r.type = 'college' AND r.scope_id = u.college_id\)`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows(chatRoomColumns,
+			[]any{"room-global", "India", "global", nil, testutil.TestTime},
+			[]any{"room-state", "Karnataka", "state", testutil.TestStateID, testutil.TestTime},
+			[]any{"room-college", "Test College", "college", testutil.TestCollegeID, testutil.TestTime})
+
+	rooms, err := store.NewChatStore(postgres).GetRoomsForUser(context.Background(), testutil.TestUserID)
+	if err != nil {
+		t.Fatalf("GetRoomsForUser: %v", err)
+	}
+	want := []store.ChatRoom{
+		{ID: "room-global", Name: "India", Type: store.ChatRoomTypeGlobal, CreatedAt: testutil.TestTime},
+		{ID: "room-state", Name: "Karnataka", Type: store.ChatRoomTypeState, ScopeID: testutil.TestStateID, CreatedAt: testutil.TestTime},
+		{ID: "room-college", Name: "Test College", Type: store.ChatRoomTypeCollege, ScopeID: testutil.TestCollegeID, CreatedAt: testutil.TestTime},
+	}
+	if !reflect.DeepEqual(rooms, want) {
+		t.Errorf("rooms = %+v, want %+v", rooms, want)
+	}
+}
+
+func TestChatStoreGetRoomByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    [][]any
+		wantErr string
+	}{
+		{name: "found", rows: [][]any{{testRoomID, "Karnataka", "state", testutil.TestStateID, testutil.TestTime}}},
+		{name: "unknown room", wantErr: "chat room not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`FROM chat_rooms WHERE id = \$1`).
+				WithArgs(testRoomID).
+				WillReturnRows(chatRoomColumns, tt.rows...)
+
+			room, err := store.NewChatStore(postgres).GetRoomByID(context.Background(), testRoomID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetRoomByID: %v", err)
+			}
+			if room.ID != testRoomID || room.Type != store.ChatRoomTypeState || room.ScopeID != testutil.TestStateID {
+				t.Errorf("room = %+v", room)
+			}
+		})
+	}
+}
+
+func TestChatStoreGetMessages(t *testing.T) {
+	message := func(i int) []any {
+		return []any{"msg-" + string(rune('a'+i)), testRoomID, testutil.TestUserID, "Test User", "", "hello", testutil.TestTime.Add(-time.Duration(i) * time.Minute)}
+	}
+	messages := func(n int) [][]any {
+		var rows [][]any
+		for i := 0; i < n; i++ {
+			rows = append(rows, message(i))
+		}
+		return rows
+	}
+	cursor := &store.ChatMessageCursor{CreatedAt: testutil.TestTime, ID: "msg-z"}
+	tests := []struct {
+		name           string
+		limit          int
+		cursor         *store.ChatMessageCursor
+		wantQuery      string
+		wantArgs       []any
+		rows           [][]any
+		wantMessages   int
+		wantNextCursor *store.ChatMessageCursor
+	}{
+		{name: "newest page", limit: 3, wantQuery: `WHERE m.room_id = \$1\s+ORDER BY m.created_at DESC, m.id DESC\s+LIMIT \$2`, wantArgs: []any{testRoomID, 4}, rows: messages(4), wantMessages: 3,
+			wantNextCursor: &store.ChatMessageCursor{CreatedAt: testutil.TestTime.Add(-2 * time.Minute), ID: "msg-c"}},
+		{name: "last page", limit: 3, wantQuery: `LIMIT \$2`, wantArgs: []any{testRoomID, 4}, rows: messages(3), wantMessages: 3},
+		{name: "default limit", wantQuery: `LIMIT \$2`, wantArgs: []any{testRoomID, 51}, rows: messages(1), wantMessages: 1},
+		{name: "empty room", limit: 3, wantQuery: `LIMIT \$2`, wantArgs: []any{testRoomID, 4}},
+		{name: "older than cursor", limit: 3, cursor: cursor, wantQuery: `WHERE m.room_id = \$1 AND \(m.created_at, m.id\) < \(\$2::timestamp, \$3::uuid\)\s+ORDER BY m.created_at DESC, m.id DESC\s+LIMIT \$4`,
+			wantArgs: []any{testRoomID, testutil.TestTime, "msg-z", 4}, rows: messages(2), wantMessages: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(tt.wantQuery).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows(chatMessageColumns, tt.rows...)
+
+			got, nextCursor, err := store.NewChatStore(postgres).GetMessages(context.Background(), testRoomID, tt.limit, tt.cursor)
+			if err != nil {
+				t.Fatalf("GetMessages: %v", err)
+			}
+			if got == nil || len(got) != tt.wantMessages {
+				t.Fatalf("got %d messages, want %d", len(got), tt.wantMessages)
+			}
+			if !reflect.DeepEqual(nextCursor, tt.wantNextCursor) {
+				t.Errorf("next cursor = %+v, want %+v", nextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
+
+func TestChatStoreCreateMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		filterMode  moderation.FilterMode // empty for no word filter
+		content     string
+		wantContent string // stored content; empty when the message is refused
+		wantErr     string
+	}{
+		{name: "without word filter", content: "darn it", wantContent: "darn it"},
+		{name: "clean message", filterMode: moderation.FilterModeReject, content: "hello all", wantContent: "hello all"},
+		{name: "blocked word masked", filterMode: moderation.FilterModeReplace, content: "darn it", wantContent: "*** it"},
+		{name: "blocked word rejected", filterMode: moderation.FilterModeReject, content: "Darn it", wantErr: "message contains blocked words"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := moderation.GetWordFilter()
+			t.Cleanup(func() { moderation.SetWordFilter(previous) })
+			moderation.SetWordFilter(nil)
+			if tt.filterMode != "" {
+				loader := func(ctx context.Context) ([]string, error) { return []string{"darn"}, nil }
+				moderation.SetWordFilter(moderation.NewWordFilter(context.Background(), loader, "", tt.filterMode))
+			}
+
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.wantErr == "" {
+				mockDB.ExpectQuery(`INSERT INTO chat_messages \(room_id, user_id, content\)\s+VALUES \(\$1, \$2, \$3\)`).
+					WithArgs(testRoomID, testutil.TestUserID, tt.wantContent).
+					WillReturnRows(chatMessageColumns, []any{"msg-a", testRoomID, testutil.TestUserID, "Test User", "", tt.wantContent, testutil.TestTime})
+			}
+
+			message, err := store.NewChatStore(postgres).CreateMessage(context.Background(), testRoomID, testutil.TestUserID, tt.content)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateMessage: %v", err)
+			}
+			if message.Content != tt.wantContent || message.UserName != "Test User" {
+				t.Errorf("message = %+v", message)
+			}
+		})
+	}
+}
+
+func TestChatRoomCanAccess(t *testing.T) {
+	user := &store.User{StateID: testutil.TestStateID, CollegeID: testutil.TestCollegeID}
+	tests := []struct {
+		name string
+		room store.ChatRoom
+		user *store.User
+		want bool
+	}{
+		{name: "global", room: store.ChatRoom{Type: store.ChatRoomTypeGlobal}, user: &store.User{}, want: true},
+		{name: "own state", room: store.ChatRoom{Type: store.ChatRoomTypeState, ScopeID: testutil.TestStateID}, user: user, want: true},
+		{name: "other state", room: store.ChatRoom{Type: store.ChatRoomTypeState, ScopeID: "other"}, user: user},
+		{name: "own college", room: store.ChatRoom{Type: store.ChatRoomTypeCollege, ScopeID: testutil.TestCollegeID}, user: user, want: true},
+		{name: "other college", room: store.ChatRoom{Type: store.ChatRoomTypeCollege, ScopeID: "other"}, user: user},
+		// A user without a college must not match a room with an empty scope
+		{name: "no college", room: store.ChatRoom{Type: store.ChatRoomTypeCollege}, user: &store.User{}},
+		{name: "unknown type", room: store.ChatRoom{Type: "direct"}, user: user},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.room.CanAccess(tt.user); got != tt.want {
+				t.Errorf("CanAccess = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatMessageCursorEncode(t *testing.T) {
+	cursor := store.ChatMessageCursor{CreatedAt: testutil.TestTime.Add(123456 * time.Nanosecond), ID: "msg-a"}
+	decoded, err := store.DecodeChatMessageCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeChatMessageCursor: %v", err)
+	}
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Errorf("decoded = %+v, want %+v", decoded, cursor)
+	}
+	for _, invalid := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "MjAyNS0wMS0wNnw"} {
+		if _, err := store.DecodeChatMessageCursor(invalid); err == nil {
+			t.Errorf("DecodeChatMessageCursor(%q) succeeded", invalid)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// ErrInsufficientCoins is returned when a user's balance is too low for a deduction
+var ErrInsufficientCoins = errors.New("insufficient coins")
+
+type CoinStore struct {
+	postgres *db.Postgres
+}
+
+func NewCoinStore(postgres *db.Postgres) *CoinStore {
+	return &CoinStore{
+		postgres: postgres,
+	}
+}
+
+// CoinLog is one entry of a user's coin transaction history
+type CoinLog struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	Amount       int       `json:"amount"` // Positive = earned, negative = spent
+	BalanceAfter int       `json:"balance_after"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AwardCoins adds coins to a user's balance, logs it in coin_logs in the same transaction and returns the new balance.
+// A negative amount deducts coins; the balance is never allowed to go below zero
+func (s *CoinStore) AwardCoins(ctx context.Context, userID string, amount int, reason string) (int, error) {
+	if amount == 0 {
+		return 0, fmt.Errorf("coin amount must not be 0")
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET coins = coins + $1
+		WHERE id = $2 AND coins + $1 >= 0
+		RETURNING coins
+	`
+	var newCoins int
+	err = tx.QueryRowContext(ctx, query, amount, userID).Scan(&newCoins)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Either the user does not exist or the balance is too low
+			var exists bool
+			checkQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+			if checkErr := tx.QueryRowContext(ctx, checkQuery, userID).Scan(&exists); checkErr == nil && !exists {
+				return 0, fmt.Errorf("user not found")
+			}
+			return 0, ErrInsufficientCoins
+		}
+		return 0, fmt.Errorf("failed to update user coins: %w", err)
+	}
+
+	logQuery := `
+		INSERT INTO coin_logs (user_id, amount, balance_after, reason)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err = tx.ExecContext(ctx, logQuery, userID, amount, newCoins, reason); err != nil {
+		return 0, fmt.Errorf("failed to log coin change: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return newCoins, nil
+}
+
+// GetBalance retrieves the current coin balance for a user
+func (s *CoinStore) GetBalance(ctx context.Context, userID string) (int, error) {
+	query := `SELECT coins FROM users WHERE id = $1`
+
+	var coins int
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&coins)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		return 0, fmt.Errorf("failed to get user coins: %w", err)
+	}
+
+	return coins, nil
+}
+
+// Deduct removes amount coins from a user's balance and logs it in coin_logs in the same transaction.
+// Returns ErrInsufficientCoins if the balance is lower than amount.
+func (s *CoinStore) Deduct(ctx context.Context, userID string, amount int, reason string) error {
+	if amount <= 0 {
+		return fmt.Errorf("coin amount must be greater than 0")
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = deductCoinsTx(ctx, tx, userID, amount, reason); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// deductCoinsTx removes amount coins from the user's balance inside tx, logs it in coin_logs and returns the new balance.
+// Returns ErrInsufficientCoins if the balance is lower than amount.
+func deductCoinsTx(ctx context.Context, tx *sql.Tx, userID string, amount int, reason string) (int, error) {
+	query := `
+		UPDATE users
+		SET coins = coins - $1
+		WHERE id = $2 AND coins >= $1
+		RETURNING coins
+	`
+	var balanceAfter int
+	err := tx.QueryRowContext(ctx, query, amount, userID).Scan(&balanceAfter)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrInsufficientCoins
+		}
+		return 0, fmt.Errorf("failed to deduct coins: %w", err)
+	}
+
+	logQuery := `
+		INSERT INTO coin_logs (user_id, amount, balance_after, reason)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err = tx.ExecContext(ctx, logQuery, userID, -amount, balanceAfter, reason); err != nil {
+		return 0, fmt.Errorf("failed to log coin deduction: %w", err)
+	}
+	return balanceAfter, nil
+}
+
+// GetCoinHistory returns a page of the user's coin transactions, newest first, and the total number of entries
+func (s *CoinStore) GetCoinHistory(ctx context.Context, userID string, page, pageSize int) ([]CoinLog, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM coin_logs WHERE user_id = $1`
+	if err := s.postgres.DB.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count coin logs: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, amount, balance_after, reason, created_at
+		FROM coin_logs
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get coin logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []CoinLog{}
+	for rows.Next() {
+		var entry CoinLog
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.Amount, &entry.BalanceAfter, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan coin log: %w", err)
+		}
+		logs = append(logs, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating coin logs: %w", err)
+	}
+
+	return logs, total, nil
+}
+
+// CoinExchange is the result of ExchangeCoinsForXP
+type CoinExchange struct {
+	CoinsSpent int
+	NewCoins   int // Balance after the exchange
+	XPLog      *XPLog
+}
+
+// ExchangeCoinsForXP spends coins of the user's balance for xpPerCoin XP each (source user_add, reason coins_exchange).
+// The debit and the XP award commit together, so the user never loses coins without getting the XP.
+// When the daily user_add cap leaves room for fewer coins' worth of XP, only those coins are spent;
+// ErrDailyCapExceeded is returned if not even one fits. Returns ErrInsufficientCoins if the balance is lower than coins.
+func (s *CoinStore) ExchangeCoinsForXP(ctx context.Context, userID string, coins, xpPerCoin int) (*CoinExchange, error) {
+	if coins <= 0 {
+		return nil, fmt.Errorf("coin amount must be greater than 0")
+	}
+	if xpPerCoin <= 0 {
+		return nil, fmt.Errorf("XP per coin must be greater than 0")
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Shrink the exchange to whole coins within what is left of the daily cap, before anything is debited
+	req := AwardXPRequest{
+		UserID: userID,
+		XP:     coins * xpPerCoin,
+		Source: XPSourceUserAdd,
+		Reason: "coins_exchange",
+	}
+	if xpCap, capped := dailyXPCap(req.Source); capped {
+		xp, err := applyDailyXPCap(ctx, tx, req, xpCap)
+		if err != nil {
+			return nil, err
+		}
+		coins = min(coins, xp/xpPerCoin)
+		if coins == 0 {
+			return nil, ErrDailyCapExceeded
+		}
+		req.XP = coins * xpPerCoin
+	}
+
+	newCoins, err := deductCoinsTx(ctx, tx, userID, coins, "coins_exchange")
+	if err != nil {
+		return nil, err
+	}
+
+	xpLog, err := awardXPTx(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Badge awarding is not critical, as in AwardXP
+	_ = NewBadgeStore(s.postgres).CheckAndAwardBadges(ctx, userID, xpLog.NewXP, xpLog.NewLevel)
+
+	return &CoinExchange{CoinsSpent: coins, NewCoins: newCoins, XPLog: xpLog}, nil
+}
@@ -0,0 +1,179 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+// expectCoinDebit expects coins to be taken from TestUserID's balance of 10
+func expectCoinDebit(mockDB *testutil.MockPostgres, coins int) {
+	mockDB.ExpectQuery(`UPDATE users\s+SET coins = coins - \$1\s+WHERE id = \$2 AND coins >= \$1`).
+		WithArgs(coins, testutil.TestUserID).
+		WillReturnRows([]string{"coins"}, []any{int64(10 - coins)})
+	mockDB.ExpectExec(`INSERT INTO coin_logs`).
+		WithArgs(testutil.TestUserID, -coins, 10-coins, "coins_exchange").
+		WillReturnResult(1)
+}
+
+// expectExchangeAward expects xp to be awarded to TestUserID, who had none, and the badges checked after the commit
+func expectExchangeAward(mockDB *testutil.MockPostgres, xp int) {
+	mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+		WithArgs(xp, testutil.TestUserID).
+		WillReturnRows([]string{"xp", "level"}, []any{int64(xp), int64(1)})
+	mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+		WithArgs(testutil.AnyArg(), testutil.TestUserID, "user_add", nil, "coins_exchange", xp).
+		WillReturnRows([]string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"},
+			[]any{"log-1", testutil.TestUserID, "user_add", nil, "coins_exchange", int64(xp), testutil.TestTime})
+	mockDB.ExpectQuery(`SELECT level FROM levels`).
+		WithArgs(xp).
+		WillReturnRows([]string{"level"}, []any{int64(1)})
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(`FROM badges b`).
+		WithArgs(xp, 1, testutil.TestUserID).
+		WillReturnRows([]string{"id", "xp", "required_level"})
+}
+
+func TestCoinStoreExchangeCoinsForXP(t *testing.T) {
+	tests := []struct {
+		name      string
+		coins     int
+		expect    func(mockDB *testutil.MockPostgres)
+		wantErr   string
+		wantIs    error
+		wantSpent int
+		wantCoins int
+		wantXP    int
+	}{
+		{
+			name:  "exchanges at rate",
+			coins: 4,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				expectCoinDebit(mockDB, 4)
+				expectExchangeAward(mockDB, 40)
+			},
+			wantSpent: 4,
+			wantCoins: 6,
+			wantXP:    40,
+		},
+		{
+			name:  "insufficient coins",
+			coins: 11,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`UPDATE users\s+SET coins = coins - \$1`).
+					WithArgs(11, testutil.TestUserID).
+					WillReturnRows([]string{"coins"})
+				mockDB.ExpectRollback()
+			},
+			wantErr: "insufficient coins",
+			wantIs:  store.ErrInsufficientCoins,
+		},
+		{
+			// The debit is rolled back with the failed award, so no coins are lost
+			name:  "award failure",
+			coins: 4,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				expectCoinDebit(mockDB, 4)
+				mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1`).
+					WithArgs(40, testutil.TestUserID).
+					WillReturnError(errors.New("connection refused"))
+				mockDB.ExpectRollback()
+			},
+			wantErr: "failed to update user XP: connection refused",
+		},
+		{name: "no coins", expect: func(*testutil.MockPostgres) {}, wantErr: "coin amount must be greater than 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			tt.expect(mockDB)
+
+			exchange, err := store.NewCoinStore(postgres).ExchangeCoinsForXP(context.Background(), testutil.TestUserID, tt.coins, 10)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+					t.Errorf("err = %v, want it to wrap %v", err, tt.wantIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExchangeCoinsForXP: %v", err)
+			}
+			if exchange.CoinsSpent != tt.wantSpent || exchange.NewCoins != tt.wantCoins || exchange.XPLog.XP != tt.wantXP {
+				t.Errorf("exchange = %d coins spent, %d left, %d XP; want %d, %d, %d",
+					exchange.CoinsSpent, exchange.NewCoins, exchange.XPLog.XP, tt.wantSpent, tt.wantCoins, tt.wantXP)
+			}
+		})
+	}
+}
+
+// expectUserAddEarnedToday expects the daily cap check to find earned XP from user_add today
+func expectUserAddEarnedToday(mockDB *testutil.MockPostgres, earned int) {
+	mockDB.ExpectQuery(`SELECT id FROM users WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"id"}, []any{testutil.TestUserID})
+	mockDB.ExpectQuery(`SELECT COALESCE\(SUM\(xp\), 0\) FROM xp_logs`).
+		WithArgs(testutil.TestUserID, "user_add").
+		WillReturnRows([]string{"sum"}, []any{int64(earned)})
+}
+
+// With XP_CAP_USER_ADD below the exchange, only the coins whose XP fits under the cap are spent
+func TestCoinStoreExchangeCoinsForXPDailyCap(t *testing.T) {
+	tests := []struct {
+		name      string
+		earned    int
+		expect    func(mockDB *testutil.MockPostgres)
+		wantSpent int
+		wantXP    int
+	}{
+		{
+			name:   "shrinks to the cap",
+			earned: 0,
+			expect: func(mockDB *testutil.MockPostgres) {
+				expectCoinDebit(mockDB, 2)
+				expectUserAddEarnedToday(mockDB, 0)
+				expectExchangeAward(mockDB, 20)
+			},
+			wantSpent: 2,
+			wantXP:    20,
+		},
+		{name: "cap reached", earned: 25, expect: func(mockDB *testutil.MockPostgres) { mockDB.ExpectRollback() }},
+		{name: "less than a coin left", earned: 20, expect: func(mockDB *testutil.MockPostgres) { mockDB.ExpectRollback() }},
+	}
+
+	store.SetDailyXPCaps(map[store.XPSource]int{store.XPSourceUserAdd: 25})
+	t.Cleanup(func() { store.SetDailyXPCaps(nil) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectBegin()
+			expectUserAddEarnedToday(mockDB, tt.earned)
+			tt.expect(mockDB)
+
+			exchange, err := store.NewCoinStore(postgres).ExchangeCoinsForXP(context.Background(), testutil.TestUserID, 4, 10)
+			if tt.wantSpent == 0 {
+				// Nothing is debited when no coin fits under the cap
+				if !errors.Is(err, store.ErrDailyCapExceeded) {
+					t.Fatalf("err = %v, want %v", err, store.ErrDailyCapExceeded)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExchangeCoinsForXP: %v", err)
+			}
+			if exchange.CoinsSpent != tt.wantSpent || exchange.XPLog.XP != tt.wantXP {
+				t.Errorf("exchange = %d coins for %d XP, want %d for %d", exchange.CoinsSpent, exchange.XPLog.XP, tt.wantSpent, tt.wantXP)
+			}
+		})
+	}
+}
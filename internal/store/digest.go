@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// DigestRecipient is a user who has opted in to the daily notification digest
+type DigestRecipient struct {
+	UserID       string
+	Name         string
+	Email        string
+	DigestSentAt *time.Time // nil if no digest has been sent yet
+}
+
+// NotificationDigest groups a user's notifications for the digest email
+type NotificationDigest struct {
+	UserName      string
+	Since         time.Time
+	TaskApprovals []Notification
+	NewFollowers  []Notification
+	BadgesEarned  []Notification
+	Other         []Notification
+	Total         int
+}
+
+// NewNotificationDigest groups notifications by type
+func NewNotificationDigest(userName string, since time.Time, notifications []Notification) *NotificationDigest {
+	digest := &NotificationDigest{
+		UserName: userName,
+		Since:    since,
+		Total:    len(notifications),
+	}
+	for _, n := range notifications {
+		switch n.Type {
+		case "task_approved":
+			digest.TaskApprovals = append(digest.TaskApprovals, n)
+		case "new_follower":
+			digest.NewFollowers = append(digest.NewFollowers, n)
+		case "badge_earned":
+			digest.BadgesEarned = append(digest.BadgesEarned, n)
+		default:
+			digest.Other = append(digest.Other, n)
+		}
+	}
+	return digest
+}
+
+type DigestStore struct {
+	postgres *db.Postgres
+}
+
+func NewDigestStore(postgres *db.Postgres) *DigestStore {
+	return &DigestStore{
+		postgres: postgres,
+	}
+}
+
+// GetUnsentSince retrieves a user's stored notifications created after since, oldest first
+func (s *DigestStore) GetUnsentSince(ctx context.Context, userID string, since time.Time) ([]Notification, error) {
+	query := `
+		SELECT id, user_id, title, body, type, is_read, created_at
+		FROM notifications
+		WHERE user_id = $1 AND created_at > $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Title, &n.Body, &n.Type, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification rows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// GetDigestRecipients retrieves every user with the digest enabled
+func (s *DigestStore) GetDigestRecipients(ctx context.Context) ([]DigestRecipient, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.digest_sent_at
+		FROM notification_preferences np
+		INNER JOIN users u ON u.id = np.user_id
+		WHERE np.digest_enabled = TRUE
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []DigestRecipient
+	for rows.Next() {
+		var r DigestRecipient
+		var sentAt sql.NullTime
+		if err := rows.Scan(&r.UserID, &r.Name, &r.Email, &sentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest recipient: %w", err)
+		}
+		if sentAt.Valid {
+			r.DigestSentAt = &sentAt.Time
+		}
+		recipients = append(recipients, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest recipient rows: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// MarkDigestSent records that a user's digest covers notifications up to sentAt
+func (s *DigestStore) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	query := `UPDATE users SET digest_sent_at = $1 WHERE id = $2`
+	if _, err := s.postgres.DB.ExecContext(ctx, query, sentAt, userID); err != nil {
+		return fmt.Errorf("failed to update digest_sent_at: %w", err)
+	}
+	return nil
+}
+
+// SetDigestEnabled turns the daily digest on or off for a user
+func (s *DigestStore) SetDigestEnabled(ctx context.Context, userID string, enabled bool) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, digest_enabled, updated_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE SET digest_enabled = EXCLUDED.digest_enabled, updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := s.postgres.DB.ExecContext(ctx, query, userID, enabled); err != nil {
+		return fmt.Errorf("failed to update digest preference: %w", err)
+	}
+	return nil
+}
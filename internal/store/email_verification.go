@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// EmailVerificationTokenTTL is how long an emailed verification link stays valid
+const EmailVerificationTokenTTL = 24 * time.Hour
+
+type EmailVerificationStore struct {
+	postgres *db.Postgres
+}
+
+func NewEmailVerificationStore(postgres *db.Postgres) *EmailVerificationStore {
+	return &EmailVerificationStore{
+		postgres: postgres,
+	}
+}
+
+// hashVerificationToken returns the hex SHA-256 of a token; only the hash is stored
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken generates a new 64-character hex verification token for the user and stores its hash.
+// Earlier tokens of the user are replaced so only the latest emailed link works.
+func (s *EmailVerificationStore) CreateToken(ctx context.Context, userID string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1`, userID); err != nil {
+		return "", fmt.Errorf("failed to delete old verification tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	expiresAt := time.Now().Add(EmailVerificationTokenTTL)
+	if _, err := tx.ExecContext(ctx, query, userID, hashVerificationToken(token), expiresAt); err != nil {
+		return "", fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return token, nil
+}
+
+// VerifyToken marks the token owner's email as verified and consumes the token.
+// It returns the user ID, or "invalid token" when the token is unknown or expired.
+func (s *EmailVerificationStore) VerifyToken(ctx context.Context, token string) (string, error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID string
+	query := `
+		DELETE FROM email_verification_tokens
+		WHERE token_hash = $1 AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err = tx.QueryRowContext(ctx, query, hashVerificationToken(token)).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("invalid token")
+		}
+		return "", fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	updateQuery := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, updateQuery, userID); err != nil {
+		return "", fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return userID, nil
+}
+
+// IsEmailVerified reports whether the user has confirmed their email address
+func (s *EmailVerificationStore) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	var verified bool
+	query := `SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&verified)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("user not found")
+		}
+		return false, fmt.Errorf("failed to check email verification: %w", err)
+	}
+	return verified, nil
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// featureFlagCacheTTL is how long flag configs are served from memory before being reloaded
+const featureFlagCacheTTL = 30 * time.Second
+
+// FeatureFlag gates a feature for all, some, or specific users
+type FeatureFlag struct {
+	FlagName          string    `json:"flag_name"`
+	Enabled           bool      `json:"enabled"`
+	RolloutPercentage int       `json:"rollout_percentage"` // 0-100
+	EnabledUserIDs    []string  `json:"enabled_user_ids"`   // Always enabled for these users while the flag is enabled
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// UpdateFeatureFlagRequest is the new configuration of a feature flag
+type UpdateFeatureFlagRequest struct {
+	Enabled           bool     `json:"enabled"`
+	RolloutPercentage int      `json:"rollout_percentage"`
+	EnabledUserIDs    []string `json:"enabled_user_ids"`
+}
+
+// featureFlagCache holds every flag in memory, shared by all FeatureFlagStores in the process
+var featureFlagCache struct {
+	mu       sync.RWMutex
+	flags    map[string]FeatureFlag
+	loadedAt time.Time
+}
+
+type FeatureFlagStore struct {
+	postgres *db.Postgres
+}
+
+func NewFeatureFlagStore(postgres *db.Postgres) *FeatureFlagStore {
+	return &FeatureFlagStore{
+		postgres: postgres,
+	}
+}
+
+// IsEnabled reports whether flagName is on for userID. Unknown flags are off.
+// Users in a partial rollout are bucketed by a hash of userID+flagName, so each user
+// consistently lands on the same side of the rollout for a given flag.
+func (s *FeatureFlagStore) IsEnabled(ctx context.Context, flagName, userID string) (bool, error) {
+	flags, err := s.cachedFlags(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	flag, ok := flags[flagName]
+	if !ok || !flag.Enabled {
+		return false, nil
+	}
+	for _, id := range flag.EnabledUserIDs {
+		if id == userID {
+			return true, nil
+		}
+	}
+	if flag.RolloutPercentage >= 100 {
+		return true, nil
+	}
+	if flag.RolloutPercentage <= 0 || userID == "" {
+		return false, nil
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(userID + flagName))
+	return int(h.Sum32()%100) < flag.RolloutPercentage, nil
+}
+
+// cachedFlags returns all flags, reloading them from the database when the cache is stale
+func (s *FeatureFlagStore) cachedFlags(ctx context.Context) (map[string]FeatureFlag, error) {
+	featureFlagCache.mu.RLock()
+	flags, loadedAt := featureFlagCache.flags, featureFlagCache.loadedAt
+	featureFlagCache.mu.RUnlock()
+	if flags != nil && time.Since(loadedAt) < featureFlagCacheTTL {
+		return flags, nil
+	}
+
+	list, err := s.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flags = make(map[string]FeatureFlag, len(list))
+	for _, flag := range list {
+		flags[flag.FlagName] = flag
+	}
+
+	featureFlagCache.mu.Lock()
+	featureFlagCache.flags = flags
+	featureFlagCache.loadedAt = time.Now()
+	featureFlagCache.mu.Unlock()
+
+	return flags, nil
+}
+
+// invalidateFeatureFlagCache forces the next IsEnabled call to reload flags
+func invalidateFeatureFlagCache() {
+	featureFlagCache.mu.Lock()
+	featureFlagCache.flags = nil
+	featureFlagCache.mu.Unlock()
+}
+
+// GetFeatureFlags returns every feature flag ordered by name, bypassing the cache
+func (s *FeatureFlagStore) GetFeatureFlags(ctx context.Context) ([]FeatureFlag, error) {
+	query := `
+		SELECT flag_name, enabled, rollout_percentage, enabled_user_ids, updated_at
+		FROM feature_flags
+		ORDER BY flag_name ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature flags: %w", err)
+	}
+	defer rows.Close()
+
+	typeMap := pgtype.NewMap()
+	flags := []FeatureFlag{}
+	for rows.Next() {
+		var flag FeatureFlag
+		if err := rows.Scan(
+			&flag.FlagName, &flag.Enabled, &flag.RolloutPercentage,
+			typeMap.SQLScanner(&flag.EnabledUserIDs), &flag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan feature flag: %w", err)
+		}
+		if flag.EnabledUserIDs == nil {
+			flag.EnabledUserIDs = []string{}
+		}
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating feature flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// UpsertFeatureFlag creates or replaces the configuration of a feature flag
+func (s *FeatureFlagStore) UpsertFeatureFlag(ctx context.Context, flagName string, req UpdateFeatureFlagRequest) (*FeatureFlag, error) {
+	if flagName == "" {
+		return nil, fmt.Errorf("flag name is required")
+	}
+	if req.RolloutPercentage < 0 || req.RolloutPercentage > 100 {
+		return nil, fmt.Errorf("rollout_percentage must be between 0 and 100")
+	}
+	if req.EnabledUserIDs == nil {
+		req.EnabledUserIDs = []string{}
+	}
+
+	query := `
+		INSERT INTO feature_flags (flag_name, enabled, rollout_percentage, enabled_user_ids, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (flag_name) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			rollout_percentage = EXCLUDED.rollout_percentage,
+			enabled_user_ids = EXCLUDED.enabled_user_ids,
+			updated_at = NOW()
+		RETURNING flag_name, enabled, rollout_percentage, enabled_user_ids, updated_at
+	`
+
+	var flag FeatureFlag
+	err := s.postgres.DB.QueryRowContext(ctx, query, flagName, req.Enabled, req.RolloutPercentage, req.EnabledUserIDs).Scan(
+		&flag.FlagName, &flag.Enabled, &flag.RolloutPercentage,
+		pgtype.NewMap().SQLScanner(&flag.EnabledUserIDs), &flag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	if flag.EnabledUserIDs == nil {
+		flag.EnabledUserIDs = []string{}
+	}
+
+	invalidateFeatureFlagCache()
+	return &flag, nil
+}
@@ -3,11 +3,14 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/moderation"
 )
 
 type FeedItem struct {
@@ -65,16 +68,48 @@ const (
 
 // GetFeedOptions represents options for getting feed
 type GetFeedOptions struct {
-	FeedType FeedType // pan-india, state, college
-	UserID   string   // Current user ID (for filtering by state/college and checking reactions)
-	Page     int      // Page number (1-based)
-	PageSize int      // Items per page
+	FeedType FeedType    // pan-india, state, college
+	UserID   string      // Current user ID (for filtering by state/college and checking reactions)
+	Page     int         // Page number (1-based), ignored when Cursor is set
+	PageSize int         // Items per page
+	Cursor   *FeedCursor // Last item of the previous page; replaces Page when set
 }
 
-// GetFeed retrieves feed items with pagination
+// FeedCursor identifies the last feed item seen when paging by (created_at, id) descending
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns an opaque cursor string for clients
+func (c FeedCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeFeedCursor parses a cursor string produced by FeedCursor.Encode
+func DecodeFeedCursor(cursor string) (*FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &FeedCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// GetFeed retrieves feed items, newest first (created_at DESC, id DESC).
+// With a cursor, only items strictly after the cursor are returned, so items added between
+// requests don't shift pages. Without a cursor, the page offset is applied. total counts the whole feed.
 func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedItem, int, error) {
 	offset := (opts.Page - 1) * opts.PageSize
-	if offset < 0 {
+	if offset < 0 || opts.Cursor != nil {
 		offset = 0
 	}
 	if opts.PageSize <= 0 {
@@ -96,6 +131,7 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 		INNER JOIN users u ON ctf.user_id = u.id
 		WHERE s.status = 'approved'
 		AND (t.proof_type = 'image' OR t.proof_type = 'video')
+		AND ctf.visibility = 'public'
 	`
 
 	// Add filtering based on feed type
@@ -104,7 +140,7 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 		// Get user's state_id
 		var stateID sql.NullString
 		userQuery := `SELECT state_id FROM users WHERE id = $1`
-		err := s.postgres.DB.QueryRowContext(ctx, userQuery, opts.UserID).Scan(&stateID)
+		err := s.postgres.Traced.QueryRowContext(ctx, userQuery, opts.UserID).Scan(&stateID)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to get user state: %w", err)
 		}
@@ -117,7 +153,7 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 		// Get user's college_id
 		var collegeID sql.NullString
 		userQuery := `SELECT college_id FROM users WHERE id = $1`
-		err := s.postgres.DB.QueryRowContext(ctx, userQuery, opts.UserID).Scan(&collegeID)
+		err := s.postgres.Traced.QueryRowContext(ctx, userQuery, opts.UserID).Scan(&collegeID)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to get user college: %w", err)
 		}
@@ -129,10 +165,18 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 		// FeedTypePanIndia - no additional filtering needed
 	}
 
+	// Hide items from users the current user has blocked or who have blocked the current user
+	if opts.UserID != "" {
+		baseQuery += fmt.Sprintf(` AND NOT EXISTS (SELECT 1 FROM user_blocks ub
+			WHERE (ub.blocker_id = ctf.user_id AND ub.blocked_id = $%[1]d) OR (ub.blocker_id = $%[1]d AND ub.blocked_id = ctf.user_id))`, argIndex)
+		args = append(args, opts.UserID)
+		argIndex++
+	}
+
 	// Count total items
 	countQuery := `SELECT COUNT(*) ` + baseQuery
 	var total int
-	err := s.postgres.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	err := s.postgres.Traced.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count feed items: %w", err)
 	}
@@ -149,26 +193,24 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 			t.title as task_title,
 			t.xp as task_xp,
 			s.proof_url,
-			COALESCE(reaction_counts.count, 0) as reaction_count,
-			COALESCE(comment_counts.count, 0) as comment_count,
+			(SELECT COUNT(*) FROM task_feed_reactions r WHERE r.feed_id = ctf.id) as reaction_count,
+			(SELECT COUNT(*) FROM task_feed_comments c WHERE c.feed_id = ctf.id) as comment_count,
 			ctf.created_at
-		` + baseQuery + `
-		LEFT JOIN (
-			SELECT feed_id, COUNT(*) as count
-			FROM task_feed_reactions
-			GROUP BY feed_id
-		) reaction_counts ON ctf.id = reaction_counts.feed_id
-		LEFT JOIN (
-			SELECT feed_id, COUNT(*) as count
-			FROM task_feed_comments
-			GROUP BY feed_id
-		) comment_counts ON ctf.id = comment_counts.feed_id
-		ORDER BY ctf.created_at DESC
+		` + baseQuery
+
+	// The cursor only narrows the page, not the total count above
+	if opts.Cursor != nil {
+		selectQuery += fmt.Sprintf(" AND (ctf.created_at, ctf.id) < ($%d::timestamp, $%d::uuid)", argIndex, argIndex+1)
+		args = append(args, opts.Cursor.CreatedAt, opts.Cursor.ID)
+		argIndex += 2
+	}
+	selectQuery += `
+		ORDER BY ctf.created_at DESC, ctf.id DESC
 		LIMIT $` + fmt.Sprintf("%d", argIndex) + ` OFFSET $` + fmt.Sprintf("%d", argIndex+1)
 
 	args = append(args, opts.PageSize, offset)
 
-	rows, err := s.postgres.DB.QueryContext(ctx, selectQuery, args...)
+	rows, err := s.postgres.Traced.QueryContext(ctx, selectQuery, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query feed: %w", err)
 	}
@@ -196,14 +238,14 @@ func (s *FeedStore) GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedIte
 		if opts.UserID != "" {
 			var reacted bool
 			reactionQuery := `SELECT EXISTS(SELECT 1 FROM task_feed_reactions WHERE feed_id = $1 AND user_id = $2)`
-			err := s.postgres.DB.QueryRowContext(ctx, reactionQuery, item.ID, opts.UserID).Scan(&reacted)
+			err := s.postgres.Traced.QueryRowContext(ctx, reactionQuery, item.ID, opts.UserID).Scan(&reacted)
 			if err == nil {
 				item.UserReacted = reacted
 			}
 		}
 
 		// Fetch comments for this feed item (limit to 50 most recent)
-		comments, err := s.GetComments(ctx, item.ID, 50)
+		comments, err := s.GetComments(ctx, item.ID, 50, 0)
 		if err == nil {
 			item.Comments = comments
 		} else {
@@ -242,9 +284,10 @@ func (s *FeedStore) GetUserFeed(ctx context.Context, userID string, page, pageSi
 		INNER JOIN tasks t ON ctf.task_id = t.id
 		WHERE ctf.user_id = $1 AND s.status = 'approved'
 		AND (t.proof_type = 'image' OR t.proof_type = 'video')
+		AND ctf.visibility = 'public'
 	`
 	var total int
-	err := s.postgres.DB.QueryRowContext(ctx, countQuery, userID).Scan(&total)
+	err := s.postgres.Traced.QueryRowContext(ctx, countQuery, userID).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count user feed items: %w", err)
 	}
@@ -280,11 +323,12 @@ func (s *FeedStore) GetUserFeed(ctx context.Context, userID string, page, pageSi
 		) comment_counts ON ctf.id = comment_counts.feed_id
 		WHERE ctf.user_id = $1 AND s.status = 'approved'
 		AND (t.proof_type = 'image' OR t.proof_type = 'video')
+		AND ctf.visibility = 'public'
 		ORDER BY ctf.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, pageSize, offset)
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, userID, pageSize, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query user feed: %w", err)
 	}
@@ -309,7 +353,7 @@ func (s *FeedStore) GetUserFeed(ctx context.Context, userID string, page, pageSi
 		}
 
 		// Fetch comments for this feed item (limit to 50 most recent)
-		comments, err := s.GetComments(ctx, item.ID, 50)
+		comments, err := s.GetComments(ctx, item.ID, 50, 0)
 		if err == nil {
 			item.Comments = comments
 		} else {
@@ -332,7 +376,7 @@ func (s *FeedStore) CreateFeedEntry(ctx context.Context, submissionID, userID, t
 	// Check if feed entry already exists
 	var exists bool
 	checkQuery := `SELECT EXISTS(SELECT 1 FROM completed_task_feed WHERE submission_id = $1)`
-	err := s.postgres.DB.QueryRowContext(ctx, checkQuery, submissionID).Scan(&exists)
+	err := s.postgres.Traced.QueryRowContext(ctx, checkQuery, submissionID).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check existing feed entry: %w", err)
 	}
@@ -347,7 +391,7 @@ func (s *FeedStore) CreateFeedEntry(ctx context.Context, submissionID, userID, t
 		INSERT INTO completed_task_feed (id, submission_id, user_id, task_id, visibility)
 		VALUES ($1, $2, $3, $4, 'public')
 	`
-	_, err = s.postgres.DB.ExecContext(ctx, query, feedID, submissionID, userID, taskID)
+	_, err = s.postgres.Traced.ExecContext(ctx, query, feedID, submissionID, userID, taskID)
 	if err != nil {
 		return fmt.Errorf("failed to create feed entry: %w", err)
 	}
@@ -360,7 +404,7 @@ func (s *FeedStore) AddReaction(ctx context.Context, feedID, userID, reaction st
 	// Check if user already reacted
 	var exists bool
 	checkQuery := `SELECT EXISTS(SELECT 1 FROM task_feed_reactions WHERE feed_id = $1 AND user_id = $2)`
-	err := s.postgres.DB.QueryRowContext(ctx, checkQuery, feedID, userID).Scan(&exists)
+	err := s.postgres.Traced.QueryRowContext(ctx, checkQuery, feedID, userID).Scan(&exists)
 	if err != nil {
 		return fmt.Errorf("failed to check existing reaction: %w", err)
 	}
@@ -368,7 +412,7 @@ func (s *FeedStore) AddReaction(ctx context.Context, feedID, userID, reaction st
 	if exists {
 		// Update existing reaction
 		query := `UPDATE task_feed_reactions SET reaction = $1 WHERE feed_id = $2 AND user_id = $3`
-		_, err = s.postgres.DB.ExecContext(ctx, query, reaction, feedID, userID)
+		_, err = s.postgres.Traced.ExecContext(ctx, query, reaction, feedID, userID)
 		if err != nil {
 			return fmt.Errorf("failed to update reaction: %w", err)
 		}
@@ -377,7 +421,7 @@ func (s *FeedStore) AddReaction(ctx context.Context, feedID, userID, reaction st
 
 	// Create new reaction
 	query := `INSERT INTO task_feed_reactions (feed_id, user_id, reaction) VALUES ($1, $2, $3)`
-	_, err = s.postgres.DB.ExecContext(ctx, query, feedID, userID, reaction)
+	_, err = s.postgres.Traced.ExecContext(ctx, query, feedID, userID, reaction)
 	if err != nil {
 		return fmt.Errorf("failed to add reaction: %w", err)
 	}
@@ -388,7 +432,7 @@ func (s *FeedStore) AddReaction(ctx context.Context, feedID, userID, reaction st
 // RemoveReaction removes a reaction from a feed item
 func (s *FeedStore) RemoveReaction(ctx context.Context, feedID, userID string) error {
 	query := `DELETE FROM task_feed_reactions WHERE feed_id = $1 AND user_id = $2`
-	_, err := s.postgres.DB.ExecContext(ctx, query, feedID, userID)
+	_, err := s.postgres.Traced.ExecContext(ctx, query, feedID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to remove reaction: %w", err)
 	}
@@ -396,7 +440,35 @@ func (s *FeedStore) RemoveReaction(ctx context.Context, feedID, userID string) e
 }
 
 // AddComment adds a comment to a feed item
+// Blocked words are rejected or masked depending on the word filter's comment mode.
+// Returns "comment blocked" if the commenter and the item's owner have blocked each other (either way).
 func (s *FeedStore) AddComment(ctx context.Context, feedID, userID, comment string) (*FeedComment, error) {
+	var blocked bool
+	blockQuery := `
+		SELECT EXISTS(
+			SELECT 1 FROM completed_task_feed ctf
+			JOIN user_blocks ub ON (ub.blocker_id = ctf.user_id AND ub.blocked_id = $2)
+				OR (ub.blocker_id = $2 AND ub.blocked_id = ctf.user_id)
+			WHERE ctf.id = $1
+		)
+	`
+	if err := s.postgres.Traced.QueryRowContext(ctx, blockQuery, feedID, userID).Scan(&blocked); err != nil {
+		return nil, fmt.Errorf("failed to check block relationship: %w", err)
+	}
+	if blocked {
+		return nil, fmt.Errorf("comment blocked")
+	}
+
+	if filter := moderation.GetWordFilter(); filter != nil {
+		cleaned, found := filter.Filter(comment)
+		if len(found) > 0 {
+			if filter.CommentMode == moderation.FilterModeReject {
+				return nil, fmt.Errorf("comment contains blocked words")
+			}
+			comment = cleaned
+		}
+	}
+
 	commentID := uuid.New().String()
 	query := `
 		INSERT INTO task_feed_comments (id, feed_id, user_id, comment)
@@ -405,7 +477,7 @@ func (s *FeedStore) AddComment(ctx context.Context, feedID, userID, comment stri
 	`
 
 	var feedComment FeedComment
-	err := s.postgres.DB.QueryRowContext(ctx, query, commentID, feedID, userID, comment).Scan(
+	err := s.postgres.Traced.QueryRowContext(ctx, query, commentID, feedID, userID, comment).Scan(
 		&feedComment.ID, &feedComment.FeedID, &feedComment.UserID, &feedComment.Comment, &feedComment.CreatedAt,
 	)
 	if err != nil {
@@ -416,7 +488,7 @@ func (s *FeedStore) AddComment(ctx context.Context, feedID, userID, comment stri
 	userQuery := `SELECT name, avatar_url FROM users WHERE id = $1`
 	var userName string
 	var userAvatar sql.NullString
-	err = s.postgres.DB.QueryRowContext(ctx, userQuery, userID).Scan(&userName, &userAvatar)
+	err = s.postgres.Traced.QueryRowContext(ctx, userQuery, userID).Scan(&userName, &userAvatar)
 	if err == nil {
 		feedComment.UserName = userName
 		if userAvatar.Valid {
@@ -428,13 +500,16 @@ func (s *FeedStore) AddComment(ctx context.Context, feedID, userID, comment stri
 }
 
 // GetComments retrieves comments for a feed item
-func (s *FeedStore) GetComments(ctx context.Context, feedID string, limit int) ([]FeedComment, error) {
+func (s *FeedStore) GetComments(ctx context.Context, feedID string, limit, offset int) ([]FeedComment, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 	if limit > 200 {
 		limit = 200
 	}
+	if offset < 0 {
+		offset = 0
+	}
 
 	query := `
 		SELECT 
@@ -449,10 +524,10 @@ func (s *FeedStore) GetComments(ctx context.Context, feedID string, limit int) (
 		INNER JOIN users u ON tfc.user_id = u.id
 		WHERE tfc.feed_id = $1
 		ORDER BY tfc.created_at ASC
-		LIMIT $2
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := s.postgres.DB.QueryContext(ctx, query, feedID, limit)
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, feedID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query comments: %w", err)
 	}
@@ -484,3 +559,46 @@ func (s *FeedStore) GetComments(ctx context.Context, feedID string, limit int) (
 
 	return comments, nil
 }
+
+// CountComments returns the number of comments on a feed item
+func (s *FeedStore) CountComments(ctx context.Context, feedID string) (int, error) {
+	var total int
+	query := `SELECT COUNT(*) FROM task_feed_comments WHERE feed_id = $1`
+	err := s.postgres.Traced.QueryRowContext(ctx, query, feedID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+	return total, nil
+}
+
+// FeedEntryExists reports whether a publicly visible feed item exists
+func (s *FeedStore) FeedEntryExists(ctx context.Context, feedID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM completed_task_feed WHERE id = $1 AND visibility = 'public')`
+	err := s.postgres.Traced.QueryRowContext(ctx, query, feedID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check feed entry: %w", err)
+	}
+	return exists, nil
+}
+
+// HideFeedEntry hides a user's own feed item from all feeds
+func (s *FeedStore) HideFeedEntry(ctx context.Context, feedID, userID string) error {
+	var ownerID string
+	err := s.postgres.Traced.QueryRowContext(ctx, `SELECT user_id FROM completed_task_feed WHERE id = $1`, feedID).Scan(&ownerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("feed item not found")
+		}
+		return fmt.Errorf("failed to get feed item: %w", err)
+	}
+	if ownerID != userID {
+		return fmt.Errorf("not the owner of this feed item")
+	}
+
+	_, err = s.postgres.Traced.ExecContext(ctx, `UPDATE completed_task_feed SET visibility = 'hidden' WHERE id = $1`, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to hide feed item: %w", err)
+	}
+	return nil
+}
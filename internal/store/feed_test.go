@@ -0,0 +1,162 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/moderation"
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestFeedStoreGetFeedCursor(t *testing.T) {
+	feedColumns := []string{"id", "submission_id", "user_id", "task_id", "user_name", "user_avatar", "task_title", "task_xp", "proof_url", "reaction_count", "comment_count", "created_at"}
+	cursor := &store.FeedCursor{CreatedAt: testutil.TestTime, ID: testutil.TestFeedID}
+	tests := []struct {
+		name      string
+		opts      store.GetFeedOptions
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "page offset",
+			opts:      store.GetFeedOptions{FeedType: store.FeedTypePanIndia, Page: 3, PageSize: 10},
+			wantQuery: `ctf.visibility\s+=\s+'public'\s+ORDER\s+BY\s+ctf.created_at\s+DESC,\s+ctf.id\s+DESC\s+LIMIT\s+\$1\s+OFFSET\s+\$2`,
+			wantArgs:  []any{10, 20},
+		},
+		{
+			name:      "cursor replaces the page",
+			opts:      store.GetFeedOptions{FeedType: store.FeedTypePanIndia, Page: 3, PageSize: 10, Cursor: cursor},
+			wantQuery: `AND\s+\(ctf.created_at,\s+ctf.id\)\s+<\s+\(\$1::timestamp,\s+\$2::uuid\)\s+ORDER\s+BY\s+ctf.created_at\s+DESC,\s+ctf.id\s+DESC\s+LIMIT\s+\$3\s+OFFSET\s+\$4`,
+			wantArgs:  []any{cursor.CreatedAt, cursor.ID, 10, 0},
+		},
+		{
+			name:      "cursor follows the block filter",
+			opts:      store.GetFeedOptions{FeedType: store.FeedTypePanIndia, UserID: testutil.TestUserID, PageSize: 10, Cursor: cursor},
+			wantQuery: `ub.blocked_id\s+=\s+ctf.user_id\)\)\s+AND\s+\(ctf.created_at,\s+ctf.id\)\s+<\s+\(\$2::timestamp,\s+\$3::uuid\)\s+ORDER\s+BY\s+ctf.created_at\s+DESC,\s+ctf.id\s+DESC\s+LIMIT\s+\$4\s+OFFSET\s+\$5`,
+			wantArgs:  []any{testutil.TestUserID, cursor.CreatedAt, cursor.ID, 10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			// total counts the whole feed, so the cursor stays out of the count
+			countArgs := tt.wantArgs[:len(tt.wantArgs)-2]
+			if tt.opts.Cursor != nil {
+				countArgs = countArgs[:len(countArgs)-2]
+			}
+			mockDB.ExpectQuery(`SELECT\s+COUNT\(\*\)\s+FROM\s+completed_task_feed\s+ctf`).
+				WithArgs(countArgs...).
+				WillReturnRows([]string{"count"}, []any{int64(25)})
+			// An empty page: the mock's single connection can't serve the per-item comment queries
+			mockDB.ExpectQuery(tt.wantQuery).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows(feedColumns)
+
+			items, total, err := store.NewFeedStore(postgres).GetFeed(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("GetFeed: %v", err)
+			}
+			if total != 25 || len(items) != 0 {
+				t.Errorf("got %d items of %d, want 0 of 25", len(items), total)
+			}
+		})
+	}
+}
+
+func TestFeedCursorEncode(t *testing.T) {
+	want := store.FeedCursor{CreatedAt: time.Date(2025, time.January, 6, 12, 0, 0, 123456789, time.UTC), ID: testutil.TestFeedID}
+	got, err := store.DecodeFeedCursor(want.Encode())
+	if err != nil {
+		t.Fatalf("DecodeFeedCursor: %v", err)
+	}
+	if got.ID != want.ID || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+
+	for _, cursor := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "MjAyNS0wMS0wNlQxMjowMDowMFp8"} {
+		if _, err := store.DecodeFeedCursor(cursor); err == nil {
+			t.Errorf("DecodeFeedCursor(%q) succeeded, want an error", cursor)
+		}
+	}
+}
+
+func TestFeedStoreGetFeedHidesBlockedUsers(t *testing.T) {
+	feedColumns := []string{"id", "submission_id", "user_id", "task_id", "user_name", "user_avatar", "task_title", "task_xp", "proof_url", "reaction_count", "comment_count", "created_at"}
+	// Items are hidden whichever of the two users created the block
+	blockFilter := `AND\s+NOT\s+EXISTS\s+\(SELECT\s+1\s+FROM\s+user_blocks\s+ub\s+WHERE\s+\(ub.blocker_id\s+=\s+ctf.user_id\s+AND\s+ub.blocked_id\s+=\s+\$1\)\s+OR\s+\(ub.blocker_id\s+=\s+\$1\s+AND\s+ub.blocked_id\s+=\s+ctf.user_id\)\)`
+	tests := []struct {
+		name      string
+		userID    string
+		wantCount string
+		wantArgs  []any
+	}{
+		{name: "signed in", userID: testutil.TestUserID, wantCount: `ctf.visibility\s+=\s+'public'\s+` + blockFilter + `$`, wantArgs: []any{testutil.TestUserID}},
+		{name: "anonymous", wantCount: `ctf.visibility\s+=\s+'public'\s*$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`^SELECT\s+COUNT\(\*\)[\s\S]+`+tt.wantCount).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows([]string{"count"}, []any{int64(0)})
+			mockDB.ExpectQuery(`ORDER\s+BY\s+ctf.created_at\s+DESC`).
+				WithArgs(append(tt.wantArgs, 20, 0)...).
+				WillReturnRows(feedColumns)
+
+			if _, _, err := store.NewFeedStore(postgres).GetFeed(context.Background(), store.GetFeedOptions{FeedType: store.FeedTypePanIndia, UserID: tt.userID, Page: 1, PageSize: 20}); err != nil {
+				t.Fatalf("GetFeed: %v", err)
+			}
+		})
+	}
+}
+
+func TestFeedStoreAddComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocked bool
+		wantErr string
+	}{
+		{name: "adds", blocked: false},
+		{name: "blocked either way", blocked: true, wantErr: "comment blocked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := moderation.GetWordFilter()
+			t.Cleanup(func() { moderation.SetWordFilter(previous) })
+			moderation.SetWordFilter(nil)
+
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`FROM completed_task_feed ctf\s+JOIN user_blocks ub ON \(ub.blocker_id = ctf.user_id AND ub.blocked_id = \$2\)\s+OR \(ub.blocker_id = \$2 AND ub.blocked_id = ctf.user_id\)\s+WHERE ctf.id = \$1`).
+				WithArgs(testutil.TestFeedID, testutil.TestUserID).
+				WillReturnRows([]string{"exists"}, []any{tt.blocked})
+			if !tt.blocked {
+				mockDB.ExpectQuery(`INSERT INTO task_feed_comments`).
+					WithArgs(testutil.AnyArg(), testutil.TestFeedID, testutil.TestUserID, "Nice work").
+					WillReturnRows([]string{"id", "feed_id", "user_id", "comment", "created_at"},
+						[]any{"comment-1", testutil.TestFeedID, testutil.TestUserID, "Nice work", testutil.TestTime})
+				mockDB.ExpectQuery(`SELECT name, avatar_url FROM users WHERE id = \$1`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"name", "avatar_url"}, []any{"Test User", nil})
+			}
+
+			comment, err := store.NewFeedStore(postgres).AddComment(context.Background(), testutil.TestFeedID, testutil.TestUserID, "Nice work")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddComment: %v", err)
+			}
+			if comment.Comment != "Nice work" || comment.UserName != "Test User" {
+				t.Errorf("comment = %+v", comment)
+			}
+		})
+	}
+}
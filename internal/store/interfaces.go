@@ -0,0 +1,323 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// Interfaces describing the public surface of the concrete stores, so callers can
+// depend on behaviour rather than on *db.Postgres-backed implementations.
+// Keep these in sync when adding or changing store methods; the assertions at
+// the bottom of the file fail the build if a store drifts from its interface.
+
+// UserStoreInterface is implemented by *UserStore
+type UserStoreInterface interface {
+	Register(ctx context.Context, req RegisterRequest, resumeURL, resumeKey, profilePicURL, profilePicKey string) (*User, error)
+	ImportUsers(ctx context.Context, users []BulkImportUser) (imported []ImportedUser, skipped int, rowErrors []BulkImportError, err error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserPasswordHash(ctx context.Context, email string) (string, error)
+	VerifyPassword(hashedPassword, password string) bool
+	UpdateResumeURL(ctx context.Context, userID, resumeURL, resumeKey string) error
+	UpdateResumeVisibility(ctx context.Context, userID, visibility string) error
+	UpdateProfilePicURL(ctx context.Context, userID, profilePicURL, profilePicKey string) error
+	UpdateProfile(ctx context.Context, userID string, fields UpdateProfileRequest) (*User, error)
+	BanUser(ctx context.Context, userID, reason string) error
+	UnbanUser(ctx context.Context, userID string) error
+	IsUserBanned(ctx context.Context, userID string) (bool, error)
+	UpdateFCMToken(ctx context.Context, userID, token string) error
+	GetFCMToken(ctx context.Context, userID string) (string, error)
+	GetUserS3Keys(ctx context.Context, userID string) (resumeKey, avatarKey string, err error)
+	GetAllUsers(ctx context.Context, limit, offset int) ([]*User, error)
+	GetUserByID(ctx context.Context, userID string) (*User, error)
+	FollowUser(ctx context.Context, followerID, followingID string) error
+	UnfollowUser(ctx context.Context, followerID, followingID string) error
+	GetFollowingCount(ctx context.Context, userID string) (int, error)
+	GetFollowersCount(ctx context.Context, userID string) (int, error)
+	GetFollowers(ctx context.Context, userID, viewerID string, limit, offset int) ([]FollowUserInfo, int, error)
+	SearchUsers(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]SearchUserResult, int, error)
+	GetFollowing(ctx context.Context, userID, viewerID string, limit, offset int) ([]FollowUserInfo, int, error)
+	BlockUser(ctx context.Context, blockerID, blockedID string) error
+	UnblockUser(ctx context.Context, blockerID, blockedID string) error
+	IsBlockedBetween(ctx context.Context, userAID, userBID string) (bool, error)
+	GetBlockedUsers(ctx context.Context, blockerID string, limit, offset int) ([]FollowUserInfo, error)
+	IsFollowing(ctx context.Context, followerID, followingID string) (bool, error)
+	IsMutualFollow(ctx context.Context, userAID, userBID string) (bool, error)
+	GetMutualFollows(ctx context.Context, userAID, userBID string, page, pageSize int) ([]FollowUserInfo, int, error)
+}
+
+// FeedStoreInterface is implemented by *FeedStore
+type FeedStoreInterface interface {
+	GetFeed(ctx context.Context, opts GetFeedOptions) ([]FeedItem, int, error)
+	GetUserFeed(ctx context.Context, userID string, page, pageSize int) ([]FeedItem, int, error)
+	CreateFeedEntry(ctx context.Context, submissionID, userID, taskID string) error
+	AddReaction(ctx context.Context, feedID, userID, reaction string) error
+	RemoveReaction(ctx context.Context, feedID, userID string) error
+	AddComment(ctx context.Context, feedID, userID, comment string) (*FeedComment, error)
+	GetComments(ctx context.Context, feedID string, limit, offset int) ([]FeedComment, error)
+	CountComments(ctx context.Context, feedID string) (int, error)
+	FeedEntryExists(ctx context.Context, feedID string) (bool, error)
+	HideFeedEntry(ctx context.Context, feedID, userID string) error
+}
+
+// TaskStoreInterface is implemented by *TaskStore
+type TaskStoreInterface interface {
+	CreateTask(ctx context.Context, req CreateTaskRequest, assignmentType AssignmentType, assignmentID string) (*Task, []string, error)
+	GetWeeklyTasksDueForRepeat(ctx context.Context) ([]WeeklyTaskTemplate, error)
+	CreateWeeklyInstance(ctx context.Context, template WeeklyTaskTemplate, startAt, endAt time.Time) (*Task, []string, error)
+	GetTaskSeries(ctx context.Context, taskID string) ([]Task, error)
+	AssignOpenTasksToNewUser(ctx context.Context, userID string) ([]string, error)
+	UpdateTaskAssignment(ctx context.Context, taskID string, assignmentType AssignmentType, assignmentID string, removeExisting bool) ([]string, error)
+	AddTaskAssignment(ctx context.Context, taskID string, userIDs []string) ([]string, error)
+	RemoveTaskAssignment(ctx context.Context, taskID string, userIDs []string) (removed int, withSubmission []string, err error)
+	GetTaskByID(ctx context.Context, taskID string) (*Task, error)
+	GetTasksForUser(ctx context.Context, userID string) ([]Task, error)
+	GetTasksForUserWithStatus(ctx context.Context, userID string) ([]TaskWithUserStatus, error)
+	ToggleReaction(ctx context.Context, taskID, userID, reaction string) (bool, error)
+	GetReactionBreakdown(ctx context.Context, taskID string) (map[string]int, error)
+	GetFlashTasks(ctx context.Context, userID string) ([]TaskWithUserStatus, error)
+	CheckSubmissionExists(ctx context.Context, taskID, userID string) (bool, error)
+}
+
+// SubmissionStoreInterface is implemented by *SubmissionStore
+type SubmissionStoreInterface interface {
+	GetSubmissionByTaskAndUser(ctx context.Context, taskID, userID string) (*Submission, error)
+	GetLatestApprovedSubmission(ctx context.Context, taskID, userID string) (*Submission, error)
+	UpdateSubmissionProof(ctx context.Context, submissionID, newProofURL, newThumbnailURL string) (*Submission, error)
+	CreateSubmission(ctx context.Context, req CreateSubmissionRequest) (*Submission, error)
+	GetSubmissionByID(ctx context.Context, submissionID string) (*Submission, error)
+	CountApprovedSubmissions(ctx context.Context, userID string) (int, error)
+	SaveLinkPreview(ctx context.Context, submissionID, title, description, imageURL string) error
+	ApproveSubmission(ctx context.Context, submissionID, adminUserID string, comment string) (*Submission, error)
+	RejectSubmission(ctx context.Context, submissionID, adminUserID, comment string) (*Submission, error)
+	BulkApprove(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]BulkReviewResult, []BulkReviewFailure, error)
+	BulkReject(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]BulkReviewResult, []BulkReviewFailure, error)
+	GetAllSubmissions(ctx context.Context, filter SubmissionFilter) ([]Submission, error)
+	ListSubmissions(ctx context.Context, filter SubmissionFilter, cursor *SubmissionCursor, limit, offset int) ([]Submission, *SubmissionCursor, error)
+	IsProofKeyReferenced(ctx context.Context, key string) (bool, error)
+}
+
+// XPStoreInterface is implemented by *XPStore
+type XPStoreInterface interface {
+	AwardXP(ctx context.Context, req AwardXPRequest) (*XPLog, error)
+	DeductXP(ctx context.Context, req AwardXPRequest) (*XPLog, error)
+	AdjustXP(ctx context.Context, adminID, userID string, delta int, reason string, allowNegative bool) (*XPLog, error)
+	GetXPLogs(ctx context.Context, userID string, limit int) ([]XPLog, error)
+	GetXPSummary(ctx context.Context, userID string) (*XPSummary, error)
+	GetUserTotalXP(ctx context.Context, userID string) (int, error)
+	GetXPAnomalies(ctx context.Context, threshold int, window time.Duration) ([]XPAnomaly, error)
+	GetAllXPLogs(ctx context.Context, filter XPLogFilter) ([]XPLogWithUser, error)
+	StreamXPLogs(ctx context.Context, filter XPLogFilter, fn func(XPLogWithUser) error) error
+}
+
+// ActivityLogStoreInterface is implemented by *ActivityLogStore
+type ActivityLogStoreInterface interface {
+	RecordActivity(ctx context.Context, req RecordActivityRequest) error
+	GetUserActivityLog(ctx context.Context, userID string, page, pageSize int) ([]ActivityLog, int, error)
+	GetPublicActivityLog(ctx context.Context, userID string, page, pageSize int) ([]ActivityLog, int, error)
+}
+
+// AdminStoreInterface is implemented by *AdminStore
+type AdminStoreInterface interface {
+	CreateAdmin(ctx context.Context, req CreateAdminRequest) (*Admin, error)
+	GetAdminByID(ctx context.Context, adminID string) (*Admin, error)
+	GetAdminByUsername(ctx context.Context, username string) (*Admin, error)
+	VerifyAdminPassword(ctx context.Context, username, password string) (bool, error)
+	UpdateAdminPassword(ctx context.Context, adminID, newHash string) error
+	UpdateAdminPermissions(ctx context.Context, adminID string, permissions []string) (*Admin, error)
+	SetTOTPSecret(ctx context.Context, adminID, secret string) error
+	GetTOTPSecret(ctx context.Context, adminID string) (string, bool, error)
+	EnableTOTP(ctx context.Context, adminID string) error
+}
+
+// AnnouncementStoreInterface is implemented by *AnnouncementStore
+type AnnouncementStoreInterface interface {
+	CreateAnnouncement(ctx context.Context, req CreateAnnouncementRequest) (*Announcement, error)
+	GetAnnouncements(ctx context.Context, limit, offset int) ([]Announcement, int, error)
+}
+
+// AuditLogStoreInterface is implemented by *AuditLogStore
+type AuditLogStoreInterface interface {
+	Record(ctx context.Context, adminID, action string, details interface{}) error
+}
+
+// BadgeStoreInterface is implemented by *BadgeStore
+type BadgeStoreInterface interface {
+	CreateBadge(ctx context.Context, req CreateBadgeRequest) (*Badge, error)
+	GetBadgeByID(ctx context.Context, badgeID string) (*Badge, error)
+	GetUserBadges(ctx context.Context, userID string) ([]UserBadge, error)
+	AwardBadge(ctx context.Context, userID, badgeID string) error
+	CheckAndAwardBadges(ctx context.Context, userID string, userXP, userLevel int) error
+	GetAllBadges(ctx context.Context) ([]Badge, error)
+	ListBadges(ctx context.Context, filter BadgeFilter, userID string) ([]BadgeWithStatus, error)
+	GetBadgeWithStatus(ctx context.Context, badgeID, userID string) (*BadgeWithStatus, error)
+}
+
+// BlockedWordStoreInterface is implemented by *BlockedWordStore
+type BlockedWordStoreInterface interface {
+	GetBlockedWords(ctx context.Context) ([]BlockedWord, error)
+	GetBlockedWordList(ctx context.Context) ([]string, error)
+	AddBlockedWord(ctx context.Context, word, adminID string) (*BlockedWord, error)
+	DeleteBlockedWord(ctx context.Context, id string) error
+}
+
+// ChatStoreInterface is implemented by *ChatStore
+type ChatStoreInterface interface {
+	GetRoomsForUser(ctx context.Context, userID string) ([]ChatRoom, error)
+	GetRoomByID(ctx context.Context, roomID string) (*ChatRoom, error)
+	GetMessages(ctx context.Context, roomID string, limit int, cursor *ChatMessageCursor) ([]ChatMessage, *ChatMessageCursor, error)
+	CreateMessage(ctx context.Context, roomID, userID, content string) (*ChatMessage, error)
+}
+
+// CoinStoreInterface is implemented by *CoinStore
+type CoinStoreInterface interface {
+	AwardCoins(ctx context.Context, userID string, amount int, reason string) (int, error)
+	GetBalance(ctx context.Context, userID string) (int, error)
+	Deduct(ctx context.Context, userID string, amount int, reason string) error
+	GetCoinHistory(ctx context.Context, userID string, page, pageSize int) ([]CoinLog, int, error)
+	ExchangeCoinsForXP(ctx context.Context, userID string, coins, xpPerCoin int) (*CoinExchange, error)
+}
+
+// CollegeStoreInterface is implemented by *CollegeStore
+type CollegeStoreInterface interface {
+	GetCollegesByStateID(ctx context.Context, stateID string) ([]College, error)
+	GetCollegeByID(ctx context.Context, collegeID string) (*College, error)
+	CreateCollege(ctx context.Context, req CreateCollegeRequest) (*College, error)
+}
+
+// DigestStoreInterface is implemented by *DigestStore
+type DigestStoreInterface interface {
+	GetUnsentSince(ctx context.Context, userID string, since time.Time) ([]Notification, error)
+	GetDigestRecipients(ctx context.Context) ([]DigestRecipient, error)
+	MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error
+	SetDigestEnabled(ctx context.Context, userID string, enabled bool) error
+}
+
+// EmailVerificationStoreInterface is implemented by *EmailVerificationStore
+type EmailVerificationStoreInterface interface {
+	CreateToken(ctx context.Context, userID string) (string, error)
+	VerifyToken(ctx context.Context, token string) (string, error)
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// FeatureFlagStoreInterface is implemented by *FeatureFlagStore
+type FeatureFlagStoreInterface interface {
+	IsEnabled(ctx context.Context, flagName, userID string) (bool, error)
+	GetFeatureFlags(ctx context.Context) ([]FeatureFlag, error)
+	UpsertFeatureFlag(ctx context.Context, flagName string, req UpdateFeatureFlagRequest) (*FeatureFlag, error)
+}
+
+// LeaderboardStoreInterface is implemented by *LeaderboardStore
+type LeaderboardStoreInterface interface {
+	GetPanIndiaLeaderboard(ctx context.Context, limit, offset int, period string) ([]LeaderboardEntry, error)
+	GetStateLeaderboard(ctx context.Context, stateID string, limit, offset int, period string) ([]LeaderboardEntry, error)
+	GetCollegeLeaderboard(ctx context.Context, collegeID string, limit, offset int, period string) ([]LeaderboardEntry, error)
+	GetUserRank(ctx context.Context, userID string) (int, error)
+	GetUserRanks(ctx context.Context, userID string) (*UserRanks, error)
+	GetTopUserPerState(ctx context.Context, limit int, period string) ([]StateTopEntry, error)
+	GetCollegeRankings(ctx context.Context, limit, offset int, period string) ([]CollegeRankEntry, error)
+	GetStateRankings(ctx context.Context, limit, offset int, period string) ([]StateRankEntry, error)
+	GetLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error)
+	GetStateLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error)
+	GetCollegeLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error)
+}
+
+// LevelStoreInterface is implemented by *LevelStore
+type LevelStoreInterface interface {
+	GetLevelForXP(ctx context.Context, xp int) (int, error)
+	GetLevelThresholds(ctx context.Context) ([]LevelThreshold, error)
+	ReplaceLevelThresholds(ctx context.Context, thresholds []LevelThreshold) error
+}
+
+// NotificationStoreInterface is implemented by *NotificationStore
+type NotificationStoreInterface interface {
+	CreateNotification(ctx context.Context, userID, title, body, notificationType string, data interface{}) error
+	GetNotifications(ctx context.Context, userID string, limit, offset int) ([]Notification, int, int, error)
+	MarkRead(ctx context.Context, userID, notificationID string) error
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+}
+
+// OnboardingStoreInterface is implemented by *OnboardingStore
+type OnboardingStoreInterface interface {
+	MarkStep(ctx context.Context, userID, step string) (bool, error)
+	GetProgress(ctx context.Context, userID string) (*OnboardingProgress, error)
+}
+
+// PasswordResetStoreInterface is implemented by *PasswordResetStore
+type PasswordResetStoreInterface interface {
+	CreateToken(ctx context.Context, userID string, ttl time.Duration) (string, error)
+	ResetPassword(ctx context.Context, token, newPassword string) (string, error)
+}
+
+// ReferralStoreInterface is implemented by *ReferralStore
+type ReferralStoreInterface interface {
+	GetReferralCount(ctx context.Context, userID string) (int, error)
+	GetReferralStats(ctx context.Context, userID string, limit, offset int) (*ReferralStats, error)
+	AwardReferralBonus(ctx context.Context, referredUserID string, xp int) (*XPLog, error)
+}
+
+// StateStoreInterface is implemented by *StateStore
+type StateStoreInterface interface {
+	GetAllStates(ctx context.Context) ([]State, error)
+	GetStateByID(ctx context.Context, stateID string) (*State, error)
+	CreateState(ctx context.Context, req CreateStateRequest) (*State, error)
+}
+
+// StatsStoreInterface is implemented by *StatsStore
+type StatsStoreInterface interface {
+	GetCollegeStats(ctx context.Context, collegeID string) (*CollegeStats, error)
+	GetStateStats(ctx context.Context, stateID string) (*StateStats, error)
+	GetUserStats(ctx context.Context, userID string) (*UserStats, error)
+	GetDashboardStats(ctx context.Context) (*DashboardStats, error)
+	GetUserCountByState(ctx context.Context) ([]StateUserCount, error)
+}
+
+// StreakStoreInterface is implemented by *StreakStore
+type StreakStoreInterface interface {
+	UpdateStreak(ctx context.Context, userID string) error
+	GetUserStreak(ctx context.Context, userID string) (int, *time.Time, *time.Time, error)
+	GetStreakDetails(ctx context.Context, userID string) (*StreakDetails, error)
+	HasUnusedStreakFreeze(ctx context.Context, userID string) (bool, error)
+	BuyStreakFreeze(ctx context.Context, userID string, cost int) (string, int, error)
+	RedeemStreakReward(ctx context.Context, userID string, streakDays int) (int, []string, error)
+}
+
+// WeeklyResetStoreInterface is implemented by *WeeklyResetStore
+type WeeklyResetStoreInterface interface {
+	ClearUnreviewedSubmissions(ctx context.Context) (int64, error)
+}
+
+// XPCodeStoreInterface is implemented by *XPCodeStore
+type XPCodeStoreInterface interface {
+	RedeemXPCode(ctx context.Context, code, userID string) (*XPCode, error)
+}
+
+var (
+	_ UserStoreInterface              = (*UserStore)(nil)
+	_ FeedStoreInterface              = (*FeedStore)(nil)
+	_ TaskStoreInterface              = (*TaskStore)(nil)
+	_ SubmissionStoreInterface        = (*SubmissionStore)(nil)
+	_ XPStoreInterface                = (*XPStore)(nil)
+	_ ActivityLogStoreInterface       = (*ActivityLogStore)(nil)
+	_ AdminStoreInterface             = (*AdminStore)(nil)
+	_ AnnouncementStoreInterface      = (*AnnouncementStore)(nil)
+	_ AuditLogStoreInterface          = (*AuditLogStore)(nil)
+	_ BadgeStoreInterface             = (*BadgeStore)(nil)
+	_ BlockedWordStoreInterface       = (*BlockedWordStore)(nil)
+	_ ChatStoreInterface              = (*ChatStore)(nil)
+	_ CoinStoreInterface              = (*CoinStore)(nil)
+	_ CollegeStoreInterface           = (*CollegeStore)(nil)
+	_ DigestStoreInterface            = (*DigestStore)(nil)
+	_ EmailVerificationStoreInterface = (*EmailVerificationStore)(nil)
+	_ FeatureFlagStoreInterface       = (*FeatureFlagStore)(nil)
+	_ LeaderboardStoreInterface       = (*LeaderboardStore)(nil)
+	_ LevelStoreInterface             = (*LevelStore)(nil)
+	_ NotificationStoreInterface      = (*NotificationStore)(nil)
+	_ OnboardingStoreInterface        = (*OnboardingStore)(nil)
+	_ PasswordResetStoreInterface     = (*PasswordResetStore)(nil)
+	_ ReferralStoreInterface          = (*ReferralStore)(nil)
+	_ StateStoreInterface             = (*StateStore)(nil)
+	_ StatsStoreInterface             = (*StatsStore)(nil)
+	_ StreakStoreInterface            = (*StreakStore)(nil)
+	_ WeeklyResetStoreInterface       = (*WeeklyResetStore)(nil)
+	_ XPCodeStoreInterface            = (*XPCodeStore)(nil)
+)
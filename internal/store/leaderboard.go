@@ -32,7 +32,7 @@ func NewLeaderboardStore(postgres *db.Postgres) *LeaderboardStore {
 }
 
 // GetPanIndiaLeaderboard retrieves the pan-India leaderboard
-// period can be "all", "weekly", or "monthly" - defaults to "all"
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
 func (s *LeaderboardStore) GetPanIndiaLeaderboard(ctx context.Context, limit, offset int, period string) ([]LeaderboardEntry, error) {
 	if limit <= 0 {
 		limit = 100
@@ -47,6 +47,24 @@ func (s *LeaderboardStore) GetPanIndiaLeaderboard(ctx context.Context, limit, of
 
 	var query string
 	switch period {
+	case "daily":
+		// Get XP earned in the last 24 hours from xp_logs; ties broken by overall XP
+		query = `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC) as rank,
+				u.id, u.name, u.avatar_url,
+				COALESCE(SUM(xl.xp), 0) as xp, u.level,
+				u.state_id, s.name as state_name, u.college_id, c.name as college_name
+			FROM users u
+			LEFT JOIN states s ON u.state_id = s.id
+			LEFT JOIN colleges c ON u.college_id = c.id
+			LEFT JOIN xp_logs xl ON u.id = xl.user_id 
+				AND xl.created_at >= NOW() - INTERVAL '24 hours'
+			WHERE u.role = 'student'
+			GROUP BY u.id, u.name, u.avatar_url, u.xp, u.level, u.created_at, u.state_id, s.name, u.college_id, c.name
+			ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC
+			LIMIT $1 OFFSET $2
+		`
 	case "weekly":
 		// Get XP earned in the last 7 days from xp_logs
 		query = `
@@ -97,7 +115,7 @@ func (s *LeaderboardStore) GetPanIndiaLeaderboard(ctx context.Context, limit, of
 		`
 	}
 
-	rows, err := s.postgres.DB.QueryContext(ctx, query, limit, offset)
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pan-india leaderboard: %w", err)
 	}
@@ -136,7 +154,7 @@ func (s *LeaderboardStore) GetPanIndiaLeaderboard(ctx context.Context, limit, of
 }
 
 // GetStateLeaderboard retrieves the state leaderboard
-// period can be "all", "weekly", or "monthly" - defaults to "all"
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
 func (s *LeaderboardStore) GetStateLeaderboard(ctx context.Context, stateID string, limit, offset int, period string) ([]LeaderboardEntry, error) {
 	if limit <= 0 {
 		limit = 100
@@ -151,6 +169,23 @@ func (s *LeaderboardStore) GetStateLeaderboard(ctx context.Context, stateID stri
 
 	var query string
 	switch period {
+	case "daily":
+		// Get XP earned in the last 24 hours from xp_logs; ties broken by overall XP
+		query = `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC) as rank,
+				u.id, u.name, u.avatar_url, COALESCE(SUM(xl.xp), 0) as xp, u.level,
+				u.state_id, s.name as state_name, u.college_id, c.name as college_name
+			FROM users u
+			INNER JOIN states s ON u.state_id = s.id
+			LEFT JOIN colleges c ON u.college_id = c.id
+			LEFT JOIN xp_logs xl ON u.id = xl.user_id 
+				AND xl.created_at >= NOW() - INTERVAL '24 hours'
+			WHERE u.role = 'student' AND u.state_id = $1
+			GROUP BY u.id, u.name, u.avatar_url, u.xp, u.level, u.created_at, u.state_id, s.name, u.college_id, c.name
+			ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC
+			LIMIT $2 OFFSET $3
+		`
 	case "weekly":
 		query = `
 			SELECT 
@@ -198,7 +233,7 @@ func (s *LeaderboardStore) GetStateLeaderboard(ctx context.Context, stateID stri
 		`
 	}
 
-	rows, err := s.postgres.DB.QueryContext(ctx, query, stateID, limit, offset)
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, stateID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query state leaderboard: %w", err)
 	}
@@ -237,7 +272,7 @@ func (s *LeaderboardStore) GetStateLeaderboard(ctx context.Context, stateID stri
 }
 
 // GetCollegeLeaderboard retrieves the college leaderboard
-// period can be "all", "weekly", or "monthly" - defaults to "all"
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
 func (s *LeaderboardStore) GetCollegeLeaderboard(ctx context.Context, collegeID string, limit, offset int, period string) ([]LeaderboardEntry, error) {
 	if limit <= 0 {
 		limit = 100
@@ -252,6 +287,23 @@ func (s *LeaderboardStore) GetCollegeLeaderboard(ctx context.Context, collegeID
 
 	var query string
 	switch period {
+	case "daily":
+		// Get XP earned in the last 24 hours from xp_logs; ties broken by overall XP
+		query = `
+			SELECT 
+				ROW_NUMBER() OVER (ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC) as rank,
+				u.id, u.name, u.avatar_url, COALESCE(SUM(xl.xp), 0) as xp, u.level,
+				u.state_id, s.name as state_name, u.college_id, c.name as college_name
+			FROM users u
+			INNER JOIN colleges c ON u.college_id = c.id
+			LEFT JOIN states s ON u.state_id = s.id
+			LEFT JOIN xp_logs xl ON u.id = xl.user_id 
+				AND xl.created_at >= NOW() - INTERVAL '24 hours'
+			WHERE u.role = 'student' AND u.college_id = $1
+			GROUP BY u.id, u.name, u.avatar_url, u.xp, u.level, u.created_at, u.state_id, s.name, u.college_id, c.name
+			ORDER BY COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC
+			LIMIT $2 OFFSET $3
+		`
 	case "weekly":
 		query = `
 			SELECT 
@@ -299,7 +351,7 @@ func (s *LeaderboardStore) GetCollegeLeaderboard(ctx context.Context, collegeID
 		`
 	}
 
-	rows, err := s.postgres.DB.QueryContext(ctx, query, collegeID, limit, offset)
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, collegeID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query college leaderboard: %w", err)
 	}
@@ -349,10 +401,441 @@ func (s *LeaderboardStore) GetUserRank(ctx context.Context, userID string) (int,
 	`
 
 	var rank int
-	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&rank)
+	err := s.postgres.Traced.QueryRowContext(ctx, query, userID).Scan(&rank)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get user rank: %w", err)
 	}
 
 	return rank, nil
 }
+
+// UserRanks is a user's all-time rank on the pan-India, state and college leaderboards.
+// StateRank and CollegeRank are nil when the user has no state or college.
+type UserRanks struct {
+	XP           int     `json:"xp"`
+	PanIndiaRank int     `json:"pan_india_rank"`
+	StateID      *string `json:"state_id"`
+	StateRank    *int    `json:"state_rank"`
+	CollegeID    *string `json:"college_id"`
+	CollegeRank  *int    `json:"college_rank"`
+}
+
+// GetUserRanks retrieves a user's rank in all three leaderboard scopes, ordered like the
+// all-time leaderboards (XP, then earliest sign-up). Returns "user not found" for unknown users.
+func (s *LeaderboardStore) GetUserRanks(ctx context.Context, userID string) (*UserRanks, error) {
+	query := `
+		SELECT
+			me.xp,
+			(SELECT COUNT(*) + 1 FROM users o
+			 WHERE o.role = 'student'
+			 AND (o.xp > me.xp OR (o.xp = me.xp AND o.created_at < me.created_at))),
+			me.state_id,
+			CASE WHEN me.state_id IS NULL THEN NULL ELSE
+				(SELECT COUNT(*) + 1 FROM users o
+				 WHERE o.role = 'student' AND o.state_id = me.state_id
+				 AND (o.xp > me.xp OR (o.xp = me.xp AND o.created_at < me.created_at)))
+			END,
+			me.college_id,
+			CASE WHEN me.college_id IS NULL THEN NULL ELSE
+				(SELECT COUNT(*) + 1 FROM users o
+				 WHERE o.role = 'student' AND o.college_id = me.college_id
+				 AND (o.xp > me.xp OR (o.xp = me.xp AND o.created_at < me.created_at)))
+			END
+		FROM users me
+		WHERE me.id = $1
+	`
+
+	var ranks UserRanks
+	var stateID, collegeID sql.NullString
+	var stateRank, collegeRank sql.NullInt64
+	err := s.postgres.Traced.QueryRowContext(ctx, query, userID).Scan(
+		&ranks.XP, &ranks.PanIndiaRank, &stateID, &stateRank, &collegeID, &collegeRank,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user ranks: %w", err)
+	}
+
+	if stateID.Valid && stateRank.Valid {
+		rank := int(stateRank.Int64)
+		ranks.StateID = &stateID.String
+		ranks.StateRank = &rank
+	}
+	if collegeID.Valid && collegeRank.Valid {
+		rank := int(collegeRank.Int64)
+		ranks.CollegeID = &collegeID.String
+		ranks.CollegeRank = &rank
+	}
+
+	return &ranks, nil
+}
+
+// StateTopEntry is the top-ranked student of a single state
+type StateTopEntry struct {
+	StateID   string            `json:"state_id"`
+	StateName string            `json:"state_name"`
+	User      *LeaderboardEntry `json:"user"` // nil when the state has no students
+}
+
+// GetTopUserPerState retrieves the top student from every state, including states with no students
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
+func (s *LeaderboardStore) GetTopUserPerState(ctx context.Context, limit int, period string) ([]StateTopEntry, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	// XP expression and xp_logs join depend on the period
+	xpExpr := "u.xp"
+	xpJoin := ""
+	groupBy := ""
+	switch period {
+	case "daily":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '24 hours'"
+	case "weekly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '7 days'"
+	case "monthly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '30 days'"
+	}
+	if xpJoin != "" {
+		groupBy = "GROUP BY u.id, u.name, u.avatar_url, u.xp, u.level, u.created_at, u.college_id, c.name"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			s.id, s.name,
+			top.id, top.name, top.avatar_url, top.xp, top.level, top.college_id, top.college_name
+		FROM states s
+		LEFT JOIN LATERAL (
+			SELECT u.id, u.name, u.avatar_url, %s as xp, u.level, u.college_id, c.name as college_name
+			FROM users u
+			LEFT JOIN colleges c ON u.college_id = c.id
+			%s
+			WHERE u.role = 'student' AND u.state_id = s.id
+			%s
+			ORDER BY %s DESC, u.xp DESC, u.created_at ASC
+			LIMIT 1
+		) top ON true
+		ORDER BY top.xp DESC NULLS LAST, s.name ASC
+		LIMIT $1
+	`, xpExpr, xpJoin, groupBy, xpExpr)
+
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top user per state: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []StateTopEntry{}
+	for rows.Next() {
+		var entry StateTopEntry
+		var userID, userName, userAvatar, collegeID, collegeName sql.NullString
+		var xp, level sql.NullInt64
+
+		err := rows.Scan(
+			&entry.StateID, &entry.StateName,
+			&userID, &userName, &userAvatar, &xp, &level, &collegeID, &collegeName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan state top entry: %w", err)
+		}
+
+		if userID.Valid {
+			entry.User = &LeaderboardEntry{
+				Rank:        1,
+				UserID:      userID.String,
+				UserName:    userName.String,
+				UserAvatar:  userAvatar.String,
+				XP:          int(xp.Int64),
+				Level:       int(level.Int64),
+				StateID:     entry.StateID,
+				StateName:   entry.StateName,
+				CollegeID:   collegeID.String,
+				CollegeName: collegeName.String,
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating state top rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CollegeRankEntry is a college ranked by the combined XP of its students
+type CollegeRankEntry struct {
+	Rank         int     `json:"rank"`
+	CollegeID    string  `json:"college_id"`
+	CollegeName  string  `json:"college_name"`
+	TotalXP      int     `json:"total_xp"`
+	StudentCount int     `json:"student_count"`
+	AverageXP    float64 `json:"average_xp"`
+}
+
+// StateRankEntry is a state ranked by the combined XP of its students
+type StateRankEntry struct {
+	Rank         int     `json:"rank"`
+	StateID      string  `json:"state_id"`
+	StateName    string  `json:"state_name"`
+	TotalXP      int     `json:"total_xp"`
+	StudentCount int     `json:"student_count"`
+	AverageXP    float64 `json:"average_xp"`
+}
+
+// groupRank is one row of an aggregate (college or state) ranking
+type groupRank struct {
+	rank         int
+	id           string
+	name         string
+	totalXP      int
+	studentCount int
+	averageXP    float64
+}
+
+// GetCollegeRankings ranks colleges by the total XP of their students
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
+func (s *LeaderboardStore) GetCollegeRankings(ctx context.Context, limit, offset int, period string) ([]CollegeRankEntry, error) {
+	rows, err := s.getGroupRankings(ctx, "colleges", "college_id", limit, offset, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get college rankings: %w", err)
+	}
+
+	entries := make([]CollegeRankEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, CollegeRankEntry{
+			Rank:         row.rank,
+			CollegeID:    row.id,
+			CollegeName:  row.name,
+			TotalXP:      row.totalXP,
+			StudentCount: row.studentCount,
+			AverageXP:    row.averageXP,
+		})
+	}
+	return entries, nil
+}
+
+// GetStateRankings ranks states by the total XP of their students
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
+func (s *LeaderboardStore) GetStateRankings(ctx context.Context, limit, offset int, period string) ([]StateRankEntry, error) {
+	rows, err := s.getGroupRankings(ctx, "states", "state_id", limit, offset, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state rankings: %w", err)
+	}
+
+	entries := make([]StateRankEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, StateRankEntry{
+			Rank:         row.rank,
+			StateID:      row.id,
+			StateName:    row.name,
+			TotalXP:      row.totalXP,
+			StudentCount: row.studentCount,
+			AverageXP:    row.averageXP,
+		})
+	}
+	return entries, nil
+}
+
+// getGroupRankings aggregates student XP per row of table, matched on users.<column>.
+// For "all" the students' lifetime XP is summed; other periods sum XP earned in the window from xp_logs.
+// Ranks are computed over every group before limit/offset is applied.
+func (s *LeaderboardStore) getGroupRankings(ctx context.Context, table, column string, limit, offset int, period string) ([]groupRank, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	xpExpr := "u.xp"
+	xpJoin := ""
+	groupBy := ""
+	switch period {
+	case "daily":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '24 hours'"
+	case "weekly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '7 days'"
+	case "monthly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '30 days'"
+	}
+	if xpJoin != "" {
+		groupBy = fmt.Sprintf("GROUP BY u.id, u.%s", column)
+	}
+
+	query := fmt.Sprintf(`
+		WITH student_xp AS (
+			SELECT u.id, u.%[1]s AS group_id, %[2]s AS xp
+			FROM users u
+			%[3]s
+			WHERE u.role = 'student' AND u.%[1]s IS NOT NULL
+			%[4]s
+		)
+		SELECT
+			ROW_NUMBER() OVER (ORDER BY SUM(sx.xp) DESC, COUNT(sx.id) DESC, g.name ASC) as rank,
+			g.id, g.name,
+			SUM(sx.xp) as total_xp,
+			COUNT(sx.id) as student_count,
+			ROUND(AVG(sx.xp), 2)::float8 as average_xp
+		FROM student_xp sx
+		JOIN %[5]s g ON g.id = sx.group_id
+		GROUP BY g.id, g.name
+		ORDER BY rank
+		LIMIT $1 OFFSET $2
+	`, column, xpExpr, xpJoin, groupBy, table)
+
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s rankings: %w", table, err)
+	}
+	defer rows.Close()
+
+	var ranks []groupRank
+	for rows.Next() {
+		var row groupRank
+		if err := rows.Scan(&row.rank, &row.id, &row.name, &row.totalXP, &row.studentCount, &row.averageXP); err != nil {
+			return nil, fmt.Errorf("failed to scan %s ranking: %w", table, err)
+		}
+		ranks = append(ranks, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating %s ranking rows: %w", table, err)
+	}
+
+	return ranks, nil
+}
+
+// GetLeaderboardAroundUser returns the pan-India leaderboard entries within radius ranks of the user,
+// plus the user's index in the returned slice. Near the top or bottom of the leaderboard fewer entries
+// are returned on that side. Returns "user not on leaderboard" if the user is not a ranked student.
+// period can be "all", "daily", "weekly", or "monthly" - defaults to "all"
+func (s *LeaderboardStore) GetLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error) {
+	return s.getLeaderboardAroundUser(ctx, userID, "", radius, period)
+}
+
+// GetStateLeaderboardAroundUser is GetLeaderboardAroundUser on the leaderboard of the user's state
+func (s *LeaderboardStore) GetStateLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error) {
+	return s.getLeaderboardAroundUser(ctx, userID, "state_id", radius, period)
+}
+
+// GetCollegeLeaderboardAroundUser is GetLeaderboardAroundUser on the leaderboard of the user's college
+func (s *LeaderboardStore) GetCollegeLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]LeaderboardEntry, int, error) {
+	return s.getLeaderboardAroundUser(ctx, userID, "college_id", radius, period)
+}
+
+// getLeaderboardAroundUser ranks the students sharing the user's scopeColumn (everyone when empty)
+// in the same order as the paginated leaderboards, then keeps the ranks within radius of the user's.
+func (s *LeaderboardStore) getLeaderboardAroundUser(ctx context.Context, userID, scopeColumn string, radius int, period string) ([]LeaderboardEntry, int, error) {
+	if radius < 0 {
+		radius = 0
+	}
+
+	// XP expression, xp_logs join and tie-breaks match the paginated leaderboard of the period
+	xpExpr := "u.xp"
+	xpJoin := ""
+	orderBy := "u.xp DESC, u.created_at ASC"
+	groupBy := ""
+	switch period {
+	case "daily":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '24 hours'"
+		orderBy = "COALESCE(SUM(xl.xp), 0) DESC, u.xp DESC, u.created_at ASC"
+	case "weekly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '7 days'"
+		orderBy = "COALESCE(SUM(xl.xp), 0) DESC, u.created_at ASC"
+	case "monthly":
+		xpExpr = "COALESCE(SUM(xl.xp), 0)"
+		xpJoin = "LEFT JOIN xp_logs xl ON u.id = xl.user_id AND xl.created_at >= NOW() - INTERVAL '30 days'"
+		orderBy = "COALESCE(SUM(xl.xp), 0) DESC, u.created_at ASC"
+	}
+	if xpJoin != "" {
+		groupBy = "GROUP BY u.id, u.name, u.avatar_url, u.xp, u.level, u.created_at, u.state_id, s.name, u.college_id, c.name"
+	}
+
+	// A user without a state or college matches nobody in that scope
+	scope := ""
+	if scopeColumn != "" {
+		scope = fmt.Sprintf("AND u.%[1]s = (SELECT %[1]s FROM users WHERE id = $1)", scopeColumn)
+	}
+
+	query := fmt.Sprintf(`
+		WITH ranked AS (
+			SELECT
+				ROW_NUMBER() OVER (ORDER BY %[1]s) as rank,
+				u.id, u.name, u.avatar_url, %[2]s as xp, u.level,
+				u.state_id, s.name as state_name, u.college_id, c.name as college_name
+			FROM users u
+			LEFT JOIN states s ON u.state_id = s.id
+			LEFT JOIN colleges c ON u.college_id = c.id
+			%[3]s
+			WHERE u.role = 'student' %[4]s
+			%[5]s
+		),
+		me AS (
+			SELECT rank FROM ranked WHERE id = $1
+		)
+		SELECT r.rank, r.id, r.name, r.avatar_url, r.xp, r.level,
+			r.state_id, r.state_name, r.college_id, r.college_name
+		FROM ranked r, me
+		WHERE r.rank BETWEEN me.rank - $2 AND me.rank + $2
+		ORDER BY r.rank
+	`, orderBy, xpExpr, xpJoin, scope, groupBy)
+
+	rows, err := s.postgres.Traced.QueryContext(ctx, query, userID, radius)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query leaderboard around user: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []LeaderboardEntry{}
+	userPosition := -1
+	for rows.Next() {
+		var entry LeaderboardEntry
+		var userAvatar, stateID, stateName, collegeID, collegeName sql.NullString
+
+		err := rows.Scan(
+			&entry.Rank, &entry.UserID, &entry.UserName, &userAvatar,
+			&entry.XP, &entry.Level,
+			&stateID, &stateName, &collegeID, &collegeName,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entry.UserAvatar = userAvatar.String
+		entry.StateID = stateID.String
+		entry.StateName = stateName.String
+		entry.CollegeID = collegeID.String
+		entry.CollegeName = collegeName.String
+		if entry.UserID == userID {
+			userPosition = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating leaderboard rows: %w", err)
+	}
+
+	if userPosition < 0 {
+		return nil, 0, fmt.Errorf("user not on leaderboard")
+	}
+
+	return entries, userPosition, nil
+}
@@ -0,0 +1,196 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestLeaderboardStoreGetUserRanks(t *testing.T) {
+	columns := []string{"xp", "pan_india_rank", "state_id", "state_rank", "college_id", "college_rank"}
+	tests := []struct {
+		name            string
+		row             []any // nil for an unknown user
+		wantPanIndia    int
+		wantStateRank   int // 0 when there should be none
+		wantCollegeRank int
+		wantErr         string
+	}{
+		{name: "every scope", row: []any{int64(900), int64(31), testutil.TestStateID, int64(4), testutil.TestCollegeID, int64(2)}, wantPanIndia: 31, wantStateRank: 4, wantCollegeRank: 2},
+		{name: "top of every board", row: []any{int64(5000), int64(1), testutil.TestStateID, int64(1), testutil.TestCollegeID, int64(1)}, wantPanIndia: 1, wantStateRank: 1, wantCollegeRank: 1},
+		{name: "no state or college", row: []any{int64(0), int64(120), nil, nil, nil, nil}, wantPanIndia: 120},
+		{name: "state only", row: []any{int64(10), int64(60), testutil.TestStateID, int64(9), nil, nil}, wantPanIndia: 60, wantStateRank: 9},
+		{name: "unknown user", wantErr: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			var rows [][]any
+			if tt.row != nil {
+				rows = append(rows, tt.row)
+			}
+			// Each scope counts the students ahead by XP, then by earlier sign-up
+			mockDB.ExpectQuery(`o.state_id = me.state_id[\s\S]+o.college_id = me.college_id[\s\S]+FROM users me\s+WHERE me.id = \$1`).
+				WithArgs(testutil.TestUserID).
+				WillReturnRows(columns, rows...)
+
+			ranks, err := store.NewLeaderboardStore(postgres).GetUserRanks(context.Background(), testutil.TestUserID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetUserRanks: %v", err)
+			}
+			if ranks.PanIndiaRank != tt.wantPanIndia {
+				t.Errorf("pan-India rank = %d, want %d", ranks.PanIndiaRank, tt.wantPanIndia)
+			}
+			checkScope := func(scope string, id *string, rank *int, wantID string, wantRank int) {
+				if wantRank == 0 {
+					if id != nil || rank != nil {
+						t.Errorf("%s = %v, rank %v; want none", scope, id, rank)
+					}
+					return
+				}
+				if id == nil || rank == nil || *id != wantID || *rank != wantRank {
+					t.Errorf("%s = %v, rank %v; want %s, rank %d", scope, id, rank, wantID, wantRank)
+				}
+			}
+			checkScope("state", ranks.StateID, ranks.StateRank, testutil.TestStateID, tt.wantStateRank)
+			checkScope("college", ranks.CollegeID, ranks.CollegeRank, testutil.TestCollegeID, tt.wantCollegeRank)
+		})
+	}
+}
+
+func TestLeaderboardStoreGetLeaderboardAroundUser(t *testing.T) {
+	columns := []string{"rank", "id", "name", "avatar_url", "xp", "level", "state_id", "state_name", "college_id", "college_name"}
+	// board returns a leaderboard of size students, rank 1 first
+	board := func(size int) []string {
+		ids := make([]string, size)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("user-%d", i+1)
+		}
+		return ids
+	}
+	tests := []struct {
+		name         string
+		board        []string
+		userRank     int // 0 when the user is not on the board
+		radius       int
+		wantRadius   int
+		wantRanks    []int
+		wantPosition int
+		wantErr      string
+	}{
+		{name: "middle of the board", board: board(20), userRank: 10, radius: 5, wantRadius: 5, wantRanks: []int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, wantPosition: 5},
+		{name: "rank 1", board: board(20), userRank: 1, radius: 5, wantRadius: 5, wantRanks: []int{1, 2, 3, 4, 5, 6}, wantPosition: 0},
+		{name: "near the top", board: board(20), userRank: 3, radius: 5, wantRadius: 5, wantRanks: []int{1, 2, 3, 4, 5, 6, 7, 8}, wantPosition: 2},
+		{name: "last rank", board: board(20), userRank: 20, radius: 5, wantRadius: 5, wantRanks: []int{15, 16, 17, 18, 19, 20}, wantPosition: 5},
+		{name: "board smaller than the radius", board: board(3), userRank: 2, radius: 5, wantRadius: 5, wantRanks: []int{1, 2, 3}, wantPosition: 1},
+		{name: "only student", board: board(1), userRank: 1, radius: 5, wantRadius: 5, wantRanks: []int{1}, wantPosition: 0},
+		{name: "zero radius", board: board(20), userRank: 7, radius: 0, wantRadius: 0, wantRanks: []int{7}, wantPosition: 0},
+		{name: "negative radius", board: board(20), userRank: 7, radius: -3, wantRadius: 0, wantRanks: []int{7}, wantPosition: 0},
+		{name: "not on the board", board: board(20), radius: 5, wantRadius: 5, wantErr: "user not on leaderboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := "not-a-student"
+			if tt.userRank > 0 {
+				userID = tt.board[tt.userRank-1]
+			}
+			// The rows Postgres returns for r.rank BETWEEN me.rank - $2 AND me.rank + $2
+			var rows [][]any
+			for i, id := range tt.board {
+				rank := i + 1
+				if tt.userRank > 0 && rank >= tt.userRank-tt.wantRadius && rank <= tt.userRank+tt.wantRadius {
+					rows = append(rows, []any{int64(rank), id, "Student " + id, nil, int64(1000 - rank), int64(1), testutil.TestStateID, "Karnataka", nil, nil})
+				}
+			}
+
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`WITH ranked AS[\s\S]+ROW_NUMBER\(\) OVER \(ORDER BY u.xp DESC, u.created_at ASC\)[\s\S]+WHERE u.role = 'student'\s+\),\s+me AS \(\s+SELECT rank FROM ranked WHERE id = \$1\s+\)[\s\S]+WHERE r.rank BETWEEN me.rank - \$2 AND me.rank \+ \$2\s+ORDER BY r.rank`).
+				WithArgs(userID, tt.wantRadius).
+				WillReturnRows(columns, rows...)
+
+			entries, position, err := store.NewLeaderboardStore(postgres).GetLeaderboardAroundUser(context.Background(), userID, tt.radius, "all")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLeaderboardAroundUser: %v", err)
+			}
+			var ranks []int
+			for _, entry := range entries {
+				ranks = append(ranks, entry.Rank)
+			}
+			if !reflect.DeepEqual(ranks, tt.wantRanks) {
+				t.Errorf("ranks = %v, want %v", ranks, tt.wantRanks)
+			}
+			if position != tt.wantPosition || entries[position].UserID != userID {
+				t.Errorf("user_position = %d, want %d (the user's own entry)", position, tt.wantPosition)
+			}
+			if entries[0].StateName != "Karnataka" || entries[0].CollegeID != "" {
+				t.Errorf("entry = %+v, want the state mapped and no college", entries[0])
+			}
+		})
+	}
+}
+
+func TestLeaderboardStoreAroundUserScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		around    func(s *store.LeaderboardStore) ([]store.LeaderboardEntry, int, error)
+		wantQuery string
+	}{
+		{
+			name: "state",
+			around: func(s *store.LeaderboardStore) ([]store.LeaderboardEntry, int, error) {
+				return s.GetStateLeaderboardAroundUser(context.Background(), testutil.TestUserID, 5, "all")
+			},
+			wantQuery: `WHERE u.role = 'student' AND u.state_id = \(SELECT state_id FROM users WHERE id = \$1\)`,
+		},
+		{
+			name: "college",
+			around: func(s *store.LeaderboardStore) ([]store.LeaderboardEntry, int, error) {
+				return s.GetCollegeLeaderboardAroundUser(context.Background(), testutil.TestUserID, 5, "all")
+			},
+			wantQuery: `WHERE u.role = 'student' AND u.college_id = \(SELECT college_id FROM users WHERE id = \$1\)`,
+		},
+		{
+			name: "weekly",
+			around: func(s *store.LeaderboardStore) ([]store.LeaderboardEntry, int, error) {
+				return s.GetLeaderboardAroundUser(context.Background(), testutil.TestUserID, 5, "weekly")
+			},
+			wantQuery: `ROW_NUMBER\(\) OVER \(ORDER BY COALESCE\(SUM\(xl.xp\), 0\) DESC, u.created_at ASC\)[\s\S]+xl.created_at >= NOW\(\) - INTERVAL '7 days'[\s\S]+GROUP BY u.id`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(tt.wantQuery).
+				WithArgs(testutil.TestUserID, 5).
+				WillReturnRows([]string{"rank", "id", "name", "avatar_url", "xp", "level", "state_id", "state_name", "college_id", "college_name"},
+					[]any{int64(1), testutil.TestUserID, "Test User", nil, int64(100), int64(1), testutil.TestStateID, "Karnataka", testutil.TestCollegeID, "IISc"})
+
+			entries, position, err := tt.around(store.NewLeaderboardStore(postgres))
+			if err != nil {
+				t.Fatalf("around user: %v", err)
+			}
+			if len(entries) != 1 || position != 0 {
+				t.Errorf("got %d entries, position %d; want the user alone", len(entries), position)
+			}
+		})
+	}
+}
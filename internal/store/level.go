@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// LevelThreshold is a row of the levels table and the XP range it covers
+type LevelThreshold struct {
+	Level int    `json:"level"`
+	Name  string `json:"name"`
+	MinXP int    `json:"min_xp"`
+	MaxXP *int   `json:"max_xp"` // One below the next level's min_xp; nil for the highest level, ignored on update
+}
+
+type LevelStore struct {
+	postgres *db.Postgres
+}
+
+func NewLevelStore(postgres *db.Postgres) *LevelStore {
+	return &LevelStore{
+		postgres: postgres,
+	}
+}
+
+// rowQuerier is implemented by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// levelForXP returns the highest level whose min_xp is <= xp (level 1 if no thresholds match)
+func levelForXP(ctx context.Context, q rowQuerier, xp int) (int, error) {
+	var level int
+	query := `SELECT level FROM levels WHERE min_xp <= $1 ORDER BY level DESC LIMIT 1`
+	err := q.QueryRowContext(ctx, query, xp).Scan(&level)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 1, nil
+		}
+		return 0, fmt.Errorf("failed to get level for XP: %w", err)
+	}
+	return level, nil
+}
+
+// GetLevelForXP returns the level a user with the given XP should be at
+func (s *LevelStore) GetLevelForXP(ctx context.Context, xp int) (int, error) {
+	return levelForXP(ctx, s.postgres.DB, xp)
+}
+
+// GetLevelThresholds returns all level thresholds ordered by level
+func (s *LevelStore) GetLevelThresholds(ctx context.Context) ([]LevelThreshold, error) {
+	query := `SELECT level, name, min_xp FROM levels ORDER BY level ASC`
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query level thresholds: %w", err)
+	}
+	defer rows.Close()
+
+	thresholds := []LevelThreshold{}
+	for rows.Next() {
+		var t LevelThreshold
+		if err := rows.Scan(&t.Level, &t.Name, &t.MinXP); err != nil {
+			return nil, fmt.Errorf("failed to scan level threshold: %w", err)
+		}
+		thresholds = append(thresholds, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read level thresholds: %w", err)
+	}
+
+	// Each level lasts until the next one starts
+	for i := 0; i+1 < len(thresholds); i++ {
+		maxXP := thresholds[i+1].MinXP - 1
+		thresholds[i].MaxXP = &maxXP
+	}
+	return thresholds, nil
+}
+
+// ReplaceLevelThresholds replaces all levels and recalculates every user's level.
+// Levels must be named, start at level 1 with min_xp 0, be consecutive and have increasing min_xp.
+func (s *LevelStore) ReplaceLevelThresholds(ctx context.Context, thresholds []LevelThreshold) error {
+	if len(thresholds) == 0 {
+		return fmt.Errorf("at least one level is required")
+	}
+	for i, t := range thresholds {
+		if t.Level != i+1 {
+			return fmt.Errorf("levels must be consecutive starting at 1")
+		}
+		if i == 0 && t.MinXP != 0 {
+			return fmt.Errorf("level 1 must start at 0 XP")
+		}
+		if i > 0 && t.MinXP <= thresholds[i-1].MinXP {
+			return fmt.Errorf("min_xp must increase with each level")
+		}
+		if strings.TrimSpace(t.Name) == "" {
+			return fmt.Errorf("every level needs a name")
+		}
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM levels`); err != nil {
+		return fmt.Errorf("failed to clear level thresholds: %w", err)
+	}
+
+	insertQuery := `INSERT INTO levels (level, name, min_xp) VALUES ($1, $2, $3)`
+	for _, t := range thresholds {
+		if _, err = tx.ExecContext(ctx, insertQuery, t.Level, strings.TrimSpace(t.Name), t.MinXP); err != nil {
+			return fmt.Errorf("failed to insert level threshold: %w", err)
+		}
+	}
+
+	recalcQuery := `
+		UPDATE users u
+		SET level = COALESCE((
+			SELECT l.level FROM levels l
+			WHERE l.min_xp <= u.xp
+			ORDER BY l.level DESC
+			LIMIT 1
+		), 1)
+	`
+	if _, err = tx.ExecContext(ctx, recalcQuery); err != nil {
+		return fmt.Errorf("failed to recalculate user levels: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestLevelStoreGetLevelThresholds(t *testing.T) {
+	postgres, mock := testutil.NewMockPostgres(t)
+	mock.ExpectQuery(`SELECT level, name, min_xp FROM levels ORDER BY level`).
+		WillReturnRows([]string{"level", "name", "min_xp"},
+			[]any{1, "Rookie", 0},
+			[]any{2, "Performer", 100},
+			[]any{3, "Pro", 500},
+		)
+
+	levels, err := store.NewLevelStore(postgres).GetLevelThresholds(context.Background())
+	if err != nil {
+		t.Fatalf("GetLevelThresholds: %v", err)
+	}
+	wantMax := []int{99, 499, -1}
+	if len(levels) != len(wantMax) {
+		t.Fatalf("got %d levels, want %d", len(levels), len(wantMax))
+	}
+	for i, level := range levels {
+		if wantMax[i] < 0 {
+			if level.MaxXP != nil {
+				t.Errorf("level %d max_xp = %d, want none", level.Level, *level.MaxXP)
+			}
+			continue
+		}
+		if level.MaxXP == nil || *level.MaxXP != wantMax[i] {
+			t.Errorf("level %d max_xp = %v, want %d", level.Level, level.MaxXP, wantMax[i])
+		}
+	}
+	if levels[0].Name != "Rookie" {
+		t.Errorf("level 1 name = %q, want Rookie", levels[0].Name)
+	}
+}
+
+func TestLevelStoreGetLevelForXP(t *testing.T) {
+	tests := []struct {
+		name      string
+		xp        int
+		rows      [][]any
+		wantLevel int
+	}{
+		{name: "matching level", xp: 150, rows: [][]any{{2}}, wantLevel: 2},
+		{name: "no levels defaults to 1", xp: 150, wantLevel: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			mock.ExpectQuery(`SELECT level FROM levels WHERE min_xp <= \$1`).
+				WithArgs(tt.xp).
+				WillReturnRows([]string{"level"}, tt.rows...)
+
+			level, err := store.NewLevelStore(postgres).GetLevelForXP(context.Background(), tt.xp)
+			if err != nil {
+				t.Fatalf("GetLevelForXP: %v", err)
+			}
+			if level != tt.wantLevel {
+				t.Errorf("level = %d, want %d", level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLevelStoreReplaceLevelThresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		levels  []store.LevelThreshold
+		wantErr string
+	}{
+		{name: "replaces levels", levels: []store.LevelThreshold{{Level: 1, Name: "Rookie", MinXP: 0}, {Level: 2, Name: " Performer ", MinXP: 100}}},
+		{name: "no levels", wantErr: "at least one level is required"},
+		{name: "gap in levels", levels: []store.LevelThreshold{{Level: 2, Name: "Rookie", MinXP: 0}}, wantErr: "levels must be consecutive starting at 1"},
+		{name: "level 1 above 0 XP", levels: []store.LevelThreshold{{Level: 1, Name: "Rookie", MinXP: 5}}, wantErr: "level 1 must start at 0 XP"},
+		{name: "same min_xp", levels: []store.LevelThreshold{{Level: 1, Name: "Rookie", MinXP: 0}, {Level: 2, Name: "Performer", MinXP: 0}}, wantErr: "min_xp must increase with each level"},
+		{name: "blank name", levels: []store.LevelThreshold{{Level: 1, Name: "  ", MinXP: 0}}, wantErr: "every level needs a name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			if tt.wantErr == "" {
+				mock.ExpectBegin()
+				mock.ExpectExec(`DELETE FROM levels`).WillReturnResult(2)
+				mock.ExpectExec(`INSERT INTO levels \(level, name, min_xp\)`).WithArgs(1, "Rookie", 0).WillReturnResult(1)
+				mock.ExpectExec(`INSERT INTO levels \(level, name, min_xp\)`).WithArgs(2, "Performer", 100).WillReturnResult(1)
+				mock.ExpectExec(`UPDATE users u\s+SET level = COALESCE\(\(\s+SELECT l.level FROM levels l`).WillReturnResult(10)
+				mock.ExpectCommit()
+			}
+
+			err := store.NewLevelStore(postgres).ReplaceLevelThresholds(context.Background(), tt.levels)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ReplaceLevelThresholds: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
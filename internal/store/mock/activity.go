@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ActivityLogStore is a stub store.ActivityLogStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type ActivityLogStore struct {
+	RecordActivityFunc       func(ctx context.Context, req store.RecordActivityRequest) error
+	GetUserActivityLogFunc   func(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error)
+	GetPublicActivityLogFunc func(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error)
+}
+
+// RecordActivity calls RecordActivityFunc
+func (m *ActivityLogStore) RecordActivity(ctx context.Context, req store.RecordActivityRequest) error {
+	if m.RecordActivityFunc == nil {
+		panic("mock: ActivityLogStore.RecordActivity called but RecordActivityFunc is not set")
+	}
+	return m.RecordActivityFunc(ctx, req)
+}
+
+// GetUserActivityLog calls GetUserActivityLogFunc
+func (m *ActivityLogStore) GetUserActivityLog(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error) {
+	if m.GetUserActivityLogFunc == nil {
+		panic("mock: ActivityLogStore.GetUserActivityLog called but GetUserActivityLogFunc is not set")
+	}
+	return m.GetUserActivityLogFunc(ctx, userID, page, pageSize)
+}
+
+// GetPublicActivityLog calls GetPublicActivityLogFunc
+func (m *ActivityLogStore) GetPublicActivityLog(ctx context.Context, userID string, page, pageSize int) ([]store.ActivityLog, int, error) {
+	if m.GetPublicActivityLogFunc == nil {
+		panic("mock: ActivityLogStore.GetPublicActivityLog called but GetPublicActivityLogFunc is not set")
+	}
+	return m.GetPublicActivityLogFunc(ctx, userID, page, pageSize)
+}
+
+var _ store.ActivityLogStoreInterface = (*ActivityLogStore)(nil)
@@ -0,0 +1,95 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// AdminStore is a stub store.AdminStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type AdminStore struct {
+	CreateAdminFunc            func(ctx context.Context, req store.CreateAdminRequest) (*store.Admin, error)
+	GetAdminByIDFunc           func(ctx context.Context, adminID string) (*store.Admin, error)
+	GetAdminByUsernameFunc     func(ctx context.Context, username string) (*store.Admin, error)
+	VerifyAdminPasswordFunc    func(ctx context.Context, username, password string) (bool, error)
+	UpdateAdminPasswordFunc    func(ctx context.Context, adminID, newHash string) error
+	UpdateAdminPermissionsFunc func(ctx context.Context, adminID string, permissions []string) (*store.Admin, error)
+	SetTOTPSecretFunc          func(ctx context.Context, adminID, secret string) error
+	GetTOTPSecretFunc          func(ctx context.Context, adminID string) (string, bool, error)
+	EnableTOTPFunc             func(ctx context.Context, adminID string) error
+}
+
+// CreateAdmin calls CreateAdminFunc
+func (m *AdminStore) CreateAdmin(ctx context.Context, req store.CreateAdminRequest) (*store.Admin, error) {
+	if m.CreateAdminFunc == nil {
+		panic("mock: AdminStore.CreateAdmin called but CreateAdminFunc is not set")
+	}
+	return m.CreateAdminFunc(ctx, req)
+}
+
+// GetAdminByID calls GetAdminByIDFunc
+func (m *AdminStore) GetAdminByID(ctx context.Context, adminID string) (*store.Admin, error) {
+	if m.GetAdminByIDFunc == nil {
+		panic("mock: AdminStore.GetAdminByID called but GetAdminByIDFunc is not set")
+	}
+	return m.GetAdminByIDFunc(ctx, adminID)
+}
+
+// GetAdminByUsername calls GetAdminByUsernameFunc
+func (m *AdminStore) GetAdminByUsername(ctx context.Context, username string) (*store.Admin, error) {
+	if m.GetAdminByUsernameFunc == nil {
+		panic("mock: AdminStore.GetAdminByUsername called but GetAdminByUsernameFunc is not set")
+	}
+	return m.GetAdminByUsernameFunc(ctx, username)
+}
+
+// VerifyAdminPassword calls VerifyAdminPasswordFunc
+func (m *AdminStore) VerifyAdminPassword(ctx context.Context, username, password string) (bool, error) {
+	if m.VerifyAdminPasswordFunc == nil {
+		panic("mock: AdminStore.VerifyAdminPassword called but VerifyAdminPasswordFunc is not set")
+	}
+	return m.VerifyAdminPasswordFunc(ctx, username, password)
+}
+
+// UpdateAdminPassword calls UpdateAdminPasswordFunc
+func (m *AdminStore) UpdateAdminPassword(ctx context.Context, adminID, newHash string) error {
+	if m.UpdateAdminPasswordFunc == nil {
+		panic("mock: AdminStore.UpdateAdminPassword called but UpdateAdminPasswordFunc is not set")
+	}
+	return m.UpdateAdminPasswordFunc(ctx, adminID, newHash)
+}
+
+// UpdateAdminPermissions calls UpdateAdminPermissionsFunc
+func (m *AdminStore) UpdateAdminPermissions(ctx context.Context, adminID string, permissions []string) (*store.Admin, error) {
+	if m.UpdateAdminPermissionsFunc == nil {
+		panic("mock: AdminStore.UpdateAdminPermissions called but UpdateAdminPermissionsFunc is not set")
+	}
+	return m.UpdateAdminPermissionsFunc(ctx, adminID, permissions)
+}
+
+// SetTOTPSecret calls SetTOTPSecretFunc
+func (m *AdminStore) SetTOTPSecret(ctx context.Context, adminID, secret string) error {
+	if m.SetTOTPSecretFunc == nil {
+		panic("mock: AdminStore.SetTOTPSecret called but SetTOTPSecretFunc is not set")
+	}
+	return m.SetTOTPSecretFunc(ctx, adminID, secret)
+}
+
+// GetTOTPSecret calls GetTOTPSecretFunc
+func (m *AdminStore) GetTOTPSecret(ctx context.Context, adminID string) (string, bool, error) {
+	if m.GetTOTPSecretFunc == nil {
+		panic("mock: AdminStore.GetTOTPSecret called but GetTOTPSecretFunc is not set")
+	}
+	return m.GetTOTPSecretFunc(ctx, adminID)
+}
+
+// EnableTOTP calls EnableTOTPFunc
+func (m *AdminStore) EnableTOTP(ctx context.Context, adminID string) error {
+	if m.EnableTOTPFunc == nil {
+		panic("mock: AdminStore.EnableTOTP called but EnableTOTPFunc is not set")
+	}
+	return m.EnableTOTPFunc(ctx, adminID)
+}
+
+var _ store.AdminStoreInterface = (*AdminStore)(nil)
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// AnnouncementStore is a stub store.AnnouncementStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type AnnouncementStore struct {
+	CreateAnnouncementFunc func(ctx context.Context, req store.CreateAnnouncementRequest) (*store.Announcement, error)
+	GetAnnouncementsFunc   func(ctx context.Context, limit, offset int) ([]store.Announcement, int, error)
+}
+
+// CreateAnnouncement calls CreateAnnouncementFunc
+func (m *AnnouncementStore) CreateAnnouncement(ctx context.Context, req store.CreateAnnouncementRequest) (*store.Announcement, error) {
+	if m.CreateAnnouncementFunc == nil {
+		panic("mock: AnnouncementStore.CreateAnnouncement called but CreateAnnouncementFunc is not set")
+	}
+	return m.CreateAnnouncementFunc(ctx, req)
+}
+
+// GetAnnouncements calls GetAnnouncementsFunc
+func (m *AnnouncementStore) GetAnnouncements(ctx context.Context, limit, offset int) ([]store.Announcement, int, error) {
+	if m.GetAnnouncementsFunc == nil {
+		panic("mock: AnnouncementStore.GetAnnouncements called but GetAnnouncementsFunc is not set")
+	}
+	return m.GetAnnouncementsFunc(ctx, limit, offset)
+}
+
+var _ store.AnnouncementStoreInterface = (*AnnouncementStore)(nil)
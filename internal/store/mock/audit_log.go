@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// AuditLogStore is a stub store.AuditLogStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type AuditLogStore struct {
+	RecordFunc func(ctx context.Context, adminID, action string, details interface{}) error
+}
+
+// Record calls RecordFunc
+func (m *AuditLogStore) Record(ctx context.Context, adminID, action string, details interface{}) error {
+	if m.RecordFunc == nil {
+		panic("mock: AuditLogStore.Record called but RecordFunc is not set")
+	}
+	return m.RecordFunc(ctx, adminID, action, details)
+}
+
+var _ store.AuditLogStoreInterface = (*AuditLogStore)(nil)
@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// BadgeStore is a stub store.BadgeStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type BadgeStore struct {
+	CreateBadgeFunc         func(ctx context.Context, req store.CreateBadgeRequest) (*store.Badge, error)
+	GetBadgeByIDFunc        func(ctx context.Context, badgeID string) (*store.Badge, error)
+	GetUserBadgesFunc       func(ctx context.Context, userID string) ([]store.UserBadge, error)
+	AwardBadgeFunc          func(ctx context.Context, userID, badgeID string) error
+	CheckAndAwardBadgesFunc func(ctx context.Context, userID string, userXP, userLevel int) error
+	GetAllBadgesFunc        func(ctx context.Context) ([]store.Badge, error)
+	ListBadgesFunc          func(ctx context.Context, filter store.BadgeFilter, userID string) ([]store.BadgeWithStatus, error)
+	GetBadgeWithStatusFunc  func(ctx context.Context, badgeID, userID string) (*store.BadgeWithStatus, error)
+}
+
+// CreateBadge calls CreateBadgeFunc
+func (m *BadgeStore) CreateBadge(ctx context.Context, req store.CreateBadgeRequest) (*store.Badge, error) {
+	if m.CreateBadgeFunc == nil {
+		panic("mock: BadgeStore.CreateBadge called but CreateBadgeFunc is not set")
+	}
+	return m.CreateBadgeFunc(ctx, req)
+}
+
+// GetBadgeByID calls GetBadgeByIDFunc
+func (m *BadgeStore) GetBadgeByID(ctx context.Context, badgeID string) (*store.Badge, error) {
+	if m.GetBadgeByIDFunc == nil {
+		panic("mock: BadgeStore.GetBadgeByID called but GetBadgeByIDFunc is not set")
+	}
+	return m.GetBadgeByIDFunc(ctx, badgeID)
+}
+
+// GetUserBadges calls GetUserBadgesFunc
+func (m *BadgeStore) GetUserBadges(ctx context.Context, userID string) ([]store.UserBadge, error) {
+	if m.GetUserBadgesFunc == nil {
+		panic("mock: BadgeStore.GetUserBadges called but GetUserBadgesFunc is not set")
+	}
+	return m.GetUserBadgesFunc(ctx, userID)
+}
+
+// AwardBadge calls AwardBadgeFunc
+func (m *BadgeStore) AwardBadge(ctx context.Context, userID, badgeID string) error {
+	if m.AwardBadgeFunc == nil {
+		panic("mock: BadgeStore.AwardBadge called but AwardBadgeFunc is not set")
+	}
+	return m.AwardBadgeFunc(ctx, userID, badgeID)
+}
+
+// CheckAndAwardBadges calls CheckAndAwardBadgesFunc
+func (m *BadgeStore) CheckAndAwardBadges(ctx context.Context, userID string, userXP, userLevel int) error {
+	if m.CheckAndAwardBadgesFunc == nil {
+		panic("mock: BadgeStore.CheckAndAwardBadges called but CheckAndAwardBadgesFunc is not set")
+	}
+	return m.CheckAndAwardBadgesFunc(ctx, userID, userXP, userLevel)
+}
+
+// GetAllBadges calls GetAllBadgesFunc
+func (m *BadgeStore) GetAllBadges(ctx context.Context) ([]store.Badge, error) {
+	if m.GetAllBadgesFunc == nil {
+		panic("mock: BadgeStore.GetAllBadges called but GetAllBadgesFunc is not set")
+	}
+	return m.GetAllBadgesFunc(ctx)
+}
+
+// ListBadges calls ListBadgesFunc
+func (m *BadgeStore) ListBadges(ctx context.Context, filter store.BadgeFilter, userID string) ([]store.BadgeWithStatus, error) {
+	if m.ListBadgesFunc == nil {
+		panic("mock: BadgeStore.ListBadges called but ListBadgesFunc is not set")
+	}
+	return m.ListBadgesFunc(ctx, filter, userID)
+}
+
+// GetBadgeWithStatus calls GetBadgeWithStatusFunc
+func (m *BadgeStore) GetBadgeWithStatus(ctx context.Context, badgeID, userID string) (*store.BadgeWithStatus, error) {
+	if m.GetBadgeWithStatusFunc == nil {
+		panic("mock: BadgeStore.GetBadgeWithStatus called but GetBadgeWithStatusFunc is not set")
+	}
+	return m.GetBadgeWithStatusFunc(ctx, badgeID, userID)
+}
+
+var _ store.BadgeStoreInterface = (*BadgeStore)(nil)
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// BlockedWordStore is a stub store.BlockedWordStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type BlockedWordStore struct {
+	GetBlockedWordsFunc    func(ctx context.Context) ([]store.BlockedWord, error)
+	GetBlockedWordListFunc func(ctx context.Context) ([]string, error)
+	AddBlockedWordFunc     func(ctx context.Context, word, adminID string) (*store.BlockedWord, error)
+	DeleteBlockedWordFunc  func(ctx context.Context, id string) error
+}
+
+// GetBlockedWords calls GetBlockedWordsFunc
+func (m *BlockedWordStore) GetBlockedWords(ctx context.Context) ([]store.BlockedWord, error) {
+	if m.GetBlockedWordsFunc == nil {
+		panic("mock: BlockedWordStore.GetBlockedWords called but GetBlockedWordsFunc is not set")
+	}
+	return m.GetBlockedWordsFunc(ctx)
+}
+
+// GetBlockedWordList calls GetBlockedWordListFunc
+func (m *BlockedWordStore) GetBlockedWordList(ctx context.Context) ([]string, error) {
+	if m.GetBlockedWordListFunc == nil {
+		panic("mock: BlockedWordStore.GetBlockedWordList called but GetBlockedWordListFunc is not set")
+	}
+	return m.GetBlockedWordListFunc(ctx)
+}
+
+// AddBlockedWord calls AddBlockedWordFunc
+func (m *BlockedWordStore) AddBlockedWord(ctx context.Context, word, adminID string) (*store.BlockedWord, error) {
+	if m.AddBlockedWordFunc == nil {
+		panic("mock: BlockedWordStore.AddBlockedWord called but AddBlockedWordFunc is not set")
+	}
+	return m.AddBlockedWordFunc(ctx, word, adminID)
+}
+
+// DeleteBlockedWord calls DeleteBlockedWordFunc
+func (m *BlockedWordStore) DeleteBlockedWord(ctx context.Context, id string) error {
+	if m.DeleteBlockedWordFunc == nil {
+		panic("mock: BlockedWordStore.DeleteBlockedWord called but DeleteBlockedWordFunc is not set")
+	}
+	return m.DeleteBlockedWordFunc(ctx, id)
+}
+
+var _ store.BlockedWordStoreInterface = (*BlockedWordStore)(nil)
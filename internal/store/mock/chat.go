@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ChatStore is a stub store.ChatStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type ChatStore struct {
+	GetRoomsForUserFunc func(ctx context.Context, userID string) ([]store.ChatRoom, error)
+	GetRoomByIDFunc     func(ctx context.Context, roomID string) (*store.ChatRoom, error)
+	GetMessagesFunc     func(ctx context.Context, roomID string, limit int, cursor *store.ChatMessageCursor) ([]store.ChatMessage, *store.ChatMessageCursor, error)
+	CreateMessageFunc   func(ctx context.Context, roomID, userID, content string) (*store.ChatMessage, error)
+}
+
+// GetRoomsForUser calls GetRoomsForUserFunc
+func (m *ChatStore) GetRoomsForUser(ctx context.Context, userID string) ([]store.ChatRoom, error) {
+	if m.GetRoomsForUserFunc == nil {
+		panic("mock: ChatStore.GetRoomsForUser called but GetRoomsForUserFunc is not set")
+	}
+	return m.GetRoomsForUserFunc(ctx, userID)
+}
+
+// GetRoomByID calls GetRoomByIDFunc
+func (m *ChatStore) GetRoomByID(ctx context.Context, roomID string) (*store.ChatRoom, error) {
+	if m.GetRoomByIDFunc == nil {
+		panic("mock: ChatStore.GetRoomByID called but GetRoomByIDFunc is not set")
+	}
+	return m.GetRoomByIDFunc(ctx, roomID)
+}
+
+// GetMessages calls GetMessagesFunc
+func (m *ChatStore) GetMessages(ctx context.Context, roomID string, limit int, cursor *store.ChatMessageCursor) ([]store.ChatMessage, *store.ChatMessageCursor, error) {
+	if m.GetMessagesFunc == nil {
+		panic("mock: ChatStore.GetMessages called but GetMessagesFunc is not set")
+	}
+	return m.GetMessagesFunc(ctx, roomID, limit, cursor)
+}
+
+// CreateMessage calls CreateMessageFunc
+func (m *ChatStore) CreateMessage(ctx context.Context, roomID, userID, content string) (*store.ChatMessage, error) {
+	if m.CreateMessageFunc == nil {
+		panic("mock: ChatStore.CreateMessage called but CreateMessageFunc is not set")
+	}
+	return m.CreateMessageFunc(ctx, roomID, userID, content)
+}
+
+var _ store.ChatStoreInterface = (*ChatStore)(nil)
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// CoinStore is a stub store.CoinStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type CoinStore struct {
+	AwardCoinsFunc         func(ctx context.Context, userID string, amount int, reason string) (int, error)
+	GetBalanceFunc         func(ctx context.Context, userID string) (int, error)
+	DeductFunc             func(ctx context.Context, userID string, amount int, reason string) error
+	GetCoinHistoryFunc     func(ctx context.Context, userID string, page, pageSize int) ([]store.CoinLog, int, error)
+	ExchangeCoinsForXPFunc func(ctx context.Context, userID string, coins, xpPerCoin int) (*store.CoinExchange, error)
+}
+
+// AwardCoins calls AwardCoinsFunc
+func (m *CoinStore) AwardCoins(ctx context.Context, userID string, amount int, reason string) (int, error) {
+	if m.AwardCoinsFunc == nil {
+		panic("mock: CoinStore.AwardCoins called but AwardCoinsFunc is not set")
+	}
+	return m.AwardCoinsFunc(ctx, userID, amount, reason)
+}
+
+// GetBalance calls GetBalanceFunc
+func (m *CoinStore) GetBalance(ctx context.Context, userID string) (int, error) {
+	if m.GetBalanceFunc == nil {
+		panic("mock: CoinStore.GetBalance called but GetBalanceFunc is not set")
+	}
+	return m.GetBalanceFunc(ctx, userID)
+}
+
+// Deduct calls DeductFunc
+func (m *CoinStore) Deduct(ctx context.Context, userID string, amount int, reason string) error {
+	if m.DeductFunc == nil {
+		panic("mock: CoinStore.Deduct called but DeductFunc is not set")
+	}
+	return m.DeductFunc(ctx, userID, amount, reason)
+}
+
+// GetCoinHistory calls GetCoinHistoryFunc
+func (m *CoinStore) GetCoinHistory(ctx context.Context, userID string, page, pageSize int) ([]store.CoinLog, int, error) {
+	if m.GetCoinHistoryFunc == nil {
+		panic("mock: CoinStore.GetCoinHistory called but GetCoinHistoryFunc is not set")
+	}
+	return m.GetCoinHistoryFunc(ctx, userID, page, pageSize)
+}
+
+// ExchangeCoinsForXP calls ExchangeCoinsForXPFunc
+func (m *CoinStore) ExchangeCoinsForXP(ctx context.Context, userID string, coins, xpPerCoin int) (*store.CoinExchange, error) {
+	if m.ExchangeCoinsForXPFunc == nil {
+		panic("mock: CoinStore.ExchangeCoinsForXP called but ExchangeCoinsForXPFunc is not set")
+	}
+	return m.ExchangeCoinsForXPFunc(ctx, userID, coins, xpPerCoin)
+}
+
+var _ store.CoinStoreInterface = (*CoinStore)(nil)
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// CollegeStore is a stub store.CollegeStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type CollegeStore struct {
+	GetCollegesByStateIDFunc func(ctx context.Context, stateID string) ([]store.College, error)
+	GetCollegeByIDFunc       func(ctx context.Context, collegeID string) (*store.College, error)
+	CreateCollegeFunc        func(ctx context.Context, req store.CreateCollegeRequest) (*store.College, error)
+}
+
+// GetCollegesByStateID calls GetCollegesByStateIDFunc
+func (m *CollegeStore) GetCollegesByStateID(ctx context.Context, stateID string) ([]store.College, error) {
+	if m.GetCollegesByStateIDFunc == nil {
+		panic("mock: CollegeStore.GetCollegesByStateID called but GetCollegesByStateIDFunc is not set")
+	}
+	return m.GetCollegesByStateIDFunc(ctx, stateID)
+}
+
+// GetCollegeByID calls GetCollegeByIDFunc
+func (m *CollegeStore) GetCollegeByID(ctx context.Context, collegeID string) (*store.College, error) {
+	if m.GetCollegeByIDFunc == nil {
+		panic("mock: CollegeStore.GetCollegeByID called but GetCollegeByIDFunc is not set")
+	}
+	return m.GetCollegeByIDFunc(ctx, collegeID)
+}
+
+// CreateCollege calls CreateCollegeFunc
+func (m *CollegeStore) CreateCollege(ctx context.Context, req store.CreateCollegeRequest) (*store.College, error) {
+	if m.CreateCollegeFunc == nil {
+		panic("mock: CollegeStore.CreateCollege called but CreateCollegeFunc is not set")
+	}
+	return m.CreateCollegeFunc(ctx, req)
+}
+
+var _ store.CollegeStoreInterface = (*CollegeStore)(nil)
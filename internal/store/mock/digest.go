@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// DigestStore is a stub store.DigestStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type DigestStore struct {
+	GetUnsentSinceFunc      func(ctx context.Context, userID string, since time.Time) ([]store.Notification, error)
+	GetDigestRecipientsFunc func(ctx context.Context) ([]store.DigestRecipient, error)
+	MarkDigestSentFunc      func(ctx context.Context, userID string, sentAt time.Time) error
+	SetDigestEnabledFunc    func(ctx context.Context, userID string, enabled bool) error
+}
+
+// GetUnsentSince calls GetUnsentSinceFunc
+func (m *DigestStore) GetUnsentSince(ctx context.Context, userID string, since time.Time) ([]store.Notification, error) {
+	if m.GetUnsentSinceFunc == nil {
+		panic("mock: DigestStore.GetUnsentSince called but GetUnsentSinceFunc is not set")
+	}
+	return m.GetUnsentSinceFunc(ctx, userID, since)
+}
+
+// GetDigestRecipients calls GetDigestRecipientsFunc
+func (m *DigestStore) GetDigestRecipients(ctx context.Context) ([]store.DigestRecipient, error) {
+	if m.GetDigestRecipientsFunc == nil {
+		panic("mock: DigestStore.GetDigestRecipients called but GetDigestRecipientsFunc is not set")
+	}
+	return m.GetDigestRecipientsFunc(ctx)
+}
+
+// MarkDigestSent calls MarkDigestSentFunc
+func (m *DigestStore) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	if m.MarkDigestSentFunc == nil {
+		panic("mock: DigestStore.MarkDigestSent called but MarkDigestSentFunc is not set")
+	}
+	return m.MarkDigestSentFunc(ctx, userID, sentAt)
+}
+
+// SetDigestEnabled calls SetDigestEnabledFunc
+func (m *DigestStore) SetDigestEnabled(ctx context.Context, userID string, enabled bool) error {
+	if m.SetDigestEnabledFunc == nil {
+		panic("mock: DigestStore.SetDigestEnabled called but SetDigestEnabledFunc is not set")
+	}
+	return m.SetDigestEnabledFunc(ctx, userID, enabled)
+}
+
+var _ store.DigestStoreInterface = (*DigestStore)(nil)
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// EmailVerificationStore is a stub store.EmailVerificationStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type EmailVerificationStore struct {
+	CreateTokenFunc     func(ctx context.Context, userID string) (string, error)
+	VerifyTokenFunc     func(ctx context.Context, token string) (string, error)
+	IsEmailVerifiedFunc func(ctx context.Context, userID string) (bool, error)
+}
+
+// CreateToken calls CreateTokenFunc
+func (m *EmailVerificationStore) CreateToken(ctx context.Context, userID string) (string, error) {
+	if m.CreateTokenFunc == nil {
+		panic("mock: EmailVerificationStore.CreateToken called but CreateTokenFunc is not set")
+	}
+	return m.CreateTokenFunc(ctx, userID)
+}
+
+// VerifyToken calls VerifyTokenFunc
+func (m *EmailVerificationStore) VerifyToken(ctx context.Context, token string) (string, error) {
+	if m.VerifyTokenFunc == nil {
+		panic("mock: EmailVerificationStore.VerifyToken called but VerifyTokenFunc is not set")
+	}
+	return m.VerifyTokenFunc(ctx, token)
+}
+
+// IsEmailVerified calls IsEmailVerifiedFunc
+func (m *EmailVerificationStore) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	if m.IsEmailVerifiedFunc == nil {
+		panic("mock: EmailVerificationStore.IsEmailVerified called but IsEmailVerifiedFunc is not set")
+	}
+	return m.IsEmailVerifiedFunc(ctx, userID)
+}
+
+var _ store.EmailVerificationStoreInterface = (*EmailVerificationStore)(nil)
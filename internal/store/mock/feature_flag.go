@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// FeatureFlagStore is a stub store.FeatureFlagStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type FeatureFlagStore struct {
+	IsEnabledFunc         func(ctx context.Context, flagName, userID string) (bool, error)
+	GetFeatureFlagsFunc   func(ctx context.Context) ([]store.FeatureFlag, error)
+	UpsertFeatureFlagFunc func(ctx context.Context, flagName string, req store.UpdateFeatureFlagRequest) (*store.FeatureFlag, error)
+}
+
+// IsEnabled calls IsEnabledFunc
+func (m *FeatureFlagStore) IsEnabled(ctx context.Context, flagName, userID string) (bool, error) {
+	if m.IsEnabledFunc == nil {
+		panic("mock: FeatureFlagStore.IsEnabled called but IsEnabledFunc is not set")
+	}
+	return m.IsEnabledFunc(ctx, flagName, userID)
+}
+
+// GetFeatureFlags calls GetFeatureFlagsFunc
+func (m *FeatureFlagStore) GetFeatureFlags(ctx context.Context) ([]store.FeatureFlag, error) {
+	if m.GetFeatureFlagsFunc == nil {
+		panic("mock: FeatureFlagStore.GetFeatureFlags called but GetFeatureFlagsFunc is not set")
+	}
+	return m.GetFeatureFlagsFunc(ctx)
+}
+
+// UpsertFeatureFlag calls UpsertFeatureFlagFunc
+func (m *FeatureFlagStore) UpsertFeatureFlag(ctx context.Context, flagName string, req store.UpdateFeatureFlagRequest) (*store.FeatureFlag, error) {
+	if m.UpsertFeatureFlagFunc == nil {
+		panic("mock: FeatureFlagStore.UpsertFeatureFlag called but UpsertFeatureFlagFunc is not set")
+	}
+	return m.UpsertFeatureFlagFunc(ctx, flagName, req)
+}
+
+var _ store.FeatureFlagStoreInterface = (*FeatureFlagStore)(nil)
@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// FeedStore is a stub store.FeedStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type FeedStore struct {
+	GetFeedFunc         func(ctx context.Context, opts store.GetFeedOptions) ([]store.FeedItem, int, error)
+	GetUserFeedFunc     func(ctx context.Context, userID string, page, pageSize int) ([]store.FeedItem, int, error)
+	CreateFeedEntryFunc func(ctx context.Context, submissionID, userID, taskID string) error
+	AddReactionFunc     func(ctx context.Context, feedID, userID, reaction string) error
+	RemoveReactionFunc  func(ctx context.Context, feedID, userID string) error
+	AddCommentFunc      func(ctx context.Context, feedID, userID, comment string) (*store.FeedComment, error)
+	GetCommentsFunc     func(ctx context.Context, feedID string, limit, offset int) ([]store.FeedComment, error)
+	CountCommentsFunc   func(ctx context.Context, feedID string) (int, error)
+	FeedEntryExistsFunc func(ctx context.Context, feedID string) (bool, error)
+	HideFeedEntryFunc   func(ctx context.Context, feedID, userID string) error
+}
+
+// GetFeed calls GetFeedFunc
+func (m *FeedStore) GetFeed(ctx context.Context, opts store.GetFeedOptions) ([]store.FeedItem, int, error) {
+	if m.GetFeedFunc == nil {
+		panic("mock: FeedStore.GetFeed called but GetFeedFunc is not set")
+	}
+	return m.GetFeedFunc(ctx, opts)
+}
+
+// GetUserFeed calls GetUserFeedFunc
+func (m *FeedStore) GetUserFeed(ctx context.Context, userID string, page, pageSize int) ([]store.FeedItem, int, error) {
+	if m.GetUserFeedFunc == nil {
+		panic("mock: FeedStore.GetUserFeed called but GetUserFeedFunc is not set")
+	}
+	return m.GetUserFeedFunc(ctx, userID, page, pageSize)
+}
+
+// CreateFeedEntry calls CreateFeedEntryFunc
+func (m *FeedStore) CreateFeedEntry(ctx context.Context, submissionID, userID, taskID string) error {
+	if m.CreateFeedEntryFunc == nil {
+		panic("mock: FeedStore.CreateFeedEntry called but CreateFeedEntryFunc is not set")
+	}
+	return m.CreateFeedEntryFunc(ctx, submissionID, userID, taskID)
+}
+
+// AddReaction calls AddReactionFunc
+func (m *FeedStore) AddReaction(ctx context.Context, feedID, userID, reaction string) error {
+	if m.AddReactionFunc == nil {
+		panic("mock: FeedStore.AddReaction called but AddReactionFunc is not set")
+	}
+	return m.AddReactionFunc(ctx, feedID, userID, reaction)
+}
+
+// RemoveReaction calls RemoveReactionFunc
+func (m *FeedStore) RemoveReaction(ctx context.Context, feedID, userID string) error {
+	if m.RemoveReactionFunc == nil {
+		panic("mock: FeedStore.RemoveReaction called but RemoveReactionFunc is not set")
+	}
+	return m.RemoveReactionFunc(ctx, feedID, userID)
+}
+
+// AddComment calls AddCommentFunc
+func (m *FeedStore) AddComment(ctx context.Context, feedID, userID, comment string) (*store.FeedComment, error) {
+	if m.AddCommentFunc == nil {
+		panic("mock: FeedStore.AddComment called but AddCommentFunc is not set")
+	}
+	return m.AddCommentFunc(ctx, feedID, userID, comment)
+}
+
+// GetComments calls GetCommentsFunc
+func (m *FeedStore) GetComments(ctx context.Context, feedID string, limit, offset int) ([]store.FeedComment, error) {
+	if m.GetCommentsFunc == nil {
+		panic("mock: FeedStore.GetComments called but GetCommentsFunc is not set")
+	}
+	return m.GetCommentsFunc(ctx, feedID, limit, offset)
+}
+
+// CountComments calls CountCommentsFunc
+func (m *FeedStore) CountComments(ctx context.Context, feedID string) (int, error) {
+	if m.CountCommentsFunc == nil {
+		panic("mock: FeedStore.CountComments called but CountCommentsFunc is not set")
+	}
+	return m.CountCommentsFunc(ctx, feedID)
+}
+
+// FeedEntryExists calls FeedEntryExistsFunc
+func (m *FeedStore) FeedEntryExists(ctx context.Context, feedID string) (bool, error) {
+	if m.FeedEntryExistsFunc == nil {
+		panic("mock: FeedStore.FeedEntryExists called but FeedEntryExistsFunc is not set")
+	}
+	return m.FeedEntryExistsFunc(ctx, feedID)
+}
+
+// HideFeedEntry calls HideFeedEntryFunc
+func (m *FeedStore) HideFeedEntry(ctx context.Context, feedID, userID string) error {
+	if m.HideFeedEntryFunc == nil {
+		panic("mock: FeedStore.HideFeedEntry called but HideFeedEntryFunc is not set")
+	}
+	return m.HideFeedEntryFunc(ctx, feedID, userID)
+}
+
+var _ store.FeedStoreInterface = (*FeedStore)(nil)
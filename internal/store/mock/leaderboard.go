@@ -0,0 +1,113 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// LeaderboardStore is a stub store.LeaderboardStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type LeaderboardStore struct {
+	GetPanIndiaLeaderboardFunc          func(ctx context.Context, limit, offset int, period string) ([]store.LeaderboardEntry, error)
+	GetStateLeaderboardFunc             func(ctx context.Context, stateID string, limit, offset int, period string) ([]store.LeaderboardEntry, error)
+	GetCollegeLeaderboardFunc           func(ctx context.Context, collegeID string, limit, offset int, period string) ([]store.LeaderboardEntry, error)
+	GetUserRankFunc                     func(ctx context.Context, userID string) (int, error)
+	GetUserRanksFunc                    func(ctx context.Context, userID string) (*store.UserRanks, error)
+	GetTopUserPerStateFunc              func(ctx context.Context, limit int, period string) ([]store.StateTopEntry, error)
+	GetCollegeRankingsFunc              func(ctx context.Context, limit, offset int, period string) ([]store.CollegeRankEntry, error)
+	GetStateRankingsFunc                func(ctx context.Context, limit, offset int, period string) ([]store.StateRankEntry, error)
+	GetLeaderboardAroundUserFunc        func(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error)
+	GetStateLeaderboardAroundUserFunc   func(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error)
+	GetCollegeLeaderboardAroundUserFunc func(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error)
+}
+
+// GetPanIndiaLeaderboard calls GetPanIndiaLeaderboardFunc
+func (m *LeaderboardStore) GetPanIndiaLeaderboard(ctx context.Context, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+	if m.GetPanIndiaLeaderboardFunc == nil {
+		panic("mock: LeaderboardStore.GetPanIndiaLeaderboard called but GetPanIndiaLeaderboardFunc is not set")
+	}
+	return m.GetPanIndiaLeaderboardFunc(ctx, limit, offset, period)
+}
+
+// GetStateLeaderboard calls GetStateLeaderboardFunc
+func (m *LeaderboardStore) GetStateLeaderboard(ctx context.Context, stateID string, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+	if m.GetStateLeaderboardFunc == nil {
+		panic("mock: LeaderboardStore.GetStateLeaderboard called but GetStateLeaderboardFunc is not set")
+	}
+	return m.GetStateLeaderboardFunc(ctx, stateID, limit, offset, period)
+}
+
+// GetCollegeLeaderboard calls GetCollegeLeaderboardFunc
+func (m *LeaderboardStore) GetCollegeLeaderboard(ctx context.Context, collegeID string, limit, offset int, period string) ([]store.LeaderboardEntry, error) {
+	if m.GetCollegeLeaderboardFunc == nil {
+		panic("mock: LeaderboardStore.GetCollegeLeaderboard called but GetCollegeLeaderboardFunc is not set")
+	}
+	return m.GetCollegeLeaderboardFunc(ctx, collegeID, limit, offset, period)
+}
+
+// GetUserRank calls GetUserRankFunc
+func (m *LeaderboardStore) GetUserRank(ctx context.Context, userID string) (int, error) {
+	if m.GetUserRankFunc == nil {
+		panic("mock: LeaderboardStore.GetUserRank called but GetUserRankFunc is not set")
+	}
+	return m.GetUserRankFunc(ctx, userID)
+}
+
+// GetUserRanks calls GetUserRanksFunc
+func (m *LeaderboardStore) GetUserRanks(ctx context.Context, userID string) (*store.UserRanks, error) {
+	if m.GetUserRanksFunc == nil {
+		panic("mock: LeaderboardStore.GetUserRanks called but GetUserRanksFunc is not set")
+	}
+	return m.GetUserRanksFunc(ctx, userID)
+}
+
+// GetTopUserPerState calls GetTopUserPerStateFunc
+func (m *LeaderboardStore) GetTopUserPerState(ctx context.Context, limit int, period string) ([]store.StateTopEntry, error) {
+	if m.GetTopUserPerStateFunc == nil {
+		panic("mock: LeaderboardStore.GetTopUserPerState called but GetTopUserPerStateFunc is not set")
+	}
+	return m.GetTopUserPerStateFunc(ctx, limit, period)
+}
+
+// GetCollegeRankings calls GetCollegeRankingsFunc
+func (m *LeaderboardStore) GetCollegeRankings(ctx context.Context, limit, offset int, period string) ([]store.CollegeRankEntry, error) {
+	if m.GetCollegeRankingsFunc == nil {
+		panic("mock: LeaderboardStore.GetCollegeRankings called but GetCollegeRankingsFunc is not set")
+	}
+	return m.GetCollegeRankingsFunc(ctx, limit, offset, period)
+}
+
+// GetStateRankings calls GetStateRankingsFunc
+func (m *LeaderboardStore) GetStateRankings(ctx context.Context, limit, offset int, period string) ([]store.StateRankEntry, error) {
+	if m.GetStateRankingsFunc == nil {
+		panic("mock: LeaderboardStore.GetStateRankings called but GetStateRankingsFunc is not set")
+	}
+	return m.GetStateRankingsFunc(ctx, limit, offset, period)
+}
+
+// GetLeaderboardAroundUser calls GetLeaderboardAroundUserFunc
+func (m *LeaderboardStore) GetLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error) {
+	if m.GetLeaderboardAroundUserFunc == nil {
+		panic("mock: LeaderboardStore.GetLeaderboardAroundUser called but GetLeaderboardAroundUserFunc is not set")
+	}
+	return m.GetLeaderboardAroundUserFunc(ctx, userID, radius, period)
+}
+
+// GetStateLeaderboardAroundUser calls GetStateLeaderboardAroundUserFunc
+func (m *LeaderboardStore) GetStateLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error) {
+	if m.GetStateLeaderboardAroundUserFunc == nil {
+		panic("mock: LeaderboardStore.GetStateLeaderboardAroundUser called but GetStateLeaderboardAroundUserFunc is not set")
+	}
+	return m.GetStateLeaderboardAroundUserFunc(ctx, userID, radius, period)
+}
+
+// GetCollegeLeaderboardAroundUser calls GetCollegeLeaderboardAroundUserFunc
+func (m *LeaderboardStore) GetCollegeLeaderboardAroundUser(ctx context.Context, userID string, radius int, period string) ([]store.LeaderboardEntry, int, error) {
+	if m.GetCollegeLeaderboardAroundUserFunc == nil {
+		panic("mock: LeaderboardStore.GetCollegeLeaderboardAroundUser called but GetCollegeLeaderboardAroundUserFunc is not set")
+	}
+	return m.GetCollegeLeaderboardAroundUserFunc(ctx, userID, radius, period)
+}
+
+var _ store.LeaderboardStoreInterface = (*LeaderboardStore)(nil)
@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// LevelStore is a stub store.LevelStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type LevelStore struct {
+	GetLevelForXPFunc          func(ctx context.Context, xp int) (int, error)
+	GetLevelThresholdsFunc     func(ctx context.Context) ([]store.LevelThreshold, error)
+	ReplaceLevelThresholdsFunc func(ctx context.Context, thresholds []store.LevelThreshold) error
+}
+
+// GetLevelForXP calls GetLevelForXPFunc
+func (m *LevelStore) GetLevelForXP(ctx context.Context, xp int) (int, error) {
+	if m.GetLevelForXPFunc == nil {
+		panic("mock: LevelStore.GetLevelForXP called but GetLevelForXPFunc is not set")
+	}
+	return m.GetLevelForXPFunc(ctx, xp)
+}
+
+// GetLevelThresholds calls GetLevelThresholdsFunc
+func (m *LevelStore) GetLevelThresholds(ctx context.Context) ([]store.LevelThreshold, error) {
+	if m.GetLevelThresholdsFunc == nil {
+		panic("mock: LevelStore.GetLevelThresholds called but GetLevelThresholdsFunc is not set")
+	}
+	return m.GetLevelThresholdsFunc(ctx)
+}
+
+// ReplaceLevelThresholds calls ReplaceLevelThresholdsFunc
+func (m *LevelStore) ReplaceLevelThresholds(ctx context.Context, thresholds []store.LevelThreshold) error {
+	if m.ReplaceLevelThresholdsFunc == nil {
+		panic("mock: LevelStore.ReplaceLevelThresholds called but ReplaceLevelThresholdsFunc is not set")
+	}
+	return m.ReplaceLevelThresholdsFunc(ctx, thresholds)
+}
+
+var _ store.LevelStoreInterface = (*LevelStore)(nil)
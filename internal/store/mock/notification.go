@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// NotificationStore is a stub store.NotificationStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type NotificationStore struct {
+	CreateNotificationFunc func(ctx context.Context, userID, title, body, notificationType string, data interface{}) error
+	GetNotificationsFunc   func(ctx context.Context, userID string, limit, offset int) ([]store.Notification, int, int, error)
+	MarkReadFunc           func(ctx context.Context, userID, notificationID string) error
+	MarkAllReadFunc        func(ctx context.Context, userID string) (int64, error)
+}
+
+// CreateNotification calls CreateNotificationFunc
+func (m *NotificationStore) CreateNotification(ctx context.Context, userID, title, body, notificationType string, data interface{}) error {
+	if m.CreateNotificationFunc == nil {
+		panic("mock: NotificationStore.CreateNotification called but CreateNotificationFunc is not set")
+	}
+	return m.CreateNotificationFunc(ctx, userID, title, body, notificationType, data)
+}
+
+// GetNotifications calls GetNotificationsFunc
+func (m *NotificationStore) GetNotifications(ctx context.Context, userID string, limit, offset int) ([]store.Notification, int, int, error) {
+	if m.GetNotificationsFunc == nil {
+		panic("mock: NotificationStore.GetNotifications called but GetNotificationsFunc is not set")
+	}
+	return m.GetNotificationsFunc(ctx, userID, limit, offset)
+}
+
+// MarkRead calls MarkReadFunc
+func (m *NotificationStore) MarkRead(ctx context.Context, userID, notificationID string) error {
+	if m.MarkReadFunc == nil {
+		panic("mock: NotificationStore.MarkRead called but MarkReadFunc is not set")
+	}
+	return m.MarkReadFunc(ctx, userID, notificationID)
+}
+
+// MarkAllRead calls MarkAllReadFunc
+func (m *NotificationStore) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	if m.MarkAllReadFunc == nil {
+		panic("mock: NotificationStore.MarkAllRead called but MarkAllReadFunc is not set")
+	}
+	return m.MarkAllReadFunc(ctx, userID)
+}
+
+var _ store.NotificationStoreInterface = (*NotificationStore)(nil)
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// OnboardingStore is a stub store.OnboardingStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type OnboardingStore struct {
+	MarkStepFunc    func(ctx context.Context, userID, step string) (bool, error)
+	GetProgressFunc func(ctx context.Context, userID string) (*store.OnboardingProgress, error)
+}
+
+// MarkStep calls MarkStepFunc
+func (m *OnboardingStore) MarkStep(ctx context.Context, userID, step string) (bool, error) {
+	if m.MarkStepFunc == nil {
+		panic("mock: OnboardingStore.MarkStep called but MarkStepFunc is not set")
+	}
+	return m.MarkStepFunc(ctx, userID, step)
+}
+
+// GetProgress calls GetProgressFunc
+func (m *OnboardingStore) GetProgress(ctx context.Context, userID string) (*store.OnboardingProgress, error) {
+	if m.GetProgressFunc == nil {
+		panic("mock: OnboardingStore.GetProgress called but GetProgressFunc is not set")
+	}
+	return m.GetProgressFunc(ctx, userID)
+}
+
+var _ store.OnboardingStoreInterface = (*OnboardingStore)(nil)
@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// PasswordResetStore is a stub store.PasswordResetStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type PasswordResetStore struct {
+	CreateTokenFunc   func(ctx context.Context, userID string, ttl time.Duration) (string, error)
+	ResetPasswordFunc func(ctx context.Context, token, newPassword string) (string, error)
+}
+
+// CreateToken calls CreateTokenFunc
+func (m *PasswordResetStore) CreateToken(ctx context.Context, userID string, ttl time.Duration) (string, error) {
+	if m.CreateTokenFunc == nil {
+		panic("mock: PasswordResetStore.CreateToken called but CreateTokenFunc is not set")
+	}
+	return m.CreateTokenFunc(ctx, userID, ttl)
+}
+
+// ResetPassword calls ResetPasswordFunc
+func (m *PasswordResetStore) ResetPassword(ctx context.Context, token, newPassword string) (string, error) {
+	if m.ResetPasswordFunc == nil {
+		panic("mock: PasswordResetStore.ResetPassword called but ResetPasswordFunc is not set")
+	}
+	return m.ResetPasswordFunc(ctx, token, newPassword)
+}
+
+var _ store.PasswordResetStoreInterface = (*PasswordResetStore)(nil)
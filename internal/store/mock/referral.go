@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// ReferralStore is a stub store.ReferralStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type ReferralStore struct {
+	GetReferralCountFunc   func(ctx context.Context, userID string) (int, error)
+	GetReferralStatsFunc   func(ctx context.Context, userID string, limit, offset int) (*store.ReferralStats, error)
+	AwardReferralBonusFunc func(ctx context.Context, referredUserID string, xp int) (*store.XPLog, error)
+}
+
+// GetReferralCount calls GetReferralCountFunc
+func (m *ReferralStore) GetReferralCount(ctx context.Context, userID string) (int, error) {
+	if m.GetReferralCountFunc == nil {
+		panic("mock: ReferralStore.GetReferralCount called but GetReferralCountFunc is not set")
+	}
+	return m.GetReferralCountFunc(ctx, userID)
+}
+
+// GetReferralStats calls GetReferralStatsFunc
+func (m *ReferralStore) GetReferralStats(ctx context.Context, userID string, limit, offset int) (*store.ReferralStats, error) {
+	if m.GetReferralStatsFunc == nil {
+		panic("mock: ReferralStore.GetReferralStats called but GetReferralStatsFunc is not set")
+	}
+	return m.GetReferralStatsFunc(ctx, userID, limit, offset)
+}
+
+// AwardReferralBonus calls AwardReferralBonusFunc
+func (m *ReferralStore) AwardReferralBonus(ctx context.Context, referredUserID string, xp int) (*store.XPLog, error) {
+	if m.AwardReferralBonusFunc == nil {
+		panic("mock: ReferralStore.AwardReferralBonus called but AwardReferralBonusFunc is not set")
+	}
+	return m.AwardReferralBonusFunc(ctx, referredUserID, xp)
+}
+
+var _ store.ReferralStoreInterface = (*ReferralStore)(nil)
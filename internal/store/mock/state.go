@@ -0,0 +1,41 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// StateStore is a stub store.StateStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type StateStore struct {
+	GetAllStatesFunc func(ctx context.Context) ([]store.State, error)
+	GetStateByIDFunc func(ctx context.Context, stateID string) (*store.State, error)
+	CreateStateFunc  func(ctx context.Context, req store.CreateStateRequest) (*store.State, error)
+}
+
+// GetAllStates calls GetAllStatesFunc
+func (m *StateStore) GetAllStates(ctx context.Context) ([]store.State, error) {
+	if m.GetAllStatesFunc == nil {
+		panic("mock: StateStore.GetAllStates called but GetAllStatesFunc is not set")
+	}
+	return m.GetAllStatesFunc(ctx)
+}
+
+// GetStateByID calls GetStateByIDFunc
+func (m *StateStore) GetStateByID(ctx context.Context, stateID string) (*store.State, error) {
+	if m.GetStateByIDFunc == nil {
+		panic("mock: StateStore.GetStateByID called but GetStateByIDFunc is not set")
+	}
+	return m.GetStateByIDFunc(ctx, stateID)
+}
+
+// CreateState calls CreateStateFunc
+func (m *StateStore) CreateState(ctx context.Context, req store.CreateStateRequest) (*store.State, error) {
+	if m.CreateStateFunc == nil {
+		panic("mock: StateStore.CreateState called but CreateStateFunc is not set")
+	}
+	return m.CreateStateFunc(ctx, req)
+}
+
+var _ store.StateStoreInterface = (*StateStore)(nil)
@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// StatsStore is a stub store.StatsStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type StatsStore struct {
+	GetCollegeStatsFunc     func(ctx context.Context, collegeID string) (*store.CollegeStats, error)
+	GetStateStatsFunc       func(ctx context.Context, stateID string) (*store.StateStats, error)
+	GetUserStatsFunc        func(ctx context.Context, userID string) (*store.UserStats, error)
+	GetDashboardStatsFunc   func(ctx context.Context) (*store.DashboardStats, error)
+	GetUserCountByStateFunc func(ctx context.Context) ([]store.StateUserCount, error)
+}
+
+// GetCollegeStats calls GetCollegeStatsFunc
+func (m *StatsStore) GetCollegeStats(ctx context.Context, collegeID string) (*store.CollegeStats, error) {
+	if m.GetCollegeStatsFunc == nil {
+		panic("mock: StatsStore.GetCollegeStats called but GetCollegeStatsFunc is not set")
+	}
+	return m.GetCollegeStatsFunc(ctx, collegeID)
+}
+
+// GetStateStats calls GetStateStatsFunc
+func (m *StatsStore) GetStateStats(ctx context.Context, stateID string) (*store.StateStats, error) {
+	if m.GetStateStatsFunc == nil {
+		panic("mock: StatsStore.GetStateStats called but GetStateStatsFunc is not set")
+	}
+	return m.GetStateStatsFunc(ctx, stateID)
+}
+
+// GetUserStats calls GetUserStatsFunc
+func (m *StatsStore) GetUserStats(ctx context.Context, userID string) (*store.UserStats, error) {
+	if m.GetUserStatsFunc == nil {
+		panic("mock: StatsStore.GetUserStats called but GetUserStatsFunc is not set")
+	}
+	return m.GetUserStatsFunc(ctx, userID)
+}
+
+// GetDashboardStats calls GetDashboardStatsFunc
+func (m *StatsStore) GetDashboardStats(ctx context.Context) (*store.DashboardStats, error) {
+	if m.GetDashboardStatsFunc == nil {
+		panic("mock: StatsStore.GetDashboardStats called but GetDashboardStatsFunc is not set")
+	}
+	return m.GetDashboardStatsFunc(ctx)
+}
+
+// GetUserCountByState calls GetUserCountByStateFunc
+func (m *StatsStore) GetUserCountByState(ctx context.Context) ([]store.StateUserCount, error) {
+	if m.GetUserCountByStateFunc == nil {
+		panic("mock: StatsStore.GetUserCountByState called but GetUserCountByStateFunc is not set")
+	}
+	return m.GetUserCountByStateFunc(ctx)
+}
+
+var _ store.StatsStoreInterface = (*StatsStore)(nil)
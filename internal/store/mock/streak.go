@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// StreakStore is a stub store.StreakStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type StreakStore struct {
+	UpdateStreakFunc          func(ctx context.Context, userID string) error
+	GetUserStreakFunc         func(ctx context.Context, userID string) (int, *time.Time, *time.Time, error)
+	GetStreakDetailsFunc      func(ctx context.Context, userID string) (*store.StreakDetails, error)
+	HasUnusedStreakFreezeFunc func(ctx context.Context, userID string) (bool, error)
+	BuyStreakFreezeFunc       func(ctx context.Context, userID string, cost int) (string, int, error)
+	RedeemStreakRewardFunc    func(ctx context.Context, userID string, streakDays int) (int, []string, error)
+}
+
+// UpdateStreak calls UpdateStreakFunc
+func (m *StreakStore) UpdateStreak(ctx context.Context, userID string) error {
+	if m.UpdateStreakFunc == nil {
+		panic("mock: StreakStore.UpdateStreak called but UpdateStreakFunc is not set")
+	}
+	return m.UpdateStreakFunc(ctx, userID)
+}
+
+// GetUserStreak calls GetUserStreakFunc
+func (m *StreakStore) GetUserStreak(ctx context.Context, userID string) (int, *time.Time, *time.Time, error) {
+	if m.GetUserStreakFunc == nil {
+		panic("mock: StreakStore.GetUserStreak called but GetUserStreakFunc is not set")
+	}
+	return m.GetUserStreakFunc(ctx, userID)
+}
+
+// GetStreakDetails calls GetStreakDetailsFunc
+func (m *StreakStore) GetStreakDetails(ctx context.Context, userID string) (*store.StreakDetails, error) {
+	if m.GetStreakDetailsFunc == nil {
+		panic("mock: StreakStore.GetStreakDetails called but GetStreakDetailsFunc is not set")
+	}
+	return m.GetStreakDetailsFunc(ctx, userID)
+}
+
+// HasUnusedStreakFreeze calls HasUnusedStreakFreezeFunc
+func (m *StreakStore) HasUnusedStreakFreeze(ctx context.Context, userID string) (bool, error) {
+	if m.HasUnusedStreakFreezeFunc == nil {
+		panic("mock: StreakStore.HasUnusedStreakFreeze called but HasUnusedStreakFreezeFunc is not set")
+	}
+	return m.HasUnusedStreakFreezeFunc(ctx, userID)
+}
+
+// BuyStreakFreeze calls BuyStreakFreezeFunc
+func (m *StreakStore) BuyStreakFreeze(ctx context.Context, userID string, cost int) (string, int, error) {
+	if m.BuyStreakFreezeFunc == nil {
+		panic("mock: StreakStore.BuyStreakFreeze called but BuyStreakFreezeFunc is not set")
+	}
+	return m.BuyStreakFreezeFunc(ctx, userID, cost)
+}
+
+// RedeemStreakReward calls RedeemStreakRewardFunc
+func (m *StreakStore) RedeemStreakReward(ctx context.Context, userID string, streakDays int) (int, []string, error) {
+	if m.RedeemStreakRewardFunc == nil {
+		panic("mock: StreakStore.RedeemStreakReward called but RedeemStreakRewardFunc is not set")
+	}
+	return m.RedeemStreakRewardFunc(ctx, userID, streakDays)
+}
+
+var _ store.StreakStoreInterface = (*StreakStore)(nil)
@@ -0,0 +1,140 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// SubmissionStore is a stub store.SubmissionStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type SubmissionStore struct {
+	GetSubmissionByTaskAndUserFunc  func(ctx context.Context, taskID, userID string) (*store.Submission, error)
+	GetLatestApprovedSubmissionFunc func(ctx context.Context, taskID, userID string) (*store.Submission, error)
+	UpdateSubmissionProofFunc       func(ctx context.Context, submissionID, newProofURL, newThumbnailURL string) (*store.Submission, error)
+	CreateSubmissionFunc            func(ctx context.Context, req store.CreateSubmissionRequest) (*store.Submission, error)
+	GetSubmissionByIDFunc           func(ctx context.Context, submissionID string) (*store.Submission, error)
+	CountApprovedSubmissionsFunc    func(ctx context.Context, userID string) (int, error)
+	SaveLinkPreviewFunc             func(ctx context.Context, submissionID, title, description, imageURL string) error
+	ApproveSubmissionFunc           func(ctx context.Context, submissionID, adminUserID string, comment string) (*store.Submission, error)
+	RejectSubmissionFunc            func(ctx context.Context, submissionID, adminUserID, comment string) (*store.Submission, error)
+	BulkApproveFunc                 func(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]store.BulkReviewResult, []store.BulkReviewFailure, error)
+	BulkRejectFunc                  func(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]store.BulkReviewResult, []store.BulkReviewFailure, error)
+	GetAllSubmissionsFunc           func(ctx context.Context, filter store.SubmissionFilter) ([]store.Submission, error)
+	ListSubmissionsFunc             func(ctx context.Context, filter store.SubmissionFilter, cursor *store.SubmissionCursor, limit, offset int) ([]store.Submission, *store.SubmissionCursor, error)
+	IsProofKeyReferencedFunc        func(ctx context.Context, key string) (bool, error)
+}
+
+// GetSubmissionByTaskAndUser calls GetSubmissionByTaskAndUserFunc
+func (m *SubmissionStore) GetSubmissionByTaskAndUser(ctx context.Context, taskID, userID string) (*store.Submission, error) {
+	if m.GetSubmissionByTaskAndUserFunc == nil {
+		panic("mock: SubmissionStore.GetSubmissionByTaskAndUser called but GetSubmissionByTaskAndUserFunc is not set")
+	}
+	return m.GetSubmissionByTaskAndUserFunc(ctx, taskID, userID)
+}
+
+// GetLatestApprovedSubmission calls GetLatestApprovedSubmissionFunc
+func (m *SubmissionStore) GetLatestApprovedSubmission(ctx context.Context, taskID, userID string) (*store.Submission, error) {
+	if m.GetLatestApprovedSubmissionFunc == nil {
+		panic("mock: SubmissionStore.GetLatestApprovedSubmission called but GetLatestApprovedSubmissionFunc is not set")
+	}
+	return m.GetLatestApprovedSubmissionFunc(ctx, taskID, userID)
+}
+
+// UpdateSubmissionProof calls UpdateSubmissionProofFunc
+func (m *SubmissionStore) UpdateSubmissionProof(ctx context.Context, submissionID, newProofURL, newThumbnailURL string) (*store.Submission, error) {
+	if m.UpdateSubmissionProofFunc == nil {
+		panic("mock: SubmissionStore.UpdateSubmissionProof called but UpdateSubmissionProofFunc is not set")
+	}
+	return m.UpdateSubmissionProofFunc(ctx, submissionID, newProofURL, newThumbnailURL)
+}
+
+// CreateSubmission calls CreateSubmissionFunc
+func (m *SubmissionStore) CreateSubmission(ctx context.Context, req store.CreateSubmissionRequest) (*store.Submission, error) {
+	if m.CreateSubmissionFunc == nil {
+		panic("mock: SubmissionStore.CreateSubmission called but CreateSubmissionFunc is not set")
+	}
+	return m.CreateSubmissionFunc(ctx, req)
+}
+
+// GetSubmissionByID calls GetSubmissionByIDFunc
+func (m *SubmissionStore) GetSubmissionByID(ctx context.Context, submissionID string) (*store.Submission, error) {
+	if m.GetSubmissionByIDFunc == nil {
+		panic("mock: SubmissionStore.GetSubmissionByID called but GetSubmissionByIDFunc is not set")
+	}
+	return m.GetSubmissionByIDFunc(ctx, submissionID)
+}
+
+// CountApprovedSubmissions calls CountApprovedSubmissionsFunc
+func (m *SubmissionStore) CountApprovedSubmissions(ctx context.Context, userID string) (int, error) {
+	if m.CountApprovedSubmissionsFunc == nil {
+		panic("mock: SubmissionStore.CountApprovedSubmissions called but CountApprovedSubmissionsFunc is not set")
+	}
+	return m.CountApprovedSubmissionsFunc(ctx, userID)
+}
+
+// SaveLinkPreview calls SaveLinkPreviewFunc
+func (m *SubmissionStore) SaveLinkPreview(ctx context.Context, submissionID, title, description, imageURL string) error {
+	if m.SaveLinkPreviewFunc == nil {
+		panic("mock: SubmissionStore.SaveLinkPreview called but SaveLinkPreviewFunc is not set")
+	}
+	return m.SaveLinkPreviewFunc(ctx, submissionID, title, description, imageURL)
+}
+
+// ApproveSubmission calls ApproveSubmissionFunc
+func (m *SubmissionStore) ApproveSubmission(ctx context.Context, submissionID, adminUserID string, comment string) (*store.Submission, error) {
+	if m.ApproveSubmissionFunc == nil {
+		panic("mock: SubmissionStore.ApproveSubmission called but ApproveSubmissionFunc is not set")
+	}
+	return m.ApproveSubmissionFunc(ctx, submissionID, adminUserID, comment)
+}
+
+// RejectSubmission calls RejectSubmissionFunc
+func (m *SubmissionStore) RejectSubmission(ctx context.Context, submissionID, adminUserID, comment string) (*store.Submission, error) {
+	if m.RejectSubmissionFunc == nil {
+		panic("mock: SubmissionStore.RejectSubmission called but RejectSubmissionFunc is not set")
+	}
+	return m.RejectSubmissionFunc(ctx, submissionID, adminUserID, comment)
+}
+
+// BulkApprove calls BulkApproveFunc
+func (m *SubmissionStore) BulkApprove(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]store.BulkReviewResult, []store.BulkReviewFailure, error) {
+	if m.BulkApproveFunc == nil {
+		panic("mock: SubmissionStore.BulkApprove called but BulkApproveFunc is not set")
+	}
+	return m.BulkApproveFunc(ctx, submissionIDs, adminUserID, comment)
+}
+
+// BulkReject calls BulkRejectFunc
+func (m *SubmissionStore) BulkReject(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]store.BulkReviewResult, []store.BulkReviewFailure, error) {
+	if m.BulkRejectFunc == nil {
+		panic("mock: SubmissionStore.BulkReject called but BulkRejectFunc is not set")
+	}
+	return m.BulkRejectFunc(ctx, submissionIDs, adminUserID, comment)
+}
+
+// GetAllSubmissions calls GetAllSubmissionsFunc
+func (m *SubmissionStore) GetAllSubmissions(ctx context.Context, filter store.SubmissionFilter) ([]store.Submission, error) {
+	if m.GetAllSubmissionsFunc == nil {
+		panic("mock: SubmissionStore.GetAllSubmissions called but GetAllSubmissionsFunc is not set")
+	}
+	return m.GetAllSubmissionsFunc(ctx, filter)
+}
+
+// ListSubmissions calls ListSubmissionsFunc
+func (m *SubmissionStore) ListSubmissions(ctx context.Context, filter store.SubmissionFilter, cursor *store.SubmissionCursor, limit, offset int) ([]store.Submission, *store.SubmissionCursor, error) {
+	if m.ListSubmissionsFunc == nil {
+		panic("mock: SubmissionStore.ListSubmissions called but ListSubmissionsFunc is not set")
+	}
+	return m.ListSubmissionsFunc(ctx, filter, cursor, limit, offset)
+}
+
+// IsProofKeyReferenced calls IsProofKeyReferencedFunc
+func (m *SubmissionStore) IsProofKeyReferenced(ctx context.Context, key string) (bool, error) {
+	if m.IsProofKeyReferencedFunc == nil {
+		panic("mock: SubmissionStore.IsProofKeyReferenced called but IsProofKeyReferencedFunc is not set")
+	}
+	return m.IsProofKeyReferencedFunc(ctx, key)
+}
+
+var _ store.SubmissionStoreInterface = (*SubmissionStore)(nil)
@@ -0,0 +1,150 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// TaskStore is a stub store.TaskStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type TaskStore struct {
+	CreateTaskFunc                 func(ctx context.Context, req store.CreateTaskRequest, assignmentType store.AssignmentType, assignmentID string) (*store.Task, []string, error)
+	GetWeeklyTasksDueForRepeatFunc func(ctx context.Context) ([]store.WeeklyTaskTemplate, error)
+	CreateWeeklyInstanceFunc       func(ctx context.Context, template store.WeeklyTaskTemplate, startAt, endAt time.Time) (*store.Task, []string, error)
+	GetTaskSeriesFunc              func(ctx context.Context, taskID string) ([]store.Task, error)
+	AssignOpenTasksToNewUserFunc   func(ctx context.Context, userID string) ([]string, error)
+	UpdateTaskAssignmentFunc       func(ctx context.Context, taskID string, assignmentType store.AssignmentType, assignmentID string, removeExisting bool) ([]string, error)
+	AddTaskAssignmentFunc          func(ctx context.Context, taskID string, userIDs []string) ([]string, error)
+	RemoveTaskAssignmentFunc       func(ctx context.Context, taskID string, userIDs []string) (removed int, withSubmission []string, err error)
+	GetTaskByIDFunc                func(ctx context.Context, taskID string) (*store.Task, error)
+	GetTasksForUserFunc            func(ctx context.Context, userID string) ([]store.Task, error)
+	GetTasksForUserWithStatusFunc  func(ctx context.Context, userID string) ([]store.TaskWithUserStatus, error)
+	ToggleReactionFunc             func(ctx context.Context, taskID, userID, reaction string) (bool, error)
+	GetReactionBreakdownFunc       func(ctx context.Context, taskID string) (map[string]int, error)
+	GetFlashTasksFunc              func(ctx context.Context, userID string) ([]store.TaskWithUserStatus, error)
+	CheckSubmissionExistsFunc      func(ctx context.Context, taskID, userID string) (bool, error)
+}
+
+// CreateTask calls CreateTaskFunc
+func (m *TaskStore) CreateTask(ctx context.Context, req store.CreateTaskRequest, assignmentType store.AssignmentType, assignmentID string) (*store.Task, []string, error) {
+	if m.CreateTaskFunc == nil {
+		panic("mock: TaskStore.CreateTask called but CreateTaskFunc is not set")
+	}
+	return m.CreateTaskFunc(ctx, req, assignmentType, assignmentID)
+}
+
+// GetWeeklyTasksDueForRepeat calls GetWeeklyTasksDueForRepeatFunc
+func (m *TaskStore) GetWeeklyTasksDueForRepeat(ctx context.Context) ([]store.WeeklyTaskTemplate, error) {
+	if m.GetWeeklyTasksDueForRepeatFunc == nil {
+		panic("mock: TaskStore.GetWeeklyTasksDueForRepeat called but GetWeeklyTasksDueForRepeatFunc is not set")
+	}
+	return m.GetWeeklyTasksDueForRepeatFunc(ctx)
+}
+
+// CreateWeeklyInstance calls CreateWeeklyInstanceFunc
+func (m *TaskStore) CreateWeeklyInstance(ctx context.Context, template store.WeeklyTaskTemplate, startAt, endAt time.Time) (*store.Task, []string, error) {
+	if m.CreateWeeklyInstanceFunc == nil {
+		panic("mock: TaskStore.CreateWeeklyInstance called but CreateWeeklyInstanceFunc is not set")
+	}
+	return m.CreateWeeklyInstanceFunc(ctx, template, startAt, endAt)
+}
+
+// GetTaskSeries calls GetTaskSeriesFunc
+func (m *TaskStore) GetTaskSeries(ctx context.Context, taskID string) ([]store.Task, error) {
+	if m.GetTaskSeriesFunc == nil {
+		panic("mock: TaskStore.GetTaskSeries called but GetTaskSeriesFunc is not set")
+	}
+	return m.GetTaskSeriesFunc(ctx, taskID)
+}
+
+// AssignOpenTasksToNewUser calls AssignOpenTasksToNewUserFunc
+func (m *TaskStore) AssignOpenTasksToNewUser(ctx context.Context, userID string) ([]string, error) {
+	if m.AssignOpenTasksToNewUserFunc == nil {
+		panic("mock: TaskStore.AssignOpenTasksToNewUser called but AssignOpenTasksToNewUserFunc is not set")
+	}
+	return m.AssignOpenTasksToNewUserFunc(ctx, userID)
+}
+
+// UpdateTaskAssignment calls UpdateTaskAssignmentFunc
+func (m *TaskStore) UpdateTaskAssignment(ctx context.Context, taskID string, assignmentType store.AssignmentType, assignmentID string, removeExisting bool) ([]string, error) {
+	if m.UpdateTaskAssignmentFunc == nil {
+		panic("mock: TaskStore.UpdateTaskAssignment called but UpdateTaskAssignmentFunc is not set")
+	}
+	return m.UpdateTaskAssignmentFunc(ctx, taskID, assignmentType, assignmentID, removeExisting)
+}
+
+// AddTaskAssignment calls AddTaskAssignmentFunc
+func (m *TaskStore) AddTaskAssignment(ctx context.Context, taskID string, userIDs []string) ([]string, error) {
+	if m.AddTaskAssignmentFunc == nil {
+		panic("mock: TaskStore.AddTaskAssignment called but AddTaskAssignmentFunc is not set")
+	}
+	return m.AddTaskAssignmentFunc(ctx, taskID, userIDs)
+}
+
+// RemoveTaskAssignment calls RemoveTaskAssignmentFunc
+func (m *TaskStore) RemoveTaskAssignment(ctx context.Context, taskID string, userIDs []string) (removed int, withSubmission []string, err error) {
+	if m.RemoveTaskAssignmentFunc == nil {
+		panic("mock: TaskStore.RemoveTaskAssignment called but RemoveTaskAssignmentFunc is not set")
+	}
+	return m.RemoveTaskAssignmentFunc(ctx, taskID, userIDs)
+}
+
+// GetTaskByID calls GetTaskByIDFunc
+func (m *TaskStore) GetTaskByID(ctx context.Context, taskID string) (*store.Task, error) {
+	if m.GetTaskByIDFunc == nil {
+		panic("mock: TaskStore.GetTaskByID called but GetTaskByIDFunc is not set")
+	}
+	return m.GetTaskByIDFunc(ctx, taskID)
+}
+
+// GetTasksForUser calls GetTasksForUserFunc
+func (m *TaskStore) GetTasksForUser(ctx context.Context, userID string) ([]store.Task, error) {
+	if m.GetTasksForUserFunc == nil {
+		panic("mock: TaskStore.GetTasksForUser called but GetTasksForUserFunc is not set")
+	}
+	return m.GetTasksForUserFunc(ctx, userID)
+}
+
+// GetTasksForUserWithStatus calls GetTasksForUserWithStatusFunc
+func (m *TaskStore) GetTasksForUserWithStatus(ctx context.Context, userID string) ([]store.TaskWithUserStatus, error) {
+	if m.GetTasksForUserWithStatusFunc == nil {
+		panic("mock: TaskStore.GetTasksForUserWithStatus called but GetTasksForUserWithStatusFunc is not set")
+	}
+	return m.GetTasksForUserWithStatusFunc(ctx, userID)
+}
+
+// ToggleReaction calls ToggleReactionFunc
+func (m *TaskStore) ToggleReaction(ctx context.Context, taskID, userID, reaction string) (bool, error) {
+	if m.ToggleReactionFunc == nil {
+		panic("mock: TaskStore.ToggleReaction called but ToggleReactionFunc is not set")
+	}
+	return m.ToggleReactionFunc(ctx, taskID, userID, reaction)
+}
+
+// GetReactionBreakdown calls GetReactionBreakdownFunc
+func (m *TaskStore) GetReactionBreakdown(ctx context.Context, taskID string) (map[string]int, error) {
+	if m.GetReactionBreakdownFunc == nil {
+		panic("mock: TaskStore.GetReactionBreakdown called but GetReactionBreakdownFunc is not set")
+	}
+	return m.GetReactionBreakdownFunc(ctx, taskID)
+}
+
+// GetFlashTasks calls GetFlashTasksFunc
+func (m *TaskStore) GetFlashTasks(ctx context.Context, userID string) ([]store.TaskWithUserStatus, error) {
+	if m.GetFlashTasksFunc == nil {
+		panic("mock: TaskStore.GetFlashTasks called but GetFlashTasksFunc is not set")
+	}
+	return m.GetFlashTasksFunc(ctx, userID)
+}
+
+// CheckSubmissionExists calls CheckSubmissionExistsFunc
+func (m *TaskStore) CheckSubmissionExists(ctx context.Context, taskID, userID string) (bool, error) {
+	if m.CheckSubmissionExistsFunc == nil {
+		panic("mock: TaskStore.CheckSubmissionExists called but CheckSubmissionExistsFunc is not set")
+	}
+	return m.CheckSubmissionExistsFunc(ctx, taskID, userID)
+}
+
+var _ store.TaskStoreInterface = (*TaskStore)(nil)
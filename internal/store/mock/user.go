@@ -0,0 +1,293 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// UserStore is a stub store.UserStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type UserStore struct {
+	RegisterFunc               func(ctx context.Context, req store.RegisterRequest, resumeURL, resumeKey, profilePicURL, profilePicKey string) (*store.User, error)
+	ImportUsersFunc            func(ctx context.Context, users []store.BulkImportUser) (imported []store.ImportedUser, skipped int, rowErrors []store.BulkImportError, err error)
+	GetUserByEmailFunc         func(ctx context.Context, email string) (*store.User, error)
+	GetUserPasswordHashFunc    func(ctx context.Context, email string) (string, error)
+	VerifyPasswordFunc         func(hashedPassword, password string) bool
+	UpdateResumeURLFunc        func(ctx context.Context, userID, resumeURL, resumeKey string) error
+	UpdateResumeVisibilityFunc func(ctx context.Context, userID, visibility string) error
+	UpdateProfilePicURLFunc    func(ctx context.Context, userID, profilePicURL, profilePicKey string) error
+	UpdateProfileFunc          func(ctx context.Context, userID string, fields store.UpdateProfileRequest) (*store.User, error)
+	BanUserFunc                func(ctx context.Context, userID, reason string) error
+	UnbanUserFunc              func(ctx context.Context, userID string) error
+	IsUserBannedFunc           func(ctx context.Context, userID string) (bool, error)
+	UpdateFCMTokenFunc         func(ctx context.Context, userID, token string) error
+	GetFCMTokenFunc            func(ctx context.Context, userID string) (string, error)
+	GetUserS3KeysFunc          func(ctx context.Context, userID string) (resumeKey, avatarKey string, err error)
+	GetAllUsersFunc            func(ctx context.Context, limit, offset int) ([]*store.User, error)
+	GetUserByIDFunc            func(ctx context.Context, userID string) (*store.User, error)
+	FollowUserFunc             func(ctx context.Context, followerID, followingID string) error
+	UnfollowUserFunc           func(ctx context.Context, followerID, followingID string) error
+	GetFollowingCountFunc      func(ctx context.Context, userID string) (int, error)
+	GetFollowersCountFunc      func(ctx context.Context, userID string) (int, error)
+	GetFollowersFunc           func(ctx context.Context, userID, viewerID string, limit, offset int) ([]store.FollowUserInfo, int, error)
+	SearchUsersFunc            func(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]store.SearchUserResult, int, error)
+	GetFollowingFunc           func(ctx context.Context, userID, viewerID string, limit, offset int) ([]store.FollowUserInfo, int, error)
+	BlockUserFunc              func(ctx context.Context, blockerID, blockedID string) error
+	UnblockUserFunc            func(ctx context.Context, blockerID, blockedID string) error
+	IsBlockedBetweenFunc       func(ctx context.Context, userAID, userBID string) (bool, error)
+	GetBlockedUsersFunc        func(ctx context.Context, blockerID string, limit, offset int) ([]store.FollowUserInfo, error)
+	IsFollowingFunc            func(ctx context.Context, followerID, followingID string) (bool, error)
+	IsMutualFollowFunc         func(ctx context.Context, userAID, userBID string) (bool, error)
+	GetMutualFollowsFunc       func(ctx context.Context, userAID, userBID string, page, pageSize int) ([]store.FollowUserInfo, int, error)
+}
+
+// Register calls RegisterFunc
+func (m *UserStore) Register(ctx context.Context, req store.RegisterRequest, resumeURL, resumeKey, profilePicURL, profilePicKey string) (*store.User, error) {
+	if m.RegisterFunc == nil {
+		panic("mock: UserStore.Register called but RegisterFunc is not set")
+	}
+	return m.RegisterFunc(ctx, req, resumeURL, resumeKey, profilePicURL, profilePicKey)
+}
+
+// ImportUsers calls ImportUsersFunc
+func (m *UserStore) ImportUsers(ctx context.Context, users []store.BulkImportUser) (imported []store.ImportedUser, skipped int, rowErrors []store.BulkImportError, err error) {
+	if m.ImportUsersFunc == nil {
+		panic("mock: UserStore.ImportUsers called but ImportUsersFunc is not set")
+	}
+	return m.ImportUsersFunc(ctx, users)
+}
+
+// GetUserByEmail calls GetUserByEmailFunc
+func (m *UserStore) GetUserByEmail(ctx context.Context, email string) (*store.User, error) {
+	if m.GetUserByEmailFunc == nil {
+		panic("mock: UserStore.GetUserByEmail called but GetUserByEmailFunc is not set")
+	}
+	return m.GetUserByEmailFunc(ctx, email)
+}
+
+// GetUserPasswordHash calls GetUserPasswordHashFunc
+func (m *UserStore) GetUserPasswordHash(ctx context.Context, email string) (string, error) {
+	if m.GetUserPasswordHashFunc == nil {
+		panic("mock: UserStore.GetUserPasswordHash called but GetUserPasswordHashFunc is not set")
+	}
+	return m.GetUserPasswordHashFunc(ctx, email)
+}
+
+// VerifyPassword calls VerifyPasswordFunc
+func (m *UserStore) VerifyPassword(hashedPassword, password string) bool {
+	if m.VerifyPasswordFunc == nil {
+		panic("mock: UserStore.VerifyPassword called but VerifyPasswordFunc is not set")
+	}
+	return m.VerifyPasswordFunc(hashedPassword, password)
+}
+
+// UpdateResumeURL calls UpdateResumeURLFunc
+func (m *UserStore) UpdateResumeURL(ctx context.Context, userID, resumeURL, resumeKey string) error {
+	if m.UpdateResumeURLFunc == nil {
+		panic("mock: UserStore.UpdateResumeURL called but UpdateResumeURLFunc is not set")
+	}
+	return m.UpdateResumeURLFunc(ctx, userID, resumeURL, resumeKey)
+}
+
+// UpdateResumeVisibility calls UpdateResumeVisibilityFunc
+func (m *UserStore) UpdateResumeVisibility(ctx context.Context, userID, visibility string) error {
+	if m.UpdateResumeVisibilityFunc == nil {
+		panic("mock: UserStore.UpdateResumeVisibility called but UpdateResumeVisibilityFunc is not set")
+	}
+	return m.UpdateResumeVisibilityFunc(ctx, userID, visibility)
+}
+
+// UpdateProfilePicURL calls UpdateProfilePicURLFunc
+func (m *UserStore) UpdateProfilePicURL(ctx context.Context, userID, profilePicURL, profilePicKey string) error {
+	if m.UpdateProfilePicURLFunc == nil {
+		panic("mock: UserStore.UpdateProfilePicURL called but UpdateProfilePicURLFunc is not set")
+	}
+	return m.UpdateProfilePicURLFunc(ctx, userID, profilePicURL, profilePicKey)
+}
+
+// UpdateProfile calls UpdateProfileFunc
+func (m *UserStore) UpdateProfile(ctx context.Context, userID string, fields store.UpdateProfileRequest) (*store.User, error) {
+	if m.UpdateProfileFunc == nil {
+		panic("mock: UserStore.UpdateProfile called but UpdateProfileFunc is not set")
+	}
+	return m.UpdateProfileFunc(ctx, userID, fields)
+}
+
+// BanUser calls BanUserFunc
+func (m *UserStore) BanUser(ctx context.Context, userID, reason string) error {
+	if m.BanUserFunc == nil {
+		panic("mock: UserStore.BanUser called but BanUserFunc is not set")
+	}
+	return m.BanUserFunc(ctx, userID, reason)
+}
+
+// UnbanUser calls UnbanUserFunc
+func (m *UserStore) UnbanUser(ctx context.Context, userID string) error {
+	if m.UnbanUserFunc == nil {
+		panic("mock: UserStore.UnbanUser called but UnbanUserFunc is not set")
+	}
+	return m.UnbanUserFunc(ctx, userID)
+}
+
+// IsUserBanned calls IsUserBannedFunc
+func (m *UserStore) IsUserBanned(ctx context.Context, userID string) (bool, error) {
+	if m.IsUserBannedFunc == nil {
+		panic("mock: UserStore.IsUserBanned called but IsUserBannedFunc is not set")
+	}
+	return m.IsUserBannedFunc(ctx, userID)
+}
+
+// UpdateFCMToken calls UpdateFCMTokenFunc
+func (m *UserStore) UpdateFCMToken(ctx context.Context, userID, token string) error {
+	if m.UpdateFCMTokenFunc == nil {
+		panic("mock: UserStore.UpdateFCMToken called but UpdateFCMTokenFunc is not set")
+	}
+	return m.UpdateFCMTokenFunc(ctx, userID, token)
+}
+
+// GetFCMToken calls GetFCMTokenFunc
+func (m *UserStore) GetFCMToken(ctx context.Context, userID string) (string, error) {
+	if m.GetFCMTokenFunc == nil {
+		panic("mock: UserStore.GetFCMToken called but GetFCMTokenFunc is not set")
+	}
+	return m.GetFCMTokenFunc(ctx, userID)
+}
+
+// GetUserS3Keys calls GetUserS3KeysFunc
+func (m *UserStore) GetUserS3Keys(ctx context.Context, userID string) (resumeKey, avatarKey string, err error) {
+	if m.GetUserS3KeysFunc == nil {
+		panic("mock: UserStore.GetUserS3Keys called but GetUserS3KeysFunc is not set")
+	}
+	return m.GetUserS3KeysFunc(ctx, userID)
+}
+
+// GetAllUsers calls GetAllUsersFunc
+func (m *UserStore) GetAllUsers(ctx context.Context, limit, offset int) ([]*store.User, error) {
+	if m.GetAllUsersFunc == nil {
+		panic("mock: UserStore.GetAllUsers called but GetAllUsersFunc is not set")
+	}
+	return m.GetAllUsersFunc(ctx, limit, offset)
+}
+
+// GetUserByID calls GetUserByIDFunc
+func (m *UserStore) GetUserByID(ctx context.Context, userID string) (*store.User, error) {
+	if m.GetUserByIDFunc == nil {
+		panic("mock: UserStore.GetUserByID called but GetUserByIDFunc is not set")
+	}
+	return m.GetUserByIDFunc(ctx, userID)
+}
+
+// FollowUser calls FollowUserFunc
+func (m *UserStore) FollowUser(ctx context.Context, followerID, followingID string) error {
+	if m.FollowUserFunc == nil {
+		panic("mock: UserStore.FollowUser called but FollowUserFunc is not set")
+	}
+	return m.FollowUserFunc(ctx, followerID, followingID)
+}
+
+// UnfollowUser calls UnfollowUserFunc
+func (m *UserStore) UnfollowUser(ctx context.Context, followerID, followingID string) error {
+	if m.UnfollowUserFunc == nil {
+		panic("mock: UserStore.UnfollowUser called but UnfollowUserFunc is not set")
+	}
+	return m.UnfollowUserFunc(ctx, followerID, followingID)
+}
+
+// GetFollowingCount calls GetFollowingCountFunc
+func (m *UserStore) GetFollowingCount(ctx context.Context, userID string) (int, error) {
+	if m.GetFollowingCountFunc == nil {
+		panic("mock: UserStore.GetFollowingCount called but GetFollowingCountFunc is not set")
+	}
+	return m.GetFollowingCountFunc(ctx, userID)
+}
+
+// GetFollowersCount calls GetFollowersCountFunc
+func (m *UserStore) GetFollowersCount(ctx context.Context, userID string) (int, error) {
+	if m.GetFollowersCountFunc == nil {
+		panic("mock: UserStore.GetFollowersCount called but GetFollowersCountFunc is not set")
+	}
+	return m.GetFollowersCountFunc(ctx, userID)
+}
+
+// GetFollowers calls GetFollowersFunc
+func (m *UserStore) GetFollowers(ctx context.Context, userID, viewerID string, limit, offset int) ([]store.FollowUserInfo, int, error) {
+	if m.GetFollowersFunc == nil {
+		panic("mock: UserStore.GetFollowers called but GetFollowersFunc is not set")
+	}
+	return m.GetFollowersFunc(ctx, userID, viewerID, limit, offset)
+}
+
+// SearchUsers calls SearchUsersFunc
+func (m *UserStore) SearchUsers(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]store.SearchUserResult, int, error) {
+	if m.SearchUsersFunc == nil {
+		panic("mock: UserStore.SearchUsers called but SearchUsersFunc is not set")
+	}
+	return m.SearchUsersFunc(ctx, viewerID, query, stateID, collegeID, limit, offset)
+}
+
+// GetFollowing calls GetFollowingFunc
+func (m *UserStore) GetFollowing(ctx context.Context, userID, viewerID string, limit, offset int) ([]store.FollowUserInfo, int, error) {
+	if m.GetFollowingFunc == nil {
+		panic("mock: UserStore.GetFollowing called but GetFollowingFunc is not set")
+	}
+	return m.GetFollowingFunc(ctx, userID, viewerID, limit, offset)
+}
+
+// BlockUser calls BlockUserFunc
+func (m *UserStore) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	if m.BlockUserFunc == nil {
+		panic("mock: UserStore.BlockUser called but BlockUserFunc is not set")
+	}
+	return m.BlockUserFunc(ctx, blockerID, blockedID)
+}
+
+// UnblockUser calls UnblockUserFunc
+func (m *UserStore) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	if m.UnblockUserFunc == nil {
+		panic("mock: UserStore.UnblockUser called but UnblockUserFunc is not set")
+	}
+	return m.UnblockUserFunc(ctx, blockerID, blockedID)
+}
+
+// IsBlockedBetween calls IsBlockedBetweenFunc
+func (m *UserStore) IsBlockedBetween(ctx context.Context, userAID, userBID string) (bool, error) {
+	if m.IsBlockedBetweenFunc == nil {
+		panic("mock: UserStore.IsBlockedBetween called but IsBlockedBetweenFunc is not set")
+	}
+	return m.IsBlockedBetweenFunc(ctx, userAID, userBID)
+}
+
+// GetBlockedUsers calls GetBlockedUsersFunc
+func (m *UserStore) GetBlockedUsers(ctx context.Context, blockerID string, limit, offset int) ([]store.FollowUserInfo, error) {
+	if m.GetBlockedUsersFunc == nil {
+		panic("mock: UserStore.GetBlockedUsers called but GetBlockedUsersFunc is not set")
+	}
+	return m.GetBlockedUsersFunc(ctx, blockerID, limit, offset)
+}
+
+// IsFollowing calls IsFollowingFunc
+func (m *UserStore) IsFollowing(ctx context.Context, followerID, followingID string) (bool, error) {
+	if m.IsFollowingFunc == nil {
+		panic("mock: UserStore.IsFollowing called but IsFollowingFunc is not set")
+	}
+	return m.IsFollowingFunc(ctx, followerID, followingID)
+}
+
+// IsMutualFollow calls IsMutualFollowFunc
+func (m *UserStore) IsMutualFollow(ctx context.Context, userAID, userBID string) (bool, error) {
+	if m.IsMutualFollowFunc == nil {
+		panic("mock: UserStore.IsMutualFollow called but IsMutualFollowFunc is not set")
+	}
+	return m.IsMutualFollowFunc(ctx, userAID, userBID)
+}
+
+// GetMutualFollows calls GetMutualFollowsFunc
+func (m *UserStore) GetMutualFollows(ctx context.Context, userAID, userBID string, page, pageSize int) ([]store.FollowUserInfo, int, error) {
+	if m.GetMutualFollowsFunc == nil {
+		panic("mock: UserStore.GetMutualFollows called but GetMutualFollowsFunc is not set")
+	}
+	return m.GetMutualFollowsFunc(ctx, userAID, userBID, page, pageSize)
+}
+
+var _ store.UserStoreInterface = (*UserStore)(nil)
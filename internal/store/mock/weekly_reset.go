@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// WeeklyResetStore is a stub store.WeeklyResetStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type WeeklyResetStore struct {
+	ClearUnreviewedSubmissionsFunc func(ctx context.Context) (int64, error)
+}
+
+// ClearUnreviewedSubmissions calls ClearUnreviewedSubmissionsFunc
+func (m *WeeklyResetStore) ClearUnreviewedSubmissions(ctx context.Context) (int64, error) {
+	if m.ClearUnreviewedSubmissionsFunc == nil {
+		panic("mock: WeeklyResetStore.ClearUnreviewedSubmissions called but ClearUnreviewedSubmissionsFunc is not set")
+	}
+	return m.ClearUnreviewedSubmissionsFunc(ctx)
+}
+
+var _ store.WeeklyResetStoreInterface = (*WeeklyResetStore)(nil)
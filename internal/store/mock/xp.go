@@ -0,0 +1,96 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// XPStore is a stub store.XPStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type XPStore struct {
+	AwardXPFunc        func(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error)
+	DeductXPFunc       func(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error)
+	AdjustXPFunc       func(ctx context.Context, adminID, userID string, delta int, reason string, allowNegative bool) (*store.XPLog, error)
+	GetXPLogsFunc      func(ctx context.Context, userID string, limit int) ([]store.XPLog, error)
+	GetXPSummaryFunc   func(ctx context.Context, userID string) (*store.XPSummary, error)
+	GetUserTotalXPFunc func(ctx context.Context, userID string) (int, error)
+	GetXPAnomaliesFunc func(ctx context.Context, threshold int, window time.Duration) ([]store.XPAnomaly, error)
+	GetAllXPLogsFunc   func(ctx context.Context, filter store.XPLogFilter) ([]store.XPLogWithUser, error)
+	StreamXPLogsFunc   func(ctx context.Context, filter store.XPLogFilter, fn func(store.XPLogWithUser) error) error
+}
+
+// AwardXP calls AwardXPFunc
+func (m *XPStore) AwardXP(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error) {
+	if m.AwardXPFunc == nil {
+		panic("mock: XPStore.AwardXP called but AwardXPFunc is not set")
+	}
+	return m.AwardXPFunc(ctx, req)
+}
+
+// DeductXP calls DeductXPFunc
+func (m *XPStore) DeductXP(ctx context.Context, req store.AwardXPRequest) (*store.XPLog, error) {
+	if m.DeductXPFunc == nil {
+		panic("mock: XPStore.DeductXP called but DeductXPFunc is not set")
+	}
+	return m.DeductXPFunc(ctx, req)
+}
+
+// AdjustXP calls AdjustXPFunc
+func (m *XPStore) AdjustXP(ctx context.Context, adminID, userID string, delta int, reason string, allowNegative bool) (*store.XPLog, error) {
+	if m.AdjustXPFunc == nil {
+		panic("mock: XPStore.AdjustXP called but AdjustXPFunc is not set")
+	}
+	return m.AdjustXPFunc(ctx, adminID, userID, delta, reason, allowNegative)
+}
+
+// GetXPLogs calls GetXPLogsFunc
+func (m *XPStore) GetXPLogs(ctx context.Context, userID string, limit int) ([]store.XPLog, error) {
+	if m.GetXPLogsFunc == nil {
+		panic("mock: XPStore.GetXPLogs called but GetXPLogsFunc is not set")
+	}
+	return m.GetXPLogsFunc(ctx, userID, limit)
+}
+
+// GetXPSummary calls GetXPSummaryFunc
+func (m *XPStore) GetXPSummary(ctx context.Context, userID string) (*store.XPSummary, error) {
+	if m.GetXPSummaryFunc == nil {
+		panic("mock: XPStore.GetXPSummary called but GetXPSummaryFunc is not set")
+	}
+	return m.GetXPSummaryFunc(ctx, userID)
+}
+
+// GetUserTotalXP calls GetUserTotalXPFunc
+func (m *XPStore) GetUserTotalXP(ctx context.Context, userID string) (int, error) {
+	if m.GetUserTotalXPFunc == nil {
+		panic("mock: XPStore.GetUserTotalXP called but GetUserTotalXPFunc is not set")
+	}
+	return m.GetUserTotalXPFunc(ctx, userID)
+}
+
+// GetXPAnomalies calls GetXPAnomaliesFunc
+func (m *XPStore) GetXPAnomalies(ctx context.Context, threshold int, window time.Duration) ([]store.XPAnomaly, error) {
+	if m.GetXPAnomaliesFunc == nil {
+		panic("mock: XPStore.GetXPAnomalies called but GetXPAnomaliesFunc is not set")
+	}
+	return m.GetXPAnomaliesFunc(ctx, threshold, window)
+}
+
+// GetAllXPLogs calls GetAllXPLogsFunc
+func (m *XPStore) GetAllXPLogs(ctx context.Context, filter store.XPLogFilter) ([]store.XPLogWithUser, error) {
+	if m.GetAllXPLogsFunc == nil {
+		panic("mock: XPStore.GetAllXPLogs called but GetAllXPLogsFunc is not set")
+	}
+	return m.GetAllXPLogsFunc(ctx, filter)
+}
+
+// StreamXPLogs calls StreamXPLogsFunc
+func (m *XPStore) StreamXPLogs(ctx context.Context, filter store.XPLogFilter, fn func(store.XPLogWithUser) error) error {
+	if m.StreamXPLogsFunc == nil {
+		panic("mock: XPStore.StreamXPLogs called but StreamXPLogsFunc is not set")
+	}
+	return m.StreamXPLogsFunc(ctx, filter, fn)
+}
+
+var _ store.XPStoreInterface = (*XPStore)(nil)
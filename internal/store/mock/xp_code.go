@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// XPCodeStore is a stub store.XPCodeStoreInterface. Each method calls the
+// matching Func field and panics if it is nil, so a test only sets what it expects
+type XPCodeStore struct {
+	RedeemXPCodeFunc func(ctx context.Context, code, userID string) (*store.XPCode, error)
+}
+
+// RedeemXPCode calls RedeemXPCodeFunc
+func (m *XPCodeStore) RedeemXPCode(ctx context.Context, code, userID string) (*store.XPCode, error) {
+	if m.RedeemXPCodeFunc == nil {
+		panic("mock: XPCodeStore.RedeemXPCode called but RedeemXPCodeFunc is not set")
+	}
+	return m.RedeemXPCodeFunc(ctx, code, userID)
+}
+
+var _ store.XPCodeStoreInterface = (*XPCodeStore)(nil)
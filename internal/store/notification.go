@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// Notification is a notification stored for a user who was offline when it was sent
+type Notification struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	IsRead    bool            `json:"is_read"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type NotificationStore struct {
+	postgres *db.Postgres
+}
+
+func NewNotificationStore(postgres *db.Postgres) *NotificationStore {
+	return &NotificationStore{
+		postgres: postgres,
+	}
+}
+
+// CreateNotification stores a notification for a user. data is the notification's extra payload
+// (task_id, submission_id, ...) and is stored as JSON; nil stores nothing.
+func (s *NotificationStore) CreateNotification(ctx context.Context, userID, title, body, notificationType string, data interface{}) error {
+	var dataJSON []byte
+	if data != nil {
+		var err error
+		dataJSON, err = json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification data: %w", err)
+		}
+	}
+
+	query := `INSERT INTO notifications (user_id, title, body, type, data) VALUES ($1, $2, $3, $4, $5)`
+	_, err := s.postgres.DB.ExecContext(ctx, query, userID, title, body, notificationType, dataJSON)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// GetNotifications retrieves a page of the user's stored notifications, unread first and then newest first.
+// Returns the page, the total number of notifications and how many are unread.
+func (s *NotificationStore) GetNotifications(ctx context.Context, userID string, limit, offset int) ([]Notification, int, int, error) {
+	var total, unread int
+	countQuery := `SELECT COUNT(*), COUNT(*) FILTER (WHERE NOT is_read) FROM notifications WHERE user_id = $1`
+	if err := s.postgres.DB.QueryRowContext(ctx, countQuery, userID).Scan(&total, &unread); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, title, body, type, data, is_read, created_at
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY is_read ASC, created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var n Notification
+		var data []byte
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Title, &n.Body, &n.Type, &data, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if len(data) > 0 {
+			n.Data = json.RawMessage(data)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, fmt.Errorf("error iterating notification rows: %w", err)
+	}
+
+	return notifications, total, unread, nil
+}
+
+// MarkRead marks one of the user's notifications as read. Marking an already read notification succeeds.
+// Returns "notification not found" if the notification does not exist or belongs to another user.
+func (s *NotificationStore) MarkRead(ctx context.Context, userID, notificationID string) error {
+	query := `UPDATE notifications SET is_read = true WHERE id = $1 AND user_id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+// MarkAllRead marks all of the user's unread notifications as read and returns how many were updated
+func (s *NotificationStore) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	query := `UPDATE notifications SET is_read = true WHERE user_id = $1 AND NOT is_read`
+	result, err := s.postgres.DB.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,65 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestNotificationStoreMarkRead(t *testing.T) {
+	const notificationID = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	tests := []struct {
+		name     string
+		affected []int64 // rows updated by each successive call
+		wantErr  []string
+	}{
+		// The update doesn't filter on is_read, so a second call still matches the row
+		{name: "marking twice succeeds", affected: []int64{1, 1}, wantErr: []string{"", ""}},
+		{name: "unknown or someone else's", affected: []int64{0}, wantErr: []string{"notification not found"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			for _, affected := range tt.affected {
+				mockDB.ExpectExec(`^UPDATE notifications SET is_read = true WHERE id = \$1 AND user_id = \$2$`).
+					WithArgs(notificationID, testutil.TestUserID).
+					WillReturnResult(affected)
+			}
+
+			notificationStore := store.NewNotificationStore(postgres)
+			for i, wantErr := range tt.wantErr {
+				err := notificationStore.MarkRead(context.Background(), testutil.TestUserID, notificationID)
+				if wantErr == "" && err != nil {
+					t.Errorf("call %d: %v", i+1, err)
+				}
+				if wantErr != "" && (err == nil || err.Error() != wantErr) {
+					t.Errorf("call %d: err = %v, want %q", i+1, err, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestNotificationStoreMarkAllRead(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	// Only unread rows are updated, so repeating the call updates nothing and still succeeds
+	for _, affected := range []int64{3, 0} {
+		mockDB.ExpectExec(`UPDATE notifications SET is_read = true WHERE user_id = \$1 AND NOT is_read`).
+			WithArgs(testutil.TestUserID).
+			WillReturnResult(affected)
+	}
+
+	notificationStore := store.NewNotificationStore(postgres)
+	for _, want := range []int64{3, 0} {
+		updated, err := notificationStore.MarkAllRead(context.Background(), testutil.TestUserID)
+		if err != nil {
+			t.Fatalf("MarkAllRead: %v", err)
+		}
+		if updated != want {
+			t.Errorf("updated = %d, want %d", updated, want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// Onboarding checklist steps
+const (
+	OnboardingStepUploadProfilePic  = "upload_profile_pic"
+	OnboardingStepUploadResume      = "upload_resume"
+	OnboardingStepFollowFirstUser   = "follow_first_user"
+	OnboardingStepCompleteFirstTask = "complete_first_task"
+	OnboardingStepEarnFirstBadge    = "earn_first_badge"
+)
+
+// OnboardingSteps lists the checklist steps in display order
+var OnboardingSteps = []string{
+	OnboardingStepUploadProfilePic,
+	OnboardingStepUploadResume,
+	OnboardingStepFollowFirstUser,
+	OnboardingStepCompleteFirstTask,
+	OnboardingStepEarnFirstBadge,
+}
+
+// OnboardingCompleteBonusXP is awarded once when every onboarding step is done
+const OnboardingCompleteBonusXP = 100
+
+// onboardingCompleteMarker is stored alongside the steps once the bonus has been awarded
+const onboardingCompleteMarker = "onboarding_complete"
+
+// OnboardingStepStatus is the completion state of one checklist step
+type OnboardingStepStatus struct {
+	Step        string     `json:"step"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// OnboardingProgress is a user's onboarding checklist
+type OnboardingProgress struct {
+	Steps          []OnboardingStepStatus `json:"steps"`
+	CompletedCount int                    `json:"completed_count"`
+	TotalSteps     int                    `json:"total_steps"`
+	IsComplete     bool                   `json:"is_complete"`
+}
+
+type OnboardingStore struct {
+	postgres *db.Postgres
+}
+
+func NewOnboardingStore(postgres *db.Postgres) *OnboardingStore {
+	return &OnboardingStore{
+		postgres: postgres,
+	}
+}
+
+// MarkStep records that the user completed an onboarding step.
+// It returns true the first time the step is completed. Completing the last
+// outstanding step awards OnboardingCompleteBonusXP.
+func (s *OnboardingStore) MarkStep(ctx context.Context, userID, step string) (bool, error) {
+	result, err := s.postgres.DB.ExecContext(ctx, `
+		INSERT INTO onboarding_steps (user_id, step_name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, step_name) DO NOTHING
+	`, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark onboarding step: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := s.awardCompletionBonus(ctx, userID); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// awardCompletionBonus awards the bonus XP once all steps are done.
+// The completion marker row makes the award happen at most once per user.
+func (s *OnboardingStore) awardCompletionBonus(ctx context.Context, userID string) error {
+	var completed int
+	err := s.postgres.DB.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM onboarding_steps
+		WHERE user_id = $1 AND step_name = ANY($2)
+	`, userID, OnboardingSteps).Scan(&completed)
+	if err != nil {
+		return fmt.Errorf("failed to count onboarding steps: %w", err)
+	}
+	if completed < len(OnboardingSteps) {
+		return nil
+	}
+
+	result, err := s.postgres.DB.ExecContext(ctx, `
+		INSERT INTO onboarding_steps (user_id, step_name)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, step_name) DO NOTHING
+	`, userID, onboardingCompleteMarker)
+	if err != nil {
+		return fmt.Errorf("failed to mark onboarding complete: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil // Bonus already awarded
+	}
+
+	xpStore := NewXPStore(s.postgres)
+	_, err = xpStore.AwardXP(ctx, AwardXPRequest{
+		UserID: userID,
+		XP:     OnboardingCompleteBonusXP,
+		Source: XPSourceUserAdd,
+		Reason: onboardingCompleteMarker,
+	})
+	if err != nil {
+		// Drop the marker so the bonus is retried on the next step
+		_, _ = s.postgres.DB.ExecContext(ctx, `DELETE FROM onboarding_steps WHERE user_id = $1 AND step_name = $2`, userID, onboardingCompleteMarker)
+		return fmt.Errorf("failed to award onboarding bonus: %w", err)
+	}
+	return nil
+}
+
+// GetProgress returns the completion state of every onboarding step for a user
+func (s *OnboardingStore) GetProgress(ctx context.Context, userID string) (*OnboardingProgress, error) {
+	rows, err := s.postgres.DB.QueryContext(ctx, `
+		SELECT step_name, completed_at FROM onboarding_steps
+		WHERE user_id = $1 AND step_name = ANY($2)
+	`, userID, OnboardingSteps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get onboarding steps: %w", err)
+	}
+	defer rows.Close()
+
+	completedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var step string
+		var at time.Time
+		if err := rows.Scan(&step, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan onboarding step: %w", err)
+		}
+		completedAt[step] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating onboarding steps: %w", err)
+	}
+
+	progress := &OnboardingProgress{
+		Steps:      make([]OnboardingStepStatus, 0, len(OnboardingSteps)),
+		TotalSteps: len(OnboardingSteps),
+	}
+	for _, step := range OnboardingSteps {
+		status := OnboardingStepStatus{Step: step}
+		if at, ok := completedAt[step]; ok {
+			status.Completed = true
+			status.CompletedAt = &at
+			progress.CompletedCount++
+		}
+		progress.Steps = append(progress.Steps, status)
+	}
+	progress.IsComplete = progress.CompletedCount == progress.TotalSteps
+
+	return progress, nil
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// How long emailed password links stay valid: set-password invites for accounts created by an
+// admin, and reset links requested by a user who forgot their password
+const (
+	PasswordResetTokenTTL  = 7 * 24 * time.Hour
+	ForgotPasswordTokenTTL = time.Hour
+)
+
+type PasswordResetStore struct {
+	postgres *db.Postgres
+}
+
+func NewPasswordResetStore(postgres *db.Postgres) *PasswordResetStore {
+	return &PasswordResetStore{
+		postgres: postgres,
+	}
+}
+
+// CreateToken generates a new 64-character hex password reset token for the user, valid for ttl, and stores its hash.
+// Earlier tokens of the user are replaced so only the latest emailed link works.
+func (s *PasswordResetStore) CreateToken(ctx context.Context, userID string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID); err != nil {
+		return "", fmt.Errorf("failed to delete old password reset tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	expiresAt := time.Now().Add(ttl)
+	if _, err := tx.ExecContext(ctx, query, userID, hashVerificationToken(token), expiresAt); err != nil {
+		return "", fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return token, nil
+}
+
+// ResetPassword sets the token owner's password and consumes the token. The link was emailed to the
+// user, so their email address is marked verified too. It returns the user ID, or "invalid token"
+// when the token is unknown or expired.
+func (s *PasswordResetStore) ResetPassword(ctx context.Context, token, newPassword string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID string
+	query := `
+		DELETE FROM password_reset_tokens
+		WHERE token_hash = $1 AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err = tx.QueryRowContext(ctx, query, hashVerificationToken(token)).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("invalid token")
+		}
+		return "", fmt.Errorf("failed to check password reset token: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE users
+		SET password_hash = $1, email_verified_at = COALESCE(email_verified_at, NOW())
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, updateQuery, string(hashedPassword), userID); err != nil {
+		return "", fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// ReferralStats summarises a user's referrals
+type ReferralStats struct {
+	TotalReferrals             int            `json:"total_referrals"`
+	ReferralsWithFirstTask     int            `json:"referrals_with_first_task"`
+	TotalXPEarnedFromReferrals int            `json:"total_xp_earned_from_referrals"`
+	ReferredUsers              []ReferredUser `json:"referred_users"`
+}
+
+// ReferredUser is a user who signed up with someone's referral code
+type ReferredUser struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	AvatarURL        string    `json:"avatar_url,omitempty"`
+	XP               int       `json:"xp"`
+	Level            int       `json:"level"`
+	HasCompletedTask bool      `json:"has_completed_task"`
+	ReferredAt       time.Time `json:"referred_at"`
+}
+
+type ReferralStore struct {
+	postgres *db.Postgres
+}
+
+func NewReferralStore(postgres *db.Postgres) *ReferralStore {
+	return &ReferralStore{
+		postgres: postgres,
+	}
+}
+
+// GetReferralCount returns how many users signed up with the user's referral code
+func (s *ReferralStore) GetReferralCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM user_referrals WHERE referrer_id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count referrals: %w", err)
+	}
+	return count, nil
+}
+
+// GetReferralStats returns referral totals for a user and a page of the users they referred (newest first)
+func (s *ReferralStore) GetReferralStats(ctx context.Context, userID string, limit, offset int) (*ReferralStats, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	stats := &ReferralStats{ReferredUsers: []ReferredUser{}}
+
+	// A referral counts as active once the referred user has an approved submission
+	statsQuery := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE EXISTS(
+				SELECT 1 FROM submissions sub WHERE sub.user_id = ur.referred_id AND sub.status = 'approved'
+			))
+		FROM user_referrals ur
+		WHERE ur.referrer_id = $1
+	`
+	err := s.postgres.DB.QueryRowContext(ctx, statsQuery, userID).Scan(&stats.TotalReferrals, &stats.ReferralsWithFirstTask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral stats: %w", err)
+	}
+
+	xpQuery := `SELECT COALESCE(SUM(xp), 0) FROM xp_logs WHERE user_id = $1 AND source = $2`
+	err = s.postgres.DB.QueryRowContext(ctx, xpQuery, userID, string(XPSourceReferral)).Scan(&stats.TotalXPEarnedFromReferrals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral XP: %w", err)
+	}
+
+	usersQuery := `
+		SELECT u.id, u.name, u.avatar_url, u.xp, u.level,
+			EXISTS(SELECT 1 FROM submissions sub WHERE sub.user_id = u.id AND sub.status = 'approved'),
+			ur.created_at
+		FROM user_referrals ur
+		INNER JOIN users u ON ur.referred_id = u.id
+		WHERE ur.referrer_id = $1
+		ORDER BY ur.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, usersQuery, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query referred users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u ReferredUser
+		var avatar sql.NullString
+		err := rows.Scan(&u.ID, &u.Name, &avatar, &u.XP, &u.Level, &u.HasCompletedTask, &u.ReferredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan referred user: %w", err)
+		}
+		if avatar.Valid {
+			u.AvatarURL = avatar.String
+		}
+		stats.ReferredUsers = append(stats.ReferredUsers, u)
+	}
+
+	return stats, rows.Err()
+}
+
+// AwardReferralBonus awards xp to whoever referred referredUserID, once per referral.
+// It returns nil when the user was not referred or the bonus has already been awarded.
+func (s *ReferralStore) AwardReferralBonus(ctx context.Context, referredUserID string, xp int) (*XPLog, error) {
+	if xp <= 0 {
+		return nil, fmt.Errorf("XP amount must be greater than 0")
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claiming the referral and awarding the XP commit together, so concurrent approvals award it once
+	var referrerID string
+	claimQuery := `
+		UPDATE user_referrals
+		SET bonus_awarded_at = CURRENT_TIMESTAMP
+		WHERE referred_id = $1 AND bonus_awarded_at IS NULL
+		RETURNING referrer_id
+	`
+	err = tx.QueryRowContext(ctx, claimQuery, referredUserID).Scan(&referrerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim referral bonus: %w", err)
+	}
+
+	xpLog, err := awardXPTx(ctx, tx, AwardXPRequest{
+		UserID:   referrerID,
+		XP:       xp,
+		Source:   XPSourceReferral,
+		SourceID: referredUserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Badge awarding is not critical, as in AwardXP
+	_ = NewBadgeStore(s.postgres).CheckAndAwardBadges(ctx, referrerID, xpLog.NewXP, xpLog.NewLevel)
+
+	return xpLog, nil
+}
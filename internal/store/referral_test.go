@@ -0,0 +1,121 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+// referrerID is the user who referred testutil.TestUserID
+const referrerID = "abcdabcd-abcd-abcd-abcd-abcdabcdabcd"
+
+// expectReferralClaim expects the claim of TestUserID's referral; referrer is empty when there is nothing to claim
+func expectReferralClaim(mockDB *testutil.MockPostgres, referrer string) {
+	var rows [][]any
+	if referrer != "" {
+		rows = append(rows, []any{referrer})
+	}
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`UPDATE user_referrals\s+SET bonus_awarded_at = CURRENT_TIMESTAMP\s+WHERE referred_id = \$1 AND bonus_awarded_at IS NULL\s+RETURNING referrer_id`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"referrer_id"}, rows...)
+}
+
+// expectReferralAward expects referrerID to be awarded 50 XP for referring TestUserID
+func expectReferralAward(mockDB *testutil.MockPostgres) {
+	mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+		WithArgs(50, referrerID).
+		WillReturnRows([]string{"xp", "level"}, []any{int64(150), int64(1)})
+	mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+		WithArgs(testutil.AnyArg(), referrerID, "referral", testutil.TestUserID, nil, 50).
+		WillReturnRows([]string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"},
+			[]any{"log-1", referrerID, "referral", testutil.TestUserID, nil, int64(50), testutil.TestTime})
+	mockDB.ExpectQuery(`SELECT level FROM levels`).
+		WithArgs(150).
+		WillReturnRows([]string{"level"}, []any{int64(1)})
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(`FROM badges b`).
+		WithArgs(150, 1, referrerID).
+		WillReturnRows([]string{"id", "xp", "required_level"})
+}
+
+// The bonus is claimed and awarded in one transaction, so a second approval finds nothing to claim
+func TestReferralStoreAwardReferralBonusOnce(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	referralStore := store.NewReferralStore(postgres)
+
+	expectReferralClaim(mockDB, referrerID)
+	expectReferralAward(mockDB)
+	xpLog, err := referralStore.AwardReferralBonus(context.Background(), testutil.TestUserID, 50)
+	if err != nil {
+		t.Fatalf("first AwardReferralBonus: %v", err)
+	}
+	if xpLog == nil || xpLog.UserID != referrerID || xpLog.XP != 50 || xpLog.Source != "referral" || xpLog.SourceID != testutil.TestUserID {
+		t.Fatalf("first award = %+v, want 50 XP to the referrer for the referred user", xpLog)
+	}
+
+	expectReferralClaim(mockDB, "")
+	mockDB.ExpectRollback()
+	xpLog, err = referralStore.AwardReferralBonus(context.Background(), testutil.TestUserID, 50)
+	if err != nil {
+		t.Fatalf("second AwardReferralBonus: %v", err)
+	}
+	if xpLog != nil {
+		t.Errorf("second award = %+v, want none", xpLog)
+	}
+}
+
+func TestReferralStoreAwardReferralBonus(t *testing.T) {
+	tests := []struct {
+		name    string
+		xp      int
+		expect  func(mockDB *testutil.MockPostgres)
+		wantErr string
+	}{
+		{
+			name: "not referred",
+			xp:   50,
+			expect: func(mockDB *testutil.MockPostgres) {
+				expectReferralClaim(mockDB, "")
+				mockDB.ExpectRollback()
+			},
+		},
+		{
+			// The claim is rolled back with the award, so a later approval can try again
+			name: "referrer deleted",
+			xp:   50,
+			expect: func(mockDB *testutil.MockPostgres) {
+				expectReferralClaim(mockDB, referrerID)
+				mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+					WithArgs(50, referrerID).
+					WillReturnRows([]string{"xp", "level"})
+				mockDB.ExpectRollback()
+			},
+			wantErr: "user not found",
+		},
+		{name: "no bonus", xp: 0, expect: func(mockDB *testutil.MockPostgres) {}, wantErr: "XP amount must be greater than 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			tt.expect(mockDB)
+
+			xpLog, err := store.NewReferralStore(postgres).AwardReferralBonus(context.Background(), testutil.TestUserID, tt.xp)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AwardReferralBonus: %v", err)
+			}
+			if xpLog != nil {
+				t.Errorf("award = %+v, want none", xpLog)
+			}
+		})
+	}
+}
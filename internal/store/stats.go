@@ -0,0 +1,297 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// StatsTopUser is the highest-XP student of a college or state
+type StatsTopUser struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	XP        int    `json:"xp"`
+}
+
+// ScopeStats are the aggregate statistics shared by college and state pages.
+// Only students are counted.
+type ScopeStats struct {
+	StudentCount        int           `json:"student_count"`
+	TotalTasksCompleted int           `json:"total_tasks_completed"`
+	TotalXPEarned       int64         `json:"total_xp_earned"`
+	AverageXPPerStudent float64       `json:"average_xp_per_student"`
+	BadgeCountTotal     int           `json:"badge_count_total"`
+	TopUser             *StatsTopUser `json:"top_user"` // nil when the scope has no students
+}
+
+// CollegeStats are the public statistics of a college
+type CollegeStats struct {
+	CollegeID   string `json:"college_id"`
+	CollegeName string `json:"college_name"`
+	ScopeStats
+}
+
+// StateStats are the public statistics of a state
+type StateStats struct {
+	StateID      string `json:"state_id"`
+	StateName    string `json:"state_name"`
+	CollegeCount int    `json:"college_count"`
+	ScopeStats
+}
+
+type StatsStore struct {
+	postgres *db.Postgres
+}
+
+func NewStatsStore(postgres *db.Postgres) *StatsStore {
+	return &StatsStore{
+		postgres: postgres,
+	}
+}
+
+// GetCollegeStats aggregates statistics over the students of a college
+func (s *StatsStore) GetCollegeStats(ctx context.Context, collegeID string) (*CollegeStats, error) {
+	collegeStore := NewCollegeStore(s.postgres)
+	college, err := collegeStore.GetCollegeByID(ctx, collegeID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeStats, err := s.getScopeStats(ctx, "college_id", collegeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollegeStats{
+		CollegeID:   college.ID,
+		CollegeName: college.Name,
+		ScopeStats:  *scopeStats,
+	}, nil
+}
+
+// GetStateStats aggregates statistics over the students of a state
+func (s *StatsStore) GetStateStats(ctx context.Context, stateID string) (*StateStats, error) {
+	stateStore := NewStateStore(s.postgres)
+	state, err := stateStore.GetStateByID(ctx, stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeStats, err := s.getScopeStats(ctx, "state_id", stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var collegeCount int
+	err = s.postgres.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM colleges WHERE state_id = $1`, stateID).Scan(&collegeCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count colleges: %w", err)
+	}
+
+	return &StateStats{
+		StateID:      state.ID,
+		StateName:    state.Name,
+		CollegeCount: collegeCount,
+		ScopeStats:   *scopeStats,
+	}, nil
+}
+
+// getScopeStats aggregates over students whose column (college_id or state_id) equals scopeID
+func (s *StatsStore) getScopeStats(ctx context.Context, column, scopeID string) (*ScopeStats, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE role = 'student' AND %[1]s = $1),
+			(SELECT COALESCE(SUM(xp), 0) FROM users WHERE role = 'student' AND %[1]s = $1),
+			(SELECT COUNT(*) FROM submissions sub
+				INNER JOIN users u ON u.id = sub.user_id
+				WHERE sub.status = 'approved' AND u.role = 'student' AND u.%[1]s = $1),
+			(SELECT COUNT(*) FROM user_badges ub
+				INNER JOIN users u ON u.id = ub.user_id
+				WHERE u.role = 'student' AND u.%[1]s = $1)
+	`, column)
+
+	var stats ScopeStats
+	err := s.postgres.DB.QueryRowContext(ctx, query, scopeID).Scan(
+		&stats.StudentCount, &stats.TotalXPEarned, &stats.TotalTasksCompleted, &stats.BadgeCountTotal,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+	if stats.StudentCount > 0 {
+		stats.AverageXPPerStudent = float64(stats.TotalXPEarned) / float64(stats.StudentCount)
+	}
+
+	topUserQuery := fmt.Sprintf(`
+		SELECT id, name, avatar_url, xp FROM users
+		WHERE role = 'student' AND %s = $1
+		ORDER BY xp DESC, created_at ASC
+		LIMIT 1
+	`, column)
+	var topUser StatsTopUser
+	var avatarURL sql.NullString
+	err = s.postgres.DB.QueryRowContext(ctx, topUserQuery, scopeID).Scan(&topUser.ID, &topUser.Name, &avatarURL, &topUser.XP)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get top user: %w", err)
+	}
+	if err == nil {
+		topUser.AvatarURL = avatarURL.String
+		stats.TopUser = &topUser
+	}
+
+	return &stats, nil
+}
+
+// UserStats is the summary shown on a user's home screen
+type UserStats struct {
+	TotalXP        int `json:"total_xp"`
+	Level          int `json:"level"`
+	Rank           int `json:"rank"`
+	TasksCompleted int `json:"tasks_completed"`
+	TasksPending   int `json:"tasks_pending"`
+	XPThisWeek     int `json:"xp_this_week"`
+	StreakDays     int `json:"streak_days"`
+	BadgeCount     int `json:"badge_count"`
+	FollowersCount int `json:"followers_count"`
+	FollowingCount int `json:"following_count"`
+}
+
+// GetUserStats computes a user's home screen summary in a single query. Rank is the all-time
+// pan-India rank, tasks pending are submissions awaiting review and XP this week counts from Monday.
+// Returns "user not found" if the user does not exist.
+func (s *StatsStore) GetUserStats(ctx context.Context, userID string) (*UserStats, error) {
+	query := `
+		WITH me AS (
+			SELECT xp, level, streak_days, created_at FROM users WHERE id = $1
+		), user_rank AS (
+			SELECT COUNT(*) + 1 AS rank FROM users o, me
+			WHERE o.role = 'student'
+			AND (o.xp > me.xp OR (o.xp = me.xp AND o.created_at < me.created_at))
+		), subs AS (
+			SELECT
+				COUNT(*) FILTER (WHERE status = 'approved') AS completed,
+				COUNT(*) FILTER (WHERE status = 'pending') AS pending
+			FROM submissions WHERE user_id = $1
+		), week AS (
+			SELECT COALESCE(SUM(xp), 0) AS xp FROM xp_logs
+			WHERE user_id = $1 AND created_at >= DATE_TRUNC('week', NOW())
+		), badges AS (
+			SELECT COUNT(*) AS count FROM user_badges WHERE user_id = $1
+		), followers AS (
+			SELECT COUNT(*) AS count FROM user_follows WHERE following_id = $1
+		), following AS (
+			SELECT COUNT(*) AS count FROM user_follows WHERE follower_id = $1
+		)
+		SELECT me.xp, me.level, user_rank.rank, subs.completed, subs.pending, week.xp,
+			me.streak_days, badges.count, followers.count, following.count
+		FROM me, user_rank, subs, week, badges, followers, following
+	`
+
+	var stats UserStats
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(
+		&stats.TotalXP, &stats.Level, &stats.Rank, &stats.TasksCompleted, &stats.TasksPending,
+		&stats.XPThisWeek, &stats.StreakDays, &stats.BadgeCount, &stats.FollowersCount, &stats.FollowingCount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// DashboardStats are the platform-wide figures shown on the admin dashboard. Users are students only;
+// "today" and "this week" start at midnight and Monday (database time zone).
+type DashboardStats struct {
+	TotalUsers         int   `json:"total_users"`
+	NewUsersToday      int   `json:"new_users_today"`
+	NewUsersThisWeek   int   `json:"new_users_this_week"`
+	TotalTasks         int   `json:"total_tasks"`
+	TotalSubmissions   int   `json:"total_submissions"`
+	PendingSubmissions int   `json:"pending_submissions"`
+	ApprovedToday      int   `json:"approved_today"`
+	RejectedToday      int   `json:"rejected_today"`
+	TotalXPAwarded     int64 `json:"total_xp_awarded"` // Sum of positive xp_logs entries; deductions are not subtracted
+	ActiveStreaks      int   `json:"active_streaks"`   // Streaks still alive: checked in today or yesterday
+}
+
+// GetDashboardStats computes the admin dashboard figures in a single query
+func (s *StatsStore) GetDashboardStats(ctx context.Context) (*DashboardStats, error) {
+	query := `
+		SELECT u.total, u.today, u.week, u.active_streaks,
+			(SELECT COUNT(*) FROM tasks),
+			sub.total, sub.pending, sub.approved_today, sub.rejected_today,
+			(SELECT COALESCE(SUM(xp), 0) FROM xp_logs WHERE xp > 0)
+		FROM (
+			SELECT
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE) AS today,
+				COUNT(*) FILTER (WHERE created_at >= DATE_TRUNC('week', CURRENT_DATE)) AS week,
+				COUNT(*) FILTER (WHERE streak_days > 0 AND last_checkin_date >= CURRENT_DATE - 1) AS active_streaks
+			FROM users
+			WHERE role = 'student'
+		) u, (
+			SELECT
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+				COUNT(*) FILTER (WHERE status = 'approved' AND updated_at >= CURRENT_DATE) AS approved_today,
+				COUNT(*) FILTER (WHERE status = 'rejected' AND updated_at >= CURRENT_DATE) AS rejected_today
+			FROM submissions
+		) sub
+	`
+
+	var stats DashboardStats
+	err := s.postgres.DB.QueryRowContext(ctx, query).Scan(
+		&stats.TotalUsers, &stats.NewUsersToday, &stats.NewUsersThisWeek, &stats.ActiveStreaks,
+		&stats.TotalTasks,
+		&stats.TotalSubmissions, &stats.PendingSubmissions, &stats.ApprovedToday, &stats.RejectedToday,
+		&stats.TotalXPAwarded,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// StateUserCount is the number of students in a state
+type StateUserCount struct {
+	StateID   string `json:"state_id"`
+	StateName string `json:"state_name"`
+	UserCount int    `json:"user_count"`
+}
+
+// GetUserCountByState counts the students of every state, including states with none, largest first
+func (s *StatsStore) GetUserCountByState(ctx context.Context) ([]StateUserCount, error) {
+	query := `
+		SELECT st.id, st.name, COUNT(u.id)
+		FROM states st
+		LEFT JOIN users u ON u.state_id = st.id AND u.role = 'student'
+		GROUP BY st.id, st.name
+		ORDER BY COUNT(u.id) DESC, st.name ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by state: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []StateUserCount{}
+	for rows.Next() {
+		var count StateUserCount
+		if err := rows.Scan(&count.StateID, &count.StateName, &count.UserCount); err != nil {
+			return nil, fmt.Errorf("failed to scan state user count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating state user counts: %w", err)
+	}
+
+	return counts, nil
+}
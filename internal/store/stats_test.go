@@ -0,0 +1,119 @@
+package store_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestStatsStoreGetUserStats(t *testing.T) {
+	columns := []string{"xp", "level", "rank", "completed", "pending", "xp", "streak_days", "count", "count", "count"}
+	tests := []struct {
+		name    string
+		row     []any // nil for an unknown user
+		want    store.UserStats
+		wantErr string
+	}{
+		{
+			name: "counts",
+			row:  []any{int64(1250), int64(4), int64(17), int64(9), int64(2), int64(180), int64(6), int64(3), int64(41), int64(12)},
+			want: store.UserStats{TotalXP: 1250, Level: 4, Rank: 17, TasksCompleted: 9, TasksPending: 2, XPThisWeek: 180, StreakDays: 6, BadgeCount: 3, FollowersCount: 41, FollowingCount: 12},
+		},
+		{
+			name: "new user",
+			row:  []any{int64(0), int64(1), int64(230), int64(0), int64(0), int64(0), int64(0), int64(0), int64(0), int64(0)},
+			want: store.UserStats{Level: 1, Rank: 230},
+		},
+		{name: "unknown user", wantErr: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			var rows [][]any
+			if tt.row != nil {
+				rows = append(rows, tt.row)
+			}
+			// One round trip, each figure from its own CTE
+			mockDB.ExpectQuery(`WITH me AS \([\s\S]+`+
+				`COUNT\(\*\) FILTER \(WHERE status = 'approved'\) AS completed,\s+COUNT\(\*\) FILTER \(WHERE status = 'pending'\) AS pending\s+FROM submissions WHERE user_id = \$1[\s\S]+`+
+				`FROM xp_logs\s+WHERE user_id = \$1 AND created_at >= DATE_TRUNC\('week', NOW\(\)\)[\s\S]+`+
+				`FROM user_badges WHERE user_id = \$1[\s\S]+`+
+				`FROM user_follows WHERE following_id = \$1[\s\S]+`+
+				`FROM user_follows WHERE follower_id = \$1[\s\S]+`+
+				`FROM me, user_rank, subs, week, badges, followers, following`).
+				WithArgs(testutil.TestUserID).
+				WillReturnRows(columns, rows...)
+
+			stats, err := store.NewStatsStore(postgres).GetUserStats(context.Background(), testutil.TestUserID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetUserStats: %v", err)
+			}
+			if *stats != tt.want {
+				t.Errorf("stats = %+v, want %+v", *stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsStoreGetDashboardStats(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	// Each figure is a conditional aggregate over one scan of users and one of submissions
+	mockDB.ExpectQuery(`\(SELECT COUNT\(\*\) FROM tasks\)[\s\S]+`+
+		`\(SELECT COALESCE\(SUM\(xp\), 0\) FROM xp_logs WHERE xp > 0\)[\s\S]+`+
+		`COUNT\(\*\) FILTER \(WHERE created_at >= CURRENT_DATE\) AS today,\s+`+
+		`COUNT\(\*\) FILTER \(WHERE created_at >= DATE_TRUNC\('week', CURRENT_DATE\)\) AS week,\s+`+
+		`COUNT\(\*\) FILTER \(WHERE streak_days > 0 AND last_checkin_date >= CURRENT_DATE - 1\) AS active_streaks\s+`+
+		`FROM users\s+WHERE role = 'student'[\s\S]+`+
+		`COUNT\(\*\) FILTER \(WHERE status = 'pending'\) AS pending,\s+`+
+		`COUNT\(\*\) FILTER \(WHERE status = 'approved' AND updated_at >= CURRENT_DATE\) AS approved_today,\s+`+
+		`COUNT\(\*\) FILTER \(WHERE status = 'rejected' AND updated_at >= CURRENT_DATE\) AS rejected_today\s+`+
+		`FROM submissions`).
+		WillReturnRows([]string{"total", "today", "week", "active_streaks", "count", "total", "pending", "approved_today", "rejected_today", "coalesce"},
+			[]any{int64(1200), int64(14), int64(90), int64(310), int64(48), int64(5400), int64(230), int64(61), int64(9), int64(3_000_000_000)})
+
+	stats, err := store.NewStatsStore(postgres).GetDashboardStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetDashboardStats: %v", err)
+	}
+	want := store.DashboardStats{
+		TotalUsers: 1200, NewUsersToday: 14, NewUsersThisWeek: 90, ActiveStreaks: 310,
+		TotalTasks:       48,
+		TotalSubmissions: 5400, PendingSubmissions: 230, ApprovedToday: 61, RejectedToday: 9,
+		// Lifetime XP outgrows an int32
+		TotalXPAwarded: 3_000_000_000,
+	}
+	if *stats != want {
+		t.Errorf("stats = %+v, want %+v", *stats, want)
+	}
+}
+
+func TestStatsStoreGetUserCountByState(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	// States without students are kept by the LEFT JOIN
+	mockDB.ExpectQuery(`FROM states st\s+LEFT JOIN users u ON u.state_id = st.id AND u.role = 'student'\s+GROUP BY st.id, st.name\s+ORDER BY COUNT\(u.id\) DESC, st.name ASC`).
+		WillReturnRows([]string{"id", "name", "count"},
+			[]any{testutil.TestStateID, "Karnataka", int64(12)},
+			[]any{"ffffffff-ffff-ffff-ffff-ffffffffffff", "Goa", int64(0)})
+
+	counts, err := store.NewStatsStore(postgres).GetUserCountByState(context.Background())
+	if err != nil {
+		t.Fatalf("GetUserCountByState: %v", err)
+	}
+	want := []store.StateUserCount{
+		{StateID: testutil.TestStateID, StateName: "Karnataka", UserCount: 12},
+		{StateID: "ffffffff-ffff-ffff-ffff-ffffffffffff", StateName: "Goa", UserCount: 0},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %+v, want %+v", counts, want)
+	}
+}
@@ -3,12 +3,17 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/rohit21755/groveserverv2/internal/db"
 )
 
+// ErrStreakFreezeHeld is returned by BuyStreakFreeze when the user already holds an unused streak freeze
+var ErrStreakFreezeHeld = errors.New("already holding an unused streak freeze")
+
 type StreakStore struct {
 	postgres *db.Postgres
 }
@@ -20,84 +25,275 @@ func NewStreakStore(postgres *db.Postgres) *StreakStore {
 }
 
 // UpdateStreak updates or creates a streak for a user
-// This should be called daily when user is active
+// This should be called daily when user is active. Repeat calls on the same day are no-ops:
+// the user row is locked while last_checkin_date is checked, so concurrent check-ins count once.
+// When exactly one day was missed, an unused streak freeze is used up to keep the streak going.
 func (s *StreakStore) UpdateStreak(ctx context.Context, userID string) error {
-	// Get current user streak info
-	var streakStartedAt sql.NullTime
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// today comes from the database so it matches the dates stored in last_checkin_date
 	var streakDays int
-	query := `SELECT streak_started_at, streak_days FROM users WHERE id = $1`
-	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&streakStartedAt, &streakDays)
+	var streakStartedAt, lastCheckinDate sql.NullTime
+	var today time.Time
+	query := `SELECT streak_days, streak_started_at, last_checkin_date, CURRENT_DATE FROM users WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, userID).Scan(&streakDays, &streakStartedAt, &lastCheckinDate, &today)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
 		return fmt.Errorf("failed to get user streak: %w", err)
 	}
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	var newStreakDays int
-	var newStreakStartedAt time.Time
-
-	if !streakStartedAt.Valid {
-		// First streak - start today
-		newStreakDays = 1
-		newStreakStartedAt = today
-	} else {
-		lastActive := time.Date(
-			streakStartedAt.Time.Year(),
-			streakStartedAt.Time.Month(),
-			streakStartedAt.Time.Day(),
-			0, 0, 0, 0,
-			streakStartedAt.Time.Location(),
-		)
-		daysDiff := int(today.Sub(lastActive).Hours() / 24)
-
-		if daysDiff == 0 {
-			// Already updated today, no change
-			return nil
-		} else if daysDiff == 1 {
-			// Consecutive day - increment streak and record today as last active
-			newStreakDays = streakDays + 1
-			newStreakStartedAt = today
-		} else {
-			// Streak broken - reset to 1
-			newStreakDays = 1
-			newStreakStartedAt = today
+	var startedAt, lastCheckin *time.Time
+	if streakStartedAt.Valid {
+		startedAt = &streakStartedAt.Time
+	}
+	if lastCheckinDate.Valid {
+		lastCheckin = &lastCheckinDate.Time
+	}
+	frozen := false
+	if lastCheckin != nil && daysBetween(*lastCheckin, today) == 2 {
+		if frozen, err = useStreakFreeze(ctx, tx, userID); err != nil {
+			return err
 		}
 	}
+	streakDays, newStartedAt, checkedIn := advanceStreak(streakDays, startedAt, lastCheckin, today, frozen)
+	if !checkedIn {
+		return nil
+	}
 
-	// Update user streak
 	updateQuery := `
 		UPDATE users
-		SET streak_started_at = $1, streak_days = $2
-		WHERE id = $3
+		SET streak_days = $1, streak_started_at = $2, last_checkin_at = NOW(), last_checkin_date = $3
+		WHERE id = $4
 	`
-	_, err = s.postgres.DB.ExecContext(ctx, updateQuery, newStreakStartedAt, newStreakDays, userID)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, updateQuery, streakDays, newStartedAt, today, userID); err != nil {
 		return fmt.Errorf("failed to update streak: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit streak: %w", err)
+	}
 
+	s.recordStreakMilestone(ctx, userID, streakDays)
 	return nil
 }
 
-// GetUserStreak retrieves streak information for a user
-func (s *StreakStore) GetUserStreak(ctx context.Context, userID string) (int, *time.Time, error) {
+// advanceStreak returns the streak after a check-in on today. The day difference is measured from
+// the last check-in: the next day extends the streak, a gap resets it to 1 and starts a new streak
+// today, and streak_started_at only moves on a reset. frozen means a streak freeze covers a single
+// missed day, which then extends the streak too. checkedIn is false when the user has already
+// checked in today, in which case nothing changes.
+func advanceStreak(streakDays int, startedAt, lastCheckin *time.Time, today time.Time, frozen bool) (int, time.Time, bool) {
+	if lastCheckin != nil {
+		switch days := daysBetween(*lastCheckin, today); {
+		case days == 0:
+			return streakDays, time.Time{}, false
+		case days == 1, days == 2 && frozen:
+			// Streaks from before streak_started_at was tracked start counting from today
+			if startedAt == nil {
+				return streakDays + 1, today, true
+			}
+			return streakDays + 1, *startedAt, true
+		}
+	}
+	return 1, today, true
+}
+
+// useStreakFreeze marks one of the user's unused streak freezes as used inside tx and reports whether there was one
+func useStreakFreeze(ctx context.Context, tx *sql.Tx, userID string) (bool, error) {
+	query := `
+		UPDATE streak_freezes
+		SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM streak_freezes
+			WHERE user_id = $1 AND used_at IS NULL
+			ORDER BY created_at
+			LIMIT 1
+		)
+	`
+	result, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to use streak freeze: %w", err)
+	}
+	used, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to use streak freeze: %w", err)
+	}
+	return used > 0, nil
+}
+
+// daysBetween counts calendar days from from to to, ignoring the time of day
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// recordStreakMilestone adds a streak_milestone activity when streakDays is one of StreakMilestones.
+// Failures are only logged; the check-in has already been saved.
+func (s *StreakStore) recordStreakMilestone(ctx context.Context, userID string, streakDays int) {
+	for _, milestone := range StreakMilestones {
+		if milestone != streakDays {
+			continue
+		}
+		activityStore := NewActivityLogStore(s.postgres)
+		if err := activityStore.RecordActivity(ctx, RecordActivityRequest{
+			UserID:      userID,
+			EventType:   ActivityEventStreakMilestone,
+			Description: fmt.Sprintf("Reached a %d-day streak", streakDays),
+		}); err != nil {
+			log.Printf("[Activity] Failed to record streak milestone for user %s: %v", userID, err)
+		}
+		return
+	}
+}
+
+// GetUserStreak retrieves a user's streak length, when the current streak started and when they last checked in
+func (s *StreakStore) GetUserStreak(ctx context.Context, userID string) (int, *time.Time, *time.Time, error) {
 	var streakDays int
-	var streakStartedAt sql.NullTime
-	query := `SELECT streak_days, streak_started_at FROM users WHERE id = $1`
-	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&streakDays, &streakStartedAt)
+	var streakStartedAt, lastCheckinAt sql.NullTime
+	query := `SELECT streak_days, streak_started_at, last_checkin_at FROM users WHERE id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&streakDays, &streakStartedAt, &lastCheckinAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return 0, nil, fmt.Errorf("user not found")
+			return 0, nil, nil, fmt.Errorf("user not found")
 		}
-		return 0, nil, fmt.Errorf("failed to get user streak: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to get user streak: %w", err)
 	}
 
-	var startedAt *time.Time
+	var startedAt, lastCheckin *time.Time
 	if streakStartedAt.Valid {
 		startedAt = &streakStartedAt.Time
 	}
+	if lastCheckinAt.Valid {
+		lastCheckin = &lastCheckinAt.Time
+	}
+
+	return streakDays, startedAt, lastCheckin, nil
+}
+
+// StreakMilestones are the streak lengths (in days) celebrated as milestones
+var StreakMilestones = []int{3, 7, 14, 30, 60, 100, 365}
 
-	return streakDays, startedAt, nil
+// StreakDetails is the full streak status of a user
+type StreakDetails struct {
+	StreakDays          int        `json:"streak_days"`
+	StreakStartedAt     *time.Time `json:"streak_started_at,omitempty"`
+	LastCheckinAt       *time.Time `json:"last_checkin_at,omitempty"`
+	NextMilestone       *int       `json:"next_milestone"`
+	XPAvailableToRedeem int        `json:"xp_available_to_redeem"`
+	FreezeAvailable     bool       `json:"freeze_available"`
+}
+
+// streakBaseXP returns the base XP reward for a streak: 10 XP per day, capped at 100 XP
+func streakBaseXP(streakDays int) int {
+	baseXP := streakDays * 10
+	if baseXP > 100 {
+		baseXP = 100
+	}
+	return baseXP
+}
+
+// GetStreakDetails retrieves the streak status of a user without recording a check-in
+func (s *StreakStore) GetStreakDetails(ctx context.Context, userID string) (*StreakDetails, error) {
+	var details StreakDetails
+	var streakStartedAt, lastCheckinAt sql.NullTime
+	query := `SELECT streak_days, streak_started_at, last_checkin_at FROM users WHERE id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&details.StreakDays, &streakStartedAt, &lastCheckinAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user streak: %w", err)
+	}
+	if streakStartedAt.Valid {
+		details.StreakStartedAt = &streakStartedAt.Time
+	}
+	if lastCheckinAt.Valid {
+		details.LastCheckinAt = &lastCheckinAt.Time
+	}
+
+	for _, milestone := range StreakMilestones {
+		if milestone > details.StreakDays {
+			m := milestone
+			details.NextMilestone = &m
+			break
+		}
+	}
+
+	// Streak XP can be redeemed once per day
+	var claimedToday bool
+	claimedQuery := `
+		SELECT EXISTS(
+			SELECT 1 FROM xp_logs
+			WHERE user_id = $1 AND source = $2 AND created_at >= CURRENT_DATE
+		)
+	`
+	err = s.postgres.DB.QueryRowContext(ctx, claimedQuery, userID, string(XPSourceDailyLogin)).Scan(&claimedToday)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check streak redemption: %w", err)
+	}
+	if !claimedToday {
+		details.XPAvailableToRedeem = streakBaseXP(details.StreakDays)
+	}
+
+	details.FreezeAvailable, err = s.HasUnusedStreakFreeze(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
+
+// HasUnusedStreakFreeze returns true if the user has a streak freeze that has not been used yet
+func (s *StreakStore) HasUnusedStreakFreeze(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM streak_freezes WHERE user_id = $1 AND used_at IS NULL)`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check streak freezes: %w", err)
+	}
+	return exists, nil
+}
+
+// BuyStreakFreeze spends cost coins on an unused streak freeze for the user and returns its ID and the new balance.
+// The freeze and the debit commit together: ErrStreakFreezeHeld is returned if the user already holds an
+// unused freeze, even when two purchases race, and ErrInsufficientCoins if the balance is lower than cost.
+func (s *StreakStore) BuyStreakFreeze(ctx context.Context, userID string, cost int) (string, int, error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// idx_streak_freezes_user_unused allows one unused freeze per user
+	var freezeID string
+	query := `
+		INSERT INTO streak_freezes (user_id) VALUES ($1)
+		ON CONFLICT (user_id) WHERE used_at IS NULL DO NOTHING
+		RETURNING id
+	`
+	err = tx.QueryRowContext(ctx, query, userID).Scan(&freezeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", 0, ErrStreakFreezeHeld
+		}
+		return "", 0, fmt.Errorf("failed to add streak freeze: %w", err)
+	}
+
+	balance, err := deductCoinsTx(ctx, tx, userID, cost, "streak_freeze")
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return freezeID, balance, nil
 }
 
 // RedeemStreakReward redeems a streak reward (XP and/or badge)
@@ -149,11 +345,7 @@ func (s *StreakStore) RedeemStreakReward(ctx context.Context, userID string, str
 	}
 
 	// Award XP for streak (base reward: 10 XP per day of streak, capped at 100 XP)
-	baseXP := streakDays * 10
-	if baseXP > 100 {
-		baseXP = 100
-	}
-	xpReward += baseXP
+	xpReward += streakBaseXP(streakDays)
 
 	// Award XP if there's any reward
 	if xpReward > 0 {
@@ -0,0 +1,172 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestStreakStoreUpdateStreak(t *testing.T) {
+	day0 := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return day0.AddDate(0, 0, n) }
+
+	// Each check-in reads the state the previous one wrote
+	steps := []struct {
+		name          string
+		today         int
+		wantCheckedIn bool
+		wantDays      int
+		wantStartedAt int
+		wantMilestone bool
+		freezeCheck   bool // Exactly one day was missed, so an unused freeze is looked for
+		freezeUsed    bool
+	}{
+		{name: "first check-in", today: 0, wantCheckedIn: true, wantDays: 1, wantStartedAt: 0},
+		{name: "same day again", today: 0, wantDays: 1, wantStartedAt: 0},
+		{name: "next day", today: 1, wantCheckedIn: true, wantDays: 2, wantStartedAt: 0},
+		{name: "third day in a row", today: 2, wantCheckedIn: true, wantDays: 3, wantStartedAt: 0, wantMilestone: true},
+		{name: "after a gap day", today: 4, wantCheckedIn: true, wantDays: 1, wantStartedAt: 4, freezeCheck: true},
+		{name: "day after the reset", today: 5, wantCheckedIn: true, wantDays: 2, wantStartedAt: 4},
+		{name: "same day after the reset", today: 5, wantDays: 2, wantStartedAt: 4},
+		{name: "gap day covered by a freeze", today: 7, wantCheckedIn: true, wantDays: 3, wantStartedAt: 4, wantMilestone: true, freezeCheck: true, freezeUsed: true},
+		{name: "two gap days", today: 10, wantCheckedIn: true, wantDays: 1, wantStartedAt: 10},
+	}
+
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	streakStore := store.NewStreakStore(postgres)
+	var streakDays int64
+	var startedAt, lastCheckin any = nil, nil
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			mockDB.ExpectBegin()
+			mockDB.ExpectQuery(`SELECT streak_days, streak_started_at, last_checkin_date, CURRENT_DATE FROM users WHERE id = \$1 FOR UPDATE`).
+				WithArgs(testutil.TestUserID).
+				WillReturnRows([]string{"streak_days", "streak_started_at", "last_checkin_date", "current_date"},
+					[]any{streakDays, startedAt, lastCheckin, day(step.today)})
+			if step.freezeCheck {
+				var used int64
+				if step.freezeUsed {
+					used = 1
+				}
+				mockDB.ExpectExec(`UPDATE streak_freezes\s+SET used_at = NOW\(\)\s+WHERE id = \(\s+SELECT id FROM streak_freezes\s+WHERE user_id = \$1 AND used_at IS NULL`).
+					WithArgs(testutil.TestUserID).
+					WillReturnResult(used)
+			}
+			if step.wantCheckedIn {
+				mockDB.ExpectExec(`UPDATE users\s+SET streak_days = \$1, streak_started_at = \$2, last_checkin_at = NOW\(\), last_checkin_date = \$3\s+WHERE id = \$4`).
+					WithArgs(step.wantDays, day(step.wantStartedAt), day(step.today), testutil.TestUserID).
+					WillReturnResult(1)
+				mockDB.ExpectCommit()
+				if step.wantMilestone {
+					mockDB.ExpectExec(`INSERT INTO user_activity_log`).
+						WithArgs(testutil.TestUserID, store.ActivityEventStreakMilestone, "", "", "Reached a 3-day streak").
+						WillReturnResult(1)
+				}
+			} else {
+				mockDB.ExpectRollback()
+			}
+
+			if err := streakStore.UpdateStreak(context.Background(), testutil.TestUserID); err != nil {
+				t.Fatalf("UpdateStreak: %v", err)
+			}
+			if err := mockDB.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+			if step.wantCheckedIn {
+				streakDays, startedAt, lastCheckin = int64(step.wantDays), day(step.wantStartedAt), day(step.today)
+			}
+		})
+	}
+}
+
+func TestStreakStoreUpdateStreakUnknownUser(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`FROM users WHERE id = \$1 FOR UPDATE`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"streak_days", "streak_started_at", "last_checkin_date", "current_date"})
+	mockDB.ExpectRollback()
+
+	err := store.NewStreakStore(postgres).UpdateStreak(context.Background(), testutil.TestUserID)
+	if err == nil || err.Error() != "user not found" {
+		t.Fatalf("err = %v, want user not found", err)
+	}
+}
+
+func TestStreakStoreBuyStreakFreeze(t *testing.T) {
+	insertFreeze := `INSERT INTO streak_freezes \(user_id\) VALUES \(\$1\)\s+ON CONFLICT \(user_id\) WHERE used_at IS NULL DO NOTHING`
+	tests := []struct {
+		name        string
+		expect      func(mockDB *testutil.MockPostgres)
+		wantErr     error
+		wantBalance int
+	}{
+		{
+			name: "buys",
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectQuery(insertFreeze).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"id"}, []any{"freeze-1"})
+				mockDB.ExpectQuery(`UPDATE users\s+SET coins = coins - \$1`).
+					WithArgs(50, testutil.TestUserID).
+					WillReturnRows([]string{"coins"}, []any{int64(10)})
+				mockDB.ExpectExec(`INSERT INTO coin_logs`).
+					WithArgs(testutil.TestUserID, -50, 10, "streak_freeze").
+					WillReturnResult(1)
+				mockDB.ExpectCommit()
+			},
+			wantBalance: 10,
+		},
+		{
+			// The unique index on unused freezes turns a concurrent second purchase into a conflict
+			name: "already holding one",
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectQuery(insertFreeze).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"id"})
+				mockDB.ExpectRollback()
+			},
+			wantErr: store.ErrStreakFreezeHeld,
+		},
+		{
+			// The freeze is rolled back with the failed debit
+			name: "insufficient coins",
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectQuery(insertFreeze).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"id"}, []any{"freeze-1"})
+				mockDB.ExpectQuery(`UPDATE users\s+SET coins = coins - \$1`).
+					WithArgs(50, testutil.TestUserID).
+					WillReturnRows([]string{"coins"})
+				mockDB.ExpectRollback()
+			},
+			wantErr: store.ErrInsufficientCoins,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectBegin()
+			tt.expect(mockDB)
+
+			freezeID, balance, err := store.NewStreakStore(postgres).BuyStreakFreeze(context.Background(), testutil.TestUserID, 50)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuyStreakFreeze: %v", err)
+			}
+			if freezeID != "freeze-1" || balance != tt.wantBalance {
+				t.Errorf("freeze, balance = %q, %d; want freeze-1, %d", freezeID, balance, tt.wantBalance)
+			}
+		})
+	}
+}
@@ -3,24 +3,40 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/telemetry"
 )
 
 type Submission struct {
-	ID          string     `json:"id"`
-	TaskID      string     `json:"task_id"`
-	UserID      string     `json:"user_id"`
-	ProofURL    string     `json:"proof_url"`
-	Status      string     `json:"status"`
-	AdminComment string    `json:"admin_comment,omitempty"`
-	ReviewedBy  string     `json:"reviewed_by,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           string                 `json:"id"`
+	TaskID       string                 `json:"task_id"`
+	UserID       string                 `json:"user_id"`
+	ProofURL     string                 `json:"proof_url"`
+	ThumbnailURL string                 `json:"thumbnail_url,omitempty"` // Video proofs only
+	Status       string                 `json:"status"`
+	AdminComment string                 `json:"admin_comment,omitempty"`
+	ReviewedBy   string                 `json:"reviewed_by,omitempty"`
+	TaskTitle    string                 `json:"task_title,omitempty"`    // Set by GetAllSubmissions
+	TaskXP       int                    `json:"task_xp,omitempty"`       // Set by GetAllSubmissions
+	TaskPriority string                 `json:"task_priority,omitempty"` // Set by GetAllSubmissions and ListSubmissions
+	LinkPreview  *SubmissionLinkPreview `json:"link_preview,omitempty"`  // Link proofs only, set by GetSubmissionByID once fetched
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// SubmissionLinkPreview is the Open Graph metadata fetched for a link proof
+type SubmissionLinkPreview struct {
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
 }
 
 type SubmissionStore struct {
@@ -35,23 +51,54 @@ func NewSubmissionStore(postgres *db.Postgres) *SubmissionStore {
 
 // CreateSubmissionRequest represents the request to create a submission
 type CreateSubmissionRequest struct {
-	TaskID   string `json:"task_id"`
-	UserID   string `json:"user_id"`
-	ProofURL string `json:"proof_url"`
+	TaskID       string `json:"task_id"`
+	UserID       string `json:"user_id"`
+	ProofURL     string `json:"proof_url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"` // Optional: thumbnail for video proofs
 }
 
-// GetSubmissionByTaskAndUser retrieves a submission by task ID and user ID
+// currentISOWeek is the week_number of a weekly task submission made now: ISO year * 100 + ISO week.
+// Submissions of other tasks have week_number 0.
+const currentISOWeek = `(EXTRACT(ISOYEAR FROM NOW()) * 100 + EXTRACT(WEEK FROM NOW()))::int`
+
+// currentWeekSubmission limits submissions (aliased s) of weekly tasks (aliased t) to the current ISO week,
+// so each week's submission of a weekly task is independent. Submissions of other tasks always match.
+const currentWeekSubmission = `(NOT t.is_weekly OR s.week_number = ` + currentISOWeek + `)`
+
+// GetSubmissionByTaskAndUser retrieves a submission by task ID and user ID.
+// For weekly tasks only this week's submission is returned.
 func (s *SubmissionStore) GetSubmissionByTaskAndUser(ctx context.Context, taskID, userID string) (*Submission, error) {
 	query := `
-		SELECT id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
-		FROM submissions WHERE task_id = $1 AND user_id = $2
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.thumbnail_url, s.status, s.admin_comment, s.reviewed_by, s.created_at, s.updated_at
+		FROM submissions s
+		JOIN tasks t ON t.id = s.task_id
+		WHERE s.task_id = $1 AND s.user_id = $2 AND ` + currentWeekSubmission + `
+		ORDER BY s.created_at DESC
+		LIMIT 1
+	`
+	return s.getTaskUserSubmission(ctx, query, taskID, userID)
+}
+
+// GetLatestApprovedSubmission retrieves the user's most recent approved submission of a task.
+// Unlike GetSubmissionByTaskAndUser it looks across every week of a weekly task.
+func (s *SubmissionStore) GetLatestApprovedSubmission(ctx context.Context, taskID, userID string) (*Submission, error) {
+	query := `
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.thumbnail_url, s.status, s.admin_comment, s.reviewed_by, s.created_at, s.updated_at
+		FROM submissions s
+		WHERE s.task_id = $1 AND s.user_id = $2 AND s.status = 'approved'
+		ORDER BY s.created_at DESC
+		LIMIT 1
 	`
+	return s.getTaskUserSubmission(ctx, query, taskID, userID)
+}
 
+// getTaskUserSubmission runs a single-submission query that takes the task ID and user ID as $1 and $2
+func (s *SubmissionStore) getTaskUserSubmission(ctx context.Context, query, taskID, userID string) (*Submission, error) {
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, taskID, userID).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
 	)
 	if err != nil {
@@ -67,28 +114,32 @@ func (s *SubmissionStore) GetSubmissionByTaskAndUser(ctx context.Context, taskID
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
 
 	return &submission, nil
 }
 
-// UpdateSubmissionProof updates the proof URL for an existing submission (for resubmission)
-func (s *SubmissionStore) UpdateSubmissionProof(ctx context.Context, submissionID, newProofURL string) (*Submission, error) {
+// UpdateSubmissionProof updates the proof URL (and thumbnail) for an existing submission (for resubmission)
+func (s *SubmissionStore) UpdateSubmissionProof(ctx context.Context, submissionID, newProofURL, newThumbnailURL string) (*Submission, error) {
 	query := `
 		UPDATE submissions
 		SET proof_url = $1,
+		    thumbnail_url = NULLIF($3, ''),
 		    status = 'pending',
 		    admin_comment = NULL,
 		    reviewed_by = NULL,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $2
-		RETURNING id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
+		RETURNING id, task_id, user_id, proof_url, thumbnail_url, status, admin_comment, reviewed_by, created_at, updated_at
 	`
 
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
 
-	err := s.postgres.DB.QueryRowContext(ctx, query, newProofURL, submissionID).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+	err := s.postgres.DB.QueryRowContext(ctx, query, newProofURL, submissionID, newThumbnailURL).Scan(
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
 	)
 	if err != nil {
@@ -104,13 +155,20 @@ func (s *SubmissionStore) UpdateSubmissionProof(ctx context.Context, submissionI
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
 
 	return &submission, nil
 }
 
 // CreateSubmission creates a new task submission
-// If a submission already exists and is rejected, it will be updated instead of creating a new one
+// If a submission already exists and is rejected, it will be updated instead of creating a new one.
+// Weekly tasks only look at this week's submission, so they can be submitted again every week.
 func (s *SubmissionStore) CreateSubmission(ctx context.Context, req CreateSubmissionRequest) (*Submission, error) {
+	ctx, span := telemetry.StartSpan(ctx, "store.CreateSubmission")
+	defer span.End()
+
 	// Check if submission already exists
 	existingSubmission, err := s.GetSubmissionByTaskAndUser(ctx, req.TaskID, req.UserID)
 	if err != nil && err.Error() != "submission not found" {
@@ -121,30 +179,39 @@ func (s *SubmissionStore) CreateSubmission(ctx context.Context, req CreateSubmis
 	if existingSubmission != nil {
 		if existingSubmission.Status == "rejected" {
 			// Allow resubmission by updating the existing rejected submission
-			return s.UpdateSubmissionProof(ctx, existingSubmission.ID, req.ProofURL)
+			return s.UpdateSubmissionProof(ctx, existingSubmission.ID, req.ProofURL, req.ThumbnailURL)
 		}
 		// If submission exists and is not rejected (pending or approved), return error
 		return nil, fmt.Errorf("submission already exists for this task with status: %s", existingSubmission.Status)
 	}
 
-	// Create submission
+	// Create submission. week_number is part of the unique index on (task_id, user_id, week_number),
+	// so a concurrent submit of the same task in the same week fails here.
 	submissionID := uuid.New().String()
 	query := `
-		INSERT INTO submissions (id, task_id, user_id, proof_url, status)
-		VALUES ($1, $2, $3, $4, 'pending')
-		RETURNING id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
+		INSERT INTO submissions (id, task_id, user_id, proof_url, thumbnail_url, status, week_number)
+		SELECT $1, t.id, $3, $4, NULLIF($5, ''), 'pending', CASE WHEN t.is_weekly THEN ` + currentISOWeek + ` ELSE 0 END
+		FROM tasks t
+		WHERE t.id = $2
+		RETURNING id, task_id, user_id, proof_url, thumbnail_url, status, admin_comment, reviewed_by, created_at, updated_at
 	`
 
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
 
-	err = s.postgres.DB.QueryRowContext(ctx, query,
-		submissionID, req.TaskID, req.UserID, req.ProofURL,
+	err = s.postgres.Traced.QueryRowContext(ctx, query,
+		submissionID, req.TaskID, req.UserID, req.ProofURL, req.ThumbnailURL,
 	).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
 	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task not found")
+		}
+		if strings.Contains(err.Error(), "idx_submissions_task_user_week") {
+			return nil, fmt.Errorf("submission already exists for this task")
+		}
 		return nil, fmt.Errorf("failed to create submission: %w", err)
 	}
 
@@ -154,6 +221,9 @@ func (s *SubmissionStore) CreateSubmission(ctx context.Context, req CreateSubmis
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
 
 	return &submission, nil
 }
@@ -161,16 +231,22 @@ func (s *SubmissionStore) CreateSubmission(ctx context.Context, req CreateSubmis
 // GetSubmissionByID retrieves a submission by ID
 func (s *SubmissionStore) GetSubmissionByID(ctx context.Context, submissionID string) (*Submission, error) {
 	query := `
-		SELECT id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
-		FROM submissions WHERE id = $1
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.thumbnail_url, s.status, s.admin_comment, s.reviewed_by, s.created_at, s.updated_at,
+			lp.title, lp.description, lp.image_url, lp.fetched_at
+		FROM submissions s
+		LEFT JOIN submission_link_previews lp ON lp.submission_id = s.id
+		WHERE s.id = $1
 	`
 
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
+	var previewTitle, previewDescription, previewImageURL sql.NullString
+	var previewFetchedAt sql.NullTime
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, submissionID).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
+		&previewTitle, &previewDescription, &previewImageURL, &previewFetchedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -185,10 +261,50 @@ func (s *SubmissionStore) GetSubmissionByID(ctx context.Context, submissionID st
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
+	if previewFetchedAt.Valid {
+		submission.LinkPreview = &SubmissionLinkPreview{
+			Title:       previewTitle.String,
+			Description: previewDescription.String,
+			ImageURL:    previewImageURL.String,
+			FetchedAt:   previewFetchedAt.Time,
+		}
+	}
 
 	return &submission, nil
 }
 
+// CountApprovedSubmissions returns how many of the user's submissions have been approved
+func (s *SubmissionStore) CountApprovedSubmissions(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM submissions WHERE user_id = $1 AND status = 'approved'`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count approved submissions: %w", err)
+	}
+	return count, nil
+}
+
+// SaveLinkPreview stores (or replaces, on resubmission) the link preview of a submission
+func (s *SubmissionStore) SaveLinkPreview(ctx context.Context, submissionID, title, description, imageURL string) error {
+	query := `
+		INSERT INTO submission_link_previews (submission_id, title, description, image_url, fetched_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		ON CONFLICT (submission_id) DO UPDATE
+		SET title = EXCLUDED.title,
+		    description = EXCLUDED.description,
+		    image_url = EXCLUDED.image_url,
+		    fetched_at = EXCLUDED.fetched_at
+	`
+	_, err := s.postgres.DB.ExecContext(ctx, query, submissionID, title, description, imageURL)
+	if err != nil {
+		return fmt.Errorf("failed to save link preview: %w", err)
+	}
+	return nil
+}
+
 // ApproveSubmission approves a submission
 func (s *SubmissionStore) ApproveSubmission(ctx context.Context, submissionID, adminUserID string, comment string) (*Submission, error) {
 	query := `
@@ -198,14 +314,14 @@ func (s *SubmissionStore) ApproveSubmission(ctx context.Context, submissionID, a
 		    admin_comment = CASE WHEN $2 != '' THEN $2 ELSE admin_comment END,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $3
-		RETURNING id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
+		RETURNING id, task_id, user_id, proof_url, thumbnail_url, status, admin_comment, reviewed_by, created_at, updated_at
 	`
 
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, adminUserID, comment, submissionID).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
 	)
 	if err != nil {
@@ -221,6 +337,9 @@ func (s *SubmissionStore) ApproveSubmission(ctx context.Context, submissionID, a
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
 
 	return &submission, nil
 }
@@ -241,14 +360,14 @@ func (s *SubmissionStore) RejectSubmission(ctx context.Context, submissionID, ad
 		    admin_comment = $2,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $3
-		RETURNING id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
+		RETURNING id, task_id, user_id, proof_url, thumbnail_url, status, admin_comment, reviewed_by, created_at, updated_at
 	`
 
 	var submission Submission
-	var adminComment, reviewedBy sql.NullString
+	var adminComment, reviewedBy, thumbnailURL sql.NullString
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, adminUserID, comment, submissionID).Scan(
-		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
+		&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
 		&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
 	)
 	if err != nil {
@@ -264,6 +383,9 @@ func (s *SubmissionStore) RejectSubmission(ctx context.Context, submissionID, ad
 	if reviewedBy.Valid {
 		submission.ReviewedBy = reviewedBy.String
 	}
+	if thumbnailURL.Valid {
+		submission.ThumbnailURL = thumbnailURL.String
+	}
 
 	// Verify the rejection was applied correctly
 	if submission.Status != "rejected" {
@@ -279,27 +401,215 @@ func (s *SubmissionStore) RejectSubmission(ctx context.Context, submissionID, ad
 	return &submission, nil
 }
 
-// GetAllSubmissions retrieves all submissions with optional filters
-func (s *SubmissionStore) GetAllSubmissions(ctx context.Context, statusFilter string) ([]Submission, error) {
-	var query string
+// MaxBulkReviewSize is the most submissions BulkApprove and BulkReject accept at once
+const MaxBulkReviewSize = 100
+
+// BulkReviewFailure explains why one submission of a bulk review could not be processed
+type BulkReviewFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// BulkReviewResult is one submission processed by BulkApprove or BulkReject
+type BulkReviewResult struct {
+	Submission Submission
+	TaskTitle  string
+	XPLog      *XPLog // BulkApprove only; nil when the task awards no XP
+}
+
+// lockPendingSubmissions locks the submissions with the given IDs inside tx and returns the task XP and title
+// of each. Every ID that does not exist or is not pending is reported as a failure.
+func lockPendingSubmissions(ctx context.Context, tx *sql.Tx, submissionIDs []string) (map[string]BulkReviewResult, []BulkReviewFailure, error) {
+	query := `
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.status, t.xp, t.title
+		FROM submissions s
+		INNER JOIN tasks t ON t.id = s.task_id
+		WHERE s.id = ANY($1::uuid[])
+		FOR UPDATE OF s
+	`
+	rows, err := tx.QueryContext(ctx, query, submissionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock submissions: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]BulkReviewResult, len(submissionIDs))
+	for rows.Next() {
+		var result BulkReviewResult
+		err := rows.Scan(
+			&result.Submission.ID, &result.Submission.TaskID, &result.Submission.UserID, &result.Submission.ProofURL,
+			&result.Submission.Status, &result.Submission.TaskXP, &result.TaskTitle,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		found[result.Submission.ID] = result
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating submissions: %w", err)
+	}
+
+	var failures []BulkReviewFailure
+	for _, id := range submissionIDs {
+		result, ok := found[id]
+		if !ok {
+			failures = append(failures, BulkReviewFailure{ID: id, Error: "submission not found"})
+		} else if result.Submission.Status != "pending" {
+			failures = append(failures, BulkReviewFailure{ID: id, Error: fmt.Sprintf("submission is already %s", result.Submission.Status)})
+		}
+	}
+	return found, failures, nil
+}
+
+// BulkApprove approves pending submissions in a single transaction, awarding each task's XP and creating
+// the feed entries. If any submission is missing, not pending or its XP cannot be awarded, nothing is changed
+// and the per-ID failures are returned instead. Badges are checked after commit.
+func (s *SubmissionStore) BulkApprove(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]BulkReviewResult, []BulkReviewFailure, error) {
+	tx, err := s.postgres.Traced.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, failures, err := lockPendingSubmissions(ctx, tx, submissionIDs)
+	if err != nil || len(failures) > 0 {
+		return nil, failures, err
+	}
+
+	query := `
+		UPDATE submissions
+		SET status = 'approved',
+		    reviewed_by = $2,
+		    admin_comment = CASE WHEN $3 != '' THEN $3 ELSE admin_comment END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1::uuid[])
+	`
+	if _, err := tx.ExecContext(ctx, query, submissionIDs, adminUserID, comment); err != nil {
+		return nil, nil, fmt.Errorf("failed to approve submissions: %w", err)
+	}
+
+	feedQuery := `
+		INSERT INTO completed_task_feed (id, submission_id, user_id, task_id, visibility)
+		SELECT gen_random_uuid(), s.id, s.user_id, s.task_id, 'public'
+		FROM submissions s
+		WHERE s.id = ANY($1::uuid[])
+		AND NOT EXISTS (SELECT 1 FROM completed_task_feed f WHERE f.submission_id = s.id)
+	`
+	if _, err := tx.ExecContext(ctx, feedQuery, submissionIDs); err != nil {
+		return nil, nil, fmt.Errorf("failed to create feed entries: %w", err)
+	}
+
+	results := make([]BulkReviewResult, 0, len(submissionIDs))
+	for _, id := range submissionIDs {
+		result := found[id]
+		if result.Submission.TaskXP > 0 {
+			xpLog, err := awardXPTx(ctx, tx, AwardXPRequest{
+				UserID:   result.Submission.UserID,
+				XP:       result.Submission.TaskXP,
+				Source:   XPSourceTaskApproval,
+				SourceID: result.Submission.TaskID,
+			})
+			if err != nil {
+				return nil, []BulkReviewFailure{{ID: id, Error: err.Error()}}, nil
+			}
+			result.XPLog = xpLog
+		}
+		result.Submission.Status = "approved"
+		result.Submission.ReviewedBy = adminUserID
+		result.Submission.AdminComment = comment
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	badgeStore := NewBadgeStore(s.postgres)
+	for _, result := range results {
+		if result.XPLog == nil {
+			continue
+		}
+		if err := badgeStore.CheckAndAwardBadges(ctx, result.XPLog.UserID, result.XPLog.NewXP, result.XPLog.NewLevel); err != nil {
+			log.Printf("[Submission] Error checking badges for user %s: %v", result.XPLog.UserID, err)
+		}
+	}
+
+	return results, nil, nil
+}
+
+// BulkReject rejects pending submissions in a single transaction with the same comment. If any submission
+// is missing or not pending, nothing is changed and the per-ID failures are returned instead.
+func (s *SubmissionStore) BulkReject(ctx context.Context, submissionIDs []string, adminUserID, comment string) ([]BulkReviewResult, []BulkReviewFailure, error) {
+	if comment == "" {
+		return nil, nil, fmt.Errorf("rejection comment is required")
+	}
+
+	tx, err := s.postgres.Traced.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	found, failures, err := lockPendingSubmissions(ctx, tx, submissionIDs)
+	if err != nil || len(failures) > 0 {
+		return nil, failures, err
+	}
+
+	query := `
+		UPDATE submissions
+		SET status = 'rejected',
+		    reviewed_by = $2,
+		    admin_comment = $3,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1::uuid[])
+	`
+	if _, err := tx.ExecContext(ctx, query, submissionIDs, adminUserID, comment); err != nil {
+		return nil, nil, fmt.Errorf("failed to reject submissions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	results := make([]BulkReviewResult, 0, len(submissionIDs))
+	for _, id := range submissionIDs {
+		result := found[id]
+		result.Submission.Status = "rejected"
+		result.Submission.ReviewedBy = adminUserID
+		result.Submission.AdminComment = comment
+		results = append(results, result)
+	}
+	return results, nil, nil
+}
+
+// GetAllSubmissions retrieves all submissions matching filter, newest first, including each task's title and XP
+func (s *SubmissionStore) GetAllSubmissions(ctx context.Context, filter SubmissionFilter) ([]Submission, error) {
+	var conditions []string
 	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Status != "" {
+		addCondition("s.status = $%d", filter.Status)
+	}
+	if filter.TaskID != "" {
+		addCondition("s.task_id = $%d", filter.TaskID)
+	}
+	if filter.UserID != "" {
+		addCondition("s.user_id = $%d", filter.UserID)
+	}
 
-	if statusFilter != "" {
-		query = `
-			SELECT id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
-			FROM submissions
-			WHERE status = $1
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{statusFilter}
-	} else {
-		query = `
-			SELECT id, task_id, user_id, proof_url, status, admin_comment, reviewed_by, created_at, updated_at
-			FROM submissions
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{}
+	query := `
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.thumbnail_url, s.status, s.admin_comment, s.reviewed_by,
+			s.created_at, s.updated_at, t.title, t.xp, COALESCE(t.priority, '')
+		FROM submissions s
+		INNER JOIN tasks t ON t.id = s.task_id
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
+	query += " ORDER BY " + submissionOrderBy(filter)
 
 	rows, err := s.postgres.DB.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -307,14 +617,15 @@ func (s *SubmissionStore) GetAllSubmissions(ctx context.Context, statusFilter st
 	}
 	defer rows.Close()
 
-	var submissions []Submission
+	submissions := []Submission{}
 	for rows.Next() {
 		var submission Submission
-		var adminComment, reviewedBy sql.NullString
+		var adminComment, reviewedBy, thumbnailURL sql.NullString
 
 		err := rows.Scan(
-			&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &submission.Status,
-			&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt,
+			&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
+			&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt, &submission.TaskTitle, &submission.TaskXP,
+			&submission.TaskPriority,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan submission: %w", err)
@@ -326,6 +637,9 @@ func (s *SubmissionStore) GetAllSubmissions(ctx context.Context, statusFilter st
 		if reviewedBy.Valid {
 			submission.ReviewedBy = reviewedBy.String
 		}
+		if thumbnailURL.Valid {
+			submission.ThumbnailURL = thumbnailURL.String
+		}
 
 		submissions = append(submissions, submission)
 	}
@@ -336,3 +650,181 @@ func (s *SubmissionStore) GetAllSubmissions(ctx context.Context, statusFilter st
 
 	return submissions, nil
 }
+
+// SubmissionSortPriority orders submissions by task priority (high first), then oldest first
+const SubmissionSortPriority = "priority"
+
+// SubmissionFilter holds optional filters and ordering for listing submissions
+type SubmissionFilter struct {
+	Status string
+	TaskID string
+	UserID string
+	// Sort is "" (newest first) or SubmissionSortPriority
+	Sort string
+	// UnreviewedFirst puts submissions from users who have never had a submission reviewed first
+	UnreviewedFirst bool
+}
+
+// CustomOrder reports whether the filter changes the default newest-first order.
+// Cursor pagination only works with the default order.
+func (f SubmissionFilter) CustomOrder() bool {
+	return f.Sort == SubmissionSortPriority || f.UnreviewedFirst
+}
+
+// submissionOrderBy returns the ORDER BY clause for a query over submissions s joined with tasks t
+func submissionOrderBy(filter SubmissionFilter) string {
+	var order []string
+	if filter.UnreviewedFirst {
+		order = append(order, `CASE WHEN EXISTS (
+			SELECT 1 FROM submissions reviewed
+			WHERE reviewed.user_id = s.user_id AND reviewed.status <> 'pending'
+		) THEN 1 ELSE 0 END`)
+	}
+	if filter.Sort == SubmissionSortPriority {
+		order = append(order, "CASE t.priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END", "s.created_at ASC", "s.id ASC")
+	} else {
+		order = append(order, "s.created_at DESC", "s.id DESC")
+	}
+	return strings.Join(order, ", ")
+}
+
+// SubmissionCursor identifies the last submission seen when paging by (created_at, id) descending
+type SubmissionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns an opaque cursor string for clients
+func (c SubmissionCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeSubmissionCursor parses a cursor string produced by SubmissionCursor.Encode
+func DecodeSubmissionCursor(cursor string) (*SubmissionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &SubmissionCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// ListSubmissions returns submissions matching the filter, newest first (created_at DESC, id DESC)
+// unless the filter sets a custom order.
+// With a cursor, only rows strictly after the cursor are returned, so new submissions arriving
+// between requests don't shift pages. Without a cursor, offset is applied instead.
+// The cursor is ignored with a custom order. nextCursor is nil when there are no more rows.
+func (s *SubmissionStore) ListSubmissions(ctx context.Context, filter SubmissionFilter, cursor *SubmissionCursor, limit, offset int) ([]Submission, *SubmissionCursor, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.CustomOrder() {
+		cursor = nil
+	}
+	if filter.Status != "" {
+		addCondition("s.status = $%d", filter.Status)
+	}
+	if filter.TaskID != "" {
+		addCondition("s.task_id = $%d", filter.TaskID)
+	}
+	if filter.UserID != "" {
+		addCondition("s.user_id = $%d", filter.UserID)
+	}
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(s.created_at, s.id) < ($%d::timestamp, $%d::uuid)", len(args)-1, len(args)))
+	}
+
+	query := `
+		SELECT s.id, s.task_id, s.user_id, s.proof_url, s.thumbnail_url, s.status, s.admin_comment, s.reviewed_by,
+			s.created_at, s.updated_at, COALESCE(t.priority, '')
+		FROM submissions s
+		INNER JOIN tasks t ON t.id = s.task_id
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	// Fetch one extra row to know whether there is a next page
+	args = append(args, limit+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d", submissionOrderBy(filter), len(args))
+	if cursor == nil && offset > 0 {
+		args = append(args, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []Submission{}
+	for rows.Next() {
+		var submission Submission
+		var adminComment, reviewedBy, thumbnailURL sql.NullString
+
+		err := rows.Scan(
+			&submission.ID, &submission.TaskID, &submission.UserID, &submission.ProofURL, &thumbnailURL, &submission.Status,
+			&adminComment, &reviewedBy, &submission.CreatedAt, &submission.UpdatedAt, &submission.TaskPriority,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+
+		if adminComment.Valid {
+			submission.AdminComment = adminComment.String
+		}
+		if reviewedBy.Valid {
+			submission.ReviewedBy = reviewedBy.String
+		}
+		if thumbnailURL.Valid {
+			submission.ThumbnailURL = thumbnailURL.String
+		}
+
+		submissions = append(submissions, submission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating submission rows: %w", err)
+	}
+
+	var nextCursor *SubmissionCursor
+	if len(submissions) > limit {
+		submissions = submissions[:limit]
+		if !filter.CustomOrder() {
+			last := submissions[len(submissions)-1]
+			nextCursor = &SubmissionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+	}
+
+	return submissions, nextCursor, nil
+}
+
+// IsProofKeyReferenced reports whether any submission's proof or thumbnail URL contains the S3 key
+func (s *SubmissionStore) IsProofKeyReferenced(ctx context.Context, key string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM submissions
+			WHERE strpos(proof_url, $1) > 0 OR strpos(COALESCE(thumbnail_url, ''), $1) > 0
+		)
+	`
+	var exists bool
+	if err := s.postgres.DB.QueryRowContext(ctx, query, key).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check proof key: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,367 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestSubmissionStoreListSubmissionsOrder(t *testing.T) {
+	columns := []string{"id", "task_id", "user_id", "proof_url", "thumbnail_url", "status", "admin_comment", "reviewed_by", "created_at", "updated_at", "priority"}
+	// Two rows for a limit of one, so there is always a next page
+	rows := [][]any{
+		{testutil.TestSubmissionID, testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/a.png", nil, "pending", nil, nil, testutil.TestTime, testutil.TestTime, "high"},
+		{"eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee", testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/b.png", nil, "pending", nil, nil, testutil.TestTime, testutil.TestTime, "normal"},
+	}
+	cursor := &store.SubmissionCursor{CreatedAt: testutil.TestTime, ID: testutil.TestSubmissionID}
+	const unreviewed = `CASE WHEN EXISTS \(\s+SELECT 1 FROM submissions reviewed\s+WHERE reviewed.user_id = s.user_id AND reviewed.status <> 'pending'\s+\) THEN 1 ELSE 0 END`
+	const priority = `CASE t.priority WHEN 'high' THEN 0 WHEN 'normal' THEN 1 ELSE 2 END, s.created_at ASC, s.id ASC`
+	tests := []struct {
+		name           string
+		filter         store.SubmissionFilter
+		wantOrder      string
+		wantArgs       []any
+		wantNextCursor bool
+	}{
+		{
+			name:           "newest first pages by cursor",
+			wantOrder:      `WHERE \(s.created_at, s.id\) < \(\$1::timestamp, \$2::uuid\) ORDER BY s.created_at DESC, s.id DESC LIMIT \$3`,
+			wantArgs:       []any{cursor.CreatedAt, cursor.ID, 2},
+			wantNextCursor: true,
+		},
+		{
+			name:      "priority, oldest first within a priority",
+			filter:    store.SubmissionFilter{Sort: store.SubmissionSortPriority},
+			wantOrder: `ORDER BY ` + priority + ` LIMIT \$1`,
+			wantArgs:  []any{2},
+		},
+		{
+			name:      "unreviewed users first, then newest",
+			filter:    store.SubmissionFilter{UnreviewedFirst: true},
+			wantOrder: `ORDER BY ` + unreviewed + `, s.created_at DESC, s.id DESC LIMIT \$1`,
+			wantArgs:  []any{2},
+		},
+		{
+			name:      "unreviewed users first, then priority",
+			filter:    store.SubmissionFilter{Status: "pending", Sort: store.SubmissionSortPriority, UnreviewedFirst: true},
+			wantOrder: `WHERE s.status = \$1 ORDER BY ` + unreviewed + `, ` + priority + ` LIMIT \$2`,
+			wantArgs:  []any{"pending", 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			// The cursor is passed every time; custom orders must ignore it
+			mock.ExpectQuery(`FROM submissions s\s+INNER JOIN tasks t ON t.id = s.task_id\s+`+tt.wantOrder+`$`).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows(columns, rows...)
+
+			submissions, next, err := store.NewSubmissionStore(postgres).ListSubmissions(context.Background(), tt.filter, cursor, 1, 0)
+			if err != nil {
+				t.Fatalf("ListSubmissions: %v", err)
+			}
+			if len(submissions) != 1 {
+				t.Fatalf("got %d submissions, want 1", len(submissions))
+			}
+			if submissions[0].TaskPriority != "high" {
+				t.Errorf("task priority = %q, want high", submissions[0].TaskPriority)
+			}
+			if (next != nil) != tt.wantNextCursor {
+				t.Errorf("next cursor = %v, want one: %v", next, tt.wantNextCursor)
+			}
+		})
+	}
+}
+
+func TestSubmissionStoreCreateSubmission(t *testing.T) {
+	columns := []string{"id", "task_id", "user_id", "proof_url", "thumbnail_url", "status", "admin_comment", "reviewed_by", "created_at", "updated_at"}
+	tests := []struct {
+		name      string
+		insertErr error
+		noTask    bool
+		wantErr   string
+	}{
+		{name: "creates"},
+		{name: "unknown task", noTask: true, wantErr: "task not found"},
+		// A concurrent submit got past the existence check first
+		{
+			name:      "same week already submitted",
+			insertErr: errors.New(`ERROR: duplicate key value violates unique constraint "idx_submissions_task_user_week" (SQLSTATE 23505)`),
+			wantErr:   "submission already exists for this task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			mock.ExpectQuery(`FROM submissions s\s+JOIN tasks t ON t.id = s.task_id\s+WHERE s.task_id = \$1 AND s.user_id = \$2 AND \(NOT t.is_weekly OR s.week_number = \(EXTRACT\(ISOYEAR FROM NOW\(\)\) \* 100 \+ EXTRACT\(WEEK FROM NOW\(\)\)\)::int\)`).
+				WithArgs(testutil.TestTaskID, testutil.TestUserID).
+				WillReturnRows(columns)
+			// week_number is set from the task, never left to a column default
+			insert := mock.ExpectQuery(`INSERT INTO submissions \(id, task_id, user_id, proof_url, thumbnail_url, status, week_number\)\s+`+
+				`SELECT \$1, t.id, \$3, \$4, NULLIF\(\$5, ''\), 'pending', CASE WHEN t.is_weekly THEN \(EXTRACT\(ISOYEAR FROM NOW\(\)\) \* 100 \+ EXTRACT\(WEEK FROM NOW\(\)\)\)::int ELSE 0 END\s+`+
+				`FROM tasks t\s+WHERE t.id = \$2`).
+				WithArgs(testutil.AnyArg(), testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/a.png", "")
+			switch {
+			case tt.insertErr != nil:
+				insert.WillReturnError(tt.insertErr)
+			case tt.noTask:
+				insert.WillReturnRows(columns)
+			default:
+				insert.WillReturnRows(columns, []any{testutil.TestSubmissionID, testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/a.png", nil, "pending", nil, nil, testutil.TestTime, testutil.TestTime})
+			}
+
+			submission, err := store.NewSubmissionStore(postgres).CreateSubmission(context.Background(), store.CreateSubmissionRequest{
+				TaskID:   testutil.TestTaskID,
+				UserID:   testutil.TestUserID,
+				ProofURL: "https://cdn.example.com/a.png",
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateSubmission: %v", err)
+			}
+			if submission.ID != testutil.TestSubmissionID || submission.Status != "pending" {
+				t.Errorf("submission = %+v", submission)
+			}
+		})
+	}
+}
+
+func TestSubmissionStoreBulkApprove(t *testing.T) {
+	const otherSubmissionID = "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"
+	const missingSubmissionID = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	lockColumns := []string{"id", "task_id", "user_id", "proof_url", "status", "xp", "title"}
+	xpLogColumns := []string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"}
+	pending := func(id, userID string, xp int64) []any {
+		return []any{id, testutil.TestTaskID, userID, "https://cdn.example.com/a.png", "pending", xp, "Test Task"}
+	}
+	expectLock := func(mock *testutil.MockPostgres, ids []string, rows ...[]any) {
+		mock.ExpectQuery(`FROM submissions s\s+INNER JOIN tasks t ON t.id = s.task_id\s+WHERE s.id = ANY\(\$1::uuid\[\]\)\s+FOR UPDATE OF s`).
+			WithArgs(ids).
+			WillReturnRows(lockColumns, rows...)
+	}
+	expectReview := func(mock *testutil.MockPostgres, ids []string) {
+		mock.ExpectExec(`UPDATE submissions\s+SET status = 'approved'[\s\S]+WHERE id = ANY\(\$1::uuid\[\]\)`).
+			WithArgs(ids, testutil.TestAdminID, "Nice work").
+			WillReturnResult(int64(len(ids)))
+		mock.ExpectExec(`INSERT INTO completed_task_feed[\s\S]+WHERE s.id = ANY\(\$1::uuid\[\]\)`).
+			WithArgs(ids).
+			WillReturnResult(int64(len(ids)))
+	}
+	expectAward := func(mock *testutil.MockPostgres, userID string, xp int64) {
+		mock.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+			WithArgs(xp, userID).
+			WillReturnRows([]string{"xp", "level"}, []any{int64(100) + xp, int64(1)})
+		mock.ExpectQuery(`INSERT INTO xp_logs`).
+			WithArgs(testutil.AnyArg(), userID, "task_approval", testutil.TestTaskID, nil, xp).
+			WillReturnRows(xpLogColumns, []any{"log-" + userID, userID, "task_approval", testutil.TestTaskID, nil, xp, testutil.TestTime})
+		mock.ExpectQuery(`SELECT level FROM levels`).
+			WithArgs(int64(100)+xp).
+			WillReturnRows([]string{"level"}, []any{int64(1)})
+	}
+
+	tests := []struct {
+		name         string
+		ids          []string
+		expect       func(mock *testutil.MockPostgres, ids []string)
+		wantFailures []store.BulkReviewFailure
+		wantApproved int
+	}{
+		{
+			name: "approves and awards XP",
+			ids:  []string{testutil.TestSubmissionID, otherSubmissionID},
+			expect: func(mock *testutil.MockPostgres, ids []string) {
+				mock.ExpectBegin()
+				expectLock(mock, ids, pending(testutil.TestSubmissionID, testutil.TestUserID, 50), pending(otherSubmissionID, testutil.TestAdminID, 0))
+				expectReview(mock, ids)
+				// The second task awards no XP
+				expectAward(mock, testutil.TestUserID, 50)
+				mock.ExpectCommit()
+				// Badges are checked after commit, only for users who gained XP
+				mock.ExpectQuery(`FROM badges b`).
+					WithArgs(int64(150), int64(1), testutil.TestUserID).
+					WillReturnRows([]string{"id", "xp", "required_level"})
+			},
+			wantApproved: 2,
+		},
+		{
+			name: "missing and reviewed submissions change nothing",
+			ids:  []string{testutil.TestSubmissionID, otherSubmissionID, missingSubmissionID},
+			expect: func(mock *testutil.MockPostgres, ids []string) {
+				mock.ExpectBegin()
+				approved := pending(otherSubmissionID, testutil.TestUserID, 50)
+				approved[4] = "approved"
+				expectLock(mock, ids, pending(testutil.TestSubmissionID, testutil.TestUserID, 50), approved)
+				mock.ExpectRollback()
+			},
+			wantFailures: []store.BulkReviewFailure{
+				{ID: otherSubmissionID, Error: "submission is already approved"},
+				{ID: missingSubmissionID, Error: "submission not found"},
+			},
+		},
+		{
+			name: "failed XP award rolls back the whole batch",
+			ids:  []string{testutil.TestSubmissionID, otherSubmissionID},
+			expect: func(mock *testutil.MockPostgres, ids []string) {
+				mock.ExpectBegin()
+				expectLock(mock, ids, pending(testutil.TestSubmissionID, testutil.TestUserID, 50), pending(otherSubmissionID, testutil.TestAdminID, 50))
+				expectReview(mock, ids)
+				expectAward(mock, testutil.TestUserID, 50)
+				mock.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+					WithArgs(int64(50), testutil.TestAdminID).
+					WillReturnRows([]string{"xp", "level"})
+				mock.ExpectRollback()
+			},
+			wantFailures: []store.BulkReviewFailure{{ID: otherSubmissionID, Error: "user not found"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			tt.expect(mock, tt.ids)
+
+			results, failures, err := store.NewSubmissionStore(postgres).BulkApprove(context.Background(), tt.ids, testutil.TestAdminID, "Nice work")
+			if err != nil {
+				t.Fatalf("BulkApprove: %v", err)
+			}
+			if !reflect.DeepEqual(failures, tt.wantFailures) {
+				t.Errorf("failures = %+v, want %+v", failures, tt.wantFailures)
+			}
+			if len(results) != tt.wantApproved {
+				t.Fatalf("%d results, want %d", len(results), tt.wantApproved)
+			}
+			for i, result := range results {
+				if result.Submission.ID != tt.ids[i] || result.Submission.Status != "approved" || result.Submission.ReviewedBy != testutil.TestAdminID {
+					t.Errorf("result %d = %+v", i, result.Submission)
+				}
+				if gotXP := result.XPLog != nil; gotXP != (result.Submission.TaskXP > 0) {
+					t.Errorf("result %d XP log = %+v for a %d XP task", i, result.XPLog, result.Submission.TaskXP)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmissionStoreBulkReject(t *testing.T) {
+	const missingSubmissionID = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	lockColumns := []string{"id", "task_id", "user_id", "proof_url", "status", "xp", "title"}
+	submission := []any{testutil.TestSubmissionID, testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/a.png", "pending", int64(50), "Test Task"}
+	tests := []struct {
+		name         string
+		ids          []string
+		comment      string
+		found        [][]any
+		wantFailures []store.BulkReviewFailure
+		wantErr      string
+	}{
+		{name: "rejects", ids: []string{testutil.TestSubmissionID}, comment: "Blurry photo", found: [][]any{submission}},
+		{
+			name:         "missing submission changes nothing",
+			ids:          []string{testutil.TestSubmissionID, missingSubmissionID},
+			comment:      "Blurry photo",
+			found:        [][]any{submission},
+			wantFailures: []store.BulkReviewFailure{{ID: missingSubmissionID, Error: "submission not found"}},
+		},
+		{name: "comment required", ids: []string{testutil.TestSubmissionID}, wantErr: "rejection comment is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			if tt.wantErr == "" {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`WHERE s.id = ANY\(\$1::uuid\[\]\)\s+FOR UPDATE OF s`).
+					WithArgs(tt.ids).
+					WillReturnRows(lockColumns, tt.found...)
+				if tt.wantFailures == nil {
+					mock.ExpectExec(`UPDATE submissions\s+SET status = 'rejected'[\s\S]+WHERE id = ANY\(\$1::uuid\[\]\)`).
+						WithArgs(tt.ids, testutil.TestAdminID, tt.comment).
+						WillReturnResult(int64(len(tt.ids)))
+					mock.ExpectCommit()
+				} else {
+					mock.ExpectRollback()
+				}
+			}
+
+			results, failures, err := store.NewSubmissionStore(postgres).BulkReject(context.Background(), tt.ids, testutil.TestAdminID, tt.comment)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BulkReject: %v", err)
+			}
+			if !reflect.DeepEqual(failures, tt.wantFailures) {
+				t.Errorf("failures = %+v, want %+v", failures, tt.wantFailures)
+			}
+			if tt.wantFailures == nil && (len(results) != 1 || results[0].Submission.Status != "rejected" || results[0].Submission.AdminComment != tt.comment) {
+				t.Errorf("results = %+v", results)
+			}
+			if tt.wantFailures != nil && results != nil {
+				t.Errorf("results = %+v, want none", results)
+			}
+		})
+	}
+}
+
+func TestSubmissionStoreCountApprovedSubmissions(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectQuery(`^SELECT COUNT\(\*\) FROM submissions WHERE user_id = \$1 AND status = 'approved'$`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"count"}, []any{int64(0)})
+
+	count, err := store.NewSubmissionStore(postgres).CountApprovedSubmissions(context.Background(), testutil.TestUserID)
+	if err != nil || count != 0 {
+		t.Errorf("CountApprovedSubmissions = %d, %v; want 0", count, err)
+	}
+}
+
+// A weekly task approved in an earlier week still has an approved submission, even after this week's
+func TestSubmissionStoreGetLatestApprovedSubmission(t *testing.T) {
+	columns := []string{"id", "task_id", "user_id", "proof_url", "thumbnail_url", "status", "admin_comment", "reviewed_by", "created_at", "updated_at"}
+	lastWeek := testutil.TestTime.AddDate(0, 0, -7)
+	tests := []struct {
+		name    string
+		rows    [][]any
+		wantErr string
+	}{
+		{name: "approved last week", rows: [][]any{{testutil.TestSubmissionID, testutil.TestTaskID, testutil.TestUserID, "https://cdn.example.com/a.png", nil, "approved", nil, testutil.TestAdminID, lastWeek, lastWeek}}},
+		{name: "never approved", wantErr: "submission not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`FROM submissions s\s+WHERE s.task_id = \$1 AND s.user_id = \$2 AND s.status = 'approved'\s+ORDER BY s.created_at DESC\s+LIMIT 1`).
+				WithArgs(testutil.TestTaskID, testutil.TestUserID).
+				WillReturnRows(columns, tt.rows...)
+
+			submission, err := store.NewSubmissionStore(postgres).GetLatestApprovedSubmission(context.Background(), testutil.TestTaskID, testutil.TestUserID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetLatestApprovedSubmission: %v", err)
+			}
+			if submission.ID != testutil.TestSubmissionID || submission.Status != "approved" || !submission.UpdatedAt.Equal(lastWeek) {
+				t.Errorf("submission = %+v", submission)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/telemetry"
 )
 
 // TaskStatus represents the task lifecycle: ongoing (open), ended (past deadline), completed (e.g. admin closed)
@@ -17,21 +18,81 @@ const (
 	TaskStatusCompleted = "completed"
 )
 
+// TaskType is the kind of activity a task asks for
+type TaskType string
+
+const (
+	TaskTypeLinkedIn TaskType = "linkedin" // post or engage on LinkedIn
+	TaskTypeGitHub   TaskType = "github"   // contribute on GitHub
+	TaskTypeOffline  TaskType = "offline"  // in-person activity (e.g. campus event)
+	TaskTypeOnline   TaskType = "online"   // any other online activity
+	TaskTypeQuiz     TaskType = "quiz"     // answer a quiz
+)
+
+// ValidTaskTypes lists every valid task type
+var ValidTaskTypes = []TaskType{
+	TaskTypeLinkedIn,
+	TaskTypeGitHub,
+	TaskTypeOffline,
+	TaskTypeOnline,
+	TaskTypeQuiz,
+}
+
+// IsValidTaskType reports whether taskType is a known task type
+func IsValidTaskType(taskType string) bool {
+	for _, t := range ValidTaskTypes {
+		if string(t) == taskType {
+			return true
+		}
+	}
+	return false
+}
+
+// ProofType is the kind of proof a user must submit for a task
+type ProofType string
+
+const (
+	ProofTypeImage ProofType = "image" // image file uploaded to S3
+	ProofTypeVideo ProofType = "video" // video file uploaded to S3
+	ProofTypeLink  ProofType = "link"  // URL submitted directly (e.g. LinkedIn post), no upload
+)
+
+// ValidProofTypes lists every valid proof type
+var ValidProofTypes = []ProofType{
+	ProofTypeImage,
+	ProofTypeVideo,
+	ProofTypeLink,
+}
+
+// IsValidProofType reports whether proofType is a known proof type
+func IsValidProofType(proofType string) bool {
+	for _, t := range ValidProofTypes {
+		if string(t) == proofType {
+			return true
+		}
+	}
+	return false
+}
+
 type Task struct {
-	ID          string     `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	XP          int        `json:"xp"`
-	Type        string     `json:"type"`
-	ProofType   string     `json:"proof_type"`
-	Priority    string     `json:"priority"`
-	StartAt     *time.Time `json:"start_at,omitempty"`
-	EndAt       *time.Time `json:"end_at,omitempty"`
-	IsFlash     bool       `json:"is_flash"`
-	IsWeekly    bool       `json:"is_weekly"`
-	CreatedBy   string     `json:"created_by"`
-	CreatedAt   time.Time  `json:"created_at"`
-	Status      string     `json:"status"` // ongoing, ended, or completed (time passed for submission = ended)
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	XP           int        `json:"xp"`
+	Type         string     `json:"type"`
+	ProofType    string     `json:"proof_type"`
+	Priority     string     `json:"priority"`
+	StartAt      *time.Time `json:"start_at,omitempty"`
+	EndAt        *time.Time `json:"end_at,omitempty"`
+	IsFlash      bool       `json:"is_flash"`
+	IsWeekly     bool       `json:"is_weekly"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	Status       string     `json:"status"`                   // ongoing, ended, or completed (time passed for submission = ended)
+	ParentTaskID string     `json:"parent_task_id,omitempty"` // Set on auto-created weekly instances
+	// Reactions are only populated on the per-user task lists
+	ReactionCount int    `json:"reaction_count"`
+	UserReaction  string `json:"user_reaction,omitempty"` // The calling user's reaction, if any
 }
 
 // UserTaskStatus is the status of a task for a specific user (completion state).
@@ -45,8 +106,9 @@ const (
 // TaskWithUserStatus extends Task with the current user's completion status for one-route completed/ongoing display.
 type TaskWithUserStatus struct {
 	Task
-	UserStatus   string `json:"user_status"`             // completed, viewing, rejected, not_started
-	SubmissionID string `json:"submission_id,omitempty"` // set when user has a submission
+	UserStatus       string `json:"user_status"`                 // completed, viewing, rejected, not_started
+	SubmissionID     string `json:"submission_id,omitempty"`     // set when user has a submission
+	SecondsRemaining *int64 `json:"seconds_remaining,omitempty"` // set for flash tasks with an end_at
 }
 
 type TaskStore struct {
@@ -84,10 +146,22 @@ const (
 	AssignmentUser    AssignmentType = "user"    // Single user
 )
 
+// IsValidAssignmentType reports whether assignmentType is a known assignment type
+func IsValidAssignmentType(assignmentType AssignmentType) bool {
+	switch assignmentType {
+	case AssignmentAll, AssignmentState, AssignmentCollege, AssignmentUser:
+		return true
+	}
+	return false
+}
+
 // CreateTask creates a new task and assigns it to users based on assignment type
 func (s *TaskStore) CreateTask(ctx context.Context, req CreateTaskRequest, assignmentType AssignmentType, assignmentID string) (*Task, []string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "store.CreateTask")
+	defer span.End()
+
 	// Start transaction
-	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	tx, err := s.postgres.Traced.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -96,8 +170,8 @@ func (s *TaskStore) CreateTask(ctx context.Context, req CreateTaskRequest, assig
 	// Create task (status = ongoing when created)
 	taskID := uuid.New().String()
 	query := `
-		INSERT INTO tasks (id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 'ongoing')
+		INSERT INTO tasks (id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, status, assignment_type, assignment_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 'ongoing', $13, NULLIF($14, '')::uuid)
 		RETURNING id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, created_at, status
 	`
 
@@ -106,7 +180,7 @@ func (s *TaskStore) CreateTask(ctx context.Context, req CreateTaskRequest, assig
 
 	err = tx.QueryRowContext(ctx, query,
 		taskID, req.Title, req.Description, req.XP, req.Type, req.ProofType, req.Priority,
-		req.StartAt, req.EndAt, req.IsFlash, req.IsWeekly, req.CreatedBy,
+		req.StartAt, req.EndAt, req.IsFlash, req.IsWeekly, req.CreatedBy, assignmentType, assignmentID,
 	).Scan(
 		&task.ID, &task.Title, &task.Description, &task.XP, &task.Type, &task.ProofType, &task.Priority,
 		&startAt, &endAt, &task.IsFlash, &task.IsWeekly, &task.CreatedBy, &task.CreatedAt, &task.Status,
@@ -127,6 +201,9 @@ func (s *TaskStore) CreateTask(ctx context.Context, req CreateTaskRequest, assig
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get user IDs for assignment: %w", err)
 	}
+	if _, err := insertTaskAssignments(ctx, tx, task.ID, userIDs); err != nil {
+		return nil, nil, err
+	}
 
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
@@ -136,6 +213,171 @@ func (s *TaskStore) CreateTask(ctx context.Context, req CreateTaskRequest, assig
 	return &task, userIDs, nil
 }
 
+// WeeklyTaskTemplate is an original weekly task together with the scope it was assigned to
+type WeeklyTaskTemplate struct {
+	Task
+	AssignmentType AssignmentType
+	AssignmentID   string
+}
+
+// GetWeeklyTasksDueForRepeat returns original weekly tasks (not generated instances) whose end_at has passed.
+// Tasks created before the assignment scope was recorded are treated as assigned to all users.
+func (s *TaskStore) GetWeeklyTasksDueForRepeat(ctx context.Context) ([]WeeklyTaskTemplate, error) {
+	query := `
+		SELECT id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, created_at,
+			COALESCE(assignment_type, 'all'), COALESCE(assignment_id::text, '')
+		FROM tasks
+		WHERE is_weekly = true AND end_at < NOW() AND parent_task_id IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query weekly tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []WeeklyTaskTemplate
+	for rows.Next() {
+		var t WeeklyTaskTemplate
+		var startAt, endAt sql.NullTime
+		var assignmentType string
+
+		err := rows.Scan(
+			&t.ID, &t.Title, &t.Description, &t.XP, &t.Type, &t.ProofType, &t.Priority,
+			&startAt, &endAt, &t.IsFlash, &t.IsWeekly, &t.CreatedBy, &t.CreatedAt,
+			&assignmentType, &t.AssignmentID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan weekly task: %w", err)
+		}
+		t.AssignmentType = AssignmentType(assignmentType)
+		if startAt.Valid {
+			t.StartAt = &startAt.Time
+		}
+		if endAt.Valid {
+			t.EndAt = &endAt.Time
+		}
+
+		templates = append(templates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating weekly task rows: %w", err)
+	}
+
+	return templates, nil
+}
+
+// CreateWeeklyInstance creates the instance of a weekly task for the week starting at startAt,
+// assigned to the same scope as the original. Returns a nil task if that week's instance already exists.
+func (s *TaskStore) CreateWeeklyInstance(ctx context.Context, template WeeklyTaskTemplate, startAt, endAt time.Time) (*Task, []string, error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO tasks (id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, status, parent_task_id, assignment_type, assignment_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, true, $11, 'ongoing', $12, $13, NULLIF($14, '')::uuid)
+		ON CONFLICT (parent_task_id, start_at) WHERE parent_task_id IS NOT NULL DO NOTHING
+		RETURNING id, title, description, xp, type, proof_type, priority, start_at, end_at, is_flash, is_weekly, created_by, created_at, status, parent_task_id
+	`
+
+	var task Task
+	var taskStartAt, taskEndAt sql.NullTime
+
+	err = tx.QueryRowContext(ctx, query,
+		uuid.New().String(), template.Title, template.Description, template.XP, template.Type, template.ProofType, template.Priority,
+		startAt, endAt, template.IsFlash, template.CreatedBy, template.ID, template.AssignmentType, template.AssignmentID,
+	).Scan(
+		&task.ID, &task.Title, &task.Description, &task.XP, &task.Type, &task.ProofType, &task.Priority,
+		&taskStartAt, &taskEndAt, &task.IsFlash, &task.IsWeekly, &task.CreatedBy, &task.CreatedAt, &task.Status, &task.ParentTaskID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to create weekly task instance: %w", err)
+	}
+
+	if taskStartAt.Valid {
+		task.StartAt = &taskStartAt.Time
+	}
+	if taskEndAt.Valid {
+		task.EndAt = &taskEndAt.Time
+	}
+
+	userIDs, err := s.getUserIDsForAssignment(ctx, tx, template.AssignmentType, template.AssignmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user IDs for assignment: %w", err)
+	}
+	if _, err := insertTaskAssignments(ctx, tx, task.ID, userIDs); err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &task, userIDs, nil
+}
+
+// GetTaskSeries returns the original weekly task and all of its generated instances, oldest first.
+// taskID may be the original or any instance in the series.
+func (s *TaskStore) GetTaskSeries(ctx context.Context, taskID string) ([]Task, error) {
+	query := `
+		WITH root AS (
+			SELECT COALESCE(parent_task_id, id) AS id FROM tasks WHERE id = $1
+		)
+		SELECT t.id, t.title, t.description, t.xp, t.type, t.proof_type, t.priority, t.start_at, t.end_at, t.is_flash, t.is_weekly, t.created_by, t.created_at,
+			CASE WHEN t.end_at IS NOT NULL AND t.end_at < NOW() THEN 'ended' ELSE COALESCE(t.status, 'ongoing') END AS status,
+			COALESCE(t.parent_task_id::text, '')
+		FROM tasks t, root
+		WHERE t.id = root.id OR t.parent_task_id = root.id
+		ORDER BY t.start_at ASC NULLS FIRST, t.created_at ASC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task series: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []Task{}
+	for rows.Next() {
+		var task Task
+		var startAt, endAt sql.NullTime
+
+		err := rows.Scan(
+			&task.ID, &task.Title, &task.Description, &task.XP, &task.Type, &task.ProofType, &task.Priority,
+			&startAt, &endAt, &task.IsFlash, &task.IsWeekly, &task.CreatedBy, &task.CreatedAt, &task.Status, &task.ParentTaskID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		if startAt.Valid {
+			task.StartAt = &startAt.Time
+		}
+		if endAt.Valid {
+			task.EndAt = &endAt.Time
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task rows: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return tasks, nil
+}
+
 // getUserIDsForAssignment gets user IDs based on assignment type
 func (s *TaskStore) getUserIDsForAssignment(ctx context.Context, tx *sql.Tx, assignmentType AssignmentType, assignmentID string) ([]string, error) {
 	var query string
@@ -188,6 +430,229 @@ func (s *TaskStore) getUserIDsForAssignment(ctx context.Context, tx *sql.Tx, ass
 	return userIDs, nil
 }
 
+// insertTaskAssignments assigns the task to userIDs, skipping users already assigned.
+// Returns the IDs of the newly assigned users.
+func insertTaskAssignments(ctx context.Context, tx *sql.Tx, taskID string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO task_assignments (task_id, user_id)
+		SELECT $1, unnest($2::text[])::uuid
+		ON CONFLICT (task_id, user_id) DO NOTHING
+		RETURNING user_id
+	`
+	rows, err := tx.QueryContext(ctx, query, taskID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert task assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var added []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan assigned user ID: %w", err)
+		}
+		added = append(added, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assigned user rows: %w", err)
+	}
+
+	return added, nil
+}
+
+// AssignOpenTasksToNewUser assigns a newly created student every task that has not ended and whose
+// scope covers them (all users, their state or their college). Returns the IDs of the tasks assigned.
+func (s *TaskStore) AssignOpenTasksToNewUser(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		INSERT INTO task_assignments (task_id, user_id)
+		SELECT t.id, u.id
+		FROM tasks t
+		JOIN users u ON u.id = $1 AND u.role = 'student'
+		WHERE (t.end_at IS NULL OR t.end_at > NOW())
+			AND (
+				COALESCE(t.assignment_type, 'all') = 'all'
+				OR (t.assignment_type = 'state' AND t.assignment_id = u.state_id)
+				OR (t.assignment_type = 'college' AND t.assignment_id = u.college_id)
+			)
+		ON CONFLICT (task_id, user_id) DO NOTHING
+		RETURNING task_id
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign open tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var taskIDs []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			return nil, fmt.Errorf("failed to scan assigned task ID: %w", err)
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assigned task rows: %w", err)
+	}
+
+	return taskIDs, nil
+}
+
+// UpdateTaskAssignment moves a task to a new assignment scope and returns the users newly assigned by it.
+// Users outside the new scope keep their assignment (and any in-progress submission) unless removeExisting is set.
+func (s *TaskStore) UpdateTaskAssignment(ctx context.Context, taskID string, assignmentType AssignmentType, assignmentID string, removeExisting bool) ([]string, error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tasks SET assignment_type = $1, assignment_id = NULLIF($2, '')::uuid
+		WHERE id = $3
+	`, assignmentType, assignmentID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task assignment: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	userIDs, err := s.getUserIDsForAssignment(ctx, tx, assignmentType, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user IDs for assignment: %w", err)
+	}
+
+	added, err := insertTaskAssignments(ctx, tx, taskID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if removeExisting {
+		if userIDs == nil {
+			userIDs = []string{}
+		}
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM task_assignments
+			WHERE task_id = $1 AND NOT (user_id::text = ANY($2::text[]))
+		`, taskID, userIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove task assignments: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added, nil
+}
+
+// AddTaskAssignment assigns an existing task to extra users on top of its assignment scope.
+// Unknown and already assigned user IDs are skipped. Returns the IDs of the newly assigned users.
+func (s *TaskStore) AddTaskAssignment(ctx context.Context, taskID string, userIDs []string) ([]string, error) {
+	var exists bool
+	err := s.postgres.DB.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1)`, taskID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	query := `
+		INSERT INTO task_assignments (task_id, user_id)
+		SELECT $1, u.id FROM users u WHERE u.id::text = ANY($2::text[])
+		ON CONFLICT (task_id, user_id) DO NOTHING
+		RETURNING user_id
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, taskID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert task assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var added []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan assigned user ID: %w", err)
+		}
+		added = append(added, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assigned user rows: %w", err)
+	}
+
+	return added, nil
+}
+
+// RemoveTaskAssignment unassigns users from a task. Users who already have a submission for the task
+// stay assigned and are returned in withSubmission.
+func (s *TaskStore) RemoveTaskAssignment(ctx context.Context, taskID string, userIDs []string) (removed int, withSubmission []string, err error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1)`, taskID).Scan(&exists)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		return 0, nil, fmt.Errorf("task not found")
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT user_id FROM submissions
+		WHERE task_id = $1 AND user_id::text = ANY($2::text[])
+	`, taskID, userIDs)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get submissions: %w", err)
+	}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, nil, fmt.Errorf("failed to scan submitted user ID: %w", err)
+		}
+		withSubmission = append(withSubmission, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("error iterating submitted user rows: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM task_assignments ta
+		WHERE ta.task_id = $1 AND ta.user_id::text = ANY($2::text[])
+		AND NOT EXISTS (SELECT 1 FROM submissions s WHERE s.task_id = ta.task_id AND s.user_id = ta.user_id)
+	`, taskID, userIDs)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to remove task assignments: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return int(rowsAffected), withSubmission, nil
+}
+
 // GetTaskByID retrieves a task by ID. Status is derived: ended when end_at has passed, else ongoing/completed from DB.
 func (s *TaskStore) GetTaskByID(ctx context.Context, taskID string) (*Task, error) {
 	query := `
@@ -235,25 +700,27 @@ func (s *TaskStore) GetTasksForUser(ctx context.Context, userID string) ([]Task,
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Query tasks that are assigned to:
-	// 1. All users (we need to check this by querying all tasks and filtering)
-	// 2. User's state (if state_id matches)
-	// 3. User's college (if college_id matches)
-	// 4. Specific user (if user_id matches)
+	// Only tasks with a task_assignments row for the user are returned; the rows are expanded
+	// from the task's scope (all users, state, college or a single user) when it is assigned.
 
-	// Return all tasks that have started (start_at in the past or null), including ongoing and ended.
+	// Return all assigned tasks that have started (start_at in the past or null), including ongoing and ended.
 	// status: rejected submission for this user → ongoing (can resubmit); past end_at → ended; else ongoing/completed from DB.
 	query := `
 		SELECT t.id, t.title, t.description, t.xp, t.type, t.proof_type, t.priority, t.start_at, t.end_at, t.is_flash, t.is_weekly, t.created_by, t.created_at,
 			CASE
-				WHEN rejected.task_id IS NOT NULL AND (t.end_at IS NULL OR t.end_at >= NOW()) THEN 'ongoing'
+				WHEN s.status = 'rejected' AND (t.end_at IS NULL OR t.end_at >= NOW()) THEN 'ongoing'
 				WHEN t.end_at IS NOT NULL AND t.end_at < NOW() THEN 'ended'
 				ELSE COALESCE(t.status, 'ongoing')
-			END AS status
+			END AS status,
+			COALESCE(reactions.count, 0) AS reaction_count,
+			COALESCE(my_reaction.reaction, '') AS user_reaction
 		FROM tasks t
+		JOIN task_assignments ta ON ta.task_id = t.id AND ta.user_id = $1
+		LEFT JOIN submissions s ON s.task_id = t.id AND s.user_id = $1 AND ` + currentWeekSubmission + `
 		LEFT JOIN (
-			SELECT task_id FROM submissions WHERE user_id = $1 AND status = 'rejected'
-		) rejected ON rejected.task_id = t.id
+			SELECT task_id, COUNT(*) AS count FROM task_reactions GROUP BY task_id
+		) reactions ON reactions.task_id = t.id
+		LEFT JOIN task_reactions my_reaction ON my_reaction.task_id = t.id AND my_reaction.user_id = $1
 		WHERE (t.start_at IS NULL OR t.start_at <= NOW())
 		ORDER BY t.created_at DESC
 	`
@@ -272,6 +739,7 @@ func (s *TaskStore) GetTasksForUser(ctx context.Context, userID string) ([]Task,
 		err := rows.Scan(
 			&task.ID, &task.Title, &task.Description, &task.XP, &task.Type, &task.ProofType, &task.Priority,
 			&startAt, &endAt, &task.IsFlash, &task.IsWeekly, &task.CreatedBy, &task.CreatedAt, &task.Status,
+			&task.ReactionCount, &task.UserReaction,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
@@ -299,7 +767,7 @@ func (s *TaskStore) GetTasksForUserWithStatus(ctx context.Context, userID string
 	query := `
 		SELECT t.id, t.title, t.description, t.xp, t.type, t.proof_type, t.priority, t.start_at, t.end_at, t.is_flash, t.is_weekly, t.created_by, t.created_at,
 			CASE
-				WHEN rejected.task_id IS NOT NULL AND (t.end_at IS NULL OR t.end_at >= NOW()) THEN 'ongoing'
+				WHEN s.status = 'rejected' AND (t.end_at IS NULL OR t.end_at >= NOW()) THEN 'ongoing'
 				WHEN t.end_at IS NOT NULL AND t.end_at < NOW() THEN 'ended'
 				ELSE COALESCE(t.status, 'ongoing')
 			END AS status,
@@ -309,12 +777,16 @@ func (s *TaskStore) GetTasksForUserWithStatus(ctx context.Context, userID string
 				WHEN s.status = 'pending' THEN 'viewing'
 				WHEN s.status = 'rejected' THEN 'rejected'
 				ELSE 'not_started'
-			END AS user_status
+			END AS user_status,
+			COALESCE(reactions.count, 0) AS reaction_count,
+			COALESCE(my_reaction.reaction, '') AS user_reaction
 		FROM tasks t
+		JOIN task_assignments ta ON ta.task_id = t.id AND ta.user_id = $1
+		LEFT JOIN submissions s ON s.task_id = t.id AND s.user_id = $1 AND ` + currentWeekSubmission + `
 		LEFT JOIN (
-			SELECT task_id FROM submissions WHERE user_id = $1 AND status = 'rejected'
-		) rejected ON rejected.task_id = t.id
-		LEFT JOIN submissions s ON s.task_id = t.id AND s.user_id = $1
+			SELECT task_id, COUNT(*) AS count FROM task_reactions GROUP BY task_id
+		) reactions ON reactions.task_id = t.id
+		LEFT JOIN task_reactions my_reaction ON my_reaction.task_id = t.id AND my_reaction.user_id = $1
 		WHERE (t.start_at IS NULL OR t.start_at <= NOW())
 		ORDER BY t.created_at DESC
 	`
@@ -333,7 +805,7 @@ func (s *TaskStore) GetTasksForUserWithStatus(ctx context.Context, userID string
 		err := rows.Scan(
 			&tw.ID, &tw.Title, &tw.Description, &tw.XP, &tw.Type, &tw.ProofType, &tw.Priority,
 			&startAt, &endAt, &tw.IsFlash, &tw.IsWeekly, &tw.CreatedBy, &tw.CreatedAt, &tw.Status,
-			&tw.SubmissionID, &tw.UserStatus,
+			&tw.SubmissionID, &tw.UserStatus, &tw.ReactionCount, &tw.UserReaction,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
@@ -356,9 +828,133 @@ func (s *TaskStore) GetTasksForUserWithStatus(ctx context.Context, userID string
 	return tasks, nil
 }
 
-// CheckSubmissionExists checks if user has already submitted a task
+// ToggleReaction sets the user's reaction on a task. Sending the same reaction again removes it;
+// a different reaction replaces it. Returns true when the user has a reaction afterwards.
+func (s *TaskStore) ToggleReaction(ctx context.Context, taskID, userID, reaction string) (bool, error) {
+	var existing string
+	checkQuery := `SELECT reaction FROM task_reactions WHERE task_id = $1 AND user_id = $2`
+	err := s.postgres.DB.QueryRowContext(ctx, checkQuery, taskID, userID).Scan(&existing)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to check existing reaction: %w", err)
+	}
+
+	if err == nil && existing == reaction {
+		query := `DELETE FROM task_reactions WHERE task_id = $1 AND user_id = $2`
+		if _, err := s.postgres.DB.ExecContext(ctx, query, taskID, userID); err != nil {
+			return false, fmt.Errorf("failed to remove reaction: %w", err)
+		}
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO task_reactions (task_id, user_id, reaction) VALUES ($1, $2, $3)
+		ON CONFLICT (task_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction
+	`
+	if _, err := s.postgres.DB.ExecContext(ctx, query, taskID, userID, reaction); err != nil {
+		return false, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetReactionBreakdown returns the number of each reaction on a task
+func (s *TaskStore) GetReactionBreakdown(ctx context.Context, taskID string) (map[string]int, error) {
+	query := `SELECT reaction, COUNT(*) FROM task_reactions WHERE task_id = $1 GROUP BY reaction`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task reactions: %w", err)
+	}
+	defer rows.Close()
+
+	breakdown := make(map[string]int)
+	for rows.Next() {
+		var reaction string
+		var count int
+		if err := rows.Scan(&reaction, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task reaction: %w", err)
+		}
+		breakdown[reaction] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task reactions: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// GetFlashTasks returns active flash tasks (not yet ended) with the user's status and seconds remaining until end_at
+func (s *TaskStore) GetFlashTasks(ctx context.Context, userID string) ([]TaskWithUserStatus, error) {
+	query := `
+		SELECT t.id, t.title, t.description, t.xp, t.type, t.proof_type, t.priority, t.start_at, t.end_at, t.is_flash, t.is_weekly, t.created_by, t.created_at,
+			COALESCE(t.status, 'ongoing') AS status,
+			COALESCE(s.id::text, '') AS submission_id,
+			CASE
+				WHEN s.status = 'approved' THEN 'completed'
+				WHEN s.status = 'pending' THEN 'viewing'
+				WHEN s.status = 'rejected' THEN 'rejected'
+				ELSE 'not_started'
+			END AS user_status,
+			CASE
+				WHEN t.end_at IS NOT NULL THEN EXTRACT(EPOCH FROM (t.end_at - NOW()))::bigint
+			END AS seconds_remaining
+		FROM tasks t
+		JOIN task_assignments ta ON ta.task_id = t.id AND ta.user_id = $1
+		LEFT JOIN submissions s ON s.task_id = t.id AND s.user_id = $1 AND ` + currentWeekSubmission + `
+		WHERE t.is_flash = true
+			AND (t.start_at IS NULL OR t.start_at <= NOW())
+			AND (t.end_at IS NULL OR t.end_at > NOW())
+		ORDER BY t.end_at ASC NULLS LAST, t.created_at DESC
+	`
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flash tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []TaskWithUserStatus{}
+	for rows.Next() {
+		var tw TaskWithUserStatus
+		var startAt, endAt sql.NullTime
+		var secondsRemaining sql.NullInt64
+
+		err := rows.Scan(
+			&tw.ID, &tw.Title, &tw.Description, &tw.XP, &tw.Type, &tw.ProofType, &tw.Priority,
+			&startAt, &endAt, &tw.IsFlash, &tw.IsWeekly, &tw.CreatedBy, &tw.CreatedAt, &tw.Status,
+			&tw.SubmissionID, &tw.UserStatus, &secondsRemaining,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan flash task: %w", err)
+		}
+
+		if startAt.Valid {
+			tw.StartAt = &startAt.Time
+		}
+		if endAt.Valid {
+			tw.EndAt = &endAt.Time
+		}
+		if secondsRemaining.Valid {
+			tw.SecondsRemaining = &secondsRemaining.Int64
+		}
+
+		tasks = append(tasks, tw)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating flash task rows: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// CheckSubmissionExists checks if user has already submitted a task (this week, for weekly tasks)
 func (s *TaskStore) CheckSubmissionExists(ctx context.Context, taskID, userID string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM submissions WHERE task_id = $1 AND user_id = $2)`
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM submissions s
+			JOIN tasks t ON t.id = s.task_id
+			WHERE s.task_id = $1 AND s.user_id = $2 AND ` + currentWeekSubmission + `
+		)
+	`
 	var exists bool
 	err := s.postgres.DB.QueryRowContext(ctx, query, taskID, userID).Scan(&exists)
 	if err != nil {
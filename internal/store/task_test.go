@@ -0,0 +1,150 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestTaskStoreCreateTaskAssignment(t *testing.T) {
+	taskColumns := []string{"id", "title", "description", "xp", "type", "proof_type", "priority", "start_at", "end_at", "is_flash", "is_weekly", "created_by", "created_at", "status"}
+	const otherUserID = "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	tests := []struct {
+		name           string
+		assignmentType store.AssignmentType
+		assignmentID   string
+		wantUserQuery  string
+		wantUserArgs   []any
+		users          [][]any
+		wantErr        bool
+	}{
+		{
+			name:           "all students",
+			assignmentType: store.AssignmentAll,
+			wantUserQuery:  `SELECT id FROM users WHERE role = 'student'$`,
+			users:          [][]any{{testutil.TestUserID}, {otherUserID}},
+		},
+		{
+			name:           "state",
+			assignmentType: store.AssignmentState,
+			assignmentID:   testutil.TestStateID,
+			wantUserQuery:  `SELECT id FROM users WHERE state_id = \$1 AND role = 'student'`,
+			wantUserArgs:   []any{testutil.TestStateID},
+			users:          [][]any{{testutil.TestUserID}},
+		},
+		{
+			name:           "college",
+			assignmentType: store.AssignmentCollege,
+			assignmentID:   testutil.TestCollegeID,
+			wantUserQuery:  `SELECT id FROM users WHERE college_id = \$1 AND role = 'student'`,
+			wantUserArgs:   []any{testutil.TestCollegeID},
+			users:          [][]any{{testutil.TestUserID}},
+		},
+		{
+			name:           "single user",
+			assignmentType: store.AssignmentUser,
+			assignmentID:   testutil.TestUserID,
+			wantUserQuery:  `SELECT id FROM users WHERE id = \$1 AND role = 'student'`,
+			wantUserArgs:   []any{testutil.TestUserID},
+			users:          [][]any{{testutil.TestUserID}},
+		},
+		{
+			name:           "scope without students",
+			assignmentType: store.AssignmentCollege,
+			assignmentID:   testutil.TestCollegeID,
+			wantUserQuery:  `SELECT id FROM users WHERE college_id = \$1 AND role = 'student'`,
+			wantUserArgs:   []any{testutil.TestCollegeID},
+		},
+		{name: "unknown type", assignmentType: "team", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mock := testutil.NewMockPostgres(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO tasks`).
+				WillReturnRows(taskColumns, []any{testutil.TestTaskID, "Share your post", "", 50, "online", "link", "medium", nil, nil, false, false, testutil.TestAdminID, testutil.TestTime, "ongoing"})
+			var wantUserIDs []string
+			if tt.wantErr {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectQuery(tt.wantUserQuery).
+					WithArgs(tt.wantUserArgs...).
+					WillReturnRows([]string{"id"}, tt.users...)
+				for _, row := range tt.users {
+					wantUserIDs = append(wantUserIDs, row[0].(string))
+				}
+				// The assignments are written in the same transaction as the task
+				if len(wantUserIDs) > 0 {
+					mock.ExpectQuery(`INSERT INTO task_assignments \(task_id, user_id\)`).
+						WithArgs(testutil.TestTaskID, wantUserIDs).
+						WillReturnRows([]string{"user_id"}, tt.users...)
+				}
+				mock.ExpectCommit()
+			}
+
+			task, userIDs, err := store.NewTaskStore(postgres).CreateTask(context.Background(), store.CreateTaskRequest{
+				Title:     "Share your post",
+				XP:        50,
+				Type:      "online",
+				ProofType: "link",
+				Priority:  "medium",
+				CreatedBy: testutil.TestAdminID,
+			}, tt.assignmentType, tt.assignmentID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("err = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			if task.ID != testutil.TestTaskID {
+				t.Errorf("task ID = %q, want %q", task.ID, testutil.TestTaskID)
+			}
+			if len(userIDs) != len(wantUserIDs) {
+				t.Errorf("assigned %v, want %v", userIDs, wantUserIDs)
+			}
+		})
+	}
+}
+
+func TestTaskStoreAssignOpenTasksToNewUser(t *testing.T) {
+	postgres, mock := testutil.NewMockPostgres(t)
+	// Only not-ended tasks whose scope covers the student: all users, their state or their college
+	mock.ExpectQuery(`INSERT INTO task_assignments \(task_id, user_id\)\s+SELECT t.id, u.id\s+FROM tasks t\s+JOIN users u ON u.id = \$1 AND u.role = 'student'\s+`+
+		`WHERE \(t.end_at IS NULL OR t.end_at > NOW\(\)\)\s+AND \(\s+COALESCE\(t.assignment_type, 'all'\) = 'all'\s+`+
+		`OR \(t.assignment_type = 'state' AND t.assignment_id = u.state_id\)\s+`+
+		`OR \(t.assignment_type = 'college' AND t.assignment_id = u.college_id\)\s+\)\s+`+
+		`ON CONFLICT \(task_id, user_id\) DO NOTHING`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"task_id"}, []any{testutil.TestTaskID})
+
+	taskIDs, err := store.NewTaskStore(postgres).AssignOpenTasksToNewUser(context.Background(), testutil.TestUserID)
+	if err != nil {
+		t.Fatalf("AssignOpenTasksToNewUser: %v", err)
+	}
+	if len(taskIDs) != 1 || taskIDs[0] != testutil.TestTaskID {
+		t.Errorf("task IDs = %v, want [%s]", taskIDs, testutil.TestTaskID)
+	}
+}
+
+func TestTaskStoreGetTasksForUserOnlyAssigned(t *testing.T) {
+	postgres, mock := testutil.NewMockPostgres(t)
+	mock.ExpectQuery(`SELECT state_id, college_id FROM users WHERE id = \$1`).
+		WithArgs(testutil.TestUserID).
+		WillReturnRows([]string{"state_id", "college_id"}, []any{testutil.TestStateID, testutil.TestCollegeID})
+	mock.ExpectQuery(`FROM tasks t\s+JOIN task_assignments ta ON ta.task_id = t.id AND ta.user_id = \$1`).
+		WillReturnRows([]string{"id"})
+
+	tasks, err := store.NewTaskStore(postgres).GetTasksForUser(context.Background(), testutil.TestUserID)
+	if err != nil {
+		t.Fatalf("GetTasksForUser: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("got %d tasks, want none", len(tasks))
+	}
+}
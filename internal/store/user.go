@@ -5,7 +5,9 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,25 +17,26 @@ import (
 )
 
 type User struct {
-	ID               string    `json:"id"`
-	Name             string    `json:"name"`
-	Email            string    `json:"email"`
-	Phone            string    `json:"phone,omitempty"`
-	StateID          string    `json:"state_id"`
-	StateName        string    `json:"state_name,omitempty"`
-	CollegeID        string    `json:"college_id"`
-	CollegeName      string    `json:"college_name,omitempty"`
-	Role             string    `json:"role"`
-	XP               int       `json:"xp"`
-	Level            int       `json:"level"`
-	Coins            int       `json:"coins"`
-	Bio              string    `json:"bio,omitempty"`
-	AvatarURL        string    `json:"avatar_url,omitempty"`
-	ResumeURL        string    `json:"resume_url,omitempty"`
-	ResumeVisibility string    `json:"resume_visibility"`
-	ReferralCode     string    `json:"referral_code"`
-	ReferredByID     string    `json:"referred_by_id,omitempty"`
-	CreatedAt        time.Time `json:"created_at"`
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Phone            string     `json:"phone,omitempty"`
+	StateID          string     `json:"state_id"`
+	StateName        string     `json:"state_name,omitempty"`
+	CollegeID        string     `json:"college_id"`
+	CollegeName      string     `json:"college_name,omitempty"`
+	Role             string     `json:"role"`
+	XP               int        `json:"xp"`
+	Level            int        `json:"level"`
+	Coins            int        `json:"coins"`
+	Bio              string     `json:"bio,omitempty"`
+	AvatarURL        string     `json:"avatar_url,omitempty"`
+	ResumeURL        string     `json:"resume_url,omitempty"`
+	ResumeVisibility string     `json:"resume_visibility"`
+	ReferralCode     string     `json:"referral_code"`
+	ReferredByID     string     `json:"referred_by_id,omitempty"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 type UserStore struct {
@@ -57,7 +60,7 @@ type RegisterRequest struct {
 }
 
 // Register creates a new user account
-func (s *UserStore) Register(ctx context.Context, req RegisterRequest, resumeURL, profilePicURL string) (*User, error) {
+func (s *UserStore) Register(ctx context.Context, req RegisterRequest, resumeURL, resumeKey, profilePicURL, profilePicKey string) (*User, error) {
 	// Start transaction
 	tx, err := s.postgres.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -95,8 +98,9 @@ func (s *UserStore) Register(ctx context.Context, req RegisterRequest, resumeURL
 	query := `
 		INSERT INTO users (
 			id, name, email, password_hash, state_id, college_id,
-			avatar_url, resume_url, referral_code, referred_by_id, role
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			avatar_url, resume_url, referral_code, referred_by_id, role,
+			avatar_s3_key, resume_s3_key
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''))
 		RETURNING id, name, email, phone, state_id, college_id, role, xp, level, coins,
 		          bio, avatar_url, resume_url, resume_visibility, referral_code, 
 		          referred_by_id, created_at
@@ -109,6 +113,7 @@ func (s *UserStore) Register(ctx context.Context, req RegisterRequest, resumeURL
 	err = tx.QueryRowContext(ctx, query,
 		userID, req.Name, req.Email, hashedPassword, req.StateID, req.CollegeID,
 		profilePicURL, resumeURL, referralCode, referrerID, "student",
+		profilePicKey, resumeKey,
 	).Scan(
 		&user.ID, &user.Name, &user.Email, &phone, &user.StateID, &user.CollegeID,
 		&user.Role, &user.XP, &user.Level, &user.Coins,
@@ -156,6 +161,110 @@ func (s *UserStore) Register(ctx context.Context, req RegisterRequest, resumeURL
 	return userWithNames, nil
 }
 
+// BulkImportUser is one account to create in a bulk import
+type BulkImportUser struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	StateID   string `json:"state_id"`
+	CollegeID string `json:"college_id"`
+}
+
+// ImportedUser is an account created by ImportUsers
+type ImportedUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// BulkImportError describes a row of a bulk import that could not be created
+type BulkImportError struct {
+	Email  string `json:"email"`
+	Reason string `json:"reason"`
+}
+
+// ImportUsers creates student accounts for users in one transaction. Emails that already have an
+// account are skipped; a row that fails is rolled back on its own and reported in rowErrors
+// without affecting the other rows. Accounts get a random password that is never shown to
+// anyone; users set their own through a password reset link.
+func (s *UserStore) ImportUsers(ctx context.Context, users []BulkImportUser) (imported []ImportedUser, skipped int, rowErrors []BulkImportError, err error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO users (id, name, email, password_hash, state_id, college_id, referral_code, role)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'student')
+		ON CONFLICT (email) DO NOTHING
+		RETURNING id, email
+	`
+
+	for _, u := range users {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_import_row`); err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		user, rowErr := s.importUser(ctx, tx, query, u)
+		if rowErr != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_import_row`); err != nil {
+				return nil, 0, nil, fmt.Errorf("failed to roll back savepoint: %w", err)
+			}
+			rowErrors = append(rowErrors, BulkImportError{Email: u.Email, Reason: rowErr.Error()})
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_import_row`); err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+
+		if user == nil {
+			skipped++
+			continue
+		}
+		imported = append(imported, *user)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return imported, skipped, rowErrors, nil
+}
+
+// importUser inserts one bulk import row. It returns a nil user when the email already has an account.
+func (s *UserStore) importUser(ctx context.Context, tx *sql.Tx, query string, u BulkImportUser) (*ImportedUser, error) {
+	password := make([]byte, 32)
+	if _, err := rand.Read(password); err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	// The password is random and never used to log in, so the minimum cost keeps large imports fast
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(password)), bcrypt.MinCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	referralCode, err := s.generateUniqueReferralCode(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ImportedUser{Name: u.Name}
+	err = tx.QueryRowContext(ctx, query,
+		uuid.New().String(), u.Name, u.Email, hashedPassword, u.StateID, u.CollegeID, referralCode,
+	).Scan(&user.ID, &user.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if strings.Contains(err.Error(), "violates foreign key constraint") {
+			return nil, fmt.Errorf("unknown state_id or college_id")
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &user, nil
+}
+
 // getUserIDByReferralCode gets user ID by referral code
 func (s *UserStore) getUserIDByReferralCode(ctx context.Context, tx *sql.Tx, referralCode string) (string, error) {
 	var userID string
@@ -176,7 +285,7 @@ func (s *UserStore) GetUserByEmail(ctx context.Context, email string) (*User, er
 		SELECT 
 			u.id, u.name, u.email, u.phone, u.state_id, u.college_id, u.role, u.xp, u.level, u.coins,
 			u.bio, u.avatar_url, u.resume_url, u.resume_visibility, u.referral_code,
-			u.referred_by_id, u.created_at,
+			u.referred_by_id, u.email_verified_at, u.created_at,
 			COALESCE(s.name, '') as state_name,
 			COALESCE(c.name, '') as college_name
 		FROM users u
@@ -187,12 +296,13 @@ func (s *UserStore) GetUserByEmail(ctx context.Context, email string) (*User, er
 	var user User
 	var phone, bio sql.NullString
 	var referredByID sql.NullString
+	var emailVerifiedAt sql.NullTime
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.Name, &user.Email, &phone, &user.StateID, &user.CollegeID,
 		&user.Role, &user.XP, &user.Level, &user.Coins,
 		&bio, &user.AvatarURL, &user.ResumeURL, &user.ResumeVisibility, &user.ReferralCode,
-		&referredByID, &user.CreatedAt,
+		&referredByID, &emailVerifiedAt, &user.CreatedAt,
 		&user.StateName, &user.CollegeName,
 	)
 	if err != nil {
@@ -211,6 +321,9 @@ func (s *UserStore) GetUserByEmail(ctx context.Context, email string) (*User, er
 	if referredByID.Valid {
 		user.ReferredByID = referredByID.String
 	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
 
 	return &user, nil
 }
@@ -257,26 +370,204 @@ func (s *UserStore) generateUniqueReferralCode(ctx context.Context, tx *sql.Tx)
 	return "", fmt.Errorf("failed to generate unique referral code after %d attempts", maxAttempts)
 }
 
-// UpdateResumeURL updates the resume URL for a user
-func (s *UserStore) UpdateResumeURL(ctx context.Context, userID, resumeURL string) error {
-	query := `UPDATE users SET resume_url = $1 WHERE id = $2`
-	_, err := s.postgres.DB.ExecContext(ctx, query, resumeURL, userID)
+// UpdateResumeURL updates the resume URL and its S3 key for a user
+func (s *UserStore) UpdateResumeURL(ctx context.Context, userID, resumeURL, resumeKey string) error {
+	query := `UPDATE users SET resume_url = $1, resume_s3_key = NULLIF($2, '') WHERE id = $3`
+	_, err := s.postgres.DB.ExecContext(ctx, query, resumeURL, resumeKey, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update resume URL: %w", err)
 	}
 	return nil
 }
 
-// UpdateProfilePicURL updates the profile picture URL for a user
-func (s *UserStore) UpdateProfilePicURL(ctx context.Context, userID, profilePicURL string) error {
-	query := `UPDATE users SET avatar_url = $1 WHERE id = $2`
-	_, err := s.postgres.DB.ExecContext(ctx, query, profilePicURL, userID)
+// Resume visibilities: who besides the owner can download a user's resume
+const (
+	ResumeVisibilityPublic      = "public"       // anyone, including anonymous visitors
+	ResumeVisibilityPrivate     = "private"      // nobody
+	ResumeVisibilityCollegeOnly = "college_only" // signed-in users of the same college
+)
+
+// IsValidResumeVisibility reports whether visibility is one of the resume visibilities
+func IsValidResumeVisibility(visibility string) bool {
+	switch visibility {
+	case ResumeVisibilityPublic, ResumeVisibilityPrivate, ResumeVisibilityCollegeOnly:
+		return true
+	}
+	return false
+}
+
+// UpdateResumeVisibility sets who can download the user's resume. Returns "user not found" if the user does not exist.
+func (s *UserStore) UpdateResumeVisibility(ctx context.Context, userID, visibility string) error {
+	query := `UPDATE users SET resume_visibility = $1 WHERE id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, visibility, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update resume visibility: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update resume visibility: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdateProfilePicURL updates the profile picture URL and its S3 key for a user
+func (s *UserStore) UpdateProfilePicURL(ctx context.Context, userID, profilePicURL, profilePicKey string) error {
+	query := `UPDATE users SET avatar_url = $1, avatar_s3_key = NULLIF($2, '') WHERE id = $3`
+	_, err := s.postgres.DB.ExecContext(ctx, query, profilePicURL, profilePicKey, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update profile picture URL: %w", err)
 	}
 	return nil
 }
 
+// UpdateProfileRequest holds the profile fields a user can change; nil fields are left unchanged
+type UpdateProfileRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Bio   *string `json:"bio,omitempty"`
+	Phone *string `json:"phone,omitempty"` // Empty string clears the phone number
+}
+
+// UpdateProfile updates the provided profile fields and returns the updated user.
+// Returns "user not found" if the user does not exist.
+func (s *UserStore) UpdateProfile(ctx context.Context, userID string, fields UpdateProfileRequest) (*User, error) {
+	updateFields := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if fields.Name != nil {
+		updateFields = append(updateFields, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *fields.Name)
+		argIndex++
+	}
+	if fields.Bio != nil {
+		updateFields = append(updateFields, fmt.Sprintf("bio = NULLIF($%d, '')", argIndex))
+		args = append(args, *fields.Bio)
+		argIndex++
+	}
+	if fields.Phone != nil {
+		updateFields = append(updateFields, fmt.Sprintf("phone = NULLIF($%d, '')", argIndex))
+		args = append(args, *fields.Phone)
+		argIndex++
+	}
+
+	if len(updateFields) > 0 {
+		args = append(args, userID)
+		query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d", strings.Join(updateFields, ", "), argIndex)
+		result, err := s.postgres.DB.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update profile: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to update profile: %w", err)
+		}
+		if rows == 0 {
+			return nil, fmt.Errorf("user not found")
+		}
+	}
+
+	return s.GetUserByID(ctx, userID)
+}
+
+// BanUser soft-bans a user so they can no longer use authenticated routes
+func (s *UserStore) BanUser(ctx context.Context, userID, reason string) error {
+	query := `UPDATE users SET is_banned = TRUE, banned_at = CURRENT_TIMESTAMP, ban_reason = NULLIF($1, '') WHERE id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, reason, userID)
+	if err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UnbanUser lifts a user's ban
+func (s *UserStore) UnbanUser(ctx context.Context, userID string) error {
+	query := `UPDATE users SET is_banned = FALSE, banned_at = NULL, ban_reason = NULL WHERE id = $1`
+	result, err := s.postgres.DB.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unban user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// IsUserBanned reports whether a user is banned. Unknown users are reported as not banned.
+func (s *UserStore) IsUserBanned(ctx context.Context, userID string) (bool, error) {
+	var banned bool
+	query := `SELECT is_banned FROM users WHERE id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&banned)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check ban status: %w", err)
+	}
+	return banned, nil
+}
+
+// UpdateFCMToken sets the push notification token of a user's device (empty clears it)
+func (s *UserStore) UpdateFCMToken(ctx context.Context, userID, token string) error {
+	query := `UPDATE users SET fcm_token = NULLIF($1, '') WHERE id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, token, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update FCM token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// GetFCMToken returns the push notification token of a user's device, or empty when none is registered
+func (s *UserStore) GetFCMToken(ctx context.Context, userID string) (string, error) {
+	var token sql.NullString
+	query := `SELECT fcm_token FROM users WHERE id = $1`
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("failed to get FCM token: %w", err)
+	}
+	return token.String, nil
+}
+
+// GetUserS3Keys returns the stored S3 keys of a user's resume and profile picture (empty when not set)
+func (s *UserStore) GetUserS3Keys(ctx context.Context, userID string) (resumeKey, avatarKey string, err error) {
+	query := `SELECT COALESCE(resume_s3_key, ''), COALESCE(avatar_s3_key, '') FROM users WHERE id = $1`
+	err = s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&resumeKey, &avatarKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", fmt.Errorf("user not found")
+		}
+		return "", "", fmt.Errorf("failed to get user S3 keys: %w", err)
+	}
+	return resumeKey, avatarKey, nil
+}
+
 // GetAllUsers retrieves all users with state and college names (for admin).
 // Returns name, email, state, college, resume_url. Supports pagination.
 func (s *UserStore) GetAllUsers(ctx context.Context, limit, offset int) ([]*User, error) {
@@ -291,7 +582,7 @@ func (s *UserStore) GetAllUsers(ctx context.Context, limit, offset int) ([]*User
 		SELECT 
 			u.id, u.name, u.email, u.phone, u.state_id, u.college_id, u.role, u.xp, u.level, u.coins,
 			u.bio, u.avatar_url, u.resume_url, u.resume_visibility, u.referral_code,
-			u.referred_by_id, u.created_at,
+			u.referred_by_id, u.email_verified_at, u.created_at,
 			COALESCE(s.name, '') as state_name,
 			COALESCE(c.name, '') as college_name
 		FROM users u
@@ -312,12 +603,13 @@ func (s *UserStore) GetAllUsers(ctx context.Context, limit, offset int) ([]*User
 		var user User
 		var phone, bio sql.NullString
 		var referredByID sql.NullString
+		var emailVerifiedAt sql.NullTime
 
 		err := rows.Scan(
 			&user.ID, &user.Name, &user.Email, &phone, &user.StateID, &user.CollegeID,
 			&user.Role, &user.XP, &user.Level, &user.Coins,
 			&bio, &user.AvatarURL, &user.ResumeURL, &user.ResumeVisibility, &user.ReferralCode,
-			&referredByID, &user.CreatedAt,
+			&referredByID, &emailVerifiedAt, &user.CreatedAt,
 			&user.StateName, &user.CollegeName,
 		)
 		if err != nil {
@@ -332,6 +624,9 @@ func (s *UserStore) GetAllUsers(ctx context.Context, limit, offset int) ([]*User
 		if referredByID.Valid {
 			user.ReferredByID = referredByID.String
 		}
+		if emailVerifiedAt.Valid {
+			user.EmailVerifiedAt = &emailVerifiedAt.Time
+		}
 		users = append(users, &user)
 	}
 	if err := rows.Err(); err != nil {
@@ -346,7 +641,7 @@ func (s *UserStore) GetUserByID(ctx context.Context, userID string) (*User, erro
 		SELECT 
 			u.id, u.name, u.email, u.phone, u.state_id, u.college_id, u.role, u.xp, u.level, u.coins,
 			u.bio, u.avatar_url, u.resume_url, u.resume_visibility, u.referral_code,
-			u.referred_by_id, u.created_at,
+			u.referred_by_id, u.email_verified_at, u.created_at,
 			COALESCE(s.name, '') as state_name,
 			COALESCE(c.name, '') as college_name
 		FROM users u
@@ -357,12 +652,13 @@ func (s *UserStore) GetUserByID(ctx context.Context, userID string) (*User, erro
 	var user User
 	var phone, bio sql.NullString
 	var referredByID sql.NullString
+	var emailVerifiedAt sql.NullTime
 
 	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(
 		&user.ID, &user.Name, &user.Email, &phone, &user.StateID, &user.CollegeID,
 		&user.Role, &user.XP, &user.Level, &user.Coins,
 		&bio, &user.AvatarURL, &user.ResumeURL, &user.ResumeVisibility, &user.ReferralCode,
-		&referredByID, &user.CreatedAt,
+		&referredByID, &emailVerifiedAt, &user.CreatedAt,
 		&user.StateName, &user.CollegeName,
 	)
 	if err != nil {
@@ -381,6 +677,9 @@ func (s *UserStore) GetUserByID(ctx context.Context, userID string) (*User, erro
 	if referredByID.Valid {
 		user.ReferredByID = referredByID.String
 	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
 
 	return &user, nil
 }
@@ -535,8 +834,9 @@ type FollowUserInfo struct {
 	CollegeName string `json:"college_name,omitempty"`
 }
 
-// GetFollowers returns users who follow the given user. Paginated.
-func (s *UserStore) GetFollowers(ctx context.Context, userID string, limit, offset int) ([]FollowUserInfo, error) {
+// GetFollowers returns a page of users who follow the given user, most recent follows first, and their total.
+// Users blocked by or blocking viewerID are left out of both; pass an empty viewerID to include everyone.
+func (s *UserStore) GetFollowers(ctx context.Context, userID, viewerID string, limit, offset int) ([]FollowUserInfo, int, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -544,41 +844,298 @@ func (s *UserStore) GetFollowers(ctx context.Context, userID string, limit, offs
 		limit = 100
 	}
 
+	from := `
+		FROM user_follows uf
+		INNER JOIN users u ON uf.follower_id = u.id
+		LEFT JOIN states s ON u.state_id = s.id
+		LEFT JOIN colleges c ON u.college_id = c.id
+		WHERE uf.following_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks ub
+				WHERE (ub.blocker_id = u.id AND ub.blocked_id = NULLIF($2, '')::uuid)
+				   OR (ub.blocker_id = NULLIF($2, '')::uuid AND ub.blocked_id = u.id)
+			)
+	`
+	var total int
+	if err := s.postgres.DB.QueryRowContext(ctx, `SELECT COUNT(*)`+from, userID, viewerID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count followers: %w", err)
+	}
+
 	query := `
 		SELECT u.id, u.name, u.avatar_url, u.xp, u.level,
 			COALESCE(s.name, '') as state_name, COALESCE(c.name, '') as college_name
+	` + from + `
+		ORDER BY uf.created_at DESC, u.id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, viewerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query followers: %w", err)
+	}
+	defer rows.Close()
+
+	list := []FollowUserInfo{}
+	for rows.Next() {
+		var u FollowUserInfo
+		var avatar sql.NullString
+		err := rows.Scan(&u.ID, &u.Name, &avatar, &u.XP, &u.Level, &u.StateName, &u.CollegeName)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan follower: %w", err)
+		}
+		if avatar.Valid {
+			u.AvatarURL = avatar.String
+		}
+		list = append(list, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating followers rows: %w", err)
+	}
+	return list, total, nil
+}
+
+// SearchUserResult is the public view of a user returned by user search; it never includes email or phone
+type SearchUserResult struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	CollegeName string `json:"college_name,omitempty"`
+	StateName   string `json:"state_name,omitempty"`
+	XP          int    `json:"xp"`
+	Level       int    `json:"level"`
+}
+
+// likeEscaper escapes LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsers finds students whose name contains query (case-insensitive), optionally limited to a
+// state and/or college. An empty query matches everyone. Banned users and users who have blocked
+// viewerID are left out. Returns the page ordered by name and the total number of matches.
+func (s *UserStore) SearchUsers(ctx context.Context, viewerID, query, stateID, collegeID string, limit, offset int) ([]SearchUserResult, int, error) {
+	conditions := []string{"u.role = 'student'", "NOT u.is_banned"}
+	args := []interface{}{}
+	argIndex := 1
+
+	if query != "" {
+		conditions = append(conditions, fmt.Sprintf("u.name ILIKE $%d", argIndex))
+		args = append(args, "%"+likeEscaper.Replace(query)+"%")
+		argIndex++
+	}
+	if stateID != "" {
+		conditions = append(conditions, fmt.Sprintf("u.state_id = $%d", argIndex))
+		args = append(args, stateID)
+		argIndex++
+	}
+	if collegeID != "" {
+		conditions = append(conditions, fmt.Sprintf("u.college_id = $%d", argIndex))
+		args = append(args, collegeID)
+		argIndex++
+	}
+	if viewerID != "" {
+		conditions = append(conditions, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE ub.blocker_id = u.id AND ub.blocked_id = $%d)", argIndex))
+		args = append(args, viewerID)
+		argIndex++
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM users u` + where
+	if err := s.postgres.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	selectQuery := `
+		SELECT u.id, u.name, u.avatar_url, COALESCE(c.name, '') as college_name, COALESCE(st.name, '') as state_name, u.xp, u.level
+		FROM users u
+		LEFT JOIN states st ON u.state_id = st.id
+		LEFT JOIN colleges c ON u.college_id = c.id
+	` + where + fmt.Sprintf(" ORDER BY u.name ASC, u.id ASC LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := s.postgres.DB.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	results := []SearchUserResult{}
+	for rows.Next() {
+		var u SearchUserResult
+		var avatar sql.NullString
+		if err := rows.Scan(&u.ID, &u.Name, &avatar, &u.CollegeName, &u.StateName, &u.XP, &u.Level); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if avatar.Valid {
+			u.AvatarURL = avatar.String
+		}
+		results = append(results, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating user rows: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// GetFollowing returns a page of users that the given user follows, most recent follows first, and their total.
+// Users blocked by or blocking viewerID are left out of both; pass an empty viewerID to include everyone.
+func (s *UserStore) GetFollowing(ctx context.Context, userID, viewerID string, limit, offset int) ([]FollowUserInfo, int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	from := `
 		FROM user_follows uf
-		INNER JOIN users u ON uf.follower_id = u.id
+		INNER JOIN users u ON uf.following_id = u.id
 		LEFT JOIN states s ON u.state_id = s.id
 		LEFT JOIN colleges c ON u.college_id = c.id
-		WHERE uf.following_id = $1
-		ORDER BY uf.created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE uf.follower_id = $1
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks ub
+				WHERE (ub.blocker_id = u.id AND ub.blocked_id = NULLIF($2, '')::uuid)
+				   OR (ub.blocker_id = NULLIF($2, '')::uuid AND ub.blocked_id = u.id)
+			)
+	`
+	var total int
+	if err := s.postgres.DB.QueryRowContext(ctx, `SELECT COUNT(*)`+from, userID, viewerID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count following: %w", err)
+	}
+
+	query := `
+		SELECT u.id, u.name, u.avatar_url, u.xp, u.level,
+			COALESCE(s.name, '') as state_name, COALESCE(c.name, '') as college_name
+	` + from + `
+		ORDER BY uf.created_at DESC, u.id DESC
+		LIMIT $3 OFFSET $4
 	`
-	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, viewerID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query followers: %w", err)
+		return nil, 0, fmt.Errorf("failed to query following: %w", err)
 	}
 	defer rows.Close()
 
-	var list []FollowUserInfo
+	list := []FollowUserInfo{}
 	for rows.Next() {
 		var u FollowUserInfo
 		var avatar sql.NullString
 		err := rows.Scan(&u.ID, &u.Name, &avatar, &u.XP, &u.Level, &u.StateName, &u.CollegeName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan follower: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan following: %w", err)
 		}
 		if avatar.Valid {
 			u.AvatarURL = avatar.String
 		}
 		list = append(list, u)
 	}
-	return list, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating following rows: %w", err)
+	}
+	return list, total, nil
+}
+
+// BlockUser creates a block relationship and removes any follow relationship between the two users
+func (s *UserStore) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	// Check if trying to block self
+	if blockerID == blockedID {
+		return fmt.Errorf("cannot block yourself")
+	}
+
+	// Check if user exists
+	_, err := s.GetUserByID(ctx, blockedID)
+	if err != nil {
+		return fmt.Errorf("user to block not found: %w", err)
+	}
+
+	// Check if already blocked
+	var exists bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`
+	err = s.postgres.DB.QueryRowContext(ctx, checkQuery, blockerID, blockedID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check block relationship: %w", err)
+	}
+
+	if exists {
+		return fmt.Errorf("already blocked this user")
+	}
+
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Create block relationship
+	query := `INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)`
+	_, err = tx.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to create block relationship: %w", err)
+	}
+
+	// Remove follow relationships in both directions
+	unfollowQuery := `
+		DELETE FROM user_follows
+		WHERE (follower_id = $1 AND following_id = $2)
+		   OR (follower_id = $2 AND following_id = $1)
+	`
+	_, err = tx.ExecContext(ctx, unfollowQuery, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to remove follow relationships: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UnblockUser removes a block relationship between two users
+func (s *UserStore) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	// Check if trying to unblock self
+	if blockerID == blockedID {
+		return fmt.Errorf("cannot unblock yourself")
+	}
+
+	// Remove block relationship
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	result, err := s.postgres.DB.ExecContext(ctx, query, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to remove block relationship: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not blocking this user")
+	}
+
+	return nil
+}
+
+// IsBlockedBetween reports whether either user has blocked the other
+func (s *UserStore) IsBlockedBetween(ctx context.Context, userAID, userBID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2)
+			   OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+	var blocked bool
+	err := s.postgres.DB.QueryRowContext(ctx, query, userAID, userBID).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check block relationship: %w", err)
+	}
+	return blocked, nil
 }
 
-// GetFollowing returns users that the given user follows. Paginated.
-func (s *UserStore) GetFollowing(ctx context.Context, userID string, limit, offset int) ([]FollowUserInfo, error) {
+// GetBlockedUsers returns the users blockerID has blocked, most recently blocked first. Paginated.
+func (s *UserStore) GetBlockedUsers(ctx context.Context, blockerID string, limit, offset int) ([]FollowUserInfo, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -589,27 +1146,27 @@ func (s *UserStore) GetFollowing(ctx context.Context, userID string, limit, offs
 	query := `
 		SELECT u.id, u.name, u.avatar_url, u.xp, u.level,
 			COALESCE(s.name, '') as state_name, COALESCE(c.name, '') as college_name
-		FROM user_follows uf
-		INNER JOIN users u ON uf.following_id = u.id
+		FROM user_blocks ub
+		INNER JOIN users u ON ub.blocked_id = u.id
 		LEFT JOIN states s ON u.state_id = s.id
 		LEFT JOIN colleges c ON u.college_id = c.id
-		WHERE uf.follower_id = $1
-		ORDER BY uf.created_at DESC
+		WHERE ub.blocker_id = $1
+		ORDER BY ub.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
-	rows, err := s.postgres.DB.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := s.postgres.DB.QueryContext(ctx, query, blockerID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query following: %w", err)
+		return nil, fmt.Errorf("failed to query blocked users: %w", err)
 	}
 	defer rows.Close()
 
-	var list []FollowUserInfo
+	list := []FollowUserInfo{}
 	for rows.Next() {
 		var u FollowUserInfo
 		var avatar sql.NullString
 		err := rows.Scan(&u.ID, &u.Name, &avatar, &u.XP, &u.Level, &u.StateName, &u.CollegeName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan following: %w", err)
+			return nil, fmt.Errorf("failed to scan blocked user: %w", err)
 		}
 		if avatar.Valid {
 			u.AvatarURL = avatar.String
@@ -618,3 +1175,87 @@ func (s *UserStore) GetFollowing(ctx context.Context, userID string, limit, offs
 	}
 	return list, rows.Err()
 }
+
+// IsFollowing reports whether followerID follows followingID
+func (s *UserStore) IsFollowing(ctx context.Context, followerID, followingID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $1 AND following_id = $2)`
+	var following bool
+	err := s.postgres.DB.QueryRowContext(ctx, query, followerID, followingID).Scan(&following)
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow relationship: %w", err)
+	}
+	return following, nil
+}
+
+// IsMutualFollow reports whether the two users follow each other
+func (s *UserStore) IsMutualFollow(ctx context.Context, userAID, userBID string) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $1 AND following_id = $2)
+		   AND EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $2 AND following_id = $1)
+	`
+	var mutual bool
+	err := s.postgres.DB.QueryRowContext(ctx, query, userAID, userBID).Scan(&mutual)
+	if err != nil {
+		return false, fmt.Errorf("failed to check mutual follow: %w", err)
+	}
+	return mutual, nil
+}
+
+// GetMutualFollows returns users that both userA and userB follow. Paginated.
+func (s *UserStore) GetMutualFollows(ctx context.Context, userAID, userBID string, page, pageSize int) ([]FollowUserInfo, int, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+	if offset < 0 {
+		offset = 0
+	}
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM user_follows a
+		INNER JOIN user_follows b ON a.following_id = b.following_id
+		WHERE a.follower_id = $1 AND b.follower_id = $2
+	`
+	var total int
+	err := s.postgres.DB.QueryRowContext(ctx, countQuery, userAID, userBID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count mutual follows: %w", err)
+	}
+
+	query := `
+		SELECT u.id, u.name, u.avatar_url, u.xp, u.level,
+			COALESCE(s.name, '') as state_name, COALESCE(c.name, '') as college_name
+		FROM user_follows a
+		INNER JOIN user_follows b ON a.following_id = b.following_id
+		INNER JOIN users u ON a.following_id = u.id
+		LEFT JOIN states s ON u.state_id = s.id
+		LEFT JOIN colleges c ON u.college_id = c.id
+		WHERE a.follower_id = $1 AND b.follower_id = $2
+		ORDER BY u.name ASC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := s.postgres.DB.QueryContext(ctx, query, userAID, userBID, pageSize, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query mutual follows: %w", err)
+	}
+	defer rows.Close()
+
+	list := []FollowUserInfo{}
+	for rows.Next() {
+		var u FollowUserInfo
+		var avatar sql.NullString
+		err := rows.Scan(&u.ID, &u.Name, &avatar, &u.XP, &u.Level, &u.StateName, &u.CollegeName)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan mutual follow: %w", err)
+		}
+		if avatar.Valid {
+			u.AvatarURL = avatar.String
+		}
+		list = append(list, u)
+	}
+	return list, total, rows.Err()
+}
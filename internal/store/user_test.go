@@ -0,0 +1,356 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestUserStoreUpdateProfile(t *testing.T) {
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		fields   store.UpdateProfileRequest
+		wantSet  string // SET clause of the expected UPDATE, empty when none is expected
+		wantArgs []any
+		affected int64
+		wantErr  string
+	}{
+		{name: "name", fields: store.UpdateProfileRequest{Name: str("New Name")}, wantSet: `name = \$1 WHERE id = \$2`, wantArgs: []any{"New Name", testutil.TestUserID}, affected: 1},
+		{name: "bio", fields: store.UpdateProfileRequest{Bio: str("Hello")}, wantSet: `bio = NULLIF\(\$1, ''\) WHERE id = \$2`, wantArgs: []any{"Hello", testutil.TestUserID}, affected: 1},
+		{name: "phone", fields: store.UpdateProfileRequest{Phone: str("9876543210")}, wantSet: `phone = NULLIF\(\$1, ''\) WHERE id = \$2`, wantArgs: []any{"9876543210", testutil.TestUserID}, affected: 1},
+		{name: "bio and phone", fields: store.UpdateProfileRequest{Bio: str(""), Phone: str("")}, wantSet: `bio = NULLIF\(\$1, ''\), phone = NULLIF\(\$2, ''\) WHERE id = \$3`, wantArgs: []any{"", "", testutil.TestUserID}, affected: 1},
+		{name: "all fields", fields: store.UpdateProfileRequest{Name: str("New Name"), Bio: str("Hello"), Phone: str("9876543210")}, wantSet: `name = \$1, bio = NULLIF\(\$2, ''\), phone = NULLIF\(\$3, ''\) WHERE id = \$4`, wantArgs: []any{"New Name", "Hello", "9876543210", testutil.TestUserID}, affected: 1},
+		{name: "nothing to set only re-reads", fields: store.UpdateProfileRequest{}},
+		{name: "unknown user", fields: store.UpdateProfileRequest{Name: str("New Name")}, wantSet: `name = \$1 WHERE id = \$2`, wantArgs: []any{"New Name", testutil.TestUserID}, wantErr: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.wantSet != "" {
+				mockDB.ExpectExec(`^UPDATE users SET ` + tt.wantSet + `$`).
+					WithArgs(tt.wantArgs...).
+					WillReturnResult(tt.affected)
+			}
+			if tt.wantErr == "" {
+				mockDB.ExpectQuery(`FROM users u\s+LEFT JOIN states s ON u.state_id = s.id`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows(userColumns, []any{testutil.TestUserID, "New Name", "test.user@example.com", "9876543210", testutil.TestStateID, testutil.TestCollegeID, "student", int64(0), int64(1), int64(0), "Hello", "", "", "public", "ABC123", nil, nil, testutil.TestTime, "", ""})
+			}
+
+			user, err := store.NewUserStore(postgres).UpdateProfile(context.Background(), testutil.TestUserID, tt.fields)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateProfile: %v", err)
+			}
+			if user.Name != "New Name" || user.Bio != "Hello" || user.Phone != "9876543210" {
+				t.Errorf("user = %+v, want the re-read profile", user)
+			}
+		})
+	}
+}
+
+func TestUserStoreGetFollowers(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		offset    int
+		wantLimit int
+	}{
+		{name: "page", limit: 10, offset: 20, wantLimit: 10},
+		{name: "default limit", limit: 0, wantLimit: 50},
+		{name: "limit capped", limit: 1000, wantLimit: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			// The count and the page apply the same follow and block filters
+			mockDB.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM user_follows uf\s+INNER JOIN users u ON uf.follower_id = u.id[\s\S]+WHERE uf.following_id = \$1[\s\S]+NULLIF\(\$2, ''\)`).
+				WithArgs(testutil.TestUserID, testutil.TestAdminID).
+				WillReturnRows([]string{"count"}, []any{int64(25)})
+			mockDB.ExpectQuery(`INNER JOIN users u ON uf.follower_id = u.id[\s\S]+WHERE uf.following_id = \$1[\s\S]+ORDER BY uf.created_at DESC, u.id DESC\s+LIMIT \$3 OFFSET \$4`).
+				WithArgs(testutil.TestUserID, testutil.TestAdminID, tt.wantLimit, tt.offset).
+				WillReturnRows([]string{"id", "name", "avatar_url", "xp", "level", "state_name", "college_name"},
+					[]any{testutil.TestFeedID, "Follower", nil, int64(120), int64(2), "Karnataka", ""})
+
+			followers, total, err := store.NewUserStore(postgres).GetFollowers(context.Background(), testutil.TestUserID, testutil.TestAdminID, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("GetFollowers: %v", err)
+			}
+			if total != 25 || len(followers) != 1 || followers[0].Name != "Follower" || followers[0].XP != 120 {
+				t.Errorf("got %+v of %d", followers, total)
+			}
+		})
+	}
+}
+
+func TestUserStoreGetFollowingEmpty(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM user_follows uf\s+INNER JOIN users u ON uf.following_id = u.id[\s\S]+WHERE uf.follower_id = \$1`).
+		WithArgs(testutil.TestUserID, "").
+		WillReturnRows([]string{"count"}, []any{int64(0)})
+	mockDB.ExpectQuery(`INNER JOIN users u ON uf.following_id = u.id[\s\S]+WHERE uf.follower_id = \$1[\s\S]+LIMIT \$3 OFFSET \$4`).
+		WithArgs(testutil.TestUserID, "", 50, 0).
+		WillReturnRows([]string{"id", "name", "avatar_url", "xp", "level", "state_name", "college_name"})
+
+	following, total, err := store.NewUserStore(postgres).GetFollowing(context.Background(), testutil.TestUserID, "", 50, 0)
+	if err != nil {
+		t.Fatalf("GetFollowing: %v", err)
+	}
+	if total != 0 || following == nil || len(following) != 0 {
+		t.Errorf("got %#v of %d, want an empty list", following, total)
+	}
+}
+
+func TestUserStoreSearchUsers(t *testing.T) {
+	const base = `u.role = 'student' AND NOT u.is_banned`
+	tests := []struct {
+		name       string
+		viewerID   string
+		query      string
+		stateID    string
+		collegeID  string
+		wantWhere  string
+		wantArgs   []any
+		wantPaging string
+	}{
+		{name: "empty query matches everyone", wantWhere: base, wantPaging: `LIMIT $1 OFFSET $2`},
+		{name: "partial name", query: "ash", wantWhere: base + ` AND u.name ILIKE $1`, wantArgs: []any{"%ash%"}, wantPaging: `LIMIT $2 OFFSET $3`},
+		{
+			name:       "state and college",
+			viewerID:   testutil.TestUserID,
+			stateID:    testutil.TestStateID,
+			collegeID:  testutil.TestCollegeID,
+			wantWhere:  base + ` AND u.state_id = $1 AND u.college_id = $2 AND NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE ub.blocker_id = u.id AND ub.blocked_id = $3)`,
+			wantArgs:   []any{testutil.TestStateID, testutil.TestCollegeID, testutil.TestUserID},
+			wantPaging: `LIMIT $4 OFFSET $5`,
+		},
+		// The query only ever reaches Postgres as a bound parameter
+		{name: "SQL injection", query: "'; DROP TABLE users; --", wantWhere: base + ` AND u.name ILIKE $1`, wantArgs: []any{"%'; DROP TABLE users; --%"}, wantPaging: `LIMIT $2 OFFSET $3`},
+		{name: "LIKE wildcards match literally", query: `100%_off\`, wantWhere: base + ` AND u.name ILIKE $1`, wantArgs: []any{`%100\%\_off\\%`}, wantPaging: `LIMIT $2 OFFSET $3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectQuery(`^`+regexp.QuoteMeta(`SELECT COUNT(*) FROM users u WHERE `+tt.wantWhere)+`$`).
+				WithArgs(tt.wantArgs...).
+				WillReturnRows([]string{"count"}, []any{int64(21)})
+			mockDB.ExpectQuery(regexp.QuoteMeta(`WHERE `+tt.wantWhere+` ORDER BY u.name ASC, u.id ASC `+tt.wantPaging)+`$`).
+				WithArgs(append(tt.wantArgs, 20, 20)...).
+				WillReturnRows([]string{"id", "name", "avatar_url", "college_name", "state_name", "xp", "level"},
+					[]any{testutil.TestFeedID, "Asha", nil, "IIT Bombay", "Maharashtra", int64(340), int64(3)})
+
+			users, total, err := store.NewUserStore(postgres).SearchUsers(context.Background(), tt.viewerID, tt.query, tt.stateID, tt.collegeID, 20, 20)
+			if err != nil {
+				t.Fatalf("SearchUsers: %v", err)
+			}
+			want := store.SearchUserResult{ID: testutil.TestFeedID, Name: "Asha", CollegeName: "IIT Bombay", StateName: "Maharashtra", XP: 340, Level: 3}
+			if total != 21 || len(users) != 1 || users[0] != want {
+				t.Errorf("got %+v of %d, want [%+v] of 21", users, total, want)
+			}
+		})
+	}
+}
+
+func TestUserStoreUpdateResumeVisibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  string
+	}{
+		{name: "updated", affected: 1},
+		{name: "unknown user", affected: 0, wantErr: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			mockDB.ExpectExec(`^UPDATE users SET resume_visibility = \$1 WHERE id = \$2$`).
+				WithArgs("college_only", testutil.TestUserID).
+				WillReturnResult(tt.affected)
+
+			err := store.NewUserStore(postgres).UpdateResumeVisibility(context.Background(), testutil.TestUserID, "college_only")
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateResumeVisibility: %v", err)
+			}
+		})
+	}
+}
+
+func TestUserStoreBlockUser(t *testing.T) {
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+	otherUserID := "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	tests := []struct {
+		name          string
+		blockedID     string
+		found         bool
+		alreadyExists bool
+		wantErr       string
+	}{
+		{name: "blocks and unfollows", blockedID: otherUserID, found: true},
+		{name: "self", blockedID: testutil.TestUserID, wantErr: "cannot block yourself"},
+		{name: "unknown user", blockedID: otherUserID, wantErr: "user to block not found: user not found"},
+		{name: "already blocked", blockedID: otherUserID, found: true, alreadyExists: true, wantErr: "already blocked this user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.blockedID != testutil.TestUserID {
+				var rows [][]any
+				if tt.found {
+					rows = append(rows, []any{otherUserID, "Other User", "other@example.com", "9876543211", testutil.TestStateID, testutil.TestCollegeID, "student", int64(0), int64(1), int64(0), "", "", "", "public", "XYZ789", nil, nil, testutil.TestTime, "", ""})
+				}
+				mockDB.ExpectQuery(`FROM users u\s+LEFT JOIN states s`).
+					WithArgs(otherUserID).
+					WillReturnRows(userColumns, rows...)
+			}
+			if tt.found {
+				mockDB.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM user_blocks WHERE blocker_id = \$1 AND blocked_id = \$2\)`).
+					WithArgs(testutil.TestUserID, otherUserID).
+					WillReturnRows([]string{"exists"}, []any{tt.alreadyExists})
+			}
+			if tt.wantErr == "" {
+				mockDB.ExpectBegin()
+				mockDB.ExpectExec(`INSERT INTO user_blocks \(blocker_id, blocked_id\) VALUES \(\$1, \$2\)`).
+					WithArgs(testutil.TestUserID, otherUserID).
+					WillReturnResult(1)
+				// Following stops in both directions along with the block
+				mockDB.ExpectExec(`DELETE FROM user_follows\s+WHERE \(follower_id = \$1 AND following_id = \$2\)\s+OR \(follower_id = \$2 AND following_id = \$1\)`).
+					WithArgs(testutil.TestUserID, otherUserID).
+					WillReturnResult(2)
+				mockDB.ExpectCommit()
+			}
+
+			err := store.NewUserStore(postgres).BlockUser(context.Background(), testutil.TestUserID, tt.blockedID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BlockUser: %v", err)
+			}
+		})
+	}
+}
+
+func TestUserStoreBlockUserRollsBackFailedUnfollow(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	otherUserID := "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	userColumns := []string{"id", "name", "email", "phone", "state_id", "college_id", "role", "xp", "level", "coins", "bio", "avatar_url", "resume_url", "resume_visibility", "referral_code", "referred_by_id", "email_verified_at", "created_at", "state_name", "college_name"}
+	mockDB.ExpectQuery(`FROM users u\s+LEFT JOIN states s`).
+		WithArgs(otherUserID).
+		WillReturnRows(userColumns, []any{otherUserID, "Other User", "other@example.com", "9876543211", testutil.TestStateID, testutil.TestCollegeID, "student", int64(0), int64(1), int64(0), "", "", "", "public", "XYZ789", nil, nil, testutil.TestTime, "", ""})
+	mockDB.ExpectQuery(`FROM user_blocks WHERE blocker_id = \$1 AND blocked_id = \$2`).
+		WithArgs(testutil.TestUserID, otherUserID).
+		WillReturnRows([]string{"exists"}, []any{false})
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO user_blocks`).
+		WithArgs(testutil.TestUserID, otherUserID).
+		WillReturnResult(1)
+	mockDB.ExpectExec(`DELETE FROM user_follows`).
+		WithArgs(testutil.TestUserID, otherUserID).
+		WillReturnError(errors.New("connection reset"))
+	// The block is not kept without the unfollow
+	mockDB.ExpectRollback()
+
+	err := store.NewUserStore(postgres).BlockUser(context.Background(), testutil.TestUserID, otherUserID)
+	if err == nil || err.Error() != "failed to remove follow relationships: connection reset" {
+		t.Fatalf("err = %v, want the unfollow error", err)
+	}
+}
+
+func TestUserStoreUnblockUser(t *testing.T) {
+	otherUserID := "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	tests := []struct {
+		name      string
+		blockedID string
+		affected  int64
+		wantErr   string
+	}{
+		{name: "unblocks", blockedID: otherUserID, affected: 1},
+		{name: "not blocked", blockedID: otherUserID, wantErr: "not blocking this user"},
+		{name: "self", blockedID: testutil.TestUserID, wantErr: "cannot unblock yourself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			if tt.blockedID != testutil.TestUserID {
+				// Unblocking leaves follows alone; they were removed by the block
+				mockDB.ExpectExec(`^DELETE FROM user_blocks WHERE blocker_id = \$1 AND blocked_id = \$2$`).
+					WithArgs(testutil.TestUserID, otherUserID).
+					WillReturnResult(tt.affected)
+			}
+
+			err := store.NewUserStore(postgres).UnblockUser(context.Background(), testutil.TestUserID, tt.blockedID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnblockUser: %v", err)
+			}
+		})
+	}
+}
+
+func TestUserStoreIsBlockedBetween(t *testing.T) {
+	otherUserID := "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	for _, blocked := range []bool{true, false} {
+		postgres, mockDB := testutil.NewMockPostgres(t)
+		// Either user's block counts
+		mockDB.ExpectQuery(`\(blocker_id = \$1 AND blocked_id = \$2\)\s+OR \(blocker_id = \$2 AND blocked_id = \$1\)`).
+			WithArgs(testutil.TestUserID, otherUserID).
+			WillReturnRows([]string{"exists"}, []any{blocked})
+
+		got, err := store.NewUserStore(postgres).IsBlockedBetween(context.Background(), testutil.TestUserID, otherUserID)
+		if err != nil {
+			t.Fatalf("IsBlockedBetween: %v", err)
+		}
+		if got != blocked {
+			t.Errorf("IsBlockedBetween = %v, want %v", got, blocked)
+		}
+	}
+}
+
+func TestUserStoreGetBlockedUsers(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	otherUserID := "dddddddd-dddd-dddd-dddd-dddddddddddd"
+	mockDB.ExpectQuery(`FROM user_blocks ub\s+INNER JOIN users u ON ub.blocked_id = u.id[\s\S]+WHERE ub.blocker_id = \$1\s+ORDER BY ub.created_at DESC\s+LIMIT \$2 OFFSET \$3`).
+		WithArgs(testutil.TestUserID, 100, 0).
+		WillReturnRows([]string{"id", "name", "avatar_url", "xp", "level", "state_name", "college_name"},
+			[]any{otherUserID, "Other User", nil, int64(40), int64(1), "", ""})
+
+	users, err := store.NewUserStore(postgres).GetBlockedUsers(context.Background(), testutil.TestUserID, 1000, 0)
+	if err != nil {
+		t.Fatalf("GetBlockedUsers: %v", err)
+	}
+	want := store.FollowUserInfo{ID: otherUserID, Name: "Other User", XP: 40, Level: 1}
+	if len(users) != 1 || users[0] != want {
+		t.Errorf("users = %+v, want [%+v]", users, want)
+	}
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// WeeklyResetStore handles the start-of-week cleanup of weekly task submissions
+type WeeklyResetStore struct {
+	postgres *db.Postgres
+}
+
+func NewWeeklyResetStore(postgres *db.Postgres) *WeeklyResetStore {
+	return &WeeklyResetStore{
+		postgres: postgres,
+	}
+}
+
+// ClearUnreviewedSubmissions deletes pending submissions of weekly tasks from before the current week.
+// Users submit weekly tasks again every week, so earlier submissions nobody reviewed would only clutter the admin queue.
+// Returns the number of submissions deleted.
+func (s *WeeklyResetStore) ClearUnreviewedSubmissions(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM submissions s
+		USING tasks t
+		WHERE t.id = s.task_id
+			AND t.is_weekly = true
+			AND s.status = 'pending'
+			AND s.created_at < date_trunc('week', NOW())
+	`
+	result, err := s.postgres.DB.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear unreviewed weekly submissions: %w", err)
+	}
+	cleared, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count cleared weekly submissions: %w", err)
+	}
+	return cleared, nil
+}
@@ -3,11 +3,16 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/telemetry"
 )
 
 // XPSource represents the source of XP
@@ -21,17 +26,75 @@ const (
 	XPSourceFeedReaction XPSource = "feed_reaction" // XP from reacting to feed
 	XPSourceComment      XPSource = "comment"       // XP from commenting
 	XPSourceAdminGrant   XPSource = "admin_grant"   // XP added by admin (manual grant)
+	XPSourceAdminDeduct  XPSource = "admin_deduct"  // XP removed by admin (manual deduction)
 	XPSourceUserAdd      XPSource = "user_add"      // XP added by user to own account (e.g. redeem code, claim reward)
 	// Add more sources as needed in the future
 )
 
+// ErrDailyCapExceeded is returned by AwardXP when the user already earned the daily cap for the source
+var ErrDailyCapExceeded = errors.New("daily XP cap reached for this source")
+
+// ErrInsufficientXP is returned by DeductXP when the deduction is larger than the user's XP
+// and the request does not allow stopping at 0
+var ErrInsufficientXP = errors.New("deduction exceeds the user's XP")
+
+var (
+	dailyXPCaps   map[XPSource]int
+	dailyXPCapsMu sync.RWMutex
+)
+
+// SetDailyXPCaps sets the most XP a user can earn per day from each source.
+// Sources not in caps are unlimited; task approvals and admin grants are never capped.
+func SetDailyXPCaps(caps map[XPSource]int) {
+	dailyXPCapsMu.Lock()
+	dailyXPCaps = caps
+	dailyXPCapsMu.Unlock()
+}
+
+// dailyXPCap returns the daily cap for source, if it has one
+func dailyXPCap(source XPSource) (int, bool) {
+	if source == XPSourceTaskApproval || source == XPSourceAdminGrant {
+		return 0, false
+	}
+	dailyXPCapsMu.RLock()
+	defer dailyXPCapsMu.RUnlock()
+	xpCap, ok := dailyXPCaps[source]
+	return xpCap, ok && xpCap > 0
+}
+
+// xpSourceLabels are the human-readable names of the XP sources shown to users
+var xpSourceLabels = map[XPSource]string{
+	XPSourceTaskApproval: "Task Approval",
+	XPSourceReferral:     "Referral",
+	XPSourceDailyLogin:   "Daily Login",
+	XPSourceFeedPost:     "Feed Post",
+	XPSourceFeedReaction: "Feed Reaction",
+	XPSourceComment:      "Comment",
+	XPSourceAdminGrant:   "Admin Adjustment",
+	XPSourceAdminDeduct:  "Admin Adjustment",
+	XPSourceUserAdd:      "Reward",
+}
+
+// XPSourceLabel returns the human-readable name of an XP source, or the source itself if it is unknown
+func XPSourceLabel(source string) string {
+	if label, ok := xpSourceLabels[XPSource(source)]; ok {
+		return label
+	}
+	return source
+}
+
 type XPLog struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Source    string    `json:"source"`
-	SourceID  string    `json:"source_id,omitempty"`
-	XP        int       `json:"xp"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Source      string    `json:"source"`
+	SourceLabel string    `json:"source_label,omitempty"` // Set by GetXPLogs: human-readable name of Source
+	SourceID    string    `json:"source_id,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	XP          int       `json:"xp"`
+	CreatedAt   time.Time `json:"created_at"`
+	NewXP       int       `json:"new_xp,omitempty"`     // Set by AwardXP and DeductXP: user's total XP after the change
+	NewLevel    int       `json:"new_level,omitempty"`  // Set by AwardXP and DeductXP: user's level after the change
+	LeveledUp   bool      `json:"leveled_up,omitempty"` // Set by AwardXP: true if the award raised the user's level
 }
 
 type XPStore struct {
@@ -49,34 +112,74 @@ type AwardXPRequest struct {
 	UserID   string   `json:"user_id"`
 	XP       int      `json:"xp"`
 	Source   XPSource `json:"source"`
-	SourceID string   `json:"source_id,omitempty"` // Optional: ID of the source (e.g., task_id, submission_id)
+	SourceID string   `json:"source_id,omitempty"` // Optional: ID of the source (e.g., task_id, submission_id); must be a UUID
+	Reason   string   `json:"reason,omitempty"`    // Optional: free-text reason (e.g. "coins_exchange")
+	// DeductXP only: remove what the user has and stop at 0 instead of returning ErrInsufficientXP
+	AllowNegative bool `json:"-"`
 }
 
 // AwardXP awards XP to a user and logs it
 // This is a transactional operation that:
 // 1. Updates the user's XP in the users table
 // 2. Logs the XP award in the xp_logs table
+// 3. Updates the user's level from the levels table
 func (s *XPStore) AwardXP(ctx context.Context, req AwardXPRequest) (*XPLog, error) {
 	if req.XP <= 0 {
 		return nil, fmt.Errorf("XP amount must be greater than 0")
 	}
 
+	ctx, span := telemetry.StartSpan(ctx, "store.AwardXP")
+	defer span.End()
+
 	// Start transaction
-	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	tx, err := s.postgres.Traced.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	xpLog, err := awardXPTx(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Check and award badges based on new XP and level (after commit)
+	// This is done outside the transaction to avoid long-running transactions
+	badgeStore := NewBadgeStore(s.postgres)
+	err = badgeStore.CheckAndAwardBadges(ctx, req.UserID, xpLog.NewXP, xpLog.NewLevel)
+	if err != nil {
+		// Log error but don't fail - badge awarding is not critical
+		// In production, you might want to use a queue/retry mechanism
+	}
+
+	return xpLog, nil
+}
+
+// awardXPTx adds req.XP to the user, logs it and updates the level inside tx. Badges are not checked.
+func awardXPTx(ctx context.Context, tx *sql.Tx, req AwardXPRequest) (*XPLog, error) {
+	// Trim the award to what is left of the source's daily cap
+	if xpCap, capped := dailyXPCap(req.Source); capped {
+		xp, err := applyDailyXPCap(ctx, tx, req, xpCap)
+		if err != nil {
+			return nil, err
+		}
+		req.XP = xp
+	}
+
 	// Update user's XP
 	updateQuery := `
 		UPDATE users
 		SET xp = xp + $1
 		WHERE id = $2
-		RETURNING xp
+		RETURNING xp, level
 	`
-	var newXP int
-	err = tx.QueryRowContext(ctx, updateQuery, req.XP, req.UserID).Scan(&newXP)
+	var newXP, userLevel int
+	err := tx.QueryRowContext(ctx, updateQuery, req.XP, req.UserID).Scan(&newXP, &userLevel)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
@@ -91,19 +194,24 @@ func (s *XPStore) AwardXP(ctx context.Context, req AwardXPRequest) (*XPLog, erro
 		sourceID = sql.NullString{String: req.SourceID, Valid: true}
 	}
 
+	var reason sql.NullString
+	if req.Reason != "" {
+		reason = sql.NullString{String: req.Reason, Valid: true}
+	}
+
 	logQuery := `
-		INSERT INTO xp_logs (id, user_id, source, source_id, xp)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, user_id, source, source_id, xp, created_at
+		INSERT INTO xp_logs (id, user_id, source, source_id, reason, xp)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, source, source_id, reason, xp, created_at
 	`
 
 	var xpLog XPLog
-	var logSourceID sql.NullString
+	var logSourceID, logReason sql.NullString
 
 	err = tx.QueryRowContext(ctx, logQuery,
-		logID, req.UserID, string(req.Source), sourceID, req.XP,
+		logID, req.UserID, string(req.Source), sourceID, reason, req.XP,
 	).Scan(
-		&xpLog.ID, &xpLog.UserID, &xpLog.Source, &logSourceID, &xpLog.XP, &xpLog.CreatedAt,
+		&xpLog.ID, &xpLog.UserID, &xpLog.Source, &logSourceID, &logReason, &xpLog.XP, &xpLog.CreatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to log XP award: %w", err)
@@ -112,37 +220,155 @@ func (s *XPStore) AwardXP(ctx context.Context, req AwardXPRequest) (*XPLog, erro
 	if logSourceID.Valid {
 		xpLog.SourceID = logSourceID.String
 	}
+	if logReason.Valid {
+		xpLog.Reason = logReason.String
+	}
 
-	// Get user's current level (for badge checking)
-	var userLevel int
-	levelQuery := `SELECT level FROM users WHERE id = $1`
-	err = tx.QueryRowContext(ctx, levelQuery, req.UserID).Scan(&userLevel)
+	// Update level if the new XP crosses a threshold
+	newLevel, err := levelForXP(ctx, tx, newXP)
 	if err != nil {
-		// Log error but don't fail - level check is not critical
-		userLevel = 1
+		return nil, err
+	}
+	if newLevel != userLevel {
+		_, err = tx.ExecContext(ctx, `UPDATE users SET level = $1 WHERE id = $2`, newLevel, req.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update user level: %w", err)
+		}
+		xpLog.LeveledUp = newLevel > userLevel
+		userLevel = newLevel
 	}
+	xpLog.NewXP = newXP
+	xpLog.NewLevel = userLevel
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	return &xpLog, nil
+}
+
+// DeductXP removes req.XP from a user's XP and logs it in xp_logs as a negative entry.
+// XP never goes below 0: a deduction larger than the user's XP returns ErrInsufficientXP unless
+// req.AllowNegative is set, in which case the logged amount is what was actually removed. The level
+// is recalculated and may go down; badges already earned are kept.
+func (s *XPStore) DeductXP(ctx context.Context, req AwardXPRequest) (*XPLog, error) {
+	if req.XP <= 0 {
+		return nil, fmt.Errorf("XP amount must be greater than 0")
 	}
 
-	// Check and award badges based on new XP and level (after commit)
-	// This is done outside the transaction to avoid long-running transactions
-	badgeStore := NewBadgeStore(s.postgres)
-	err = badgeStore.CheckAndAwardBadges(ctx, req.UserID, newXP, userLevel)
+	ctx, span := telemetry.StartSpan(ctx, "store.DeductXP")
+	defer span.End()
+
+	tx, err := s.postgres.Traced.BeginTx(ctx, nil)
 	if err != nil {
-		// Log error but don't fail - badge awarding is not critical
-		// In production, you might want to use a queue/retry mechanism
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the row so the deducted amount matches the XP it was computed from
+	var oldXP, userLevel int
+	err = tx.QueryRowContext(ctx, `SELECT xp, level FROM users WHERE id = $1 FOR UPDATE`, req.UserID).Scan(&oldXP, &userLevel)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user XP: %w", err)
+	}
+
+	deducted := req.XP
+	if deducted > oldXP {
+		if !req.AllowNegative {
+			return nil, fmt.Errorf("%w: user has %d XP", ErrInsufficientXP, oldXP)
+		}
+		deducted = oldXP
+	}
+	newXP := oldXP - deducted
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET xp = $1 WHERE id = $2`, newXP, req.UserID); err != nil {
+		return nil, fmt.Errorf("failed to update user XP: %w", err)
+	}
+
+	var sourceID sql.NullString
+	if req.SourceID != "" {
+		sourceID = sql.NullString{String: req.SourceID, Valid: true}
+	}
+
+	var reason sql.NullString
+	if req.Reason != "" {
+		reason = sql.NullString{String: req.Reason, Valid: true}
+	}
+
+	logQuery := `
+		INSERT INTO xp_logs (id, user_id, source, source_id, reason, xp)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, source, source_id, reason, xp, created_at
+	`
+
+	var xpLog XPLog
+	var logSourceID, logReason sql.NullString
+
+	err = tx.QueryRowContext(ctx, logQuery,
+		uuid.New().String(), req.UserID, string(req.Source), sourceID, reason, -deducted,
+	).Scan(
+		&xpLog.ID, &xpLog.UserID, &xpLog.Source, &logSourceID, &logReason, &xpLog.XP, &xpLog.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log XP deduction: %w", err)
+	}
+
+	if logSourceID.Valid {
+		xpLog.SourceID = logSourceID.String
+	}
+	if logReason.Valid {
+		xpLog.Reason = logReason.String
+	}
+
+	newLevel, err := levelForXP(ctx, tx, newXP)
+	if err != nil {
+		return nil, err
+	}
+	if newLevel != userLevel {
+		_, err = tx.ExecContext(ctx, `UPDATE users SET level = $1 WHERE id = $2`, newLevel, req.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update user level: %w", err)
+		}
+		userLevel = newLevel
+	}
+	xpLog.NewXP = newXP
+	xpLog.NewLevel = userLevel
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return &xpLog, nil
 }
 
+// AdjustXP applies an admin's manual XP correction of delta (positive or negative) to a user.
+// Grants are logged with source admin_grant and deductions with admin_deduct, with the admin's ID
+// as source_id. A deduction larger than the user's XP returns ErrInsufficientXP unless allowNegative
+// is set, in which case XP stops at 0 and only what was actually removed is logged.
+func (s *XPStore) AdjustXP(ctx context.Context, adminID, userID string, delta int, reason string, allowNegative bool) (*XPLog, error) {
+	if delta == 0 {
+		return nil, fmt.Errorf("XP amount must not be 0")
+	}
+
+	req := AwardXPRequest{
+		UserID:   userID,
+		SourceID: adminID,
+		Reason:   reason,
+	}
+	if delta > 0 {
+		req.XP = delta
+		req.Source = XPSourceAdminGrant
+		return s.AwardXP(ctx, req)
+	}
+	req.XP = -delta
+	req.Source = XPSourceAdminDeduct
+	req.AllowNegative = allowNegative
+	return s.DeductXP(ctx, req)
+}
+
 // GetXPLogs retrieves XP logs for a user
 func (s *XPStore) GetXPLogs(ctx context.Context, userID string, limit int) ([]XPLog, error) {
 	query := `
-		SELECT id, user_id, source, source_id, xp, created_at
+		SELECT id, user_id, source, source_id, reason, xp, created_at
 		FROM xp_logs
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -158,10 +384,10 @@ func (s *XPStore) GetXPLogs(ctx context.Context, userID string, limit int) ([]XP
 	var logs []XPLog
 	for rows.Next() {
 		var log XPLog
-		var sourceID sql.NullString
+		var sourceID, reason sql.NullString
 
 		err := rows.Scan(
-			&log.ID, &log.UserID, &log.Source, &sourceID, &log.XP, &log.CreatedAt,
+			&log.ID, &log.UserID, &log.Source, &sourceID, &reason, &log.XP, &log.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan XP log: %w", err)
@@ -170,6 +396,10 @@ func (s *XPStore) GetXPLogs(ctx context.Context, userID string, limit int) ([]XP
 		if sourceID.Valid {
 			log.SourceID = sourceID.String
 		}
+		if reason.Valid {
+			log.Reason = reason.String
+		}
+		log.SourceLabel = XPSourceLabel(log.Source)
 
 		logs = append(logs, log)
 	}
@@ -181,6 +411,37 @@ func (s *XPStore) GetXPLogs(ctx context.Context, userID string, limit int) ([]XP
 	return logs, nil
 }
 
+// XPSummary is a user's total XP and level with the XP earned in the current week and month
+type XPSummary struct {
+	TotalXP     int `json:"total_xp"`
+	XPThisWeek  int `json:"xp_this_week"`
+	XPThisMonth int `json:"xp_this_month"`
+	Level       int `json:"level"`
+}
+
+// GetXPSummary retrieves a user's XP summary. Weeks start on Monday. Deductions count against
+// the week and month they were made in. Returns "user not found" if the user does not exist.
+func (s *XPStore) GetXPSummary(ctx context.Context, userID string) (*XPSummary, error) {
+	query := `
+		SELECT u.xp, u.level,
+			COALESCE(SUM(l.xp) FILTER (WHERE l.created_at >= DATE_TRUNC('week', NOW())), 0),
+			COALESCE(SUM(l.xp) FILTER (WHERE l.created_at >= DATE_TRUNC('month', NOW())), 0)
+		FROM users u
+		LEFT JOIN xp_logs l ON l.user_id = u.id AND l.created_at >= LEAST(DATE_TRUNC('week', NOW()), DATE_TRUNC('month', NOW()))
+		WHERE u.id = $1
+		GROUP BY u.id
+	`
+	var summary XPSummary
+	err := s.postgres.DB.QueryRowContext(ctx, query, userID).Scan(&summary.TotalXP, &summary.Level, &summary.XPThisWeek, &summary.XPThisMonth)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get XP summary: %w", err)
+	}
+	return &summary, nil
+}
+
 // GetUserTotalXP retrieves the current total XP for a user
 func (s *XPStore) GetUserTotalXP(ctx context.Context, userID string) (int, error) {
 	query := `SELECT xp FROM users WHERE id = $1`
@@ -196,3 +457,210 @@ func (s *XPStore) GetUserTotalXP(ctx context.Context, userID string) (int, error
 
 	return xp, nil
 }
+
+// XPAnomaly is a user who earned an unusually large amount of XP within a time window
+type XPAnomaly struct {
+	UserID           string         `json:"user_id"`
+	UserName         string         `json:"user_name"`
+	TotalXPInWindow  int            `json:"total_xp_in_window"`
+	TransactionCount int            `json:"transaction_count"`
+	Sources          []string       `json:"sources"`
+	XPBySource       map[string]int `json:"xp_by_source"`
+}
+
+// applyDailyXPCap returns how much of req.XP the user can still earn today from req.Source.
+// The user row is locked first so concurrent awards can't both slip under the cap.
+func applyDailyXPCap(ctx context.Context, tx *sql.Tx, req AwardXPRequest, xpCap int) (int, error) {
+	var locked string
+	err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, req.UserID).Scan(&locked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		return 0, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	var earnedToday int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(xp), 0) FROM xp_logs
+		WHERE user_id = $1 AND source = $2 AND created_at >= CURRENT_DATE
+	`, req.UserID, string(req.Source)).Scan(&earnedToday)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get XP earned today: %w", err)
+	}
+
+	remaining := xpCap - earnedToday
+	if remaining >= req.XP {
+		return req.XP, nil
+	}
+	log.Printf("[XP] XPCapReached user_id=%s source=%s cap=%d earned_today=%d requested=%d awarded=%d",
+		req.UserID, req.Source, xpCap, earnedToday, req.XP, max(remaining, 0))
+	if remaining <= 0 {
+		return 0, ErrDailyCapExceeded
+	}
+	return remaining, nil
+}
+
+// GetXPAnomalies returns users who earned more than threshold XP within the last window,
+// with their XP broken down by source, sorted by total XP in the window (highest first)
+func (s *XPStore) GetXPAnomalies(ctx context.Context, threshold int, window time.Duration) ([]XPAnomaly, error) {
+	query := `
+		WITH window_xp AS (
+			SELECT user_id, source, SUM(xp) AS xp, COUNT(*) AS transactions
+			FROM xp_logs
+			WHERE created_at >= $2
+			GROUP BY user_id, source
+		),
+		flagged AS (
+			SELECT user_id, SUM(xp) AS total_xp, SUM(transactions) AS transactions
+			FROM window_xp
+			GROUP BY user_id
+			HAVING SUM(xp) > $1
+		)
+		SELECT f.user_id, u.name, f.total_xp, f.transactions, w.source, w.xp
+		FROM flagged f
+		JOIN users u ON u.id = f.user_id
+		JOIN window_xp w ON w.user_id = f.user_id
+		ORDER BY f.total_xp DESC, f.user_id, w.xp DESC
+	`
+
+	since := time.Now().Add(-window)
+	rows, err := s.postgres.DB.QueryContext(ctx, query, threshold, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query XP anomalies: %w", err)
+	}
+	defer rows.Close()
+
+	// Rows arrive grouped by user (one row per source), so fold them into one anomaly per user
+	anomalies := []XPAnomaly{}
+	for rows.Next() {
+		var userID, userName, source string
+		var totalXP, transactions, sourceXP int
+		if err := rows.Scan(&userID, &userName, &totalXP, &transactions, &source, &sourceXP); err != nil {
+			return nil, fmt.Errorf("failed to scan XP anomaly: %w", err)
+		}
+
+		if len(anomalies) == 0 || anomalies[len(anomalies)-1].UserID != userID {
+			anomalies = append(anomalies, XPAnomaly{
+				UserID:           userID,
+				UserName:         userName,
+				TotalXPInWindow:  totalXP,
+				TransactionCount: transactions,
+				Sources:          []string{},
+				XPBySource:       map[string]int{},
+			})
+		}
+		anomaly := &anomalies[len(anomalies)-1]
+		anomaly.Sources = append(anomaly.Sources, source)
+		anomaly.XPBySource[source] = sourceXP
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating XP anomaly rows: %w", err)
+	}
+
+	return anomalies, nil
+}
+
+// XPLogFilter holds optional filters for listing XP logs across all users.
+// From/To bound created_at (inclusive/exclusive). PageSize 0 means no limit.
+type XPLogFilter struct {
+	UserID   string
+	Source   string
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PageSize int
+}
+
+// XPLogWithUser is an XP log entry with the name and email of the user who received it
+type XPLogWithUser struct {
+	XPLog
+	UserName  string `json:"user_name"`
+	UserEmail string `json:"user_email"`
+}
+
+// GetAllXPLogs retrieves one page of XP logs matching filter, newest first
+func (s *XPStore) GetAllXPLogs(ctx context.Context, filter XPLogFilter) ([]XPLogWithUser, error) {
+	logs := []XPLogWithUser{}
+	err := s.StreamXPLogs(ctx, filter, func(log XPLogWithUser) error {
+		logs = append(logs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// StreamXPLogs calls fn for each XP log matching filter, newest first, scanning rows one at a
+// time so large exports are never held in memory. Iteration stops at the first error from fn.
+func (s *XPStore) StreamXPLogs(ctx context.Context, filter XPLogFilter, fn func(XPLogWithUser) error) error {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.UserID != "" {
+		addCondition("xl.user_id = $%d", filter.UserID)
+	}
+	if filter.Source != "" {
+		addCondition("xl.source = $%d", filter.Source)
+	}
+	if filter.From != nil {
+		addCondition("xl.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("xl.created_at < $%d", *filter.To)
+	}
+
+	query := `
+		SELECT xl.id, xl.user_id, xl.source, xl.source_id, xl.reason, xl.xp, xl.created_at, u.name, u.email
+		FROM xp_logs xl
+		INNER JOIN users u ON u.id = xl.user_id
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY xl.created_at DESC, xl.id DESC"
+	if filter.PageSize > 0 {
+		page := filter.Page
+		if page < 1 {
+			page = 1
+		}
+		args = append(args, filter.PageSize, (page-1)*filter.PageSize)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := s.postgres.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query XP logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var log XPLogWithUser
+		var sourceID, reason sql.NullString
+
+		err := rows.Scan(
+			&log.ID, &log.UserID, &log.Source, &sourceID, &reason, &log.XP, &log.CreatedAt,
+			&log.UserName, &log.UserEmail,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan XP log: %w", err)
+		}
+		log.SourceID = sourceID.String
+		log.Reason = reason.String
+
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating XP log rows: %w", err)
+	}
+
+	return nil
+}
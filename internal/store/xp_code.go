@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// XPCode is a promo code users can redeem for a fixed amount of XP
+type XPCode struct {
+	ID        string     `json:"id"`
+	Code      string     `json:"code"`
+	XPAmount  int        `json:"xp_amount"`
+	MaxUses   int        `json:"max_uses"`
+	UsesCount int        `json:"uses_count"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type XPCodeStore struct {
+	postgres *db.Postgres
+}
+
+func NewXPCodeStore(postgres *db.Postgres) *XPCodeStore {
+	return &XPCodeStore{
+		postgres: postgres,
+	}
+}
+
+// RedeemXPCode validates a code for a user and consumes one use of it.
+// A user can redeem each code only once. The caller awards the returned XPAmount.
+func (s *XPCodeStore) RedeemXPCode(ctx context.Context, code, userID string) (*XPCode, error) {
+	tx, err := s.postgres.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the code row so concurrent redemptions cannot exceed max_uses
+	query := `
+		SELECT id, code, xp_amount, max_uses, uses_count, expires_at, created_at
+		FROM xp_codes
+		WHERE code = $1
+		FOR UPDATE
+	`
+	var xpCode XPCode
+	var expiresAt sql.NullTime
+	err = tx.QueryRowContext(ctx, query, code).Scan(
+		&xpCode.ID, &xpCode.Code, &xpCode.XPAmount, &xpCode.MaxUses, &xpCode.UsesCount, &expiresAt, &xpCode.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid code")
+		}
+		return nil, fmt.Errorf("failed to get XP code: %w", err)
+	}
+
+	if expiresAt.Valid {
+		xpCode.ExpiresAt = &expiresAt.Time
+		if expiresAt.Time.Before(time.Now()) {
+			return nil, fmt.Errorf("code expired")
+		}
+	}
+	if xpCode.UsesCount >= xpCode.MaxUses {
+		return nil, fmt.Errorf("code fully redeemed")
+	}
+
+	// Check if this user already redeemed the code
+	var redeemed bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM xp_logs WHERE user_id = $1 AND source = $2 AND source_id = $3)`
+	err = tx.QueryRowContext(ctx, checkQuery, userID, string(XPSourceUserAdd), xpCode.ID).Scan(&redeemed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check code redemption: %w", err)
+	}
+	if redeemed {
+		return nil, fmt.Errorf("code already redeemed")
+	}
+
+	updateQuery := `UPDATE xp_codes SET uses_count = uses_count + 1 WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, updateQuery, xpCode.ID); err != nil {
+		return nil, fmt.Errorf("failed to update XP code: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	xpCode.UsesCount++
+	return &xpCode, nil
+}
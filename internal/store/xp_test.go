@@ -0,0 +1,225 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+	"github.com/rohit21755/groveserverv2/internal/testutil"
+)
+
+func TestXPStoreGetXPSummary(t *testing.T) {
+	summaryColumns := []string{"xp", "level", "xp_this_week", "xp_this_month"}
+	tests := []struct {
+		name    string
+		row     []any // nil for an unknown user
+		want    store.XPSummary
+		wantErr string
+	}{
+		{name: "week and month", row: []any{int64(1200), int64(4), int64(150), int64(600)}, want: store.XPSummary{TotalXP: 1200, Level: 4, XPThisWeek: 150, XPThisMonth: 600}},
+		{name: "deductions are netted", row: []any{int64(50), int64(1), int64(-30), int64(-10)}, want: store.XPSummary{TotalXP: 50, Level: 1, XPThisWeek: -30, XPThisMonth: -10}},
+		{name: "unknown user", wantErr: "user not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			var rows [][]any
+			if tt.row != nil {
+				rows = append(rows, tt.row)
+			}
+			// One query: both windows filter the same join, which reaches back to whichever starts first
+			mockDB.ExpectQuery(`SUM\(l.xp\) FILTER \(WHERE l.created_at >= DATE_TRUNC\('week', NOW\(\)\)\), 0\),\s+`+
+				`COALESCE\(SUM\(l.xp\) FILTER \(WHERE l.created_at >= DATE_TRUNC\('month', NOW\(\)\)\), 0\)\s+`+
+				`FROM users u\s+LEFT JOIN xp_logs l ON l.user_id = u.id AND l.created_at >= LEAST\(DATE_TRUNC\('week', NOW\(\)\), DATE_TRUNC\('month', NOW\(\)\)\)`).
+				WithArgs(testutil.TestUserID).
+				WillReturnRows(summaryColumns, rows...)
+
+			summary, err := store.NewXPStore(postgres).GetXPSummary(context.Background(), testutil.TestUserID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetXPSummary: %v", err)
+			}
+			if *summary != tt.want {
+				t.Errorf("summary = %+v, want %+v", *summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestXPStoreGetXPLogsSourceLabel(t *testing.T) {
+	postgres, mockDB := testutil.NewMockPostgres(t)
+	mockDB.ExpectQuery(`FROM xp_logs\s+WHERE user_id = \$1\s+ORDER BY created_at DESC\s+LIMIT \$2`).
+		WithArgs(testutil.TestUserID, 20).
+		WillReturnRows([]string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"},
+			[]any{"1", testutil.TestUserID, string(store.XPSourceTaskApproval), testutil.TestTaskID, nil, int64(50), testutil.TestTime},
+			[]any{"2", testutil.TestUserID, string(store.XPSourceAdminDeduct), nil, "Spam", int64(-20), testutil.TestTime},
+			[]any{"3", testutil.TestUserID, "legacy_source", nil, nil, int64(5), testutil.TestTime})
+
+	logs, err := store.NewXPStore(postgres).GetXPLogs(context.Background(), testutil.TestUserID, 20)
+	if err != nil {
+		t.Fatalf("GetXPLogs: %v", err)
+	}
+	wantLabels := []string{"Task Approval", "Admin Adjustment", "legacy_source"}
+	if len(logs) != len(wantLabels) {
+		t.Fatalf("got %d logs, want %d", len(logs), len(wantLabels))
+	}
+	for i, want := range wantLabels {
+		if logs[i].SourceLabel != want {
+			t.Errorf("log %s source_label = %q, want %q", logs[i].ID, logs[i].SourceLabel, want)
+		}
+	}
+	if logs[1].Reason != "Spam" || logs[1].XP != -20 {
+		t.Errorf("deduction = %+v", logs[1])
+	}
+}
+
+func TestXPStoreAdjustXP(t *testing.T) {
+	logColumns := []string{"id", "user_id", "source", "source_id", "reason", "xp", "created_at"}
+	const reason = "dispute resolution"
+	tests := []struct {
+		name          string
+		delta         int
+		allowNegative bool
+		expect        func(mockDB *testutil.MockPostgres)
+		wantXP        int // logged change
+		wantNewXP     int
+		wantNewLevel  int
+		wantErr       string
+		wantIs        error
+	}{
+		{
+			// admin_grant has a cap configured below, but manual grants are never capped
+			name:  "grant skips the daily cap",
+			delta: 100,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`UPDATE users\s+SET xp = xp \+ \$1\s+WHERE id = \$2`).
+					WithArgs(100, testutil.TestUserID).
+					WillReturnRows([]string{"xp", "level"}, []any{int64(600), int64(2)})
+				mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+					WithArgs(testutil.AnyArg(), testutil.TestUserID, "admin_grant", testutil.TestAdminID, reason, 100).
+					WillReturnRows(logColumns, []any{"log-1", testutil.TestUserID, "admin_grant", testutil.TestAdminID, reason, int64(100), testutil.TestTime})
+				mockDB.ExpectQuery(`SELECT level FROM levels`).
+					WithArgs(600).
+					WillReturnRows([]string{"level"}, []any{int64(2)})
+				mockDB.ExpectCommit()
+				mockDB.ExpectQuery(`FROM badges b`).
+					WithArgs(600, 2, testutil.TestUserID).
+					WillReturnRows([]string{"id", "xp", "required_level"})
+			},
+			wantXP: 100, wantNewXP: 600, wantNewLevel: 2,
+		},
+		{
+			name:  "deduction within the user's XP",
+			delta: -100,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`SELECT xp, level FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"xp", "level"}, []any{int64(500), int64(2)})
+				mockDB.ExpectExec(`UPDATE users SET xp = \$1 WHERE id = \$2`).
+					WithArgs(400, testutil.TestUserID).
+					WillReturnResult(1)
+				mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+					WithArgs(testutil.AnyArg(), testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, -100).
+					WillReturnRows(logColumns, []any{"log-2", testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, int64(-100), testutil.TestTime})
+				mockDB.ExpectQuery(`SELECT level FROM levels`).
+					WithArgs(400).
+					WillReturnRows([]string{"level"}, []any{int64(2)})
+				mockDB.ExpectCommit()
+			},
+			wantXP: -100, wantNewXP: 400, wantNewLevel: 2,
+		},
+		{
+			name:  "deduction below 0 rejected",
+			delta: -100,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				// Checked against the locked row, so a concurrent award can't slip between check and update
+				mockDB.ExpectQuery(`SELECT xp, level FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"xp", "level"}, []any{int64(40), int64(2)})
+				mockDB.ExpectRollback()
+			},
+			wantErr: "deduction exceeds the user's XP: user has 40 XP",
+			wantIs:  store.ErrInsufficientXP,
+		},
+		{
+			name:          "deduction below 0 floors at 0 when allowed",
+			delta:         -100,
+			allowNegative: true,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`SELECT xp, level FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"xp", "level"}, []any{int64(40), int64(2)})
+				mockDB.ExpectExec(`UPDATE users SET xp = \$1 WHERE id = \$2`).
+					WithArgs(0, testutil.TestUserID).
+					WillReturnResult(1)
+				// Only what the user had is logged as removed
+				mockDB.ExpectQuery(`INSERT INTO xp_logs`).
+					WithArgs(testutil.AnyArg(), testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, -40).
+					WillReturnRows(logColumns, []any{"log-3", testutil.TestUserID, "admin_deduct", testutil.TestAdminID, reason, int64(-40), testutil.TestTime})
+				mockDB.ExpectQuery(`SELECT level FROM levels`).
+					WithArgs(0).
+					WillReturnRows([]string{"level"}, []any{int64(1)})
+				mockDB.ExpectExec(`UPDATE users SET level = \$1 WHERE id = \$2`).
+					WithArgs(1, testutil.TestUserID).
+					WillReturnResult(1)
+				mockDB.ExpectCommit()
+			},
+			wantXP: -40, wantNewXP: 0, wantNewLevel: 1,
+		},
+		{
+			name:  "unknown user",
+			delta: -100,
+			expect: func(mockDB *testutil.MockPostgres) {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(`SELECT xp, level FROM users WHERE id = \$1 FOR UPDATE`).
+					WithArgs(testutil.TestUserID).
+					WillReturnRows([]string{"xp", "level"})
+				mockDB.ExpectRollback()
+			},
+			wantErr: "user not found",
+		},
+		{name: "zero", expect: func(*testutil.MockPostgres) {}, wantErr: "XP amount must not be 0"},
+	}
+
+	store.SetDailyXPCaps(map[store.XPSource]int{store.XPSourceAdminGrant: 10})
+	t.Cleanup(func() { store.SetDailyXPCaps(nil) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres, mockDB := testutil.NewMockPostgres(t)
+			tt.expect(mockDB)
+
+			xpLog, err := store.NewXPStore(postgres).AdjustXP(context.Background(), testutil.TestAdminID, testutil.TestUserID, tt.delta, reason, tt.allowNegative)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+					t.Errorf("err = %v, want it to wrap %v", err, tt.wantIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AdjustXP: %v", err)
+			}
+			// The audit trail: who changed the XP, why, and by how much
+			if xpLog.XP != tt.wantXP || xpLog.SourceID != testutil.TestAdminID || xpLog.Reason != reason {
+				t.Errorf("xp log = %+v, want %d XP by the admin with the reason", xpLog, tt.wantXP)
+			}
+			if xpLog.NewXP != tt.wantNewXP || xpLog.NewLevel != tt.wantNewLevel {
+				t.Errorf("new XP, level = %d, %d; want %d, %d", xpLog.NewXP, xpLog.NewLevel, tt.wantNewXP, tt.wantNewLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package templates
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed *.html
+var files embed.FS
+
+// Digest renders the daily notification digest email from a store.NotificationDigest
+var Digest = template.Must(template.ParseFS(files, "digest.html"))
+
+// VerifyEmail renders the email address confirmation email from an email.VerificationEmail
+var VerifyEmail = template.Must(template.ParseFS(files, "verify_email.html"))
+
+// SetPassword renders the email inviting an imported user to choose a password from an email.SetPasswordEmail
+var SetPassword = template.Must(template.ParseFS(files, "set_password.html"))
+
+// ResetPassword renders the forgotten password email from an email.PasswordResetEmail
+var ResetPassword = template.Must(template.ParseFS(files, "reset_password.html"))
@@ -0,0 +1,146 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// Fixed IDs used by the fixtures, so tests can refer to them without reading them back
+const (
+	TestUserID       = "11111111-1111-1111-1111-111111111111"
+	TestTaskID       = "22222222-2222-2222-2222-222222222222"
+	TestSubmissionID = "33333333-3333-3333-3333-333333333333"
+	TestAdminID      = "44444444-4444-4444-4444-444444444444"
+	TestStateID      = "55555555-5555-5555-5555-555555555555"
+	TestCollegeID    = "66666666-6666-6666-6666-666666666666"
+	TestFeedID       = "77777777-7777-7777-7777-777777777777"
+	TestBadgeID      = "88888888-8888-8888-8888-888888888888"
+)
+
+// TestTime is the creation time of every fixture
+var TestTime = time.Date(2025, time.January, 6, 12, 0, 0, 0, time.UTC)
+
+// NewTestUser returns a level 1 student with no XP; overrides are applied in order
+func NewTestUser(overrides ...func(*store.User)) *store.User {
+	user := &store.User{
+		ID:               TestUserID,
+		Name:             "Test User",
+		Email:            "test.user@example.com",
+		StateID:          TestStateID,
+		CollegeID:        TestCollegeID,
+		Role:             "student",
+		Level:            1,
+		ResumeVisibility: "public",
+		ReferralCode:     "TESTCODE",
+		CreatedAt:        TestTime,
+	}
+	for _, override := range overrides {
+		override(user)
+	}
+	return user
+}
+
+// NewTestTask returns an ongoing 100 XP online task with image proof; overrides are applied in order
+func NewTestTask(overrides ...func(*store.Task)) *store.Task {
+	task := &store.Task{
+		ID:          TestTaskID,
+		Title:       "Test Task",
+		Description: "A task for tests",
+		XP:          100,
+		Type:        string(store.TaskTypeOnline),
+		ProofType:   string(store.ProofTypeImage),
+		Priority:    "medium",
+		CreatedBy:   TestAdminID,
+		CreatedAt:   TestTime,
+		Status:      store.TaskStatusOngoing,
+	}
+	for _, override := range overrides {
+		override(task)
+	}
+	return task
+}
+
+// NewTestSubmission returns a pending submission of the test task by the test user; overrides are applied in order
+func NewTestSubmission(overrides ...func(*store.Submission)) *store.Submission {
+	submission := &store.Submission{
+		ID:        TestSubmissionID,
+		TaskID:    TestTaskID,
+		UserID:    TestUserID,
+		ProofURL:  "https://cdn.example.com/task-proofs/proof.jpg",
+		Status:    "pending",
+		CreatedAt: TestTime,
+		UpdatedAt: TestTime,
+	}
+	for _, override := range overrides {
+		override(submission)
+	}
+	return submission
+}
+
+// NewTestAdmin returns an admin with no permission scopes; overrides are applied in order
+func NewTestAdmin(overrides ...func(*store.Admin)) *store.Admin {
+	admin := &store.Admin{
+		ID:        TestAdminID,
+		Name:      "Test Admin",
+		Username:  "testadmin",
+		Role:      "admin",
+		CreatedAt: TestTime,
+		UpdatedAt: TestTime,
+	}
+	for _, override := range overrides {
+		override(admin)
+	}
+	return admin
+}
+
+// NewTestFeedItem returns the feed entry of the test submission; overrides are applied in order
+func NewTestFeedItem(overrides ...func(*store.FeedItem)) *store.FeedItem {
+	item := &store.FeedItem{
+		ID:           TestFeedID,
+		SubmissionID: TestSubmissionID,
+		UserID:       TestUserID,
+		TaskID:       TestTaskID,
+		UserName:     "Test User",
+		TaskTitle:    "Test Task",
+		TaskXP:       100,
+		ProofURL:     "https://cdn.example.com/task-proofs/proof.jpg",
+		CreatedAt:    TestTime,
+	}
+	for _, override := range overrides {
+		override(item)
+	}
+	return item
+}
+
+// NewTestBadge returns a level 1 badge worth no XP; overrides are applied in order
+func NewTestBadge(overrides ...func(*store.Badge)) *store.Badge {
+	badge := &store.Badge{
+		ID:            TestBadgeID,
+		Name:          "Test Badge",
+		RequiredLevel: 1,
+		CreatedAt:     TestTime,
+	}
+	for _, override := range overrides {
+		override(badge)
+	}
+	return badge
+}
+
+// NewTestXPLog returns a 100 XP task approval for the test user; overrides are applied in order
+func NewTestXPLog(overrides ...func(*store.XPLog)) *store.XPLog {
+	xpLog := &store.XPLog{
+		ID:        "99999999-9999-9999-9999-999999999999",
+		UserID:    TestUserID,
+		Source:    string(store.XPSourceTaskApproval),
+		SourceID:  TestSubmissionID,
+		XP:        100,
+		CreatedAt: TestTime,
+		NewXP:     100,
+		NewLevel:  1,
+	}
+	for _, override := range overrides {
+		override(xpLog)
+	}
+	return xpLog
+}
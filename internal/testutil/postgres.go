@@ -0,0 +1,341 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// MockPostgres is a scripted database behind a *db.Postgres. Statements must arrive in the
+// order they were expected; each is matched against a regular expression and, optionally, its
+// arguments. Unmet or unexpected statements fail the test when it finishes.
+type MockPostgres struct {
+	t        testing.TB
+	mu       sync.Mutex
+	expected []*Expectation
+}
+
+// Expectation is one statement MockPostgres expects, built with the Expect methods
+type Expectation struct {
+	kind     string
+	pattern  *regexp.Regexp
+	args     []any
+	checkArg bool
+	columns  []string
+	rows     [][]any
+	affected int64
+	err      error
+	met      bool
+}
+
+// anyArg matches any argument value in WithArgs
+type anyArg struct{}
+
+// AnyArg returns a placeholder for WithArgs that matches any value, e.g. a generated timestamp
+func AnyArg() any {
+	return anyArg{}
+}
+
+// NewMockPostgres returns a *db.Postgres whose statements are answered by the returned MockPostgres
+func NewMockPostgres(t testing.TB) (*db.Postgres, *MockPostgres) {
+	t.Helper()
+	m := &MockPostgres{t: t}
+	sqlDB := sql.OpenDB(&mockConnector{m: m})
+	// One connection keeps statements in the order the code under test issued them
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return &db.Postgres{DB: sqlDB, Traced: db.NewTracedPostgres(sqlDB)}, m
+}
+
+// ExpectQuery expects a statement returning rows whose SQL matches pattern
+func (m *MockPostgres) ExpectQuery(pattern string) *Expectation {
+	return m.expect("query", pattern)
+}
+
+// ExpectExec expects a statement not returning rows whose SQL matches pattern
+func (m *MockPostgres) ExpectExec(pattern string) *Expectation {
+	return m.expect("exec", pattern)
+}
+
+// ExpectBegin expects a transaction to be started
+func (m *MockPostgres) ExpectBegin() *Expectation {
+	return m.expect("begin", "")
+}
+
+// ExpectCommit expects the open transaction to be committed
+func (m *MockPostgres) ExpectCommit() *Expectation {
+	return m.expect("commit", "")
+}
+
+// ExpectRollback expects the open transaction to be rolled back
+func (m *MockPostgres) ExpectRollback() *Expectation {
+	return m.expect("rollback", "")
+}
+
+// ExpectPing expects the database to be pinged
+func (m *MockPostgres) ExpectPing() *Expectation {
+	return m.expect("ping", "")
+}
+
+func (m *MockPostgres) expect(kind, pattern string) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &Expectation{kind: kind}
+	if pattern != "" {
+		e.pattern = regexp.MustCompile(pattern)
+	}
+	m.expected = append(m.expected, e)
+	return e
+}
+
+// WithArgs requires the statement to be called with exactly these arguments.
+// Integers compare by value whatever their size; use AnyArg for values the test cannot know.
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.checkArg = true
+	return e
+}
+
+// WillReturnRows answers a query with rows, each holding one value per column
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns = columns
+	e.rows = rows
+	return e
+}
+
+// WillReturnResult answers an exec with the number of rows it affected
+func (e *Expectation) WillReturnResult(rowsAffected int64) *Expectation {
+	e.affected = rowsAffected
+	return e
+}
+
+// WillReturnError makes the statement fail with err
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// ExpectationsWereMet returns an error naming the first expectation that was not used
+func (m *MockPostgres) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.expected {
+		if !e.met {
+			return fmt.Errorf("testutil: expected %s was not run", e)
+		}
+	}
+	return nil
+}
+
+func (e *Expectation) String() string {
+	if e.pattern == nil {
+		return e.kind
+	}
+	return fmt.Sprintf("%s matching %q", e.kind, e.pattern.String())
+}
+
+// next consumes the next expectation, failing the test if it does not match the statement
+func (m *MockPostgres) next(kind, query string, args []driver.NamedValue) (*Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.expected {
+		if e.met {
+			continue
+		}
+		if err := e.match(kind, query, args); err != nil {
+			m.t.Errorf("testutil: %v", err)
+			return nil, err
+		}
+		e.met = true
+		return e, e.err
+	}
+	err := fmt.Errorf("unexpected %s %q", kind, strings.Join(strings.Fields(query), " "))
+	m.t.Errorf("testutil: %v", err)
+	return nil, err
+}
+
+func (e *Expectation) match(kind, query string, args []driver.NamedValue) error {
+	if e.kind != kind {
+		return fmt.Errorf("got %s %q, expected %s", kind, strings.Join(strings.Fields(query), " "), e)
+	}
+	if e.pattern != nil && !e.pattern.MatchString(query) {
+		return fmt.Errorf("%s %q does not match %q", kind, strings.Join(strings.Fields(query), " "), e.pattern.String())
+	}
+	if !e.checkArg {
+		return nil
+	}
+	if len(args) != len(e.args) {
+		return fmt.Errorf("%s got %d args, expected %d", e, len(args), len(e.args))
+	}
+	for i, want := range e.args {
+		if _, ok := want.(anyArg); ok {
+			continue
+		}
+		if got := args[i].Value; !reflect.DeepEqual(normalize(got), normalize(want)) {
+			return fmt.Errorf("%s arg %d is %#v, expected %#v", e, i+1, got, want)
+		}
+	}
+	return nil
+}
+
+// normalize maps the many Go integer and pointer forms onto one so WithArgs(1) matches an int64 argument
+func normalize(v any) any {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			v = value
+		}
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	}
+	return v
+}
+
+type mockConnector struct {
+	m *MockPostgres
+}
+
+func (c *mockConnector) Connect(context.Context) (driver.Conn, error) {
+	return &mockConn{m: c.m}, nil
+}
+
+func (c *mockConnector) Driver() driver.Driver {
+	return mockDriver{}
+}
+
+type mockDriver struct{}
+
+func (mockDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("testutil: use NewMockPostgres")
+}
+
+type mockConn struct {
+	m *MockPostgres
+}
+
+func (c *mockConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("testutil: prepared statements are not supported")
+}
+
+func (c *mockConn) Close() error {
+	return nil
+}
+
+func (c *mockConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *mockConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if _, err := c.m.next("begin", "", nil); err != nil {
+		return nil, err
+	}
+	return &mockTx{m: c.m}, nil
+}
+
+func (c *mockConn) Ping(ctx context.Context) error {
+	_, err := c.m.next("ping", "", nil)
+	return err
+}
+
+// CheckNamedValue passes arguments through unchanged, as pgx accepts slices and other non-driver types
+func (c *mockConn) CheckNamedValue(nv *driver.NamedValue) error {
+	return nil
+}
+
+func (c *mockConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.m.next("query", query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &mockRows{columns: e.columns, rows: e.rows}, nil
+}
+
+func (c *mockConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.m.next("exec", query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(e.affected), nil
+}
+
+type mockTx struct {
+	m *MockPostgres
+}
+
+func (tx *mockTx) Commit() error {
+	_, err := tx.m.next("commit", "", nil)
+	return err
+}
+
+func (tx *mockTx) Rollback() error {
+	_, err := tx.m.next("rollback", "", nil)
+	return err
+}
+
+type mockRows struct {
+	columns []string
+	rows    [][]any
+	pos     int
+}
+
+func (r *mockRows) Columns() []string {
+	return r.columns
+}
+
+func (r *mockRows) Close() error {
+	return nil
+}
+
+func (r *mockRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	row := r.rows[r.pos]
+	r.pos++
+	for i := range dest {
+		dest[i] = driverValue(row[i])
+	}
+	return nil
+}
+
+// driverValue converts a row value given by a test into one database/sql can scan from
+func driverValue(v any) driver.Value {
+	switch v := v.(type) {
+	case nil, int64, float64, bool, []byte, string, time.Time:
+		return v
+	case []string:
+		// Postgres text array literal, which pgtype scanners and string targets accept
+		return "{" + strings.Join(v, ",") + "}"
+	}
+	if value, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		return value
+	}
+	return fmt.Sprint(v)
+}
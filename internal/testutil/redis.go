@@ -0,0 +1,22 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+)
+
+// NewMockRedis returns a *db.Redis backed by an in-memory server, which the test can inspect
+// or fast-forward through the returned *miniredis.Miniredis. Both are closed when the test ends.
+func NewMockRedis(t testing.TB) (*db.Redis, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return &db.Redis{Client: client}, server
+}
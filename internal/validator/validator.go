@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// Maximum lengths for user-supplied text, in characters (not bytes)
+const (
+	MaxTaskTitleLength       = 200
+	MaxTaskDescriptionLength = 5000
+	MinNameLength            = 2
+	MaxNameLength            = 100
+	MaxEmailLength           = 254
+	MaxBioLength             = 500
+	MinPhoneLength           = 10
+	MaxPhoneLength           = 15
+)
+
+// ValidationError describes a single invalid field
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// maxLength returns a ValidationError when value is longer than max characters
+func maxLength(field, value string, max int) error {
+	if utf8.RuneCountInString(value) > max {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at most %d characters", max)}
+	}
+	return nil
+}
+
+// ValidateTask checks the length of a task's text fields. Required fields are checked by the handlers.
+func ValidateTask(req store.CreateTaskRequest) error {
+	if err := maxLength("title", req.Title, MaxTaskTitleLength); err != nil {
+		return err
+	}
+	return maxLength("description", req.Description, MaxTaskDescriptionLength)
+}
+
+// ValidateTaskXP checks that a task's XP reward is between 1 and maxXP
+func ValidateTaskXP(xp, maxXP int) error {
+	if xp < 1 || xp > maxXP {
+		return &ValidationError{Field: "xp", Message: fmt.Sprintf("must be between 1 and %d", maxXP)}
+	}
+	return nil
+}
+
+// ValidateTaskEndAt checks that a newly set end_at is in the future
+func ValidateTaskEndAt(endAt *time.Time) error {
+	if endAt != nil && !endAt.After(time.Now()) {
+		return &ValidationError{Field: "end_at", Message: "must be in the future"}
+	}
+	return nil
+}
+
+// ValidateTaskSchedule checks that start_at is before end_at when both are set
+func ValidateTaskSchedule(startAt, endAt *time.Time) error {
+	if startAt != nil && endAt != nil && !startAt.Before(*endAt) {
+		return &ValidationError{Field: "start_at", Message: "must be before end_at"}
+	}
+	return nil
+}
+
+// ValidateUser checks the length of a registration's name and the format and length of its email
+func ValidateUser(req store.RegisterRequest) error {
+	if err := maxLength("name", req.Name, MaxNameLength); err != nil {
+		return err
+	}
+	if err := maxLength("email", req.Email, MaxEmailLength); err != nil {
+		return err
+	}
+	if req.Email != "" {
+		addr, err := mail.ParseAddress(req.Email)
+		if err != nil || addr.Address != req.Email {
+			return &ValidationError{Field: "email", Message: "must be a valid email address"}
+		}
+	}
+	return nil
+}
+
+// ValidateName checks that a user's name is between MinNameLength and MaxNameLength characters
+func ValidateName(name string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(name)) < MinNameLength {
+		return &ValidationError{Field: "name", Message: fmt.Sprintf("must be at least %d characters", MinNameLength)}
+	}
+	return maxLength("name", name, MaxNameLength)
+}
+
+// ValidateProfile checks the length of a user's bio and that the phone number, when set,
+// is MinPhoneLength to MaxPhoneLength digits
+func ValidateProfile(bio, phone string) error {
+	if err := maxLength("bio", bio, MaxBioLength); err != nil {
+		return err
+	}
+	if phone == "" {
+		return nil
+	}
+	if len(phone) < MinPhoneLength || len(phone) > MaxPhoneLength || strings.Trim(phone, "0123456789") != "" {
+		return &ValidationError{Field: "phone", Message: fmt.Sprintf("must be %d to %d digits", MinPhoneLength, MaxPhoneLength)}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// wantField checks err is a *ValidationError for field, or nil when field is empty
+func wantField(t *testing.T, err error, field string) {
+	t.Helper()
+	if field == "" {
+		if err != nil {
+			t.Errorf("err = %v, want nil", err)
+		}
+		return
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a *ValidationError for %s", err, field)
+	}
+	if validationErr.Field != field {
+		t.Errorf("field = %q, want %q", validationErr.Field, field)
+	}
+}
+
+func TestValidateTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       store.CreateTaskRequest
+		wantField string
+	}{
+		{name: "empty", req: store.CreateTaskRequest{}},
+		{name: "title at limit", req: store.CreateTaskRequest{Title: strings.Repeat("a", MaxTaskTitleLength)}},
+		{name: "title over limit", req: store.CreateTaskRequest{Title: strings.Repeat("a", MaxTaskTitleLength+1)}, wantField: "title"},
+		// 600 bytes but 200 characters
+		{name: "multibyte title at limit", req: store.CreateTaskRequest{Title: strings.Repeat("日", MaxTaskTitleLength)}},
+		{name: "multibyte title over limit", req: store.CreateTaskRequest{Title: strings.Repeat("日", MaxTaskTitleLength+1)}, wantField: "title"},
+		{name: "description at limit", req: store.CreateTaskRequest{Description: strings.Repeat("a", MaxTaskDescriptionLength)}},
+		{name: "description over limit", req: store.CreateTaskRequest{Description: strings.Repeat("a", MaxTaskDescriptionLength+1)}, wantField: "description"},
+		{name: "emoji description at limit", req: store.CreateTaskRequest{Description: strings.Repeat("😀", MaxTaskDescriptionLength)}},
+		{name: "emoji description over limit", req: store.CreateTaskRequest{Description: strings.Repeat("😀", MaxTaskDescriptionLength+1)}, wantField: "description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateTask(tt.req), tt.wantField)
+		})
+	}
+}
+
+func TestValidateTaskXP(t *testing.T) {
+	tests := []struct {
+		name      string
+		xp        int
+		wantField string
+	}{
+		{name: "minimum", xp: 1},
+		{name: "maximum", xp: 1000},
+		{name: "zero", xp: 0, wantField: "xp"},
+		{name: "negative", xp: -5, wantField: "xp"},
+		{name: "over maximum", xp: 1001, wantField: "xp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateTaskXP(tt.xp, 1000), tt.wantField)
+		})
+	}
+}
+
+func TestValidateTaskEndAt(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name      string
+		endAt     *time.Time
+		wantField string
+	}{
+		{name: "unset", endAt: nil},
+		{name: "future", endAt: &future},
+		{name: "past", endAt: &past, wantField: "end_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateTaskEndAt(tt.endAt), tt.wantField)
+		})
+	}
+}
+
+func TestValidateTaskSchedule(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	tests := []struct {
+		name      string
+		startAt   *time.Time
+		endAt     *time.Time
+		wantField string
+	}{
+		{name: "both unset"},
+		{name: "only start", startAt: &start},
+		{name: "only end", endAt: &end},
+		{name: "start before end", startAt: &start, endAt: &end},
+		{name: "start equals end", startAt: &start, endAt: &start, wantField: "start_at"},
+		{name: "start after end", startAt: &end, endAt: &start, wantField: "start_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateTaskSchedule(tt.startAt, tt.endAt), tt.wantField)
+		})
+	}
+}
+
+func TestValidateUser(t *testing.T) {
+	// local@domain exactly MaxEmailLength characters long
+	longEmail := strings.Repeat("a", 64) + "@" + strings.Repeat("b", MaxEmailLength-64-1-4) + ".com"
+	tests := []struct {
+		name      string
+		req       store.RegisterRequest
+		wantField string
+	}{
+		{name: "valid", req: store.RegisterRequest{Name: "Test User", Email: "test.user@example.com"}},
+		{name: "name at limit", req: store.RegisterRequest{Name: strings.Repeat("a", MaxNameLength), Email: "a@example.com"}},
+		{name: "name over limit", req: store.RegisterRequest{Name: strings.Repeat("a", MaxNameLength+1), Email: "a@example.com"}, wantField: "name"},
+		{name: "multibyte name at limit", req: store.RegisterRequest{Name: strings.Repeat("é", MaxNameLength), Email: "a@example.com"}},
+		{name: "multibyte name over limit", req: store.RegisterRequest{Name: strings.Repeat("é", MaxNameLength+1), Email: "a@example.com"}, wantField: "name"},
+		{name: "email at limit", req: store.RegisterRequest{Name: "Test User", Email: longEmail}},
+		{name: "email over limit", req: store.RegisterRequest{Name: "Test User", Email: "a" + longEmail}, wantField: "email"},
+		{name: "email without domain", req: store.RegisterRequest{Name: "Test User", Email: "test.user"}, wantField: "email"},
+		{name: "email with display name", req: store.RegisterRequest{Name: "Test User", Email: "Test <test.user@example.com>"}, wantField: "email"},
+		{name: "email left to required check", req: store.RegisterRequest{Name: "Test User"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateUser(tt.req), tt.wantField)
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantField string
+	}{
+		{name: "minimum", value: "Al"},
+		{name: "too short", value: "A", wantField: "name"},
+		{name: "padded too short", value: "  A  ", wantField: "name"},
+		{name: "multibyte minimum", value: "李明"},
+		{name: "maximum", value: strings.Repeat("a", MaxNameLength)},
+		{name: "over maximum", value: strings.Repeat("a", MaxNameLength+1), wantField: "name"},
+		{name: "multibyte maximum", value: strings.Repeat("李", MaxNameLength)},
+		{name: "multibyte over maximum", value: strings.Repeat("李", MaxNameLength+1), wantField: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateName(tt.value), tt.wantField)
+		})
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		bio       string
+		phone     string
+		wantField string
+	}{
+		{name: "empty"},
+		{name: "bio at limit", bio: strings.Repeat("a", MaxBioLength)},
+		{name: "bio over limit", bio: strings.Repeat("a", MaxBioLength+1), wantField: "bio"},
+		{name: "emoji bio at limit", bio: strings.Repeat("🙂", MaxBioLength)},
+		{name: "emoji bio over limit", bio: strings.Repeat("🙂", MaxBioLength+1), wantField: "bio"},
+		{name: "phone minimum", phone: strings.Repeat("9", MinPhoneLength)},
+		{name: "phone maximum", phone: strings.Repeat("9", MaxPhoneLength)},
+		{name: "phone too short", phone: strings.Repeat("9", MinPhoneLength-1), wantField: "phone"},
+		{name: "phone too long", phone: strings.Repeat("9", MaxPhoneLength+1), wantField: "phone"},
+		{name: "phone with plus", phone: "+919876543210", wantField: "phone"},
+		// Arabic-Indic digits are digits, but not the ASCII ones a phone number is stored as
+		{name: "phone with non-ASCII digits", phone: strings.Repeat("٩", MinPhoneLength), wantField: "phone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantField(t, ValidateProfile(tt.bio, tt.phone), tt.wantField)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rohit21755/groveserverv2/internal/db"
+	"github.com/rohit21755/groveserverv2/internal/router/ws"
+	"github.com/rohit21755/groveserverv2/internal/store"
+)
+
+// xpAwardTimeout bounds the DB work done for a single queued award
+const xpAwardTimeout = 10 * time.Second
+
+// AwardXPRequest is a queued XP award. When TaskID is set, a task approval
+// notification is sent to the user once the award has been processed.
+type AwardXPRequest struct {
+	store.AwardXPRequest
+	TaskID    string
+	TaskTitle string
+}
+
+// XPWorker awards XP in the background so request handlers don't wait on
+// the XP transaction, badge checks and leaderboard broadcasts
+type XPWorker struct {
+	postgres    *db.Postgres
+	redisClient *db.Redis
+	jobs        chan AwardXPRequest
+	done        chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewXPWorker creates an XP worker with a queue of the given size
+func NewXPWorker(postgres *db.Postgres, redisClient *db.Redis, queueSize int) *XPWorker {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &XPWorker{
+		postgres:    postgres,
+		redisClient: redisClient,
+		jobs:        make(chan AwardXPRequest, queueSize),
+		done:        make(chan struct{}),
+	}
+}
+
+// Run processes queued awards sequentially until Shutdown is called
+func (w *XPWorker) Run() {
+	defer close(w.done)
+	for req := range w.jobs {
+		w.process(req)
+	}
+}
+
+// Submit queues an award. If the queue is full or the worker is shutting down,
+// the award is processed synchronously on the calling goroutine instead.
+func (w *XPWorker) Submit(req AwardXPRequest) {
+	w.mu.RLock()
+	if !w.closed {
+		select {
+		case w.jobs <- req:
+			w.mu.RUnlock()
+			return
+		default:
+		}
+	}
+	w.mu.RUnlock()
+
+	log.Printf("XP worker queue unavailable, awarding XP synchronously for user %s", req.UserID)
+	w.process(req)
+}
+
+// Shutdown stops accepting awards and waits for the queue to drain until ctx is done
+func (w *XPWorker) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.jobs)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		log.Printf("XP worker shutdown deadline reached with %d awards still queued", len(w.jobs))
+		return ctx.Err()
+	}
+}
+
+// process awards XP, broadcasts leaderboard updates and sends notifications for one request
+func (w *XPWorker) process(req AwardXPRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), xpAwardTimeout)
+	defer cancel()
+
+	xpAwarded := 0
+	if req.XP > 0 {
+		xpStore := store.NewXPStore(w.postgres)
+		xpLog, err := xpStore.AwardXP(ctx, req.AwardXPRequest)
+		if err != nil {
+			log.Printf("Error awarding XP to user %s (source: %s, source_id: %s): %v", req.UserID, req.Source, req.SourceID, err)
+		} else {
+			xpAwarded = req.XP
+			log.Printf("Awarded %d XP to user %s (source: %s, source_id: %s, xp_log_id: %s)",
+				req.XP, req.UserID, req.Source, req.SourceID, xpLog.ID)
+
+			if xpLog.LeveledUp {
+				if wsHub := ws.GetNotificationHub(); wsHub != nil {
+					if err := ws.SendLevelUpNotification(wsHub, xpLog.UserID, xpLog.NewLevel); err != nil {
+						log.Printf("Error sending level up notification: %v", err)
+					}
+				}
+			}
+
+			// Tell the user's leaderboards to refresh
+			userStore := store.NewUserStore(w.postgres)
+			user, err := userStore.GetUserByID(ctx, req.UserID)
+			if err == nil {
+				ws.BroadcastUserXPChange(ctx, w.redisClient, user)
+			}
+		}
+	}
+
+	if req.TaskID == "" {
+		return
+	}
+	if wsHub := ws.GetNotificationHub(); wsHub != nil {
+		if err := ws.SendTaskApprovalNotification(wsHub, req.UserID, req.TaskID, req.TaskTitle, xpAwarded); err != nil {
+			log.Printf("Error sending task approval notification: %v", err)
+		} else {
+			log.Printf("Sent task approval notification to user %s for task %s", req.UserID, req.TaskID)
+		}
+	}
+}
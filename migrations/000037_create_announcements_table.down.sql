@@ -0,0 +1,2 @@
+-- Drop announcements table
+DROP TABLE IF EXISTS announcements;
@@ -0,0 +1,2 @@
+-- Drop user_blocks table
+DROP TABLE IF EXISTS user_blocks;
@@ -0,0 +1,3 @@
+ALTER TABLE xp_logs DROP COLUMN IF EXISTS reason;
+DROP INDEX IF EXISTS idx_xp_codes_code;
+DROP TABLE IF EXISTS xp_codes;
@@ -0,0 +1,16 @@
+-- Create xp_codes table (promo codes users can redeem for XP)
+CREATE TABLE xp_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(64) UNIQUE NOT NULL,
+    xp_amount INTEGER NOT NULL CHECK (xp_amount > 0),
+    max_uses INTEGER NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    uses_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_xp_codes_code ON xp_codes(code);
+
+-- Free-text reason for XP awards (xp_logs.source_id is a UUID and cannot hold labels)
+ALTER TABLE xp_logs ADD COLUMN IF NOT EXISTS reason TEXT;
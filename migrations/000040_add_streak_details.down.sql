@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_streak_freezes_user_id;
+DROP TABLE IF EXISTS streak_freezes;
+ALTER TABLE users DROP COLUMN IF EXISTS last_checkin_at;
@@ -0,0 +1,13 @@
+-- Track the last daily check-in separately from the streak start
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_checkin_at TIMESTAMPTZ;
+
+-- Create streak_freezes table (a freeze protects a streak from breaking for one missed day)
+CREATE TABLE IF NOT EXISTS streak_freezes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_streak_freezes_user_id ON streak_freezes(user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE levels
+    DROP CONSTRAINT IF EXISTS levels_min_xp_key,
+    DROP CONSTRAINT IF EXISTS levels_min_xp_check,
+    DROP CONSTRAINT IF EXISTS levels_level_check;
@@ -0,0 +1,5 @@
+-- Levels are edited by admins, so keep each level's XP threshold valid and distinct
+ALTER TABLE levels
+    ADD CONSTRAINT levels_level_check CHECK (level >= 1),
+    ADD CONSTRAINT levels_min_xp_check CHECK (min_xp >= 0),
+    ADD CONSTRAINT levels_min_xp_key UNIQUE (min_xp);
@@ -0,0 +1,2 @@
+-- Thumbnail image for video proofs (generated with ffmpeg on submission)
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
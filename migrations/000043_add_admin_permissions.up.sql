@@ -0,0 +1,7 @@
+-- Per-admin permission scopes. New admins get every permission except manage_admins.
+ALTER TABLE admins
+    ADD COLUMN permissions TEXT[] NOT NULL DEFAULT ARRAY['manage_tasks', 'review_submissions', 'manage_users', 'manage_badges'];
+
+-- Existing admins keep full access (including creating admins)
+UPDATE admins
+SET permissions = ARRAY['manage_tasks', 'review_submissions', 'manage_users', 'manage_badges', 'manage_admins'];
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_coin_logs_user_id_created_at;
+DROP TABLE IF EXISTS coin_logs;
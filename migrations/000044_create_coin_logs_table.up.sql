@@ -0,0 +1,12 @@
+-- Create coin_logs table (audit trail of coin balance changes)
+CREATE TABLE coin_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL, -- positive = earned, negative = spent
+    balance_after INTEGER NOT NULL,
+    reason VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX idx_coin_logs_user_id_created_at ON coin_logs(user_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- Create blocked_words table (admin-managed word filter for comments and task descriptions)
+CREATE TABLE blocked_words (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    word VARCHAR(100) UNIQUE NOT NULL,
+    created_by UUID REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_parent_task_id_start_at;
+DROP INDEX IF EXISTS idx_tasks_parent_task_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignment_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignment_type;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_task_id;
@@ -0,0 +1,11 @@
+-- Track weekly task series: each generated instance points at the original task
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_task_id UUID REFERENCES tasks(id) ON DELETE CASCADE;
+
+-- Remember the assignment scope so recurring instances can be assigned the same way
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignment_type VARCHAR(20);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignment_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_task_id ON tasks(parent_task_id);
+
+-- One instance per series per week
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_parent_task_id_start_at ON tasks(parent_task_id, start_at) WHERE parent_task_id IS NOT NULL;
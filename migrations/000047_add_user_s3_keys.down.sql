@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_s3_key;
+ALTER TABLE users DROP COLUMN IF EXISTS resume_s3_key;
@@ -0,0 +1,10 @@
+-- Store raw S3 keys next to public URLs so old files can be deleted reliably
+ALTER TABLE users ADD COLUMN IF NOT EXISTS resume_s3_key TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_s3_key TEXT;
+
+-- Backfill keys from existing URLs (keys are resumes/... and profile-pics/...)
+UPDATE users SET resume_s3_key = substring(resume_url FROM '(resumes/[^/?#]+)')
+WHERE resume_url IS NOT NULL AND resume_s3_key IS NULL;
+
+UPDATE users SET avatar_s3_key = substring(avatar_url FROM '(profile-pics/[^/?#]+)')
+WHERE avatar_url IS NOT NULL AND avatar_s3_key IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE admins
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS totp_secret;
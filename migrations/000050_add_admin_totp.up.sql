@@ -0,0 +1,5 @@
+-- TOTP two-factor authentication for admins. totp_secret is stored as soon as
+-- setup starts; totp_enabled only flips to true once a code has been confirmed.
+ALTER TABLE admins
+    ADD COLUMN totp_secret TEXT,
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,14 @@
+-- Feature flags gate features per user without a deployment.
+-- A flag is on for a user when enabled and either the user is listed in enabled_user_ids
+-- or hash(user_id || flag_name) % 100 falls below rollout_percentage.
+CREATE TABLE feature_flags (
+    flag_name TEXT PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percentage INT NOT NULL DEFAULT 0 CHECK (rollout_percentage BETWEEN 0 AND 100),
+    enabled_user_ids TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Streak check-in is already live for everyone
+INSERT INTO feature_flags (flag_name, enabled, rollout_percentage)
+VALUES ('streak', TRUE, 100);
@@ -0,0 +1,2 @@
+-- Firebase Cloud Messaging registration token of the user's device, for push notifications while offline
+ALTER TABLE users ADD COLUMN fcm_token TEXT;
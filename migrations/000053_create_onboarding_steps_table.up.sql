@@ -0,0 +1,20 @@
+-- Onboarding checklist: one row per completed step per user
+CREATE TABLE onboarding_steps (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    step_name VARCHAR(50) NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, step_name)
+);
+
+-- Backfill steps existing users have already done
+INSERT INTO onboarding_steps (user_id, step_name)
+SELECT id, 'upload_profile_pic' FROM users WHERE avatar_url IS NOT NULL AND avatar_url <> ''
+UNION
+SELECT id, 'upload_resume' FROM users WHERE resume_url IS NOT NULL AND resume_url <> ''
+UNION
+SELECT DISTINCT follower_id, 'follow_first_user' FROM user_follows
+UNION
+SELECT DISTINCT user_id, 'complete_first_task' FROM submissions WHERE status = 'approved'
+UNION
+SELECT DISTINCT user_id, 'earn_first_badge' FROM user_badges
+ON CONFLICT DO NOTHING;
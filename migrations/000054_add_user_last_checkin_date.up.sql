@@ -0,0 +1,5 @@
+-- The calendar day of the last streak check-in; UpdateStreak uses it to update at most once a day
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_checkin_date DATE;
+
+UPDATE users SET last_checkin_date = COALESCE(last_checkin_at, streak_started_at)::date
+WHERE last_checkin_date IS NULL AND (last_checkin_at IS NOT NULL OR streak_started_at IS NOT NULL);
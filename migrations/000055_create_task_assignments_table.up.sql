@@ -0,0 +1,21 @@
+-- Users each task is assigned to (expanded from the task's assignment scope)
+CREATE TABLE task_assignments (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_assignments_user_id ON task_assignments(user_id);
+
+-- Backfill from the scope recorded on existing tasks (tasks without one went to all users)
+INSERT INTO task_assignments (task_id, user_id)
+SELECT t.id, u.id
+FROM tasks t
+JOIN users u ON u.role = 'student' AND (
+    COALESCE(t.assignment_type, 'all') = 'all'
+    OR (t.assignment_type = 'state' AND u.state_id = t.assignment_id)
+    OR (t.assignment_type = 'college' AND u.college_id = t.assignment_id)
+    OR (t.assignment_type = 'user' AND u.id = t.assignment_id)
+)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- Chronological timeline of user actions shown on profile pages
+CREATE TABLE user_activity_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255),
+    entity_type VARCHAR(50),
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_activity_log_user_created ON user_activity_log(user_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- Reactions on tasks (one per user per task), separate from reactions on feed items
+CREATE TABLE task_reactions (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_reactions_user_id ON task_reactions(user_id);
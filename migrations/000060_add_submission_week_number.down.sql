@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_submissions_task_user_week;
+
+-- Keep only the latest submission per task and user so the original unique index can be restored
+DELETE FROM submissions s
+USING submissions newer
+WHERE newer.task_id = s.task_id AND newer.user_id = s.user_id
+    AND (newer.created_at, newer.id) > (s.created_at, s.id);
+
+CREATE UNIQUE INDEX idx_submissions_task_user ON submissions(task_id, user_id);
+
+ALTER TABLE submissions DROP COLUMN IF EXISTS week_number;
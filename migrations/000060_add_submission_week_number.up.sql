@@ -0,0 +1,9 @@
+-- ISO week of created_at so weekly tasks can be submitted once per week
+ALTER TABLE submissions ADD COLUMN week_number INTEGER NOT NULL DEFAULT EXTRACT(WEEK FROM CURRENT_TIMESTAMP)::int;
+
+UPDATE submissions SET week_number = EXTRACT(WEEK FROM created_at)::int;
+
+-- One submission per task and user per ISO week instead of one per task and user.
+-- Non-weekly tasks still allow a single submission (enforced when submitting).
+DROP INDEX IF EXISTS idx_submissions_task_user;
+CREATE UNIQUE INDEX idx_submissions_task_user_week ON submissions(task_id, user_id, (EXTRACT(ISOYEAR FROM created_at)), week_number);
@@ -0,0 +1,2 @@
+-- Extra payload (task_id, submission_id, ...) sent with the notification, kept for offline users
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS data JSONB;
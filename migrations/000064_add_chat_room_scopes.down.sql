@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_chat_messages_room_created;
+CREATE INDEX idx_messages_created_at ON chat_messages(room_id, created_at DESC);
+
+ALTER INDEX idx_chat_messages_user_id RENAME TO idx_messages_sender_id;
+ALTER INDEX idx_chat_messages_room_id RENAME TO idx_messages_room_id;
+ALTER TABLE chat_messages RENAME COLUMN user_id TO sender_id;
+ALTER TABLE chat_messages RENAME TO messages;
+
+-- Rooms created by the server have no creator and cannot be kept once created_by is required again
+DELETE FROM chat_rooms WHERE created_by IS NULL;
+
+DROP INDEX IF EXISTS idx_chat_rooms_type_scope;
+ALTER TABLE chat_rooms DROP CONSTRAINT IF EXISTS chat_rooms_scope_check;
+ALTER TABLE chat_rooms DROP CONSTRAINT IF EXISTS chat_rooms_type_check;
+ALTER TABLE chat_rooms ALTER COLUMN created_by SET NOT NULL;
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS scope_id;
@@ -0,0 +1,27 @@
+-- Chat rooms are scoped: one global room for everyone plus a room per state and per college.
+-- scope_id is the state or college id and is NULL for global rooms.
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS scope_id UUID;
+
+-- Scope rooms are created by the server, not by a user
+ALTER TABLE chat_rooms ALTER COLUMN created_by DROP NOT NULL;
+
+ALTER TABLE chat_rooms ADD CONSTRAINT chat_rooms_type_check
+CHECK (type IN ('global', 'state', 'college'));
+
+ALTER TABLE chat_rooms ADD CONSTRAINT chat_rooms_scope_check
+CHECK ((type = 'global') = (scope_id IS NULL));
+
+-- One room per state and per college
+CREATE UNIQUE INDEX idx_chat_rooms_type_scope ON chat_rooms(type, scope_id) WHERE scope_id IS NOT NULL;
+
+INSERT INTO chat_rooms (name, type) VALUES ('Pan India', 'global');
+
+-- The messages table has never been written to; it becomes chat_messages
+ALTER TABLE messages RENAME TO chat_messages;
+ALTER TABLE chat_messages RENAME COLUMN sender_id TO user_id;
+ALTER INDEX idx_messages_room_id RENAME TO idx_chat_messages_room_id;
+ALTER INDEX idx_messages_sender_id RENAME TO idx_chat_messages_user_id;
+
+-- Messages are paged newest first by (created_at, id)
+DROP INDEX IF EXISTS idx_messages_created_at;
+CREATE INDEX idx_chat_messages_room_created ON chat_messages(room_id, created_at DESC, id DESC);
@@ -0,0 +1 @@
+ALTER TABLE user_referrals DROP COLUMN IF EXISTS bonus_awarded_at;
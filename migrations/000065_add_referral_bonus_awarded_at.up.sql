@@ -0,0 +1,8 @@
+-- Set when the referrer is awarded the bonus for the referred user's first approved task,
+-- so the bonus is only ever awarded once per referral
+ALTER TABLE user_referrals ADD COLUMN IF NOT EXISTS bonus_awarded_at TIMESTAMP;
+
+-- Referred users who already completed a task before the bonus existed do not earn it retroactively
+UPDATE user_referrals ur SET bonus_awarded_at = CURRENT_TIMESTAMP
+WHERE bonus_awarded_at IS NULL
+    AND EXISTS (SELECT 1 FROM submissions s WHERE s.user_id = ur.referred_id AND s.status = 'approved');
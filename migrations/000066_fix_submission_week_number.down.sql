@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_submissions_task_user_week;
+
+UPDATE submissions SET week_number = EXTRACT(WEEK FROM created_at)::int;
+ALTER TABLE submissions ALTER COLUMN week_number SET DEFAULT EXTRACT(WEEK FROM CURRENT_TIMESTAMP)::int;
+
+CREATE UNIQUE INDEX idx_submissions_task_user_week ON submissions(task_id, user_id, (EXTRACT(ISOYEAR FROM created_at)), week_number);
@@ -0,0 +1,28 @@
+-- week_number now holds ISO year * 100 + ISO week (e.g. 202602) for weekly tasks, so the year and week
+-- always come from the same date, and 0 for every other task, so the unique index below keeps
+-- non-weekly tasks to one submission per task and user. CreateSubmission sets it; there is no DEFAULT.
+DROP INDEX IF EXISTS idx_submissions_task_user_week;
+ALTER TABLE submissions ALTER COLUMN week_number DROP DEFAULT;
+
+UPDATE submissions s
+SET week_number = CASE
+    WHEN t.is_weekly THEN (EXTRACT(ISOYEAR FROM s.created_at) * 100 + EXTRACT(WEEK FROM s.created_at))::int
+    ELSE 0
+END
+FROM tasks t
+WHERE t.id = s.task_id;
+
+-- Concurrent submits may have left duplicates while only the app checked; keep the approved one, else the latest
+DELETE FROM submissions
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY task_id, user_id, week_number
+            ORDER BY (status = 'approved') DESC, created_at DESC, id DESC
+        ) AS position
+        FROM submissions
+    ) ranked
+    WHERE position > 1
+);
+
+CREATE UNIQUE INDEX idx_submissions_task_user_week ON submissions(task_id, user_id, week_number);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_streak_freezes_user_unused;
@@ -0,0 +1,13 @@
+-- One unused streak freeze per user, so concurrent purchases cannot both insert one.
+-- Concurrent purchases may have left extra unused freezes while only the app checked; keep the oldest.
+DELETE FROM streak_freezes
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS position
+        FROM streak_freezes
+        WHERE used_at IS NULL
+    ) ranked
+    WHERE position > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_streak_freezes_user_unused ON streak_freezes(user_id) WHERE used_at IS NULL;